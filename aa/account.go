@@ -0,0 +1,411 @@
+package aa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+var (
+	// EntryPointV07 is the canonical EntryPoint v0.7 deployment.
+	EntryPointV07 = common.HexToAddress("0x0000000071727De22E5C6B3BCA16FD7D0F0251f4")
+
+	// SimpleAccountFactoryV07 deploys eth-infinitism SimpleAccounts for v0.7.
+	SimpleAccountFactoryV07 = common.HexToAddress("0x91E60e0613810449d098b0b5Ec8b51A0FE8c8985")
+)
+
+// dummySignature is a well-formed ECDSA signature used while estimating gas,
+// before the real hash is known.
+var dummySignature = hexutil.MustDecode("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+const contractsABI = `[
+{"inputs":[{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"name":"createAccount","outputs":[{"name":"","type":"address"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"name":"getAddress","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"dest","type":"address[]"},{"name":"value","type":"uint256[]"},{"name":"func","type":"bytes[]"}],"name":"executeBatch","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],"name":"getNonce","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}
+]`
+
+var parsedABI abi.ABI
+
+func init() {
+	var err error
+	parsedABI, err = abi.JSON(strings.NewReader(contractsABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Client sends user operations through per-chain bundlers, paying gas with
+// an ERC-20 token through an ERC-7677 paymaster served by the same endpoint.
+type Client struct {
+	rpcClients map[string]*ethclient.Client
+	bundlers   map[string]*rpc.Client
+	gasTokens  map[string]common.Address
+	factory    common.Address
+
+	mu       sync.Mutex
+	accounts map[common.Address]common.Address // owner -> account
+}
+
+// NewClient connects to the bundlers (chain key -> URL). gasTokens names the
+// ERC-20 the paymaster charges on each chain.
+func NewClient(rpcClients map[string]*ethclient.Client, bundlerURLs map[string]string, gasTokens map[string]common.Address, factory common.Address) (*Client, error) {
+	bundlers := make(map[string]*rpc.Client, len(bundlerURLs))
+	for chain, url := range bundlerURLs {
+		client, err := rpc.Dial(url)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to %s bundler: %w", chain, err)
+		}
+		bundlers[chain] = client
+	}
+	return &Client{
+		rpcClients: rpcClients,
+		bundlers:   bundlers,
+		gasTokens:  gasTokens,
+		factory:    factory,
+		accounts:   make(map[common.Address]common.Address),
+	}, nil
+}
+
+// AccountAddress returns the counterfactual smart account address for owner.
+// The factory deploys at the same address on every chain, so any bundler
+// chain's RPC gives the same answer.
+func (c *Client) AccountAddress(ctx context.Context, owner common.Address) (common.Address, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if addr, ok := c.accounts[owner]; ok {
+		return addr, nil
+	}
+
+	var chains []string
+	for chain := range c.bundlers {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	if len(chains) == 0 {
+		return common.Address{}, fmt.Errorf("no bundlers configured")
+	}
+
+	data, err := parsedABI.Pack("getAddress", owner, big.NewInt(0))
+	if err != nil {
+		return common.Address{}, err
+	}
+	out, err := c.rpcClients[chains[0]].CallContract(ctx, ethereum.CallMsg{To: &c.factory, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("getting account address: %w", err)
+	}
+	if len(out) < 32 {
+		return common.Address{}, fmt.Errorf("unexpected getAddress result %x", out)
+	}
+	addr := common.BytesToAddress(out[12:32])
+	c.accounts[owner] = addr
+	return addr, nil
+}
+
+// paymasterResult is the ERC-7677 pm_getPaymasterStubData/pm_getPaymasterData result.
+type paymasterResult struct {
+	Paymaster                     *common.Address `json:"paymaster"`
+	PaymasterData                 hexutil.Bytes   `json:"paymasterData"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit"`
+}
+
+func (op *UserOperation) setPaymaster(pm paymasterResult) {
+	op.Paymaster = pm.Paymaster
+	op.PaymasterData = pm.PaymasterData
+	if pm.PaymasterVerificationGasLimit != nil {
+		op.PaymasterVerificationGasLimit = pm.PaymasterVerificationGasLimit
+	}
+	if pm.PaymasterPostOpGasLimit != nil {
+		op.PaymasterPostOpGasLimit = pm.PaymasterPostOpGasLimit
+	}
+}
+
+// gasEstimate is the eth_estimateUserOperationGas result.
+type gasEstimate struct {
+	PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+	VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+	CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+	PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit"`
+	PaymasterPostOpGasLimit       *hexutil.Big `json:"paymasterPostOpGasLimit"`
+}
+
+// userOpReceipt is the part of eth_getUserOperationReceipt we need.
+type userOpReceipt struct {
+	Success bool   `json:"success"`
+	Reason  string `json:"reason"`
+	Receipt struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+	} `json:"receipt"`
+}
+
+// send builds, sponsors, signs and submits a user operation running calls
+// from owner's account, then waits for it to be included.
+func (c *Client) send(ctx context.Context, chain string, owner wallet.Signer, account common.Address, calls []swaps.Call) (string, error) {
+	ethRPC, ok := c.rpcClients[chain]
+	if !ok {
+		return "", fmt.Errorf("no RPC client for %s", chain)
+	}
+	bundler, ok := c.bundlers[chain]
+	if !ok {
+		return "", fmt.Errorf("no bundler configured for %s", chain)
+	}
+	gasToken, ok := c.gasTokens[chain]
+	if !ok {
+		return "", fmt.Errorf("no paymaster token for %s", chain)
+	}
+
+	chainID, err := ethRPC.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting chain ID: %w", err)
+	}
+	chainIDHex := hexutil.EncodeBig(chainID)
+	pmContext := map[string]string{"token": gasToken.Hex()}
+
+	op := &UserOperation{Sender: account, Signature: dummySignature}
+
+	nonceData, err := parsedABI.Pack("getNonce", account, big.NewInt(0))
+	if err != nil {
+		return "", err
+	}
+	out, err := ethRPC.CallContract(ctx, ethereum.CallMsg{To: &EntryPointV07, Data: nonceData}, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting account nonce: %w", err)
+	}
+	op.Nonce = (*hexutil.Big)(new(big.Int).SetBytes(out))
+
+	// Deploy the account with its first operation
+	code, err := ethRPC.CodeAt(ctx, account, nil)
+	if err != nil {
+		return "", fmt.Errorf("checking account code: %w", err)
+	}
+	if len(code) == 0 {
+		factoryData, err := parsedABI.Pack("createAccount", owner.Address(), big.NewInt(0))
+		if err != nil {
+			return "", err
+		}
+		op.Factory = &c.factory
+		op.FactoryData = factoryData
+		log.Printf("AA: deploying smart account %s on %s", account.Hex(), chain)
+	}
+
+	tip, err := ethRPC.SuggestGasTipCap(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas tip: %w", err)
+	}
+	head, err := ethRPC.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting head block: %w", err)
+	}
+	maxFee := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	op.MaxPriorityFeePerGas = (*hexutil.Big)(tip)
+	op.MaxFeePerGas = (*hexutil.Big)(maxFee)
+	op.CallGasLimit = (*hexutil.Big)(big.NewInt(0))
+	op.VerificationGasLimit = (*hexutil.Big)(big.NewInt(0))
+	op.PreVerificationGas = (*hexutil.Big)(big.NewInt(0))
+
+	if op.CallData, err = batchCallData(calls); err != nil {
+		return "", err
+	}
+
+	var stub paymasterResult
+	if err := bundler.CallContext(ctx, &stub, "pm_getPaymasterStubData", op, EntryPointV07, chainIDHex, pmContext); err != nil {
+		return "", fmt.Errorf("pm_getPaymasterStubData: %w", err)
+	}
+	if stub.Paymaster == nil {
+		return "", fmt.Errorf("paymaster returned no address")
+	}
+	op.setPaymaster(stub)
+
+	// The ERC-20 paymaster pulls its fee from the account, so approve it in
+	// the same batch the first time
+	allowanceData, err := parsedABI.Pack("allowance", account, *stub.Paymaster)
+	if err != nil {
+		return "", err
+	}
+	out, err = ethRPC.CallContract(ctx, ethereum.CallMsg{To: &gasToken, Data: allowanceData}, nil)
+	if err != nil {
+		return "", fmt.Errorf("checking paymaster allowance: %w", err)
+	}
+	if new(big.Int).SetBytes(out).Cmp(new(big.Int).Rsh(maxUint256, 1)) < 0 {
+		approveData, err := parsedABI.Pack("approve", *stub.Paymaster, maxUint256)
+		if err != nil {
+			return "", err
+		}
+		calls = append([]swaps.Call{{To: gasToken, Value: big.NewInt(0), Data: approveData}}, calls...)
+		if op.CallData, err = batchCallData(calls); err != nil {
+			return "", err
+		}
+	}
+
+	var est gasEstimate
+	if err := bundler.CallContext(ctx, &est, "eth_estimateUserOperationGas", op, EntryPointV07); err != nil {
+		return "", fmt.Errorf("eth_estimateUserOperationGas: %w", err)
+	}
+	op.PreVerificationGas = est.PreVerificationGas
+	op.VerificationGasLimit = est.VerificationGasLimit
+	op.CallGasLimit = est.CallGasLimit
+	if est.PaymasterVerificationGasLimit != nil {
+		op.PaymasterVerificationGasLimit = est.PaymasterVerificationGasLimit
+	}
+	if est.PaymasterPostOpGasLimit != nil {
+		op.PaymasterPostOpGasLimit = est.PaymasterPostOpGasLimit
+	}
+
+	var pm paymasterResult
+	if err := bundler.CallContext(ctx, &pm, "pm_getPaymasterData", op, EntryPointV07, chainIDHex, pmContext); err != nil {
+		return "", fmt.Errorf("pm_getPaymasterData: %w", err)
+	}
+	if pm.Paymaster == nil {
+		return "", fmt.Errorf("paymaster declined to sponsor the operation")
+	}
+	op.setPaymaster(pm)
+
+	// SimpleAccount checks an EIP-191 signature over the user operation hash
+	hash := op.Hash(EntryPointV07, chainID)
+	sig, err := owner.SignDigest(ctx, accounts.TextHash(hash.Bytes()))
+	if err != nil {
+		return "", fmt.Errorf("signing user operation: %w", err)
+	}
+	sig[64] += 27
+	op.Signature = sig
+
+	var opHash common.Hash
+	if err := bundler.CallContext(ctx, &opHash, "eth_sendUserOperation", op, EntryPointV07); err != nil {
+		return "", fmt.Errorf("eth_sendUserOperation: %w", err)
+	}
+	log.Printf("AA: user operation %s sent on %s", opHash.Hex(), chain)
+
+	return waitForReceipt(ctx, bundler, opHash)
+}
+
+// waitForReceipt polls the bundler until the user operation is included.
+func waitForReceipt(ctx context.Context, bundler *rpc.Client, opHash common.Hash) (string, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		var raw json.RawMessage
+		if err := bundler.CallContext(waitCtx, &raw, "eth_getUserOperationReceipt", opHash); err != nil {
+			return "", fmt.Errorf("eth_getUserOperationReceipt: %w", err)
+		}
+		if len(raw) > 0 && string(raw) != "null" {
+			var receipt userOpReceipt
+			if err := json.Unmarshal(raw, &receipt); err != nil {
+				return "", fmt.Errorf("decoding user operation receipt: %w", err)
+			}
+			txHash := receipt.Receipt.TransactionHash.Hex()
+			if !receipt.Success {
+				return "", fmt.Errorf("user operation reverted in tx %s: %s", txHash, receipt.Reason)
+			}
+			return txHash, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return "", fmt.Errorf("user operation %s not included: %w", opHash.Hex(), waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// batchCallData encodes calls as a SimpleAccount executeBatch.
+func batchCallData(calls []swaps.Call) ([]byte, error) {
+	dest := make([]common.Address, len(calls))
+	values := make([]*big.Int, len(calls))
+	data := make([][]byte, len(calls))
+	for i, call := range calls {
+		dest[i] = call.To
+		values[i] = call.Value
+		if values[i] == nil {
+			values[i] = big.NewInt(0)
+		}
+		data[i] = call.Data
+	}
+	return parsedABI.Pack("executeBatch", dest, values, data)
+}
+
+// Keyring serves smart accounts owned by the keys of a base keyring. The
+// wallet address for an index is its account, not the owner key.
+type Keyring struct {
+	base   wallet.Keyring
+	client *Client
+}
+
+func NewKeyring(base wallet.Keyring, client *Client) *Keyring {
+	return &Keyring{base: base, client: client}
+}
+
+func (k *Keyring) Address(index uint32) (common.Address, error) {
+	owner, err := k.base.Address(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return k.client.AccountAddress(context.Background(), owner)
+}
+
+func (k *Keyring) Signer(index uint32) (wallet.Signer, error) {
+	owner, err := k.base.Signer(index)
+	if err != nil {
+		return nil, err
+	}
+	account, err := k.client.AccountAddress(context.Background(), owner.Address())
+	if err != nil {
+		return nil, err
+	}
+	return &Account{owner: owner, address: account, client: k.client}, nil
+}
+
+// Account is a smart account that executes batches of calls through the
+// bundler. It implements swaps.CallSender; it can't sign plain transactions.
+type Account struct {
+	owner   wallet.Signer
+	address common.Address
+	client  *Client
+}
+
+func (a *Account) Address() common.Address {
+	return a.address
+}
+
+func (a *Account) SendCalls(ctx context.Context, chain string, calls []swaps.Call) (string, error) {
+	return a.client.send(ctx, chain, a.owner, a.address, calls)
+}
+
+func (a *Account) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, fmt.Errorf("smart account %s sends calls through the bundler, not signed transactions", a.address.Hex())
+}
+
+func (a *Account) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return nil, fmt.Errorf("smart account %s can't sign %s messages", a.address.Hex(), data.PrimaryType)
+}
+
+func (a *Account) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return nil, fmt.Errorf("smart account %s can't sign raw digests", a.address.Hex())
+}
@@ -0,0 +1,91 @@
+package aa
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// UserOperation is an EntryPoint v0.7 user operation in the unpacked form
+// bundler and paymaster RPCs use.
+type UserOperation struct {
+	Sender                        common.Address  `json:"sender"`
+	Nonce                         *hexutil.Big    `json:"nonce"`
+	Factory                       *common.Address `json:"factory,omitempty"`
+	FactoryData                   hexutil.Bytes   `json:"factoryData,omitempty"`
+	CallData                      hexutil.Bytes   `json:"callData"`
+	CallGasLimit                  *hexutil.Big    `json:"callGasLimit"`
+	VerificationGasLimit          *hexutil.Big    `json:"verificationGasLimit"`
+	PreVerificationGas            *hexutil.Big    `json:"preVerificationGas"`
+	MaxFeePerGas                  *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas          *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Paymaster                     *common.Address `json:"paymaster,omitempty"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit,omitempty"`
+	PaymasterData                 hexutil.Bytes   `json:"paymasterData,omitempty"`
+	Signature                     hexutil.Bytes   `json:"signature"`
+}
+
+// Hash returns the user operation hash the account signs, as computed by
+// EntryPoint v0.7's getUserOpHash.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	var initCode []byte
+	if op.Factory != nil {
+		initCode = append(op.Factory.Bytes(), op.FactoryData...)
+	}
+
+	var paymasterAndData []byte
+	if op.Paymaster != nil {
+		paymasterAndData = append(paymasterAndData, op.Paymaster.Bytes()...)
+		paymasterAndData = append(paymasterAndData, pack128(op.PaymasterVerificationGasLimit, op.PaymasterPostOpGasLimit)...)
+		paymasterAndData = append(paymasterAndData, op.PaymasterData...)
+	}
+
+	packed := concatWords(
+		common.LeftPadBytes(op.Sender.Bytes(), 32),
+		word(op.Nonce),
+		crypto.Keccak256(initCode),
+		crypto.Keccak256(op.CallData),
+		pack128(op.VerificationGasLimit, op.CallGasLimit),
+		word(op.PreVerificationGas),
+		pack128(op.MaxPriorityFeePerGas, op.MaxFeePerGas),
+		crypto.Keccak256(paymasterAndData),
+	)
+
+	return crypto.Keccak256Hash(concatWords(
+		crypto.Keccak256(packed),
+		common.LeftPadBytes(entryPoint.Bytes(), 32),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+	))
+}
+
+// word left-pads v to a 32 byte ABI word.
+func word(v *hexutil.Big) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return common.LeftPadBytes(v.ToInt().Bytes(), 32)
+}
+
+// pack128 packs two values into one 32 byte word, 16 bytes each, as v0.7
+// does for accountGasLimits, gasFees and the paymaster gas limits.
+func pack128(hi, lo *hexutil.Big) []byte {
+	out := make([]byte, 32)
+	if hi != nil {
+		hi.ToInt().FillBytes(out[:16])
+	}
+	if lo != nil {
+		lo.ToInt().FillBytes(out[16:])
+	}
+	return out
+}
+
+func concatWords(words ...[]byte) []byte {
+	var out []byte
+	for _, w := range words {
+		out = append(out, w...)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package across
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const baseURL = "https://app.across.to/api"
+
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(httpClient *http.Client) *Client {
+	return &Client{
+		httpClient: httpClient,
+	}
+}
+
+// SuggestedFeesResponse is the response from GET /suggested-fees.
+// Across returns most numeric fields as strings; json.Number accepts either form.
+type SuggestedFeesResponse struct {
+	OutputAmount         string      `json:"outputAmount"`
+	SpokePoolAddress     string      `json:"spokePoolAddress"`
+	ExclusiveRelayer     string      `json:"exclusiveRelayer"`
+	ExclusivityDeadline  json.Number `json:"exclusivityDeadline"`
+	FillDeadline         json.Number `json:"fillDeadline"`
+	Timestamp            json.Number `json:"timestamp"`
+	IsAmountTooLow       bool        `json:"isAmountTooLow"`
+	EstimatedFillTimeSec int64       `json:"estimatedFillTimeSec"`
+	TotalRelayFee        struct {
+		Pct   string `json:"pct"`
+		Total string `json:"total"`
+	} `json:"totalRelayFee"`
+	Limits struct {
+		MinDeposit string `json:"minDeposit"`
+		MaxDeposit string `json:"maxDeposit"`
+	} `json:"limits"`
+}
+
+// DepositStatusResponse is the response from GET /deposit/status.
+type DepositStatusResponse struct {
+	Status      string `json:"status"` // pending, filled, expired, refunded
+	FillTx      string `json:"fillTx"`
+	DepositID   string `json:"depositId"`
+	DestChainID int64  `json:"destinationChainId"`
+}
+
+// GetSuggestedFees quotes a bridge of amount (in input token smallest units) between two chains.
+func (c *Client) GetSuggestedFees(ctx context.Context, inputToken, outputToken string, originChainID, destChainID int64, amount string, recipient string) (*SuggestedFeesResponse, error) {
+	params := url.Values{}
+	params.Set("inputToken", inputToken)
+	params.Set("outputToken", outputToken)
+	params.Set("originChainId", fmt.Sprintf("%d", originChainID))
+	params.Set("destinationChainId", fmt.Sprintf("%d", destChainID))
+	params.Set("amount", amount)
+	params.Set("recipient", recipient)
+
+	u := baseURL + "/suggested-fees?" + params.Encode()
+
+	var result SuggestedFeesResponse
+	if err := c.get(ctx, u, &result); err != nil {
+		return nil, fmt.Errorf("across suggested-fees: %w", err)
+	}
+	return &result, nil
+}
+
+// GetDepositStatus looks up the fill status of a deposit by its origin chain tx hash.
+func (c *Client) GetDepositStatus(ctx context.Context, originChainID int64, depositTxHash string) (*DepositStatusResponse, error) {
+	params := url.Values{}
+	params.Set("originChainId", fmt.Sprintf("%d", originChainID))
+	params.Set("depositTxHash", depositTxHash)
+
+	u := baseURL + "/deposit/status?" + params.Encode()
+
+	var result DepositStatusResponse
+	if err := c.get(ctx, u, &result); err != nil {
+		return nil, fmt.Errorf("across deposit status: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *Client) get(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package across
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// destinationUSDC maps our chain notation to the chain ID and native USDC contract
+// for each chain Across can deliver USDC to.
+var destinationUSDC = map[string]struct {
+	ChainID int64
+	USDC    common.Address
+}{
+	"ETH":     {ChainID: 1, USDC: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")},
+	"ARB":     {ChainID: 42161, USDC: common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831")},
+	"OP":      {ChainID: 10, USDC: common.HexToAddress("0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85")},
+	"BASE":    {ChainID: 8453, USDC: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")},
+	"POLYGON": {ChainID: 137, USDC: common.HexToAddress("0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359")},
+}
+
+// sourceChains maps our RPC chain name to the Across origin chain ID.
+// Across has no SpokePool on Avalanche, so it can't fund deposits.
+var sourceChains = map[string]int64{
+	"base":     8453,
+	"ethereum": 1,
+	"optimism": 10,
+	"polygon":  137,
+}
+
+// AssetToOutput returns the destination chain ID and USDC contract for a target asset.
+// Only USDC is supported: fundbot holds USDC, and Across deposits bridge like-for-like.
+func AssetToOutput(asset swaps.Asset) (int64, common.Address, bool) {
+	if asset.Symbol != "USDC" {
+		return 0, common.Address{}, false
+	}
+	dest, ok := destinationUSDC[asset.Chain]
+	if !ok {
+		return 0, common.Address{}, false
+	}
+	// Reject bridged variants (e.g. USDC.e) if an explicit contract was given
+	if asset.ContractAddress != "" && !strings.EqualFold(asset.ContractAddress, dest.USDC.Hex()) {
+		return 0, common.Address{}, false
+	}
+	return dest.ChainID, dest.USDC, true
+}
+
+// SourceChainID returns the Across origin chain ID for a source RPC chain.
+func SourceChainID(chain string) (int64, bool) {
+	id, ok := sourceChains[chain]
+	return id, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that Across can source USDC from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceChains))
+	for k := range sourceChains {
+		chains = append(chains, k)
+	}
+	return chains
+}
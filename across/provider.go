@@ -0,0 +1,413 @@
+package across
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+// SpokePool ABI for depositV3
+const spokePoolDepositABI = `[{"inputs":[{"name":"depositor","type":"address"},{"name":"recipient","type":"address"},{"name":"inputToken","type":"address"},{"name":"outputToken","type":"address"},{"name":"inputAmount","type":"uint256"},{"name":"outputAmount","type":"uint256"},{"name":"destinationChainId","type":"uint256"},{"name":"exclusiveRelayer","type":"address"},{"name":"quoteTimestamp","type":"uint32"},{"name":"fillDeadline","type":"uint32"},{"name":"exclusivityDeadline","type":"uint32"},{"name":"message","type":"bytes"}],"name":"depositV3","outputs":[],"stateMutability":"payable","type":"function"}]`
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "across"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, _, ok := AssetToOutput(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	destChainID, outputToken, ok := AssetToOutput(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("across: unsupported target asset %s", toAsset)
+	}
+	if !common.IsHexAddress(destination) {
+		return nil, fmt.Errorf("across: destination %q is not an EVM address", destination)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		originChainID, ok := SourceChainID(chain)
+		if !ok || originChainID == destChainID {
+			continue
+		}
+
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Across bridges USDC like-for-like, so only USDC can fund deposits
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, swaps.OnlySymbol("USDC"))
+		if err != nil {
+			log.Printf("across: skipping %s: %v", chain, err)
+			continue
+		}
+
+		fees, err := p.client.GetSuggestedFees(ctx, token.Address.Hex(), outputToken.Hex(), originChainID, destChainID, inputAmount.String(), destination)
+		if err != nil {
+			log.Printf("across quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+		if fees.IsAmountTooLow {
+			log.Printf("across: skipping %s, amount too low (min deposit %s)", chain, fees.Limits.MinDeposit)
+			continue
+		}
+		if maxDeposit, ok := new(big.Int).SetString(fees.Limits.MaxDeposit, 10); ok && inputAmount.Cmp(maxDeposit) > 0 {
+			log.Printf("across: skipping %s, above max deposit %s", chain, maxDeposit)
+			continue
+		}
+
+		outputAmount, ok := new(big.Int).SetString(fees.OutputAmount, 10)
+		if !ok {
+			log.Printf("across: invalid output amount %q", fees.OutputAmount)
+			continue
+		}
+		expectedOut := formatUSDC(outputAmount)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "across",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    expectedOut,
+			ExpectedOutputRaw: parseToBigInt(expectedOut),
+			Router:            fees.SpokePoolAddress,
+			ExtraData: map[string]interface{}{
+				"across_output_token":         outputToken.Hex(),
+				"across_output_amount":        fees.OutputAmount,
+				"across_destination_chain_id": destChainID,
+				"across_destination":          destination,
+				"across_exclusive_relayer":    fees.ExclusiveRelayer,
+				"across_quote_timestamp":      fees.Timestamp.String(),
+				"across_fill_deadline":        fees.FillDeadline.String(),
+				"across_exclusivity_deadline": fees.ExclusivityDeadline.String(),
+				"across_fill_time_s":          fees.EstimatedFillTimeSec,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("across: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	if !common.IsHexAddress(quote.Router) {
+		return swaps.ExecuteResult{}, fmt.Errorf("across: missing SpokePool address in quote")
+	}
+	spokePool := common.HexToAddress(quote.Router)
+	fromAddr := signer.Address()
+
+	params, err := depositParamsFromQuote(quote)
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+	params.depositor = fromAddr
+	params.inputToken = token.Address
+	params.inputAmount = quote.InputAmount
+
+	// Step 1: Approve SpokePool to spend USDC
+	if err := approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, spokePool, quote.InputAmount); err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("approving USDC: %w", err)
+	}
+
+	// Step 2: Call depositV3 on the SpokePool
+	txHash, err := depositV3(ctx, rpc, chainID, signer, fromAddr, spokePool, params)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("deposit: %w", err)
+	}
+
+	// The status API is keyed by origin chain + deposit tx hash, so keep the origin chain ID.
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: chainID.String(),
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	originChainID, err := strconv.ParseInt(externalID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("across: invalid origin chain ID %q", externalID)
+	}
+
+	status, err := p.client.GetDepositStatus(ctx, originChainID, txHash)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, status.Status)
+
+	switch status.Status {
+	case "filled":
+		return "completed", nil
+	case "refunded":
+		return "refunded", nil
+	case "expired":
+		return "failed", nil
+	default:
+		return "pending", nil
+	}
+}
+
+// depositParams holds the arguments to SpokePool.depositV3.
+type depositParams struct {
+	depositor           common.Address
+	recipient           common.Address
+	inputToken          common.Address
+	outputToken         common.Address
+	inputAmount         *big.Int
+	outputAmount        *big.Int
+	destinationChainID  *big.Int
+	exclusiveRelayer    common.Address
+	quoteTimestamp      uint32
+	fillDeadline        uint32
+	exclusivityDeadline uint32
+}
+
+func depositParamsFromQuote(quote swaps.Quote) (depositParams, error) {
+	var params depositParams
+
+	str := func(key string) string {
+		s, _ := quote.ExtraData[key].(string)
+		return s
+	}
+	u32 := func(key string) (uint32, error) {
+		v, err := strconv.ParseUint(str(key), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("across: invalid %s in quote ExtraData: %w", key, err)
+		}
+		return uint32(v), nil
+	}
+
+	destination := str("across_destination")
+	if !common.IsHexAddress(destination) {
+		return params, fmt.Errorf("across: missing destination in quote ExtraData")
+	}
+	params.recipient = common.HexToAddress(destination)
+	params.outputToken = common.HexToAddress(str("across_output_token"))
+	params.exclusiveRelayer = common.HexToAddress(str("across_exclusive_relayer"))
+
+	outputAmount, ok := new(big.Int).SetString(str("across_output_amount"), 10)
+	if !ok {
+		return params, fmt.Errorf("across: missing output amount in quote ExtraData")
+	}
+	params.outputAmount = outputAmount
+
+	destChainID, ok := quote.ExtraData["across_destination_chain_id"].(int64)
+	if !ok {
+		return params, fmt.Errorf("across: missing destination chain in quote ExtraData")
+	}
+	params.destinationChainID = big.NewInt(destChainID)
+
+	var err error
+	if params.quoteTimestamp, err = u32("across_quote_timestamp"); err != nil {
+		return params, err
+	}
+	if params.fillDeadline, err = u32("across_fill_deadline"); err != nil {
+		return params, err
+	}
+	if params.exclusivityDeadline, err = u32("across_exclusivity_deadline"); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+func approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
+	parsed, err := abi.JSON(strings.NewReader(thorchain.ERC20ApproveABI))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("signing approve tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("sending approve tx: %w", err)
+	}
+
+	log.Printf("Across approve tx sent: %s", signedTx.Hash().Hex())
+
+	// Wait for approval to be mined with 2-minute timeout (deposit depends on this)
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return fmt.Errorf("waiting for approve: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx failed")
+	}
+
+	return nil
+}
+
+func depositV3(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, spokePool common.Address, params depositParams) (string, error) {
+	parsed, err := abi.JSON(strings.NewReader(spokePoolDepositABI))
+	if err != nil {
+		return "", err
+	}
+
+	data, err := parsed.Pack("depositV3",
+		params.depositor,
+		params.recipient,
+		params.inputToken,
+		params.outputToken,
+		params.inputAmount,
+		params.outputAmount,
+		params.destinationChainID,
+		params.exclusiveRelayer,
+		params.quoteTimestamp,
+		params.fillDeadline,
+		params.exclusivityDeadline,
+		[]byte{},
+	)
+	if err != nil {
+		return "", fmt.Errorf("packing deposit: %w", err)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	// ERC20 deposit: value is 0 (tokens transferred via approve+transferFrom)
+	tx := types.NewTransaction(nonce, spokePool, big.NewInt(0), 200000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing deposit tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending deposit tx: %w", err)
+	}
+
+	log.Printf("Across deposit tx sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// formatUSDC renders a 6-decimal USDC amount as a decimal string.
+func formatUSDC(amount *big.Int) string {
+	whole := new(big.Int).Div(amount, big.NewInt(1e6))
+	frac := new(big.Int).Mod(amount, big.NewInt(1e6))
+	return fmt.Sprintf("%s.%06d", whole, frac.Int64())
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
@@ -0,0 +1,300 @@
+// Package alerts tells the admin on Telegram when the wallets run short of
+// funds: USDC across every wallet below a floor, or a wallet's native gas
+// below a chain's threshold. Once a day it also lists every wallet that
+// needs funding.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// totalKey marks the total USDC floor in Alerter.breached.
+const totalKey = "total"
+
+// Alerter checks the wallets' balances against the balance_alerts
+// thresholds. A breach is reported when it starts, not on every check;
+// once the balance recovers, the next breach is reported again. Breaches
+// are kept in memory, so a restart reports the ones still open once more.
+type Alerter struct {
+	cfg        *config.Config
+	store      *db.Store
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+	botAPI     *tgbotapi.BotAPI
+
+	// Breaches already reported: totalKey, or "chain/address"
+	breached map[string]bool
+
+	// UTC day of the last digest, YYYY-MM-DD
+	lastDigest string
+}
+
+func New(cfg *config.Config, store *db.Store, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client, botAPI *tgbotapi.BotAPI) *Alerter {
+	return &Alerter{
+		cfg:        cfg,
+		store:      store,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+		botAPI:     botAPI,
+		breached:   make(map[string]bool),
+	}
+}
+
+func (a *Alerter) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(a.cfg.BalanceAlerts.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	a.check(ctx, time.Now().UTC())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Balance alerts stopped")
+			return
+		case now := <-ticker.C:
+			a.check(ctx, now.UTC())
+		}
+	}
+}
+
+// owned is a wallet in use and whose it is, for the admin's messages.
+type owned struct {
+	index uint32
+	owner string
+}
+
+// low is a wallet whose native balance is below its chain's threshold.
+type low struct {
+	owned
+	address   string
+	chain     string
+	native    string
+	threshold *big.Int
+}
+
+// wallets returns the wallets in use: the shared wallet in single mode, or
+// one per address assignment in multi mode. Wallets of anonymized users
+// are left out.
+func (a *Alerter) wallets(ctx context.Context) ([]owned, error) {
+	if a.cfg.Mode == config.ModeSingle {
+		return []owned{{index: 0, owner: "Shared Wallet"}}, nil
+	}
+
+	users, err := a.store.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userByID := make(map[int64]db.User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+	chats, err := a.store.ListChats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatByID := make(map[int64]db.Chat, len(chats))
+	for _, c := range chats {
+		chatByID[c.ID] = c
+	}
+
+	assignments, err := a.store.ListAddressAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var wallets []owned
+	for _, as := range assignments {
+		w := owned{index: uint32(as.ID), owner: "Unknown"}
+		switch as.AssignedToType {
+		case "user":
+			u, ok := userByID[as.AssignedToID]
+			if !ok || u.DeletedAt.Valid {
+				continue
+			}
+			w.owner = fmt.Sprintf("User #%d", u.TelegramID)
+			if u.Username != "" {
+				w.owner = "@" + u.Username
+			}
+		case "chat":
+			if c, ok := chatByID[as.AssignedToID]; ok {
+				w.owner = c.Title
+			}
+		}
+		wallets = append(wallets, w)
+	}
+	return wallets, nil
+}
+
+func (a *Alerter) check(ctx context.Context, now time.Time) {
+	settings := a.cfg.BalanceAlerts
+	wallets, err := a.wallets(ctx)
+	if err != nil {
+		log.Printf("Balance alerts: error listing wallets: %v", err)
+		return
+	}
+
+	var addresses []common.Address
+	byAddr := make(map[string]owned)
+	for _, w := range wallets {
+		addr, err := a.keyring.Address(w.index)
+		if err != nil {
+			log.Printf("Balance alerts: error deriving wallet %d: %v", w.index, err)
+			continue
+		}
+		addresses = append(addresses, addr)
+		byAddr[addr.Hex()] = w
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	// USDC is read on every chain for the total; chains without it are
+	// read for their native balance only
+	clients := swaps.EnabledClients(a.rpcClients)
+	usdcTokens := make(map[string]swaps.FundingToken)
+	contracts := make(map[string][]common.Address)
+	for chain := range clients {
+		usdc, ok := thorchain.FundingTokens.Lookup(chain, "USDC")
+		if ok && !usdc.Native && settings.MinTotalUSDC > 0 {
+			usdcTokens[chain] = usdc
+			contracts[chain] = []common.Address{usdc.Address}
+		} else if settings.MinNative(chain) != nil {
+			contracts[chain] = nil
+		}
+	}
+
+	bals, err := balances.FetchBalances(ctx, clients, addresses, contracts, nil)
+	if err != nil {
+		log.Printf("Balance alerts: error fetching balances: %v", err)
+		return
+	}
+
+	var totalUSDC float64
+	var lows []low
+	for _, bal := range bals {
+		if usdc, ok := usdcTokens[bal.Chain]; ok && len(bal.TokenBalances) > 0 {
+			if raw, ok := new(big.Int).SetString(bal.TokenBalances[0], 10); ok {
+				totalUSDC += usdc.Units(raw)
+			}
+		}
+		threshold := settings.MinNative(bal.Chain)
+		native, ok := new(big.Int).SetString(bal.NativeBalance, 10)
+		if threshold == nil || !ok || native.Cmp(threshold) >= 0 {
+			continue
+		}
+		lows = append(lows, low{owned: byAddr[bal.Address], address: bal.Address, chain: bal.Chain, native: bal.NativeBalance, threshold: threshold})
+	}
+	slices.SortFunc(lows, func(x, y low) int {
+		if x.index != y.index {
+			return int(x.index) - int(y.index)
+		}
+		return strings.Compare(x.chain, y.chain)
+	})
+
+	totalLow := settings.MinTotalUSDC > 0 && totalUSDC < settings.MinTotalUSDC
+	a.alert(totalLow, totalUSDC, lows)
+
+	if today := now.Format(time.DateOnly); now.Hour() >= settings.DigestHour && a.lastDigest != today {
+		a.lastDigest = today
+		if totalLow || len(lows) > 0 {
+			a.notifyAdmin(digest(totalLow, totalUSDC, settings.MinTotalUSDC, lows))
+		}
+	}
+}
+
+// alert reports the breaches that started since the last check and forgets
+// the ones that ended.
+func (a *Alerter) alert(totalLow bool, totalUSDC float64, lows []low) {
+	open := make(map[string]bool)
+	var lines []string
+	if totalLow {
+		open[totalKey] = true
+		if !a.breached[totalKey] {
+			lines = append(lines, fmt.Sprintf("USDC across all wallets is down to $%.2f, below the $%.2f floor.", totalUSDC, a.cfg.BalanceAlerts.MinTotalUSDC))
+		}
+	}
+	for _, l := range lows {
+		key := l.chain + "/" + l.address
+		open[key] = true
+		if !a.breached[key] {
+			lines = append(lines, lowLine(l))
+		}
+	}
+	for key := range a.breached {
+		if !open[key] {
+			log.Printf("Balance alerts: %s recovered", key)
+		}
+	}
+	a.breached = open
+
+	if len(lines) == 0 {
+		return
+	}
+	log.Printf("Balance alerts: %d new breach(es)", len(lines))
+	a.notifyAdmin("Low balance alert\n\n" + strings.Join(lines, "\n"))
+}
+
+// digest lists everything below its threshold, for the daily message.
+func digest(totalLow bool, totalUSDC, floor float64, lows []low) string {
+	var b strings.Builder
+	b.WriteString("Funding needed\n")
+	if totalLow {
+		fmt.Fprintf(&b, "\nUSDC across all wallets: $%.2f (floor $%.2f)\n", totalUSDC, floor)
+	}
+	if len(lows) > 0 {
+		fmt.Fprintf(&b, "\n%d wallet(s) low on gas:\n", len(lows))
+		for _, l := range lows {
+			b.WriteString(lowLine(l) + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func lowLine(l low) string {
+	symbol := nativeSymbol(l.chain)
+	return fmt.Sprintf("#%d %s (%s) on %s: %s %s, below %s %s",
+		l.index, l.owner, l.address, l.chain, formatNative(l.native), symbol, formatNative(l.threshold.String()), symbol)
+}
+
+func (a *Alerter) notifyAdmin(text string) {
+	msg := tgbotapi.NewMessage(a.cfg.AdminUserID, text)
+	msg.DisableWebPagePreview = true
+	if _, err := a.botAPI.Send(msg); err != nil {
+		log.Printf("Balance alerts: error notifying admin: %v", err)
+	}
+}
+
+// nativeSymbol returns the ticker of a chain's native coin.
+func nativeSymbol(chain string) string {
+	if native, ok := thorchain.NativeToken(chain); ok {
+		return native.Symbol
+	}
+	return strings.ToUpper(chain)
+}
+
+// formatNative renders wei with six decimals.
+func formatNative(wei string) string {
+	val, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		val = new(big.Int)
+	}
+	whole := new(big.Int).Div(val, big.NewInt(1e18))
+	frac := new(big.Int).Mod(val, big.NewInt(1e18))
+	return fmt.Sprintf("%s.%s", whole, fmt.Sprintf("%018s", frac.String())[:6])
+}
@@ -0,0 +1,76 @@
+package apilog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Exchange is a logged request and the response it got.
+type Exchange struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Status int             `json:"status,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"` // as received if JSON, else a JSON string
+}
+
+// Capture collects the exchanges of logged HTTP clients made with a context
+// from WithCapture, so a caller can keep what a provider answered without
+// going through the API log, which is pruned.
+type Capture struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+type captureKey struct{}
+
+// WithCapture returns a context whose logged requests are added to the
+// returned Capture.
+func WithCapture(ctx context.Context) (context.Context, *Capture) {
+	c := new(Capture)
+	return context.WithValue(ctx, captureKey{}, c), c
+}
+
+func captureFrom(ctx context.Context) *Capture {
+	c, _ := ctx.Value(captureKey{}).(*Capture)
+	return c
+}
+
+func (c *Capture) add(e Exchange, body []byte) {
+	if json.Valid(body) {
+		e.Body = body
+	} else if len(body) > 0 {
+		e.Body, _ = json.Marshal(string(body))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exchanges = append(c.exchanges, e)
+}
+
+// Payload returns the captured exchanges as a JSON array, or nil if there
+// were none. Once the bodies add up to more than limit bytes, the rest are
+// replaced by a note of their size and truncated is set.
+func (c *Capture) Payload(limit int) (payload []byte, truncated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.exchanges) == 0 {
+		return nil, false
+	}
+
+	exchanges := make([]Exchange, len(c.exchanges))
+	copy(exchanges, c.exchanges)
+	used := 0
+	for i := range exchanges {
+		size := len(exchanges[i].Body)
+		if used+size > limit {
+			exchanges[i].Body, _ = json.Marshal(fmt.Sprintf("[truncated %d bytes]", size))
+			truncated = true
+			continue
+		}
+		used += size
+	}
+	payload, _ = json.Marshal(exchanges)
+	return payload, truncated
+}
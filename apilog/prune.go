@@ -0,0 +1,59 @@
+package apilog
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// pruneInterval is how often RunPruner applies the retention limits.
+const pruneInterval = time.Hour
+
+// Prune deletes logged calls older than retention_days, then the oldest
+// beyond max_rows, and returns how many rows it deleted. SQLite reuses the
+// freed pages, so the file stops growing rather than shrinking.
+func Prune(ctx context.Context, store *db.Store, cfg config.APILogConfig) (int64, error) {
+	var deleted int64
+	if cfg.RetentionDays > 0 {
+		n, err := store.DeleteAPIRequestsBefore(ctx, time.Now().UTC().AddDate(0, 0, -cfg.RetentionDays))
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	if cfg.MaxRows > 0 {
+		n, err := store.DeleteAPIRequestsBeyond(ctx, cfg.MaxRows)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// RunPruner applies the api_log retention limits now and every hour until
+// ctx is cancelled. It returns at once if no limit is set.
+func RunPruner(ctx context.Context, store *db.Store, cfg config.APILogConfig) {
+	if cfg.RetentionDays == 0 && cfg.MaxRows == 0 {
+		return
+	}
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := Prune(ctx, store, cfg); err != nil {
+			log.Printf("apilog: pruning failed: %v", err)
+		} else if n > 0 {
+			log.Printf("apilog: pruned %d logged calls", n)
+		}
+		select {
+		case <-ctx.Done():
+			log.Println("API log pruner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
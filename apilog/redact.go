@@ -0,0 +1,151 @@
+package apilog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/RaghavSood/fundbot/config"
+)
+
+// redacted replaces masked values. It needs no escaping in a query string.
+const redacted = "REDACTED"
+
+// Names masked whatever the config says: the auth headers, key parameters
+// and secrets the providers and the resolver send. Matching ignores case.
+var (
+	defaultRedactHeaders = []string{
+		"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+		"X-Api-Key", "0x-Api-Key", "X-Integrator-Id", "Garden-App-Id",
+	}
+	defaultRedactQueryParams = []string{
+		"api_key", "apikey", "key", "x_cg_demo_api_key", "x_cg_pro_api_key",
+	}
+	defaultRedactJSONFields = []string{
+		"api_key", "apiKey", "secret", "signature", "private_key", "privateKey", "password",
+	}
+)
+
+// redactor masks header values, query parameters and JSON fields by name.
+type redactor struct {
+	headers     map[string]bool // lower case
+	queryParams map[string]bool // lower case
+	jsonFields  map[string]bool // lower case
+}
+
+var activeRedactor atomic.Pointer[redactor]
+
+func init() {
+	SetRedaction(config.APILogRedactConfig{})
+}
+
+// SetRedaction masks the names in cfg, besides the defaults, in every call
+// logged from now on, and in captured exchanges. Calls already stored are
+// left as they are.
+func SetRedaction(cfg config.APILogRedactConfig) {
+	activeRedactor.Store(&redactor{
+		headers:     nameSet(defaultRedactHeaders, cfg.Headers),
+		queryParams: nameSet(defaultRedactQueryParams, cfg.QueryParams),
+		jsonFields:  nameSet(defaultRedactJSONFields, cfg.JSONFields),
+	})
+}
+
+func nameSet(lists ...[]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, list := range lists {
+		for _, name := range list {
+			if name = strings.TrimSpace(name); name != "" {
+				set[strings.ToLower(name)] = true
+			}
+		}
+	}
+	return set
+}
+
+// header returns h as headerString would, with masked values replaced.
+func (r *redactor) header(h http.Header) string {
+	masked := h.Clone()
+	for name, values := range masked {
+		if r.headers[strings.ToLower(name)] {
+			for i := range values {
+				values[i] = redacted
+			}
+		}
+	}
+	return headerString(masked)
+}
+
+// url returns u with the values of masked query parameters replaced. The
+// other parameters keep their order and encoding.
+func (r *redactor) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	parts := strings.Split(u.RawQuery, "&")
+	changed := false
+	for i, part := range parts {
+		rawName, _, _ := strings.Cut(part, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		if r.queryParams[strings.ToLower(name)] {
+			parts[i] = rawName + "=" + redacted
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	masked := *u
+	masked.RawQuery = strings.Join(parts, "&")
+	return masked.String()
+}
+
+// body returns a JSON body with the values of masked fields replaced, at
+// any depth. Bodies that aren't JSON, or have nothing to mask, are
+// returned as they are.
+func (r *redactor) body(body []byte) []byte {
+	if len(r.jsonFields) == 0 || !json.Valid(body) {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !r.mask(v) {
+		return body
+	}
+	masked, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return masked
+}
+
+// mask replaces the masked fields' values in v and reports whether there
+// were any.
+func (r *redactor) mask(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if r.jsonFields[strings.ToLower(k)] {
+				v[k] = redacted
+				changed = true
+				continue
+			}
+			changed = r.mask(val) || changed
+		}
+	case []any:
+		for _, val := range v {
+			changed = r.mask(val) || changed
+		}
+	}
+	return changed
+}
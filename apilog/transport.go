@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/RaghavSood/fundbot/db"
@@ -14,6 +15,9 @@ import (
 
 const maxBodySize = 64 * 1024 // 64KB
 
+// pending counts log writes still in flight, so shutdown can wait for them
+var pending sync.WaitGroup
+
 // Transport is an http.RoundTripper that logs all requests and responses to the database.
 type Transport struct {
 	inner    http.RoundTripper
@@ -21,7 +25,12 @@ type Transport struct {
 	store    *db.Store
 }
 
+// NewHTTPClient returns a client that logs provider's requests to store. With
+// a nil store nothing is logged.
 func NewHTTPClient(provider string, store *db.Store) *http.Client {
+	if store == nil {
+		return &http.Client{Timeout: 30 * time.Second}
+	}
 	return &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &Transport{
@@ -40,7 +49,10 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		req.Body = io.NopCloser(bytes.NewReader(reqBody))
 	}
 
-	reqHeaders := headerString(req.Header)
+	// Secrets are masked before anything is stored
+	redact := activeRedactor.Load()
+	reqURL := redact.url(req.URL)
+	reqHeaders := redact.header(req.Header)
 
 	start := time.Now()
 	resp, err := t.inner.RoundTrip(req)
@@ -49,14 +61,18 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	params := db.InsertAPIRequestParams{
 		Provider:       t.provider,
 		Method:         req.Method,
-		Url:            req.URL.String(),
+		Url:            reqURL,
 		RequestHeaders: toNullString(reqHeaders),
-		RequestBody:    toNullString(truncate(string(reqBody))),
+		RequestBody:    toNullString(truncate(string(redact.body(reqBody)))),
 		DurationMs:     sql.NullInt64{Int64: duration, Valid: true},
 	}
 
+	capture := captureFrom(req.Context())
 	if err != nil {
 		params.Error = toNullString(err.Error())
+		if capture != nil {
+			capture.add(Exchange{Method: req.Method, URL: reqURL, Error: err.Error()}, nil)
+		}
 	} else {
 		// Capture response body
 		var respBody []byte
@@ -64,13 +80,19 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 			respBody, _ = io.ReadAll(resp.Body)
 			resp.Body = io.NopCloser(bytes.NewReader(respBody))
 		}
+		loggedBody := redact.body(respBody)
 		params.ResponseStatus = sql.NullInt64{Int64: int64(resp.StatusCode), Valid: true}
-		params.ResponseHeaders = toNullString(headerString(resp.Header))
-		params.ResponseBody = toNullString(truncate(string(respBody)))
+		params.ResponseHeaders = toNullString(redact.header(resp.Header))
+		params.ResponseBody = toNullString(truncate(string(loggedBody)))
+		if capture != nil {
+			capture.add(Exchange{Method: req.Method, URL: reqURL, Status: resp.StatusCode}, loggedBody)
+		}
 	}
 
 	// Insert asynchronously so we don't slow down the request
+	pending.Add(1)
 	go func() {
+		defer pending.Done()
 		if dbErr := t.store.InsertAPIRequest(context.Background(), params); dbErr != nil {
 			log.Printf("apilog: failed to log %s %s: %v", params.Method, params.Url, dbErr)
 		}
@@ -79,6 +101,21 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+// Flush waits for in-flight log writes to finish, or for ctx to be done.
+func Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func headerString(h http.Header) string {
 	var buf bytes.Buffer
 	h.Write(&buf)
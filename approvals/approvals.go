@@ -0,0 +1,443 @@
+// Package approvals holds topups and gas refills above approvals.threshold_usd
+// until the admin approves or rejects them, from Telegram or the admin panel,
+// and runs the approved ones.
+package approvals
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/topups"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// ErrStatus is returned when deciding on a request that was already
+// decided or has expired.
+var ErrStatus = errors.New("approval already decided")
+
+// Service queues requests for approval, asks the admin about them on
+// Telegram and runs them once approved.
+type Service struct {
+	cfg        *config.Config
+	store      *db.Store
+	topups     *topups.Service
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+	cowClient  *cowswap.Client
+	hooks      *webhooks.Client
+
+	// botAPI asks the admin and tells requesters, once the bot is running
+	botMu  sync.RWMutex
+	botAPI *tgbotapi.BotAPI
+
+	// Decisions run one at a time, so a request can't be run twice
+	mu sync.Mutex
+}
+
+func New(cfg *config.Config, store *db.Store, svc *topups.Service, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client, cowClient *cowswap.Client, hooks *webhooks.Client) *Service {
+	return &Service{
+		cfg:        cfg,
+		store:      store,
+		topups:     svc,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+		cowClient:  cowClient,
+		hooks:      hooks,
+	}
+}
+
+// SetBotAPI lets the service message the admin and requesters.
+func (s *Service) SetBotAPI(botAPI *tgbotapi.BotAPI) {
+	s.botMu.Lock()
+	defer s.botMu.Unlock()
+	s.botAPI = botAPI
+}
+
+func (s *Service) bot() *tgbotapi.BotAPI {
+	s.botMu.RLock()
+	defer s.botMu.RUnlock()
+	return s.botAPI
+}
+
+// Required reports whether a topup or gas refill of usd asked for by
+// userID has to wait for approval. The admin's own requests don't, nor do
+// watch-only wallets, whose transactions are signed offline anyway. A nil
+// Service requires nothing.
+func (s *Service) Required(usd float64, userID int64) bool {
+	if s == nil || userID == s.cfg.AdminUserID || usd <= s.cfg.Approvals.ThresholdUSD {
+		return false
+	}
+	return !wallet.WatchOnly(s.keyring)
+}
+
+// Run expires requests left undecided past approvals.expiry_hours, telling
+// their requesters.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Approvals stopped")
+			return
+		case <-ticker.C:
+			s.expire(ctx)
+		}
+	}
+}
+
+// RequestTopup queues req for approval and asks the admin. messageID is
+// the Telegram message the requester is answered under, or 0.
+func (s *Service) RequestTopup(ctx context.Context, req topups.Request, messageID int) (db.Approval, error) {
+	index, err := s.topups.WalletIndex(ctx, req.Owner)
+	if err != nil {
+		return db.Approval{}, err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return db.Approval{}, err
+	}
+	a, err := s.store.InsertApproval(ctx, db.InsertApprovalParams{
+		Kind:        "topup",
+		AmountUsd:   req.USDAmount,
+		Summary:     fmt.Sprintf("$%.2f topup → %s to %s", req.USDAmount, req.Asset, req.Destination),
+		Request:     string(payload),
+		WalletIndex: int64(index),
+		UserID:      req.Owner.UserID,
+		ChatID:      req.Owner.ChatID,
+		MessageID:   int64(messageID),
+	})
+	if err != nil {
+		return db.Approval{}, fmt.Errorf("storing approval: %w", err)
+	}
+	s.askAdmin(ctx, a, req.Owner.Username)
+	return a, nil
+}
+
+// RequestGasRefill queues a gas refill of usd on chain for the wallet at
+// index and asks the admin. It returns false, queueing nothing, if one is
+// already waiting for that wallet and chain.
+func (s *Service) RequestGasRefill(ctx context.Context, chain string, index uint32, userID, chatID int64, usd float64) (db.Approval, bool, error) {
+	n, err := s.store.CountPendingGasRefillApprovals(ctx, db.CountPendingGasRefillApprovalsParams{Chain: chain, WalletIndex: int64(index)})
+	if err != nil {
+		return db.Approval{}, false, err
+	}
+	if n > 0 {
+		return db.Approval{}, false, nil
+	}
+	a, err := s.store.InsertApproval(ctx, db.InsertApprovalParams{
+		Kind:        "gas_refill",
+		AmountUsd:   usd,
+		Summary:     fmt.Sprintf("$%.2f gas refill on %s for wallet %d", usd, chain, index),
+		Chain:       chain,
+		WalletIndex: int64(index),
+		UserID:      userID,
+		ChatID:      chatID,
+	})
+	if err != nil {
+		return db.Approval{}, false, fmt.Errorf("storing approval: %w", err)
+	}
+	s.askAdmin(ctx, a, "")
+	return a, true, nil
+}
+
+// ListPending returns the requests still waiting, oldest first, after
+// expiring overdue ones.
+func (s *Service) ListPending(ctx context.Context) ([]db.Approval, error) {
+	s.expire(ctx)
+	return s.store.ListPendingApprovals(ctx)
+}
+
+// Approve marks a pending request approved by by and runs it. Failures to
+// run it are recorded on the returned approval, with status failed, rather
+// than returned.
+func (s *Service) Approve(ctx context.Context, id int64, by string) (db.Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.decide(ctx, id, "approved", by, "")
+	if err != nil {
+		return a, err
+	}
+	log.Printf("Approval %d (%s) approved by %s", a.ID, a.Summary, by)
+
+	signCtx := wallet.WithConfirmNotifier(ctx, func(what string) {
+		s.send(s.cfg.AdminUserID, 0, fmt.Sprintf("Confirm the %s on the hardware wallet to run approval #%d.", what, a.ID))
+	})
+	var text string
+	switch a.Kind {
+	case "topup":
+		a.Result, text, err = s.runTopup(signCtx, a)
+	case "gas_refill":
+		a.Result, text, err = s.runGasRefill(signCtx, a)
+	default:
+		err = fmt.Errorf("unknown kind %q", a.Kind)
+	}
+	a.Status = "executed"
+	if err != nil {
+		a.Status, a.Result = "failed", err.Error()
+		text = fmt.Sprintf("Your %s (approval #%d) was approved, but failed: %v", a.Summary, a.ID, err)
+		log.Printf("Approval %d failed: %v", a.ID, err)
+	}
+	if err := s.store.FinishApproval(ctx, db.FinishApprovalParams{Status: a.Status, Result: a.Result, ID: a.ID}); err != nil {
+		log.Printf("Error recording result of approval %d: %v", a.ID, err)
+	}
+	s.send(a.ChatID, int(a.MessageID), text)
+	s.updateAdmin(a)
+	return a, nil
+}
+
+// Reject marks a pending request rejected by by, with an optional note for
+// the requester.
+func (s *Service) Reject(ctx context.Context, id int64, by, note string) (db.Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.decide(ctx, id, "rejected", by, note)
+	if err != nil {
+		return a, err
+	}
+	log.Printf("Approval %d (%s) rejected by %s", a.ID, a.Summary, by)
+
+	text := fmt.Sprintf("Your %s (approval #%d) was rejected by the admin.", a.Summary, a.ID)
+	if note != "" {
+		text += "\nNote: " + note
+	}
+	s.send(a.ChatID, int(a.MessageID), text)
+	s.updateAdmin(a)
+	return a, nil
+}
+
+// decide moves a pending request to status, failing with ErrStatus if it
+// is no longer pending.
+func (s *Service) decide(ctx context.Context, id int64, status, by, note string) (db.Approval, error) {
+	s.expire(ctx)
+	n, err := s.store.DecideApproval(ctx, db.DecideApprovalParams{Status: status, DecidedBy: by, Note: note, ID: id})
+	if err != nil {
+		return db.Approval{}, err
+	}
+	a, err := s.store.GetApproval(ctx, id)
+	if err != nil {
+		return a, err
+	}
+	if n == 0 {
+		return a, fmt.Errorf("%w: approval %d is %s", ErrStatus, id, a.Status)
+	}
+	return a, nil
+}
+
+// expire marks requests older than approvals.expiry_hours expired and
+// tells their requesters and the admin.
+func (s *Service) expire(ctx context.Context) {
+	cutoff := time.Now().UTC().Add(-time.Duration(s.cfg.Approvals.ExpiryHours) * time.Hour)
+	expired, err := s.store.ExpireApprovals(ctx, cutoff)
+	if err != nil {
+		log.Printf("Error expiring approvals: %v", err)
+		return
+	}
+	for _, a := range expired {
+		log.Printf("Approval %d (%s) expired", a.ID, a.Summary)
+		s.send(a.ChatID, int(a.MessageID), fmt.Sprintf("Your %s (approval #%d) expired without a decision. Ask again if it is still needed.", a.Summary, a.ID))
+		s.updateAdmin(a)
+	}
+}
+
+// runTopup executes an approved topup, returning the result recorded on
+// the approval and the message for the requester.
+func (s *Service) runTopup(ctx context.Context, a db.Approval) (string, string, error) {
+	var req topups.Request
+	if err := json.Unmarshal([]byte(a.Request), &req); err != nil {
+		return "", "", fmt.Errorf("decoding request: %w", err)
+	}
+	result, err := s.topups.Execute(ctx, req, func(text string) {
+		s.send(a.ChatID, int(a.MessageID), text)
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	explorerURL := s.cfg.ExplorerTxURL(result.Quote.FromChain, result.Swap.TxHash)
+	text := fmt.Sprintf("Your %s (approval #%d) was approved.\n*Topup %s*\nTx: `%s`\n[Explorer](%s)\nUse /status %s to check progress.",
+		a.Summary, a.ID, result.Topup.ShortID, result.Swap.TxHash, explorerURL, result.Topup.ShortID)
+	if result.Fresh != nil {
+		text += fmt.Sprintf("\nSent from fresh address `%s`; refunds go there.", result.Fresh.Address)
+	}
+	if result.Topup.ShortID == "" {
+		return "tx " + result.Swap.TxHash, text, nil
+	}
+	return "topup " + result.Topup.ShortID, text, nil
+}
+
+// runGasRefill places an approved gas refill for the amount approved, at
+// the wallet's balances now. A wallet that no longer needs gas, or can no
+// longer pay for it, gets no order.
+func (s *Service) runGasRefill(ctx context.Context, a db.Approval) (string, string, error) {
+	settings, ok := s.cfg.GasRefill(a.Chain)
+	usdc, hasUSDC := thorchain.FundingTokens.Lookup(a.Chain, "USDC")
+	rpc, enabled := swaps.EnabledClients(s.rpcClients)[a.Chain]
+	if !ok || !hasUSDC || usdc.Native || !enabled || s.cowClient == nil {
+		return "", "", fmt.Errorf("gas refills are not available on %s", a.Chain)
+	}
+	signer, err := s.keyring.Signer(uint32(a.WalletIndex))
+	if err != nil {
+		return "", "", fmt.Errorf("loading signer: %w", err)
+	}
+	if _, ok := signer.(swaps.CallSender); ok {
+		return "", "", fmt.Errorf("smart accounts pay gas through the paymaster")
+	}
+	addr := signer.Address()
+
+	native, err := rpc.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("reading %s balance: %w", a.Chain, err)
+	}
+	usdcBal, err := balances.TokenBalance(ctx, rpc, usdc.Address, addr)
+	if err != nil {
+		return "", "", fmt.Errorf("reading USDC balance: %w", err)
+	}
+
+	guards := cowswap.Guards{SlippageBps: settings.SlippageBps, MaxFeeBps: settings.MaxFeeBps, MaxDeviationBps: settings.MaxDeviationBps}
+	result, err := s.cowClient.RefillGasIfNeeded(ctx, a.Chain, signer, native, usdcBal, settings.MinNative(), settings.TargetNative(), usdc.Amount(a.AmountUsd), guards)
+	if err != nil || result != nil {
+		if err := s.store.SetGasRefillCooldown(ctx, db.SetGasRefillCooldownParams{Chain: a.Chain, WalletAddress: addr.Hex()}); err != nil {
+			log.Printf("Error recording gas refill cooldown on %s: %v", a.Chain, err)
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if result == nil {
+		return "not needed any more", fmt.Sprintf("Your %s (approval #%d) was approved, but the wallet no longer needs gas there or has too little USDC.", a.Summary, a.ID), nil
+	}
+
+	params := db.InsertGasRefillParams{
+		Chain:         result.Chain,
+		OrderUid:      result.OrderUID,
+		WalletAddress: addr.Hex(),
+		SellAmount:    result.SellAmount,
+		BuyAmount:     result.BuyAmount,
+		Status:        "open",
+		UserID:        a.UserID,
+		ChatID:        a.ChatID,
+		WalletIndex:   a.WalletIndex,
+		Attempt:       1,
+		OrderType:     "market",
+		Kind:          result.Kind,
+		ValidTo:       sql.NullTime{Time: result.ValidTo, Valid: true},
+	}
+	refillID, err := s.store.InsertGasRefill(ctx, params)
+	if err != nil {
+		log.Printf("Error storing gas refill record: %v", err)
+	} else {
+		s.hooks.GasRefillStatus(webhooks.GasRefill{
+			ID:            refillID,
+			Status:        params.Status,
+			Chain:         params.Chain,
+			OrderUID:      params.OrderUid,
+			WalletAddress: params.WalletAddress,
+			SellAmount:    params.SellAmount,
+			BuyAmount:     params.BuyAmount,
+			Attempt:       params.Attempt,
+			OrderType:     params.OrderType,
+			Kind:          params.Kind,
+			UserID:        params.UserID,
+			ChatID:        params.ChatID,
+		})
+	}
+	text := fmt.Sprintf("Your %s (approval #%d) was approved; the order is on CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
+		a.Summary, a.ID, result.OrderUID)
+	return "order " + result.OrderUID, text, nil
+}
+
+// askAdmin sends admin_user_id the request with Approve and Reject buttons,
+// and records the message so it can be updated once decided.
+func (s *Service) askAdmin(ctx context.Context, a db.Approval, username string) {
+	botAPI := s.bot()
+	if botAPI == nil {
+		return
+	}
+	text := fmt.Sprintf("Approval #%d: %s\nChat %d", a.ID, a.Summary, a.ChatID)
+	switch {
+	case username != "":
+		text += ", requested by @" + username
+	case a.UserID != 0:
+		text += ", requested by user " + strconv.FormatInt(a.UserID, 10)
+	}
+	text += fmt.Sprintf(".\nIt expires if not decided within %d hours.", s.cfg.Approvals.ExpiryHours)
+	msg := tgbotapi.NewMessage(s.cfg.AdminUserID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Approve", fmt.Sprintf("approval:approve:%d", a.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("Reject", fmt.Sprintf("approval:reject:%d", a.ID)),
+		),
+	)
+	sent, err := botAPI.Send(msg)
+	if err != nil {
+		log.Printf("Error asking admin about approval %d: %v", a.ID, err)
+		return
+	}
+	if err := s.store.SetApprovalAdminMessage(ctx, db.SetApprovalAdminMessageParams{AdminMessageID: int64(sent.MessageID), ID: a.ID}); err != nil {
+		log.Printf("Error recording admin message of approval %d: %v", a.ID, err)
+	}
+}
+
+// updateAdmin replaces the admin's prompt for a decided request with the
+// decision, removing its buttons.
+func (s *Service) updateAdmin(a db.Approval) {
+	botAPI := s.bot()
+	if botAPI == nil || a.AdminMessageID == 0 {
+		return
+	}
+	text := fmt.Sprintf("Approval #%d: %s\n%s", a.ID, a.Summary, a.Status)
+	if a.DecidedBy != "" {
+		text += " by " + a.DecidedBy
+	}
+	if a.Result != "" {
+		text += ": " + a.Result
+	}
+	if a.Note != "" {
+		text += "\nNote: " + a.Note
+	}
+	edit := tgbotapi.NewEditMessageText(s.cfg.AdminUserID, int(a.AdminMessageID), text)
+	if _, err := botAPI.Send(edit); err != nil {
+		log.Printf("Error updating admin message of approval %d: %v", a.ID, err)
+	}
+}
+
+// send posts a Markdown message to chatID, under replyTo if set, doing
+// nothing for chat 0.
+func (s *Service) send(chatID int64, replyTo int, text string) {
+	botAPI := s.bot()
+	if botAPI == nil || chatID == 0 {
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = replyTo
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	if _, err := botAPI.Send(msg); err != nil {
+		// Summaries carry user input, which can break Markdown
+		msg.ParseMode = ""
+		if _, err := botAPI.Send(msg); err != nil {
+			log.Printf("Error notifying chat %d of approval: %v", chatID, err)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Package backup snapshots the SQLite database on a schedule, keeping a
+// fixed number of rotations on disk and optionally in S3.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+const (
+	// filePrefix and fileSuffix surround the UTC timestamp in snapshot
+	// names, so names sort by age
+	filePrefix = "fundbot-"
+	fileSuffix = ".db"
+	timeLayout = "20060102-150405"
+)
+
+// File is a snapshot in the backup directory.
+type File struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Status describes the backup schedule and its latest run.
+type Status struct {
+	Dir             string     `json:"dir"`
+	S3Bucket        string     `json:"s3_bucket,omitempty"`
+	IntervalMinutes int        `json:"interval_minutes"`
+	Keep            int        `json:"keep"`
+	LastRun         *time.Time `json:"last_run"`
+	LastSuccess     *time.Time `json:"last_success"`
+
+	// Why the last run failed; empty if it succeeded
+	LastError string `json:"last_error"`
+
+	// Why the last upload failed; the local snapshot is kept regardless
+	UploadError string `json:"upload_error,omitempty"`
+
+	// Snapshots in dir, newest first
+	Files []File `json:"files"`
+}
+
+// Backuper writes a snapshot every interval_minutes and prunes old ones.
+type Backuper struct {
+	cfg   *config.BackupConfig
+	store *db.Store
+	s3    *s3.Client // nil without backup.s3
+
+	// runMu keeps scheduled and admin-requested runs from overlapping
+	runMu sync.Mutex
+
+	mu          sync.Mutex
+	lastRun     time.Time
+	lastSuccess time.Time
+	lastErr     error
+	uploadErr   error
+}
+
+// New returns a Backuper for cfg, loading AWS credentials if snapshots go
+// to S3.
+func New(ctx context.Context, cfg *config.BackupConfig, store *db.Store) (*Backuper, error) {
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating backup dir: %w", err)
+	}
+	b := &Backuper{cfg: cfg, store: store}
+	if cfg.S3 != nil {
+		var opts []func(*awsconfig.LoadOptions) error
+		if cfg.S3.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(cfg.S3.Region))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("loading AWS config: %w", err)
+		}
+		b.s3 = s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			if cfg.S3.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.S3.Endpoint)
+				o.UsePathStyle = true
+			}
+		})
+	}
+	return b, nil
+}
+
+// Run takes a snapshot every interval_minutes until ctx is cancelled. The
+// schedule carries on from the newest snapshot on disk, so restarts neither
+// delay nor repeat a backup.
+func (b *Backuper) Run(ctx context.Context) {
+	interval := time.Duration(b.cfg.IntervalMinutes) * time.Minute
+	var wait time.Duration
+	if files, err := b.Files(); err == nil && len(files) > 0 {
+		wait = max(interval-time.Since(files[0].CreatedAt), 0)
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Backups stopped")
+			return
+		case <-timer.C:
+			if _, err := b.Backup(ctx); err != nil {
+				log.Printf("backup: %v", err)
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// Backup takes a snapshot now, uploads it if S3 is configured and removes
+// snapshots beyond keep. A failed upload is recorded in Status but doesn't
+// fail the backup.
+func (b *Backuper) Backup(ctx context.Context) (File, error) {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	file, err := b.snapshot(ctx, now)
+	var uploadErr error
+	if err == nil && b.s3 != nil {
+		uploadErr = b.upload(ctx, file)
+		if uploadErr != nil {
+			log.Printf("backup: uploading %s: %v", file.Name, uploadErr)
+		}
+	}
+
+	b.mu.Lock()
+	b.lastRun, b.lastErr, b.uploadErr = now, err, uploadErr
+	if err == nil {
+		b.lastSuccess = now
+	}
+	b.mu.Unlock()
+	if err != nil {
+		return File{}, err
+	}
+	log.Printf("Backed up database to %s (%d bytes)", file.Name, file.Size)
+
+	if err := b.pruneLocal(); err != nil {
+		log.Printf("backup: pruning %s: %v", b.cfg.Dir, err)
+	}
+	if b.s3 != nil && uploadErr == nil {
+		if err := b.pruneS3(ctx); err != nil {
+			log.Printf("backup: pruning s3://%s: %v", b.cfg.S3.Bucket, err)
+		}
+	}
+	return file, nil
+}
+
+// snapshot writes the database to a temporary file and renames it into
+// place, so a snapshot cut short never looks complete.
+func (b *Backuper) snapshot(ctx context.Context, now time.Time) (File, error) {
+	name := filePrefix + now.Format(timeLayout) + fileSuffix
+	path := filepath.Join(b.cfg.Dir, name)
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := b.store.Backup(ctx, tmp); err != nil {
+		os.Remove(tmp)
+		return File{}, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return File{}, fmt.Errorf("renaming snapshot: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Name: name, Size: info.Size(), CreatedAt: now}, nil
+}
+
+func (b *Backuper) upload(ctx context.Context, file File) error {
+	f, err := os.Open(filepath.Join(b.cfg.Dir, file.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = b.s3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(b.cfg.S3.Bucket),
+		Key:           aws.String(b.cfg.S3.Prefix + file.Name),
+		Body:          f,
+		ContentLength: aws.Int64(file.Size),
+	})
+	return err
+}
+
+// Files lists the snapshots in the backup directory, newest first.
+func (b *Backuper) Files() ([]File, error) {
+	entries, err := os.ReadDir(b.cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		created, err := time.Parse(timeLayout, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix))
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{Name: name, Size: info.Size(), CreatedAt: created})
+	}
+	slices.SortFunc(files, func(x, y File) int { return y.CreatedAt.Compare(x.CreatedAt) })
+	return files, nil
+}
+
+func (b *Backuper) pruneLocal() error {
+	files, err := b.Files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files[min(b.cfg.Keep, len(files)):] {
+		if err := os.Remove(filepath.Join(b.cfg.Dir, f.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Backuper) pruneS3(ctx context.Context) error {
+	var keys []string
+	pages := s3.NewListObjectsV2Paginator(b.s3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.cfg.S3.Bucket),
+		Prefix: aws.String(b.cfg.S3.Prefix + filePrefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			if strings.HasSuffix(aws.ToString(obj.Key), fileSuffix) {
+				keys = append(keys, aws.ToString(obj.Key))
+			}
+		}
+	}
+	// Names sort by age, so the oldest come first
+	slices.Sort(keys)
+	for _, key := range keys[:max(len(keys)-b.cfg.Keep, 0)] {
+		if _, err := b.s3.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(b.cfg.S3.Bucket), Key: aws.String(key)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status reports the schedule, the latest run and the snapshots on disk.
+func (b *Backuper) Status() (Status, error) {
+	files, err := b.Files()
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{
+		Dir:             b.cfg.Dir,
+		IntervalMinutes: b.cfg.IntervalMinutes,
+		Keep:            b.cfg.Keep,
+		Files:           files,
+	}
+	if b.cfg.S3 != nil {
+		st.S3Bucket = b.cfg.S3.Bucket
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lastRun := b.lastRun; !lastRun.IsZero() {
+		st.LastRun = &lastRun
+	}
+	if lastSuccess := b.lastSuccess; !lastSuccess.IsZero() {
+		st.LastSuccess = &lastSuccess
+	}
+	if b.lastErr != nil {
+		st.LastError = b.lastErr.Error()
+	}
+	if b.uploadErr != nil {
+		st.UploadError = b.uploadErr.Error()
+	}
+	return st, nil
+}
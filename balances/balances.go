@@ -3,19 +3,33 @@ package balances
 import (
 	"context"
 	"fmt"
+	"log"
 	"math/big"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/RaghavSood/fundbot/config"
 	"github.com/RaghavSood/fundbot/contracts"
 )
 
 var multicallAddr = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
 
+const (
+	// maxMulticallCalls caps the calls packed into one aggregate3. Larger
+	// batches run into the node's gas and response size limits once there
+	// are a few hundred wallets.
+	maxMulticallCalls = 300
+
+	// chunkConcurrency is how many aggregate3 calls run at once per chain
+	chunkConcurrency = 4
+)
+
 var erc20ABI abi.ABI
 
 func init() {
@@ -28,23 +42,167 @@ func init() {
 
 // AddressBalance holds balance info for a single address on a single chain.
 type AddressBalance struct {
-	Address       string `json:"address"`
-	Chain         string `json:"chain"`
-	NativeBalance string `json:"native_balance"` // wei string
-	USDCBalance   string `json:"usdc_balance"`   // smallest unit string
+	Address       string   `json:"address"`
+	Chain         string   `json:"chain"`
+	NativeBalance string   `json:"native_balance"` // wei string
+	TokenBalances []string `json:"token_balances"` // smallest unit strings, in the order of the chain's token list
+
+	// USD values, set by Value. NativeUSD is only meaningful when
+	// NativePriced is set; TokenUSD is in the order of TokenBalances.
+	NativeUSD    float64   `json:"native_usd"`
+	NativePriced bool      `json:"native_priced"`
+	TokenUSD     []float64 `json:"token_usd"`
+
+	// Balances of the chain's watchlist tokens, in watchlist order
+	Watched []WatchedBalance `json:"watched,omitempty"`
+}
+
+// WatchedToken is an ERC20 read beside the funding tokens, such as USDT or
+// WETH left in a wallet by a failed swap.
+type WatchedToken struct {
+	Symbol   string
+	Address  common.Address
+	Decimals int
+
+	// Stable tokens count at $1 in Value; others are left unpriced
+	Stable bool
+}
+
+// Watchlist returns the config's token_watchlist as the watchlist argument
+// of FetchBalances.
+func Watchlist(cfg *config.Config) map[string][]WatchedToken {
+	tokens := make(map[string][]WatchedToken, len(cfg.TokenWatchlist))
+	for chain, list := range cfg.TokenWatchlist {
+		for _, t := range list {
+			tokens[chain] = append(tokens[chain], WatchedToken{
+				Symbol:   t.Symbol,
+				Address:  common.HexToAddress(t.Address),
+				Decimals: t.Decimals,
+				Stable:   t.Stable,
+			})
+		}
+	}
+	return tokens
+}
+
+// WatchedBalance is a wallet's balance of a WatchedToken. Priced is set
+// for stable tokens, whose USD Value fills in.
+type WatchedBalance struct {
+	Symbol   string  `json:"symbol"`
+	Balance  string  `json:"balance"` // smallest unit string
+	Decimals int     `json:"decimals"`
+	USD      float64 `json:"usd"`
+	Priced   bool    `json:"priced"`
+}
+
+// WalletBalances is one address's balances on every chain read.
+type WalletBalances struct {
+	Address common.Address
+	Chains  []AddressBalance // in the order FetchBalances returned them
+}
+
+// GroupByAddress collects fetched balances by address, one entry per
+// address in the order of addresses, so callers get a wallet's chains
+// together. Repeated addresses get one entry; an address with no balances
+// gets an empty one.
+func GroupByAddress(bals []AddressBalance, addresses []common.Address) []WalletBalances {
+	index := make(map[common.Address]int, len(addresses))
+	wallets := make([]WalletBalances, 0, len(addresses))
+	for _, addr := range addresses {
+		if _, ok := index[addr]; ok {
+			continue
+		}
+		index[addr] = len(wallets)
+		wallets = append(wallets, WalletBalances{Address: addr})
+	}
+	for _, b := range bals {
+		if i, ok := index[common.HexToAddress(b.Address)]; ok {
+			wallets[i].Chains = append(wallets[i].Chains, b)
+		}
+	}
+	return wallets
+}
+
+// USD returns the balance's total value, leaving out an unpriced native
+// coin and unpriced watched tokens.
+func (b AddressBalance) USD() float64 {
+	total := b.NativeUSD
+	for _, usd := range b.TokenUSD {
+		total += usd
+	}
+	for _, w := range b.Watched {
+		total += w.USD
+	}
+	return total
 }
 
-// USDCBalance returns the USDC balance (smallest unit) for a single address on a single chain.
-func USDCBalance(ctx context.Context, rpc *ethclient.Client, usdcAddr common.Address, addr common.Address) (*big.Int, error) {
+// Value fills in the USD values of fetched balances. Native coins (18
+// decimals on every supported chain) are priced with nativeUSD, once per
+// chain; tokens are the funding stablecoins and count at $1. tokenDecimals
+// maps chain key to the decimals of the tokens passed to FetchBalances, in
+// the same order. Watched tokens count at $1 if they are stable and are
+// otherwise left unpriced. Chains whose native coin can't be priced are
+// logged and left unpriced rather than failing the whole view.
+func Value(ctx context.Context, bals []AddressBalance, nativeUSD func(ctx context.Context, chain string) (float64, error), tokenDecimals map[string][]int) {
+	prices := make(map[string]float64)
+	for i := range bals {
+		b := &bals[i]
+		price, ok := prices[b.Chain]
+		if !ok {
+			var err error
+			if price, err = nativeUSD(ctx, b.Chain); err != nil {
+				log.Printf("Error pricing %s native coin: %v", b.Chain, err)
+				price = 0
+			}
+			prices[b.Chain] = price
+		}
+		if price > 0 {
+			b.NativeUSD = units(b.NativeBalance, 18) * price
+			b.NativePriced = true
+		}
+
+		decimals := tokenDecimals[b.Chain]
+		b.TokenUSD = make([]float64, len(b.TokenBalances))
+		for j, raw := range b.TokenBalances {
+			if j < len(decimals) {
+				b.TokenUSD[j] = units(raw, decimals[j])
+			}
+		}
+		for j := range b.Watched {
+			if w := &b.Watched[j]; w.Priced {
+				w.USD = units(w.Balance, w.Decimals)
+			}
+		}
+	}
+}
+
+// units converts a smallest-unit amount string to whole tokens.
+func units(raw string, decimals int) float64 {
+	amount, ok := new(big.Float).SetString(raw)
+	if !ok {
+		return 0
+	}
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	f, _ := amount.Quo(amount, unit).Float64()
+	return f
+}
+
+// TokenBalance returns the ERC20 balance (smallest unit) of token for a single address on a single chain.
+func TokenBalance(ctx context.Context, rpc *ethclient.Client, token common.Address, addr common.Address) (*big.Int, error) {
+	return tokenBalanceAt(ctx, rpc, token, addr, nil)
+}
+
+// tokenBalanceAt is TokenBalance as of block, or the latest block if nil.
+func tokenBalanceAt(ctx context.Context, rpc *ethclient.Client, token common.Address, addr common.Address, block *big.Int) (*big.Int, error) {
 	balOfData, err := erc20ABI.Pack("balanceOf", addr)
 	if err != nil {
 		return nil, err
 	}
 
 	output, err := rpc.CallContract(ctx, ethereum.CallMsg{
-		To:   &usdcAddr,
+		To:   &token,
 		Data: balOfData,
-	}, nil)
+	}, block)
 	if err != nil {
 		return nil, err
 	}
@@ -57,32 +215,161 @@ func USDCBalance(ctx context.Context, rpc *ethclient.Client, usdcAddr common.Add
 	return bal, nil
 }
 
-// FetchBalances retrieves native + USDC balances for the given addresses on all chains.
-// usdcContracts maps chain key to USDC contract address.
-func FetchBalances(ctx context.Context, rpcClients map[string]*ethclient.Client, addresses []common.Address, usdcContracts map[string]common.Address) ([]AddressBalance, error) {
-	var results []AddressBalance
+// FetchBalances retrieves native + token balances for the given addresses on all chains.
+// tokenContracts maps chain key to the ERC20 contracts to read on that chain.
+// watchlist adds tokens per chain, read into Watched on the chains
+// tokenContracts lists; it may be nil. Chains are read concurrently; the
+// results are ordered by chain key, then in the order of addresses.
+func FetchBalances(ctx context.Context, rpcClients map[string]*ethclient.Client, addresses []common.Address, tokenContracts map[string][]common.Address, watchlist map[string][]WatchedToken) ([]AddressBalance, error) {
+	var chains []string
+	for chainKey := range rpcClients {
+		if _, ok := tokenContracts[chainKey]; ok {
+			chains = append(chains, chainKey)
+		}
+	}
+	slices.Sort(chains)
 
-	for chainKey, rpc := range rpcClients {
-		usdcAddr, ok := usdcContracts[chainKey]
-		if !ok {
-			continue
+	perChain := make([][]AddressBalance, len(chains))
+	errs := make([]error, len(chains))
+	var wg sync.WaitGroup
+	for i, chainKey := range chains {
+		wg.Go(func() {
+			perChain[i], errs[i] = fetchWatchedBalances(ctx, rpcClients[chainKey], chainKey, addresses, tokenContracts[chainKey], watchlist[chainKey])
+		})
+	}
+	wg.Wait()
+
+	var results []AddressBalance
+	for i, chainKey := range chains {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("fetching %s balances: %w", chainKey, errs[i])
 		}
+		results = append(results, perChain[i]...)
+	}
+	return results, nil
+}
 
-		balances, err := fetchChainBalances(ctx, rpc, chainKey, usdcAddr, addresses)
-		if err != nil {
-			return nil, fmt.Errorf("fetching %s balances: %w", chainKey, err)
+// fetchWatchedBalances reads one chain's balances of tokens, plus watched
+// tokens into Watched.
+func fetchWatchedBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, addresses []common.Address, tokens []common.Address, watched []WatchedToken) ([]AddressBalance, error) {
+	// Watched tokens are read after the chain's own, then split off
+	tokens = tokens[:len(tokens):len(tokens)]
+	for _, w := range watched {
+		tokens = append(tokens, w.Address)
+	}
+
+	balances, err := fetchChainBalances(ctx, rpc, chainKey, tokens, addresses, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range balances {
+		b := &balances[i]
+		split := len(b.TokenBalances) - len(watched)
+		for j, w := range watched {
+			b.Watched = append(b.Watched, WatchedBalance{
+				Symbol:   w.Symbol,
+				Balance:  b.TokenBalances[split+j],
+				Decimals: w.Decimals,
+				Priced:   w.Stable,
+			})
 		}
-		results = append(results, balances...)
+		b.TokenBalances = b.TokenBalances[:split]
 	}
+	return balances, nil
+}
 
-	return results, nil
+// FetchChainBalancesAt retrieves native + token balances for the given
+// addresses on one chain as of block, so they can be compared with events up
+// to that block. Nodes that don't keep old state only answer for recent blocks.
+func FetchChainBalancesAt(ctx context.Context, rpc *ethclient.Client, chainKey string, addresses []common.Address, tokens []common.Address, block *big.Int) ([]AddressBalance, error) {
+	return fetchChainBalances(ctx, rpc, chainKey, tokens, addresses, block)
 }
 
-func fetchChainBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, usdcAddr common.Address, addresses []common.Address) ([]AddressBalance, error) {
+// fetchChainBalances splits addresses into aggregate3 calls of at most
+// maxMulticallCalls calls, runs up to chunkConcurrency of them at once and
+// returns the balances in the order of addresses.
+func fetchChainBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, tokens []common.Address, addresses []common.Address, block *big.Int) ([]AddressBalance, error) {
 	if len(addresses) == 0 {
 		return nil, nil
 	}
 
+	// Each address has one native call followed by one call per token
+	perChunk := max(1, maxMulticallCalls/(1+len(tokens)))
+	if len(addresses) <= perChunk {
+		return fetchChunkBalances(ctx, rpc, chainKey, tokens, addresses, block)
+	}
+
+	chunks := slices.Collect(slices.Chunk(addresses, perChunk))
+	results := make([][]AddressBalance, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, chunkConcurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = fetchChunkBalances(ctx, rpc, chainKey, tokens, chunk, block)
+		})
+	}
+	wg.Wait()
+
+	bals := make([]AddressBalance, 0, len(addresses))
+	for i := range chunks {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("addresses %d-%d: %w", i*perChunk, i*perChunk+len(chunks[i])-1, errs[i])
+		}
+		bals = append(bals, results[i]...)
+	}
+	return bals, nil
+}
+
+// fetchChunkBalances reads addresses' balances in a single aggregate3 call,
+// falling back to one call per balance when that fails, as it does on a
+// chain without the canonical Multicall3 deployment.
+func fetchChunkBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, tokens []common.Address, addresses []common.Address, block *big.Int) ([]AddressBalance, error) {
+	bals, err := fetchMulticallBalances(ctx, rpc, chainKey, tokens, addresses, block)
+	if err == nil || ctx.Err() != nil {
+		return bals, err
+	}
+	log.Printf("Multicall on %s failed, reading %d balances directly: %v", chainKey, len(addresses)*(1+len(tokens)), err)
+	return fetchDirectBalances(ctx, rpc, chainKey, tokens, addresses, block)
+}
+
+// fetchDirectBalances reads each balance with its own eth_getBalance or
+// balanceOf call. As in the multicall, a failed balanceOf counts as zero;
+// a failed eth_getBalance fails the read.
+func fetchDirectBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, tokens []common.Address, addresses []common.Address, block *big.Int) ([]AddressBalance, error) {
+	var bals []AddressBalance
+	for _, addr := range addresses {
+		native, err := rpc.BalanceAt(ctx, addr, block)
+		if err != nil {
+			return nil, fmt.Errorf("getting native balance of %s: %w", addr.Hex(), err)
+		}
+
+		tokenBals := make([]string, len(tokens))
+		for j, token := range tokens {
+			bal, err := tokenBalanceAt(ctx, rpc, token, addr, block)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				bal = big.NewInt(0)
+			}
+			tokenBals[j] = bal.String()
+		}
+
+		bals = append(bals, AddressBalance{
+			Address:       addr.Hex(),
+			Chain:         chainKey,
+			NativeBalance: native.String(),
+			TokenBalances: tokenBals,
+		})
+	}
+	return bals, nil
+}
+
+// fetchMulticallBalances reads addresses' balances in a single aggregate3 call.
+func fetchMulticallBalances(ctx context.Context, rpc *ethclient.Client, chainKey string, tokens []common.Address, addresses []common.Address, block *big.Int) ([]AddressBalance, error) {
 	multicallABI, err := contracts.ContractsMetaData.GetAbi()
 	if err != nil {
 		return nil, fmt.Errorf("parsing multicall ABI: %w", err)
@@ -104,11 +391,13 @@ func fetchChainBalances(ctx context.Context, rpc *ethclient.Client, chainKey str
 		if err != nil {
 			return nil, fmt.Errorf("packing balanceOf: %w", err)
 		}
-		calls = append(calls, contracts.Multicall3Call3{
-			Target:       usdcAddr,
-			AllowFailure: true,
-			CallData:     balOfData,
-		})
+		for _, token := range tokens {
+			calls = append(calls, contracts.Multicall3Call3{
+				Target:       token,
+				AllowFailure: true,
+				CallData:     balOfData,
+			})
+		}
 	}
 
 	callData, err := multicallABI.Pack("aggregate3", calls)
@@ -119,7 +408,7 @@ func fetchChainBalances(ctx context.Context, rpc *ethclient.Client, chainKey str
 	output, err := rpc.CallContract(ctx, ethereum.CallMsg{
 		To:   &multicallAddr,
 		Data: callData,
-	}, nil)
+	}, block)
 	if err != nil {
 		return nil, fmt.Errorf("calling aggregate3: %w", err)
 	}
@@ -137,26 +426,33 @@ func fetchChainBalances(ctx context.Context, rpc *ethclient.Client, chainKey str
 		return nil, fmt.Errorf("unexpected aggregate3 return type")
 	}
 
+	// Each address has one native call followed by one call per token
+	stride := 1 + len(tokens)
+
 	var bals []AddressBalance
 	for i, addr := range addresses {
 		native := big.NewInt(0)
-		usdc := big.NewInt(0)
-
-		ethIdx := i * 2
-		usdcIdx := i*2 + 1
 
+		ethIdx := i * stride
 		if ethIdx < len(rawResults) && rawResults[ethIdx].Success && len(rawResults[ethIdx].ReturnData) >= 32 {
 			native.SetBytes(rawResults[ethIdx].ReturnData)
 		}
-		if usdcIdx < len(rawResults) && rawResults[usdcIdx].Success && len(rawResults[usdcIdx].ReturnData) >= 32 {
-			usdc.SetBytes(rawResults[usdcIdx].ReturnData)
+
+		tokenBals := make([]string, len(tokens))
+		for j := range tokens {
+			bal := big.NewInt(0)
+			idx := ethIdx + 1 + j
+			if idx < len(rawResults) && rawResults[idx].Success && len(rawResults[idx].ReturnData) >= 32 {
+				bal.SetBytes(rawResults[idx].ReturnData)
+			}
+			tokenBals[j] = bal.String()
 		}
 
 		bals = append(bals, AddressBalance{
 			Address:       addr.Hex(),
 			Chain:         chainKey,
 			NativeBalance: native.String(),
-			USDCBalance:   usdc.String(),
+			TokenBalances: tokenBals,
 		})
 	}
 
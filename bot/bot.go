@@ -1,12 +1,17 @@
 package bot
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,24 +19,35 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/RaghavSood/fundbot/approvals"
 	"github.com/RaghavSood/fundbot/balances"
 	"github.com/RaghavSood/fundbot/config"
 	"github.com/RaghavSood/fundbot/cowswap"
 	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/gastreasury"
 	"github.com/RaghavSood/fundbot/resolver"
 	"github.com/RaghavSood/fundbot/swaps"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/topups"
 	"github.com/RaghavSood/fundbot/version"
 	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
 )
 
+// resolutionTTL is how long a token confirmed in a chat is used there
+// without resolving and confirming it again.
+const resolutionTTL = 24 * time.Hour
+
 // pendingResolution stores context for a token confirmation callback.
 type pendingResolution struct {
+	Typed       string // the asset as the user gave it, for remembering the resolution
 	Asset       swaps.Asset
 	Resolution  *resolver.Resolution
+	Candidates  []resolver.Candidate // offered to pick from, before Resolution is known
 	Command     string // "quote" or "topup"
 	Destination string
 	USDAmount   float64
@@ -49,13 +65,34 @@ type Bot struct {
 	swapMgr    *swaps.Manager
 	rpcClients map[string]*ethclient.Client
 	cowClient  *cowswap.Client
+	treasury   *gastreasury.Treasury
+	approvals  *approvals.Service
 	resolver   *resolver.Resolver
+	keyring    wallet.Keyring
+	hooks      *webhooks.Client
+	events     *events.Bus
+	topups     *topups.Service
 
 	pendingMu          sync.Mutex
 	pendingResolutions map[string]*pendingResolution
+
+	// Tokens confirmed in each chat, with the resolver's hints applied
+	resolvedMu sync.Mutex
+	resolved   map[resolvedKey]resolvedAsset
+}
+
+// resolvedKey is an asset as typed in a chat, upper cased.
+type resolvedKey struct {
+	chatID int64
+	asset  string
+}
+
+type resolvedAsset struct {
+	asset       swaps.Asset
+	confirmedAt time.Time
 }
 
-func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, rpcClients map[string]*ethclient.Client, cowClient *cowswap.Client, res *resolver.Resolver) (*Bot, error) {
+func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, rpcClients map[string]*ethclient.Client, cowClient *cowswap.Client, treasury *gastreasury.Treasury, approvals *approvals.Service, res *resolver.Resolver, keyring wallet.Keyring, hooks *webhooks.Client, bus *events.Bus, svc *topups.Service) (*Bot, error) {
 	api, err := tgbotapi.NewBotAPI(cfg.TelegramToken)
 	if err != nil {
 		return nil, fmt.Errorf("creating bot API: %w", err)
@@ -69,8 +106,15 @@ func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, rpcClients
 		swapMgr:            swapMgr,
 		rpcClients:         rpcClients,
 		cowClient:          cowClient,
+		treasury:           treasury,
+		approvals:          approvals,
 		resolver:           res,
+		keyring:            keyring,
+		hooks:              hooks,
+		events:             bus,
+		topups:             svc,
 		pendingResolutions: make(map[string]*pendingResolution),
+		resolved:           make(map[resolvedKey]resolvedAsset),
 	}, nil
 }
 
@@ -137,6 +181,12 @@ func (b *Bot) handleMessage(msg *tgbotapi.Message) {
 		b.handleStatus(msg)
 	case "balance", "balances":
 		b.handleBalance(msg)
+	case "limitorder":
+		b.handleLimitOrder(msg)
+	case "cancelorder":
+		b.handleCancelOrder(msg)
+	case "announcements":
+		b.handleAnnouncements(msg)
 	case "help":
 		b.handleStart(msg)
 	case "version":
@@ -147,16 +197,14 @@ func (b *Bot) handleMessage(msg *tgbotapi.Message) {
 	}
 }
 
-// Minimum native balance thresholds (~$1 worth of gas token).
-// Conservative estimates to avoid unnecessary refills.
-var minNativeWei = map[string]*big.Int{
-	"base":      new(big.Int).Mul(big.NewInt(4), big.NewInt(1e14)), // 0.0004 ETH (~$1 at $2500)
-	"avalanche": new(big.Int).Mul(big.NewInt(4), big.NewInt(1e16)), // 0.04 AVAX (~$1 at $25)
+// signingContext lets hardware signers tell the chat when a signature is
+// waiting for confirmation on the device.
+func (b *Bot) signingContext(msg *tgbotapi.Message) context.Context {
+	return wallet.WithConfirmNotifier(context.Background(), func(what string) {
+		b.reply(msg, fmt.Sprintf("Confirm the %s on the hardware wallet...", what))
+	})
 }
 
-// refillUSDC is $5 USDC in smallest units (6 decimals).
-var refillUSDC = big.NewInt(5_000_000)
-
 func (b *Bot) handleBalance(msg *tgbotapi.Message) {
 	index, err := b.walletIndex(msg)
 	if err != nil {
@@ -164,14 +212,14 @@ func (b *Bot) handleBalance(msg *tgbotapi.Message) {
 		return
 	}
 
-	addr, err := wallet.DeriveAddress(b.config.Mnemonic, index)
+	addr, err := b.keyring.Address(index)
 	if err != nil {
 		b.reply(msg, fmt.Sprintf("Error deriving address: %v", err))
 		return
 	}
 
 	ctx := context.Background()
-	bals, err := balances.FetchBalances(ctx, b.rpcClients, []common.Address{addr}, thorchain.USDCContracts)
+	bals, err := balances.FetchBalances(ctx, swaps.EnabledClients(b.rpcClients), []common.Address{addr}, thorchain.FundingTokens.Contracts(), balances.Watchlist(b.config))
 	if err != nil {
 		b.reply(msg, fmt.Sprintf("Error fetching balances: %v", err))
 		return
@@ -181,39 +229,105 @@ func (b *Bot) handleBalance(msg *tgbotapi.Message) {
 		b.reply(msg, "No balances found.")
 		return
 	}
+	balances.Value(ctx, bals, swaps.NativePriceUSD, thorchain.FundingTokens.Decimals())
 
+	refreshed := events.Balances{Address: addr.Hex(), Index: index, Chains: make(map[string]events.ChainBalance)}
 	text := fmt.Sprintf("*Balances for* `%s`\n", addr.Hex())
+	var unpriced []string
 	for _, bal := range bals {
+		refreshed.Add(bal)
 		native := formatWei(bal.NativeBalance, bal.Chain)
-		usdc := formatUSDC(bal.USDCBalance)
-		text += fmt.Sprintf("\n*%s*\n  %s\n  %s USDC", chainLabel(bal.Chain), native, usdc)
+		if bal.NativePriced {
+			native += fmt.Sprintf(" ($%.2f)", bal.NativeUSD)
+		} else if !slices.Contains(unpriced, nativeSymbol(bal.Chain)) {
+			unpriced = append(unpriced, nativeSymbol(bal.Chain))
+		}
+		text += fmt.Sprintf("\n*%s* ($%.2f)\n  %s", chainLabel(bal.Chain), bal.USD(), native)
+		for i, token := range thorchain.FundingTokens.ERC20s(bal.Chain) {
+			stable, _ := new(big.Int).SetString(bal.TokenBalances[i], 10)
+			text += fmt.Sprintf("\n  %s %s", token.Format(stable), token.Symbol)
+		}
+		for _, w := range bal.Watched {
+			if w.Balance == "0" {
+				continue
+			}
+			text += fmt.Sprintf("\n  %s %s", formatToken(w.Balance, w.Decimals), w.Symbol)
+			if w.Priced {
+				text += fmt.Sprintf(" ($%.2f)", w.USD)
+			}
+		}
+	}
+	text += fmt.Sprintf("\n\n*Total:* $%.2f", refreshed.USD)
+	if len(unpriced) > 0 {
+		text += fmt.Sprintf(" (without %s, which couldn't be priced)", strings.Join(unpriced, ", "))
 	}
 	b.reply(msg, text)
+	b.events.BalanceRefresh(refreshed)
 
-	// Check if any chain needs a gas refill (USDC → native token via CoWSwap)
-	if b.cowClient == nil {
+	// Check if any chain needs a gas refill (USDC → native token via CoWSwap);
+	// watch-only wallets can't sign the order
+	if b.cowClient == nil || wallet.WatchOnly(b.keyring) {
 		return
 	}
 
-	privateKey, err := wallet.DeriveKey(b.config.Mnemonic, index)
+	signer, err := b.keyring.Signer(index)
 	if err != nil {
-		log.Printf("Error deriving key for gas refill: %v", err)
+		log.Printf("Error loading signer for gas refill: %v", err)
 		return
 	}
+	if _, ok := signer.(swaps.CallSender); ok {
+		return // smart accounts pay gas through the paymaster
+	}
 
 	for _, bal := range bals {
-		threshold, ok := minNativeWei[bal.Chain]
-		if !ok {
+		refill, ok := b.config.GasRefill(bal.Chain)
+		if _, cow := cowswap.SupportedChains[bal.Chain]; !ok || !cow {
 			continue
 		}
-
+		threshold := refill.MinNative()
 		nativeBal := new(big.Int)
 		nativeBal.SetString(bal.NativeBalance, 10)
+		if nativeBal.Cmp(threshold) >= 0 {
+			continue
+		}
 
-		usdcBal := new(big.Int)
-		usdcBal.SetString(bal.USDCBalance, 10)
+		// Refills sell USDC, so only chains funded with USDC can refill
+		usdcBal, usdc, ok := usdcBalance(bal)
+		if !ok {
+			continue
+		}
 
-		result, err := b.cowClient.RefillGasIfNeeded(ctx, bal.Chain, addr, privateKey, nativeBal, usdcBal, threshold, refillUSDC)
+		minInterval := time.Duration(refill.MinIntervalMinutes) * time.Minute
+		if err := b.db.CheckGasRefillLimits(ctx, bal.Chain, addr.Hex(), minInterval, refill.MaxPerDay); err != nil {
+			if errors.Is(err, db.ErrGasRefillLimit) {
+				b.reply(msg, fmt.Sprintf("Low %s balance on %s, but %v.", nativeSymbol(bal.Chain), chainLabel(bal.Chain), err))
+			} else {
+				log.Printf("Error checking gas refill limits on %s: %v", bal.Chain, err)
+			}
+			continue
+		}
+
+		amount := usdc.Amount(refill.RefillUSD)
+		if b.approvals.Required(refill.RefillUSD, msg.From.ID) && cowswap.CanRefill(nativeBal, usdcBal, refill.TargetNative(), amount) {
+			a, queued, err := b.approvals.RequestGasRefill(ctx, bal.Chain, index, msg.From.ID, msg.Chat.ID, refill.RefillUSD)
+			if err != nil {
+				log.Printf("Error queueing gas refill approval on %s: %v", bal.Chain, err)
+				continue
+			}
+			if queued {
+				b.reply(msg, fmt.Sprintf("Low %s balance on %s. The $%.2f refill is above the approval threshold and is waiting for the admin (approval #%d).",
+					nativeSymbol(bal.Chain), chainLabel(bal.Chain), refill.RefillUSD, a.ID))
+			}
+			continue
+		}
+		guards := cowswap.Guards{SlippageBps: refill.SlippageBps, MaxFeeBps: refill.MaxFeeBps, MaxDeviationBps: refill.MaxDeviationBps}
+		result, err := b.cowClient.RefillGasIfNeeded(b.signingContext(msg), bal.Chain, signer, nativeBal, usdcBal, threshold, refill.TargetNative(), amount, guards)
+		if err != nil || result != nil {
+			// Holds off the gas monitor on this wallet and chain
+			if err := b.db.SetGasRefillCooldown(ctx, db.SetGasRefillCooldownParams{Chain: bal.Chain, WalletAddress: addr.Hex()}); err != nil {
+				log.Printf("Error recording gas refill cooldown on %s: %v", bal.Chain, err)
+			}
+		}
 		if err != nil {
 			log.Printf("Gas refill error on %s: %v", bal.Chain, err)
 			b.reply(msg, fmt.Sprintf("Gas refill error on %s: %v", chainLabel(bal.Chain), err))
@@ -221,7 +335,7 @@ func (b *Bot) handleBalance(msg *tgbotapi.Message) {
 		}
 		if result != nil {
 			// Store gas refill for tracking
-			_, err := b.db.InsertGasRefill(ctx, db.InsertGasRefillParams{
+			refillID, err := b.db.InsertGasRefill(ctx, db.InsertGasRefillParams{
 				Chain:         result.Chain,
 				OrderUid:      result.OrderUID,
 				WalletAddress: addr.Hex(),
@@ -230,17 +344,326 @@ func (b *Bot) handleBalance(msg *tgbotapi.Message) {
 				Status:        "open",
 				UserID:        msg.From.ID,
 				ChatID:        msg.Chat.ID,
+				WalletIndex:   int64(index),
+				Attempt:       1,
+				OrderType:     "market",
+				Kind:          result.Kind,
+				ValidTo:       sql.NullTime{Time: result.ValidTo, Valid: true},
 			})
 			if err != nil {
 				log.Printf("Error storing gas refill record: %v", err)
+			} else {
+				b.hooks.GasRefillStatus(webhooks.GasRefill{
+					ID:            refillID,
+					Status:        "open",
+					Chain:         result.Chain,
+					OrderUID:      result.OrderUID,
+					WalletAddress: addr.Hex(),
+					SellAmount:    result.SellAmount,
+					BuyAmount:     result.BuyAmount,
+					Attempt:       1,
+					OrderType:     "market",
+					Kind:          result.Kind,
+					UserID:        msg.From.ID,
+					ChatID:        msg.Chat.ID,
+				})
 			}
 
-			b.reply(msg, fmt.Sprintf("Low %s balance detected. Swapping $5 USDC → %s via CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
-				nativeSymbol(bal.Chain), nativeSymbol(bal.Chain), result.OrderUID))
+			if result.Kind == "buy" {
+				sold, _ := new(big.Int).SetString(result.SellAmount, 10)
+				b.reply(msg, fmt.Sprintf("Low %s balance detected. Buying %s for at most %s USDC via CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
+					nativeSymbol(bal.Chain), formatWei(result.BuyAmount, bal.Chain), usdc.Format(sold), result.OrderUID))
+				continue
+			}
+			b.reply(msg, fmt.Sprintf("Low %s balance detected. Swapping %s USDC → %s via CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
+				nativeSymbol(bal.Chain), usdc.Format(amount), nativeSymbol(bal.Chain), result.OrderUID))
+		} else if b.treasury != nil {
+			// Too little USDC to refill, so the gas treasury sends some
+			transfer, err := b.treasury.Fund(b.signingContext(msg), bal.Chain, index, addr, msg.From.ID, msg.Chat.ID)
+			if err != nil {
+				log.Printf("Gas treasury top-up error on %s: %v", bal.Chain, err)
+				b.reply(msg, fmt.Sprintf("Low %s balance on %s and not enough USDC for a refill; the gas treasury top-up failed.",
+					nativeSymbol(bal.Chain), chainLabel(bal.Chain)))
+				continue
+			}
+			if transfer != nil {
+				b.reply(msg, fmt.Sprintf("Low %s balance and not enough USDC for a refill. Sent %s from the gas treasury.\n[View on Explorer](%s)",
+					nativeSymbol(bal.Chain), formatWei(transfer.Amount.String(), bal.Chain), b.config.ExplorerTxURL(bal.Chain, transfer.TxHash)))
+			}
 		}
 	}
 }
 
+// usdcBalance returns the USDC balance from a fetched AddressBalance, if USDC
+// is one of the chain's funding tokens.
+func usdcBalance(bal balances.AddressBalance) (*big.Int, swaps.FundingToken, bool) {
+	for i, token := range thorchain.FundingTokens.ERC20s(bal.Chain) {
+		if token.Symbol != "USDC" {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(bal.TokenBalances[i], 10)
+		return amount, token, ok
+	}
+	return nil, swaps.FundingToken{}, false
+}
+
+const (
+	// Limit orders stay open this many days unless the user says otherwise,
+	// and at most maxLimitOrderDays
+	defaultLimitOrderDays = 7
+	maxLimitOrderDays     = 30
+
+	// maxOpenLimitOrders caps the open limit orders of one wallet
+	maxOpenLimitOrders = 5
+)
+
+// handleLimitOrder places a CoW limit order selling USDC for the chain's
+// native token at the user's price, open for days rather than minutes:
+// /limitorder <chain> <usdc> <native amount> [days] [partial]. With
+// partial the order can fill in parts. The tracker follows it like a gas
+// refill but never resubmits it.
+func (b *Bot) handleLimitOrder(msg *tgbotapi.Message) {
+	args := strings.Fields(msg.CommandArguments())
+	partial := len(args) > 0 && strings.EqualFold(args[len(args)-1], "partial")
+	if partial {
+		args = args[:len(args)-1]
+	}
+	if len(args) < 3 || len(args) > 4 {
+		b.reply(msg, fmt.Sprintf("Usage: /limitorder `<chain> <usdc> <native amount> [days] [partial]`\nExample: /limitorder base 20 0.01 sells 20 USDC for at least 0.01 ETH, open for %d days. Add `partial` to let it fill in parts.", defaultLimitOrderDays))
+		return
+	}
+	if b.cowClient == nil || wallet.WatchOnly(b.keyring) {
+		b.reply(msg, "Limit orders need CoWSwap and a wallet that can sign.")
+		return
+	}
+
+	chain := strings.ToLower(args[0])
+	if _, ok := cowswap.SupportedChains[chain]; !ok || !swaps.ChainEnabled(chain) || b.rpcClients[chain] == nil {
+		b.reply(msg, fmt.Sprintf("CoWSwap limit orders aren't available on %s.", chain))
+		return
+	}
+	usdAmount, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || usdAmount <= 0 {
+		b.reply(msg, fmt.Sprintf("Invalid USDC amount %q.", args[1]))
+		return
+	}
+	buyAmount, err := parseWei(args[2])
+	if err != nil || buyAmount.Sign() <= 0 {
+		b.reply(msg, fmt.Sprintf("Invalid %s amount %q.", nativeSymbol(chain), args[2]))
+		return
+	}
+	days := defaultLimitOrderDays
+	if len(args) == 4 {
+		days, err = strconv.Atoi(args[3])
+		if err != nil || days < 1 || days > maxLimitOrderDays {
+			b.reply(msg, fmt.Sprintf("Days must be between 1 and %d.", maxLimitOrderDays))
+			return
+		}
+	}
+
+	index, err := b.walletIndex(msg)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
+		return
+	}
+	signer, err := b.keyring.Signer(index)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error loading signer: %v", err))
+		return
+	}
+	addr := signer.Address()
+
+	ctx := context.Background()
+	open, err := b.db.ListOpenGasRefillsByWallet(ctx, addr.Hex())
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error listing open orders: %v", err))
+		return
+	}
+	limits := 0
+	for _, o := range open {
+		if o.OrderType == "limit" {
+			limits++
+		}
+	}
+	if limits >= maxOpenLimitOrders {
+		b.reply(msg, fmt.Sprintf("This wallet already has %d open limit orders. Cancel one with /cancelorder first.", limits))
+		return
+	}
+
+	bals, err := balances.FetchBalances(ctx, map[string]*ethclient.Client{chain: b.rpcClients[chain]}, []common.Address{addr}, thorchain.FundingTokens.Contracts(), nil)
+	if err != nil || len(bals) == 0 {
+		b.reply(msg, fmt.Sprintf("Error fetching balances on %s: %v", chainLabel(chain), err))
+		return
+	}
+	usdcBal, usdc, ok := usdcBalance(bals[0])
+	if !ok {
+		b.reply(msg, fmt.Sprintf("%s has no USDC to sell.", chainLabel(chain)))
+		return
+	}
+	sellAmount := usdc.Amount(usdAmount)
+	if usdcBal.Cmp(sellAmount) < 0 {
+		b.reply(msg, fmt.Sprintf("The wallet has %s USDC on %s.", usdc.Format(usdcBal), chainLabel(chain)))
+		return
+	}
+
+	validFor := time.Duration(days) * 24 * time.Hour
+	result, err := b.cowClient.SellUSDCLimit(b.signingContext(msg), chain, signer, sellAmount, cowswap.NativeToken, buyAmount, addr, validFor, partial)
+	if err != nil {
+		log.Printf("Limit order error on %s: %v", chain, err)
+		b.reply(msg, fmt.Sprintf("Limit order error on %s: %v", chainLabel(chain), err))
+		return
+	}
+
+	params := db.InsertGasRefillParams{
+		Chain:         result.Chain,
+		OrderUid:      result.OrderUID,
+		WalletAddress: addr.Hex(),
+		SellAmount:    result.SellAmount,
+		BuyAmount:     result.BuyAmount,
+		Status:        "open",
+		UserID:        msg.From.ID,
+		ChatID:        msg.Chat.ID,
+		WalletIndex:   int64(index),
+		Attempt:       1,
+		OrderType:     "limit",
+		Kind:          result.Kind,
+
+		PartiallyFillable: result.PartiallyFillable,
+		ValidTo:       sql.NullTime{Time: result.ValidTo, Valid: true},
+	}
+	id, err := b.db.InsertGasRefill(ctx, params)
+	if err != nil {
+		// The order is placed; say so rather than invite a second one
+		log.Printf("Error storing limit order %s: %v", result.OrderUID, err)
+		b.reply(msg, fmt.Sprintf("Limit order placed but not recorded, so it won't be tracked: %v\n[View Order](https://explorer.cow.fi/orders/%s)", err, result.OrderUID))
+		return
+	}
+	b.hooks.GasRefillStatus(webhooks.GasRefill{
+		ID:            id,
+		Status:        params.Status,
+		Chain:         params.Chain,
+		OrderUID:      params.OrderUid,
+		WalletAddress: params.WalletAddress,
+		SellAmount:    params.SellAmount,
+		BuyAmount:     params.BuyAmount,
+		Attempt:       params.Attempt,
+		OrderType:     params.OrderType,
+		Kind:          params.Kind,
+		UserID:        params.UserID,
+		ChatID:        params.ChatID,
+	})
+
+	fills := ""
+	if result.PartiallyFillable {
+		fills = " It can fill in parts."
+	}
+	b.reply(msg, fmt.Sprintf("*Limit order #%d* on %s\nSelling %s USDC for at least %s, open until %s UTC.%s\nCancel it with /cancelorder %d.\n[View Order](https://explorer.cow.fi/orders/%s)",
+		id, chainLabel(chain), usdc.Format(sellAmount), formatWei(result.BuyAmount, chain),
+		result.ValidTo.UTC().Format("2006-01-02 15:04"), fills, id, result.OrderUID))
+}
+
+// handleCancelOrder lists the open CoW orders of the chat's wallet, gas
+// refills and limit orders alike, or cancels one: /cancelorder [id]. A
+// cancelled refill isn't resubmitted.
+func (b *Bot) handleCancelOrder(msg *tgbotapi.Message) {
+	if b.cowClient == nil {
+		b.reply(msg, "CoWSwap is not enabled.")
+		return
+	}
+	index, err := b.walletIndex(msg)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
+		return
+	}
+	addr, err := b.keyring.Address(index)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error deriving address: %v", err))
+		return
+	}
+
+	ctx := context.Background()
+	open, err := b.db.ListOpenGasRefillsByWallet(ctx, addr.Hex())
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error listing open orders: %v", err))
+		return
+	}
+
+	arg := strings.TrimPrefix(strings.TrimSpace(msg.CommandArguments()), "#")
+	if arg == "" {
+		if len(open) == 0 {
+			b.reply(msg, "No open orders.")
+			return
+		}
+		text := "*Open orders*\n"
+		for _, o := range open {
+			kind := "gas refill"
+			if o.OrderType == "limit" {
+				kind = "limit order"
+			}
+			text += fmt.Sprintf("\n#%d %s on %s: %s for at least %s", o.ID, kind, chainLabel(o.Chain), formatUSDC(o.SellAmount, o.Chain), formatWei(o.BuyAmount, o.Chain))
+			if o.ValidTo.Valid {
+				text += fmt.Sprintf(", until %s UTC", o.ValidTo.Time.UTC().Format("2006-01-02 15:04"))
+			}
+			if filled := cowswap.FilledPercent(o.Kind, o.SellAmount, o.BuyAmount, o.ExecutedSellAmount, o.ExecutedBuyAmount); filled > 0 {
+				text += fmt.Sprintf(", %d%% filled", filled)
+			}
+		}
+		text += "\n\nCancel one with /cancelorder `<id>`."
+		b.reply(msg, text)
+		return
+	}
+
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		b.reply(msg, "Usage: /cancelorder `[id]`")
+		return
+	}
+	i := slices.IndexFunc(open, func(o db.GasRefill) bool { return o.ID == id })
+	if i < 0 {
+		b.reply(msg, fmt.Sprintf("No open order #%d for this wallet.", id))
+		return
+	}
+	order := open[i]
+
+	if wallet.WatchOnly(b.keyring) {
+		b.reply(msg, "A watch-only wallet can't sign the cancellation.")
+		return
+	}
+	signer, err := b.keyring.Signer(index)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error loading signer: %v", err))
+		return
+	}
+	if err := b.cowClient.CancelOrder(b.signingContext(msg), order.Chain, signer, order.OrderUid); err != nil {
+		b.reply(msg, fmt.Sprintf("Error cancelling order #%d: %v", id, err))
+		return
+	}
+	// The tracker may have seen the cancellation first; the note still
+	// keeps a refill from being resubmitted
+	note := "Cancelled by user"
+	if _, err := b.db.CancelGasRefill(ctx, db.CancelGasRefillParams{ResolutionNote: note, ID: order.ID}); err != nil {
+		log.Printf("Error recording cancel of gas refill %d: %v", order.ID, err)
+	}
+	log.Printf("User %d cancelled gas refill %d (order %s)", msg.From.ID, order.ID, order.OrderUid)
+
+	b.hooks.GasRefillStatus(webhooks.GasRefill{
+		ID:            order.ID,
+		Status:        "cancelled",
+		Chain:         order.Chain,
+		OrderUID:      order.OrderUid,
+		WalletAddress: order.WalletAddress,
+		SellAmount:    order.SellAmount,
+		BuyAmount:     order.BuyAmount,
+		Attempt:       order.Attempt,
+		OrderType:     order.OrderType,
+		Kind:          order.Kind,
+		UserID:        order.UserID,
+		ChatID:        order.ChatID,
+	})
+	b.reply(msg, fmt.Sprintf("Order #%d on %s cancelled. A solver already settling it can still fill it.\n[View Order](https://explorer.cow.fi/orders/%s)",
+		id, chainLabel(order.Chain), order.OrderUid))
+}
+
 func formatWei(wei string, chain string) string {
 	val := new(big.Int)
 	val.SetString(wei, 10)
@@ -250,21 +673,71 @@ func formatWei(wei string, chain string) string {
 	return fmt.Sprintf("%s.%s %s", whole, fracStr, nativeSymbol(chain))
 }
 
-func formatUSDC(raw string) string {
-	val := new(big.Int)
-	val.SetString(raw, 10)
-	whole := new(big.Int).Div(val, big.NewInt(1e6))
-	frac := new(big.Int).Mod(val, big.NewInt(1e6))
-	fracStr := fmt.Sprintf("%06s", frac.String())[:2]
-	return fmt.Sprintf("%s.%s", whole, fracStr)
+// formatToken formats an amount in a token's smallest units to at most six
+// decimal places.
+func formatToken(raw string, decimals int) string {
+	val, ok := new(big.Int).SetString(raw, 10)
+	if !ok || decimals <= 0 {
+		return raw
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole := new(big.Int).Div(val, unit)
+	frac := fmt.Sprintf("%0*s", decimals, new(big.Int).Mod(val, unit).String())
+	return fmt.Sprintf("%s.%s", whole, frac[:min(decimals, 6)])
+}
+
+// parseWei parses a decimal amount of a native token with 18 decimals.
+func parseWei(s string) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 18 {
+		return nil, fmt.Errorf("more than 18 decimals")
+	}
+	digits := whole + frac + strings.Repeat("0", 18-len(frac))
+	if strings.Trim(digits, "0123456789") != "" || whole+frac == "" {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	wei, _ := new(big.Int).SetString(digits, 10)
+	return wei, nil
+}
+
+// formatUSDC formats an amount of a chain's USDC in its smallest units.
+func formatUSDC(amount string, chain string) string {
+	raw, _ := new(big.Int).SetString(amount, 10)
+	for _, token := range thorchain.FundingTokens.ERC20s(chain) {
+		if token.Symbol == "USDC" && raw != nil {
+			return token.Format(raw) + " USDC"
+		}
+	}
+	return amount + " USDC units"
+}
+
+// formatMarketCap renders a CoinGecko market cap, e.g. "$3.2B".
+func formatMarketCap(usd float64) string {
+	switch {
+	case usd <= 0:
+		return "unknown"
+	case usd >= 1e9:
+		return fmt.Sprintf("$%.1fB", usd/1e9)
+	case usd >= 1e6:
+		return fmt.Sprintf("$%.1fM", usd/1e6)
+	case usd >= 1e3:
+		return fmt.Sprintf("$%.1fK", usd/1e3)
+	}
+	return fmt.Sprintf("$%.0f", usd)
 }
 
 func nativeSymbol(chain string) string {
 	switch chain {
 	case "avalanche":
 		return "AVAX"
-	case "base":
+	case "base", "arbitrum", "ethereum", "optimism":
 		return "ETH"
+	case "polygon":
+		return "POL"
+	case "bsc":
+		return "BNB"
+	case "gnosis":
+		return "XDAI"
 	default:
 		return strings.ToUpper(chain)
 	}
@@ -276,6 +749,18 @@ func chainLabel(chain string) string {
 		return "Avalanche"
 	case "base":
 		return "Base"
+	case "arbitrum":
+		return "Arbitrum"
+	case "ethereum":
+		return "Ethereum"
+	case "optimism":
+		return "Optimism"
+	case "polygon":
+		return "Polygon"
+	case "bsc":
+		return "BSC"
+	case "gnosis":
+		return "Gnosis"
 	default:
 		return strings.Title(chain)
 	}
@@ -288,15 +773,29 @@ func (b *Bot) handleStart(msg *tgbotapi.Message) {
 		"/balance - Show wallet balances\n" +
 		"/quote `<addr> <amount> <CHAIN.ASSET> [routing]`\n" +
 		"/topup `<addr> <amount> <CHAIN.ASSET> [routing]`\n" +
-		"/status `<topup_id>` - Check topup status\n\n" +
+		"/status `<topup_id>` - Check topup status\n" +
+		"/limitorder `<chain> <usdc> <native> [days] [partial]` - Sell USDC for gas at your price via CoWSwap\n" +
+		"/cancelorder `[id]` - List or cancel open CoWSwap orders\n" +
+		"/announcements `on|off` - Admin announcements in this chat\n\n" +
 		"*Asset examples:*\n" +
-		"`BTC.BTC`, `ETH.ETH`, `SOL.SOL`, `DOGE.DOGE`\n\n" +
+		"`BTC.BTC`, `ETH.ETH`, `SOL.SOL`, `DOGE.DOGE`\n" +
+		"Or paste a token contract: `BASE.0x...`\n\n" +
 		"*Routing hints* (optional):\n" +
 		"`thorchain` - DEX, non-custodial\n" +
+		"`stream` - Thorchain streaming swap (better price on large amounts, slower); `stream:<interval>/<quantity>` sets blocks between and number of sub-swaps\n" +
+		"`thorswap` - DEX, Thorchain + DEX aggregator (ERC20 targets)\n" +
 		"`simpleswap` - Private, custodial\n" +
 		"`near` - DEX, intent-based (Near Intents)\n" +
 		"`houdini` - Private, CEX-routed\n" +
 		"`hanon` - Private, anonymous routing\n" +
+		"`stealthex` - Private, custodial (StealthEX)\n" +
+		"`relay` - DEX, fast EVM bridging (Relay)\n" +
+		"`across` - DEX, USDC bridging to EVM L2s (Across)\n" +
+		"`squid` - DEX, Cosmos via Axelar (Squid)\n" +
+		"`rango` - DEX, bridge/DEX aggregator (Rango)\n" +
+		"`0x` - DEX, same-chain swaps on Base/Avalanche\n" +
+		"`uniswap` - DEX, on-chain Uniswap v3 swaps on Base\n" +
+		"`garden` - DEX, atomic swap to BTC (Garden)\n" +
 		"`dex` - Any DEX provider\n" +
 		"`private` - Any private/custodial provider\n" +
 		"Omit for best price across all providers."
@@ -310,7 +809,7 @@ func (b *Bot) handleAddress(msg *tgbotapi.Message) {
 		return
 	}
 
-	addr, err := wallet.DeriveAddress(b.config.Mnemonic, index)
+	addr, err := b.keyring.Address(index)
 	if err != nil {
 		b.reply(msg, fmt.Sprintf("Error deriving address: %v", err))
 		return
@@ -319,23 +818,12 @@ func (b *Bot) handleAddress(msg *tgbotapi.Message) {
 	b.reply(msg, fmt.Sprintf("Your wallet address: `%s`", addr.Hex()))
 }
 
-// validHints maps accepted routing hint strings to their type and normalized value.
-var validHints = map[string]swaps.RoutingHint{
-	"thorchain":  {Type: "provider", Value: "thorchain"},
-	"simpleswap": {Type: "provider", Value: "simpleswap"},
-	"near":       {Type: "provider", Value: "nearintents"},
-	"houdini":    {Type: "provider", Value: "houdini"},
-	"hanon":      {Type: "provider", Value: "houdini-anon"},
-	"dex":        {Type: "category", Value: "dex"},
-	"private":    {Type: "category", Value: "private"},
-}
-
 // parseSwapArgs parses "<address> <amount> <CHAIN.ASSET> [routing_hint]" from command arguments.
 // The routing hint is optional: a provider name (thorchain, simpleswap) or category (dex, private).
 func parseSwapArgs(args string) (destination string, usdAmount float64, asset swaps.Asset, hint swaps.RoutingHint, err error) {
 	fields := strings.Fields(args)
 	if len(fields) < 3 || len(fields) > 4 {
-		err = fmt.Errorf("usage: <address> <amount> <CHAIN.ASSET> [thorchain|simpleswap|near|houdini|hanon|dex|private]")
+		err = fmt.Errorf("usage: <address> <amount> <CHAIN.ASSET> [thorchain|stream[:<interval>/<quantity>]|thorswap|simpleswap|near|houdini|hanon|stealthex|relay|across|squid|rango|0x|uniswap|garden|dex|private]")
 		return
 	}
 
@@ -358,9 +846,9 @@ func parseSwapArgs(args string) (destination string, usdAmount float64, asset sw
 	}
 
 	if len(fields) == 4 {
-		h, ok := validHints[strings.ToLower(fields[3])]
+		h, ok := swaps.ParseRoutingHint(fields[3])
 		if !ok {
-			err = fmt.Errorf("unknown routing hint %q (use thorchain, simpleswap, near, houdini, hanon, dex, or private)", fields[3])
+			err = fmt.Errorf("unknown routing hint %q (use thorchain, stream or stream:<interval>/<quantity>, thorswap, simpleswap, near, houdini, hanon, stealthex, relay, across, squid, rango, 0x, uniswap, garden, dex, or private)", fields[3])
 			return
 		}
 		hint = h
@@ -369,26 +857,6 @@ func parseSwapArgs(args string) (destination string, usdAmount float64, asset sw
 	return
 }
 
-func (b *Bot) insertQuote(ctx context.Context, quote *swaps.Quote, userID int64, chatID int64, destination string) (int64, error) {
-	return b.db.InsertQuote(ctx, db.InsertQuoteParams{
-		Type:           "fast",
-		Provider:       quote.Provider,
-		UserID:         userID,
-		FromAsset:      quote.FromAsset.String(),
-		FromChain:      quote.FromChain,
-		ToAsset:        quote.ToAsset.String(),
-		Destination:    destination,
-		InputAmountUsd: quote.InputAmountUSD,
-		InputAmount:    quote.InputAmount.String(),
-		ExpectedOutput: quote.ExpectedOutput,
-		Memo:           quote.Memo,
-		Router:         quote.Router,
-		VaultAddress:   quote.VaultAddress,
-		Expiry:         quote.Expiry,
-		ChatID:         chatID,
-	})
-}
-
 func (b *Bot) handleQuote(msg *tgbotapi.Message) {
 	destination, usdAmount, asset, hint, err := parseSwapArgs(msg.CommandArguments())
 	if err != nil {
@@ -396,44 +864,46 @@ func (b *Bot) handleQuote(msg *tgbotapi.Message) {
 		return
 	}
 
-	// If asset is not statically known, try dynamic resolution.
-	if !b.swapMgr.IsStaticallyKnown(asset) {
-		b.tryResolve(msg, asset, "quote", destination, usdAmount, hint)
+	// Tokens pinned in the registry skip resolution; disabled ones are refused.
+	asset, pinned, err := b.topups.Registered(context.Background(), asset)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
 		return
 	}
 
+	// If asset is not statically known, use the token confirmed for it in
+	// this chat, or try dynamic resolution.
+	if !pinned && !b.swapMgr.IsStaticallyKnown(asset) {
+		resolved, ok := b.resolvedAsset(msg.Chat.ID, asset)
+		if !ok {
+			b.tryResolve(msg, asset, "quote", destination, usdAmount, hint)
+			return
+		}
+		asset = resolved
+	}
+
 	b.executeQuote(msg, asset, destination, usdAmount, hint)
 }
 
 func (b *Bot) executeQuote(msg *tgbotapi.Message, asset swaps.Asset, destination string, usdAmount float64, hint swaps.RoutingHint) {
-	index, err := b.walletIndex(msg)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Error: %v", err))
-		return
-	}
-	senderAddr, err := wallet.DeriveAddress(b.config.Mnemonic, index)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Error deriving address: %v", err))
-		return
-	}
-
 	b.reply(msg, fmt.Sprintf("Fetching quote for $%.2f → %s to %s...", usdAmount, asset, destination))
 
 	ctx := context.Background()
-	quote, err := b.swapMgr.BestQuote(ctx, asset, usdAmount, destination, senderAddr, hint)
+	quote, quoteID, err := b.topups.Quote(ctx, b.topupRequest(msg, asset, destination, usdAmount, hint))
 	if err != nil {
-		b.reply(msg, fmt.Sprintf("Quote error: %v", err))
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
 		return
 	}
 
-	quoteID, err := b.insertQuote(ctx, quote, msg.From.ID, msg.Chat.ID, destination)
-	if err != nil {
-		log.Printf("Error storing quote: %v", err)
-	}
-
-	text := fmt.Sprintf("*Quote #%d*\nProvider: %s\nSource: %s (%s)\nInput: $%.2f USDC\nExpected output: %s (raw units)\nMemo: `%s`",
+	text := fmt.Sprintf("*Quote #%d*\nProvider: %s\nSource: %s (%s)\nInput: $%.2f %s\nExpected output: %s (raw units)\nMemo: `%s`",
 		quoteID, quote.Provider, quote.FromAsset, quote.FromChain,
-		quote.InputAmountUSD, quote.ExpectedOutput, quote.Memo)
+		quote.InputAmountUSD, quote.FromAsset.Symbol, quote.ExpectedOutput, quote.Memo)
+	if secs, ok := quote.ExtraData["total_swap_s"].(int64); ok && secs > 0 {
+		text += fmt.Sprintf("\nEstimated time: %s", time.Duration(secs)*time.Second)
+	}
+	if warning := b.swapMgr.GasWarning(ctx, quote); warning != "" {
+		text += "\n\n" + warning
+	}
 	b.reply(msg, text)
 }
 
@@ -444,68 +914,84 @@ func (b *Bot) handleTopup(msg *tgbotapi.Message) {
 		return
 	}
 
-	// If asset is not statically known, try dynamic resolution.
-	if !b.swapMgr.IsStaticallyKnown(asset) {
-		b.tryResolve(msg, asset, "topup", destination, usdAmount, hint)
+	// Tokens pinned in the registry skip resolution; disabled ones are refused.
+	asset, pinned, err := b.topups.Registered(context.Background(), asset)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
 		return
 	}
 
+	// If asset is not statically known, use the token confirmed for it in
+	// this chat, or try dynamic resolution.
+	if !pinned && !b.swapMgr.IsStaticallyKnown(asset) {
+		resolved, ok := b.resolvedAsset(msg.Chat.ID, asset)
+		if !ok {
+			b.tryResolve(msg, asset, "topup", destination, usdAmount, hint)
+			return
+		}
+		asset = resolved
+	}
+
 	b.executeTopup(msg, asset, destination, usdAmount, hint)
 }
 
 func (b *Bot) executeTopup(msg *tgbotapi.Message, asset swaps.Asset, destination string, usdAmount float64, hint swaps.RoutingHint) {
-	index, err := b.walletIndex(msg)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Error: %v", err))
-		return
-	}
-	privateKey, err := wallet.DeriveKey(b.config.Mnemonic, index)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Error deriving key: %v", err))
+	req := b.topupRequest(msg, asset, destination, usdAmount, hint)
+	if b.approvals.Required(usdAmount, msg.From.ID) {
+		a, err := b.approvals.RequestTopup(context.Background(), req, msg.MessageID)
+		if err != nil {
+			b.reply(msg, fmt.Sprintf("Error: %v", err))
+			return
+		}
+		b.reply(msg, fmt.Sprintf("$%.2f is above the approval threshold, so this topup is waiting for the admin (approval #%d). You'll be told here once it is decided.", usdAmount, a.ID))
 		return
 	}
-	senderAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
 
 	b.reply(msg, fmt.Sprintf("Executing swap: $%.2f → %s to %s...", usdAmount, asset, destination))
 
-	ctx := context.Background()
-	quote, err := b.swapMgr.BestQuote(ctx, asset, usdAmount, destination, senderAddr, hint)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Quote error: %v", err))
+	ctx := b.signingContext(msg)
+	result, err := b.topups.Execute(ctx, req, func(text string) {
+		b.reply(msg, text)
+	})
+	var unsigned *wallet.UnsignedTxError
+	if errors.As(err, &unsigned) {
+		b.replyUnsignedTx(msg, result.Quote, unsigned)
 		return
 	}
-
-	quoteID, err := b.insertQuote(ctx, quote, msg.From.ID, msg.Chat.ID, destination)
 	if err != nil {
-		b.reply(msg, fmt.Sprintf("Error storing quote: %v", err))
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
 		return
 	}
 
-	result, err := b.swapMgr.ExecuteSwap(ctx, quote, privateKey)
-	if err != nil {
-		b.reply(msg, fmt.Sprintf("Swap execution failed: %v", err))
-		return
+	explorerURL := b.config.ExplorerTxURL(result.Quote.FromChain, result.Swap.TxHash)
+	text := fmt.Sprintf("*Topup %s*\nTx: `%s`\n[Explorer](%s)\nUse /status %s to check progress.",
+		result.Topup.ShortID, result.Swap.TxHash, explorerURL, result.Topup.ShortID)
+	if result.Fresh != nil {
+		text += fmt.Sprintf("\nSent from fresh address `%s`; refunds go there.", result.Fresh.Address)
 	}
+	b.reply(msg, text)
+}
 
-	topupRow, err := b.db.InsertTopupWithShortID(ctx, db.InsertTopupParams{
-		Type:       "fast",
-		QuoteID:    quoteID,
-		UserID:     msg.From.ID,
-		Provider:   quote.Provider,
-		FromChain:  quote.FromChain,
-		TxHash:     result.TxHash,
-		Status:     "pending",
-		ChatID:     msg.Chat.ID,
-		ExternalID: result.ExternalID,
-	})
-	if err != nil {
-		log.Printf("Error storing topup: %v", err)
+// topupRequest describes a quote or topup asked for in msg.
+func (b *Bot) topupRequest(msg *tgbotapi.Message, asset swaps.Asset, destination string, usdAmount float64, hint swaps.RoutingHint) topups.Request {
+	return topups.Request{
+		Owner:       owner(msg),
+		Asset:       asset,
+		Destination: destination,
+		USDAmount:   usdAmount,
+		Hint:        hint,
 	}
+}
 
-	explorerURL := b.config.ExplorerTxURL(quote.FromChain, result.TxHash)
-	text := fmt.Sprintf("*Topup %s*\nTx: `%s`\n[Explorer](%s)\nUse /status %s to check progress.",
-		topupRow.ShortID, result.TxHash, explorerURL, topupRow.ShortID)
-	b.reply(msg, text)
+// owner is whoever msg acts for: the sender in DMs, the group otherwise.
+func owner(msg *tgbotapi.Message) topups.Owner {
+	return topups.Owner{
+		UserID:    msg.From.ID,
+		Username:  msg.From.UserName,
+		ChatID:    msg.Chat.ID,
+		ChatTitle: msg.Chat.Title,
+		Private:   msg.Chat.IsPrivate(),
+	}
 }
 
 func (b *Bot) handleStatus(msg *tgbotapi.Message) {
@@ -525,42 +1011,163 @@ func (b *Bot) handleStatus(msg *tgbotapi.Message) {
 	explorerURL := b.config.ExplorerTxURL(topup.FromChain, topup.TxHash)
 	text := fmt.Sprintf("*Topup %s*\nProvider: %s\nChain: %s\nTx: `%s`\nStatus: %s\n[Explorer](%s)",
 		topup.ShortID, topup.Provider, topup.FromChain, topup.TxHash, topup.Status, explorerURL)
-	b.reply(msg, text)
-}
+	if topup.ActualOutput != "" {
+		text += fmt.Sprintf("\nReceived: %s", topup.ActualOutput)
+	}
+	if topup.DestTxHash != "" {
+		text += fmt.Sprintf("\nPayout: `%s`", topup.DestTxHash)
+	}
+	if topup.RefundTxHash != "" {
+		text += fmt.Sprintf("\nRefund: `%s`\n[Refund Explorer](%s)", topup.RefundTxHash, b.config.ExplorerTxURL(topup.FromChain, topup.RefundTxHash))
+	}
 
-// walletIndex returns the BIP44 derivation index for a message context.
-// Single mode: always 0. Multi mode: address_assignments row ID.
-func (b *Bot) walletIndex(msg *tgbotapi.Message) (uint32, error) {
-	if b.config.Mode == config.ModeSingle {
-		return 0, nil
+	if gas := b.gasCost(ctx, topup.ID, topup.FromChain); gas != "" {
+		text += "\nGas cost: " + gas
 	}
 
-	ctx := context.Background()
+	events, err := b.db.ListTopupStatusEvents(ctx, topup.ID)
+	if err != nil {
+		log.Printf("Error loading status history of topup %s: %v", topup.ShortID, err)
+	}
+	if len(events) > 0 {
+		text += "\n\n*History*"
+		for _, e := range events {
+			text += fmt.Sprintf("\n%s UTC: %s", e.CreatedAt.UTC().Format("2006-01-02 15:04"), e.Status)
+			if e.RawStatus != "" {
+				text += fmt.Sprintf(" (`%s`)", e.RawStatus)
+			}
+		}
+	}
+	b.reply(msg, text)
+}
 
-	var assignedToID int64
-	var assignedToType string
+// handleAnnouncements shows or changes whether the chat gets admin
+// broadcasts. In groups only chat admins can change it.
+func (b *Bot) handleAnnouncements(msg *tgbotapi.Message) {
+	ctx := context.Background()
+	chatID := msg.Chat.ID
+	arg := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	switch arg {
+	case "":
+		n, err := b.db.IsAnnouncementOptOut(ctx, chatID)
+		if err != nil {
+			b.reply(msg, fmt.Sprintf("Error: %v", err))
+			return
+		}
+		state := "on"
+		if n > 0 {
+			state = "off"
+		}
+		b.reply(msg, fmt.Sprintf("Announcements are %s for this chat. Use /announcements on or /announcements off to change it.", state))
+		return
+	case "on", "off":
+	default:
+		b.reply(msg, "Usage: /announcements on|off")
+		return
+	}
 
-	if msg.Chat.IsPrivate() {
-		user, err := b.db.GetOrCreateUser(ctx, msg.From.ID, msg.From.UserName)
+	if !msg.Chat.IsPrivate() {
+		member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+			ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: msg.From.ID},
+		})
 		if err != nil {
-			return 0, err
+			b.reply(msg, fmt.Sprintf("Error checking your chat permissions: %v", err))
+			return
 		}
-		assignedToID = user.ID
-		assignedToType = "user"
+		if !member.IsCreator() && !member.IsAdministrator() {
+			b.reply(msg, "Only group admins can change announcements.")
+			return
+		}
+	}
+
+	var err error
+	if arg == "off" {
+		err = b.db.InsertAnnouncementOptOut(ctx, chatID)
 	} else {
-		chat, err := b.db.GetOrCreateChat(ctx, msg.Chat.ID, msg.Chat.Title)
-		if err != nil {
-			return 0, err
+		err = b.db.DeleteAnnouncementOptOut(ctx, chatID)
+	}
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Error: %v", err))
+		return
+	}
+	b.reply(msg, fmt.Sprintf("Announcements turned %s for this chat.", arg))
+}
+
+// gasCost sums the gas spent by a topup's mined transactions, in the native
+// coin of chain. Returns "" until at least one has been priced.
+func (b *Bot) gasCost(ctx context.Context, topupID int64, chain string) string {
+	txs, err := b.db.ListTopupTransactions(ctx, topupID)
+	if err != nil {
+		log.Printf("Error loading transactions of topup %d: %v", topupID, err)
+		return ""
+	}
+	total := new(big.Int)
+	var priced, pending int
+	for _, tx := range txs {
+		cost, ok := new(big.Int).SetString(tx.GasCost, 10)
+		if !ok {
+			pending++
+			continue
 		}
-		assignedToID = chat.ID
-		assignedToType = "chat"
+		total.Add(total, cost)
+		priced++
+	}
+	if priced == 0 {
+		return ""
 	}
+	symbol := strings.ToUpper(chain)
+	units, _ := new(big.Float).Quo(new(big.Float).SetInt(total), big.NewFloat(1e18)).Float64()
+	if native, ok := thorchain.NativeToken(chain); ok {
+		symbol = native.Symbol
+		units = native.Units(total)
+	}
+	text := fmt.Sprintf("%.6f %s over %d tx", units, symbol, priced)
+	if pending > 0 {
+		text += fmt.Sprintf(" (%d unmined)", pending)
+	}
+	return text
+}
+
+// walletIndex returns the BIP44 derivation index for a message context; see
+// topups.Service.WalletIndex.
+func (b *Bot) walletIndex(msg *tgbotapi.Message) (uint32, error) {
+	return b.topups.WalletIndex(context.Background(), owner(msg))
+}
 
-	assignment, err := b.db.GetOrCreateAddressAssignment(ctx, assignedToID, assignedToType)
+// approveSelector is the ERC20 approve(address,uint256) method ID.
+var approveSelector = []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+// replyUnsignedTx hands a watch-only wallet's transaction to the chat: a short
+// summary plus the full transaction as JSON, ready for an offline signer.
+func (b *Bot) replyUnsignedTx(msg *tgbotapi.Message, quote *swaps.Quote, unsigned *wallet.UnsignedTxError) {
+	tx := unsigned.Tx
+	payload, err := json.MarshalIndent(map[string]interface{}{
+		"from":     unsigned.From,
+		"to":       tx.To(),
+		"value":    (*hexutil.Big)(tx.Value()),
+		"data":     hexutil.Bytes(tx.Data()),
+		"nonce":    hexutil.Uint64(tx.Nonce()),
+		"gas":      hexutil.Uint64(tx.Gas()),
+		"gasPrice": (*hexutil.Big)(tx.GasPrice()),
+		"chainId":  (*hexutil.Big)(unsigned.ChainID),
+	}, "", "  ")
 	if err != nil {
-		return 0, fmt.Errorf("address assignment: %w", err)
+		b.reply(msg, fmt.Sprintf("Error encoding transaction: %v", err))
+		return
+	}
+
+	text := fmt.Sprintf("*Watch-only wallet*: sign and broadcast the attached transaction from `%s` on %s to continue this %s swap.",
+		unsigned.From.Hex(), chainLabel(quote.FromChain), quote.Provider)
+	if bytes.HasPrefix(tx.Data(), approveSelector) {
+		text += "\nIt is a token approval; run the same command again once it confirms to get the swap transaction."
+	}
+	b.reply(msg, text)
+
+	doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FileBytes{Name: "unsigned-tx.json", Bytes: payload})
+	doc.ReplyToMessageID = msg.MessageID
+	if _, err := b.api.Send(doc); err != nil {
+		log.Printf("Error sending unsigned tx: %v", err)
 	}
-	return uint32(assignment.ID), nil
 }
 
 func (b *Bot) reply(msg *tgbotapi.Message, text string) {
@@ -577,7 +1184,8 @@ func (b *Bot) reply(msg *tgbotapi.Message, text string) {
 	}
 }
 
-// tryResolve attempts dynamic token resolution and sends a confirmation prompt.
+// tryResolve attempts dynamic token resolution and sends a confirmation
+// prompt, first asking the user which token they mean when several match.
 func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, destination string, usdAmount float64, hint swaps.RoutingHint) {
 	if b.resolver == nil {
 		b.reply(msg, fmt.Sprintf("Asset %s is not supported. No dynamic token resolution configured.", asset))
@@ -587,11 +1195,103 @@ func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, dest
 	b.reply(msg, fmt.Sprintf("Asset %s not in static list, looking up...", asset))
 
 	ctx := context.Background()
-	res, err := b.resolver.Resolve(ctx, asset)
+	if asset.ContractOnly() {
+		res, err := b.resolver.ResolveContract(ctx, asset)
+		if err != nil {
+			b.reply(msg, fmt.Sprintf("Could not resolve asset %s: %v", asset, err))
+			return
+		}
+		b.confirmResolution(msg, asset, res, command, destination, usdAmount, hint)
+		return
+	}
+
+	candidates, err := b.resolver.Candidates(ctx, asset)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Could not resolve asset %s: %v", asset, err))
+		return
+	}
+	if len(candidates) > 1 {
+		b.askCandidate(msg, asset, candidates, command, destination, usdAmount, hint)
+		return
+	}
+
+	res, err := b.resolver.ResolveCandidate(ctx, asset, candidates[0])
 	if err != nil {
 		b.reply(msg, fmt.Sprintf("Could not resolve asset %s: %v", asset, err))
 		return
 	}
+	b.confirmResolution(msg, asset, res, command, destination, usdAmount, hint)
+}
+
+// askCandidate lists the tokens matching the asset's symbol with a button
+// for each, so the user picks one instead of getting the best ranked.
+func (b *Bot) askCandidate(msg *tgbotapi.Message, asset swaps.Asset, candidates []resolver.Candidate, command, destination string, usdAmount float64, hint swaps.RoutingHint) {
+	id := randomID()
+
+	var lines []string
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i, c := range candidates {
+		rank := "unranked"
+		switch {
+		case c.CoinGeckoID == "":
+			rank = fmt.Sprintf("liquidity %s on DexScreener", formatMarketCap(c.LiquidityUSD))
+		case c.MarketCapRank > 0:
+			rank = fmt.Sprintf("rank #%d", c.MarketCapRank)
+		}
+		line := fmt.Sprintf("%d. *%s (%s)*, %s, market cap %s",
+			i+1, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, c.Name), tgbotapi.EscapeText(tgbotapi.ModeMarkdown, c.Symbol), rank, formatMarketCap(c.MarketCapUSD))
+		switch {
+		case c.ContractAddress != "":
+			line += fmt.Sprintf("\n    %s: `%s`", strings.ToUpper(asset.Chain), c.ContractAddress)
+		case len(c.Chains) > 0:
+			line += "\n    On: " + strings.Join(c.Chains, ", ")
+		default:
+			line += "\n    Native coin"
+		}
+		lines = append(lines, line)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, c.Name), fmt.Sprintf("resolve:pick:%s:%d", id, i)))
+	}
+
+	b.pendingMu.Lock()
+	b.pendingResolutions[id] = &pendingResolution{
+		Asset:       asset,
+		Candidates:  candidates,
+		Command:     command,
+		Destination: destination,
+		USDAmount:   usdAmount,
+		Hint:        hint,
+		ChatID:      msg.Chat.ID,
+		UserID:      msg.From.ID,
+		MessageID:   msg.MessageID,
+		CreatedAt:   time.Now(),
+	}
+	b.pendingMu.Unlock()
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, button := range buttons {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Cancel", "resolve:cancel:"+id)))
+
+	text := fmt.Sprintf("Several tokens match %s. Which one do you mean?\n\n%s", asset, strings.Join(lines, "\n"))
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	reply.ParseMode = "Markdown"
+	reply.DisableWebPagePreview = true
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.api.Send(reply); err != nil {
+		log.Printf("Error sending token choice prompt: %v", err)
+	}
+}
+
+// confirmResolution asks the user to confirm the resolved token before the
+// quote or topup goes ahead. asset is as the user typed it.
+func (b *Bot) confirmResolution(msg *tgbotapi.Message, asset swaps.Asset, res *resolver.Resolution, command, destination string, usdAmount float64, hint swaps.RoutingHint) {
+	typed := asset.String()
+	// An asset given by contract alone takes the resolved symbol
+	if asset.ContractOnly() {
+		asset.Symbol = res.Symbol
+	}
 
 	// Build confirmation message.
 	var providerNames []string
@@ -603,10 +1303,26 @@ func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, dest
 	if res.ContractAddress != "" {
 		contractDisplay = fmt.Sprintf("\nContract: `%s`", res.ContractAddress)
 	}
+	// Tokens only DexScreener knows are long-tail; say so and show how deep the pools are
+	if res.Source == resolver.SourceDexScreener {
+		contractDisplay += fmt.Sprintf("\nNot on CoinGecko; found on DexScreener with %s liquidity", formatMarketCap(res.LiquidityUSD))
+	}
 
-	text := fmt.Sprintf("Found: *%s (%s)*%s\nAvailable via: %s\n\nConfirm this token for your $%.2f %s?",
+	// Tokens failing the safety checks need an explicit override
+	warningDisplay := ""
+	confirmLabel, confirmAction := "Confirm", "confirm"
+	if len(res.Warnings) > 0 {
+		var lines []string
+		for _, w := range res.Warnings {
+			lines = append(lines, "- "+tgbotapi.EscapeText(tgbotapi.ModeMarkdown, w))
+		}
+		warningDisplay = "\n\n*Warning:* this token failed safety checks and may be a scam:\n" + strings.Join(lines, "\n")
+		confirmLabel, confirmAction = "Confirm anyway", "override"
+	}
+
+	text := fmt.Sprintf("Found: *%s (%s)*%s\nAvailable via: %s%s\n\nConfirm this token for your $%.2f %s?",
 		res.Name, res.Symbol, contractDisplay,
-		strings.Join(providerNames, ", "),
+		strings.Join(providerNames, ", "), warningDisplay,
 		usdAmount, command)
 
 	// Generate callback ID.
@@ -614,6 +1330,7 @@ func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, dest
 
 	b.pendingMu.Lock()
 	b.pendingResolutions[id] = &pendingResolution{
+		Typed:       typed,
 		Asset:       asset,
 		Resolution:  res,
 		Command:     command,
@@ -629,7 +1346,7 @@ func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, dest
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Confirm", "resolve:confirm:"+id),
+			tgbotapi.NewInlineKeyboardButtonData(confirmLabel, "resolve:"+confirmAction+":"+id),
 			tgbotapi.NewInlineKeyboardButtonData("Cancel", "resolve:cancel:"+id),
 		),
 	)
@@ -644,7 +1361,8 @@ func (b *Bot) tryResolve(msg *tgbotapi.Message, asset swaps.Asset, command, dest
 	}
 }
 
-// handleCallback processes inline keyboard callbacks for token confirmation.
+// handleCallback processes inline keyboard callbacks for token confirmation
+// and approvals.
 func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
 	// Always answer the callback to dismiss the loading indicator.
 	callback := tgbotapi.NewCallback(query.ID, "")
@@ -653,6 +1371,10 @@ func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
 	}
 
 	data := query.Data
+	if strings.HasPrefix(data, "approval:") {
+		b.handleApprovalCallback(query)
+		return
+	}
 	if !strings.HasPrefix(data, "resolve:") {
 		return
 	}
@@ -662,7 +1384,8 @@ func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
 		return
 	}
 	action := parts[1]
-	id := parts[2]
+	// Picks carry the candidate's index after the ID
+	id, choice, _ := strings.Cut(parts[2], ":")
 
 	b.pendingMu.Lock()
 	pending, ok := b.pendingResolutions[id]
@@ -686,13 +1409,31 @@ func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	if action != "confirm" {
+	if action == "pick" {
+		b.pickCandidate(query, pending, choice)
+		return
+	}
+	if pending.Resolution == nil {
+		return
+	}
+
+	switch action {
+	case "confirm":
+	case "override":
+		log.Printf("User %d confirmed %s (%s) despite failed safety checks: %s",
+			query.From.ID, pending.Resolution.Symbol, pending.Resolution.CoinGeckoID, strings.Join(pending.Resolution.Warnings, "; "))
+	default:
 		return
 	}
 
 	// Apply resolved hints to the asset.
 	pending.Asset.Hints = pending.Resolution.ToHints()
 
+	// Tokens confirmed despite failed safety checks are confirmed each time
+	if action == "confirm" {
+		b.rememberResolution(pending.ChatID, pending.Typed, pending.Asset)
+	}
+
 	// Build a synthetic message for the execute functions.
 	syntheticMsg := query.Message
 	if syntheticMsg == nil {
@@ -718,6 +1459,98 @@ func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
 	}
 }
 
+// resolvedAsset returns the token confirmed for asset in the chat within
+// resolutionTTL, with its hints.
+func (b *Bot) resolvedAsset(chatID int64, asset swaps.Asset) (swaps.Asset, bool) {
+	b.resolvedMu.Lock()
+	defer b.resolvedMu.Unlock()
+	key := resolvedKey{chatID: chatID, asset: strings.ToUpper(asset.String())}
+	r, ok := b.resolved[key]
+	if !ok {
+		return swaps.Asset{}, false
+	}
+	if time.Since(r.confirmedAt) > resolutionTTL {
+		delete(b.resolved, key)
+		return swaps.Asset{}, false
+	}
+	return r.asset, true
+}
+
+// rememberResolution keeps a confirmed token for the chat, so the asset as
+// typed is used without asking again. Expired entries are dropped.
+func (b *Bot) rememberResolution(chatID int64, typed string, asset swaps.Asset) {
+	b.resolvedMu.Lock()
+	defer b.resolvedMu.Unlock()
+	for key, r := range b.resolved {
+		if time.Since(r.confirmedAt) > resolutionTTL {
+			delete(b.resolved, key)
+		}
+	}
+	b.resolved[resolvedKey{chatID: chatID, asset: strings.ToUpper(typed)}] = resolvedAsset{asset: asset, confirmedAt: time.Now()}
+}
+
+// pickCandidate resolves the token the user picked from askCandidate's list
+// and asks them to confirm it.
+func (b *Bot) pickCandidate(query *tgbotapi.CallbackQuery, pending *pendingResolution, choice string) {
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 0 || n >= len(pending.Candidates) {
+		return
+	}
+	picked := pending.Candidates[n]
+
+	msg := query.Message
+	if msg == nil {
+		return
+	}
+	// Restore the original user and message ID, as for a confirmation.
+	msg.From = query.From
+	msg.MessageID = pending.MessageID
+
+	b.editCallbackMessage(query, fmt.Sprintf("Picked: *%s (%s)*",
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, picked.Name), tgbotapi.EscapeText(tgbotapi.ModeMarkdown, picked.Symbol)))
+
+	res, err := b.resolver.ResolveCandidate(context.Background(), pending.Asset, picked)
+	if err != nil {
+		b.reply(msg, fmt.Sprintf("Could not resolve asset %s: %v", pending.Asset, err))
+		return
+	}
+	b.confirmResolution(msg, pending.Asset, res, pending.Command, pending.Destination, pending.USDAmount, pending.Hint)
+}
+
+// handleApprovalCallback approves or rejects a queued request from the
+// admin's Approve and Reject buttons. The service updates the message.
+func (b *Bot) handleApprovalCallback(query *tgbotapi.CallbackQuery) {
+	if b.approvals == nil || query.From.ID != b.config.AdminUserID {
+		return
+	}
+	parts := strings.SplitN(query.Data, ":", 3)
+	if len(parts) != 3 {
+		return
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return
+	}
+
+	by := "telegram:" + strconv.FormatInt(query.From.ID, 10)
+	if query.From.UserName != "" {
+		by = "telegram:@" + query.From.UserName
+	}
+	ctx := context.Background()
+	switch parts[1] {
+	case "approve":
+		b.editCallbackMessage(query, fmt.Sprintf("Approval #%d: running...", id))
+		_, err = b.approvals.Approve(ctx, id, by)
+	case "reject":
+		_, err = b.approvals.Reject(ctx, id, by, "")
+	default:
+		return
+	}
+	if err != nil {
+		b.editCallbackMessage(query, fmt.Sprintf("Approval #%d: %v", id, err))
+	}
+}
+
 func (b *Bot) editCallbackMessage(query *tgbotapi.CallbackQuery, text string) {
 	if query.Message == nil {
 		return
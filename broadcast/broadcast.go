@@ -0,0 +1,210 @@
+// Package broadcast sends admin announcements, such as maintenance windows
+// or provider outages, to every chat the bot knows about.
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+const (
+	// sendInterval spaces out messages to stay under Telegram's limit of
+	// about 30 messages per second
+	sendInterval = 50 * time.Millisecond
+
+	// maxFloodWaits is how many times a message is retried after Telegram
+	// asks the bot to slow down
+	maxFloodWaits = 3
+
+	// MaxLength is the longest announcement accepted, leaving room in a
+	// Telegram message for the opt-out footer
+	MaxLength = 3900
+
+	footer = "\n\n—\nSend /announcements off to stop these messages."
+)
+
+// Broadcaster delivers queued announcements in the background. Deliveries
+// are recorded per chat, so an announcement interrupted by a restart picks
+// up where it stopped without messaging anyone twice.
+type Broadcaster struct {
+	cfg    *config.Config
+	store  *db.Store
+	botAPI *tgbotapi.BotAPI
+
+	wake chan struct{}
+}
+
+func New(cfg *config.Config, store *db.Store, botAPI *tgbotapi.BotAPI) *Broadcaster {
+	return &Broadcaster{
+		cfg:    cfg,
+		store:  store,
+		botAPI: botAPI,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Queue stores an announcement and wakes the sender. It returns the
+// broadcast ID to follow delivery by.
+func (b *Broadcaster) Queue(ctx context.Context, text string) (int64, error) {
+	if text == "" {
+		return 0, fmt.Errorf("announcement is empty")
+	}
+	if len(text) > MaxLength {
+		return 0, fmt.Errorf("announcement is longer than %d bytes", MaxLength)
+	}
+	id, err := b.store.InsertBroadcast(ctx, text)
+	if err != nil {
+		return 0, err
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+func (b *Broadcaster) Run(ctx context.Context) {
+	for {
+		b.sendQueued(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Broadcaster stopped")
+			return
+		case <-b.wake:
+		}
+	}
+}
+
+func (b *Broadcaster) sendQueued(ctx context.Context) {
+	queued, err := b.store.ListUnfinishedBroadcasts(ctx)
+	if err != nil {
+		log.Printf("Broadcaster: error listing broadcasts: %v", err)
+		return
+	}
+	for _, broadcast := range queued {
+		if ctx.Err() != nil {
+			return
+		}
+		b.send(ctx, broadcast)
+	}
+}
+
+// recipients returns the chats to announce to: every user and group chat
+// the bot has dealt with, and the configured users, minus those that opted
+// out. In single mode users no longer whitelisted are left out.
+func (b *Broadcaster) recipients(ctx context.Context) ([]int64, error) {
+	known, err := b.store.ListKnownChatIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known = append(known, b.cfg.AdminUserID)
+	known = append(known, b.cfg.Whitelist()...)
+
+	optOuts, err := b.store.ListAnnouncementOptOuts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var chats []int64
+	for _, id := range known {
+		// Private chat IDs are user IDs; group chat IDs are negative
+		if id == 0 || (id > 0 && !b.cfg.IsAuthorized(id)) || slices.Contains(optOuts, id) {
+			continue
+		}
+		chats = append(chats, id)
+	}
+	slices.Sort(chats)
+	return slices.Compact(chats), nil
+}
+
+func (b *Broadcaster) send(ctx context.Context, broadcast db.Broadcast) {
+	chats, err := b.recipients(ctx)
+	if err != nil {
+		log.Printf("Broadcaster: error listing recipients of broadcast %d: %v", broadcast.ID, err)
+		return
+	}
+	delivered, err := b.store.ListBroadcastDeliveredChatIDs(ctx, broadcast.ID)
+	if err != nil {
+		log.Printf("Broadcaster: error loading deliveries of broadcast %d: %v", broadcast.ID, err)
+		return
+	}
+	remaining := slices.DeleteFunc(chats, func(id int64) bool { return slices.Contains(delivered, id) })
+
+	if err := b.store.StartBroadcast(ctx, db.StartBroadcastParams{
+		Recipients: int64(len(delivered) + len(remaining)),
+		ID:         broadcast.ID,
+	}); err != nil {
+		log.Printf("Broadcaster: error starting broadcast %d: %v", broadcast.ID, err)
+		return
+	}
+	log.Printf("Broadcaster: sending broadcast %d to %d chat(s)", broadcast.ID, len(remaining))
+
+	ticker := time.NewTicker(sendInterval)
+	defer ticker.Stop()
+	for _, chatID := range remaining {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status, errText := "sent", ""
+		if err := b.deliver(ctx, chatID, broadcast.Text); err != nil {
+			status, errText = deliveryStatus(err), err.Error()
+			log.Printf("Broadcaster: broadcast %d to %d %s: %v", broadcast.ID, chatID, status, err)
+		}
+		if err := b.store.InsertBroadcastDelivery(ctx, db.InsertBroadcastDeliveryParams{
+			BroadcastID: broadcast.ID,
+			ChatID:      chatID,
+			Status:      status,
+			Error:       errText,
+		}); err != nil {
+			log.Printf("Broadcaster: error recording delivery of broadcast %d to %d: %v", broadcast.ID, chatID, err)
+		}
+	}
+
+	if err := b.store.FinishBroadcast(ctx, broadcast.ID); err != nil {
+		log.Printf("Broadcaster: error finishing broadcast %d: %v", broadcast.ID, err)
+		return
+	}
+	log.Printf("Broadcaster: broadcast %d done", broadcast.ID)
+}
+
+// deliver sends the announcement as plain text, so the admin's text can't
+// break Markdown parsing, waiting out Telegram's flood control.
+func (b *Broadcaster) deliver(ctx context.Context, chatID int64, text string) error {
+	msg := tgbotapi.NewMessage(chatID, text+footer)
+	msg.DisableWebPagePreview = true
+	for attempt := 0; ; attempt++ {
+		_, err := b.botAPI.Send(msg)
+		var tgErr *tgbotapi.Error
+		if !errors.As(err, &tgErr) || tgErr.RetryAfter == 0 || attempt == maxFloodWaits {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(tgErr.RetryAfter) * time.Second):
+		}
+	}
+}
+
+// deliveryStatus classifies a failed delivery: "blocked" when the user
+// blocked the bot or it was removed from the group, "failed" otherwise.
+func deliveryStatus(err error) string {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden {
+		return "blocked"
+	}
+	return "failed"
+}
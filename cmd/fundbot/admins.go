@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/server"
+)
+
+// addAdmin prompts for a password and creates the admin account username
+// with role, or resets the password and role of an existing one, e.g. to
+// recover from a lost superadmin login.
+func addAdmin(store *db.Store, username, role string) error {
+	if err := server.ValidateRole(role); err != nil {
+		return err
+	}
+	password, err := promptHidden("Password: ")
+	if err != nil {
+		return err
+	}
+	confirm, err := promptHidden("Repeat password: ")
+	if err != nil {
+		return err
+	}
+	if password != confirm {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	ctx := context.Background()
+	existing, err := store.GetAdminByUsername(ctx, username)
+	if err == sql.ErrNoRows {
+		if _, err := server.CreateAdmin(ctx, store, username, password, role); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s account %s\n", role, username)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	hash, err := server.HashAdminPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := store.UpdateAdminPassword(ctx, db.UpdateAdminPasswordParams{PasswordHash: hash, ID: existing.ID}); err != nil {
+		return err
+	}
+	if err := store.UpdateAdminRole(ctx, db.UpdateAdminRoleParams{Role: role, ID: existing.ID}); err != nil {
+		return err
+	}
+	fmt.Printf("Reset the password of %s and made it %s\n", username, role)
+	return nil
+}
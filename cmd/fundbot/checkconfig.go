@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+const (
+	// checkTimeout bounds each network check of -check-config
+	checkTimeout = 15 * time.Second
+
+	// checkMaxBlockAge is how far an RPC's latest block may lag, as in /readyz
+	checkMaxBlockAge = 5 * time.Minute
+)
+
+// checkResult is one line of the -check-config report. Status is "ok",
+// "FAIL" or "skip".
+type checkResult struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// checkConfig loads the config at path and checks it without starting the
+// bot: the wallet's mnemonics, every RPC endpoint, the Telegram token and
+// the enabled providers' APIs. It prints a report to stdout and returns an
+// error if any check failed. Nothing is written to the database.
+func checkConfig(path string) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		printChecks([]checkResult{{Name: "config", Status: "FAIL", Detail: err.Error()}})
+		return fmt.Errorf("config is invalid")
+	}
+
+	results := []checkResult{{Name: "config", Status: "ok", Detail: fmt.Sprintf("%s, %s mode", path, cfg.Mode)}}
+	results = append(results, checkWallet(cfg)...)
+
+	// Network checks run side by side; the report is sorted by name
+	var network []checkResult
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	add := func(r checkResult) {
+		mu.Lock()
+		network = append(network, r)
+		mu.Unlock()
+	}
+	for chain, urls := range cfg.RPCEndpoints {
+		for i, u := range urls {
+			name := "rpc:" + chain
+			if len(urls) > 1 {
+				name = fmt.Sprintf("rpc:%s[%d]", chain, i)
+			}
+			wg.Go(func() { add(checkRPCEndpoint(name, chain, u)) })
+		}
+	}
+	wg.Go(func() { add(checkTelegram(cfg.TelegramToken)) })
+	for _, p := range buildProviders(cfg, make(map[string]*ethclient.Client), nil) {
+		wg.Go(func() { add(checkProvider(p)) })
+	}
+	wg.Wait()
+	slices.SortFunc(network, func(a, b checkResult) int { return strings.Compare(a.Name, b.Name) })
+	results = append(results, network...)
+
+	printChecks(results)
+	failed := 0
+	for _, r := range results {
+		if r.Status == "FAIL" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func printChecks(results []checkResult) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Name, r.Status, r.Detail)
+	}
+	tw.Flush()
+}
+
+// checkWallet checks the mnemonic checksums, or whatever else holds the
+// keys, and shows the address at index 0 of each.
+func checkWallet(cfg *config.Config) []checkResult {
+	var results []checkResult
+	switch {
+	case cfg.Mnemonic != "":
+		results = append(results, checkMnemonic("wallet", cfg.Mnemonic))
+	case cfg.MnemonicKeystore != "":
+		pass, ok := os.LookupEnv(passphraseEnv)
+		if !ok {
+			r := checkResult{Name: "wallet", Status: "skip", Detail: fmt.Sprintf("keystore %s not unlocked; set %s to check it", cfg.MnemonicKeystore, passphraseEnv)}
+			if _, err := os.Stat(cfg.MnemonicKeystore); err != nil {
+				r.Status, r.Detail = "FAIL", err.Error()
+			}
+			results = append(results, r)
+			break
+		}
+		mnemonic, err := wallet.LoadMnemonic(cfg.MnemonicKeystore, pass)
+		if err != nil {
+			results = append(results, checkResult{Name: "wallet", Status: "FAIL", Detail: err.Error()})
+			break
+		}
+		results = append(results, checkMnemonic("wallet", mnemonic))
+	case cfg.XPub != "":
+		r := checkResult{Name: "wallet", Status: "ok"}
+		if k, err := wallet.NewWatchKeyring(cfg.XPub); err != nil {
+			r.Status, r.Detail = "FAIL", err.Error()
+		} else if addr, err := k.Address(0); err != nil {
+			r.Status, r.Detail = "FAIL", err.Error()
+		} else {
+			r.Detail = "watch-only xpub, index 0 is " + addr.Hex()
+		}
+		results = append(results, r)
+	case cfg.RemoteSigner != nil:
+		results = append(results, checkResult{Name: "wallet", Status: "skip", Detail: "remote signer at " + redactURL(cfg.RemoteSigner.URL)})
+	case cfg.Ledger != nil:
+		results = append(results, checkResult{Name: "wallet", Status: "skip", Detail: "Ledger, checked when connected"})
+	}
+
+	names := make([]string, 0, len(cfg.WalletPools))
+	for name := range cfg.WalletPools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		results = append(results, checkMnemonic("pool:"+name, cfg.WalletPools[name].Mnemonic))
+	}
+	return results
+}
+
+func checkMnemonic(name, mnemonic string) checkResult {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return checkResult{Name: name, Status: "FAIL", Detail: "mnemonic checksum is invalid"}
+	}
+	addr, err := wallet.DeriveAddress(mnemonic, 0)
+	if err != nil {
+		return checkResult{Name: name, Status: "FAIL", Detail: err.Error()}
+	}
+	return checkResult{Name: name, Status: "ok", Detail: "mnemonic valid, index 0 is " + addr.Hex()}
+}
+
+// checkRPCEndpoint dials one RPC URL on its own, without failover, and
+// checks its chain ID and latest block.
+func checkRPCEndpoint(name, chain, rawURL string) checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	r := checkResult{Name: name, Status: "FAIL", Detail: redactURL(rawURL)}
+	fail := func(err error) checkResult {
+		r.Detail += ": " + errorText(err)
+		return r
+	}
+
+	client, err := ethclient.DialContext(ctx, rawURL)
+	if err != nil {
+		return fail(err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fail(fmt.Errorf("chain ID: %w", err))
+	}
+	want, known := swaps.ChainIDs[chain]
+	if known && chainID.Int64() != want {
+		return fail(fmt.Errorf("chain ID %s, expected %d", chainID, want))
+	}
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fail(fmt.Errorf("latest block: %w", err))
+	}
+	age := time.Since(time.Unix(int64(head.Time), 0)).Truncate(time.Second)
+	if age > checkMaxBlockAge {
+		return fail(fmt.Errorf("latest block %s is %s old", head.Number, age))
+	}
+
+	r.Status = "ok"
+	r.Detail += fmt.Sprintf(": chain %s, block %s, %s old", chainID, head.Number, age)
+	if !known {
+		r.Detail += " (no expected chain ID for " + chain + ")"
+	}
+	return r
+}
+
+// checkTelegram asks Telegram who the bot token belongs to.
+func checkTelegram(token string) checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	r := checkResult{Name: "telegram", Status: "FAIL"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.telegram.org/bot"+token+"/getMe", nil)
+	if err != nil {
+		r.Detail = "invalid token"
+		return r
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		r.Detail = "Telegram API unreachable: " + errorText(err)
+		return r
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Result      struct {
+			Username string `json:"username"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		r.Detail = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return r
+	}
+	if !body.OK {
+		r.Detail = "token rejected: " + body.Description
+		return r
+	}
+	r.Status, r.Detail = "ok", "bot @"+body.Result.Username
+	return r
+}
+
+// checkProvider verifies a provider's API key where it can, and otherwise
+// that its API answers. Providers without an API are listed as skipped.
+func checkProvider(p swaps.Provider) checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	r := checkResult{Name: "provider:" + p.Name(), Status: "FAIL"}
+	if kc, ok := p.(swaps.KeyChecker); ok {
+		if err := kc.CheckKey(ctx); err != nil {
+			r.Detail = "key check: " + errorText(err)
+			return r
+		}
+		r.Status, r.Detail = "ok", "API key accepted"
+		return r
+	}
+
+	reporter, ok := p.(swaps.EndpointReporter)
+	if !ok {
+		r.Status, r.Detail = "skip", "no API to check"
+		return r
+	}
+	// As in /readyz, any answer below 500 means the API is up
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reporter.Endpoint(), nil)
+	if err != nil {
+		r.Detail = err.Error()
+		return r
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		r.Detail = errorText(err)
+		return r
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		r.Detail = fmt.Sprintf("%s: HTTP %d", reporter.Endpoint(), resp.StatusCode)
+		return r
+	}
+	r.Status, r.Detail = "ok", fmt.Sprintf("%s reachable (HTTP %d), key not checked", reporter.Endpoint(), resp.StatusCode)
+	return r
+}
+
+// redactURL keeps a URL's scheme and host, dropping paths and queries that
+// often carry API keys.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "(unparsable URL)"
+	}
+	if u.Path != "" && u.Path != "/" || u.RawQuery != "" {
+		return u.Scheme + "://" + u.Host + "/…"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// errorText describes err without the URL of a failed request, which can
+// hold an API key or the bot token.
+func errorText(err error) string {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return strings.Replace(err.Error(), uerr.Error(), uerr.Err.Error(), 1)
+	}
+	return err.Error()
+}
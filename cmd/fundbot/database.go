@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// databaseKeyEnv supplies the SQLCipher key without a prompt.
+const databaseKeyEnv = "FUNDBOT_DATABASE_KEY"
+
+// openDatabase opens the configured database, asking for the key first if
+// it is encrypted.
+func openDatabase(cfg *config.Config) (*db.Store, error) {
+	if !cfg.DatabaseEncrypted {
+		return db.Open(cfg.DatabaseDSN())
+	}
+	key, err := databaseKey(false)
+	if err != nil {
+		return nil, err
+	}
+	return db.OpenEncrypted(cfg.DatabasePath, key)
+}
+
+// databaseKey reads the SQLCipher key from FUNDBOT_DATABASE_KEY or, failing
+// that, the terminal, asking twice if confirm is set. The database is
+// needed before the admin panel starts, so unlike the keystore it can't
+// wait for an unlock there.
+func databaseKey(confirm bool) (string, error) {
+	if key, ok := os.LookupEnv(databaseKeyEnv); ok {
+		os.Unsetenv(databaseKeyEnv)
+		return key, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("the database is encrypted: set %s or run from a terminal", databaseKeyEnv)
+	}
+	key, err := promptHidden("Database key: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		repeat, err := promptHidden("Repeat database key: ")
+		if err != nil {
+			return "", err
+		}
+		if key != repeat {
+			return "", fmt.Errorf("keys do not match")
+		}
+	}
+	return key, nil
+}
+
+// encryptDatabase writes an encrypted copy of the plain SQLite database to
+// dest. Point database_path at it and set database_encrypted to switch.
+func encryptDatabase(cfg *config.Config, database *db.Store, dest string) error {
+	if cfg.DatabaseURL != "" || cfg.DatabaseEncrypted {
+		return fmt.Errorf("only an unencrypted SQLite database can be encrypted")
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	key, err := databaseKey(true)
+	if err != nil {
+		return err
+	}
+	return database.EncryptTo(context.Background(), dest, key)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/server"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// passphraseEnv unlocks the mnemonic keystore without a prompt.
+const passphraseEnv = "FUNDBOT_KEYSTORE_PASSPHRASE"
+
+// promptHidden reads a line from the terminal without echoing it.
+func promptHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// writeKeystore prompts for a mnemonic and passphrase and writes the
+// encrypted keystore to path.
+func writeKeystore(path string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("stdin is not a terminal")
+	}
+
+	mnemonic, err := promptHidden("Mnemonic: ")
+	if err != nil {
+		return err
+	}
+	passphrase, err := promptHidden("Passphrase: ")
+	if err != nil {
+		return err
+	}
+	confirm, err := promptHidden("Repeat passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase != confirm {
+		return fmt.Errorf("passphrases do not match")
+	}
+
+	data, err := wallet.EncryptMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// unlockKeystore fills in cfg.Mnemonic from the configured keystore. The
+// passphrase comes from FUNDBOT_KEYSTORE_PASSPHRASE, a terminal prompt, or,
+// when neither is available, the admin panel; in that case it blocks until
+// an admin unlocks the wallet.
+func unlockKeystore(cfg *config.Config, srv *server.Server) error {
+	if pass, ok := os.LookupEnv(passphraseEnv); ok {
+		os.Unsetenv(passphraseEnv)
+		mnemonic, err := wallet.LoadMnemonic(cfg.MnemonicKeystore, pass)
+		if err != nil {
+			return fmt.Errorf("unlocking with %s: %w", passphraseEnv, err)
+		}
+		cfg.Mnemonic = mnemonic
+		return nil
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		for attempt := 1; attempt <= 3; attempt++ {
+			pass, err := promptHidden("Keystore passphrase: ")
+			if err != nil {
+				return err
+			}
+			mnemonic, err := wallet.LoadMnemonic(cfg.MnemonicKeystore, pass)
+			if err == nil {
+				cfg.Mnemonic = mnemonic
+				return nil
+			}
+			log.Printf("Unlock attempt %d failed: %v", attempt, err)
+		}
+		return fmt.Errorf("too many failed unlock attempts")
+	}
+
+	// Check the file is readable now rather than on the first admin attempt
+	if _, err := os.Stat(cfg.MnemonicKeystore); err != nil {
+		return fmt.Errorf("reading keystore: %w", err)
+	}
+
+	unlocked := make(chan struct{})
+	srv.SetUnlocker(func(pass string) error {
+		mnemonic, err := wallet.LoadMnemonic(cfg.MnemonicKeystore, pass)
+		if err != nil {
+			return err
+		}
+		cfg.Mnemonic = mnemonic
+		close(unlocked)
+		return nil
+	})
+	log.Println("Wallet keystore is locked; waiting for unlock via the admin panel")
+	<-unlocked
+	return nil
+}
@@ -2,142 +2,519 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/RaghavSood/fundbot/alerts"
 	"github.com/RaghavSood/fundbot/apilog"
+	"github.com/RaghavSood/fundbot/approvals"
+	"github.com/RaghavSood/fundbot/backup"
 	"github.com/RaghavSood/fundbot/bot"
+	"github.com/RaghavSood/fundbot/broadcast"
 	"github.com/RaghavSood/fundbot/config"
 	"github.com/RaghavSood/fundbot/cowswap"
-	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/delivery"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/gasmonitor"
+	"github.com/RaghavSood/fundbot/gastreasury"
 	"github.com/RaghavSood/fundbot/houdini"
-	"github.com/RaghavSood/fundbot/nearintents"
+	"github.com/RaghavSood/fundbot/ledger"
 	"github.com/RaghavSood/fundbot/resolver"
+	"github.com/RaghavSood/fundbot/rpcpool"
 	"github.com/RaghavSood/fundbot/server"
 	"github.com/RaghavSood/fundbot/simpleswap"
+	"github.com/RaghavSood/fundbot/stealthex"
 	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/sweeper"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/topups"
 	"github.com/RaghavSood/fundbot/tracker"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
 )
 
 func main() {
 	configPath := flag.String("config", "config.json", "path to config file")
+	encryptTo := flag.String("encrypt-mnemonic", "", "write an encrypted mnemonic keystore to this path and exit")
+	moveID := flag.Int64("move-assignment", 0, "move this address assignment to -to-pool and exit")
+	toPool := flag.String("to-pool", "", "wallet pool for -move-assignment")
+	addAdminName := flag.String("add-admin", "", "create this admin account, or reset its password, and exit")
+	adminRole := flag.String("role", "viewer", "role for -add-admin: viewer, operator or superadmin")
+	encryptDBTo := flag.String("encrypt-database", "", "write an SQLCipher-encrypted copy of the SQLite database to this path and exit")
+	toMulti := flag.Bool("migrate-to-multi", false, "give a single-mode database's users and chats their own wallets for multi mode, and exit")
+	checkCfg := flag.Bool("check-config", false, "check the config, wallet, RPC endpoints and provider keys, print a report and exit")
 	flag.Parse()
 
+	// Also runs as "fundbot check-config"
+	if *checkCfg || flag.Arg(0) == "check-config" {
+		if err := checkConfig(*configPath); err != nil {
+			log.Fatalf("Config check failed: %v", err)
+		}
+		return
+	}
+
+	if *encryptTo != "" {
+		if err := writeKeystore(*encryptTo); err != nil {
+			log.Fatalf("Failed to write keystore: %v", err)
+		}
+		log.Printf("Keystore written to %s", *encryptTo)
+		return
+	}
+
 	cfg, err := config.Load(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Provider calls are logged with their secrets masked
+	apilog.SetRedaction(cfg.APILog.Redact)
+
 	// Open database (always needed now for quotes/topups tables)
-	database, err := db.Open(cfg.DatabasePath)
+	database, err := openDatabase(cfg)
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
 	defer database.Close()
 
+	if *encryptDBTo != "" {
+		if err := encryptDatabase(cfg, database, *encryptDBTo); err != nil {
+			log.Fatalf("Failed to encrypt database: %v", err)
+		}
+		log.Printf("Encrypted database written to %s", *encryptDBTo)
+		return
+	}
+
+	if *moveID != 0 {
+		if err := moveAssignment(cfg, database, *moveID, *toPool); err != nil {
+			log.Fatalf("Failed to move assignment: %v", err)
+		}
+		return
+	}
+
+	if *addAdminName != "" {
+		if err := addAdmin(database, *addAdminName, *adminRole); err != nil {
+			log.Fatalf("Failed to add admin: %v", err)
+		}
+		return
+	}
+
+	if *toMulti {
+		if err := migrateToMulti(cfg, database); err != nil {
+			log.Fatalf("Failed to migrate to multi mode: %v", err)
+		}
+		return
+	}
+	if err := checkModeSwitch(cfg, database); err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+
+	hasAdmins, err := server.SeedAdmin(context.Background(), cfg, database)
+	if err != nil {
+		log.Fatalf("Failed to set up admin accounts: %v", err)
+	}
+	if !hasAdmins {
+		log.Println("No admin accounts: set admin_password or run with -add-admin <name> -role superadmin to use the admin panel")
+	}
+	if err := server.LoadWhitelist(context.Background(), cfg, database); err != nil {
+		log.Fatalf("Failed to load whitelist changes: %v", err)
+	}
+
 	// Connect RPC clients
 	rpcClients := make(map[string]*ethclient.Client)
-	for name, url := range cfg.RPCEndpoints {
-		client, err := ethclient.Dial(url)
+	rpcPools := make(map[string]*rpcpool.Pool)
+	for name, urls := range cfg.RPCEndpoints {
+		client, pool, err := rpcpool.Dial(name, swaps.ChainIDs[name], urls)
 		if err != nil {
-			log.Fatalf("Failed to connect to %s RPC at %s: %v", name, url, err)
+			log.Fatalf("Failed to connect to %s RPC: %v", name, err)
 		}
 		rpcClients[name] = client
-		log.Printf("Connected to %s RPC", name)
+		if pool != nil {
+			rpcPools[name] = pool
+		}
+		log.Printf("Connected to %s RPC (%d endpoint(s))", name, len(urls))
 	}
 
-	// Initialize providers
-	var providers []swaps.Provider
-	tcProvider := thorchain.NewProvider(rpcClients, apilog.NewHTTPClient("thorchain", database))
-	providers = append(providers, tcProvider)
+	// Live updates from the bot and tracker, streamed by the HTTP server
+	bus := events.NewBus()
 
-	if ssCfg, ok := cfg.Providers["simpleswap"]; ok && ssCfg.APIKey != "" {
-		ssProvider := simpleswap.NewProvider(ssCfg.APIKey, rpcClients, apilog.NewHTTPClient("simpleswap", database))
-		providers = append(providers, ssProvider)
-		log.Println("SimpleSwap provider enabled")
+	// Start HTTP server early so a locked keystore can be unlocked from the admin panel
+	srv := server.New(cfg, database, rpcClients, bus)
+	srv.SetRPCPools(rpcPools)
+	go func() {
+		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("HTTP server error: %v", err)
+		}
+	}()
+
+	if cfg.MnemonicKeystore != "" {
+		if err := unlockKeystore(cfg, srv); err != nil {
+			log.Fatalf("Failed to unlock keystore: %v", err)
+		}
+		log.Println("Wallet keystore unlocked")
 	}
 
-	if niCfg, ok := cfg.Providers["nearintents"]; ok && niCfg.APIKey != "" {
-		niProvider := nearintents.NewProvider(niCfg.APIKey, rpcClients, apilog.NewHTTPClient("nearintents", database))
-		providers = append(providers, niProvider)
-		log.Println("Near Intents provider enabled")
+	keyring, err := walletKeyring(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up wallet signer: %v", err)
 	}
+	keyring = poolKeyring(cfg, database, keyring)
 
-	if hCfg, ok := cfg.Providers["houdini"]; ok && hCfg.APIKey != "" {
-		hHTTP := apilog.NewHTTPClient("houdini", database)
-		hProvider := houdini.NewProvider(hCfg.APIKey, hCfg.APISecret, rpcClients, hHTTP)
-		providers = append(providers, hProvider)
-		log.Println("Houdini Swap provider enabled")
+	for _, chain := range cfg.DisabledChains {
+		swaps.SetChainEnabled(chain, false)
+		log.Printf("%s disabled by config", chain)
+	}
 
-		hanonProvider := houdini.NewAnonProvider(hCfg.APIKey, hCfg.APISecret, rpcClients, hHTTP)
-		providers = append(providers, hanonProvider)
-		log.Println("Houdini anonymous provider enabled")
+	// Apply configured funding stablecoins before any provider quotes
+	if len(cfg.FundingTokens) > 0 {
+		tokens, err := fundingTokens(cfg.FundingTokens)
+		if err != nil {
+			log.Fatalf("Invalid funding_tokens: %v", err)
+		}
+		thorchain.SetFundingTokens(tokens)
 	}
 
+	// Smart accounts wrap the derived keys and need the funding tokens for the paymaster
+	if cfg.SmartAccount != nil {
+		keyring, err = smartAccountKeyring(cfg, rpcClients, keyring)
+		if err != nil {
+			log.Fatalf("Failed to set up smart accounts: %v", err)
+		}
+	}
+	srv.SetKeyring(keyring)
+
+	// Native funding tokens are priced from Thorchain pools
+	swaps.SetNativePricer(thorchain.NewClient(apilog.NewHTTPClient("thorchain", database)))
+
+	for chain := range cfg.GasRefills {
+		if _, ok := cowswap.SupportedChains[chain]; !ok {
+			log.Fatalf("Invalid gas_refills: CoW Protocol not supported on %s", chain)
+		}
+	}
+	for chain, d := range cfg.CowPermitDomains {
+		if err := cowswap.SetPermitDomain(chain, cowswap.PermitDomain{Name: d.Name, Version: d.Version}); err != nil {
+			log.Fatalf("Invalid cow_permit_domains: %v", err)
+		}
+	}
+
+	providers := buildProviders(cfg, rpcClients, database)
+
 	// Initialize swap manager
-	swapMgr := swaps.NewManager(rpcClients, thorchain.USDCContracts, providers...)
+	swapMgr := swaps.NewManager(rpcClients, thorchain.FundingTokens, providers...)
 
 	// Initialize CoWSwap client for gas refills
 	cowClient := cowswap.NewClient(rpcClients, apilog.NewHTTPClient("cowswap", database))
+	cowClient.SetPartner(cowPartner(cfg))
 	log.Println("CoWSwap client enabled for gas refills")
 
 	// Initialize token resolver
 	var res *resolver.Resolver
-	if cfg.CoinGeckoAPIKey != "" {
-		res = resolver.New(cfg.CoinGeckoAPIKey, simpleswap.LookupSymbol, houdini.LookupSymbol)
+	if cgKeys := cfg.CoinGeckoKeys(); len(cgKeys) > 0 {
+		res = resolver.New(cgKeys, simpleswap.LookupSymbol, houdini.LookupSymbol, stealthex.LookupCurrency)
+		res.SetCoinGeckoPro(cfg.CoinGeckoPro)
+		res.SetStore(database)
+		if cfg.TokenScreening != nil {
+			res.SetScreening(cfg.TokenScreening)
+			log.Println("Token screening enabled for resolved tokens")
+		}
 
 		// Set up dynamic currency lookup for private providers
-		if ssCfg, ok := cfg.Providers["simpleswap"]; ok && ssCfg.APIKey != "" {
+		if ssCfg, ok := cfg.Provider("simpleswap"); ok && ssCfg.APIKey != "" {
 			ssClient := simpleswap.NewClient(ssCfg.APIKey, apilog.NewHTTPClient("simpleswap-resolver", database))
 			res.SetSimpleSwapClient(ssClient)
 		}
-		if hCfg, ok := cfg.Providers["houdini"]; ok && hCfg.APIKey != "" {
+		if hCfg, ok := cfg.Provider("houdini"); ok && hCfg.APIKey != "" {
 			hClient := houdini.NewClient(hCfg.APIKey, hCfg.APISecret, apilog.NewHTTPClient("houdini-resolver", database))
 			res.SetHoudiniClient(hClient)
 		}
+		if sxCfg, ok := cfg.Provider("stealthex"); ok && sxCfg.APIKey != "" {
+			sxClient := stealthex.NewClient(sxCfg.APIKey, apilog.NewHTTPClient("stealthex-resolver", database))
+			res.SetStealthEXClient(sxClient)
+		}
 
 		// Refresh private provider currency lists
 		res.RefreshPrivateProviders(context.Background())
-		log.Println("Token resolver enabled (CoinGecko)")
+		log.Printf("Token resolver enabled (CoinGecko, %d key(s), pro=%v)", len(cgKeys), cfg.CoinGeckoPro)
 	}
 
 	// Create and run bot
-	b, err := bot.New(cfg, database, swapMgr, rpcClients, cowClient, res)
+	hooks := webhooks.New(cfg.Webhooks)
+	if hooks != nil {
+		log.Printf("Webhooks enabled: %d endpoint(s)", len(cfg.Webhooks))
+	}
+
+	// Quotes and topups are shared by the bot and the REST API
+	svc := topups.New(cfg, database, swapMgr, keyring, hooks, bus)
+	srv.SetTopups(svc, swapMgr)
+	srv.SetResolver(res)
+	srv.SetGasRefills(cowClient, hooks)
+
+	reload := configReloader(cfg, swapMgr, cowClient, rpcClients, database)
+	srv.SetReloader(reload)
+	reloadOnSIGHUP(reload)
+
+	// Native gas for wallets that can't pay for a refill
+	var treasury *gastreasury.Treasury
+	if cfg.GasTreasury != nil {
+		if wallet.WatchOnly(keyring) {
+			log.Println("Gas treasury disabled: watch-only wallet cannot sign")
+		} else if addr, err := keyring.Address(cfg.GasTreasury.Index); err != nil {
+			log.Fatalf("Failed to derive gas treasury wallet: %v", err)
+		} else if _, err := database.GetAddressAssignmentByID(context.Background(), int64(cfg.GasTreasury.Index)); err == nil {
+			log.Fatalf("gas_treasury index %d is already assigned to a user or chat", cfg.GasTreasury.Index)
+		} else {
+			treasury = gastreasury.New(cfg, database, keyring, rpcClients)
+			log.Printf("Gas treasury enabled: wallet %d (%s)", cfg.GasTreasury.Index, addr.Hex())
+		}
+	}
+
+	// Large topups and gas refills wait for the admin
+	var appr *approvals.Service
+	if cfg.Approvals != nil {
+		appr = approvals.New(cfg, database, svc, keyring, rpcClients, cowClient, hooks)
+		srv.SetApprovals(appr)
+	}
+
+	b, err := bot.New(cfg, database, swapMgr, rpcClients, cowClient, treasury, appr, res, keyring, hooks, bus, svc)
 	if err != nil {
 		log.Fatalf("Failed to create bot: %v", err)
 	}
 
-	// Start HTTP server
-	srv := server.New(cfg, database, rpcClients)
-	go func() {
-		if err := srv.Start(); err != nil {
-			log.Fatalf("HTTP server error: %v", err)
-		}
-	}()
+	// Background workers stop at the end of their current pass on shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	if appr != nil {
+		appr.SetBotAPI(b.BotAPI())
+		workers.Go(func() { appr.Run(ctx) })
+		log.Printf("Approvals enabled: above $%.2f, expiring after %d hours", cfg.Approvals.ThresholdUSD, cfg.Approvals.ExpiryHours)
+	}
+
+	checker := delivery.New(cfg.DeliveryCheck, rpcClients)
+	if checker != nil {
+		log.Printf("Delivery check enabled")
+	}
 
 	// Start swap completion tracker
-	ctx, cancel := context.WithCancel(context.Background())
-	trk := tracker.New(cfg, database, swapMgr, cowClient, b.BotAPI())
-	go trk.Run(ctx)
+	trk := tracker.New(cfg, database, swapMgr, cowClient, checker, keyring, hooks, bus, b.BotAPI())
+	workers.Go(func() { trk.Run(ctx) })
+	for _, pool := range rpcPools {
+		workers.Go(func() { pool.Run(ctx) })
+	}
+
+	// Start treasury sweeps
+	if cfg.Sweep != nil {
+		if wallet.WatchOnly(keyring) {
+			log.Println("Treasury sweeps disabled: watch-only wallet cannot sign")
+		} else {
+			swp := sweeper.New(cfg, database, keyring, rpcClients, cowClient, svc, b.BotAPI())
+			workers.Go(func() { swp.Run(ctx) })
+			log.Printf("Treasury sweeps enabled: above %.2f USDC to %s via %s", cfg.Sweep.Ceiling, cfg.Sweep.Treasury, cfg.Sweep.Method)
+		}
+	}
+
+	// Background gas refills for every wallet
+	if cfg.GasMonitor != nil {
+		switch {
+		case wallet.WatchOnly(keyring):
+			log.Println("Gas monitor disabled: watch-only wallet cannot sign")
+		case cfg.SmartAccount != nil:
+			log.Println("Gas monitor disabled: smart accounts pay gas through the paymaster")
+		default:
+			mon := gasmonitor.New(cfg, database, keyring, rpcClients, cowClient, treasury, appr, hooks, b.BotAPI())
+			workers.Go(func() { mon.Run(ctx) })
+			log.Printf("Gas monitor enabled: every %d minutes, %d minute cooldown", cfg.GasMonitor.IntervalMinutes, cfg.GasMonitor.CooldownMinutes)
+		}
+	}
+
+	// Low balance alerts to the admin
+	if cfg.BalanceAlerts != nil {
+		alerter := alerts.New(cfg, database, keyring, rpcClients, b.BotAPI())
+		workers.Go(func() { alerter.Run(ctx) })
+		log.Printf("Balance alerts enabled: every %d minutes, digest after %02d:00 UTC", cfg.BalanceAlerts.IntervalMinutes, cfg.BalanceAlerts.DigestHour)
+	}
+
+	// Admin announcements, queued from the admin panel
+	bc := broadcast.New(cfg, database, b.BotAPI())
+	srv.SetBroadcaster(bc)
+	srv.SetBotAPI(b.BotAPI())
+	workers.Go(func() { bc.Run(ctx) })
 
+	// Wallet balances for the admin panel, read ahead of views
+	workers.Go(func() { srv.RunBalances(ctx) })
+	if cfg.BalanceHistory != nil {
+		workers.Go(func() { srv.RunBalanceHistory(ctx) })
+		log.Printf("Balance history enabled: every %d minutes", cfg.BalanceHistory.IntervalMinutes)
+	}
+
+	// Provider API logs, trimmed to the api_log retention limits
+	workers.Go(func() { apilog.RunPruner(ctx, database, cfg.APILog) })
+
+	// Scheduled database snapshots
+	if cfg.Backup != nil {
+		bk, err := backup.New(ctx, cfg.Backup, database)
+		if err != nil {
+			log.Fatalf("Failed to set up backups: %v", err)
+		}
+		srv.SetBackups(bk)
+		workers.Go(func() { bk.Run(ctx) })
+		log.Printf("Backups enabled: every %d minutes to %s, keeping %d", cfg.Backup.IntervalMinutes, cfg.Backup.Dir, cfg.Backup.Keep)
+	}
+
+	// Wallet ledger, reconciled with on-chain balances
+	if cfg.Reconcile != nil {
+		rec := ledger.New(cfg, database, keyring, rpcClients)
+		workers.Go(func() { rec.Run(ctx) })
+		log.Printf("Ledger reconciliation enabled: every %d minutes", cfg.Reconcile.IntervalMinutes)
+	}
+
+	// The first signal stops taking Telegram updates; Run returns once the
+	// update being handled (possibly a swap) is done. A second one forces exit.
 	go func() {
 		sig := make(chan os.Signal, 1)
 		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 		<-sig
-		log.Println("Shutting down...")
-		cancel()
+		log.Println("Shutting down, finishing in-flight requests...")
 		b.Stop()
-		os.Exit(0)
+		<-sig
+		log.Println("Forced exit")
+		os.Exit(1)
 	}()
 
 	log.Println("Starting FundBot...")
 	if err := b.Run(); err != nil {
 		log.Fatalf("Bot error: %v", err)
 	}
+
+	shutdown(cancel, &workers, srv)
+}
+
+// shutdownTimeout bounds how long shutdown waits for the HTTP server and
+// pending API log writes.
+const shutdownTimeout = 15 * time.Second
+
+// shutdown stops the background workers, then the HTTP server, and flushes
+// API logs so the deferred database close loses nothing.
+func shutdown(cancel context.CancelFunc, workers *sync.WaitGroup, srv *server.Server) {
+	cancel()
+	workers.Wait()
+
+	ctx, done := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer done()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
+	}
+	if err := apilog.Flush(ctx); err != nil {
+		log.Printf("API log flush: %v", err)
+	}
+	log.Println("Shutdown complete")
+}
+
+// walletKeyring returns the configured source of wallet signers: KMS keys for
+// the indexes that have one, and a remote signer, Ledger, xpub or the
+// (possibly just unlocked) mnemonic for the rest.
+func walletKeyring(cfg *config.Config) (wallet.Keyring, error) {
+	var base wallet.Keyring
+	switch {
+	case cfg.RemoteSigner != nil:
+		addresses := make([]common.Address, len(cfg.RemoteSigner.Addresses))
+		for i, addr := range cfg.RemoteSigner.Addresses {
+			addresses[i] = common.HexToAddress(addr)
+		}
+		remote, err := wallet.NewRemoteKeyring(cfg.RemoteSigner.URL, addresses)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Using remote signer with %d addresses", len(addresses))
+		base = remote
+	case cfg.Ledger != nil:
+		ledger, err := wallet.NewLedgerKeyring(time.Duration(cfg.Ledger.ConfirmTimeout) * time.Second)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Using Ledger signer")
+		base = ledger
+	case cfg.XPub != "":
+		watch, err := wallet.NewWatchKeyring(cfg.XPub)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Watch-only mode: topups reply with unsigned transactions")
+		base = watch
+	case cfg.Mnemonic != "":
+		base = wallet.NewMnemonicKeyring(cfg.Mnemonic)
+	}
+
+	if len(cfg.KMSKeys) == 0 {
+		return base, nil
+	}
+
+	ctx := context.Background()
+	clients := make(map[string]*kms.Client)
+	signers := make(map[uint32]wallet.Signer, len(cfg.KMSKeys))
+	for index, key := range cfg.KMSKeys {
+		client, ok := clients[key.Region]
+		if !ok {
+			var opts []func(*awsconfig.LoadOptions) error
+			if key.Region != "" {
+				opts = append(opts, awsconfig.WithRegion(key.Region))
+			}
+			awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("loading AWS config: %w", err)
+			}
+			client = kms.NewFromConfig(awsCfg)
+			clients[key.Region] = client
+		}
+
+		signer, err := wallet.NewKMSSigner(ctx, client, key.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("kms_keys %d: %w", index, err)
+		}
+		signers[index] = signer
+		log.Printf("Wallet index %d signs via KMS as %s", index, signer.Address().Hex())
+	}
+
+	return wallet.NewOverrideKeyring(base, signers), nil
+}
+
+// fundingTokens converts the funding_tokens config into token lists, filling in
+// address and decimals from the built-in catalog for known symbols.
+func fundingTokens(cfg map[string][]config.FundingTokenConfig) (swaps.FundingTokens, error) {
+	result := make(swaps.FundingTokens, len(cfg))
+	for chain, entries := range cfg {
+		for _, e := range entries {
+			token, known := thorchain.KnownFundingTokens[chain][strings.ToUpper(e.Symbol)]
+			if e.Address != "" {
+				if !common.IsHexAddress(e.Address) {
+					return nil, fmt.Errorf("%s %s: invalid address %q", chain, e.Symbol, e.Address)
+				}
+				token.Address = common.HexToAddress(e.Address)
+			}
+			if e.Decimals != 0 {
+				token.Decimals = e.Decimals
+			}
+			if !known && (e.Address == "" || e.Decimals == 0) {
+				return nil, fmt.Errorf("%s %s: unknown token needs an address and decimals", chain, e.Symbol)
+			}
+			if !known {
+				token.Symbol = e.Symbol
+			}
+			result[chain] = append(result[chain], token)
+		}
+	}
+	return result, nil
 }
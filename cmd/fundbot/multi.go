@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// migrateToMulti prepares a single-mode database for multi mode (see
+// Store.MigrateToMulti) and prints the wallets it assigned.
+func migrateToMulti(cfg *config.Config, store *db.Store) error {
+	m, err := store.MigrateToMulti(context.Background(), cfg.AdminUserID, cfg.PoolFor)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Assigned %d wallet(s):\n", len(m.Assignments))
+	for _, a := range m.Assignments {
+		owner := fmt.Sprintf("%s %d", a.AssignedToType, a.AssignedToID)
+		if a.ID == 0 {
+			owner += " (admin user, the shared wallet)"
+		}
+		fmt.Printf("  index %d, pool %s: %s  %s\n", a.ID, a.Pool, owner, poolAddress(cfg, a.Pool, uint32(a.ID)))
+	}
+	fmt.Printf("Pinned %d earlier topup(s) to the shared wallet.\n", m.LegacyTopups)
+	fmt.Println("Funds in the shared wallet stay there; transfer users their share from the admin panel if needed.")
+	fmt.Println("Set \"mode\": \"multi\" in the config and restart.")
+	return nil
+}
+
+// checkModeSwitch refuses to run in multi mode on a single-mode database
+// that was never migrated, where every user would silently get a new,
+// empty wallet while the shared wallet's funds went untracked.
+func checkModeSwitch(cfg *config.Config, store *db.Store) error {
+	if cfg.Mode != config.ModeMulti {
+		return nil
+	}
+	ctx := context.Background()
+	_, err := store.GetAddressAssignmentByID(ctx, 0)
+	if err != sql.ErrNoRows {
+		return err
+	}
+	n, err := store.CountTopupsByWalletIndex(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("the database has %d topups from the single-mode wallet; run with -migrate-to-multi before switching to multi mode", n)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// poolKeyring routes wallet indexes to their assigned pool when wallet_pools
+// are configured; otherwise the default pool's keyring is used directly.
+func poolKeyring(cfg *config.Config, store *db.Store, base wallet.Keyring) wallet.Keyring {
+	if len(cfg.WalletPools) == 0 {
+		return base
+	}
+
+	pools := make(map[string]wallet.Keyring, len(cfg.WalletPools))
+	for name, pool := range cfg.WalletPools {
+		pools[name] = wallet.NewMnemonicKeyring(pool.Mnemonic)
+	}
+	log.Printf("Using %d extra wallet pool(s)", len(pools))
+
+	return wallet.NewPoolKeyring(base, pools, func(index uint32) (string, error) {
+		return store.WalletPool(context.Background(), index)
+	})
+}
+
+// moveAssignment moves a wallet to another pool. It keeps its index, so it
+// gets the address at that index in the new pool; funds at the old address
+// stay there until moved by hand.
+func moveAssignment(cfg *config.Config, store *db.Store, id int64, pool string) error {
+	if !cfg.HasPool(pool) {
+		return fmt.Errorf("unknown pool %s", pool)
+	}
+
+	ctx := context.Background()
+	a, err := store.GetAddressAssignmentByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no address assignment %d", id)
+	}
+	if err != nil {
+		return err
+	}
+	if a.Pool == pool {
+		return fmt.Errorf("assignment %d is already in pool %s", id, pool)
+	}
+
+	if err := store.UpdateAddressAssignmentPool(ctx, db.UpdateAddressAssignmentPoolParams{
+		Pool: pool,
+		ID:   id,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Moved assignment %d (%s %d) from pool %s to %s\n", id, a.AssignedToType, a.AssignedToID, a.Pool, pool)
+	fmt.Printf("  old address: %s\n", poolAddress(cfg, a.Pool, uint32(id)))
+	fmt.Printf("  new address: %s\n", poolAddress(cfg, pool, uint32(id)))
+	fmt.Println("Funds at the old address were not moved; transfer them (e.g. after exporting its key from the admin panel).")
+	return nil
+}
+
+// poolAddress derives the address of index in pool for display, where that
+// is possible without a remote signer, Ledger, KMS or a locked keystore.
+func poolAddress(cfg *config.Config, pool string, index uint32) string {
+	mnemonic := cfg.Mnemonic
+	if pool != wallet.DefaultPool {
+		mnemonic = cfg.WalletPools[pool].Mnemonic
+	} else if _, ok := cfg.KMSKeys[index]; ok {
+		return "(KMS key)"
+	}
+	if mnemonic == "" {
+		return "(not derivable offline)"
+	}
+	addr, err := wallet.DeriveAddress(mnemonic, index)
+	if err != nil {
+		return fmt.Sprintf("(error: %v)", err)
+	}
+	return addr.Hex()
+}
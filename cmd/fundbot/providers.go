@@ -0,0 +1,145 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/across"
+	"github.com/RaghavSood/fundbot/apilog"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/garden"
+	"github.com/RaghavSood/fundbot/houdini"
+	"github.com/RaghavSood/fundbot/nearintents"
+	"github.com/RaghavSood/fundbot/rango"
+	"github.com/RaghavSood/fundbot/relay"
+	"github.com/RaghavSood/fundbot/simpleswap"
+	"github.com/RaghavSood/fundbot/squid"
+	"github.com/RaghavSood/fundbot/stealthex"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/thorswap"
+	"github.com/RaghavSood/fundbot/uniswap"
+	"github.com/RaghavSood/fundbot/zeroex"
+)
+
+// buildProviders sets up the swap providers, those that need an API key
+// only when the config has one. It runs again when the config is reloaded.
+func buildProviders(cfg *config.Config, rpcClients map[string]*ethclient.Client, database *db.Store) []swaps.Provider {
+	var providers []swaps.Provider
+	affiliates := cfg.Affiliate()
+	var tcAffiliate thorchain.Affiliate
+	if a := affiliates.Thorchain; a != nil {
+		tcAffiliate = thorchain.Affiliate{Name: a.Name, Bps: a.Bps}
+		log.Printf("Thorchain affiliate %s at %d bps", a.Name, a.Bps)
+	}
+
+	tcProvider := thorchain.NewProvider(rpcClients, apilog.NewHTTPClient("thorchain", database), tcAffiliate)
+	providers = append(providers, tcProvider)
+
+	tcsProvider := thorchain.NewStreamingProvider(rpcClients, apilog.NewHTTPClient("thorchain", database), thorchain.StreamingParams{
+		Interval: cfg.ThorchainStreaming.Interval,
+		Quantity: cfg.ThorchainStreaming.Quantity,
+	}, tcAffiliate)
+	providers = append(providers, tcsProvider)
+
+	acProvider := across.NewProvider(rpcClients, apilog.NewHTTPClient("across", database))
+	providers = append(providers, acProvider)
+
+	// On-chain only (no API), so always available when a Base RPC is configured
+	if _, ok := rpcClients["base"]; ok {
+		providers = append(providers, uniswap.NewProvider(rpcClients))
+	}
+
+	if ssCfg, ok := cfg.Provider("simpleswap"); ok && ssCfg.APIKey != "" {
+		ssProvider := simpleswap.NewProvider(ssCfg.APIKey, affiliates.SimpleSwap, rpcClients, apilog.NewHTTPClient("simpleswap", database))
+		providers = append(providers, ssProvider)
+		log.Println("SimpleSwap provider enabled")
+	}
+
+	if niCfg, ok := cfg.Provider("nearintents"); ok && niCfg.APIKey != "" {
+		niProvider := nearintents.NewProvider(niCfg.APIKey, rpcClients, apilog.NewHTTPClient("nearintents", database))
+		providers = append(providers, niProvider)
+		log.Println("Near Intents provider enabled")
+	}
+
+	if hCfg, ok := cfg.Provider("houdini"); ok && hCfg.APIKey != "" {
+		hHTTP := apilog.NewHTTPClient("houdini", database)
+		hProvider := houdini.NewProvider(hCfg.APIKey, hCfg.APISecret, affiliates.Houdini, rpcClients, hHTTP)
+		providers = append(providers, hProvider)
+		log.Println("Houdini Swap provider enabled")
+
+		hanonProvider := houdini.NewAnonProvider(hCfg.APIKey, hCfg.APISecret, affiliates.Houdini, rpcClients, hHTTP)
+		providers = append(providers, hanonProvider)
+		log.Println("Houdini anonymous provider enabled")
+	}
+
+	if sxCfg, ok := cfg.Provider("stealthex"); ok && sxCfg.APIKey != "" {
+		sxProvider := stealthex.NewProvider(sxCfg.APIKey, rpcClients, apilog.NewHTTPClient("stealthex", database))
+		providers = append(providers, sxProvider)
+		log.Println("StealthEX provider enabled")
+	}
+
+	// Relay works without an API key; a key only raises rate limits.
+	if rlCfg, ok := cfg.Provider("relay"); ok {
+		rlProvider := relay.NewProvider(rlCfg.APIKey, rpcClients, apilog.NewHTTPClient("relay", database))
+		providers = append(providers, rlProvider)
+		log.Println("Relay provider enabled")
+	}
+
+	// Squid's api_key is the integrator ID issued by Squid.
+	if sqCfg, ok := cfg.Provider("squid"); ok && sqCfg.APIKey != "" {
+		sqProvider := squid.NewProvider(sqCfg.APIKey, rpcClients, apilog.NewHTTPClient("squid", database))
+		providers = append(providers, sqProvider)
+		log.Println("Squid provider enabled")
+	}
+
+	if rgCfg, ok := cfg.Provider("rango"); ok && rgCfg.APIKey != "" {
+		rgProvider := rango.NewProvider(rgCfg.APIKey, rpcClients, apilog.NewHTTPClient("rango", database))
+		providers = append(providers, rgProvider)
+		log.Println("Rango provider enabled")
+	}
+
+	if zxCfg, ok := cfg.Provider("zeroex"); ok && zxCfg.APIKey != "" {
+		zxProvider := zeroex.NewProvider(zxCfg.APIKey, rpcClients, apilog.NewHTTPClient("zeroex", database))
+		providers = append(providers, zxProvider)
+		log.Println("0x provider enabled")
+	}
+
+	if tsCfg, ok := cfg.Provider("thorswap"); ok && tsCfg.APIKey != "" {
+		tsProvider := thorswap.NewProvider(tsCfg.APIKey, rpcClients, apilog.NewHTTPClient("thorswap", database))
+		providers = append(providers, tsProvider)
+		log.Println("ThorSwap provider enabled")
+	}
+
+	// Garden's api_key is the app ID issued by Garden.
+	if gdCfg, ok := cfg.Provider("garden"); ok && gdCfg.APIKey != "" {
+		gdProvider := garden.NewProvider(gdCfg.APIKey, rpcClients, apilog.NewHTTPClient("garden", database))
+		providers = append(providers, gdProvider)
+		log.Println("Garden provider enabled")
+	}
+
+	// Smart accounts can only use providers that run as a batch of calls
+	if cfg.SmartAccount != nil {
+		providers = swaps.CallExecutors(providers)
+		log.Printf("Smart accounts: %d provider(s) can execute from the account", len(providers))
+	}
+
+	return providers
+}
+
+// cowPartner returns the appData partner settings for CoW orders.
+func cowPartner(cfg *config.Config) cowswap.Partner {
+	a := cfg.Affiliate().CowSwap
+	if a == nil {
+		return cowswap.Partner{}
+	}
+	return cowswap.Partner{
+		AppCode:   a.AppCode,
+		FeeBps:    a.Bps,
+		Recipient: common.HexToAddress(a.Recipient),
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// configReloader returns the function SIGHUP and the admin panel reload the
+// config with: Config.Reload, then the swap providers rebuilt with the new
+// provider keys and affiliates, and the new CoW partner settings.
+func configReloader(cfg *config.Config, swapMgr *swaps.Manager, cowClient *cowswap.Client, rpcClients map[string]*ethclient.Client, database *db.Store) func() ([]string, error) {
+	return func() ([]string, error) {
+		restart, err := cfg.Reload()
+		if err != nil {
+			return nil, err
+		}
+		swapMgr.SetProviders(buildProviders(cfg, rpcClients, database)...)
+		cowClient.SetPartner(cowPartner(cfg))
+		log.Println("Config reloaded")
+		if len(restart) > 0 {
+			log.Printf("Config changes to %s take effect on restart", strings.Join(restart, ", "))
+		}
+		return restart, nil
+	}
+}
+
+// reloadOnSIGHUP reloads the config each time the process gets SIGHUP.
+func reloadOnSIGHUP(reload func() ([]string, error)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if _, err := reload(); err != nil {
+				log.Printf("Config reload failed, keeping the running config: %v", err)
+			}
+		}
+	}()
+}
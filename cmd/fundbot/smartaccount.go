@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/aa"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// smartAccountKeyring wraps the derived keys as owners of ERC-4337 smart
+// accounts. Chains without a bundler are disabled, since the accounts can
+// only act through one.
+func smartAccountKeyring(cfg *config.Config, rpcClients map[string]*ethclient.Client, base wallet.Keyring) (wallet.Keyring, error) {
+	gasTokens := make(map[string]common.Address, len(cfg.SmartAccount.Bundlers))
+	for chain := range cfg.SmartAccount.Bundlers {
+		token, ok := thorchain.FundingTokens.Lookup(chain, cfg.SmartAccount.PaymasterToken)
+		if !ok || token.Native {
+			return nil, fmt.Errorf("%s has no %s funding token for the paymaster", chain, cfg.SmartAccount.PaymasterToken)
+		}
+		gasTokens[chain] = token.Address
+	}
+
+	factory := aa.SimpleAccountFactoryV07
+	if cfg.SmartAccount.Factory != "" {
+		factory = common.HexToAddress(cfg.SmartAccount.Factory)
+	}
+
+	client, err := aa.NewClient(rpcClients, cfg.SmartAccount.Bundlers, gasTokens, factory)
+	if err != nil {
+		return nil, err
+	}
+
+	for chain := range rpcClients {
+		if _, ok := cfg.SmartAccount.Bundlers[chain]; !ok && swaps.ChainEnabled(chain) {
+			swaps.SetChainEnabled(chain, false)
+			log.Printf("%s disabled: no smart account bundler", chain)
+		}
+	}
+	log.Printf("Smart accounts enabled on %d chain(s), gas paid in %s", len(gasTokens), cfg.SmartAccount.PaymasterToken)
+
+	return aa.NewKeyring(base, client), nil
+}
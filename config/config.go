@@ -3,7 +3,16 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type ProviderConfig struct {
@@ -11,6 +20,524 @@ type ProviderConfig struct {
 	APISecret string `json:"api_secret"`
 }
 
+// StreamingConfig holds Thorchain streaming swap parameters.
+type StreamingConfig struct {
+	// Blocks between sub-swaps (default 3, ~18s per sub-swap)
+	Interval int64 `json:"interval"`
+
+	// Number of sub-swaps; 0 lets Thorchain choose the optimal count
+	Quantity int64 `json:"quantity"`
+}
+
+// RPCURLs are a chain's RPC endpoints, in order of preference. In JSON
+// they are a URL or a list of URLs.
+type RPCURLs []string
+
+func (u *RPCURLs) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*u = RPCURLs{one}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("rpc endpoints must be a URL or a list of URLs")
+	}
+	*u = list
+	return nil
+}
+
+// UnmarshalText reads a comma-separated list, for environment overrides.
+func (u *RPCURLs) UnmarshalText(text []byte) error {
+	*u = strings.Split(string(text), ",")
+	return nil
+}
+
+// WebhookConfig is an endpoint that receives topup and gas refill status
+// events as signed JSON POSTs.
+type WebhookConfig struct {
+	URL string `json:"url"`
+
+	// HMAC-SHA256 key for the X-FundBot-Signature header
+	Secret string `json:"secret"`
+
+	// Event types to send (e.g. ["topup.completed", "topup.failed"]); empty
+	// sends every event
+	Events []string `json:"events"`
+}
+
+// APIKeyConfig grants a REST API client access to FundBot as a Telegram
+// user: topups use that user's wallet and notify their DM.
+type APIKeyConfig struct {
+	// Label for logs
+	Name string `json:"name"`
+
+	// Sent as "Authorization: Bearer <key>"
+	Key string `json:"key"`
+
+	// Telegram user ID the key acts as; must be authorized to use the bot
+	UserID int64 `json:"user_id"`
+}
+
+// TrackerConfig sets how often pending topups and gas refills are polled.
+type TrackerConfig struct {
+	// Seconds between polls (default 15)
+	IntervalSeconds int `json:"interval_s"`
+
+	// Slower intervals in seconds for specific providers, e.g.
+	// {"thorchain-streaming": 60}. Gas refill orders use "cowswap".
+	ProviderIntervals map[string]int `json:"provider_intervals_s"`
+
+	// Up to this many seconds added at random to each wait, so several
+	// instances don't hit provider APIs in lockstep (default 0)
+	JitterSeconds int `json:"jitter_s"`
+
+	// Longest a topup is backed off after repeated status check errors
+	// (default 1800)
+	MaxBackoffSeconds int `json:"max_backoff_s"`
+
+	// Consecutive status check errors for one topup before the admin is
+	// alerted (default 10)
+	AlertAfterErrors int `json:"alert_after_errors"`
+
+	// Minutes a topup may stay pending before it is marked stalled and the
+	// chat and admin are told (default 60)
+	StallAfterMinutes int `json:"stall_after_min"`
+
+	// Per-provider overrides of stall_after_min, e.g. {"thorchain-streaming": 180}
+	ProviderStallMinutes map[string]int `json:"provider_stall_after_min"`
+}
+
+// FundingTokenConfig names a stablecoin that can fund swaps on a chain.
+// Address and decimals may be omitted for tokens in the built-in catalog
+// (USDC, USDT, DAI on supported chains).
+type FundingTokenConfig struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+}
+
+// PermitDomainConfig overrides the EIP-712 domain of a chain's USDC permit,
+// used for CoW gas refills.
+type PermitDomainConfig struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// RemoteSignerConfig points at a JSON-RPC signer (web3signer, clef, or an
+// HSM gateway) that holds the wallet keys instead of a local mnemonic.
+type RemoteSignerConfig struct {
+	URL string `json:"url"`
+
+	// Addresses managed by the signer; addresses[i] is wallet index i, so
+	// single mode needs one and multi mode one per user or group
+	Addresses []string `json:"addresses"`
+}
+
+// BackupConfig snapshots the SQLite database on a schedule.
+type BackupConfig struct {
+	// Directory the snapshots are written to
+	Dir string `json:"dir"`
+
+	// Minutes between snapshots (default 360)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Snapshots to keep in dir, and in S3 if set (default 7)
+	Keep int `json:"keep"`
+
+	// Also upload each snapshot to this bucket
+	S3 *BackupS3Config `json:"s3"`
+}
+
+// BackupS3Config is an S3 bucket backups are copied to. Credentials come
+// from the standard AWS environment.
+type BackupS3Config struct {
+	Bucket string `json:"bucket"`
+
+	// Key prefix, e.g. "fundbot/"
+	Prefix string `json:"prefix"`
+
+	// AWS region of the bucket; empty uses the environment's default region
+	Region string `json:"region"`
+
+	// URL of an S3-compatible service to use instead of AWS, addressed
+	// path-style (e.g. "https://minio.example.com")
+	Endpoint string `json:"endpoint"`
+}
+
+// KMSKeyConfig is an AWS KMS key (spec ECC_SECG_P256K1) that signs for one
+// wallet index. Credentials come from the standard AWS environment.
+type KMSKeyConfig struct {
+	KeyID string `json:"key_id"`
+
+	// AWS region of the key; empty uses the environment's default region
+	Region string `json:"region"`
+}
+
+// LedgerConfig enables signing with a Ledger connected over USB.
+type LedgerConfig struct {
+	// Seconds to wait for each on-device confirmation (default 120)
+	ConfirmTimeout int `json:"confirm_timeout_s"`
+}
+
+// SmartAccountConfig runs every wallet as an ERC-4337 smart account
+// (SimpleAccount v0.7) owned by its derived key, paying gas in a funding
+// token through an ERC-20 paymaster instead of native coins.
+type SmartAccountConfig struct {
+	// Bundler RPC URL per chain; it must also serve the ERC-7677 pm_ methods
+	// (e.g. Pimlico). Chains without a bundler are disabled.
+	Bundlers map[string]string `json:"bundlers"`
+
+	// Funding token symbol the paymaster charges (default "USDC")
+	PaymasterToken string `json:"paymaster_token"`
+
+	// Account factory (default SimpleAccountFactory v0.7)
+	Factory string `json:"factory"`
+}
+
+// WalletPoolConfig is an extra mnemonic that wallets can be assigned to,
+// alongside the default pool from the main wallet config.
+type WalletPoolConfig struct {
+	Mnemonic string `json:"mnemonic"`
+}
+
+// GasRefillConfig sets when /balance tops up a wallet's native gas on a
+// chain by selling USDC through CoW.
+type GasRefillConfig struct {
+	// Native balance in wei below which a refill triggers, e.g.
+	// "400000000000000" for 0.0004 ETH; "0" turns automatic refills off.
+	// Defaults to about $1 of gas on chains with a built-in threshold.
+	MinNativeWei string `json:"min_native_wei"`
+
+	// Native balance in wei a refill tops up to, above min_native_wei. When
+	// set, refills buy exactly the shortfall and refill_usd is the most USDC
+	// they sell; otherwise they sell refill_usd.
+	TargetNativeWei string `json:"target_native_wei"`
+
+	// USDC sold per refill (default 5)
+	RefillUSD float64 `json:"refill_usd"`
+
+	// Refills triggered per wallet on the chain in any 24 hours (default
+	// 4). Resubmissions of an expired order don't count.
+	MaxPerDay int `json:"max_per_day"`
+
+	// Minutes a wallet waits on the chain after a refill is attempted
+	// before /balance or the gas monitor places another (default 30)
+	MinIntervalMinutes int `json:"min_interval_minutes"`
+
+	// Slippage from the CoW quote signed into refill orders, in basis
+	// points (default 100)
+	SlippageBps int `json:"slippage_bps"`
+
+	// Most the quote's network fee may be, in basis points of the USDC
+	// sold; 0 is no limit
+	MaxFeeBps int `json:"max_fee_bps"`
+
+	// Most the quote's price may be above the native token's Thorchain
+	// pool price, in basis points; 0 is no limit
+	MaxDeviationBps int `json:"max_deviation_bps"`
+}
+
+// MinNative returns min_native_wei as a number.
+func (g GasRefillConfig) MinNative() *big.Int {
+	n, _ := new(big.Int).SetString(g.MinNativeWei, 10)
+	return n
+}
+
+// TargetNative returns target_native_wei as a number, or nil if refills
+// sell a fixed amount.
+func (g GasRefillConfig) TargetNative() *big.Int {
+	if g.TargetNativeWei == "" {
+		return nil
+	}
+	n, _ := new(big.Int).SetString(g.TargetNativeWei, 10)
+	return n
+}
+
+// WatchTokenConfig is a token_watchlist entry.
+type WatchTokenConfig struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+
+	// Counted at $1 in balance totals; other tokens are shown without a
+	// USD value
+	Stable bool `json:"stable"`
+}
+
+// AffiliateConfig attributes swap volume to the operator, and takes a fee,
+// where a provider supports it.
+type AffiliateConfig struct {
+	// THORChain affiliate, added to quotes (and so to the memos deposited)
+	Thorchain *ThorchainAffiliateConfig `json:"thorchain"`
+
+	// Extra parameters sent with SimpleSwap and Houdini quotes and
+	// exchanges, e.g. a partner or referral ID. Parameters the client
+	// sets itself take precedence.
+	SimpleSwap map[string]string `json:"simpleswap"`
+	Houdini    map[string]string `json:"houdini"`
+
+	// CoW appData for gas refill and sweep orders
+	CowSwap *CowAffiliateConfig `json:"cowswap"`
+}
+
+// ThorchainAffiliateConfig is a THORChain affiliate and its fee.
+type ThorchainAffiliateConfig struct {
+	// THORName or THORChain address credited with the fee
+	Name string `json:"name"`
+
+	// Fee in basis points of the swap, 0 to 1000
+	Bps int `json:"bps"`
+}
+
+// CowAffiliateConfig identifies CoW orders and sets a partner fee.
+type CowAffiliateConfig struct {
+	// appCode recorded in each order's appData
+	AppCode string `json:"app_code"`
+
+	// Partner fee in basis points of the order, 0 to 100, paid to recipient
+	Bps       int    `json:"bps"`
+	Recipient string `json:"recipient"`
+}
+
+// SweepConfig moves USDC above a ceiling out of the derived wallets and into
+// a treasury address.
+type SweepConfig struct {
+	// Address that receives swept funds
+	Treasury string `json:"treasury"`
+
+	// USDC to leave in each wallet per chain; anything above it is swept
+	Ceiling float64 `json:"ceiling"`
+
+	// Skip sweeps smaller than this many USDC (default 10)
+	MinAmount float64 `json:"min_amount"`
+
+	// "transfer" (default) sends the USDC directly and needs native gas in
+	// the wallet; "cow" sells it through a CoW order paid to the treasury,
+	// using a permit so no gas is needed. CoW sweeps take the chain's
+	// gas_refills guards and are skipped on chains without fee caps.
+	Method string `json:"method"`
+
+	// Token address the cow method buys on each chain; chains not listed
+	// buy the native token
+	BuyTokens map[string]string `json:"buy_tokens"`
+
+	// Minutes between balance checks (default 60)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Hours to wait after sweeping a wallet on a chain before sweeping it
+	// there again (default 24)
+	CooldownHours int `json:"cooldown_hours"`
+}
+
+// GasMonitorConfig has every wallet in use checked for low gas in the
+// background, refilling it as /balance would without waiting for someone
+// to run it.
+type GasMonitorConfig struct {
+	// Minutes between checks (default 30)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Minutes to wait after a refill is attempted for a wallet on a chain,
+	// by the monitor or anything else, before the monitor tries there
+	// again (default 60)
+	CooldownMinutes int `json:"cooldown_minutes"`
+}
+
+// GasTreasuryConfig has one of the bot's own wallets send native gas to
+// wallets that run low on gas without enough USDC for a CoW refill.
+type GasTreasuryConfig struct {
+	// Wallet index that pays; pick one that is never assigned to a user,
+	// such as 1000000, and keep it funded
+	Index uint32 `json:"index"`
+
+	// Wei sent per top-up by chain, e.g. {"base": "300000000000000"};
+	// chains not listed aren't topped up
+	AmountWei map[string]string `json:"amount_wei"`
+
+	// Top-ups per wallet on a chain in any 24 hours (default 1)
+	MaxPerDay int `json:"max_per_day"`
+
+	// Top-ups across all wallets on a chain in any 24 hours (default 20)
+	MaxTotalPerDay int `json:"max_total_per_day"`
+}
+
+// Amount returns the wei sent per top-up on chain, or nil if the chain
+// isn't topped up.
+func (g *GasTreasuryConfig) Amount(chain string) *big.Int {
+	n, ok := new(big.Int).SetString(g.AmountWei[chain], 10)
+	if !ok || n.Sign() <= 0 {
+		return nil
+	}
+	return n
+}
+
+// ApprovalConfig holds large topups and gas refills until the admin
+// approves them, on Telegram or in the admin panel.
+type ApprovalConfig struct {
+	// Topups and gas refills of more than this many USD wait for approval
+	ThresholdUSD float64 `json:"threshold_usd"`
+
+	// Hours a request waits for a decision before it expires (default 24)
+	ExpiryHours int `json:"expiry_hours"`
+}
+
+// BalanceAlertConfig tells the admin on Telegram when the wallets run short
+// of funds, and once a day lists the wallets that need funding.
+type BalanceAlertConfig struct {
+	// Minutes between checks (default 30)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Alert when the USDC held across all wallets and chains falls below
+	// this many dollars; zero turns the check off
+	MinTotalUSDC float64 `json:"min_total_usdc"`
+
+	// Alert when a wallet's native balance falls below this many wei, by
+	// chain, e.g. {"base": "100000000000000"}
+	MinNativeWei map[string]string `json:"min_native_wei"`
+
+	// UTC hour (0-23) after which the daily funding digest is sent
+	DigestHour int `json:"digest_hour"`
+}
+
+// MinNative returns the native balance below which a wallet on chain is
+// reported, or nil if the chain isn't checked.
+func (b *BalanceAlertConfig) MinNative(chain string) *big.Int {
+	n, ok := new(big.Int).SetString(b.MinNativeWei[chain], 10)
+	if !ok || n.Sign() <= 0 {
+		return nil
+	}
+	return n
+}
+
+// DeliveryCheckConfig has the tracker read a completed topup's destination
+// transaction to confirm what arrived. EVM chains are read over
+// rpc_endpoints; UTXO chains need an Esplora API.
+type DeliveryCheckConfig struct {
+	// Esplora API base URLs by asset chain prefix (default mempool.space
+	// for BTC and litecoinspace.org for LTC)
+	UTXOAPIs map[string]string `json:"utxo_apis"`
+
+	// Seconds each check may take (default 15)
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// defaultUTXOAPIs are the Esplora APIs delivery_check uses unless
+// utxo_apis is set.
+var defaultUTXOAPIs = map[string]string{
+	"BTC": "https://mempool.space/api",
+	"LTC": "https://litecoinspace.org/api",
+}
+
+// TokenScreeningConfig checks tokens the resolver finds on CoinGecko before
+// they are offered for a topup, so scam tickers aren't bought by mistake.
+type TokenScreeningConfig struct {
+	// CoinGecko market cap in USD a token needs; 0 skips the check
+	MinMarketCapUSD float64 `json:"min_market_cap_usd"`
+
+	// 24 hour CoinGecko trading volume in USD a token needs; 0 skips the
+	// check
+	MinVolumeUSD float64 `json:"min_volume_usd"`
+
+	// Ask a honeypot API whether EVM tokens can be sold
+	HoneypotCheck bool `json:"honeypot_check"`
+
+	// Honeypot.is compatible endpoint (default
+	// https://api.honeypot.is/v2/IsHoneypot)
+	HoneypotURL string `json:"honeypot_url"`
+
+	// Refuse tokens that fail a check instead of letting the user confirm
+	// them anyway
+	Block bool `json:"block"`
+}
+
+// ReconcileConfig schedules reconciliation of the wallet ledger with
+// on-chain balances.
+type ReconcileConfig struct {
+	// Minutes between reconciliations (default 60)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Blocks per log query when looking for deposits (default 2000); lower it
+	// for RPCs that limit eth_getLogs ranges
+	LogBlockRange int `json:"log_block_range"`
+}
+
+// BalanceHistoryConfig schedules snapshots of every wallet's balances into
+// balances_history, for the admin panel's balance trend chart.
+type BalanceHistoryConfig struct {
+	// Minutes between snapshots (default 60)
+	IntervalMinutes int `json:"interval_minutes"`
+
+	// Delete snapshots older than this many days; zero keeps everything
+	RetentionDays int `json:"retention_days"`
+}
+
+// KeyExportConfig guards private key export from the admin panel.
+type KeyExportConfig struct {
+	// Export attempts, including wrong passwords, allowed per admin account
+	// per hour (default 3)
+	MaxPerHour int `json:"max_per_hour"`
+
+	// DM admin_user_id on Telegram whenever a key is exported
+	NotifyAdmin bool `json:"notify_admin"`
+}
+
+// APILogConfig bounds the api_requests table, which keeps the full request
+// and response of every provider call. Zero keeps everything.
+type APILogConfig struct {
+	// Delete logged calls older than this many days
+	RetentionDays int `json:"retention_days"`
+
+	// Keep at most this many logged calls, deleting the oldest
+	MaxRows int64 `json:"max_rows"`
+
+	// Secrets to mask in logged calls, besides the built-in ones
+	Redact APILogRedactConfig `json:"redact"`
+}
+
+// APILogRedactConfig names what apilog masks before storing a call, on top
+// of defaults covering the providers' auth headers, key parameters and
+// signatures. Names are matched ignoring case.
+type APILogRedactConfig struct {
+	// Request and response headers
+	Headers []string `json:"headers"`
+
+	// URL query parameters
+	QueryParams []string `json:"query_params"`
+
+	// JSON object fields in request and response bodies, at any depth
+	JSONFields []string `json:"json_fields"`
+}
+
+// TLSConfig serves the dashboard, admin panel and API over HTTPS, from
+// certificate files or with certificates obtained from Let's Encrypt. Set
+// either cert_file and key_file or autocert_domains.
+type TLSConfig struct {
+	// PEM certificate chain and private key
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// Hostnames to obtain certificates for; requests for other names are
+	// refused. Let's Encrypt validates on port 443, or on port 80 when
+	// http_port is 80.
+	AutocertDomains []string `json:"autocert_domains"`
+
+	// Contact address for Let's Encrypt expiry and policy notices
+	AutocertEmail string `json:"autocert_email"`
+
+	// Directory caching issued certificates and the account key (default
+	// "autocert" next to the database)
+	AutocertCacheDir string `json:"autocert_cache_dir"`
+
+	// Plain HTTP port redirecting to HTTPS and answering Let's Encrypt
+	// challenges; 0 disables it
+	HTTPPort int `json:"http_port"`
+}
+
+// Autocert reports whether certificates come from Let's Encrypt.
+func (t *TLSConfig) Autocert() bool {
+	return len(t.AutocertDomains) > 0
+}
+
 type Mode string
 
 const (
@@ -25,20 +552,80 @@ type Config struct {
 	// Operating mode: "single" or "multi"
 	Mode Mode `json:"mode"`
 
-	// BIP39 mnemonic for wallet derivation
+	// BIP39 mnemonic for wallet derivation. Leave empty when using
+	// mnemonic_keystore; it is filled in once the keystore is unlocked.
 	Mnemonic string `json:"mnemonic"`
 
+	// Path to an encrypted mnemonic keystore (created with -encrypt-mnemonic).
+	// Unlocked at startup from FUNDBOT_KEYSTORE_PASSPHRASE, a terminal prompt,
+	// or POST /api/admin/unlock.
+	MnemonicKeystore string `json:"mnemonic_keystore"`
+
+	// Remote JSON-RPC signer used instead of a mnemonic
+	RemoteSigner *RemoteSignerConfig `json:"remote_signer"`
+
+	// Account-level extended public key (xpub of m/44'/60'/0') for a
+	// watch-only instance: addresses and balances work, but /topup replies
+	// with the unsigned transaction instead of sending it
+	XPub string `json:"xpub"`
+
+	// Sign with a connected Ledger instead of a mnemonic (single mode only)
+	Ledger *LedgerConfig `json:"ledger"`
+
+	// AWS KMS keys by wallet index (e.g. {"0": {"key_id": "..."}}). These
+	// indexes sign via KMS; others use the mnemonic or remote signer, which
+	// may be omitted entirely if every index in use has a KMS key.
+	KMSKeys map[uint32]KMSKeyConfig `json:"kms_keys"`
+
+	// Extra mnemonics by pool name (multi mode only), e.g. {"reserve":
+	// {"mnemonic": "..."}}. The main wallet config is the "default" pool.
+	WalletPools map[string]WalletPoolConfig `json:"wallet_pools"`
+
+	// Pool for new wallets by Telegram chat ID (a user's ID for DMs), e.g.
+	// {"-1001234567890": "reserve"}. Only applies when a wallet is first
+	// assigned; use -move-assignment to change an existing one.
+	PoolChats map[int64]string `json:"pool_chats"`
+
+	// Pool for new wallets not matched by pool_chats (default "default")
+	DefaultPool string `json:"default_pool"`
+
+	// Use ERC-4337 smart accounts with an ERC-20 paymaster instead of EOAs
+	SmartAccount *SmartAccountConfig `json:"smart_account"`
+
+	// Fund each topup from a freshly derived address instead of the wallet's
+	// stable one, so providers see (and refund to) a new address every time.
+	// The mapping is kept in the fresh_addresses table.
+	FreshAddresses bool `json:"fresh_addresses"`
+
 	// Admin telegram user ID - can approve users in single mode
 	AdminUserID int64 `json:"admin_user_id"`
 
-	// Whitelisted telegram user IDs (single mode only)
+	// Whitelisted telegram user IDs (single mode only). Users added or
+	// removed from the admin panel are kept in the database and applied on
+	// top of this list.
 	WhitelistedUsers []int64 `json:"whitelisted_users"`
 
 	// Path to SQLite database (multi mode only)
 	DatabasePath string `json:"database_path"`
 
-	// RPC endpoints for supported chains
-	RPCEndpoints map[string]string `json:"rpc_endpoints"`
+	// Encrypt the SQLite database with SQLCipher. The key is read at
+	// startup from FUNDBOT_DATABASE_KEY or a terminal prompt. Needs a binary
+	// built with -tags libsqlite3 against SQLCipher
+	DatabaseEncrypted bool `json:"database_encrypted"`
+
+	// PostgreSQL URL to use instead of database_path, e.g.
+	// "postgres://fundbot:secret@db:5432/fundbot?sslmode=require", so
+	// several instances can share one database
+	DatabaseURL string `json:"database_url"`
+
+	// RPC endpoints for supported chains: a URL, or a list of http(s) URLs
+	// in order of preference that are failed over between
+	RPCEndpoints map[string]RPCURLs `json:"rpc_endpoints"`
+
+	// Chains from rpc_endpoints to start disabled (e.g. while an RPC is broken).
+	// Disabled chains are skipped for quotes, balances, tracking and gas refills;
+	// the admin panel can switch them back on at runtime.
+	DisabledChains []string `json:"disabled_chains"`
 
 	// Explorer base URLs per chain (e.g. {"base": "https://basescan.org"})
 	// Defaults provided for known chains if not set.
@@ -47,17 +634,130 @@ type Config struct {
 	// Provider-specific configuration (e.g. API keys)
 	Providers map[string]ProviderConfig `json:"providers"`
 
+	// Stablecoins accepted to fund swaps per chain, in preference order
+	// (e.g. {"arbitrum": [{"symbol": "USDC"}, {"symbol": "USDT"}]}).
+	// Chains not listed keep their default (USDC, or USDT on BSC).
+	FundingTokens map[string][]FundingTokenConfig `json:"funding_tokens"`
+
+	// USDC permit domain overrides per chain for CoW gas refills
+	// (e.g. {"gnosis": {"name": "Bridged USDC (Gnosis)", "version": "2"}}).
+	// Defaults to "USD Coin"/"2" except where a chain is known to differ.
+	CowPermitDomains map[string]PermitDomainConfig `json:"cow_permit_domains"`
+
+	// Gas refill thresholds by chain, e.g. {"base": {"min_native_wei":
+	// "1000000000000000", "max_per_day": 2}}. Chains listed here or in the
+	// built-in thresholds refill automatically.
+	GasRefills map[string]GasRefillConfig `json:"gas_refills"`
+
+	// Affiliate and partner fee settings per provider
+	Affiliates AffiliateConfig `json:"affiliates"`
+
+	// Sweep USDC above a ceiling from the wallets into a treasury address
+	Sweep *SweepConfig `json:"sweep"`
+
+	// Refill low gas on every wallet in the background
+	GasMonitor *GasMonitorConfig `json:"gas_monitor"`
+
+	// Send gas from a wallet of the bot's when a wallet can't pay for a refill
+	GasTreasury *GasTreasuryConfig `json:"gas_treasury"`
+
+	// Hold topups and gas refills above a USD amount for admin approval
+	Approvals *ApprovalConfig `json:"approvals"`
+
+	// Tell the admin when wallets run low, with a daily digest
+	BalanceAlerts *BalanceAlertConfig `json:"balance_alerts"`
+
+	// Confirm on the destination chain that completed topups arrived
+	DeliveryCheck *DeliveryCheckConfig `json:"delivery_check"`
+
+	// Scheduled snapshots of the SQLite database
+	Backup *BackupConfig `json:"backup"`
+
+	// Reconcile the wallet ledger with on-chain balances
+	Reconcile *ReconcileConfig `json:"reconcile"`
+
+	// Record wallet balances periodically for trend charts
+	BalanceHistory *BalanceHistoryConfig `json:"balance_history"`
+
+	// Thorchain streaming swap parameters for the "stream" routing hint
+	ThorchainStreaming StreamingConfig `json:"thorchain_streaming"`
+
+	// Polling intervals for the status tracker
+	Tracker TrackerConfig `json:"tracker"`
+
+	// Endpoints notified when a topup or gas refill changes status
+	Webhooks []WebhookConfig `json:"webhooks"`
+
+	// Keys for the /api/v1 REST API; empty disables it
+	APIKeys []APIKeyConfig `json:"api_keys"`
+
 	// CoinGecko API key for dynamic token resolution
 	CoinGeckoAPIKey string `json:"coingecko_api_key"`
 
-	// HTTP server port (default 8080)
+	// More CoinGecko API keys, rotated with coingecko_api_key so one key's
+	// rate limit doesn't stall resolution
+	CoinGeckoAPIKeys []string `json:"coingecko_api_keys"`
+
+	// The CoinGecko keys are Pro keys, used with the Pro API
+	CoinGeckoPro bool `json:"coingecko_pro"`
+
+	// Safety checks on resolved tokens before they are offered
+	TokenScreening *TokenScreeningConfig `json:"token_screening"`
+
+	// HTTP server port (default 8080, or 443 with tls)
 	Port int `json:"port"`
 
+	// Serve HTTPS directly instead of plain HTTP
+	TLS *TLSConfig `json:"tls"`
+
+	// URL prefix the web server is reached under behind a reverse proxy,
+	// e.g. "/fundbot"; empty serves from the root
+	BasePath string `json:"base_path"`
+
+	// Reverse proxies, as IPs or CIDRs, whose X-Forwarded-For and
+	// X-Forwarded-Proto headers are trusted
+	TrustedProxies []string `json:"trusted_proxies"`
+
 	// Optional password to protect the dashboard; empty = public
 	DashboardPassword string `json:"dashboard_password"`
 
-	// Required password to protect the admin panel
+	// Let users sign in at /me with the Telegram Login Widget to see their
+	// own wallet, balances and topups. Link the site's domain to the bot
+	// with BotFather's /setdomain first.
+	UserDashboard bool `json:"user_dashboard"`
+
+	// Password of the superadmin account "admin", created on startup while
+	// there are no admin accounts. Later changes have no effect; manage
+	// accounts from the admin panel or with -add-admin.
 	AdminPassword string `json:"admin_password"`
+
+	// Limits and notifications for private key export
+	KeyExport KeyExportConfig `json:"key_export"`
+
+	// Minutes between background refreshes of the admin panel's wallet
+	// balances (default 5)
+	BalanceRefreshMinutes int `json:"balance_refresh_minutes"`
+
+	// ERC20s shown beside native coins and funding tokens in /balance and
+	// the balance views, by chain, e.g. {"base": [{"symbol": "WETH",
+	// "address": "0x4200000000000000000000000000000000000006",
+	// "decimals": 18}]}
+	TokenWatchlist map[string][]WatchTokenConfig `json:"token_watchlist"`
+
+	// Retention of logged provider API calls
+	APILog APILogConfig `json:"api_log"`
+
+	// Whitelist changes from the admin panel, by user ID: true adds the
+	// user, false removes a configured one
+	whitelistMu      sync.RWMutex
+	whitelistChanges map[int64]bool
+
+	// trusted_proxies, parsed
+	trustedProxies []netip.Prefix
+
+	// File Reload reads, and a lock on the other keys it replaces
+	path     string
+	reloadMu sync.RWMutex
 }
 
 func Load(path string) (*Config, error) {
@@ -70,10 +770,14 @@ func Load(path string) (*Config, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
+	if err := cfg.applyEnv(os.Environ()); err != nil {
+		return nil, fmt.Errorf("applying environment: %w", err)
+	}
 
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
+	cfg.path = path
 
 	return &cfg, nil
 }
@@ -82,8 +786,30 @@ func (c *Config) validate() error {
 	if c.TelegramToken == "" {
 		return fmt.Errorf("telegram_token is required")
 	}
-	if c.Mnemonic == "" {
-		return fmt.Errorf("mnemonic is required")
+	walletSources := 0
+	for _, set := range []bool{c.Mnemonic != "", c.MnemonicKeystore != "", c.RemoteSigner != nil, c.Ledger != nil, c.XPub != ""} {
+		if set {
+			walletSources++
+		}
+	}
+	if walletSources == 0 && len(c.KMSKeys) == 0 {
+		return fmt.Errorf("one of mnemonic, mnemonic_keystore, remote_signer, ledger, xpub or kms_keys is required")
+	}
+	if walletSources > 1 {
+		return fmt.Errorf("set only one of mnemonic, mnemonic_keystore, remote_signer, ledger and xpub")
+	}
+	if c.RemoteSigner != nil {
+		if c.RemoteSigner.URL == "" {
+			return fmt.Errorf("remote_signer url is required")
+		}
+		if len(c.RemoteSigner.Addresses) == 0 {
+			return fmt.Errorf("remote_signer must list at least one address")
+		}
+		for _, addr := range c.RemoteSigner.Addresses {
+			if !common.IsHexAddress(addr) {
+				return fmt.Errorf("remote_signer: invalid address %q", addr)
+			}
+		}
 	}
 	if c.Mode != ModeSingle && c.Mode != ModeMulti {
 		return fmt.Errorf("mode must be 'single' or 'multi'")
@@ -91,14 +817,490 @@ func (c *Config) validate() error {
 	if c.AdminUserID == 0 {
 		return fmt.Errorf("admin_user_id is required")
 	}
-	if c.DatabasePath == "" {
-		return fmt.Errorf("database_path is required")
+	if c.DatabasePath == "" && c.DatabaseURL == "" {
+		return fmt.Errorf("database_path or database_url is required")
+	}
+	if c.DatabaseURL != "" && !strings.HasPrefix(c.DatabaseURL, "postgres://") && !strings.HasPrefix(c.DatabaseURL, "postgresql://") {
+		return fmt.Errorf("database_url must be a postgres:// URL")
 	}
-	if c.AdminPassword == "" {
-		return fmt.Errorf("admin_password is required")
+	if c.DatabaseEncrypted && c.DatabaseURL != "" {
+		return fmt.Errorf("database_encrypted applies to SQLite only; encrypt PostgreSQL storage with its own tools")
 	}
 	if c.Port == 0 {
 		c.Port = 8080
+		if c.TLS != nil {
+			c.Port = 443
+		}
+	}
+	if c.KeyExport.MaxPerHour < 0 {
+		return fmt.Errorf("key_export max_per_hour must not be negative")
+	}
+	if c.KeyExport.MaxPerHour == 0 {
+		c.KeyExport.MaxPerHour = 3
+	}
+	if c.BalanceRefreshMinutes < 0 {
+		return fmt.Errorf("balance_refresh_minutes must not be negative")
+	}
+	if c.BalanceRefreshMinutes == 0 {
+		c.BalanceRefreshMinutes = 5
+	}
+	for chain, tokens := range c.TokenWatchlist {
+		if _, ok := c.RPCEndpoints[chain]; !ok {
+			return fmt.Errorf("token_watchlist: %s has no rpc_endpoints entry", chain)
+		}
+		seen := make(map[common.Address]bool)
+		for _, t := range tokens {
+			if t.Symbol == "" || !common.IsHexAddress(t.Address) {
+				return fmt.Errorf("token_watchlist %s: every token needs a symbol and a valid address", chain)
+			}
+			if t.Decimals < 0 || t.Decimals > 36 {
+				return fmt.Errorf("token_watchlist %s: %s decimals must be between 0 and 36", chain, t.Symbol)
+			}
+			addr := common.HexToAddress(t.Address)
+			if seen[addr] {
+				return fmt.Errorf("token_watchlist %s: %s is listed twice", chain, t.Address)
+			}
+			seen[addr] = true
+		}
+	}
+	if c.BasePath != "" {
+		c.BasePath = "/" + strings.Trim(c.BasePath, "/")
+		if strings.ContainsAny(c.BasePath, "?#\"'<> ") {
+			return fmt.Errorf("base_path must be a plain URL path")
+		}
+		if c.BasePath == "/" {
+			c.BasePath = ""
+		}
+	}
+	c.trustedProxies = nil
+	for _, p := range c.TrustedProxies {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(p)
+			if addrErr != nil {
+				return fmt.Errorf("trusted_proxies: %q is not an IP or CIDR", p)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		c.trustedProxies = append(c.trustedProxies, prefix.Masked())
+	}
+	if c.Backup != nil {
+		if c.DatabaseURL != "" {
+			return fmt.Errorf("backup only snapshots SQLite; back up PostgreSQL with its own tools")
+		}
+		if c.Backup.Dir == "" {
+			return fmt.Errorf("backup dir is required")
+		}
+		if c.Backup.IntervalMinutes < 0 || c.Backup.Keep < 0 {
+			return fmt.Errorf("backup interval_minutes and keep must not be negative")
+		}
+		if c.Backup.IntervalMinutes == 0 {
+			c.Backup.IntervalMinutes = 360
+		}
+		if c.Backup.Keep == 0 {
+			c.Backup.Keep = 7
+		}
+		if c.Backup.S3 != nil && c.Backup.S3.Bucket == "" {
+			return fmt.Errorf("backup s3 bucket is required")
+		}
+	}
+	if c.Reconcile != nil {
+		if c.Reconcile.IntervalMinutes < 0 || c.Reconcile.LogBlockRange < 0 {
+			return fmt.Errorf("reconcile interval_minutes and log_block_range must not be negative")
+		}
+		if c.Reconcile.IntervalMinutes == 0 {
+			c.Reconcile.IntervalMinutes = 60
+		}
+		if c.Reconcile.LogBlockRange == 0 {
+			c.Reconcile.LogBlockRange = 2000
+		}
+	}
+	if c.BalanceHistory != nil {
+		if c.BalanceHistory.IntervalMinutes < 0 || c.BalanceHistory.RetentionDays < 0 {
+			return fmt.Errorf("balance_history interval_minutes and retention_days must not be negative")
+		}
+		if c.BalanceHistory.IntervalMinutes == 0 {
+			c.BalanceHistory.IntervalMinutes = 60
+		}
+	}
+	if c.APILog.RetentionDays < 0 || c.APILog.MaxRows < 0 {
+		return fmt.Errorf("api_log retention_days and max_rows must not be negative")
+	}
+	if c.TLS != nil {
+		hasFiles := c.TLS.CertFile != "" || c.TLS.KeyFile != ""
+		if hasFiles == c.TLS.Autocert() {
+			return fmt.Errorf("tls: set either cert_file and key_file or autocert_domains")
+		}
+		if hasFiles && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+			return fmt.Errorf("tls: cert_file and key_file must be set together")
+		}
+		for _, domain := range c.TLS.AutocertDomains {
+			if domain == "" || strings.ContainsAny(domain, ":/* ") {
+				return fmt.Errorf("tls autocert_domains: %q is not a hostname", domain)
+			}
+		}
+		if c.TLS.Autocert() && c.TLS.AutocertCacheDir == "" {
+			c.TLS.AutocertCacheDir = filepath.Join(filepath.Dir(c.DatabasePath), "autocert")
+		}
+		if c.TLS.HTTPPort < 0 || c.TLS.HTTPPort == c.Port {
+			return fmt.Errorf("tls http_port must differ from port and not be negative")
+		}
+	}
+	if c.ThorchainStreaming.Interval < 0 || c.ThorchainStreaming.Quantity < 0 {
+		return fmt.Errorf("thorchain_streaming interval and quantity must not be negative")
+	}
+	if c.ThorchainStreaming.Interval == 0 {
+		c.ThorchainStreaming.Interval = 3
+	}
+	if c.Tracker.IntervalSeconds < 0 || c.Tracker.JitterSeconds < 0 || c.Tracker.MaxBackoffSeconds < 0 || c.Tracker.AlertAfterErrors < 0 || c.Tracker.StallAfterMinutes < 0 {
+		return fmt.Errorf("tracker interval_s, jitter_s, max_backoff_s, alert_after_errors and stall_after_min must not be negative")
+	}
+	if c.Tracker.IntervalSeconds == 0 {
+		c.Tracker.IntervalSeconds = 15
+	}
+	if c.Tracker.MaxBackoffSeconds == 0 {
+		c.Tracker.MaxBackoffSeconds = 1800
+	}
+	if c.Tracker.AlertAfterErrors == 0 {
+		c.Tracker.AlertAfterErrors = 10
+	}
+	if c.Tracker.StallAfterMinutes == 0 {
+		c.Tracker.StallAfterMinutes = 60
+	}
+	for provider, minutes := range c.Tracker.ProviderStallMinutes {
+		if minutes <= 0 {
+			return fmt.Errorf("tracker provider_stall_after_min %s: must be positive", provider)
+		}
+	}
+	for provider, interval := range c.Tracker.ProviderIntervals {
+		if interval < c.Tracker.IntervalSeconds {
+			return fmt.Errorf("tracker provider_intervals_s %s: must be at least interval_s (%d)", provider, c.Tracker.IntervalSeconds)
+		}
+	}
+	for i, hook := range c.Webhooks {
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks[%d]: url must be an http(s) URL", i)
+		}
+		if hook.Secret == "" {
+			return fmt.Errorf("webhooks[%d]: secret is required", i)
+		}
+	}
+	apiKeys := make(map[string]bool)
+	for i, k := range c.APIKeys {
+		if len(k.Key) < 32 {
+			return fmt.Errorf("api_keys[%d]: key must be at least 32 characters", i)
+		}
+		if apiKeys[k.Key] {
+			return fmt.Errorf("api_keys[%d]: duplicate key", i)
+		}
+		apiKeys[k.Key] = true
+		if k.UserID == 0 || !c.IsAuthorized(k.UserID) {
+			return fmt.Errorf("api_keys[%d]: user_id %d is not authorized to use the bot", i, k.UserID)
+		}
+	}
+	if c.Ledger != nil {
+		if c.Mode != ModeSingle {
+			return fmt.Errorf("ledger requires single mode")
+		}
+		if c.Ledger.ConfirmTimeout < 0 {
+			return fmt.Errorf("ledger confirm_timeout_s must not be negative")
+		}
+		if c.Ledger.ConfirmTimeout == 0 {
+			c.Ledger.ConfirmTimeout = 120
+		}
+	}
+	if c.Sweep != nil {
+		if !common.IsHexAddress(c.Sweep.Treasury) {
+			return fmt.Errorf("sweep treasury must be an address")
+		}
+		if c.Sweep.Ceiling < 0 || c.Sweep.MinAmount < 0 || c.Sweep.IntervalMinutes < 0 || c.Sweep.CooldownHours < 0 {
+			return fmt.Errorf("sweep ceiling, min_amount, interval_minutes and cooldown_hours must not be negative")
+		}
+		switch c.Sweep.Method {
+		case "":
+			c.Sweep.Method = "transfer"
+		case "transfer", "cow":
+		default:
+			return fmt.Errorf("sweep method must be 'transfer' or 'cow'")
+		}
+		if len(c.Sweep.BuyTokens) > 0 && c.Sweep.Method != "cow" {
+			return fmt.Errorf("sweep buy_tokens only apply to the cow method")
+		}
+		for chain, token := range c.Sweep.BuyTokens {
+			if !common.IsHexAddress(token) {
+				return fmt.Errorf("sweep buy_tokens %s must be an address", chain)
+			}
+		}
+		if c.Sweep.MinAmount == 0 {
+			c.Sweep.MinAmount = 10
+		}
+		if c.Sweep.IntervalMinutes == 0 {
+			c.Sweep.IntervalMinutes = 60
+		}
+		if c.Sweep.CooldownHours == 0 {
+			c.Sweep.CooldownHours = 24
+		}
+	}
+	if m := c.GasMonitor; m != nil {
+		if m.IntervalMinutes < 0 || m.CooldownMinutes < 0 {
+			return fmt.Errorf("gas_monitor interval_minutes and cooldown_minutes must not be negative")
+		}
+		if m.IntervalMinutes == 0 {
+			m.IntervalMinutes = 30
+		}
+		if m.CooldownMinutes == 0 {
+			m.CooldownMinutes = 60
+		}
+	}
+	if t := c.GasTreasury; t != nil {
+		if t.Index == 0 {
+			return fmt.Errorf("gas_treasury index must not be 0, the shared or admin wallet")
+		}
+		if len(t.AmountWei) == 0 {
+			return fmt.Errorf("gas_treasury must list at least one chain in amount_wei")
+		}
+		for chain := range t.AmountWei {
+			if _, ok := c.RPCEndpoints[chain]; !ok {
+				return fmt.Errorf("gas_treasury amount_wei: %s has no rpc_endpoints entry", chain)
+			}
+			if t.Amount(chain) == nil {
+				return fmt.Errorf("gas_treasury amount_wei %s: must be a whole number of wei above 0", chain)
+			}
+		}
+		if t.MaxPerDay < 0 || t.MaxTotalPerDay < 0 {
+			return fmt.Errorf("gas_treasury max_per_day and max_total_per_day must not be negative")
+		}
+		if t.MaxPerDay == 0 {
+			t.MaxPerDay = 1
+		}
+		if t.MaxTotalPerDay == 0 {
+			t.MaxTotalPerDay = 20
+		}
+	}
+	if a := c.Approvals; a != nil {
+		if a.ThresholdUSD <= 0 {
+			return fmt.Errorf("approvals threshold_usd must be above 0")
+		}
+		if a.ExpiryHours < 0 {
+			return fmt.Errorf("approvals expiry_hours must not be negative")
+		}
+		if a.ExpiryHours == 0 {
+			a.ExpiryHours = 24
+		}
+	}
+	if b := c.BalanceAlerts; b != nil {
+		if b.IntervalMinutes < 0 || b.MinTotalUSDC < 0 {
+			return fmt.Errorf("balance_alerts interval_minutes and min_total_usdc must not be negative")
+		}
+		if b.IntervalMinutes == 0 {
+			b.IntervalMinutes = 30
+		}
+		if b.MinTotalUSDC == 0 && len(b.MinNativeWei) == 0 {
+			return fmt.Errorf("balance_alerts needs min_total_usdc or min_native_wei")
+		}
+		for chain := range b.MinNativeWei {
+			if _, ok := c.RPCEndpoints[chain]; !ok {
+				return fmt.Errorf("balance_alerts min_native_wei: %s has no rpc_endpoints entry", chain)
+			}
+			if b.MinNative(chain) == nil {
+				return fmt.Errorf("balance_alerts min_native_wei %s: must be a whole number of wei above 0", chain)
+			}
+		}
+		if b.DigestHour < 0 || b.DigestHour > 23 {
+			return fmt.Errorf("balance_alerts digest_hour must be between 0 and 23")
+		}
+	}
+	if d := c.DeliveryCheck; d != nil {
+		if d.TimeoutSeconds < 0 {
+			return fmt.Errorf("delivery_check timeout_seconds must not be negative")
+		}
+		if d.TimeoutSeconds == 0 {
+			d.TimeoutSeconds = 15
+		}
+		if d.UTXOAPIs == nil {
+			d.UTXOAPIs = defaultUTXOAPIs
+		}
+		// Asset notation chain prefixes are upper case
+		apis := make(map[string]string, len(d.UTXOAPIs))
+		for chain, api := range d.UTXOAPIs {
+			if u, err := url.Parse(api); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("delivery_check utxo_apis %s: %q is not an http(s) URL", chain, api)
+			}
+			apis[strings.ToUpper(chain)] = api
+		}
+		d.UTXOAPIs = apis
+	}
+	if t := c.TokenScreening; t != nil {
+		if len(c.CoinGeckoKeys()) == 0 {
+			return fmt.Errorf("token_screening requires coingecko_api_key")
+		}
+		if t.MinMarketCapUSD < 0 || t.MinVolumeUSD < 0 {
+			return fmt.Errorf("token_screening min_market_cap_usd and min_volume_usd must not be negative")
+		}
+		if t.HoneypotURL == "" {
+			t.HoneypotURL = "https://api.honeypot.is/v2/IsHoneypot"
+		}
+		if u, err := url.Parse(t.HoneypotURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("token_screening honeypot_url %q is not an http(s) URL", t.HoneypotURL)
+		}
+	}
+	if len(c.WalletPools) > 0 {
+		if c.Mode != ModeMulti {
+			return fmt.Errorf("wallet_pools requires multi mode")
+		}
+		if c.XPub != "" {
+			return fmt.Errorf("wallet_pools can't be combined with xpub")
+		}
+	}
+	if c.FreshAddresses && (c.RemoteSigner != nil || c.XPub != "") {
+		return fmt.Errorf("fresh_addresses needs a wallet that can derive new addresses (mnemonic, keystore or ledger)")
+	}
+	if c.SmartAccount != nil {
+		if c.RemoteSigner != nil || c.Ledger != nil || c.XPub != "" {
+			return fmt.Errorf("smart_account needs keys that sign user operation hashes (mnemonic, keystore or kms_keys)")
+		}
+		if c.FreshAddresses {
+			return fmt.Errorf("smart_account can't be combined with fresh_addresses")
+		}
+		if c.Sweep != nil && c.Sweep.Method == "cow" {
+			return fmt.Errorf("smart_account can't sweep via cow; use the transfer method")
+		}
+		if len(c.SmartAccount.Bundlers) == 0 {
+			return fmt.Errorf("smart_account must list at least one bundler")
+		}
+		for chain := range c.SmartAccount.Bundlers {
+			if _, ok := c.RPCEndpoints[chain]; !ok {
+				return fmt.Errorf("smart_account bundlers: %s has no rpc_endpoints entry", chain)
+			}
+		}
+		if c.SmartAccount.Factory != "" && !common.IsHexAddress(c.SmartAccount.Factory) {
+			return fmt.Errorf("smart_account factory must be an address")
+		}
+		if c.SmartAccount.PaymasterToken == "" {
+			c.SmartAccount.PaymasterToken = "USDC"
+		}
+	}
+	for name, pool := range c.WalletPools {
+		if name == "" || name == "default" {
+			return fmt.Errorf("wallet_pools: %q is not a valid pool name", name)
+		}
+		if pool.Mnemonic == "" {
+			return fmt.Errorf("wallet_pools %s: mnemonic is required", name)
+		}
+	}
+	if c.DefaultPool == "" {
+		c.DefaultPool = "default"
+	}
+	if !c.HasPool(c.DefaultPool) {
+		return fmt.Errorf("default_pool: unknown pool %s", c.DefaultPool)
+	}
+	for chatID, pool := range c.PoolChats {
+		if !c.HasPool(pool) {
+			return fmt.Errorf("pool_chats %d: unknown pool %s", chatID, pool)
+		}
+	}
+	for index, key := range c.KMSKeys {
+		if key.KeyID == "" {
+			return fmt.Errorf("kms_keys %d: key_id is required", index)
+		}
+	}
+	for chain, urls := range c.RPCEndpoints {
+		if len(urls) == 0 {
+			return fmt.Errorf("rpc_endpoints %s: at least one URL is required", chain)
+		}
+		for _, raw := range urls {
+			u, err := url.Parse(raw)
+			if err != nil || raw == "" {
+				return fmt.Errorf("rpc_endpoints %s: %q is not a URL", chain, raw)
+			}
+			if len(urls) > 1 && u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("rpc_endpoints %s: only http(s) endpoints can be failed over between", chain)
+			}
+		}
+	}
+	for _, chain := range c.DisabledChains {
+		if _, ok := c.RPCEndpoints[chain]; !ok {
+			return fmt.Errorf("disabled_chains: %s has no rpc_endpoints entry", chain)
+		}
+	}
+	if c.GasRefills == nil {
+		c.GasRefills = make(map[string]GasRefillConfig)
+	}
+	for chain, minWei := range defaultMinNativeWei {
+		if _, ok := c.GasRefills[chain]; !ok {
+			c.GasRefills[chain] = GasRefillConfig{MinNativeWei: minWei}
+		}
+	}
+	for chain, g := range c.GasRefills {
+		if _, ok := defaultMinNativeWei[chain]; !ok {
+			if _, ok := c.RPCEndpoints[chain]; !ok {
+				return fmt.Errorf("gas_refills: %s has no rpc_endpoints entry", chain)
+			}
+		}
+		if g.MinNativeWei == "" {
+			g.MinNativeWei = defaultMinNativeWei[chain]
+		}
+		if g.MinNativeWei == "" {
+			return fmt.Errorf("gas_refills %s: min_native_wei is required", chain)
+		}
+		minWei, ok := new(big.Int).SetString(g.MinNativeWei, 10)
+		if !ok || minWei.Sign() < 0 {
+			return fmt.Errorf("gas_refills %s: min_native_wei must be a whole number of wei", chain)
+		}
+		if g.TargetNativeWei != "" {
+			if n, ok := new(big.Int).SetString(g.TargetNativeWei, 10); !ok || n.Cmp(minWei) <= 0 {
+				return fmt.Errorf("gas_refills %s: target_native_wei must be a whole number of wei above min_native_wei", chain)
+			}
+		}
+		if g.RefillUSD < 0 || g.RefillUSD > 100 || g.MaxPerDay < 0 || g.MinIntervalMinutes < 0 {
+			return fmt.Errorf("gas_refills %s: refill_usd must be between 0 and 100 and max_per_day and min_interval_minutes not negative", chain)
+		}
+		if g.RefillUSD == 0 {
+			g.RefillUSD = 5
+		}
+		if g.MaxPerDay == 0 {
+			g.MaxPerDay = 4
+		}
+		if g.MinIntervalMinutes == 0 {
+			g.MinIntervalMinutes = 30
+		}
+		if g.SlippageBps < 0 || g.SlippageBps > 1000 {
+			return fmt.Errorf("gas_refills %s: slippage_bps must be between 0 and 1000", chain)
+		}
+		if g.MaxFeeBps < 0 || g.MaxFeeBps > 10000 || g.MaxDeviationBps < 0 || g.MaxDeviationBps > 10000 {
+			return fmt.Errorf("gas_refills %s: max_fee_bps and max_deviation_bps must be between 0 and 10000", chain)
+		}
+		if g.SlippageBps == 0 {
+			g.SlippageBps = 100
+		}
+		c.GasRefills[chain] = g
+	}
+	if a := c.Affiliates.Thorchain; a != nil {
+		if a.Name == "" || a.Bps < 0 || a.Bps > 1000 {
+			return fmt.Errorf("affiliates.thorchain needs a name and bps between 0 and 1000")
+		}
+	}
+	if a := c.Affiliates.CowSwap; a != nil {
+		if a.Bps < 0 || a.Bps > 100 {
+			return fmt.Errorf("affiliates.cowswap: bps must be between 0 and 100")
+		}
+		if a.Bps > 0 && !common.IsHexAddress(a.Recipient) {
+			return fmt.Errorf("affiliates.cowswap: recipient must be an address when bps is set")
+		}
+	}
+	for chain, tokens := range c.FundingTokens {
+		if len(tokens) == 0 {
+			return fmt.Errorf("funding_tokens for %s must list at least one token", chain)
+		}
+		for _, t := range tokens {
+			if t.Symbol == "" {
+				return fmt.Errorf("funding_tokens for %s: symbol is required", chain)
+			}
+			if t.Decimals < 0 {
+				return fmt.Errorf("funding_tokens for %s: %s decimals must not be negative", chain, t.Symbol)
+			}
+		}
 	}
 	return nil
 }
@@ -111,17 +1313,41 @@ var defaultExplorers = map[string]string{
 	"polygon":   "https://polygonscan.com",
 	"optimism":  "https://optimistic.etherscan.io",
 	"bsc":       "https://bscscan.com",
+	"gnosis":    "https://gnosisscan.io",
+}
+
+// defaultMinNativeWei are the gas refill thresholds for chains gas_refills
+// doesn't set, about $1 of the native token, and enough on mainnet for an
+// approve and a deposit. Chains without a CoW deployment, or whose funding
+// token lacks an EIP-2612 permit, have none.
+var defaultMinNativeWei = map[string]string{
+	"base":      "400000000000000",     // 0.0004 ETH (~$1 at $2500)
+	"avalanche": "40000000000000000",   // 0.04 AVAX (~$1 at $25)
+	"arbitrum":  "400000000000000",     // 0.0004 ETH (~$1 at $2500)
+	"polygon":   "4000000000000000000", // 4 POL (~$1 at $0.25)
+	"gnosis":    "1000000000000000000", // 1 XDAI (~$1)
+	"ethereum":  "2000000000000000",    // 0.002 ETH (~$5 at $2500)
+}
+
+// GasRefill returns the gas refill settings of a chain. Chains without any
+// don't refill automatically.
+func (c *Config) GasRefill(chain string) (GasRefillConfig, bool) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	g, ok := c.GasRefills[chain]
+	return g, ok
+}
+
+// Affiliate returns the affiliate settings.
+func (c *Config) Affiliate() AffiliateConfig {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	return c.Affiliates
 }
 
 // ExplorerTxURL returns the full explorer URL for a transaction hash on the given chain.
 func (c *Config) ExplorerTxURL(chain, txHash string) string {
-	base := ""
-	if c.Explorers != nil {
-		base = c.Explorers[chain]
-	}
-	if base == "" {
-		base = defaultExplorers[chain]
-	}
+	base := c.ExplorerBaseURL(chain)
 	if base == "" {
 		return txHash
 	}
@@ -130,6 +1356,8 @@ func (c *Config) ExplorerTxURL(chain, txHash string) string {
 
 // ExplorerBaseURL returns the explorer base URL for a chain.
 func (c *Config) ExplorerBaseURL(chain string) string {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
 	if c.Explorers != nil {
 		if u := c.Explorers[chain]; u != "" {
 			return u
@@ -138,6 +1366,55 @@ func (c *Config) ExplorerBaseURL(chain string) string {
 	return defaultExplorers[chain]
 }
 
+// HasPool reports whether name is the default pool or one of wallet_pools.
+func (c *Config) HasPool(name string) bool {
+	if name == "default" {
+		return true
+	}
+	_, ok := c.WalletPools[name]
+	return ok
+}
+
+// PoolFor returns the pool a new wallet for the given Telegram chat goes into.
+func (c *Config) PoolFor(chatID int64) string {
+	if pool, ok := c.PoolChats[chatID]; ok {
+		return pool
+	}
+	return c.DefaultPool
+}
+
+// CoinGeckoKeys returns coingecko_api_key and coingecko_api_keys, without
+// blanks or repeats. None leaves the token resolver off.
+func (c *Config) CoinGeckoKeys() []string {
+	var keys []string
+	for _, k := range append([]string{c.CoinGeckoAPIKey}, c.CoinGeckoAPIKeys...) {
+		if k = strings.TrimSpace(k); k != "" && !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// DatabaseDSN returns what db.Open connects to: database_url if set,
+// otherwise database_path.
+func (c *Config) DatabaseDSN() string {
+	if c.DatabaseURL != "" {
+		return c.DatabaseURL
+	}
+	return c.DatabasePath
+}
+
+// TrustedProxy reports whether addr is one of trusted_proxies.
+func (c *Config) TrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range c.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) IsAuthorized(userID int64) bool {
 	if userID == c.AdminUserID {
 		return true
@@ -145,10 +1422,41 @@ func (c *Config) IsAuthorized(userID int64) bool {
 	if c.Mode == ModeMulti {
 		return true // all users allowed, they get their own wallet
 	}
+	c.whitelistMu.RLock()
+	defer c.whitelistMu.RUnlock()
+	if allowed, ok := c.whitelistChanges[userID]; ok {
+		return allowed
+	}
+	return slices.Contains(c.WhitelistedUsers, userID)
+}
+
+// SetWhitelisted adds a user to the single mode whitelist or removes them
+// at runtime, overriding whitelisted_users.
+func (c *Config) SetWhitelisted(userID int64, allowed bool) {
+	c.whitelistMu.Lock()
+	defer c.whitelistMu.Unlock()
+	if c.whitelistChanges == nil {
+		c.whitelistChanges = make(map[int64]bool)
+	}
+	c.whitelistChanges[userID] = allowed
+}
+
+// Whitelist returns the whitelisted user IDs with runtime changes applied,
+// sorted.
+func (c *Config) Whitelist() []int64 {
+	c.whitelistMu.RLock()
+	defer c.whitelistMu.RUnlock()
+	var ids []int64
 	for _, id := range c.WhitelistedUsers {
-		if id == userID {
-			return true
+		if allowed, ok := c.whitelistChanges[id]; !ok || allowed {
+			ids = append(ids, id)
 		}
 	}
-	return false
+	for id, allowed := range c.whitelistChanges {
+		if allowed {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
 }
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// envPrefix starts every environment variable that overrides a config key.
+const envPrefix = "FUNDBOT"
+
+// applyEnv overrides config keys from FUNDBOT_* environment variables, so
+// secrets can be injected instead of kept in the config file. A key's
+// variable is its JSON path, upper-cased and joined with underscores:
+// FUNDBOT_TELEGRAM_TOKEN, FUNDBOT_SWEEP_TREASURY. Map entries append the
+// key, matched to an entry already in the file regardless of case and with
+// _ for -, otherwise lower-cased: FUNDBOT_RPC_ENDPOINTS_BASE,
+// FUNDBOT_PROVIDERS_SIMPLESWAP_API_KEY. Any key, including lists, can also
+// be set whole as JSON, e.g. FUNDBOT_WHITELISTED_USERS='[1, 2]'; entries
+// named individually apply on top. Strings are taken as they are.
+func (c *Config) applyEnv(environ []string) error {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, envPrefix+"_") {
+			env[k] = v
+		}
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	return applyEnvStruct(reflect.ValueOf(c).Elem(), envPrefix, env)
+}
+
+func applyEnvStruct(v reflect.Value, prefix string, env map[string]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		if err := applyEnvValue(v.Field(i), prefix+"_"+strings.ToUpper(tag), env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyEnvValue(v reflect.Value, name string, env map[string]string) error {
+	if s, ok := env[name]; ok {
+		if err := setEnvValue(v, s); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return applyEnvStruct(v, name, env)
+	case reflect.Pointer:
+		if v.Type().Elem().Kind() != reflect.Struct || !hasEnvPrefix(env, name+"_") {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return applyEnvStruct(v.Elem(), name, env)
+	case reflect.Map:
+		return applyEnvMap(v, name, env)
+	}
+	return nil
+}
+
+// applyEnvMap sets the entries of a map of scalars or structs named by
+// variables under prefix.
+func applyEnvMap(v reflect.Value, prefix string, env map[string]string) error {
+	elem := v.Type().Elem()
+	for name, s := range env {
+		rest, ok := strings.CutPrefix(name, prefix+"_")
+		if !ok || rest == "" {
+			continue
+		}
+
+		// For structs, the entry's key is followed by one of its fields
+		field := -1
+		if elem.Kind() == reflect.Struct {
+			for i := 0; i < elem.NumField(); i++ {
+				tag, _, _ := strings.Cut(elem.Field(i).Tag.Get("json"), ",")
+				if key, ok := strings.CutSuffix(rest, "_"+strings.ToUpper(tag)); ok && tag != "" && key != "" {
+					rest, field = key, i
+					break
+				}
+			}
+			if field < 0 {
+				continue
+			}
+		}
+
+		key, err := envMapKey(v, rest)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		// Map entries aren't addressable, so set a copy and store it back
+		entry := reflect.New(elem).Elem()
+		if old := v.MapIndex(key); old.IsValid() {
+			entry.Set(old)
+		}
+		target := entry
+		if field >= 0 {
+			target = entry.Field(field)
+		}
+		if err := setEnvValue(target, s); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		v.SetMapIndex(key, entry)
+	}
+	return nil
+}
+
+// envMapKey converts the key part of a variable name to a key of map m.
+func envMapKey(m reflect.Value, s string) (reflect.Value, error) {
+	kt := m.Type().Key()
+	switch kt.Kind() {
+	case reflect.String:
+		for _, k := range m.MapKeys() {
+			if strings.ToUpper(strings.ReplaceAll(k.String(), "-", "_")) == s {
+				return k, nil
+			}
+		}
+		return reflect.ValueOf(strings.ToLower(s)).Convert(kt), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid key %q", s)
+		}
+		return reflect.ValueOf(n).Convert(kt), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid key %q", s)
+		}
+		return reflect.ValueOf(n).Convert(kt), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported map key type %s", kt)
+}
+
+// setEnvValue sets v from a variable's value: strings as they are, anything
+// else as JSON, or as text if it isn't JSON and the type reads text.
+func setEnvValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(s)
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok && !json.Valid([]byte(s)) {
+		return u.UnmarshalText([]byte(s))
+	}
+	if err := json.Unmarshal([]byte(s), v.Addr().Interface()); err != nil {
+		return fmt.Errorf("parsing value: %w", err)
+	}
+	return nil
+}
+
+func hasEnvPrefix(env map[string]string, prefix string) bool {
+	for name := range env {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
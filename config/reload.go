@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// reloadable are the keys Reload swaps into the running config. Everything
+// else is read once at startup.
+var reloadable = []string{"whitelisted_users", "explorers", "providers", "gas_refills", "affiliates"}
+
+// Reload re-reads the config file Load read, with environment overrides,
+// and if it is valid swaps in the keys that can change while running:
+// whitelisted_users, explorers, providers, gas_refills and affiliates.
+// Runtime whitelist changes stay on top of the new list. It returns the other keys whose value
+// differs from the running config, sorted; they take effect on restart.
+// Nothing changes if the new config doesn't load.
+func (c *Config) Reload() (restart []string, err error) {
+	next, err := Load(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, nv := reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		f := cur.Type().Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || tag == "" || slices.Contains(reloadable, tag) {
+			continue
+		}
+		// An unlocked keystore fills in the mnemonic
+		if tag == "mnemonic" && c.MnemonicKeystore != "" {
+			continue
+		}
+		if !reflect.DeepEqual(cur.Field(i).Interface(), nv.Field(i).Interface()) {
+			restart = append(restart, tag)
+		}
+	}
+	slices.Sort(restart)
+
+	c.whitelistMu.Lock()
+	c.WhitelistedUsers = next.WhitelistedUsers
+	c.whitelistMu.Unlock()
+
+	c.reloadMu.Lock()
+	c.Explorers = next.Explorers
+	c.Providers = next.Providers
+	c.GasRefills = next.GasRefills
+	c.Affiliates = next.Affiliates
+	c.reloadMu.Unlock()
+
+	return restart, nil
+}
+
+// Provider returns the config of the named swap provider.
+func (c *Config) Provider(name string) (ProviderConfig, bool) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	p, ok := c.Providers[name]
+	return p, ok
+}
+
+// ConfigWhitelisted reports whether whitelisted_users lists the user,
+// ignoring changes made at runtime.
+func (c *Config) ConfigWhitelisted(userID int64) bool {
+	c.whitelistMu.RLock()
+	defer c.whitelistMu.RUnlock()
+	return slices.Contains(c.WhitelistedUsers, userID)
+}
@@ -4,12 +4,16 @@
 //
 // Approvals use EIP-2612 permit signatures (gasless) embedded as CoW pre-hooks,
 // so orders can be placed even with zero native token balance.
+//
+// Smart accounts (swaps.CallSender) can't sign EIP-712 orders, so they use
+// the presign scheme: the order is submitted unsigned and approved with a
+// setPreSignature call to the settlement contract, batched with any USDC
+// approval.
 package cowswap
 
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -18,15 +22,20 @@ import (
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 const (
@@ -45,12 +54,47 @@ const (
 	permitGasLimit = "80000"
 )
 
+// PermitDomain is the EIP-712 domain name and version of a token's EIP-2612 permit.
+type PermitDomain struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// DefaultPermitDomain is the domain of Circle's native USDC (FiatToken v2).
+var DefaultPermitDomain = PermitDomain{Name: "USD Coin", Version: "2"}
+
 // ChainConfig holds chain-specific CoW Protocol configuration.
 type ChainConfig struct {
 	APIBase      string
 	ChainID      int64
 	USDCAddress  string
 	NativeSymbol string
+
+	// USDCPermit is the USDC permit domain; zero means DefaultPermitDomain.
+	// Bridged USDC deployments often use a different name.
+	USDCPermit PermitDomain
+}
+
+// permitDomain returns the USDC permit domain for the chain.
+func (cc ChainConfig) permitDomain() PermitDomain {
+	if cc.USDCPermit.Name == "" {
+		return DefaultPermitDomain
+	}
+	return cc.USDCPermit
+}
+
+// SetPermitDomain overrides the USDC permit domain for a supported chain.
+func SetPermitDomain(chain string, domain PermitDomain) error {
+	cc, ok := SupportedChains[chain]
+	if !ok {
+		return fmt.Errorf("CoW Protocol not supported on %s", chain)
+	}
+	if domain.Name == "" || domain.Version == "" {
+		return fmt.Errorf("permit domain for %s needs a name and version", chain)
+	}
+	cc.USDCPermit = domain
+	SupportedChains[chain] = cc
+	return nil
 }
 
 // SupportedChains maps RPC chain key to CoW Protocol config.
@@ -67,12 +111,51 @@ var SupportedChains = map[string]ChainConfig{
 		USDCAddress:  "0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E",
 		NativeSymbol: "AVAX",
 	},
+	"arbitrum": {
+		APIBase:      "https://api.cow.fi/arbitrum_one/api/v1",
+		ChainID:      42161,
+		USDCAddress:  "0xaf88d065e77c8cC2239327C5EDb3A432268e5831",
+		NativeSymbol: "ETH",
+	},
+	"ethereum": {
+		APIBase:      "https://api.cow.fi/mainnet/api/v1",
+		ChainID:      1,
+		USDCAddress:  "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		NativeSymbol: "ETH",
+	},
+	"polygon": {
+		APIBase:      "https://api.cow.fi/polygon/api/v1",
+		ChainID:      137,
+		USDCAddress:  "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359",
+		NativeSymbol: "POL",
+	},
+	// Gnosis has no native Circle USDC; USDC.e is Circle's bridged FiatToken,
+	// which signs permits under its own name.
+	"gnosis": {
+		APIBase:      "https://api.cow.fi/xdai/api/v1",
+		ChainID:      100,
+		USDCAddress:  "0x2a22f9c3b484c3629090FeED35F17Ff8F88f76F0",
+		NativeSymbol: "XDAI",
+		USDCPermit:   PermitDomain{Name: "Bridged USDC (Gnosis)", Version: "2"},
+	},
 }
 
 // Client handles CoW Protocol API interactions.
 type Client struct {
 	httpClient *http.Client
 	rpcClients map[string]*ethclient.Client
+
+	mu      sync.RWMutex
+	partner Partner
+}
+
+// Partner identifies the operator's orders by AppCode in their appData and
+// charges a partner fee of FeeBps basis points of each order, paid by CoW
+// to Recipient.
+type Partner struct {
+	AppCode   string
+	FeeBps    int
+	Recipient common.Address
 }
 
 // NewClient creates a new CoW Protocol client.
@@ -83,6 +166,19 @@ func NewClient(rpcClients map[string]*ethclient.Client, httpClient *http.Client)
 	}
 }
 
+// SetPartner sets the partner appData of orders placed from now on.
+func (c *Client) SetPartner(p Partner) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.partner = p
+}
+
+func (c *Client) getPartner() Partner {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.partner
+}
+
 // --- API types ---
 
 // QuoteRequest is the POST body for /api/v1/quote.
@@ -90,7 +186,8 @@ type QuoteRequest struct {
 	SellToken           string `json:"sellToken"`
 	BuyToken            string `json:"buyToken"`
 	Receiver            string `json:"receiver"`
-	SellAmountBeforeFee string `json:"sellAmountBeforeFee"`
+	SellAmountBeforeFee string `json:"sellAmountBeforeFee,omitempty"`
+	BuyAmountAfterFee   string `json:"buyAmountAfterFee,omitempty"`
 	Kind                string `json:"kind"`
 	From                string `json:"from"`
 	AppData             string `json:"appData"`
@@ -140,31 +237,85 @@ type OrderSubmission struct {
 	QuoteID           int64  `json:"quoteId,omitempty"`
 }
 
-// GasRefillResult holds the result of a gas refill operation.
-type GasRefillResult struct {
+// OrderResult holds a submitted USDC sell order.
+type OrderResult struct {
 	Chain      string
 	OrderUID   string
 	Status     string
-	SellAmount string // USDC amount in smallest units
-	BuyAmount  string // native token amount in smallest units
+	Kind       string // "sell", or "buy" for an exact buy amount
+	SellAmount string // USDC amount in smallest units; the most sold for buy orders
+	BuyAmount  string // buy token amount in smallest units
+	ValidTo    time.Time
+
+	// PartiallyFillable orders can settle in several trades
+	PartiallyFillable bool
 }
 
-// --- Core API methods (reusable for future swap provider) ---
+// Order is an order as the CoW API reports it. The executed amounts are
+// running totals, including fees, and only move before the order is
+// fulfilled if it is partially fillable.
+type Order struct {
+	Status             string `json:"status"`
+	Kind               string `json:"kind"`
+	PartiallyFillable  bool   `json:"partiallyFillable"`
+	SellAmount         string `json:"sellAmount"`
+	BuyAmount          string `json:"buyAmount"`
+	ExecutedSellAmount string `json:"executedSellAmount"`
+	ExecutedBuyAmount  string `json:"executedBuyAmount"`
+}
 
-// GetQuote requests a quote from the CoW Protocol API.
-// appData/appDataHash can be empty to use defaults (no hooks).
-func (c *Client) GetQuote(chain string, sellToken, buyToken string, sellAmount *big.Int, from common.Address, receiver common.Address, appData, appDataHashHex string) (*QuoteResult, error) {
-	cc, ok := SupportedChains[chain]
-	if !ok {
-		return nil, fmt.Errorf("chain %q not supported by CoW Protocol", chain)
+// FilledPercent is how much of an order has filled, 0 to 100: of its buy
+// amount for buy orders, of its sell amount otherwise.
+func FilledPercent(kind, sellAmount, buyAmount, executedSell, executedBuy string) int {
+	total, executed := sellAmount, executedSell
+	if kind == "buy" {
+		total, executed = buyAmount, executedBuy
 	}
+	t, ok := new(big.Int).SetString(total, 10)
+	e, ok2 := new(big.Int).SetString(executed, 10)
+	if !ok || !ok2 || t.Sign() <= 0 {
+		return 0
+	}
+	pct := e.Mul(e, big.NewInt(100))
+	pct.Div(pct, t)
+	return int(min(pct.Int64(), 100))
+}
 
-	if appData == "" {
-		appData = defaultAppDataJSON
-		appDataHashHex = defaultAppDataHash
+// Surplus is what the executed part of an order got beyond its limit price:
+// for sell orders, buy token above the buy amount pro-rated to the sell
+// amount executed; for buy orders, sell token below the sell amount
+// pro-rated to the buy amount executed. Never negative.
+func Surplus(kind, sellAmount, buyAmount, executedSell, executedBuy string) *big.Int {
+	sell, ok1 := new(big.Int).SetString(sellAmount, 10)
+	buy, ok2 := new(big.Int).SetString(buyAmount, 10)
+	execSell, ok3 := new(big.Int).SetString(executedSell, 10)
+	execBuy, ok4 := new(big.Int).SetString(executedBuy, 10)
+	if !ok1 || !ok2 || !ok3 || !ok4 || sell.Sign() <= 0 || buy.Sign() <= 0 {
+		return new(big.Int)
 	}
+	var surplus *big.Int
+	if kind == "buy" {
+		limit := new(big.Int).Div(new(big.Int).Mul(sell, execBuy), buy)
+		surplus = limit.Sub(limit, execSell)
+	} else {
+		limit := new(big.Int).Div(new(big.Int).Mul(buy, execSell), sell)
+		surplus = execBuy.Sub(execBuy, limit)
+	}
+	if surplus.Sign() < 0 {
+		return new(big.Int)
+	}
+	return surplus
+}
+
+// GasRefillResult holds the result of a gas refill operation.
+type GasRefillResult = OrderResult
+
+// --- Core API methods (reusable for future swap provider) ---
 
-	req := QuoteRequest{
+// GetQuote requests a quote from the CoW Protocol API.
+// appData/appDataHash can be empty to use defaults (no hooks).
+func (c *Client) GetQuote(chain string, sellToken, buyToken string, sellAmount *big.Int, from common.Address, receiver common.Address, appData, appDataHashHex string) (*QuoteResult, error) {
+	return c.quote(chain, QuoteRequest{
 		SellToken:           sellToken,
 		BuyToken:            buyToken,
 		Receiver:            receiver.Hex(),
@@ -173,7 +324,37 @@ func (c *Client) GetQuote(chain string, sellToken, buyToken string, sellAmount *
 		From:                from.Hex(),
 		AppData:             appData,
 		AppDataHash:         appDataHashHex,
-		SigningScheme:       "eip712",
+	})
+}
+
+// GetBuyQuote requests a quote for buying exactly buyAmount of buyToken. The
+// quote's sellAmount excludes its feeAmount, which the signed order has to
+// cover since orders are placed with a zero fee.
+func (c *Client) GetBuyQuote(chain string, sellToken, buyToken string, buyAmount *big.Int, from common.Address, receiver common.Address, appData, appDataHashHex string) (*QuoteResult, error) {
+	return c.quote(chain, QuoteRequest{
+		SellToken:         sellToken,
+		BuyToken:          buyToken,
+		Receiver:          receiver.Hex(),
+		BuyAmountAfterFee: buyAmount.String(),
+		Kind:              "buy",
+		From:              from.Hex(),
+		AppData:           appData,
+		AppDataHash:       appDataHashHex,
+	})
+}
+
+func (c *Client) quote(chain string, req QuoteRequest) (*QuoteResult, error) {
+	cc, ok := SupportedChains[chain]
+	if !ok {
+		return nil, fmt.Errorf("chain %q not supported by CoW Protocol", chain)
+	}
+
+	if req.AppData == "" {
+		req.AppData = defaultAppDataJSON
+		req.AppDataHash = defaultAppDataHash
+	}
+	if req.SigningScheme == "" {
+		req.SigningScheme = "eip712"
 	}
 
 	body, err := json.Marshal(req)
@@ -205,7 +386,7 @@ func (c *Client) GetQuote(chain string, sellToken, buyToken string, sellAmount *
 }
 
 // SignOrder signs a CoW Protocol order using EIP-712 and returns the signature hex.
-func (c *Client) SignOrder(cc ChainConfig, qr *QuoteResult, privateKey *ecdsa.PrivateKey) (string, error) {
+func (c *Client) SignOrder(ctx context.Context, cc ChainConfig, qr *QuoteResult, signer wallet.Signer) (string, error) {
 	q := qr.Quote
 
 	typedData := apitypes.TypedData{
@@ -254,29 +435,12 @@ func (c *Client) SignOrder(cc ChainConfig, qr *QuoteResult, privateKey *ecdsa.Pr
 		},
 	}
 
-	domainSep, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
-	if err != nil {
-		return "", fmt.Errorf("hashing domain: %w", err)
-	}
-
-	msgHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
-	if err != nil {
-		return "", fmt.Errorf("hashing message: %w", err)
-	}
-
-	rawData := fmt.Sprintf("\x19\x01%s%s", string(domainSep), string(msgHash))
-	digest := crypto.Keccak256Hash([]byte(rawData))
-
-	sig, err := crypto.Sign(digest.Bytes(), privateKey)
+	// Signature comes back with v = 27 or 28, as CoW expects
+	sig, err := signer.SignTypedData(ctx, typedData)
 	if err != nil {
 		return "", fmt.Errorf("signing order: %w", err)
 	}
 
-	// Ethereum signature convention: v = 27 or 28
-	if sig[64] < 27 {
-		sig[64] += 27
-	}
-
 	return fmt.Sprintf("0x%x", sig), nil
 }
 
@@ -324,6 +488,13 @@ func (c *Client) RegisterAppData(chain string, appDataHash string, fullAppData s
 // document) so the backend registers any hooks (e.g. EIP-2612 permit pre-hooks).
 // When empty, the bytes32 hash from the quote response is used instead.
 func (c *Client) SubmitOrder(chain string, qr *QuoteResult, signature string, from common.Address, fullAppData string) (string, error) {
+	return c.submitOrder(chain, qr, "eip712", signature, from, fullAppData)
+}
+
+// submitOrder submits an order under a signing scheme: "eip712" with the
+// owner's signature, or "presign" with the owner's address as the
+// signature, to be approved on-chain.
+func (c *Client) submitOrder(chain string, qr *QuoteResult, scheme, signature string, from common.Address, fullAppData string) (string, error) {
 	cc, ok := SupportedChains[chain]
 	if !ok {
 		return "", fmt.Errorf("chain %q not supported by CoW Protocol", chain)
@@ -347,7 +518,7 @@ func (c *Client) SubmitOrder(chain string, qr *QuoteResult, signature string, fr
 		PartiallyFillable: q.PartiallyFillable,
 		SellTokenBalance:  q.SellTokenBalance,
 		BuyTokenBalance:   q.BuyTokenBalance,
-		SigningScheme:     "eip712",
+		SigningScheme:     scheme,
 		Signature:         signature,
 		From:              from.Hex(),
 		QuoteID:           qr.ID,
@@ -384,41 +555,138 @@ func (c *Client) SubmitOrder(chain string, qr *QuoteResult, signature string, fr
 // CheckOrderStatus checks the status of a CoW order.
 // Returns one of: "presignaturePending", "open", "fulfilled", "cancelled", "expired".
 func (c *Client) CheckOrderStatus(chain string, orderUID string) (string, error) {
+	order, err := c.GetOrder(chain, orderUID)
+	if err != nil {
+		return "", err
+	}
+	return order.Status, nil
+}
+
+// GetOrder fetches an order's status and how much of it has executed.
+func (c *Client) GetOrder(chain string, orderUID string) (*Order, error) {
 	cc, ok := SupportedChains[chain]
 	if !ok {
-		return "", fmt.Errorf("unsupported chain: %s", chain)
+		return nil, fmt.Errorf("unsupported chain: %s", chain)
 	}
 
 	url := fmt.Sprintf("%s/orders/%s", cc.APIBase, orderUID)
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
-		return "", fmt.Errorf("fetching order status: %w", err)
+		return nil, fmt.Errorf("fetching order status: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("order status API returned %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("order status API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var order Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, fmt.Errorf("decoding order status: %w", err)
 	}
 
-	var result struct {
-		Status string `json:"status"`
+	return &order, nil
+}
+
+// CancelOrder asks the CoW API to cancel an open order. The cancellation is
+// signed off-chain by the order's owner, so it costs no gas, but an order a
+// solver is already settling can still fill.
+//
+// A smart account's order is instead invalidated on-chain through the
+// settlement contract, which the account's paymaster pays for.
+func (c *Client) CancelOrder(ctx context.Context, chain string, signer wallet.Signer, orderUID string) error {
+	cc, ok := SupportedChains[chain]
+	if !ok {
+		return fmt.Errorf("chain %q not supported by CoW Protocol", chain)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decoding order status: %w", err)
+
+	if sender, ok := signer.(swaps.CallSender); ok {
+		uid, err := hexutil.Decode(orderUID)
+		if err != nil {
+			return fmt.Errorf("invalid order UID: %w", err)
+		}
+		data, err := settlementABI.Pack("invalidateOrder", uid)
+		if err != nil {
+			return err
+		}
+		txHash, err := sender.SendCalls(ctx, chain, []swaps.Call{{To: common.HexToAddress(SettlementContract), Value: big.NewInt(0), Data: data}})
+		if err != nil {
+			return fmt.Errorf("invalidating order: %w", err)
+		}
+		log.Printf("CoW order %s invalidated on %s in %s", orderUID, chain, txHash)
+		return nil
 	}
 
-	return result.Status, nil
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"OrderCancellations": {
+				{Name: "orderUids", Type: "bytes[]"},
+			},
+		},
+		PrimaryType: "OrderCancellations",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Gnosis Protocol",
+			Version:           "v2",
+			ChainId:           math.NewHexOrDecimal256(cc.ChainID),
+			VerifyingContract: SettlementContract,
+		},
+		Message: apitypes.TypedDataMessage{
+			"orderUids": []interface{}{orderUID},
+		},
+	}
+	sig, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return fmt.Errorf("signing cancellation: %w", err)
+	}
+
+	body, err := json.Marshal(struct {
+		OrderUIDs     []string `json:"orderUids"`
+		Signature     string   `json:"signature"`
+		SigningScheme string   `json:"signingScheme"`
+	}{
+		OrderUIDs:     []string{orderUID},
+		Signature:     fmt.Sprintf("0x%x", sig),
+		SigningScheme: "eip712",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, cc.APIBase+"/orders", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("cancellation API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
 }
 
 // --- EIP-2612 permit (gasless approval) ---
 
 var erc20ABI abi.ABI
 var permitABI abi.ABI
+var settlementABI abi.ABI
 
 func init() {
 	var err error
-	erc20ABI, err = abi.JSON(strings.NewReader(`[{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"owner","type":"address"}],"name":"nonces","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`))
+	erc20ABI, err = abi.JSON(strings.NewReader(`[{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"owner","type":"address"}],"name":"nonces","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`))
 	if err != nil {
 		panic(err)
 	}
@@ -426,6 +694,10 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	settlementABI, err = abi.JSON(strings.NewReader(`[{"inputs":[{"name":"orderUid","type":"bytes"},{"name":"signed","type":"bool"}],"name":"setPreSignature","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"orderUid","type":"bytes"}],"name":"invalidateOrder","outputs":[],"stateMutability":"nonpayable","type":"function"}]`))
+	if err != nil {
+		panic(err)
+	}
 }
 
 // permitHook represents a CoW pre-hook for an EIP-2612 permit call.
@@ -438,11 +710,18 @@ type permitHook struct {
 // appDataDoc is the appData JSON document structure.
 type appDataDoc struct {
 	Version  string          `json:"version"`
+	AppCode  string          `json:"appCode,omitempty"`
 	Metadata appDataMetadata `json:"metadata"`
 }
 
 type appDataMetadata struct {
-	Hooks *appDataHooks `json:"hooks,omitempty"`
+	Hooks      *appDataHooks      `json:"hooks,omitempty"`
+	PartnerFee *appDataPartnerFee `json:"partnerFee,omitempty"`
+}
+
+type appDataPartnerFee struct {
+	Bps       int    `json:"bps"`
+	Recipient string `json:"recipient"`
 }
 
 type appDataHooks struct {
@@ -450,6 +729,29 @@ type appDataHooks struct {
 	Post []struct{}   `json:"post,omitempty"`
 }
 
+// buildAppData returns an order's appData JSON, with the pre-hooks and the
+// partner's appCode and fee, and its hash. Both are empty when there is
+// nothing to add, so GetQuote uses the default document.
+func buildAppData(pre []permitHook, partner Partner) (string, string, error) {
+	if len(pre) == 0 && partner.AppCode == "" && partner.FeeBps == 0 {
+		return "", "", nil
+	}
+
+	doc := appDataDoc{Version: "1.3.0", AppCode: partner.AppCode}
+	if len(pre) > 0 {
+		doc.Metadata.Hooks = &appDataHooks{Pre: pre}
+	}
+	if partner.FeeBps > 0 {
+		doc.Metadata.PartnerFee = &appDataPartnerFee{Bps: partner.FeeBps, Recipient: partner.Recipient.Hex()}
+	}
+
+	appJSON, err := json.Marshal(doc)
+	if err != nil {
+		return "", "", fmt.Errorf("marshaling appData: %w", err)
+	}
+	return string(appJSON), buildAppDataHash(string(appJSON)), nil
+}
+
 // buildAppDataHash computes keccak256 of the appData JSON string.
 func buildAppDataHash(appDataJSON string) string {
 	hash := crypto.Keccak256Hash([]byte(appDataJSON))
@@ -501,21 +803,24 @@ func (c *Client) getNonce(ctx context.Context, chain string, token common.Addres
 	return new(big.Int).SetBytes(output), nil
 }
 
-// signPermit signs an EIP-2612 permit for USDC and returns the permit callData
-// to be used as a CoW pre-hook, plus the appData JSON and its hash.
+// signPermit signs an EIP-2612 permit for USDC and returns the permit call
+// as a CoW pre-hook. The permit lasts at least 30 minutes, and until
+// validTo so a long-lived order can still use it when it fills.
 //
-// USDC uses EIP-2612 with domain: name="USD Coin", version="2".
-func (c *Client) signPermit(ctx context.Context, chain string, cc ChainConfig, owner common.Address, privateKey *ecdsa.PrivateKey, amount *big.Int) (string, string, error) {
+// The domain name/version come from the chain's USDCPermit (default "USD Coin"/"2").
+func (c *Client) signPermit(ctx context.Context, chain string, cc ChainConfig, signer wallet.Signer, amount *big.Int, validTo time.Time) (permitHook, error) {
+	owner := signer.Address()
 	token := common.HexToAddress(cc.USDCAddress)
 	spender := common.HexToAddress(VaultRelayer)
 
 	nonce, err := c.getNonce(ctx, chain, token, owner)
 	if err != nil {
-		return "", "", fmt.Errorf("getting permit nonce: %w", err)
+		return permitHook{}, fmt.Errorf("getting permit nonce: %w", err)
 	}
 
-	// Deadline: 30 minutes from now
-	deadline := big.NewInt(time.Now().Unix() + 1800)
+	deadline := big.NewInt(max(time.Now().Unix()+1800, validTo.Unix()))
+
+	domain := cc.permitDomain()
 
 	// Sign EIP-712 permit
 	typedData := apitypes.TypedData{
@@ -536,8 +841,8 @@ func (c *Client) signPermit(ctx context.Context, chain string, cc ChainConfig, o
 		},
 		PrimaryType: "Permit",
 		Domain: apitypes.TypedDataDomain{
-			Name:              "USD Coin",
-			Version:           "2",
+			Name:              domain.Name,
+			Version:           domain.Version,
 			ChainId:           math.NewHexOrDecimal256(cc.ChainID),
 			VerifyingContract: cc.USDCAddress,
 		},
@@ -550,78 +855,45 @@ func (c *Client) signPermit(ctx context.Context, chain string, cc ChainConfig, o
 		},
 	}
 
-	domainSep, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
-	if err != nil {
-		return "", "", fmt.Errorf("hashing permit domain: %w", err)
-	}
-
-	msgHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	sig, err := signer.SignTypedData(ctx, typedData)
 	if err != nil {
-		return "", "", fmt.Errorf("hashing permit message: %w", err)
+		return permitHook{}, fmt.Errorf("signing permit: %w", err)
 	}
 
-	rawData := fmt.Sprintf("\x19\x01%s%s", string(domainSep), string(msgHash))
-	digest := crypto.Keccak256Hash([]byte(rawData))
-
-	sig, err := crypto.Sign(digest.Bytes(), privateKey)
-	if err != nil {
-		return "", "", fmt.Errorf("signing permit: %w", err)
-	}
-
-	// Extract r, s, v
+	// Extract r, s, v (v is already 27 or 28)
 	r := [32]byte{}
 	s := [32]byte{}
 	copy(r[:], sig[:32])
 	copy(s[:], sig[32:64])
 	v := sig[64]
-	if v < 27 {
-		v += 27
-	}
 
 	// ABI-encode the permit() call
 	callData, err := permitABI.Pack("permit", owner, spender, amount, deadline, v, r, s)
 	if err != nil {
-		return "", "", fmt.Errorf("encoding permit callData: %w", err)
-	}
-
-	// Build appData with permit pre-hook
-	doc := appDataDoc{
-		Version: "1.3.0",
-		Metadata: appDataMetadata{
-			Hooks: &appDataHooks{
-				Pre: []permitHook{
-					{
-						Target:   cc.USDCAddress,
-						CallData: "0x" + hex.EncodeToString(callData),
-						GasLimit: permitGasLimit,
-					},
-				},
-			},
-		},
-	}
-
-	appJSON, err := json.Marshal(doc)
-	if err != nil {
-		return "", "", fmt.Errorf("marshaling appData: %w", err)
+		return permitHook{}, fmt.Errorf("encoding permit callData: %w", err)
 	}
 
-	appJSONStr := string(appJSON)
-	appHash := buildAppDataHash(appJSONStr)
-
 	log.Printf("Built permit pre-hook for %s on %s (nonce=%s, deadline=%s)",
 		owner.Hex(), cc.NativeSymbol, nonce.String(), deadline.String())
 
-	return appJSONStr, appHash, nil
+	return permitHook{
+		Target:   cc.USDCAddress,
+		CallData: "0x" + hex.EncodeToString(callData),
+		GasLimit: permitGasLimit,
+	}, nil
 }
 
 // --- Gas refill (high-level) ---
 
 // RefillGasIfNeeded checks if the wallet needs gas on a chain and submits a CoW swap if so.
 // Uses EIP-2612 permit for gasless approval when the vault relayer allowance is insufficient.
+// With a target above the balance it buys exactly the shortfall, selling at
+// most refillUSDC (or the USDC balance, if lower); otherwise it sells
+// refillUSDC for whatever it fetches.
+// The quote has to pass guards.
 // Returns nil result if no refill was needed or conditions weren't met.
-func (c *Client) RefillGasIfNeeded(ctx context.Context, chain string, addr common.Address, privateKey *ecdsa.PrivateKey, nativeBalance *big.Int, usdcBalance *big.Int, minNativeWei *big.Int, refillUSDC *big.Int) (*GasRefillResult, error) {
-	cc, ok := SupportedChains[chain]
-	if !ok {
+func (c *Client) RefillGasIfNeeded(ctx context.Context, chain string, signer wallet.Signer, nativeBalance *big.Int, usdcBalance *big.Int, minNativeWei *big.Int, targetNativeWei *big.Int, refillUSDC *big.Int, guards Guards) (*GasRefillResult, error) {
+	if _, ok := SupportedChains[chain]; !ok {
 		return nil, nil // chain not supported by CoW
 	}
 
@@ -629,6 +901,22 @@ func (c *Client) RefillGasIfNeeded(ctx context.Context, chain string, addr commo
 		return nil, nil // sufficient gas
 	}
 
+	addr := signer.Address()
+
+	if targetNativeWei != nil && targetNativeWei.Cmp(nativeBalance) > 0 {
+		maxSell := refillUSDC
+		if usdcBalance.Cmp(maxSell) < 0 {
+			maxSell = usdcBalance
+		}
+		if maxSell.Sign() <= 0 {
+			return nil, nil // no USDC for refill
+		}
+		shortfall := new(big.Int).Sub(targetNativeWei, nativeBalance)
+		log.Printf("Gas refill needed on %s for %s: native=%s, threshold=%s, buying %s",
+			chain, addr.Hex(), nativeBalance.String(), minNativeWei.String(), shortfall.String())
+		return c.BuyWithUSDC(ctx, chain, signer, NativeToken, shortfall, maxSell, addr, guards)
+	}
+
 	if usdcBalance.Cmp(refillUSDC) < 0 {
 		return nil, nil // insufficient USDC for refill
 	}
@@ -636,63 +924,358 @@ func (c *Client) RefillGasIfNeeded(ctx context.Context, chain string, addr commo
 	log.Printf("Gas refill needed on %s for %s: native=%s, threshold=%s",
 		chain, addr.Hex(), nativeBalance.String(), minNativeWei.String())
 
-	sellToken := common.HexToAddress(cc.USDCAddress)
+	return c.SellUSDC(ctx, chain, signer, refillUSDC, NativeToken, addr, guards)
+}
 
-	// Check if we need a permit (allowance < refillUSDC)
-	var appData, appHash string
-	needs, err := c.needsPermit(ctx, chain, sellToken, addr, refillUSDC)
-	if err != nil {
-		return nil, fmt.Errorf("checking permit need: %w", err)
+// CanRefill reports whether RefillGasIfNeeded would have the USDC to place
+// a refill with these balances and settings, without placing it.
+func CanRefill(nativeBalance, usdcBalance, targetNativeWei, refillUSDC *big.Int) bool {
+	if targetNativeWei != nil && targetNativeWei.Cmp(nativeBalance) > 0 {
+		return usdcBalance.Sign() > 0 && refillUSDC.Sign() > 0
 	}
+	return usdcBalance.Cmp(refillUSDC) >= 0
+}
 
-	if needs {
-		// Use max uint256 for permit value so we don't need to permit again next time
-		maxValue := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
-		appData, appHash, err = c.signPermit(ctx, chain, cc, addr, privateKey, maxValue)
-		if err != nil {
-			return nil, fmt.Errorf("signing permit: %w", err)
+// marketOrderValidity is how long a quote-priced order stays open; short,
+// so a refill that doesn't fill is retried at a fresh price.
+const marketOrderValidity = 3 * time.Minute
+
+// defaultSlippageBps is the slippage of market orders whose Guards don't
+// set one.
+const defaultSlippageBps = 100
+
+// Guards bound what a market order takes from its quote. The zero value
+// allows the default slippage and any fee or price.
+type Guards struct {
+	// Slippage from the quote signed into the order, in basis points: the
+	// buy amount is lowered by it, or a buy order's sell amount raised
+	SlippageBps int
+
+	// Most the quote's network fee may be, in basis points of the USDC
+	// the order sells; 0 is no limit
+	MaxFeeBps int
+
+	// Most the quote's price may be above the native token's reference
+	// price (swaps.NativePriceUSD), in basis points; 0 is no limit. Only
+	// applies to orders buying the native token, and is skipped when the
+	// chain has no reference price.
+	MaxDeviationBps int
+}
+
+func (g Guards) slippageBps() int64 {
+	if g.SlippageBps <= 0 {
+		return defaultSlippageBps
+	}
+	return int64(g.SlippageBps)
+}
+
+// check refuses a quote whose fee or price is past the guards, before
+// anything is signed. The price paid is the quote's sell amount and fee
+// over its buy amount, leaving out slippage and any partner fee.
+func (g Guards) check(ctx context.Context, chain string, qr *QuoteResult) error {
+	if g.MaxFeeBps <= 0 && g.MaxDeviationBps <= 0 {
+		return nil
+	}
+	sell, ok := new(big.Int).SetString(qr.Quote.SellAmount, 10)
+	if !ok {
+		return fmt.Errorf("invalid sellAmount: %s", qr.Quote.SellAmount)
+	}
+	buy, ok := new(big.Int).SetString(qr.Quote.BuyAmount, 10)
+	if !ok || buy.Sign() <= 0 {
+		return fmt.Errorf("invalid buyAmount: %s", qr.Quote.BuyAmount)
+	}
+	fee, ok := new(big.Int).SetString(qr.Quote.FeeAmount, 10)
+	if !ok {
+		fee = new(big.Int)
+	}
+	total := new(big.Int).Add(sell, fee)
+	if total.Sign() <= 0 {
+		return fmt.Errorf("quote sells nothing")
+	}
+
+	if g.MaxFeeBps > 0 {
+		feeBps := new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(10000)), total).Int64()
+		if feeBps > int64(g.MaxFeeBps) {
+			return fmt.Errorf("CoW fee of %s USDC units is %.2f%% of the order, above the %.2f%% allowed", fee, float64(feeBps)/100, float64(g.MaxFeeBps)/100)
 		}
 	}
-	// If no permit needed, appData/appHash are empty strings → GetQuote uses defaults
+
+	if g.MaxDeviationBps > 0 && strings.EqualFold(qr.Quote.BuyToken, NativeToken) {
+		ref, err := swaps.NativePriceUSD(ctx, chain)
+		if err != nil || ref <= 0 {
+			log.Printf("CoW quote on %s: no reference price, not checking its price: %v", chain, err)
+			return nil
+		}
+		// USDC has 6 decimals and native tokens 18 on every CoW chain
+		paid, _ := new(big.Float).Quo(new(big.Float).SetInt(total), big.NewFloat(1e6)).Float64()
+		got, _ := new(big.Float).Quo(new(big.Float).SetInt(buy), big.NewFloat(1e18)).Float64()
+		price := paid / got
+		if deviation := (price/ref - 1) * 10000; deviation > float64(g.MaxDeviationBps) {
+			return fmt.Errorf("CoW quote prices the native token at $%.4f, %.2f%% above the $%.4f reference (at most %.2f%% allowed)", price, deviation/100, ref, float64(g.MaxDeviationBps)/100)
+		}
+	}
+	return nil
+}
+
+// SellUSDC places a CoW order selling sellAmount of the chain's USDC for
+// buyToken, paid out to receiver. Uses an EIP-2612 permit pre-hook when the
+// vault relayer allowance is insufficient, so no native gas is needed. The
+// quote has to pass guards, and the order takes their slippage.
+func (c *Client) SellUSDC(ctx context.Context, chain string, signer wallet.Signer, sellAmount *big.Int, buyToken string, receiver common.Address, guards Guards) (*OrderResult, error) {
+	return c.sellUSDC(ctx, chain, signer, sellAmount, buyToken, receiver, nil, marketOrderValidity, false, guards)
+}
+
+// SellUSDCLimit places a CoW limit order selling sellAmount of the chain's
+// USDC for at least buyAmount of buyToken, open for validFor. Unlike
+// SellUSDC the price is the caller's, not the quote's, so the order may
+// wait until the market reaches it. Any partner fee comes out of the
+// surplus above buyAmount. A partial order can fill in parts, each at the
+// limit price or better.
+func (c *Client) SellUSDCLimit(ctx context.Context, chain string, signer wallet.Signer, sellAmount *big.Int, buyToken string, buyAmount *big.Int, receiver common.Address, validFor time.Duration, partial bool) (*OrderResult, error) {
+	if buyAmount == nil || buyAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("limit order needs a positive buy amount")
+	}
+	return c.sellUSDC(ctx, chain, signer, sellAmount, buyToken, receiver, buyAmount, validFor, partial, Guards{})
+}
+
+// sellUSDC places a USDC sell order expiring after validFor. With a nil
+// limit the quote has to pass guards, and the buy amount is the quote's
+// less the partner fee and the guards' slippage; otherwise it is limit.
+func (c *Client) sellUSDC(ctx context.Context, chain string, signer wallet.Signer, sellAmount *big.Int, buyToken string, receiver common.Address, limit *big.Int, validFor time.Duration, partial bool, guards Guards) (*OrderResult, error) {
+	cc, ok := SupportedChains[chain]
+	if !ok {
+		return nil, fmt.Errorf("CoW does not support %s", chain)
+	}
+
+	validTo := time.Now().Add(validFor).Truncate(time.Second)
+	appData, appHash, partner, approve, err := c.orderAppData(ctx, chain, cc, signer, sellAmount, validTo)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get quote (with permit hook appData if needed)
-	qr, err := c.GetQuote(chain, cc.USDCAddress, NativeToken, refillUSDC, addr, addr, appData, appHash)
+	qr, err := c.quote(chain, QuoteRequest{
+		SellToken:           cc.USDCAddress,
+		BuyToken:            buyToken,
+		Receiver:            receiver.Hex(),
+		SellAmountBeforeFee: sellAmount.String(),
+		Kind:                "sell",
+		From:                signer.Address().Hex(),
+		AppData:             appData,
+		AppDataHash:         appHash,
+		SigningScheme:       signingScheme(signer),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("getting quote: %w", err)
 	}
 
-	// Override expiry to 3 minutes from now for faster retry cycle
-	qr.Quote.ValidTo = uint32(time.Now().Unix() + 180)
+	qr.Quote.PartiallyFillable = partial
+	if limit != nil {
+		qr.Quote.BuyAmount = limit.String()
+	} else {
+		if err := guards.check(ctx, chain, qr); err != nil {
+			return nil, err
+		}
+		// Apply slippage tolerance to buyAmount so the order fills quickly
+		buyAmt, ok := new(big.Int).SetString(qr.Quote.BuyAmount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid buyAmount: %s", qr.Quote.BuyAmount)
+		}
+		// The quote doesn't include the partner fee, which CoW takes from the
+		// proceeds, so the limit leaves room for it
+		if partner.FeeBps > 0 {
+			buyAmt.Mul(buyAmt, big.NewInt(int64(10000-partner.FeeBps)))
+			buyAmt.Div(buyAmt, big.NewInt(10000))
+		}
+		// Reduce by the slippage: buyAmount * (10000 - bps) / 10000
+		buyAmt.Mul(buyAmt, big.NewInt(10000-guards.slippageBps()))
+		buyAmt.Div(buyAmt, big.NewInt(10000))
+		qr.Quote.BuyAmount = buyAmt.String()
+	}
+
+	return c.submit(ctx, chain, cc, signer, qr, appData, approve, validTo)
+}
 
-	// Apply 1% slippage tolerance to buyAmount so the order fills quickly
-	buyAmt, ok := new(big.Int).SetString(qr.Quote.BuyAmount, 10)
+// BuyWithUSDC places a CoW buy order for exactly buyAmount of buyToken, paid
+// out to receiver, selling at most maxSell of the chain's USDC. The sell
+// amount is the quote's plus its fee, the partner fee and the guards'
+// slippage; the order is refused if that exceeds maxSell, or if the quote
+// doesn't pass guards. Expires like SellUSDC.
+func (c *Client) BuyWithUSDC(ctx context.Context, chain string, signer wallet.Signer, buyToken string, buyAmount *big.Int, maxSell *big.Int, receiver common.Address, guards Guards) (*OrderResult, error) {
+	cc, ok := SupportedChains[chain]
 	if !ok {
-		return nil, fmt.Errorf("invalid buyAmount: %s", qr.Quote.BuyAmount)
+		return nil, fmt.Errorf("CoW does not support %s", chain)
+	}
+	if buyAmount == nil || buyAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("buy order needs a positive buy amount")
 	}
-	// Reduce by 1%: buyAmount * 99 / 100
-	buyAmt.Mul(buyAmt, big.NewInt(99))
-	buyAmt.Div(buyAmt, big.NewInt(100))
-	qr.Quote.BuyAmount = buyAmt.String()
 
-	// Sign order
-	sig, err := c.SignOrder(cc, qr, privateKey)
+	validTo := time.Now().Add(marketOrderValidity).Truncate(time.Second)
+	appData, appHash, partner, approve, err := c.orderAppData(ctx, chain, cc, signer, maxSell, validTo)
 	if err != nil {
-		return nil, fmt.Errorf("signing order: %w", err)
+		return nil, err
+	}
+
+	qr, err := c.quote(chain, QuoteRequest{
+		SellToken:         cc.USDCAddress,
+		BuyToken:          buyToken,
+		Receiver:          receiver.Hex(),
+		BuyAmountAfterFee: buyAmount.String(),
+		Kind:              "buy",
+		From:              signer.Address().Hex(),
+		AppData:           appData,
+		AppDataHash:       appHash,
+		SigningScheme:     signingScheme(signer),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting quote: %w", err)
+	}
+	if err := guards.check(ctx, chain, qr); err != nil {
+		return nil, err
+	}
+
+	sellAmt, ok := new(big.Int).SetString(qr.Quote.SellAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid sellAmount: %s", qr.Quote.SellAmount)
+	}
+	if fee, ok := new(big.Int).SetString(qr.Quote.FeeAmount, 10); ok {
+		sellAmt.Add(sellAmt, fee)
 	}
+	// Buy orders pay the partner fee in the sell token
+	if partner.FeeBps > 0 {
+		sellAmt.Mul(sellAmt, big.NewInt(int64(10000+partner.FeeBps)))
+		sellAmt.Div(sellAmt, big.NewInt(10000))
+	}
+	// Raise by the slippage: sellAmount * (10000 + bps) / 10000
+	sellAmt.Mul(sellAmt, big.NewInt(10000+guards.slippageBps()))
+	sellAmt.Div(sellAmt, big.NewInt(10000))
+	if sellAmt.Cmp(maxSell) > 0 {
+		return nil, fmt.Errorf("buying %s costs up to %s USDC units, more than the %s allowed", buyAmount, sellAmt, maxSell)
+	}
+	qr.Quote.SellAmount = sellAmt.String()
+	qr.Quote.BuyAmount = buyAmount.String()
+
+	return c.submit(ctx, chain, cc, signer, qr, appData, approve, validTo)
+}
 
-	// Submit order — pass full appData JSON so CoW registers the permit hook
-	orderUID, err := c.SubmitOrder(chain, qr, sig, addr, appData)
+// orderAppData builds the appData of a USDC order: a permit pre-hook when
+// the vault relayer's allowance doesn't cover amount, and the partner
+// settings. Without either, appData and its hash are empty, so the quote
+// uses the defaults. A smart account can't sign a permit, so for one it
+// reports that an approval has to be sent with the presignature instead.
+func (c *Client) orderAppData(ctx context.Context, chain string, cc ChainConfig, signer wallet.Signer, amount *big.Int, validTo time.Time) (appData, appHash string, partner Partner, approve bool, err error) {
+	addr := signer.Address()
+	sellToken := common.HexToAddress(cc.USDCAddress)
+
+	// Check if we need a permit (allowance < amount)
+	var pre []permitHook
+	needs, err := c.needsPermit(ctx, chain, sellToken, addr, amount)
 	if err != nil {
-		return nil, fmt.Errorf("submitting order: %w", err)
+		return "", "", Partner{}, false, fmt.Errorf("checking permit need: %w", err)
 	}
 
-	log.Printf("CoW gas refill order submitted on %s: %s (expires in 3m)", cc.NativeSymbol, orderUID)
+	_, presign := signer.(swaps.CallSender)
+	if needs && !presign {
+		// Use max uint256 for permit value so we don't need to permit again next time
+		hook, err := c.signPermit(ctx, chain, cc, signer, maxUint256(), validTo)
+		if err != nil {
+			return "", "", Partner{}, false, fmt.Errorf("signing permit: %w", err)
+		}
+		pre = append(pre, hook)
+	}
 
-	return &GasRefillResult{
+	partner = c.getPartner()
+	appData, appHash, err = buildAppData(pre, partner)
+	if err != nil {
+		return "", "", Partner{}, false, err
+	}
+	return appData, appHash, partner, needs && presign, nil
+}
+
+func maxUint256() *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+}
+
+// signingScheme is the scheme the signer's orders are placed under.
+func signingScheme(signer wallet.Signer) string {
+	if _, ok := signer.(swaps.CallSender); ok {
+		return "presign"
+	}
+	return "eip712"
+}
+
+// submit signs a quoted order, expiring at validTo, and submits it with the
+// full appData so CoW registers the permit hook and partner fee. A smart
+// account's order is presigned instead, approving the vault relayer in the
+// same batch if approve is set.
+func (c *Client) submit(ctx context.Context, chain string, cc ChainConfig, signer wallet.Signer, qr *QuoteResult, appData string, approve bool, validTo time.Time) (*OrderResult, error) {
+	qr.Quote.ValidTo = uint32(validTo.Unix())
+
+	var orderUID string
+	if sender, ok := signer.(swaps.CallSender); ok {
+		uid, err := c.submitOrder(chain, qr, "presign", sender.Address().Hex(), sender.Address(), appData)
+		if err != nil {
+			return nil, fmt.Errorf("submitting order: %w", err)
+		}
+		if err := c.presign(ctx, chain, cc, sender, uid, approve); err != nil {
+			// Unsigned, the order just expires
+			return nil, fmt.Errorf("presigning order %s: %w", uid, err)
+		}
+		orderUID = uid
+	} else {
+		sig, err := c.SignOrder(ctx, cc, qr, signer)
+		if err != nil {
+			return nil, fmt.Errorf("signing order: %w", err)
+		}
+		orderUID, err = c.SubmitOrder(chain, qr, sig, signer.Address(), appData)
+		if err != nil {
+			return nil, fmt.Errorf("submitting order: %w", err)
+		}
+	}
+
+	log.Printf("CoW %s order submitted on %s: %s (expires in %s)", qr.Quote.Kind, cc.NativeSymbol, orderUID, time.Until(validTo).Round(time.Second))
+
+	return &OrderResult{
 		Chain:      chain,
 		OrderUID:   orderUID,
 		Status:     "open",
+		Kind:       qr.Quote.Kind,
 		SellAmount: qr.Quote.SellAmount,
 		BuyAmount:  qr.Quote.BuyAmount,
+		ValidTo:    validTo,
+
+		PartiallyFillable: qr.Quote.PartiallyFillable,
 	}, nil
 }
+
+// presign approves a submitted order on-chain with setPreSignature from the
+// smart account that owns it, after a max USDC approval of the vault relayer
+// if approve is set. The order stays "presignaturePending" until the batch
+// is mined.
+func (c *Client) presign(ctx context.Context, chain string, cc ChainConfig, sender swaps.CallSender, orderUID string, approve bool) error {
+	uid, err := hexutil.Decode(orderUID)
+	if err != nil {
+		return fmt.Errorf("invalid order UID: %w", err)
+	}
+
+	var calls []swaps.Call
+	if approve {
+		data, err := erc20ABI.Pack("approve", common.HexToAddress(VaultRelayer), maxUint256())
+		if err != nil {
+			return err
+		}
+		calls = append(calls, swaps.Call{To: common.HexToAddress(cc.USDCAddress), Value: big.NewInt(0), Data: data})
+	}
+	data, err := settlementABI.Pack("setPreSignature", uid, true)
+	if err != nil {
+		return err
+	}
+	calls = append(calls, swaps.Call{To: common.HexToAddress(SettlementContract), Value: big.NewInt(0), Data: data})
+
+	txHash, err := sender.SendCalls(ctx, chain, calls)
+	if err != nil {
+		return err
+	}
+	log.Printf("CoW order %s presigned on %s in %s", orderUID, chain, txHash)
+	return nil
+}
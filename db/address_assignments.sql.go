@@ -9,31 +9,66 @@ import (
 	"context"
 )
 
+const countAddressAssignments = `-- name: CountAddressAssignments :one
+SELECT COUNT(*) FROM address_assignments
+`
+
+func (q *Queries) CountAddressAssignments(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAddressAssignments)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAddressAssignment = `-- name: CreateAddressAssignment :one
-INSERT INTO address_assignments (assigned_to_id, assigned_to_type)
-VALUES (?, ?)
-RETURNING id, assigned_to_id, assigned_to_type, created_at
+INSERT INTO address_assignments (assigned_to_id, assigned_to_type, pool)
+VALUES (?, ?, ?)
+RETURNING id, assigned_to_id, assigned_to_type, created_at, pool
 `
 
 type CreateAddressAssignmentParams struct {
 	AssignedToID   int64
 	AssignedToType string
+	Pool           string
 }
 
 func (q *Queries) CreateAddressAssignment(ctx context.Context, arg CreateAddressAssignmentParams) (AddressAssignment, error) {
-	row := q.db.QueryRowContext(ctx, createAddressAssignment, arg.AssignedToID, arg.AssignedToType)
+	row := q.db.QueryRowContext(ctx, createAddressAssignment, arg.AssignedToID, arg.AssignedToType, arg.Pool)
 	var i AddressAssignment
 	err := row.Scan(
 		&i.ID,
 		&i.AssignedToID,
 		&i.AssignedToType,
 		&i.CreatedAt,
+		&i.Pool,
 	)
 	return i, err
 }
 
+const createAddressAssignmentWithID = `-- name: CreateAddressAssignmentWithID :exec
+INSERT INTO address_assignments (id, assigned_to_id, assigned_to_type, pool)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateAddressAssignmentWithIDParams struct {
+	ID             int64
+	AssignedToID   int64
+	AssignedToType string
+	Pool           string
+}
+
+func (q *Queries) CreateAddressAssignmentWithID(ctx context.Context, arg CreateAddressAssignmentWithIDParams) error {
+	_, err := q.db.ExecContext(ctx, createAddressAssignmentWithID,
+		arg.ID,
+		arg.AssignedToID,
+		arg.AssignedToType,
+		arg.Pool,
+	)
+	return err
+}
+
 const getAddressAssignment = `-- name: GetAddressAssignment :one
-SELECT id, assigned_to_id, assigned_to_type, created_at
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
 FROM address_assignments
 WHERE assigned_to_id = ? AND assigned_to_type = ?
 `
@@ -51,12 +86,32 @@ func (q *Queries) GetAddressAssignment(ctx context.Context, arg GetAddressAssign
 		&i.AssignedToID,
 		&i.AssignedToType,
 		&i.CreatedAt,
+		&i.Pool,
+	)
+	return i, err
+}
+
+const getAddressAssignmentByID = `-- name: GetAddressAssignmentByID :one
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
+FROM address_assignments
+WHERE id = ?
+`
+
+func (q *Queries) GetAddressAssignmentByID(ctx context.Context, id int64) (AddressAssignment, error) {
+	row := q.db.QueryRowContext(ctx, getAddressAssignmentByID, id)
+	var i AddressAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.AssignedToID,
+		&i.AssignedToType,
+		&i.CreatedAt,
+		&i.Pool,
 	)
 	return i, err
 }
 
 const listAddressAssignments = `-- name: ListAddressAssignments :many
-SELECT id, assigned_to_id, assigned_to_type, created_at
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
 FROM address_assignments
 ORDER BY id
 `
@@ -75,6 +130,7 @@ func (q *Queries) ListAddressAssignments(ctx context.Context) ([]AddressAssignme
 			&i.AssignedToID,
 			&i.AssignedToType,
 			&i.CreatedAt,
+			&i.Pool,
 		); err != nil {
 			return nil, err
 		}
@@ -88,3 +144,17 @@ func (q *Queries) ListAddressAssignments(ctx context.Context) ([]AddressAssignme
 	}
 	return items, nil
 }
+
+const updateAddressAssignmentPool = `-- name: UpdateAddressAssignmentPool :exec
+UPDATE address_assignments SET pool = ? WHERE id = ?
+`
+
+type UpdateAddressAssignmentPoolParams struct {
+	Pool string
+	ID   int64
+}
+
+func (q *Queries) UpdateAddressAssignmentPool(ctx context.Context, arg UpdateAddressAssignmentPoolParams) error {
+	_, err := q.db.ExecContext(ctx, updateAddressAssignmentPool, arg.Pool, arg.ID)
+	return err
+}
@@ -12,7 +12,7 @@ import (
 
 const countAPIRequests = `-- name: CountAPIRequests :one
 SELECT COUNT(*) FROM api_requests
-WHERE CASE WHEN ?1 = '' THEN 1 ELSE (
+WHERE (?1 = '' OR (
     provider LIKE '%' || ?1 || '%'
     OR method LIKE '%' || ?1 || '%'
     OR url LIKE '%' || ?1 || '%'
@@ -22,7 +22,7 @@ WHERE CASE WHEN ?1 = '' THEN 1 ELSE (
     OR COALESCE(response_headers, '') LIKE '%' || ?1 || '%'
     OR COALESCE(response_body, '') LIKE '%' || ?1 || '%'
     OR COALESCE(error, '') LIKE '%' || ?1 || '%'
-) END
+))
 `
 
 func (q *Queries) CountAPIRequests(ctx context.Context, search interface{}) (int64, error) {
@@ -32,6 +32,32 @@ func (q *Queries) CountAPIRequests(ctx context.Context, search interface{}) (int
 	return count, err
 }
 
+const deleteAPIRequestsBefore = `-- name: DeleteAPIRequestsBefore :execrows
+DELETE FROM api_requests WHERE created_at < datetime(?1)
+`
+
+func (q *Queries) DeleteAPIRequestsBefore(ctx context.Context, createdBefore interface{}) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIRequestsBefore, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteAPIRequestsBeyond = `-- name: DeleteAPIRequestsBeyond :execrows
+DELETE FROM api_requests WHERE id < (
+    SELECT MIN(id) FROM (SELECT id FROM api_requests ORDER BY id DESC LIMIT ?1) AS newest
+)
+`
+
+func (q *Queries) DeleteAPIRequestsBeyond(ctx context.Context, keep int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIRequestsBeyond, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAPIRequest = `-- name: GetAPIRequest :one
 SELECT id, provider, method, url, request_headers, request_body,
        response_status, response_headers, response_body, duration_ms, error, created_at
@@ -58,6 +84,39 @@ func (q *Queries) GetAPIRequest(ctx context.Context, id int64) (ApiRequest, erro
 	return i, err
 }
 
+const getLatestAPIRequestFor = `-- name: GetLatestAPIRequestFor :one
+SELECT id, provider, method, url, request_headers, request_body,
+       response_status, response_headers, response_body, duration_ms, error, created_at
+FROM api_requests
+WHERE provider = ?1 AND url LIKE '%' || ?2 || '%'
+ORDER BY created_at DESC, id DESC LIMIT 1
+`
+
+type GetLatestAPIRequestForParams struct {
+	Provider string
+	Ref      interface{}
+}
+
+func (q *Queries) GetLatestAPIRequestFor(ctx context.Context, arg GetLatestAPIRequestForParams) (ApiRequest, error) {
+	row := q.db.QueryRowContext(ctx, getLatestAPIRequestFor, arg.Provider, arg.Ref)
+	var i ApiRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Provider,
+		&i.Method,
+		&i.Url,
+		&i.RequestHeaders,
+		&i.RequestBody,
+		&i.ResponseStatus,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.DurationMs,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertAPIRequest = `-- name: InsertAPIRequest :exec
 INSERT INTO api_requests (provider, method, url, request_headers, request_body,
     response_status, response_headers, response_body, duration_ms, error)
@@ -93,11 +152,59 @@ func (q *Queries) InsertAPIRequest(ctx context.Context, arg InsertAPIRequestPara
 	return err
 }
 
+const providerCallStats = `-- name: ProviderCallStats :many
+SELECT provider,
+       CAST(COUNT(CASE WHEN created_at >= datetime(?1) THEN 1 END) AS INTEGER) AS calls,
+       CAST(COUNT(CASE WHEN created_at >= datetime(?1)
+                        AND (error IS NOT NULL OR response_status >= 500) THEN 1 END) AS INTEGER) AS failures,
+       CAST(COALESCE(AVG(CASE WHEN created_at >= datetime(?1) THEN duration_ms END), 0) AS REAL) AS avg_duration_ms,
+       COALESCE(CAST(MAX(CASE WHEN error IS NULL AND response_status < 500 THEN created_at END) AS TEXT), '') AS last_success
+FROM api_requests
+GROUP BY provider ORDER BY provider
+`
+
+type ProviderCallStatsRow struct {
+	Provider      string
+	Calls         int64
+	Failures      int64
+	AvgDurationMs float64
+	LastSuccess   string
+}
+
+func (q *Queries) ProviderCallStats(ctx context.Context, createdFrom interface{}) ([]ProviderCallStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, providerCallStats, createdFrom)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProviderCallStatsRow
+	for rows.Next() {
+		var i ProviderCallStatsRow
+		if err := rows.Scan(
+			&i.Provider,
+			&i.Calls,
+			&i.Failures,
+			&i.AvgDurationMs,
+			&i.LastSuccess,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchAPIRequests = `-- name: SearchAPIRequests :many
 SELECT id, provider, method, url, request_headers, request_body,
        response_status, response_headers, response_body, duration_ms, error, created_at
 FROM api_requests
-WHERE CASE WHEN ?1 = '' THEN 1 ELSE (
+WHERE (?1 = '' OR (
     provider LIKE '%' || ?1 || '%'
     OR method LIKE '%' || ?1 || '%'
     OR url LIKE '%' || ?1 || '%'
@@ -107,7 +214,7 @@ WHERE CASE WHEN ?1 = '' THEN 1 ELSE (
     OR COALESCE(response_headers, '') LIKE '%' || ?1 || '%'
     OR COALESCE(response_body, '') LIKE '%' || ?1 || '%'
     OR COALESCE(error, '') LIKE '%' || ?1 || '%'
-) END
+))
 ORDER BY created_at DESC LIMIT ?3 OFFSET ?2
 `
 
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Backup copies the SQLite database to a new file at dest with SQLite's
+// online backup API, which takes a consistent snapshot without stopping
+// other writers for longer than the copy. The copy of an encrypted database
+// is encrypted under the same key.
+func (s *Store) Backup(ctx context.Context, dest string) error {
+	var destDB *sql.DB
+	if s.key != "" {
+		destDB = openSQLCipher(dest, s.key)
+	} else {
+		var err error
+		if destDB, err = sql.Open("sqlite3", dest); err != nil {
+			return fmt.Errorf("opening backup file: %w", err)
+		}
+	}
+	defer destDB.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("opening backup file: %w", err)
+	}
+	defer destConn.Close()
+	srcConn, err := s.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting database connection: %w", err)
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			src, ok := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("backups need a SQLite database")
+			}
+			backup, err := destDriver.(*sqlite3.SQLiteConn).Backup("main", src, "main")
+			if err != nil {
+				return fmt.Errorf("starting backup: %w", err)
+			}
+			// Step reports not done, without an error, while the database
+			// is busy; try again shortly
+			for {
+				done, err := backup.Step(-1)
+				if err != nil {
+					backup.Close()
+					return fmt.Errorf("copying database: %w", err)
+				}
+				if done {
+					return backup.Finish()
+				}
+				select {
+				case <-ctx.Done():
+					backup.Close()
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+		})
+	})
+}
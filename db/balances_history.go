@@ -0,0 +1,19 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// InsertBalanceSnapshot stores one balance snapshot, a row per wallet and
+// chain, in a single transaction so the history never holds part of one.
+func (s *Store) InsertBalanceSnapshot(ctx context.Context, rows []InsertBalanceHistoryParams) error {
+	return s.inTx(ctx, func(q *Queries) error {
+		for _, row := range rows {
+			if err := q.InsertBalanceHistory(ctx, row); err != nil {
+				return fmt.Errorf("recording %s balance of %s: %w", row.Chain, row.Address, err)
+			}
+		}
+		return nil
+	})
+}
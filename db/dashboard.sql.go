@@ -32,6 +32,17 @@ func (q *Queries) CountDistinctProviders(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countRefundedTopups = `-- name: CountRefundedTopups :one
+SELECT COUNT(*) FROM topups WHERE status = 'refunded'
+`
+
+func (q *Queries) CountRefundedTopups(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRefundedTopups)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countTopups = `-- name: CountTopups :one
 SELECT COUNT(*) FROM topups
 `
@@ -54,6 +65,99 @@ func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
 	return column_1, err
 }
 
+const feesByPeriod = `-- name: FeesByPeriod :many
+SELECT CAST(CASE ?1
+         WHEN 'week' THEN DATE(created_at, 'weekday 0', '-6 days')
+         WHEN 'month' THEN DATE(created_at, 'start of month')
+         ELSE DATE(created_at) END AS TEXT) as period,
+       chain, COALESCE(SUM(CAST(gas_cost AS REAL)), 0) as total_wei, COUNT(*) as tx_count
+FROM topup_transactions
+WHERE gas_cost != '' AND created_at >= datetime(?2) AND created_at < datetime(?3)
+GROUP BY period, chain ORDER BY period, chain
+`
+
+type FeesByPeriodParams struct {
+	Grouping    interface{}
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
+type FeesByPeriodRow struct {
+	Period   string
+	Chain    string
+	TotalWei interface{}
+	TxCount  int64
+}
+
+func (q *Queries) FeesByPeriod(ctx context.Context, arg FeesByPeriodParams) ([]FeesByPeriodRow, error) {
+	rows, err := q.db.QueryContext(ctx, feesByPeriod, arg.Grouping, arg.CreatedFrom, arg.CreatedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeesByPeriodRow
+	for rows.Next() {
+		var i FeesByPeriodRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Chain,
+			&i.TotalWei,
+			&i.TxCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const gasCostByChain = `-- name: GasCostByChain :many
+SELECT chain, COALESCE(SUM(CAST(gas_cost AS REAL)), 0) as total_wei, COUNT(*) as tx_count
+FROM topup_transactions
+WHERE gas_cost != '' AND created_at >= datetime(?1) AND created_at < datetime(?2)
+GROUP BY chain ORDER BY chain
+`
+
+type GasCostByChainParams struct {
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
+type GasCostByChainRow struct {
+	Chain    string
+	TotalWei interface{}
+	TxCount  int64
+}
+
+func (q *Queries) GasCostByChain(ctx context.Context, arg GasCostByChainParams) ([]GasCostByChainRow, error) {
+	rows, err := q.db.QueryContext(ctx, gasCostByChain, arg.CreatedFrom, arg.CreatedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GasCostByChainRow
+	for rows.Next() {
+		var i GasCostByChainRow
+		if err := rows.Scan(&i.Chain, &i.TotalWei, &i.TxCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTopupsByUserID = `-- name: GetTopupsByUserID :many
 SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
        t.tx_hash, t.status, t.created_at
@@ -109,15 +213,33 @@ func (q *Queries) GetTopupsByUserID(ctx context.Context, userID int64) ([]GetTop
 
 const listRecentTopups = `-- name: ListRecentTopups :many
 SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
-       t.tx_hash, t.status, t.created_at,
+       t.tx_hash, t.status, t.created_at, t.actual_output, t.dest_tx_hash, t.resolution_note,
        q.from_asset, q.to_asset, q.destination, q.input_amount_usd, q.expected_output
 FROM topups t JOIN quotes q ON t.quote_id = q.id
-ORDER BY t.created_at DESC LIMIT ? OFFSET ?
+WHERE (?1 = '' OR t.status = ?1)
+  AND (?2 = '' OR t.provider = ?2)
+  AND (?3 = '' OR t.from_chain = ?3)
+  AND (?4 = 0 OR t.user_id = ?4)
+  AND t.created_at >= datetime(?5)
+  AND t.created_at < datetime(?6)
+ORDER BY
+  CASE WHEN ?7 = 'oldest' THEN t.created_at END ASC,
+  CASE WHEN ?7 = 'usd_desc' THEN q.input_amount_usd END DESC,
+  CASE WHEN ?7 = 'usd_asc' THEN q.input_amount_usd END ASC,
+  t.created_at DESC, t.id DESC
+LIMIT ?8 OFFSET ?9
 `
 
 type ListRecentTopupsParams struct {
-	Limit  int64
-	Offset int64
+	Status      interface{}
+	Provider    interface{}
+	FromChain   interface{}
+	UserID      interface{}
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+	Sort        interface{}
+	PageSize    int64
+	PageOffset  int64
 }
 
 type ListRecentTopupsRow struct {
@@ -131,6 +253,9 @@ type ListRecentTopupsRow struct {
 	TxHash         string
 	Status         string
 	CreatedAt      time.Time
+	ActualOutput   string
+	DestTxHash     string
+	ResolutionNote string
 	FromAsset      string
 	ToAsset        string
 	Destination    string
@@ -139,7 +264,17 @@ type ListRecentTopupsRow struct {
 }
 
 func (q *Queries) ListRecentTopups(ctx context.Context, arg ListRecentTopupsParams) ([]ListRecentTopupsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRecentTopups, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listRecentTopups,
+		arg.Status,
+		arg.Provider,
+		arg.FromChain,
+		arg.UserID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Sort,
+		arg.PageSize,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +282,75 @@ func (q *Queries) ListRecentTopups(ctx context.Context, arg ListRecentTopupsPara
 	var items []ListRecentTopupsRow
 	for rows.Next() {
 		var i ListRecentTopupsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShortID,
+			&i.Type,
+			&i.QuoteID,
+			&i.UserID,
+			&i.Provider,
+			&i.FromChain,
+			&i.TxHash,
+			&i.Status,
+			&i.CreatedAt,
+			&i.ActualOutput,
+			&i.DestTxHash,
+			&i.ResolutionNote,
+			&i.FromAsset,
+			&i.ToAsset,
+			&i.Destination,
+			&i.InputAmountUsd,
+			&i.ExpectedOutput,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStalledTopups = `-- name: ListStalledTopups :many
+SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
+       t.tx_hash, t.status, t.created_at,
+       q.from_asset, q.to_asset, q.destination, q.input_amount_usd, q.expected_output
+FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.status = 'stalled'
+ORDER BY t.created_at
+`
+
+type ListStalledTopupsRow struct {
+	ID             int64
+	ShortID        string
+	Type           string
+	QuoteID        int64
+	UserID         int64
+	Provider       string
+	FromChain      string
+	TxHash         string
+	Status         string
+	CreatedAt      time.Time
+	FromAsset      string
+	ToAsset        string
+	Destination    string
+	InputAmountUsd float64
+	ExpectedOutput string
+}
+
+func (q *Queries) ListStalledTopups(ctx context.Context) ([]ListStalledTopupsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStalledTopups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStalledTopupsRow
+	for rows.Next() {
+		var i ListStalledTopupsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.ShortID,
@@ -178,7 +382,7 @@ func (q *Queries) ListRecentTopups(ctx context.Context, arg ListRecentTopupsPara
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, telegram_id, username, created_at FROM users ORDER BY id
+SELECT id, telegram_id, username, created_at, deleted_at FROM users ORDER BY id
 `
 
 func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
@@ -195,6 +399,7 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.TelegramID,
 			&i.Username,
 			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -209,39 +414,40 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const totalVolumeUSD = `-- name: TotalVolumeUSD :one
-SELECT COALESCE(SUM(q.input_amount_usd), 0) FROM topups t JOIN quotes q ON t.quote_id = q.id
+const surplusByChain = `-- name: SurplusByChain :many
+SELECT chain, kind, COALESCE(SUM(CAST(surplus AS REAL)), 0) as total_surplus, COUNT(*) as order_count
+FROM gas_refills
+WHERE surplus != '0' AND created_at >= datetime(?1) AND created_at < datetime(?2)
+GROUP BY chain, kind ORDER BY chain, kind
 `
 
-func (q *Queries) TotalVolumeUSD(ctx context.Context) (interface{}, error) {
-	row := q.db.QueryRowContext(ctx, totalVolumeUSD)
-	var coalesce interface{}
-	err := row.Scan(&coalesce)
-	return coalesce, err
+type SurplusByChainParams struct {
+	CreatedFrom interface{}
+	CreatedTo   interface{}
 }
 
-const volumeByDay = `-- name: VolumeByDay :many
-SELECT DATE(t.created_at) as day, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
-FROM topups t JOIN quotes q ON t.quote_id = q.id
-GROUP BY DATE(t.created_at) ORDER BY day
-`
-
-type VolumeByDayRow struct {
-	Day      interface{}
-	TotalUsd interface{}
-	TxCount  int64
+type SurplusByChainRow struct {
+	Chain        string
+	Kind         string
+	TotalSurplus interface{}
+	OrderCount   int64
 }
 
-func (q *Queries) VolumeByDay(ctx context.Context) ([]VolumeByDayRow, error) {
-	rows, err := q.db.QueryContext(ctx, volumeByDay)
+func (q *Queries) SurplusByChain(ctx context.Context, arg SurplusByChainParams) ([]SurplusByChainRow, error) {
+	rows, err := q.db.QueryContext(ctx, surplusByChain, arg.CreatedFrom, arg.CreatedTo)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []VolumeByDayRow
+	var items []SurplusByChainRow
 	for rows.Next() {
-		var i VolumeByDayRow
-		if err := rows.Scan(&i.Day, &i.TotalUsd, &i.TxCount); err != nil {
+		var i SurplusByChainRow
+		if err := rows.Scan(
+			&i.Chain,
+			&i.Kind,
+			&i.TotalSurplus,
+			&i.OrderCount,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -255,20 +461,37 @@ func (q *Queries) VolumeByDay(ctx context.Context) ([]VolumeByDayRow, error) {
 	return items, nil
 }
 
+const totalVolumeUSD = `-- name: TotalVolumeUSD :one
+SELECT COALESCE(SUM(q.input_amount_usd), 0) FROM topups t JOIN quotes q ON t.quote_id = q.id
+`
+
+func (q *Queries) TotalVolumeUSD(ctx context.Context) (interface{}, error) {
+	row := q.db.QueryRowContext(ctx, totalVolumeUSD)
+	var coalesce interface{}
+	err := row.Scan(&coalesce)
+	return coalesce, err
+}
+
 const volumeByFromChain = `-- name: VolumeByFromChain :many
 SELECT t.from_chain, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(?1) AND t.created_at < datetime(?2)
 GROUP BY t.from_chain ORDER BY total_usd DESC
 `
 
+type VolumeByFromChainParams struct {
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
 type VolumeByFromChainRow struct {
 	FromChain string
 	TotalUsd  interface{}
 	TxCount   int64
 }
 
-func (q *Queries) VolumeByFromChain(ctx context.Context) ([]VolumeByFromChainRow, error) {
-	rows, err := q.db.QueryContext(ctx, volumeByFromChain)
+func (q *Queries) VolumeByFromChain(ctx context.Context, arg VolumeByFromChainParams) ([]VolumeByFromChainRow, error) {
+	rows, err := q.db.QueryContext(ctx, volumeByFromChain, arg.CreatedFrom, arg.CreatedTo)
 	if err != nil {
 		return nil, err
 	}
@@ -290,20 +513,72 @@ func (q *Queries) VolumeByFromChain(ctx context.Context) ([]VolumeByFromChainRow
 	return items, nil
 }
 
+const volumeByPeriod = `-- name: VolumeByPeriod :many
+SELECT CAST(CASE ?1
+         WHEN 'week' THEN DATE(t.created_at, 'weekday 0', '-6 days')
+         WHEN 'month' THEN DATE(t.created_at, 'start of month')
+         ELSE DATE(t.created_at) END AS TEXT) as period,
+       COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
+FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(?2) AND t.created_at < datetime(?3)
+GROUP BY period ORDER BY period
+`
+
+type VolumeByPeriodParams struct {
+	Grouping    interface{}
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
+type VolumeByPeriodRow struct {
+	Period   string
+	TotalUsd interface{}
+	TxCount  int64
+}
+
+func (q *Queries) VolumeByPeriod(ctx context.Context, arg VolumeByPeriodParams) ([]VolumeByPeriodRow, error) {
+	rows, err := q.db.QueryContext(ctx, volumeByPeriod, arg.Grouping, arg.CreatedFrom, arg.CreatedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VolumeByPeriodRow
+	for rows.Next() {
+		var i VolumeByPeriodRow
+		if err := rows.Scan(&i.Period, &i.TotalUsd, &i.TxCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const volumeByProvider = `-- name: VolumeByProvider :many
 SELECT t.provider, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(?1) AND t.created_at < datetime(?2)
 GROUP BY t.provider ORDER BY total_usd DESC
 `
 
+type VolumeByProviderParams struct {
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
 type VolumeByProviderRow struct {
 	Provider string
 	TotalUsd interface{}
 	TxCount  int64
 }
 
-func (q *Queries) VolumeByProvider(ctx context.Context) ([]VolumeByProviderRow, error) {
-	rows, err := q.db.QueryContext(ctx, volumeByProvider)
+func (q *Queries) VolumeByProvider(ctx context.Context, arg VolumeByProviderParams) ([]VolumeByProviderRow, error) {
+	rows, err := q.db.QueryContext(ctx, volumeByProvider, arg.CreatedFrom, arg.CreatedTo)
 	if err != nil {
 		return nil, err
 	}
@@ -328,17 +603,23 @@ func (q *Queries) VolumeByProvider(ctx context.Context) ([]VolumeByProviderRow,
 const volumeByToAsset = `-- name: VolumeByToAsset :many
 SELECT q.to_asset, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(?1) AND t.created_at < datetime(?2)
 GROUP BY q.to_asset ORDER BY total_usd DESC
 `
 
+type VolumeByToAssetParams struct {
+	CreatedFrom interface{}
+	CreatedTo   interface{}
+}
+
 type VolumeByToAssetRow struct {
 	ToAsset  string
 	TotalUsd interface{}
 	TxCount  int64
 }
 
-func (q *Queries) VolumeByToAsset(ctx context.Context) ([]VolumeByToAssetRow, error) {
-	rows, err := q.db.QueryContext(ctx, volumeByToAsset)
+func (q *Queries) VolumeByToAsset(ctx context.Context, arg VolumeByToAssetParams) ([]VolumeByToAssetRow, error) {
+	rows, err := q.db.QueryContext(ctx, volumeByToAsset, arg.CreatedFrom, arg.CreatedTo)
 	if err != nil {
 		return nil, err
 	}
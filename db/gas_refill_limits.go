@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrGasRefillLimit is returned by CheckGasRefillLimits when a wallet has
+// to wait for its next gas refill on a chain.
+var ErrGasRefillLimit = errors.New("gas refills are limited")
+
+// CheckGasRefillLimits returns an error wrapping ErrGasRefillLimit if a gas
+// refill was attempted for the wallet on chain less than minInterval ago,
+// going by gas_refill_cooldowns, or if it already had maxPerDay refills in
+// the last 24 hours. maxPerDay 0 is no cap. Both survive restarts, so a
+// wallet that keeps burning gas gets a bounded number of refill orders.
+func (s *Store) CheckGasRefillLimits(ctx context.Context, chain, address string, minInterval time.Duration, maxPerDay int) error {
+	last, err := s.GetGasRefillCooldown(ctx, GetGasRefillCooldownParams{Chain: chain, WalletAddress: address})
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("checking gas refill cooldown: %w", err)
+	}
+	if err == nil {
+		if ago := time.Since(last); ago < minInterval {
+			return fmt.Errorf("%w: the last one was %d minutes ago, the next can be in %d minutes",
+				ErrGasRefillLimit, int(ago.Minutes()), int((minInterval-ago).Minutes())+1)
+		}
+	}
+
+	if maxPerDay <= 0 {
+		return nil
+	}
+	n, err := s.CountGasRefillsSince(ctx, CountGasRefillsSinceParams{
+		Chain:         chain,
+		WalletAddress: address,
+		CreatedFrom:   time.Now().UTC().Add(-24 * time.Hour),
+	})
+	if err != nil {
+		return fmt.Errorf("counting gas refills: %w", err)
+	}
+	if n >= int64(maxPerDay) {
+		return fmt.Errorf("%w: this wallet already had %d in the last 24 hours", ErrGasRefillLimit, n)
+	}
+	return nil
+}
@@ -7,23 +7,162 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
-const insertGasRefill = `-- name: InsertGasRefill :one
-INSERT INTO gas_refills (chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id
+const anonymizeGasRefills = `-- name: AnonymizeGasRefills :exec
+UPDATE gas_refills SET
+    user_id = CASE WHEN user_id = ?1 THEN ?2 ELSE user_id END,
+    chat_id = CASE WHEN chat_id = ?1 THEN ?2 ELSE chat_id END
+WHERE user_id = ?1 OR chat_id = ?1
 `
 
-type InsertGasRefillParams struct {
+type AnonymizeGasRefillsParams struct {
+	TelegramID int64
+	Tombstone  interface{}
+}
+
+func (q *Queries) AnonymizeGasRefills(ctx context.Context, arg AnonymizeGasRefillsParams) error {
+	_, err := q.db.ExecContext(ctx, anonymizeGasRefills, arg.TelegramID, arg.Tombstone)
+	return err
+}
+
+const cancelGasRefill = `-- name: CancelGasRefill :execrows
+UPDATE gas_refills SET status = 'cancelled', resolution_note = ? WHERE id = ? AND status IN ('open', 'cancelled')
+`
+
+type CancelGasRefillParams struct {
+	ResolutionNote string
+	ID             int64
+}
+
+func (q *Queries) CancelGasRefill(ctx context.Context, arg CancelGasRefillParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelGasRefill, arg.ResolutionNote, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countGasRefillsBySale = `-- name: CountGasRefillsBySale :one
+SELECT COUNT(*) FROM gas_refills
+WHERE chain = ?1 AND wallet_address = ?2 AND (sell_amount = ?3 OR executed_sell_amount = ?3)
+`
+
+type CountGasRefillsBySaleParams struct {
 	Chain         string
-	OrderUid      string
 	WalletAddress string
 	SellAmount    string
-	BuyAmount     string
-	Status        string
-	UserID        int64
-	ChatID        int64
+}
+
+func (q *Queries) CountGasRefillsBySale(ctx context.Context, arg CountGasRefillsBySaleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGasRefillsBySale, arg.Chain, arg.WalletAddress, arg.SellAmount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countGasRefillsSince = `-- name: CountGasRefillsSince :one
+SELECT COUNT(*) FROM gas_refills
+WHERE chain = ?1 AND wallet_address = ?2 AND attempt = 1 AND order_type = 'market'
+  AND created_at >= datetime(?3)
+`
+
+type CountGasRefillsSinceParams struct {
+	Chain         string
+	WalletAddress string
+	CreatedFrom   interface{}
+}
+
+func (q *Queries) CountGasRefillsSince(ctx context.Context, arg CountGasRefillsSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGasRefillsSince, arg.Chain, arg.WalletAddress, arg.CreatedFrom)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOpenGasRefillsByUser = `-- name: CountOpenGasRefillsByUser :one
+SELECT COUNT(*) FROM gas_refills WHERE user_id = ? AND status = 'open'
+`
+
+func (q *Queries) CountOpenGasRefillsByUser(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenGasRefillsByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getGasRefill = `-- name: GetGasRefill :one
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills WHERE id = ?
+`
+
+func (q *Queries) GetGasRefill(ctx context.Context, id int64) (GasRefill, error) {
+	row := q.db.QueryRowContext(ctx, getGasRefill, id)
+	var i GasRefill
+	err := row.Scan(
+		&i.ID,
+		&i.Chain,
+		&i.OrderUid,
+		&i.WalletAddress,
+		&i.SellAmount,
+		&i.BuyAmount,
+		&i.Status,
+		&i.UserID,
+		&i.ChatID,
+		&i.CreatedAt,
+		&i.WalletIndex,
+		&i.Attempt,
+		&i.ResolutionNote,
+		&i.OrderType,
+		&i.ValidTo,
+		&i.Kind,
+		&i.PartiallyFillable,
+		&i.ExecutedSellAmount,
+		&i.ExecutedBuyAmount,
+		&i.Surplus,
+	)
+	return i, err
+}
+
+const getGasRefillCooldown = `-- name: GetGasRefillCooldown :one
+SELECT attempted_at FROM gas_refill_cooldowns WHERE chain = ? AND wallet_address = ?
+`
+
+type GetGasRefillCooldownParams struct {
+	Chain         string
+	WalletAddress string
+}
+
+func (q *Queries) GetGasRefillCooldown(ctx context.Context, arg GetGasRefillCooldownParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getGasRefillCooldown, arg.Chain, arg.WalletAddress)
+	var attempted_at time.Time
+	err := row.Scan(&attempted_at)
+	return attempted_at, err
+}
+
+const insertGasRefill = `-- name: InsertGasRefill :one
+INSERT INTO gas_refills (chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, wallet_index, attempt, order_type, valid_to, kind, partially_fillable)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type InsertGasRefillParams struct {
+	Chain             string
+	OrderUid          string
+	WalletAddress     string
+	SellAmount        string
+	BuyAmount         string
+	Status            string
+	UserID            int64
+	ChatID            int64
+	WalletIndex       int64
+	Attempt           int64
+	OrderType         string
+	ValidTo           sql.NullTime
+	Kind              string
+	PartiallyFillable bool
 }
 
 func (q *Queries) InsertGasRefill(ctx context.Context, arg InsertGasRefillParams) (int64, error) {
@@ -36,14 +175,69 @@ func (q *Queries) InsertGasRefill(ctx context.Context, arg InsertGasRefillParams
 		arg.Status,
 		arg.UserID,
 		arg.ChatID,
+		arg.WalletIndex,
+		arg.Attempt,
+		arg.OrderType,
+		arg.ValidTo,
+		arg.Kind,
+		arg.PartiallyFillable,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const listOpenGasRefillsByWallet = `-- name: ListOpenGasRefillsByWallet :many
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills WHERE wallet_address = ? AND status = 'open' ORDER BY created_at
+`
+
+func (q *Queries) ListOpenGasRefillsByWallet(ctx context.Context, walletAddress string) ([]GasRefill, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenGasRefillsByWallet, walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GasRefill
+	for rows.Next() {
+		var i GasRefill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Chain,
+			&i.OrderUid,
+			&i.WalletAddress,
+			&i.SellAmount,
+			&i.BuyAmount,
+			&i.Status,
+			&i.UserID,
+			&i.ChatID,
+			&i.CreatedAt,
+			&i.WalletIndex,
+			&i.Attempt,
+			&i.ResolutionNote,
+			&i.OrderType,
+			&i.ValidTo,
+			&i.Kind,
+			&i.PartiallyFillable,
+			&i.ExecutedSellAmount,
+			&i.ExecutedBuyAmount,
+			&i.Surplus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPendingGasRefills = `-- name: ListPendingGasRefills :many
-SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
 FROM gas_refills WHERE status = 'open' ORDER BY created_at
 `
 
@@ -67,6 +261,71 @@ func (q *Queries) ListPendingGasRefills(ctx context.Context) ([]GasRefill, error
 			&i.UserID,
 			&i.ChatID,
 			&i.CreatedAt,
+			&i.WalletIndex,
+			&i.Attempt,
+			&i.ResolutionNote,
+			&i.OrderType,
+			&i.ValidTo,
+			&i.Kind,
+			&i.PartiallyFillable,
+			&i.ExecutedSellAmount,
+			&i.ExecutedBuyAmount,
+			&i.Surplus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRetryableGasRefills = `-- name: ListRetryableGasRefills :many
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills
+WHERE status IN ('expired', 'cancelled', 'failed') AND order_type = 'market' AND wallet_index >= 0 AND attempt < ? AND resolution_note = ''
+  AND NOT EXISTS (
+    SELECT 1 FROM gas_refills newer
+    WHERE newer.wallet_address = gas_refills.wallet_address AND newer.chain = gas_refills.chain AND newer.id > gas_refills.id
+  )
+ORDER BY created_at
+`
+
+func (q *Queries) ListRetryableGasRefills(ctx context.Context, attempt int64) ([]GasRefill, error) {
+	rows, err := q.db.QueryContext(ctx, listRetryableGasRefills, attempt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GasRefill
+	for rows.Next() {
+		var i GasRefill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Chain,
+			&i.OrderUid,
+			&i.WalletAddress,
+			&i.SellAmount,
+			&i.BuyAmount,
+			&i.Status,
+			&i.UserID,
+			&i.ChatID,
+			&i.CreatedAt,
+			&i.WalletIndex,
+			&i.Attempt,
+			&i.ResolutionNote,
+			&i.OrderType,
+			&i.ValidTo,
+			&i.Kind,
+			&i.PartiallyFillable,
+			&i.ExecutedSellAmount,
+			&i.ExecutedBuyAmount,
+			&i.Surplus,
 		); err != nil {
 			return nil, err
 		}
@@ -81,6 +340,37 @@ func (q *Queries) ListPendingGasRefills(ctx context.Context) ([]GasRefill, error
 	return items, nil
 }
 
+const setGasRefillCooldown = `-- name: SetGasRefillCooldown :exec
+INSERT INTO gas_refill_cooldowns (chain, wallet_address) VALUES (?, ?)
+ON CONFLICT (chain, wallet_address) DO UPDATE SET attempted_at = CURRENT_TIMESTAMP
+`
+
+type SetGasRefillCooldownParams struct {
+	Chain         string
+	WalletAddress string
+}
+
+func (q *Queries) SetGasRefillCooldown(ctx context.Context, arg SetGasRefillCooldownParams) error {
+	_, err := q.db.ExecContext(ctx, setGasRefillCooldown, arg.Chain, arg.WalletAddress)
+	return err
+}
+
+const updateGasRefillFill = `-- name: UpdateGasRefillFill :exec
+UPDATE gas_refills SET executed_sell_amount = ?, executed_buy_amount = ?, surplus = ? WHERE id = ?
+`
+
+type UpdateGasRefillFillParams struct {
+	ExecutedSellAmount string
+	ExecutedBuyAmount  string
+	Surplus            string
+	ID                 int64
+}
+
+func (q *Queries) UpdateGasRefillFill(ctx context.Context, arg UpdateGasRefillFillParams) error {
+	_, err := q.db.ExecContext(ctx, updateGasRefillFill, arg.ExecutedSellAmount, arg.ExecutedBuyAmount, arg.Surplus, arg.ID)
+	return err
+}
+
 const updateGasRefillStatus = `-- name: UpdateGasRefillStatus :exec
 UPDATE gas_refills SET status = ? WHERE id = ?
 `
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE sweeps (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chain TEXT NOT NULL,
+    wallet_index INTEGER NOT NULL,
+    wallet_address TEXT NOT NULL,
+    treasury_address TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    method TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_sweeps_wallet_chain ON sweeps(wallet_address, chain);
+
+-- +goose Down
+DROP TABLE sweeps;
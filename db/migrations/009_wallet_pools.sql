@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE address_assignments ADD COLUMN pool TEXT NOT NULL DEFAULT 'default';
+
+-- +goose Down
+ALTER TABLE address_assignments DROP COLUMN pool;
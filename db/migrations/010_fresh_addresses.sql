@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE fresh_addresses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    parent_index INTEGER NOT NULL,
+    parent_address TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    chain TEXT NOT NULL,
+    funding_tx_hash TEXT NOT NULL DEFAULT '',
+    topup_id INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_fresh_addresses_address ON fresh_addresses(address);
+
+-- +goose Down
+DROP TABLE fresh_addresses;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE topups ADD COLUMN refund_tx_hash TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE topups DROP COLUMN refund_tx_hash;
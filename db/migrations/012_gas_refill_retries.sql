@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE gas_refills ADD COLUMN wallet_index INTEGER NOT NULL DEFAULT -1;
+ALTER TABLE gas_refills ADD COLUMN attempt INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN attempt;
+ALTER TABLE gas_refills DROP COLUMN wallet_index;
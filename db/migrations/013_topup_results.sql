@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE topups ADD COLUMN actual_output TEXT NOT NULL DEFAULT '';
+ALTER TABLE topups ADD COLUMN dest_tx_hash TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE topups DROP COLUMN dest_tx_hash;
+ALTER TABLE topups DROP COLUMN actual_output;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE topup_status_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topup_id INTEGER NOT NULL REFERENCES topups(id),
+    status TEXT NOT NULL,
+    raw_status TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_topup_status_events_topup ON topup_status_events(topup_id);
+
+-- +goose Down
+DROP TABLE topup_status_events;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE topup_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topup_id INTEGER NOT NULL REFERENCES topups(id),
+    chain TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    gas_used INTEGER NOT NULL DEFAULT 0,
+    gas_cost TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(topup_id, tx_hash)
+);
+CREATE INDEX idx_topup_transactions_unpriced ON topup_transactions(gas_cost);
+
+-- +goose Down
+DROP TABLE topup_transactions;
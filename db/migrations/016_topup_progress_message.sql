@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE topups ADD COLUMN progress_message_id INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE topups DROP COLUMN progress_message_id;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE topups ADD COLUMN resolution_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE gas_refills ADD COLUMN resolution_note TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN resolution_note;
+ALTER TABLE topups DROP COLUMN resolution_note;
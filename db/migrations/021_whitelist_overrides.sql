@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE whitelist_overrides (
+    user_id INTEGER PRIMARY KEY,
+    allowed BOOLEAN NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE whitelist_overrides;
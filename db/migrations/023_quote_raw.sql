@@ -0,0 +1,11 @@
+-- +goose Up
+-- The provider responses a quote was built from, as gzipped JSON
+CREATE TABLE quote_raw (
+    quote_id INTEGER PRIMARY KEY REFERENCES quotes(id),
+    payload BLOB NOT NULL,
+    size INTEGER NOT NULL,
+    truncated BOOLEAN NOT NULL
+);
+
+-- +goose Down
+DROP TABLE quote_raw;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Set when a user is anonymized. Their telegram_id is then the tombstone
+-- -id and their username is blank; see Store.AnonymizeUser.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN deleted_at;
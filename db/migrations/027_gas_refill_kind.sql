@@ -0,0 +1,7 @@
+-- +goose Up
+-- 'sell' for refills selling a fixed USDC amount, 'buy' for refills buying
+-- an exact native amount; sell_amount is then the most they may sell
+ALTER TABLE gas_refills ADD COLUMN kind TEXT NOT NULL DEFAULT 'sell';
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN kind;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Native gas sent from the gas treasury wallet to wallets too short of USDC
+-- for a CoW refill. Failed sends are kept and count toward the limits.
+CREATE TABLE gas_transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chain TEXT NOT NULL,
+    wallet_index INTEGER NOT NULL,
+    wallet_address TEXT NOT NULL,
+    treasury_address TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    status TEXT NOT NULL,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    chat_id INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_gas_transfers_chain_created ON gas_transfers(chain, created_at);
+
+-- +goose Down
+DROP TABLE gas_transfers;
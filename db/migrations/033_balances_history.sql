@@ -0,0 +1,19 @@
+-- +goose Up
+-- Periodic snapshots of every wallet's balance on each chain, one row per
+-- wallet and chain, all rows of a snapshot sharing taken_at. USD values
+-- leave out native coins that couldn't be priced, as in the admin panel.
+CREATE TABLE balances_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    taken_at TIMESTAMP NOT NULL,
+    wallet_index INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    chain TEXT NOT NULL,
+    native_balance TEXT NOT NULL,
+    native_usd REAL NOT NULL DEFAULT 0,
+    stable_usd REAL NOT NULL DEFAULT 0,
+    usd REAL NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_balances_history_taken_at ON balances_history(taken_at, chain);
+
+-- +goose Down
+DROP TABLE balances_history;
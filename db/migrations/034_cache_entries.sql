@@ -0,0 +1,14 @@
+-- +goose Up
+-- Responses of slow or rate-limited lookups (CoinGecko, THORNode pools,
+-- 1click tokens) kept across restarts and shared between instances. value is
+-- JSON; entries past expires_at are ignored and pruned on startup.
+CREATE TABLE cache_entries (
+    cache_key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_cache_entries_expires_at ON cache_entries(expires_at);
+
+-- +goose Down
+DROP TABLE cache_entries;
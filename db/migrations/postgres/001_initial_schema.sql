@@ -0,0 +1,226 @@
+-- +goose Up
+-- The SQLite schema as of migration 021. Later migrations are added to both
+-- directories with matching numbers.
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    telegram_id BIGINT UNIQUE NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE quotes (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL DEFAULT 'fast',
+    provider TEXT NOT NULL,
+    user_id BIGINT NOT NULL,
+    from_asset TEXT NOT NULL,
+    from_chain TEXT NOT NULL,
+    to_asset TEXT NOT NULL,
+    destination TEXT NOT NULL,
+    input_amount_usd DOUBLE PRECISION NOT NULL,
+    input_amount TEXT NOT NULL,
+    expected_output TEXT NOT NULL,
+    memo TEXT NOT NULL,
+    router TEXT NOT NULL,
+    vault_address TEXT NOT NULL,
+    expiry BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    chat_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE topups (
+    id BIGSERIAL PRIMARY KEY,
+    short_id TEXT UNIQUE NOT NULL,
+    type TEXT NOT NULL DEFAULT 'fast',
+    quote_id BIGINT NOT NULL REFERENCES quotes(id),
+    user_id BIGINT NOT NULL,
+    provider TEXT NOT NULL,
+    from_chain TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    chat_id BIGINT NOT NULL DEFAULT 0,
+    external_id TEXT NOT NULL DEFAULT '',
+    refund_tx_hash TEXT NOT NULL DEFAULT '',
+    actual_output TEXT NOT NULL DEFAULT '',
+    dest_tx_hash TEXT NOT NULL DEFAULT '',
+    progress_message_id BIGINT NOT NULL DEFAULT 0,
+    resolution_note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE chats (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT UNIQUE NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE address_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    assigned_to_id BIGINT NOT NULL,
+    assigned_to_type TEXT NOT NULL CHECK (assigned_to_type IN ('user', 'chat')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    pool TEXT NOT NULL DEFAULT 'default',
+    UNIQUE (assigned_to_id, assigned_to_type)
+);
+
+CREATE TABLE gas_refills (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL,
+    order_uid TEXT NOT NULL,
+    wallet_address TEXT NOT NULL,
+    sell_amount TEXT NOT NULL,
+    buy_amount TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    user_id BIGINT NOT NULL DEFAULT 0,
+    chat_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    wallet_index BIGINT NOT NULL DEFAULT -1,
+    attempt BIGINT NOT NULL DEFAULT 1,
+    resolution_note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE api_requests (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    method TEXT NOT NULL,
+    url TEXT NOT NULL,
+    request_headers TEXT,
+    request_body TEXT,
+    response_status BIGINT,
+    response_headers TEXT,
+    response_body TEXT,
+    duration_ms BIGINT,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_requests_provider ON api_requests(provider);
+CREATE INDEX idx_api_requests_created_at ON api_requests(created_at);
+CREATE INDEX idx_api_requests_url ON api_requests(url);
+CREATE INDEX idx_api_requests_method ON api_requests(method);
+CREATE INDEX idx_api_requests_response_status ON api_requests(response_status);
+
+CREATE TABLE sweeps (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL,
+    wallet_index BIGINT NOT NULL,
+    wallet_address TEXT NOT NULL,
+    treasury_address TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    method TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sweeps_wallet_chain ON sweeps(wallet_address, chain);
+
+CREATE TABLE fresh_addresses (
+    id BIGSERIAL PRIMARY KEY,
+    parent_index BIGINT NOT NULL,
+    parent_address TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    chain TEXT NOT NULL,
+    funding_tx_hash TEXT NOT NULL DEFAULT '',
+    topup_id BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fresh_addresses_address ON fresh_addresses(address);
+
+CREATE TABLE topup_status_events (
+    id BIGSERIAL PRIMARY KEY,
+    topup_id BIGINT NOT NULL REFERENCES topups(id),
+    status TEXT NOT NULL,
+    raw_status TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_topup_status_events_topup ON topup_status_events(topup_id);
+
+CREATE TABLE topup_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    topup_id BIGINT NOT NULL REFERENCES topups(id),
+    chain TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    gas_used BIGINT NOT NULL DEFAULT 0,
+    gas_cost TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(topup_id, tx_hash)
+);
+
+CREATE INDEX idx_topup_transactions_unpriced ON topup_transactions(gas_cost);
+
+CREATE TABLE broadcasts (
+    id BIGSERIAL PRIMARY KEY,
+    text TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued',
+    recipients BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE TABLE broadcast_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    broadcast_id BIGINT NOT NULL REFERENCES broadcasts(id),
+    chat_id BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (broadcast_id, chat_id)
+);
+
+CREATE TABLE announcement_opt_outs (
+    chat_id BIGINT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE admins (
+    id BIGSERIAL PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id BIGINT NOT NULL,
+    admin_username TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT '',
+    source_ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_action ON audit_log(action);
+
+CREATE TABLE whitelist_overrides (
+    user_id BIGINT PRIMARY KEY,
+    allowed BOOLEAN NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE whitelist_overrides;
+DROP TABLE audit_log;
+DROP TABLE admins;
+DROP TABLE announcement_opt_outs;
+DROP TABLE broadcast_deliveries;
+DROP TABLE broadcasts;
+DROP TABLE topup_transactions;
+DROP TABLE topup_status_events;
+DROP TABLE fresh_addresses;
+DROP TABLE sweeps;
+DROP TABLE api_requests;
+DROP TABLE gas_refills;
+DROP TABLE address_assignments;
+DROP TABLE chats;
+DROP TABLE topups;
+DROP TABLE quotes;
+DROP TABLE users;
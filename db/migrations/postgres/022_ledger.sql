@@ -0,0 +1,41 @@
+-- +goose Up
+-- Each entry moves amount (smallest unit) of asset from one account to
+-- another, so every debit has its credit in the same row.
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL,
+    asset TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    from_account TEXT NOT NULL,
+    to_account TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (chain, reference, asset)
+);
+CREATE INDEX idx_ledger_entries_from ON ledger_entries(from_account);
+CREATE INDEX idx_ledger_entries_to ON ledger_entries(to_account);
+
+CREATE TABLE ledger_cursors (
+    chain TEXT PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE ledger_reconciliations (
+    account TEXT NOT NULL,
+    chain TEXT NOT NULL,
+    asset TEXT NOT NULL,
+    ledger_balance TEXT NOT NULL,
+    onchain_balance TEXT NOT NULL,
+    difference TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account, chain, asset)
+);
+
+-- +goose Down
+DROP TABLE ledger_reconciliations;
+DROP TABLE ledger_cursors;
+DROP TABLE ledger_entries;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- The wallet index a topup was paid from; -1 for topups from before it was
+-- recorded
+ALTER TABLE topups ADD COLUMN wallet_index BIGINT NOT NULL DEFAULT -1;
+
+-- +goose Down
+ALTER TABLE topups DROP COLUMN wallet_index;
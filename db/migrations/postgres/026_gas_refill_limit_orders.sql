@@ -0,0 +1,9 @@
+-- +goose Up
+-- 'market' for refills priced from a quote, 'limit' for orders at a price
+-- the user set. valid_to is when the order expires at CoW.
+ALTER TABLE gas_refills ADD COLUMN order_type TEXT NOT NULL DEFAULT 'market';
+ALTER TABLE gas_refills ADD COLUMN valid_to TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN valid_to;
+ALTER TABLE gas_refills DROP COLUMN order_type;
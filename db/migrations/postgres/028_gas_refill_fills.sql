@@ -0,0 +1,12 @@
+-- +goose Up
+-- Partially fillable orders can settle in several trades; the executed
+-- amounts are the running totals CoW reports, in the same units as
+-- sell_amount and buy_amount
+ALTER TABLE gas_refills ADD COLUMN partially_fillable BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE gas_refills ADD COLUMN executed_sell_amount TEXT NOT NULL DEFAULT '0';
+ALTER TABLE gas_refills ADD COLUMN executed_buy_amount TEXT NOT NULL DEFAULT '0';
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN executed_buy_amount;
+ALTER TABLE gas_refills DROP COLUMN executed_sell_amount;
+ALTER TABLE gas_refills DROP COLUMN partially_fillable;
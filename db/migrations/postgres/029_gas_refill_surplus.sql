@@ -0,0 +1,8 @@
+-- +goose Up
+-- Surplus is what an order got beyond its signed limit for the part that
+-- executed: extra buy token for sell orders, sell token saved for buy
+-- orders, in that token's smallest units
+ALTER TABLE gas_refills ADD COLUMN surplus TEXT NOT NULL DEFAULT '0';
+
+-- +goose Down
+ALTER TABLE gas_refills DROP COLUMN surplus;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- When a gas refill was last attempted for a wallet on a chain, whatever
+-- placed it; the gas monitor waits out its cooldown from here
+CREATE TABLE gas_refill_cooldowns (
+    chain TEXT NOT NULL,
+    wallet_address TEXT NOT NULL,
+    attempted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chain, wallet_address)
+);
+
+-- +goose Down
+DROP TABLE gas_refill_cooldowns;
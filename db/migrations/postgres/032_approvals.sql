@@ -0,0 +1,27 @@
+-- +goose Up
+-- Topups and gas refills above approvals.threshold_usd, held until the
+-- admin approves or rejects them. request is the topups.Request as JSON for
+-- topups; gas refills need only the chain and wallet index.
+CREATE TABLE approvals (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    amount_usd DOUBLE PRECISION NOT NULL,
+    summary TEXT NOT NULL,
+    request TEXT NOT NULL DEFAULT '',
+    chain TEXT NOT NULL DEFAULT '',
+    wallet_index BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    chat_id BIGINT NOT NULL,
+    message_id BIGINT NOT NULL DEFAULT 0,
+    admin_message_id BIGINT NOT NULL DEFAULT 0,
+    decided_by TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    note TEXT NOT NULL DEFAULT '',
+    result TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_approvals_status ON approvals(status, created_at);
+
+-- +goose Down
+DROP TABLE approvals;
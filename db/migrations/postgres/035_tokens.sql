@@ -0,0 +1,26 @@
+-- +goose Up
+-- Token mappings pinned by the admin, used before static mappings and the
+-- resolver. Provider IDs are the resolver's hints; empty ones are left to
+-- the static mappings. Disabled tokens are refused for quotes and topups.
+CREATE TABLE tokens (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    contract_address TEXT NOT NULL DEFAULT '',
+    thorchain_asset TEXT NOT NULL DEFAULT '',
+    simpleswap_symbol TEXT NOT NULL DEFAULT '',
+    nearintents_token_id TEXT NOT NULL DEFAULT '',
+    houdini_symbol TEXT NOT NULL DEFAULT '',
+    stealthex_currency TEXT NOT NULL DEFAULT '',
+    relay_currency TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (chain, symbol)
+);
+CREATE INDEX idx_tokens_contract ON tokens(chain, contract_address);
+
+-- +goose Down
+DROP TABLE tokens;
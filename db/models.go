@@ -14,6 +14,20 @@ type AddressAssignment struct {
 	AssignedToID   int64
 	AssignedToType string
 	CreatedAt      time.Time
+	Pool           string
+}
+
+type Admin struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	Role         string
+	CreatedAt    time.Time
+}
+
+type AnnouncementOptOut struct {
+	ChatID    int64
+	CreatedAt time.Time
 }
 
 type ApiRequest struct {
@@ -31,6 +45,74 @@ type ApiRequest struct {
 	CreatedAt       sql.NullTime
 }
 
+type Approval struct {
+	ID             int64
+	Kind           string
+	Status         string
+	AmountUsd      float64
+	Summary        string
+	Request        string
+	Chain          string
+	WalletIndex    int64
+	UserID         int64
+	ChatID         int64
+	MessageID      int64
+	AdminMessageID int64
+	DecidedBy      string
+	DecidedAt      sql.NullTime
+	Note           string
+	Result         string
+	CreatedAt      time.Time
+}
+
+type AuditLog struct {
+	ID            int64
+	AdminID       int64
+	AdminUsername string
+	Action        string
+	Target        string
+	Detail        string
+	SourceIp      string
+	CreatedAt     time.Time
+}
+
+type BalancesHistory struct {
+	ID            int64
+	TakenAt       time.Time
+	WalletIndex   int64
+	Address       string
+	Chain         string
+	NativeBalance string
+	NativeUsd     float64
+	StableUsd     float64
+	Usd           float64
+}
+
+type Broadcast struct {
+	ID         int64
+	Text       string
+	Status     string
+	Recipients int64
+	CreatedAt  time.Time
+	FinishedAt sql.NullTime
+}
+
+type BroadcastDelivery struct {
+	ID          int64
+	BroadcastID int64
+	ChatID      int64
+	Status      string
+	Error       string
+	CreatedAt   time.Time
+}
+
+type CacheEntry struct {
+	CacheKey  string
+	Value     string
+	ExpiresAt time.Time
+	UpdatedAt time.Time
+}
+
 type Chat struct {
 	ID        int64
 	ChatID    int64
@@ -38,19 +120,90 @@ type Chat struct {
 	CreatedAt time.Time
 }
 
-type GasRefill struct {
+type FreshAddress struct {
 	ID            int64
+	ParentIndex   int64
+	ParentAddress string
+	Address       string
 	Chain         string
-	OrderUid      string
-	WalletAddress string
-	SellAmount    string
-	BuyAmount     string
-	Status        string
-	UserID        int64
-	ChatID        int64
+	FundingTxHash string
+	TopupID       int64
 	CreatedAt     time.Time
 }
 
+type GasRefill struct {
+	ID                 int64
+	Chain              string
+	OrderUid           string
+	WalletAddress      string
+	SellAmount         string
+	BuyAmount          string
+	Status             string
+	UserID             int64
+	ChatID             int64
+	CreatedAt          time.Time
+	WalletIndex        int64
+	Attempt            int64
+	ResolutionNote     string
+	OrderType          string
+	ValidTo            sql.NullTime
+	Kind               string
+	PartiallyFillable  bool
+	ExecutedSellAmount string
+	ExecutedBuyAmount  string
+	Surplus            string
+}
+
+type GasRefillCooldown struct {
+	Chain         string
+	WalletAddress string
+	AttemptedAt   time.Time
+}
+
+type GasTransfer struct {
+	ID              int64
+	Chain           string
+	WalletIndex     int64
+	WalletAddress   string
+	TreasuryAddress string
+	Amount          string
+	TxHash          string
+	Status          string
+	UserID          int64
+	ChatID          int64
+	CreatedAt       time.Time
+}
+
+type LedgerCursor struct {
+	Chain       string
+	BlockNumber int64
+	UpdatedAt   time.Time
+}
+
+type LedgerEntry struct {
+	ID          int64
+	Chain       string
+	Asset       string
+	Amount      string
+	FromAccount string
+	ToAccount   string
+	Kind        string
+	Reference   string
+	Note        string
+	CreatedAt   time.Time
+}
+
+type LedgerReconciliation struct {
+	Account        string
+	Chain          string
+	Asset          string
+	LedgerBalance  string
+	OnchainBalance string
+	Difference     string
+	BlockNumber    int64
+	CheckedAt      time.Time
+}
+
 type Quote struct {
 	ID             int64
 	Type           string
@@ -71,19 +224,82 @@ type Quote struct {
 	ChatID         int64
 }
 
+type QuoteRaw struct {
+	QuoteID   int64
+	Payload   []byte
+	Size      int64
+	Truncated bool
+}
+
+type Sweep struct {
+	ID              int64
+	Chain           string
+	WalletIndex     int64
+	WalletAddress   string
+	TreasuryAddress string
+	Amount          string
+	Method          string
+	Reference       string
+	Status          string
+	CreatedAt       time.Time
+}
+
+type Token struct {
+	ID                 int64
+	Chain              string
+	Symbol             string
+	ContractAddress    string
+	ThorchainAsset     string
+	SimpleswapSymbol   string
+	NearintentsTokenID string
+	HoudiniSymbol      string
+	StealthexCurrency  string
+	RelayCurrency      string
+	Enabled            bool
+	Note               string
+	ChangedBy          string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
 type Topup struct {
-	ID         int64
-	ShortID    string
-	Type       string
-	QuoteID    int64
-	UserID     int64
-	Provider   string
-	FromChain  string
-	TxHash     string
-	Status     string
-	CreatedAt  time.Time
-	ChatID     int64
-	ExternalID string
+	ID                int64
+	ShortID           string
+	Type              string
+	QuoteID           int64
+	UserID            int64
+	Provider          string
+	FromChain         string
+	TxHash            string
+	Status            string
+	CreatedAt         time.Time
+	ChatID            int64
+	ExternalID        string
+	RefundTxHash      string
+	ActualOutput      string
+	DestTxHash        string
+	ProgressMessageID int64
+	ResolutionNote    string
+	WalletIndex       int64
+}
+
+type TopupStatusEvent struct {
+	ID        int64
+	TopupID   int64
+	Status    string
+	RawStatus string
+	CreatedAt time.Time
+}
+
+type TopupTransaction struct {
+	ID        int64
+	TopupID   int64
+	Chain     string
+	TxHash    string
+	Kind      string
+	GasUsed   int64
+	GasCost   string
+	CreatedAt time.Time
 }
 
 type User struct {
@@ -91,4 +307,13 @@ type User struct {
 	TelegramID int64
 	Username   string
 	CreatedAt  time.Time
+	DeletedAt  sql.NullTime
+}
+
+type WhitelistOverride struct {
+	UserID    int64
+	Allowed   bool
+	Note      string
+	ChangedBy string
+	ChangedAt time.Time
 }
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// MultiMigration is what MigrateToMulti did.
+type MultiMigration struct {
+	// Assignments created, in index order; index 0 is the shared wallet
+	Assignments []AddressAssignment
+	// Topups from before wallet_index was recorded, now pinned to index 0
+	LegacyTopups int64
+}
+
+// MigrateToMulti prepares a single-mode database for multi mode, in one
+// transaction. The shared wallet at index 0 becomes the admin user's, so
+// its funds, sweeps and history stay with a tracked wallet. Every other
+// user and group chat that has quoted gets a wallet, in order of their
+// first quote, in the pool poolFor picks for their chat. Existing topups
+// keep their owners but are pinned to index 0, which paid for them.
+// It refuses if any address assignment exists.
+func (s *Store) MigrateToMulti(ctx context.Context, adminUserID int64, poolFor func(chatID int64) string) (MultiMigration, error) {
+	var m MultiMigration
+	err := s.inTx(ctx, func(q *Queries) error {
+		n, err := q.CountAddressAssignments(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%d address assignments exist; the database is already in multi mode", n)
+		}
+
+		admin, err := q.GetOrCreateUser(ctx, adminUserID, "")
+		if err != nil {
+			return err
+		}
+		if err := q.CreateAddressAssignmentWithID(ctx, CreateAddressAssignmentWithIDParams{
+			ID:             0,
+			AssignedToID:   admin.ID,
+			AssignedToType: "user",
+			Pool:           "default",
+		}); err != nil {
+			return fmt.Errorf("assigning the shared wallet: %w", err)
+		}
+		shared, err := q.GetAddressAssignmentByID(ctx, 0)
+		if err != nil {
+			return err
+		}
+		m.Assignments = append(m.Assignments, shared)
+
+		owners, err := q.ListQuoteOwners(ctx)
+		if err != nil {
+			return fmt.Errorf("listing quote owners: %w", err)
+		}
+		for _, o := range owners {
+			// Legacy quotes have no chat; they were made in the user's DM
+			var a AddressAssignment
+			if o.ChatID == 0 || o.ChatID == o.UserID {
+				if o.UserID == adminUserID {
+					continue
+				}
+				user, err := q.GetOrCreateUser(ctx, o.UserID, "")
+				if err != nil {
+					return err
+				}
+				a, err = q.GetOrCreateAddressAssignment(ctx, user.ID, "user", poolFor(o.UserID))
+				if err != nil {
+					return err
+				}
+			} else {
+				chat, err := q.GetOrCreateChat(ctx, o.ChatID, "")
+				if err != nil {
+					return err
+				}
+				a, err = q.GetOrCreateAddressAssignment(ctx, chat.ID, "chat", poolFor(o.ChatID))
+				if err != nil {
+					return err
+				}
+			}
+			// Users quote in several chats, groups by several users
+			if !slices.ContainsFunc(m.Assignments, func(b AddressAssignment) bool { return b.ID == a.ID }) {
+				m.Assignments = append(m.Assignments, a)
+			}
+		}
+
+		m.LegacyTopups, err = q.SetLegacyTopupWalletIndex(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("pinning topups to the shared wallet: %w", err)
+		}
+		return nil
+	})
+	return m, err
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// postgresRewrites turn the SQLite functions and syntax the queries use into
+// their PostgreSQL equivalents. Sessions run in UTC (see Open), so timestamps
+// keep SQLite's UTC convention.
+var postgresRewrites = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`datetime\('now', '([^']+)'\)`), `(LOCALTIMESTAMP + INTERVAL '$1')`},
+	{regexp.MustCompile(`datetime\((\?\d*)\)`), `CAST(CAST($1 AS TIMESTAMPTZ) AS TIMESTAMP)`},
+	{regexp.MustCompile(`DATE\(([\w.]+), 'weekday 0', '-6 days'\)`), `CAST(date_trunc('week', $1) AS DATE)`},
+	{regexp.MustCompile(`DATE\(([\w.]+), 'start of month'\)`), `CAST(date_trunc('month', $1) AS DATE)`},
+	{regexp.MustCompile(`DATE\(([\w.]+)\)`), `CAST($1 AS DATE)`},
+	{regexp.MustCompile(`GROUP_CONCAT\(([^)]+)\)`), `string_agg($1, ',')`},
+	{regexp.MustCompile(`AS REAL\)`), `AS DOUBLE PRECISION)`},
+	// SQLite's LIKE ignores ASCII case
+	{regexp.MustCompile(`\bLIKE\b`), `ILIKE`},
+	{regexp.MustCompile(`(?s)INSERT OR IGNORE (INTO .*?)\s*$`), "INSERT ${1}\nON CONFLICT DO NOTHING\n"},
+}
+
+// postgresQuery rewrites a sqlc query written for SQLite to run on
+// PostgreSQL, including numbering its ? and ?N placeholders as $N.
+func postgresQuery(query string) string {
+	for _, r := range postgresRewrites {
+		query = r.re.ReplaceAllString(query, r.repl)
+	}
+
+	var b strings.Builder
+	next := 1
+	inString, inComment := false, false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case inComment:
+			inComment = c != '\n'
+		case inString:
+			inString = c != '\''
+		case c == '\'':
+			inString = true
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			inComment = true
+		case c == '?':
+			// As in SQLite, a bare ? takes the number after the highest
+			// one used so far
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			n := next
+			if j > i+1 {
+				n, _ = strconv.Atoi(query[i+1 : j])
+			}
+			if n >= next {
+				next = n + 1
+			}
+			b.WriteString("$" + strconv.Itoa(n))
+			i = j - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// postgresDB runs the sqlc queries against PostgreSQL, on a connection
+// pool or a transaction, rewriting each one the first time it is used.
+type postgresDB struct {
+	conn    DBTX
+	queries sync.Map // SQLite query -> PostgreSQL query
+}
+
+func (p *postgresDB) query(query string) string {
+	if q, ok := p.queries.Load(query); ok {
+		return q.(string)
+	}
+	q := postgresQuery(query)
+	p.queries.Store(query, q)
+	return q
+}
+
+func (p *postgresDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.conn.ExecContext(ctx, p.query(query), args...)
+}
+
+func (p *postgresDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.conn.PrepareContext(ctx, p.query(query))
+}
+
+func (p *postgresDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.conn.QueryContext(ctx, p.query(query), args...)
+}
+
+func (p *postgresDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.conn.QueryRowContext(ctx, p.query(query), args...)
+}
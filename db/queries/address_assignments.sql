@@ -1,14 +1,29 @@
 -- name: GetAddressAssignment :one
-SELECT id, assigned_to_id, assigned_to_type, created_at
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
 FROM address_assignments
 WHERE assigned_to_id = ? AND assigned_to_type = ?;
 
+-- name: GetAddressAssignmentByID :one
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
+FROM address_assignments
+WHERE id = ?;
+
 -- name: CreateAddressAssignment :one
-INSERT INTO address_assignments (assigned_to_id, assigned_to_type)
-VALUES (?, ?)
-RETURNING id, assigned_to_id, assigned_to_type, created_at;
+INSERT INTO address_assignments (assigned_to_id, assigned_to_type, pool)
+VALUES (?, ?, ?)
+RETURNING id, assigned_to_id, assigned_to_type, created_at, pool;
 
 -- name: ListAddressAssignments :many
-SELECT id, assigned_to_id, assigned_to_type, created_at
+SELECT id, assigned_to_id, assigned_to_type, created_at, pool
 FROM address_assignments
 ORDER BY id;
+
+-- name: UpdateAddressAssignmentPool :exec
+UPDATE address_assignments SET pool = ? WHERE id = ?;
+
+-- name: CountAddressAssignments :one
+SELECT COUNT(*) FROM address_assignments;
+
+-- name: CreateAddressAssignmentWithID :exec
+INSERT INTO address_assignments (id, assigned_to_id, assigned_to_type, pool)
+VALUES (?, ?, ?, ?);
@@ -0,0 +1,27 @@
+-- name: InsertAdmin :one
+INSERT INTO admins (username, password_hash, role) VALUES (?, ?, ?)
+RETURNING id;
+
+-- name: GetAdmin :one
+SELECT id, username, password_hash, role, created_at FROM admins WHERE id = ?;
+
+-- name: GetAdminByUsername :one
+SELECT id, username, password_hash, role, created_at FROM admins WHERE username = ?;
+
+-- name: ListAdmins :many
+SELECT id, username, role, created_at FROM admins ORDER BY id;
+
+-- name: CountAdmins :one
+SELECT COUNT(*) FROM admins;
+
+-- name: CountSuperadmins :one
+SELECT COUNT(*) FROM admins WHERE role = 'superadmin';
+
+-- name: UpdateAdminRole :exec
+UPDATE admins SET role = ? WHERE id = ?;
+
+-- name: UpdateAdminPassword :exec
+UPDATE admins SET password_hash = ? WHERE id = ?;
+
+-- name: DeleteAdmin :exec
+DELETE FROM admins WHERE id = ?;
@@ -7,7 +7,7 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 SELECT id, provider, method, url, request_headers, request_body,
        response_status, response_headers, response_body, duration_ms, error, created_at
 FROM api_requests
-WHERE CASE WHEN @search = '' THEN 1 ELSE (
+WHERE (@search = '' OR (
     provider LIKE '%' || @search || '%'
     OR method LIKE '%' || @search || '%'
     OR url LIKE '%' || @search || '%'
@@ -17,12 +17,12 @@ WHERE CASE WHEN @search = '' THEN 1 ELSE (
     OR COALESCE(response_headers, '') LIKE '%' || @search || '%'
     OR COALESCE(response_body, '') LIKE '%' || @search || '%'
     OR COALESCE(error, '') LIKE '%' || @search || '%'
-) END
+))
 ORDER BY created_at DESC LIMIT @limit OFFSET @offset;
 
 -- name: CountAPIRequests :one
 SELECT COUNT(*) FROM api_requests
-WHERE CASE WHEN @search = '' THEN 1 ELSE (
+WHERE (@search = '' OR (
     provider LIKE '%' || @search || '%'
     OR method LIKE '%' || @search || '%'
     OR url LIKE '%' || @search || '%'
@@ -32,9 +32,34 @@ WHERE CASE WHEN @search = '' THEN 1 ELSE (
     OR COALESCE(response_headers, '') LIKE '%' || @search || '%'
     OR COALESCE(response_body, '') LIKE '%' || @search || '%'
     OR COALESCE(error, '') LIKE '%' || @search || '%'
-) END;
+));
 
 -- name: GetAPIRequest :one
 SELECT id, provider, method, url, request_headers, request_body,
        response_status, response_headers, response_body, duration_ms, error, created_at
 FROM api_requests WHERE id = ?;
+
+-- name: GetLatestAPIRequestFor :one
+SELECT id, provider, method, url, request_headers, request_body,
+       response_status, response_headers, response_body, duration_ms, error, created_at
+FROM api_requests
+WHERE provider = @provider AND url LIKE '%' || @ref || '%'
+ORDER BY created_at DESC, id DESC LIMIT 1;
+
+-- name: DeleteAPIRequestsBefore :execrows
+DELETE FROM api_requests WHERE created_at < datetime(@created_before);
+
+-- name: DeleteAPIRequestsBeyond :execrows
+DELETE FROM api_requests WHERE id < (
+    SELECT MIN(id) FROM (SELECT id FROM api_requests ORDER BY id DESC LIMIT @keep) AS newest
+);
+
+-- name: ProviderCallStats :many
+SELECT provider,
+       CAST(COUNT(CASE WHEN created_at >= datetime(@created_from) THEN 1 END) AS INTEGER) AS calls,
+       CAST(COUNT(CASE WHEN created_at >= datetime(@created_from)
+                        AND (error IS NOT NULL OR response_status >= 500) THEN 1 END) AS INTEGER) AS failures,
+       CAST(COALESCE(AVG(CASE WHEN created_at >= datetime(@created_from) THEN duration_ms END), 0) AS REAL) AS avg_duration_ms,
+       COALESCE(CAST(MAX(CASE WHEN error IS NULL AND response_status < 500 THEN created_at END) AS TEXT), '') AS last_success
+FROM api_requests
+GROUP BY provider ORDER BY provider;
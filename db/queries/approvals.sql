@@ -0,0 +1,42 @@
+-- name: InsertApproval :one
+INSERT INTO approvals (kind, amount_usd, summary, request, chain, wallet_index, user_id, chat_id, message_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, kind, status, amount_usd, summary, request, chain, wallet_index, user_id, chat_id, message_id, admin_message_id, decided_by, decided_at, note, result, created_at;
+
+-- name: GetApproval :one
+SELECT id, kind, status, amount_usd, summary, request, chain, wallet_index, user_id, chat_id, message_id, admin_message_id, decided_by, decided_at, note, result, created_at
+FROM approvals WHERE id = ?;
+
+-- name: ListPendingApprovals :many
+SELECT id, kind, status, amount_usd, summary, request, chain, wallet_index, user_id, chat_id, message_id, admin_message_id, decided_by, decided_at, note, result, created_at
+FROM approvals WHERE status = 'pending'
+ORDER BY id;
+
+-- name: DecideApproval :execrows
+UPDATE approvals SET status = ?, decided_by = ?, note = ?, decided_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: FinishApproval :exec
+UPDATE approvals SET status = ?, result = ? WHERE id = ?;
+
+-- name: SetApprovalAdminMessage :exec
+UPDATE approvals SET admin_message_id = ? WHERE id = ?;
+
+-- name: ExpireApprovals :many
+UPDATE approvals SET status = 'expired', decided_at = CURRENT_TIMESTAMP
+WHERE status = 'pending' AND created_at < datetime(@created_before)
+RETURNING id, kind, status, amount_usd, summary, request, chain, wallet_index, user_id, chat_id, message_id, admin_message_id, decided_by, decided_at, note, result, created_at;
+
+-- name: CountPendingGasRefillApprovals :one
+SELECT COUNT(*) FROM approvals
+WHERE kind = 'gas_refill' AND status = 'pending' AND chain = ? AND wallet_index = ?;
+
+-- name: CountPendingApprovalsByUser :one
+SELECT COUNT(*) FROM approvals WHERE user_id = ? AND status IN ('pending', 'approved');
+
+-- name: AnonymizeApprovals :exec
+UPDATE approvals SET
+    user_id = CASE WHEN user_id = @telegram_id THEN @tombstone ELSE user_id END,
+    chat_id = CASE WHEN chat_id = @telegram_id THEN @tombstone ELSE chat_id END,
+    request = ''
+WHERE user_id = @telegram_id OR chat_id = @telegram_id;
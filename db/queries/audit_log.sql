@@ -0,0 +1,16 @@
+-- name: InsertAuditEntry :exec
+INSERT INTO audit_log (admin_id, admin_username, action, target, detail, source_ip)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListAuditLog :many
+SELECT id, admin_id, admin_username, action, target, detail, source_ip, created_at
+FROM audit_log
+WHERE (@action = '' OR action = @action)
+  AND (@admin_username = '' OR admin_username = @admin_username)
+ORDER BY id DESC
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: CountAuditLog :one
+SELECT COUNT(*) FROM audit_log
+WHERE (@action = '' OR action = @action)
+  AND (@admin_username = '' OR admin_username = @admin_username);
@@ -0,0 +1,16 @@
+-- name: InsertBalanceHistory :exec
+INSERT INTO balances_history (taken_at, wallet_index, address, chain, native_balance, native_usd, stable_usd, usd)
+VALUES (datetime(@taken_at), @wallet_index, @address, @chain, @native_balance, @native_usd, @stable_usd, @usd);
+
+-- name: BalanceHistoryByChain :many
+SELECT taken_at, chain,
+       COALESCE(SUM(native_usd), 0) as native_usd,
+       COALESCE(SUM(stable_usd), 0) as stable_usd,
+       COALESCE(SUM(usd), 0) as total_usd,
+       COUNT(*) as wallet_count
+FROM balances_history
+WHERE taken_at >= datetime(@taken_from) AND taken_at < datetime(@taken_to)
+GROUP BY taken_at, chain ORDER BY taken_at, chain;
+
+-- name: DeleteBalanceHistoryBefore :execrows
+DELETE FROM balances_history WHERE taken_at < datetime(@taken_before);
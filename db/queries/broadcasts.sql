@@ -0,0 +1,59 @@
+-- name: InsertBroadcast :one
+INSERT INTO broadcasts (text) VALUES (?)
+RETURNING id;
+
+-- name: ListUnfinishedBroadcasts :many
+SELECT id, text, status, recipients, created_at, finished_at
+FROM broadcasts WHERE status != 'done' ORDER BY id;
+
+-- name: StartBroadcast :exec
+UPDATE broadcasts SET status = 'sending', recipients = ? WHERE id = ?;
+
+-- name: FinishBroadcast :exec
+UPDATE broadcasts SET status = 'done', finished_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: ListBroadcasts :many
+SELECT b.id, b.text, b.status, b.recipients, b.created_at, b.finished_at,
+       COUNT(CASE WHEN d.status = 'sent' THEN 1 END) AS sent,
+       COUNT(CASE WHEN d.status = 'failed' THEN 1 END) AS failed,
+       COUNT(CASE WHEN d.status = 'blocked' THEN 1 END) AS blocked
+FROM broadcasts b LEFT JOIN broadcast_deliveries d ON d.broadcast_id = b.id
+GROUP BY b.id
+ORDER BY b.id DESC
+LIMIT ?;
+
+-- name: InsertBroadcastDelivery :exec
+INSERT INTO broadcast_deliveries (broadcast_id, chat_id, status, error)
+VALUES (?, ?, ?, ?);
+
+-- name: ListBroadcastDeliveredChatIDs :many
+SELECT chat_id FROM broadcast_deliveries WHERE broadcast_id = ?;
+
+-- name: ListBroadcastFailures :many
+SELECT id, broadcast_id, chat_id, status, error, created_at
+FROM broadcast_deliveries
+WHERE broadcast_id = ? AND status != 'sent'
+ORDER BY id;
+
+-- name: ListKnownChatIDs :many
+SELECT telegram_id AS chat_id FROM users WHERE deleted_at IS NULL
+UNION SELECT chat_id FROM chats
+UNION SELECT CASE WHEN chat_id != 0 THEN chat_id ELSE user_id END FROM quotes
+WHERE user_id NOT IN (SELECT telegram_id FROM users WHERE deleted_at IS NOT NULL)
+ORDER BY chat_id;
+
+-- name: ListAnnouncementOptOuts :many
+SELECT chat_id FROM announcement_opt_outs;
+
+-- name: IsAnnouncementOptOut :one
+SELECT COUNT(*) FROM announcement_opt_outs WHERE chat_id = ?;
+
+-- name: InsertAnnouncementOptOut :exec
+INSERT INTO announcement_opt_outs (chat_id) VALUES (?)
+ON CONFLICT (chat_id) DO NOTHING;
+
+-- name: DeleteAnnouncementOptOut :exec
+DELETE FROM announcement_opt_outs WHERE chat_id = ?;
+
+-- name: AnonymizeBroadcastDeliveries :exec
+UPDATE broadcast_deliveries SET chat_id = @tombstone WHERE chat_id = @telegram_id;
@@ -0,0 +1,10 @@
+-- name: GetCacheEntry :one
+SELECT cache_key, value, expires_at, updated_at FROM cache_entries
+WHERE cache_key = @cache_key AND expires_at > datetime(@now);
+
+-- name: SetCacheEntry :exec
+INSERT INTO cache_entries (cache_key, value, expires_at) VALUES (@cache_key, @value, datetime(@expires_at))
+ON CONFLICT (cache_key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at, updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteExpiredCacheEntries :execrows
+DELETE FROM cache_entries WHERE expires_at <= datetime(@now);
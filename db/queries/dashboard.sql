@@ -4,6 +4,9 @@ SELECT (SELECT COUNT(*) FROM users) + (SELECT COUNT(*) FROM chats);
 -- name: CountTopups :one
 SELECT COUNT(*) FROM topups;
 
+-- name: CountRefundedTopups :one
+SELECT COUNT(*) FROM topups WHERE status = 'refunded';
+
 -- name: TotalVolumeUSD :one
 SELECT COALESCE(SUM(q.input_amount_usd), 0) FROM topups t JOIN quotes q ON t.quote_id = q.id;
 
@@ -14,14 +17,33 @@ SELECT COUNT(DISTINCT q.from_asset || '->' || q.to_asset) FROM topups t JOIN quo
 SELECT COUNT(DISTINCT provider) FROM topups;
 
 -- name: ListRecentTopups :many
+SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
+       t.tx_hash, t.status, t.created_at, t.actual_output, t.dest_tx_hash, t.resolution_note,
+       q.from_asset, q.to_asset, q.destination, q.input_amount_usd, q.expected_output
+FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE (@status = '' OR t.status = @status)
+  AND (@provider = '' OR t.provider = @provider)
+  AND (@from_chain = '' OR t.from_chain = @from_chain)
+  AND (@user_id = 0 OR t.user_id = @user_id)
+  AND t.created_at >= datetime(@created_from)
+  AND t.created_at < datetime(@created_to)
+ORDER BY
+  CASE WHEN @sort = 'oldest' THEN t.created_at END ASC,
+  CASE WHEN @sort = 'usd_desc' THEN q.input_amount_usd END DESC,
+  CASE WHEN @sort = 'usd_asc' THEN q.input_amount_usd END ASC,
+  t.created_at DESC, t.id DESC
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: ListStalledTopups :many
 SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
        t.tx_hash, t.status, t.created_at,
        q.from_asset, q.to_asset, q.destination, q.input_amount_usd, q.expected_output
 FROM topups t JOIN quotes q ON t.quote_id = q.id
-ORDER BY t.created_at DESC LIMIT ? OFFSET ?;
+WHERE t.status = 'stalled'
+ORDER BY t.created_at;
 
 -- name: ListUsers :many
-SELECT id, telegram_id, username, created_at FROM users ORDER BY id;
+SELECT id, telegram_id, username, created_at, deleted_at FROM users ORDER BY id;
 
 -- name: GetTopupsByUserID :many
 SELECT t.id, t.short_id, t.type, t.quote_id, t.user_id, t.provider, t.from_chain,
@@ -31,19 +53,49 @@ FROM topups t WHERE t.user_id = ? ORDER BY t.created_at DESC;
 -- name: VolumeByToAsset :many
 SELECT q.to_asset, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(@created_from) AND t.created_at < datetime(@created_to)
 GROUP BY q.to_asset ORDER BY total_usd DESC;
 
 -- name: VolumeByFromChain :many
 SELECT t.from_chain, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(@created_from) AND t.created_at < datetime(@created_to)
 GROUP BY t.from_chain ORDER BY total_usd DESC;
 
--- name: VolumeByDay :many
-SELECT DATE(t.created_at) as day, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
+-- name: VolumeByPeriod :many
+SELECT CAST(CASE @grouping
+         WHEN 'week' THEN DATE(t.created_at, 'weekday 0', '-6 days')
+         WHEN 'month' THEN DATE(t.created_at, 'start of month')
+         ELSE DATE(t.created_at) END AS TEXT) as period,
+       COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
-GROUP BY DATE(t.created_at) ORDER BY day;
+WHERE t.created_at >= datetime(@created_from) AND t.created_at < datetime(@created_to)
+GROUP BY period ORDER BY period;
 
 -- name: VolumeByProvider :many
 SELECT t.provider, COALESCE(SUM(q.input_amount_usd), 0) as total_usd, COUNT(*) as tx_count
 FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.created_at >= datetime(@created_from) AND t.created_at < datetime(@created_to)
 GROUP BY t.provider ORDER BY total_usd DESC;
+
+-- name: GasCostByChain :many
+SELECT chain, COALESCE(SUM(CAST(gas_cost AS REAL)), 0) as total_wei, COUNT(*) as tx_count
+FROM topup_transactions
+WHERE gas_cost != '' AND created_at >= datetime(@created_from) AND created_at < datetime(@created_to)
+GROUP BY chain ORDER BY chain;
+
+-- name: FeesByPeriod :many
+SELECT CAST(CASE @grouping
+         WHEN 'week' THEN DATE(created_at, 'weekday 0', '-6 days')
+         WHEN 'month' THEN DATE(created_at, 'start of month')
+         ELSE DATE(created_at) END AS TEXT) as period,
+       chain, COALESCE(SUM(CAST(gas_cost AS REAL)), 0) as total_wei, COUNT(*) as tx_count
+FROM topup_transactions
+WHERE gas_cost != '' AND created_at >= datetime(@created_from) AND created_at < datetime(@created_to)
+GROUP BY period, chain ORDER BY period, chain;
+
+-- name: SurplusByChain :many
+SELECT chain, kind, COALESCE(SUM(CAST(surplus AS REAL)), 0) as total_surplus, COUNT(*) as order_count
+FROM gas_refills
+WHERE surplus != '0' AND created_at >= datetime(@created_from) AND created_at < datetime(@created_to)
+GROUP BY chain, kind ORDER BY chain, kind;
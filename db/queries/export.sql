@@ -0,0 +1,33 @@
+-- name: ExportTopups :many
+SELECT t.id, t.short_id, t.created_at, t.status, t.provider, t.user_id,
+       COALESCE(u.username, '') AS username, t.chat_id, COALESCE(c.title, '') AS chat_title,
+       t.from_chain, q.from_asset, q.to_asset, q.destination,
+       q.input_amount_usd, q.input_amount, q.expected_output,
+       t.actual_output, t.tx_hash, t.dest_tx_hash, t.refund_tx_hash, t.external_id,
+       CAST(COALESCE((SELECT GROUP_CONCAT(tt.gas_cost) FROM topup_transactions tt
+                      WHERE tt.topup_id = t.id AND tt.gas_cost != ''), '') AS TEXT) AS gas_costs
+FROM topups t
+JOIN quotes q ON t.quote_id = q.id
+LEFT JOIN users u ON u.telegram_id = t.user_id
+LEFT JOIN chats c ON c.chat_id = t.chat_id
+WHERE t.id > @after_id
+  AND t.created_at >= datetime(@created_from)
+  AND t.created_at < datetime(@created_to)
+ORDER BY t.id
+LIMIT @page_size;
+
+-- name: ExportQuotes :many
+SELECT q.id, q.created_at, q.type, q.provider, q.user_id,
+       COALESCE(u.username, '') AS username, q.chat_id, COALESCE(c.title, '') AS chat_title,
+       q.from_chain, q.from_asset, q.to_asset, q.destination,
+       q.input_amount_usd, q.input_amount, q.expected_output, q.expiry,
+       COALESCE(t.short_id, '') AS topup_short_id
+FROM quotes q
+LEFT JOIN topups t ON t.quote_id = q.id
+LEFT JOIN users u ON u.telegram_id = q.user_id
+LEFT JOIN chats c ON c.chat_id = q.chat_id
+WHERE q.id > @after_id
+  AND q.created_at >= datetime(@created_from)
+  AND q.created_at < datetime(@created_to)
+ORDER BY q.id
+LIMIT @page_size;
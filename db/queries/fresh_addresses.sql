@@ -0,0 +1,27 @@
+-- name: CreateFreshAddress :one
+INSERT INTO fresh_addresses (parent_index, parent_address, chain)
+VALUES (?, ?, ?)
+RETURNING id, parent_index, parent_address, address, chain, funding_tx_hash, topup_id, created_at;
+
+-- name: GetFreshAddress :one
+SELECT id, parent_index, parent_address, address, chain, funding_tx_hash, topup_id, created_at
+FROM fresh_addresses
+WHERE id = ?;
+
+-- name: SetFreshAddressFunding :exec
+UPDATE fresh_addresses SET address = ?, funding_tx_hash = ? WHERE id = ?;
+
+-- name: SetFreshAddressTopup :exec
+UPDATE fresh_addresses SET topup_id = ? WHERE id = ?;
+
+-- name: ListFreshAddressesByParent :many
+SELECT id, parent_index, parent_address, address, chain, funding_tx_hash, topup_id, created_at
+FROM fresh_addresses
+WHERE parent_index = ?
+ORDER BY id;
+
+-- name: ListFundedFreshAddresses :many
+SELECT id, parent_index, parent_address, address, chain, funding_tx_hash, topup_id, created_at
+FROM fresh_addresses
+WHERE address <> '' AND chain = ?
+ORDER BY id;
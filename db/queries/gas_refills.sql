@@ -1,11 +1,60 @@
 -- name: InsertGasRefill :one
-INSERT INTO gas_refills (chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO gas_refills (chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, wallet_index, attempt, order_type, valid_to, kind, partially_fillable)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id;
 
 -- name: ListPendingGasRefills :many
-SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
 FROM gas_refills WHERE status = 'open' ORDER BY created_at;
 
+-- name: ListRetryableGasRefills :many
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills
+WHERE status IN ('expired', 'cancelled', 'failed') AND order_type = 'market' AND wallet_index >= 0 AND attempt < ? AND resolution_note = ''
+  AND NOT EXISTS (
+    SELECT 1 FROM gas_refills newer
+    WHERE newer.wallet_address = gas_refills.wallet_address AND newer.chain = gas_refills.chain AND newer.id > gas_refills.id
+  )
+ORDER BY created_at;
+
 -- name: UpdateGasRefillStatus :exec
 UPDATE gas_refills SET status = ? WHERE id = ?;
+
+-- name: UpdateGasRefillFill :exec
+UPDATE gas_refills SET executed_sell_amount = ?, executed_buy_amount = ?, surplus = ? WHERE id = ?;
+
+-- name: GetGasRefill :one
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills WHERE id = ?;
+
+-- name: CancelGasRefill :execrows
+UPDATE gas_refills SET status = 'cancelled', resolution_note = ? WHERE id = ? AND status IN ('open', 'cancelled');
+
+-- name: CountGasRefillsBySale :one
+SELECT COUNT(*) FROM gas_refills
+WHERE chain = @chain AND wallet_address = @wallet_address AND (sell_amount = @sell_amount OR executed_sell_amount = @sell_amount);
+
+-- name: CountGasRefillsSince :one
+SELECT COUNT(*) FROM gas_refills
+WHERE chain = @chain AND wallet_address = @wallet_address AND attempt = 1 AND order_type = 'market'
+  AND created_at >= datetime(@created_from);
+
+-- name: ListOpenGasRefillsByWallet :many
+SELECT id, chain, order_uid, wallet_address, sell_amount, buy_amount, status, user_id, chat_id, created_at, wallet_index, attempt, resolution_note, order_type, valid_to, kind, partially_fillable, executed_sell_amount, executed_buy_amount, surplus
+FROM gas_refills WHERE wallet_address = ? AND status = 'open' ORDER BY created_at;
+
+-- name: CountOpenGasRefillsByUser :one
+SELECT COUNT(*) FROM gas_refills WHERE user_id = ? AND status = 'open';
+
+-- name: AnonymizeGasRefills :exec
+UPDATE gas_refills SET
+    user_id = CASE WHEN user_id = @telegram_id THEN @tombstone ELSE user_id END,
+    chat_id = CASE WHEN chat_id = @telegram_id THEN @tombstone ELSE chat_id END
+WHERE user_id = @telegram_id OR chat_id = @telegram_id;
+
+-- name: GetGasRefillCooldown :one
+SELECT attempted_at FROM gas_refill_cooldowns WHERE chain = ? AND wallet_address = ?;
+
+-- name: SetGasRefillCooldown :exec
+INSERT INTO gas_refill_cooldowns (chain, wallet_address) VALUES (?, ?)
+ON CONFLICT (chain, wallet_address) DO UPDATE SET attempted_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,18 @@
+-- name: AnonymizeGasTransfers :exec
+UPDATE gas_transfers SET
+    user_id = CASE WHEN user_id = @telegram_id THEN @tombstone ELSE user_id END,
+    chat_id = CASE WHEN chat_id = @telegram_id THEN @tombstone ELSE chat_id END
+WHERE user_id = @telegram_id OR chat_id = @telegram_id;
+
+-- name: InsertGasTransfer :one
+INSERT INTO gas_transfers (chain, wallet_index, wallet_address, treasury_address, amount, tx_hash, status, user_id, chat_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: CountGasTransfersSince :one
+SELECT COUNT(*) FROM gas_transfers
+WHERE chain = @chain AND wallet_address = @wallet_address AND created_at >= datetime(@created_from);
+
+-- name: CountChainGasTransfersSince :one
+SELECT COUNT(*) FROM gas_transfers
+WHERE chain = @chain AND created_at >= datetime(@created_from);
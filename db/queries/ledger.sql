@@ -0,0 +1,50 @@
+-- name: InsertLedgerEntry :exec
+INSERT OR IGNORE INTO ledger_entries (chain, asset, amount, from_account, to_account, kind, reference, note)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListChainLedgerEntries :many
+SELECT id, chain, asset, amount, from_account, to_account, kind, reference, note, created_at
+FROM ledger_entries WHERE chain = ?
+ORDER BY id;
+
+-- name: ListLedgerEntries :many
+SELECT id, chain, asset, amount, from_account, to_account, kind, reference, note, created_at
+FROM ledger_entries
+WHERE (@account = '' OR from_account = @account OR to_account = @account)
+  AND (@chain = '' OR chain = @chain)
+  AND (@kind = '' OR kind = @kind)
+ORDER BY id DESC
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: GetLedgerCursor :one
+SELECT block_number FROM ledger_cursors WHERE chain = ?;
+
+-- name: SetLedgerCursor :exec
+INSERT INTO ledger_cursors (chain, block_number) VALUES (?, ?)
+ON CONFLICT (chain) DO UPDATE SET
+    block_number = excluded.block_number,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: UpsertLedgerReconciliation :exec
+INSERT INTO ledger_reconciliations (account, chain, asset, ledger_balance, onchain_balance, difference, block_number)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (account, chain, asset) DO UPDATE SET
+    ledger_balance = excluded.ledger_balance,
+    onchain_balance = excluded.onchain_balance,
+    difference = excluded.difference,
+    block_number = excluded.block_number,
+    checked_at = CURRENT_TIMESTAMP;
+
+-- name: ListLedgerReconciliations :many
+SELECT account, chain, asset, ledger_balance, onchain_balance, difference, block_number, checked_at
+FROM ledger_reconciliations
+ORDER BY CASE WHEN difference = '0' THEN 1 ELSE 0 END, account, chain, asset;
+
+-- name: CountLedgerDiscrepancies :one
+SELECT COUNT(*) FROM ledger_reconciliations WHERE difference <> '0';
+
+-- name: CountLedgerEntries :one
+SELECT COUNT(*) FROM ledger_entries
+WHERE (@account = '' OR from_account = @account OR to_account = @account)
+  AND (@chain = '' OR chain = @chain)
+  AND (@kind = '' OR kind = @kind);
@@ -10,3 +10,22 @@ SELECT id, type, provider, user_id, from_asset, from_chain, to_asset, destinatio
     input_amount_usd, input_amount, expected_output, memo, router, vault_address, expiry, chat_id, created_at
 FROM quotes
 WHERE id = ?;
+
+-- name: InsertQuoteRaw :exec
+INSERT INTO quote_raw (quote_id, payload, size, truncated)
+VALUES (?, ?, ?, ?);
+
+-- name: GetQuoteRaw :one
+SELECT quote_id, payload, size, truncated FROM quote_raw WHERE quote_id = ?;
+
+-- name: AnonymizeQuotes :exec
+UPDATE quotes SET
+    user_id = CASE WHEN user_id = @telegram_id THEN @tombstone ELSE user_id END,
+    chat_id = CASE WHEN chat_id = @telegram_id THEN @tombstone ELSE chat_id END
+WHERE user_id = @telegram_id OR chat_id = @telegram_id;
+
+-- name: ListQuoteOwners :many
+SELECT user_id, chat_id FROM quotes
+WHERE user_id > 0
+GROUP BY user_id, chat_id
+ORDER BY MIN(id);
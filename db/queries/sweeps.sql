@@ -0,0 +1,12 @@
+-- name: InsertSweep :one
+INSERT INTO sweeps (chain, wallet_index, wallet_address, treasury_address, amount, method, reference, status)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: GetLastSweep :one
+SELECT id, chain, wallet_index, wallet_address, treasury_address, amount, method, reference, status, created_at
+FROM sweeps WHERE wallet_address = ? AND chain = ?
+ORDER BY created_at DESC, id DESC LIMIT 1;
+
+-- name: CountCowSweeps :one
+SELECT COUNT(*) FROM sweeps WHERE chain = ? AND wallet_address = ? AND method = 'cow' AND amount = ?;
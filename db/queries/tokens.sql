@@ -0,0 +1,37 @@
+-- name: ListTokens :many
+SELECT id, chain, symbol, contract_address, thorchain_asset, simpleswap_symbol, nearintents_token_id, houdini_symbol, stealthex_currency, relay_currency, enabled, note, changed_by, created_at, updated_at
+FROM tokens ORDER BY chain, symbol;
+
+-- name: GetToken :one
+SELECT id, chain, symbol, contract_address, thorchain_asset, simpleswap_symbol, nearintents_token_id, houdini_symbol, stealthex_currency, relay_currency, enabled, note, changed_by, created_at, updated_at
+FROM tokens WHERE id = ?;
+
+-- name: GetTokenBySymbol :one
+SELECT id, chain, symbol, contract_address, thorchain_asset, simpleswap_symbol, nearintents_token_id, houdini_symbol, stealthex_currency, relay_currency, enabled, note, changed_by, created_at, updated_at
+FROM tokens WHERE chain = ? AND symbol = ?;
+
+-- name: GetTokenByContract :one
+SELECT id, chain, symbol, contract_address, thorchain_asset, simpleswap_symbol, nearintents_token_id, houdini_symbol, stealthex_currency, relay_currency, enabled, note, changed_by, created_at, updated_at
+FROM tokens WHERE chain = @chain AND contract_address != '' AND LOWER(contract_address) = LOWER(@contract_address)
+ORDER BY id LIMIT 1;
+
+-- name: UpsertToken :exec
+INSERT INTO tokens (
+    chain, symbol, contract_address, thorchain_asset, simpleswap_symbol, nearintents_token_id,
+    houdini_symbol, stealthex_currency, relay_currency, enabled, note, changed_by
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (chain, symbol) DO UPDATE SET
+    contract_address = excluded.contract_address,
+    thorchain_asset = excluded.thorchain_asset,
+    simpleswap_symbol = excluded.simpleswap_symbol,
+    nearintents_token_id = excluded.nearintents_token_id,
+    houdini_symbol = excluded.houdini_symbol,
+    stealthex_currency = excluded.stealthex_currency,
+    relay_currency = excluded.relay_currency,
+    enabled = excluded.enabled,
+    note = excluded.note,
+    changed_by = excluded.changed_by,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteToken :execrows
+DELETE FROM tokens WHERE id = ?;
@@ -0,0 +1,13 @@
+-- name: InsertTopupStatusEvent :exec
+INSERT INTO topup_status_events (topup_id, status, raw_status)
+VALUES (?, ?, ?);
+
+-- name: GetLatestTopupStatusEvent :one
+SELECT id, topup_id, status, raw_status, created_at
+FROM topup_status_events WHERE topup_id = ?
+ORDER BY created_at DESC, id DESC LIMIT 1;
+
+-- name: ListTopupStatusEvents :many
+SELECT id, topup_id, status, raw_status, created_at
+FROM topup_status_events WHERE topup_id = ?
+ORDER BY created_at, id;
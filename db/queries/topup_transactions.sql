@@ -0,0 +1,30 @@
+-- name: InsertTopupTransaction :exec
+INSERT OR IGNORE INTO topup_transactions (topup_id, chain, tx_hash, kind)
+VALUES (?, ?, ?, ?);
+
+-- name: ListTopupTransactions :many
+SELECT id, topup_id, chain, tx_hash, kind, gas_used, gas_cost, created_at
+FROM topup_transactions WHERE topup_id = ?
+ORDER BY id;
+
+-- name: ListUnpricedTopupTransactions :many
+SELECT id, topup_id, chain, tx_hash, kind, gas_used, gas_cost, created_at
+FROM topup_transactions
+WHERE gas_cost = '' AND created_at > datetime('now', '-1 day')
+ORDER BY id LIMIT 50;
+
+-- name: SetTopupTransactionGas :exec
+UPDATE topup_transactions SET gas_used = ?, gas_cost = ? WHERE id = ?;
+
+-- name: CountTopupTransactionsByHash :one
+SELECT COUNT(*) FROM topup_transactions WHERE chain = ? AND tx_hash = ?;
+
+-- name: ListUnledgeredTopupGas :many
+SELECT DISTINCT tx_hash, gas_cost
+FROM topup_transactions
+WHERE chain = ? AND gas_cost <> ''
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_entries
+    WHERE ledger_entries.chain = topup_transactions.chain AND ledger_entries.kind = 'gas' AND ledger_entries.reference = topup_transactions.tx_hash
+  )
+ORDER BY tx_hash LIMIT 100;
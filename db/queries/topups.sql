@@ -1,16 +1,51 @@
 -- name: InsertTopup :one
-INSERT INTO topups (short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO topups (short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, wallet_index)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, short_id;
 
 -- name: GetTopupByShortID :one
-SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, created_at
+SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, refund_tx_hash, actual_output, dest_tx_hash, created_at
 FROM topups
 WHERE short_id = ?;
 
 -- name: UpdateTopupStatus :exec
 UPDATE topups SET status = ? WHERE id = ?;
 
+-- name: SetTopupResult :exec
+UPDATE topups SET actual_output = ?, dest_tx_hash = ? WHERE id = ?;
+
+-- name: SetTopupProgressMessage :exec
+UPDATE topups SET progress_message_id = ? WHERE id = ?;
+
+-- name: SetTopupRefunded :exec
+UPDATE topups SET status = 'refunded', refund_tx_hash = ? WHERE id = ?;
+
 -- name: ListPendingTopups :many
-SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, created_at
-FROM topups WHERE status = 'pending' ORDER BY created_at;
+SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, progress_message_id, created_at
+FROM topups WHERE status IN ('pending', 'stalled') ORDER BY created_at;
+
+-- name: ListUserTopups :many
+SELECT t.short_id, t.provider, t.from_chain, t.tx_hash, t.status, t.created_at, t.actual_output, t.dest_tx_hash,
+       q.to_asset, q.destination, q.input_amount_usd, q.expected_output
+FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.user_id = ?
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT ?;
+
+-- name: ResolveTopup :execrows
+UPDATE topups SET status = 'resolved', resolution_note = ? WHERE id = ? AND status = ?;
+
+-- name: CountOpenTopupsByUser :one
+SELECT COUNT(*) FROM topups WHERE user_id = ? AND status IN ('pending', 'stalled');
+
+-- name: AnonymizeTopups :exec
+UPDATE topups SET
+    user_id = CASE WHEN user_id = @telegram_id THEN @tombstone ELSE user_id END,
+    chat_id = CASE WHEN chat_id = @telegram_id THEN @tombstone ELSE chat_id END
+WHERE user_id = @telegram_id OR chat_id = @telegram_id;
+
+-- name: CountTopupsByWalletIndex :one
+SELECT COUNT(*) FROM topups WHERE wallet_index = ?;
+
+-- name: SetLegacyTopupWalletIndex :execrows
+UPDATE topups SET wallet_index = ? WHERE wallet_index = -1;
@@ -1,9 +1,14 @@
 -- name: GetUserByTelegramID :one
-SELECT id, telegram_id, username, created_at
+SELECT id, telegram_id, username, created_at, deleted_at
 FROM users
 WHERE telegram_id = ?;
 
 -- name: CreateUser :one
 INSERT INTO users (telegram_id, username)
 VALUES (?, ?)
-RETURNING id, telegram_id, username, created_at;
+RETURNING id, telegram_id, username, created_at, deleted_at;
+
+-- name: AnonymizeUser :one
+UPDATE users SET telegram_id = -id, username = '', deleted_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, telegram_id, username, created_at, deleted_at;
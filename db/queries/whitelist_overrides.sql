@@ -0,0 +1,13 @@
+-- name: ListWhitelistOverrides :many
+SELECT user_id, allowed, note, changed_by, changed_at FROM whitelist_overrides ORDER BY user_id;
+
+-- name: UpsertWhitelistOverride :exec
+INSERT INTO whitelist_overrides (user_id, allowed, note, changed_by) VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    allowed = excluded.allowed,
+    note = excluded.note,
+    changed_by = excluded.changed_by,
+    changed_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteWhitelistOverride :exec
+DELETE FROM whitelist_overrides WHERE user_id = ?;
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+)
+
+// SaveQuoteRaw stores the provider responses behind a quote, gzipped.
+// truncated records that payload was already cut down to size.
+func (s *Store) SaveQuoteRaw(ctx context.Context, quoteID int64, payload []byte, truncated bool) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return s.InsertQuoteRaw(ctx, InsertQuoteRawParams{
+		QuoteID:   quoteID,
+		Payload:   buf.Bytes(),
+		Size:      int64(len(payload)),
+		Truncated: truncated,
+	})
+}
+
+// LoadQuoteRaw returns the provider responses stored for a quote,
+// decompressed, and whether they were truncated. sql.ErrNoRows means none
+// were stored.
+func (s *Store) LoadQuoteRaw(ctx context.Context, quoteID int64) ([]byte, bool, error) {
+	raw, err := s.GetQuoteRaw(ctx, quoteID)
+	if err != nil {
+		return nil, false, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw.Payload))
+	if err != nil {
+		return nil, false, err
+	}
+	defer zr.Close()
+	payload, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, false, err
+	}
+	return payload, raw.Truncated, nil
+}
@@ -10,6 +10,23 @@ import (
 	"time"
 )
 
+const anonymizeQuotes = `-- name: AnonymizeQuotes :exec
+UPDATE quotes SET
+    user_id = CASE WHEN user_id = ?1 THEN ?2 ELSE user_id END,
+    chat_id = CASE WHEN chat_id = ?1 THEN ?2 ELSE chat_id END
+WHERE user_id = ?1 OR chat_id = ?1
+`
+
+type AnonymizeQuotesParams struct {
+	TelegramID int64
+	Tombstone  interface{}
+}
+
+func (q *Queries) AnonymizeQuotes(ctx context.Context, arg AnonymizeQuotesParams) error {
+	_, err := q.db.ExecContext(ctx, anonymizeQuotes, arg.TelegramID, arg.Tombstone)
+	return err
+}
+
 const getQuote = `-- name: GetQuote :one
 SELECT id, type, provider, user_id, from_asset, from_chain, to_asset, destination,
     input_amount_usd, input_amount, expected_output, memo, router, vault_address, expiry, chat_id, created_at
@@ -62,6 +79,22 @@ func (q *Queries) GetQuote(ctx context.Context, id int64) (GetQuoteRow, error) {
 	return i, err
 }
 
+const getQuoteRaw = `-- name: GetQuoteRaw :one
+SELECT quote_id, payload, size, truncated FROM quote_raw WHERE quote_id = ?
+`
+
+func (q *Queries) GetQuoteRaw(ctx context.Context, quoteID int64) (QuoteRaw, error) {
+	row := q.db.QueryRowContext(ctx, getQuoteRaw, quoteID)
+	var i QuoteRaw
+	err := row.Scan(
+		&i.QuoteID,
+		&i.Payload,
+		&i.Size,
+		&i.Truncated,
+	)
+	return i, err
+}
+
 const insertQuote = `-- name: InsertQuote :one
 INSERT INTO quotes (
     type, provider, user_id, from_asset, from_chain, to_asset, destination,
@@ -110,3 +143,60 @@ func (q *Queries) InsertQuote(ctx context.Context, arg InsertQuoteParams) (int64
 	err := row.Scan(&id)
 	return id, err
 }
+
+const insertQuoteRaw = `-- name: InsertQuoteRaw :exec
+INSERT INTO quote_raw (quote_id, payload, size, truncated)
+VALUES (?, ?, ?, ?)
+`
+
+type InsertQuoteRawParams struct {
+	QuoteID   int64
+	Payload   []byte
+	Size      int64
+	Truncated bool
+}
+
+func (q *Queries) InsertQuoteRaw(ctx context.Context, arg InsertQuoteRawParams) error {
+	_, err := q.db.ExecContext(ctx, insertQuoteRaw,
+		arg.QuoteID,
+		arg.Payload,
+		arg.Size,
+		arg.Truncated,
+	)
+	return err
+}
+
+const listQuoteOwners = `-- name: ListQuoteOwners :many
+SELECT user_id, chat_id FROM quotes
+WHERE user_id > 0
+GROUP BY user_id, chat_id
+ORDER BY MIN(id)
+`
+
+type ListQuoteOwnersRow struct {
+	UserID int64
+	ChatID int64
+}
+
+func (q *Queries) ListQuoteOwners(ctx context.Context) ([]ListQuoteOwnersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listQuoteOwners)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListQuoteOwnersRow
+	for rows.Next() {
+		var i ListQuoteOwnersRow
+		if err := rows.Scan(&i.UserID, &i.ChatID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqlcipherPragmas are the settings of sqliteParams. mattn's driver applies
+// DSN parameters before a ConnectHook runs, when an encrypted file can't be
+// read yet, so encrypted connections run them after the key instead.
+var sqlcipherPragmas = []string{
+	"PRAGMA journal_mode = WAL",
+	"PRAGMA synchronous = NORMAL",
+	"PRAGMA busy_timeout = 5000",
+	"PRAGMA foreign_keys = ON",
+}
+
+var errNoSQLCipher = errors.New("the SQLite library fundbot is linked against is not SQLCipher; build with -tags libsqlite3 against SQLCipher to use database_encrypted")
+
+// openSQLCipher opens the SQLite file at path with every connection keyed
+// before it reads anything. Encryption needs the binary linked against
+// SQLCipher rather than mattn's bundled SQLite; plain SQLite would accept
+// the key and ignore it, so connections check for SQLCipher first.
+func openSQLCipher(path, key string) *sql.DB {
+	return sql.OpenDB(sqlcipherConnector{
+		path: path,
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				if _, err := conn.Exec("PRAGMA key = "+quoteSQL(key), nil); err != nil {
+					return err
+				}
+				if err := checkSQLCipher(conn); err != nil {
+					return err
+				}
+				for _, pragma := range sqlcipherPragmas {
+					if _, err := conn.Exec(pragma, nil); err != nil {
+						// The first read of a file under the wrong key
+						return fmt.Errorf("%s (wrong key?): %w", pragma, err)
+					}
+				}
+				return nil
+			},
+		},
+	})
+}
+
+// sqlcipherConnector opens connections with a driver of its own, so the
+// key stays out of the DSN and the global driver registry.
+type sqlcipherConnector struct {
+	path   string
+	driver *sqlite3.SQLiteDriver
+}
+
+func (c sqlcipherConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.path)
+}
+
+func (c sqlcipherConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// checkSQLCipher returns errNoSQLCipher unless conn is SQLCipher, which
+// answers PRAGMA cipher_version.
+func checkSQLCipher(conn *sqlite3.SQLiteConn) error {
+	rows, err := conn.Query("PRAGMA cipher_version", nil)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if err := rows.Next(make([]driver.Value, len(rows.Columns()))); err == io.EOF {
+		return errNoSQLCipher
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// quoteSQL quotes s as an SQL string literal.
+func quoteSQL(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// EncryptTo writes a copy of an unencrypted SQLite database to dest,
+// encrypted under key with sqlcipher_export, for moving a deployment to an
+// encrypted database. The backup API can't copy between encrypted and
+// plain files.
+func (s *Store) EncryptTo(ctx context.Context, dest, key string) error {
+	if key == "" {
+		return fmt.Errorf("database key is empty")
+	}
+	conn, err := s.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting database connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Plain SQLite would attach dest unencrypted
+	err = conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("only a SQLite database can be encrypted")
+		}
+		return checkSQLCipher(c)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS encrypted KEY ?", dest, key); err != nil {
+		return fmt.Errorf("creating %s: %w", dest, err)
+	}
+	defer conn.ExecContext(context.Background(), "DETACH DATABASE encrypted")
+	if _, err := conn.ExecContext(ctx, "SELECT sqlcipher_export('encrypted')"); err != nil {
+		return fmt.Errorf("copying database: %w", err)
+	}
+	return nil
+}
@@ -7,39 +7,111 @@ import (
 	"embed"
 	"encoding/hex"
 	"fmt"
+	"net/url"
+	"strings"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/pressly/goose/v3"
 )
 
-//go:embed migrations/*.sql
+//go:embed migrations/*.sql migrations/postgres/*.sql
 var migrations embed.FS
 
+// sqliteParams are added to every SQLite DSN, so each pooled connection
+// gets them. WAL lets readers carry on while another connection writes, and
+// with it synchronous=NORMAL is still crash-safe. busy_timeout makes a writer
+// wait for the lock rather than fail at once with SQLITE_BUSY. Foreign keys
+// are off by default in SQLite.
+const sqliteParams = "_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000&_foreign_keys=on"
+
+// sqliteMaxConns bounds the SQLite pool. Readers run side by side, but
+// writers queue on SQLite's single write lock, so a larger pool only adds
+// contention. Idle connections are kept rather than reopened.
+const sqliteMaxConns = 8
+
 // Store wraps sqlc Queries with connection management and helpers.
 type Store struct {
 	*Queries
 	conn *sql.DB
+	key  string // SQLCipher key, if the SQLite file is encrypted
 }
 
-func Open(path string) (*Store, error) {
-	conn, err := sql.Open("sqlite3", path)
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
+// Open connects to the database named by dsn and applies any pending
+// migrations. A postgres:// or postgresql:// URL selects PostgreSQL; anything
+// else is a SQLite file path.
+func Open(dsn string) (*Store, error) {
+	return open(dsn, "")
+}
+
+// OpenEncrypted opens the SQLite database at path, encrypted with SQLCipher
+// under key, creating it encrypted if it doesn't exist. The binary must be
+// linked against SQLCipher; see openSQLCipher.
+func OpenEncrypted(path, key string) (*Store, error) {
+	if key == "" {
+		return nil, fmt.Errorf("database key is empty")
+	}
+	return open(path, key)
+}
+
+func open(dsn, key string) (*Store, error) {
+	driver, dialect, dir, source := "sqlite3", "sqlite3", "migrations", dsn
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("parsing database URL: %w", err)
+		}
+		// Timestamps are stored in UTC, as SQLite's CURRENT_TIMESTAMP does
+		q := u.Query()
+		q.Set("timezone", "UTC")
+		u.RawQuery = q.Encode()
+		driver, dialect, dir, source = "postgres", "postgres", "migrations/postgres", u.String()
+	} else if key == "" {
+		// openSQLCipher applies sqliteParams itself, once the key is set
+		if strings.Contains(source, "?") {
+			source += "&" + sqliteParams
+		} else {
+			source += "?" + sqliteParams
+		}
+	}
+
+	var conn *sql.DB
+	if key != "" {
+		// Connections are only made once used, so check the key up front
+		conn = openSQLCipher(source, key)
+		if err := conn.Ping(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("opening encrypted database: %w", err)
+		}
+	} else {
+		var err error
+		if conn, err = sql.Open(driver, source); err != nil {
+			return nil, fmt.Errorf("opening database: %w", err)
+		}
+	}
+	if driver == "sqlite3" {
+		conn.SetMaxOpenConns(sqliteMaxConns)
+		conn.SetMaxIdleConns(sqliteMaxConns)
 	}
 
 	goose.SetBaseFS(migrations)
-	if err := goose.SetDialect("sqlite3"); err != nil {
+	if err := goose.SetDialect(dialect); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("setting goose dialect: %w", err)
 	}
-	if err := goose.Up(conn, "migrations"); err != nil {
+	if err := goose.Up(conn, dir); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("running migrations: %w", err)
 	}
 
+	var queries DBTX = conn
+	if driver == "postgres" {
+		queries = &postgresDB{conn: conn}
+	}
 	return &Store{
-		Queries: New(conn),
+		Queries: New(queries),
 		conn:    conn,
+		key:     key,
 	}, nil
 }
 
@@ -47,9 +119,33 @@ func (s *Store) Close() error {
 	return s.conn.Close()
 }
 
+// Ping checks that the database answers queries.
+func (s *Store) Ping(ctx context.Context) error {
+	var one int
+	return s.conn.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// inTx runs fn with queries bound to a transaction, committing it if fn
+// succeeds and rolling it back otherwise.
+func (s *Store) inTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var queries DBTX = tx
+	if _, ok := s.db.(*postgresDB); ok {
+		queries = &postgresDB{conn: tx}
+	}
+	if err := fn(New(queries)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // GetOrCreateUser returns the user for a telegram ID, creating one if needed.
-func (s *Store) GetOrCreateUser(ctx context.Context, telegramID int64, username string) (User, error) {
-	user, err := s.GetUserByTelegramID(ctx, telegramID)
+func (q *Queries) GetOrCreateUser(ctx context.Context, telegramID int64, username string) (User, error) {
+	user, err := q.GetUserByTelegramID(ctx, telegramID)
 	if err == nil {
 		return user, nil
 	}
@@ -57,15 +153,15 @@ func (s *Store) GetOrCreateUser(ctx context.Context, telegramID int64, username
 		return User{}, fmt.Errorf("querying user: %w", err)
 	}
 
-	return s.CreateUser(ctx, CreateUserParams{
+	return q.CreateUser(ctx, CreateUserParams{
 		TelegramID: telegramID,
 		Username:   username,
 	})
 }
 
 // GetOrCreateChat returns the chat for a Telegram chat ID, creating one if needed.
-func (s *Store) GetOrCreateChat(ctx context.Context, chatID int64, title string) (Chat, error) {
-	chat, err := s.GetChatByChatID(ctx, chatID)
+func (q *Queries) GetOrCreateChat(ctx context.Context, chatID int64, title string) (Chat, error) {
+	chat, err := q.GetChatByChatID(ctx, chatID)
 	if err == nil {
 		return chat, nil
 	}
@@ -73,15 +169,16 @@ func (s *Store) GetOrCreateChat(ctx context.Context, chatID int64, title string)
 		return Chat{}, fmt.Errorf("querying chat: %w", err)
 	}
 
-	return s.CreateChat(ctx, CreateChatParams{
+	return q.CreateChat(ctx, CreateChatParams{
 		ChatID: chatID,
 		Title:  title,
 	})
 }
 
-// GetOrCreateAddressAssignment returns the address assignment for the given entity, creating one if needed.
-func (s *Store) GetOrCreateAddressAssignment(ctx context.Context, assignedToID int64, assignedToType string) (AddressAssignment, error) {
-	a, err := s.GetAddressAssignment(ctx, GetAddressAssignmentParams{
+// GetOrCreateAddressAssignment returns the address assignment for the given entity, creating one
+// in pool if needed. An existing assignment keeps its pool.
+func (q *Queries) GetOrCreateAddressAssignment(ctx context.Context, assignedToID int64, assignedToType string, pool string) (AddressAssignment, error) {
+	a, err := q.GetAddressAssignment(ctx, GetAddressAssignmentParams{
 		AssignedToID:   assignedToID,
 		AssignedToType: assignedToType,
 	})
@@ -92,12 +189,44 @@ func (s *Store) GetOrCreateAddressAssignment(ctx context.Context, assignedToID i
 		return AddressAssignment{}, fmt.Errorf("querying address assignment: %w", err)
 	}
 
-	return s.CreateAddressAssignment(ctx, CreateAddressAssignmentParams{
+	return q.CreateAddressAssignment(ctx, CreateAddressAssignmentParams{
 		AssignedToID:   assignedToID,
 		AssignedToType: assignedToType,
+		Pool:           pool,
 	})
 }
 
+// FreshIndexBase offsets fresh_addresses IDs into their own range of wallet
+// indexes, well clear of address assignment IDs.
+const FreshIndexBase = 1 << 30
+
+// FreshIndex returns the wallet index that derives a fresh address.
+func FreshIndex(id int64) uint32 {
+	return uint32(FreshIndexBase + id)
+}
+
+// WalletPool returns the pool that derives a wallet index: the assignment's
+// pool, the parent wallet's pool for fresh addresses, or the default pool for
+// unassigned indexes such as the single-mode wallet.
+func (s *Store) WalletPool(ctx context.Context, index uint32) (string, error) {
+	if index >= FreshIndexBase {
+		fresh, err := s.GetFreshAddress(ctx, int64(index-FreshIndexBase))
+		if err != nil {
+			return "", fmt.Errorf("querying fresh address: %w", err)
+		}
+		index = uint32(fresh.ParentIndex)
+	}
+
+	a, err := s.GetAddressAssignmentByID(ctx, int64(index))
+	if err == sql.ErrNoRows {
+		return "default", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("querying address assignment: %w", err)
+	}
+	return a.Pool, nil
+}
+
 // InsertTopupWithShortID generates a random short ID and inserts the topup.
 func (s *Store) InsertTopupWithShortID(ctx context.Context, arg InsertTopupParams) (InsertTopupRow, error) {
 	arg.ShortID = generateShortID()
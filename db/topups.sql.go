@@ -10,25 +10,67 @@ import (
 	"time"
 )
 
+const anonymizeTopups = `-- name: AnonymizeTopups :exec
+UPDATE topups SET
+    user_id = CASE WHEN user_id = ?1 THEN ?2 ELSE user_id END,
+    chat_id = CASE WHEN chat_id = ?1 THEN ?2 ELSE chat_id END
+WHERE user_id = ?1 OR chat_id = ?1
+`
+
+type AnonymizeTopupsParams struct {
+	TelegramID int64
+	Tombstone  interface{}
+}
+
+func (q *Queries) AnonymizeTopups(ctx context.Context, arg AnonymizeTopupsParams) error {
+	_, err := q.db.ExecContext(ctx, anonymizeTopups, arg.TelegramID, arg.Tombstone)
+	return err
+}
+
+const countOpenTopupsByUser = `-- name: CountOpenTopupsByUser :one
+SELECT COUNT(*) FROM topups WHERE user_id = ? AND status IN ('pending', 'stalled')
+`
+
+func (q *Queries) CountOpenTopupsByUser(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenTopupsByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTopupsByWalletIndex = `-- name: CountTopupsByWalletIndex :one
+SELECT COUNT(*) FROM topups WHERE wallet_index = ?
+`
+
+func (q *Queries) CountTopupsByWalletIndex(ctx context.Context, walletIndex int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTopupsByWalletIndex, walletIndex)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getTopupByShortID = `-- name: GetTopupByShortID :one
-SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, created_at
+SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, refund_tx_hash, actual_output, dest_tx_hash, created_at
 FROM topups
 WHERE short_id = ?
 `
 
 type GetTopupByShortIDRow struct {
-	ID         int64
-	ShortID    string
-	Type       string
-	QuoteID    int64
-	UserID     int64
-	Provider   string
-	FromChain  string
-	TxHash     string
-	Status     string
-	ChatID     int64
-	ExternalID string
-	CreatedAt  time.Time
+	ID           int64
+	ShortID      string
+	Type         string
+	QuoteID      int64
+	UserID       int64
+	Provider     string
+	FromChain    string
+	TxHash       string
+	Status       string
+	ChatID       int64
+	ExternalID   string
+	RefundTxHash string
+	ActualOutput string
+	DestTxHash   string
+	CreatedAt    time.Time
 }
 
 func (q *Queries) GetTopupByShortID(ctx context.Context, shortID string) (GetTopupByShortIDRow, error) {
@@ -46,28 +88,32 @@ func (q *Queries) GetTopupByShortID(ctx context.Context, shortID string) (GetTop
 		&i.Status,
 		&i.ChatID,
 		&i.ExternalID,
+		&i.RefundTxHash,
+		&i.ActualOutput,
+		&i.DestTxHash,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const insertTopup = `-- name: InsertTopup :one
-INSERT INTO topups (short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO topups (short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, wallet_index)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, short_id
 `
 
 type InsertTopupParams struct {
-	ShortID    string
-	Type       string
-	QuoteID    int64
-	UserID     int64
-	Provider   string
-	FromChain  string
-	TxHash     string
-	Status     string
-	ChatID     int64
-	ExternalID string
+	ShortID     string
+	Type        string
+	QuoteID     int64
+	UserID      int64
+	Provider    string
+	FromChain   string
+	TxHash      string
+	Status      string
+	ChatID      int64
+	ExternalID  string
+	WalletIndex int64
 }
 
 type InsertTopupRow struct {
@@ -87,6 +133,7 @@ func (q *Queries) InsertTopup(ctx context.Context, arg InsertTopupParams) (Inser
 		arg.Status,
 		arg.ChatID,
 		arg.ExternalID,
+		arg.WalletIndex,
 	)
 	var i InsertTopupRow
 	err := row.Scan(&i.ID, &i.ShortID)
@@ -94,23 +141,24 @@ func (q *Queries) InsertTopup(ctx context.Context, arg InsertTopupParams) (Inser
 }
 
 const listPendingTopups = `-- name: ListPendingTopups :many
-SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, created_at
-FROM topups WHERE status = 'pending' ORDER BY created_at
+SELECT id, short_id, type, quote_id, user_id, provider, from_chain, tx_hash, status, chat_id, external_id, progress_message_id, created_at
+FROM topups WHERE status IN ('pending', 'stalled') ORDER BY created_at
 `
 
 type ListPendingTopupsRow struct {
-	ID         int64
-	ShortID    string
-	Type       string
-	QuoteID    int64
-	UserID     int64
-	Provider   string
-	FromChain  string
-	TxHash     string
-	Status     string
-	ChatID     int64
-	ExternalID string
-	CreatedAt  time.Time
+	ID                int64
+	ShortID           string
+	Type              string
+	QuoteID           int64
+	UserID            int64
+	Provider          string
+	FromChain         string
+	TxHash            string
+	Status            string
+	ChatID            int64
+	ExternalID        string
+	ProgressMessageID int64
+	CreatedAt         time.Time
 }
 
 func (q *Queries) ListPendingTopups(ctx context.Context) ([]ListPendingTopupsRow, error) {
@@ -134,7 +182,73 @@ func (q *Queries) ListPendingTopups(ctx context.Context) ([]ListPendingTopupsRow
 			&i.Status,
 			&i.ChatID,
 			&i.ExternalID,
+			&i.ProgressMessageID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTopups = `-- name: ListUserTopups :many
+SELECT t.short_id, t.provider, t.from_chain, t.tx_hash, t.status, t.created_at, t.actual_output, t.dest_tx_hash,
+       q.to_asset, q.destination, q.input_amount_usd, q.expected_output
+FROM topups t JOIN quotes q ON t.quote_id = q.id
+WHERE t.user_id = ?
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT ?
+`
+
+type ListUserTopupsParams struct {
+	UserID int64
+	Limit  int64
+}
+
+type ListUserTopupsRow struct {
+	ShortID        string
+	Provider       string
+	FromChain      string
+	TxHash         string
+	Status         string
+	CreatedAt      time.Time
+	ActualOutput   string
+	DestTxHash     string
+	ToAsset        string
+	Destination    string
+	InputAmountUsd float64
+	ExpectedOutput string
+}
+
+func (q *Queries) ListUserTopups(ctx context.Context, arg ListUserTopupsParams) ([]ListUserTopupsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTopups, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserTopupsRow
+	for rows.Next() {
+		var i ListUserTopupsRow
+		if err := rows.Scan(
+			&i.ShortID,
+			&i.Provider,
+			&i.FromChain,
+			&i.TxHash,
+			&i.Status,
 			&i.CreatedAt,
+			&i.ActualOutput,
+			&i.DestTxHash,
+			&i.ToAsset,
+			&i.Destination,
+			&i.InputAmountUsd,
+			&i.ExpectedOutput,
 		); err != nil {
 			return nil, err
 		}
@@ -149,6 +263,79 @@ func (q *Queries) ListPendingTopups(ctx context.Context) ([]ListPendingTopupsRow
 	return items, nil
 }
 
+const resolveTopup = `-- name: ResolveTopup :execrows
+UPDATE topups SET status = 'resolved', resolution_note = ? WHERE id = ? AND status = ?
+`
+
+type ResolveTopupParams struct {
+	ResolutionNote string
+	ID             int64
+	Status         string
+}
+
+func (q *Queries) ResolveTopup(ctx context.Context, arg ResolveTopupParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resolveTopup, arg.ResolutionNote, arg.ID, arg.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setLegacyTopupWalletIndex = `-- name: SetLegacyTopupWalletIndex :execrows
+UPDATE topups SET wallet_index = ? WHERE wallet_index = -1
+`
+
+func (q *Queries) SetLegacyTopupWalletIndex(ctx context.Context, walletIndex int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setLegacyTopupWalletIndex, walletIndex)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTopupProgressMessage = `-- name: SetTopupProgressMessage :exec
+UPDATE topups SET progress_message_id = ? WHERE id = ?
+`
+
+type SetTopupProgressMessageParams struct {
+	ProgressMessageID int64
+	ID                int64
+}
+
+func (q *Queries) SetTopupProgressMessage(ctx context.Context, arg SetTopupProgressMessageParams) error {
+	_, err := q.db.ExecContext(ctx, setTopupProgressMessage, arg.ProgressMessageID, arg.ID)
+	return err
+}
+
+const setTopupRefunded = `-- name: SetTopupRefunded :exec
+UPDATE topups SET status = 'refunded', refund_tx_hash = ? WHERE id = ?
+`
+
+type SetTopupRefundedParams struct {
+	RefundTxHash string
+	ID           int64
+}
+
+func (q *Queries) SetTopupRefunded(ctx context.Context, arg SetTopupRefundedParams) error {
+	_, err := q.db.ExecContext(ctx, setTopupRefunded, arg.RefundTxHash, arg.ID)
+	return err
+}
+
+const setTopupResult = `-- name: SetTopupResult :exec
+UPDATE topups SET actual_output = ?, dest_tx_hash = ? WHERE id = ?
+`
+
+type SetTopupResultParams struct {
+	ActualOutput string
+	DestTxHash   string
+	ID           int64
+}
+
+func (q *Queries) SetTopupResult(ctx context.Context, arg SetTopupResultParams) error {
+	_, err := q.db.ExecContext(ctx, setTopupResult, arg.ActualOutput, arg.DestTxHash, arg.ID)
+	return err
+}
+
 const updateTopupStatus = `-- name: UpdateTopupStatus :exec
 UPDATE topups SET status = ? WHERE id = ?
 `
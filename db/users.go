@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrUserBusy is returned by WipeUser while the user has a topup or gas
+// refill in flight, or one awaiting approval, whose updates are still
+// addressed to them.
+var ErrUserBusy = errors.New("user has topups or gas refills in progress")
+
+// WipeUser anonymizes the user with a Telegram ID. Their row becomes a
+// tombstone with telegram_id -id and no username, and the quotes, topups,
+// gas refills and broadcast deliveries that named them by Telegram ID or
+// private chat now name the tombstone, so the financial records stay
+// joined to the user and their wallet index. The address assignment is
+// kept, so the index is never handed out again; if they come back they are
+// a new user with a new index. Whitelist overrides and announcement
+// opt-outs for them are deleted. sql.ErrNoRows means there is no such
+// user, or they were already wiped.
+func (s *Store) WipeUser(ctx context.Context, telegramID int64) (User, error) {
+	var user User
+	err := s.inTx(ctx, func(q *Queries) error {
+		var err error
+		if user, err = q.GetUserByTelegramID(ctx, telegramID); err != nil {
+			return err
+		}
+		if user.DeletedAt.Valid {
+			return sql.ErrNoRows
+		}
+		topups, err := q.CountOpenTopupsByUser(ctx, telegramID)
+		if err != nil {
+			return fmt.Errorf("counting topups: %w", err)
+		}
+		refills, err := q.CountOpenGasRefillsByUser(ctx, telegramID)
+		if err != nil {
+			return fmt.Errorf("counting gas refills: %w", err)
+		}
+		approvals, err := q.CountPendingApprovalsByUser(ctx, telegramID)
+		if err != nil {
+			return fmt.Errorf("counting approvals: %w", err)
+		}
+		if topups > 0 || refills > 0 || approvals > 0 {
+			return ErrUserBusy
+		}
+
+		if user, err = q.AnonymizeUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("anonymizing user: %w", err)
+		}
+		tombstone := user.TelegramID
+		if err := q.AnonymizeQuotes(ctx, AnonymizeQuotesParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing quotes: %w", err)
+		}
+		if err := q.AnonymizeTopups(ctx, AnonymizeTopupsParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing topups: %w", err)
+		}
+		if err := q.AnonymizeGasRefills(ctx, AnonymizeGasRefillsParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing gas refills: %w", err)
+		}
+		if err := q.AnonymizeGasTransfers(ctx, AnonymizeGasTransfersParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing gas transfers: %w", err)
+		}
+		if err := q.AnonymizeApprovals(ctx, AnonymizeApprovalsParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing approvals: %w", err)
+		}
+		if err := q.AnonymizeBroadcastDeliveries(ctx, AnonymizeBroadcastDeliveriesParams{TelegramID: telegramID, Tombstone: tombstone}); err != nil {
+			return fmt.Errorf("anonymizing broadcast deliveries: %w", err)
+		}
+		if err := q.DeleteWhitelistOverride(ctx, telegramID); err != nil {
+			return fmt.Errorf("deleting whitelist override: %w", err)
+		}
+		if err := q.DeleteAnnouncementOptOut(ctx, telegramID); err != nil {
+			return fmt.Errorf("deleting announcement opt-out: %w", err)
+		}
+		return nil
+	})
+	return user, err
+}
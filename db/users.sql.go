@@ -9,10 +9,29 @@ import (
 	"context"
 )
 
+const anonymizeUser = `-- name: AnonymizeUser :one
+UPDATE users SET telegram_id = -id, username = '', deleted_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, telegram_id, username, created_at, deleted_at
+`
+
+func (q *Queries) AnonymizeUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, anonymizeUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TelegramID,
+		&i.Username,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (telegram_id, username)
 VALUES (?, ?)
-RETURNING id, telegram_id, username, created_at
+RETURNING id, telegram_id, username, created_at, deleted_at
 `
 
 type CreateUserParams struct {
@@ -28,12 +47,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.TelegramID,
 		&i.Username,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByTelegramID = `-- name: GetUserByTelegramID :one
-SELECT id, telegram_id, username, created_at
+SELECT id, telegram_id, username, created_at, deleted_at
 FROM users
 WHERE telegram_id = ?
 `
@@ -46,6 +66,7 @@ func (q *Queries) GetUserByTelegramID(ctx context.Context, telegramID int64) (Us
 		&i.TelegramID,
 		&i.Username,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,207 @@
+// Package delivery checks on the destination chain that a completed
+// topup's funds arrived, by reading the provider's destination transaction:
+// over RPC for EVM chains the bot has endpoints for, and through Esplora
+// APIs (mempool.space and the like) for UTXO chains.
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// ErrUnsupported is returned for destination chains that can't be checked.
+var ErrUnsupported = errors.New("destination chain can't be checked")
+
+var (
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	// decimals()
+	decimalsSelector = []byte{0x31, 0x3c, 0xe5, 0x67}
+)
+
+// Delivery is what a destination transaction paid the destination.
+type Delivery struct {
+	Amount   *big.Int // smallest unit
+	Decimals int
+	Symbol   string
+
+	// Whether the transaction is in a block; EVM receipts always are
+	Confirmed bool
+}
+
+// String formats the amount with its symbol, e.g. "0.01234 BTC".
+func (d Delivery) String() string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Decimals)), nil)
+	whole := new(big.Int).Div(d.Amount, unit)
+	frac := fmt.Sprintf("%0*s", d.Decimals, new(big.Int).Mod(d.Amount, unit).String())
+	frac = strings.TrimRight(frac[:min(d.Decimals, 8)], "0")
+	if frac == "" {
+		return fmt.Sprintf("%s %s", whole, d.Symbol)
+	}
+	return fmt.Sprintf("%s.%s %s", whole, frac, d.Symbol)
+}
+
+// Checker reads destination transactions. A nil Checker checks nothing, so
+// callers don't need to check whether delivery_check is configured.
+type Checker struct {
+	rpcClients map[string]*ethclient.Client
+	utxoAPIs   map[string]string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// New returns a checker, or nil if delivery_check isn't configured.
+func New(cfg *config.DeliveryCheckConfig, rpcClients map[string]*ethclient.Client) *Checker {
+	if cfg == nil {
+		return nil
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	return &Checker{
+		rpcClients: rpcClients,
+		utxoAPIs:   cfg.UTXOAPIs,
+		httpClient: &http.Client{Timeout: timeout},
+		timeout:    timeout,
+	}
+}
+
+// Verify returns what destTx paid destination in toAsset, in Thorchain
+// asset notation (e.g. "BTC.BTC", "BASE.USDC-0x8335..."). A zero amount
+// means the transaction paid the destination nothing. It returns
+// ErrUnsupported for chains without an RPC endpoint or UTXO API.
+func (c *Checker) Verify(ctx context.Context, toAsset, destination, destTx string) (Delivery, error) {
+	if c == nil || destTx == "" {
+		return Delivery{}, ErrUnsupported
+	}
+	asset, err := swaps.ParseAsset(toAsset)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if api, ok := c.utxoAPIs[asset.Chain]; ok {
+		return c.verifyUTXO(ctx, api, asset, destination, destTx)
+	}
+	chain, ok := swaps.AssetChain(asset.Chain)
+	if !ok {
+		return Delivery{}, ErrUnsupported
+	}
+	rpc, ok := swaps.EnabledClients(c.rpcClients)[chain]
+	if !ok || !common.IsHexAddress(destination) {
+		return Delivery{}, ErrUnsupported
+	}
+	return verifyEVM(ctx, rpc, asset, common.HexToAddress(destination), destTx)
+}
+
+// verifyEVM sums the token Transfer logs to the destination in the
+// transaction's receipt, or for the native coin, the transaction's value if
+// it pays the destination directly and otherwise the destination's balance
+// change over the block, since contracts pay native coins out in internal
+// calls that leave no logs.
+func verifyEVM(ctx context.Context, rpc *ethclient.Client, asset swaps.Asset, dest common.Address, destTx string) (Delivery, error) {
+	hash := common.HexToHash(strings.TrimPrefix(strings.ToLower(destTx), "0x"))
+	receipt, err := rpc.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("getting receipt of %s: %w", hash.Hex(), err)
+	}
+	d := Delivery{Amount: new(big.Int), Symbol: asset.Symbol, Confirmed: true}
+
+	if asset.ContractAddress == "" {
+		d.Decimals = 18
+		tx, _, err := rpc.TransactionByHash(ctx, hash)
+		if err != nil {
+			return Delivery{}, fmt.Errorf("getting %s: %w", hash.Hex(), err)
+		}
+		if tx.To() != nil && *tx.To() == dest {
+			d.Amount.Set(tx.Value())
+			return d, nil
+		}
+		after, err := rpc.BalanceAt(ctx, dest, receipt.BlockNumber)
+		if err != nil {
+			return Delivery{}, fmt.Errorf("getting balance at block %s: %w", receipt.BlockNumber, err)
+		}
+		before, err := rpc.BalanceAt(ctx, dest, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+		if err != nil {
+			return Delivery{}, fmt.Errorf("getting balance before block %s: %w", receipt.BlockNumber, err)
+		}
+		if diff := new(big.Int).Sub(after, before); diff.Sign() > 0 {
+			d.Amount = diff
+		}
+		return d, nil
+	}
+
+	token := common.HexToAddress(asset.ContractAddress)
+	for _, l := range receipt.Logs {
+		if l.Address != token || len(l.Topics) != 3 || l.Topics[0] != transferTopic {
+			continue
+		}
+		if common.BytesToAddress(l.Topics[2].Bytes()) == dest {
+			d.Amount.Add(d.Amount, new(big.Int).SetBytes(l.Data))
+		}
+	}
+	out, err := rpc.CallContract(ctx, ethereum.CallMsg{To: &token, Data: decimalsSelector}, nil)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("reading decimals of %s: %w", token.Hex(), err)
+	}
+	if len(out) < 32 {
+		return Delivery{}, fmt.Errorf("reading decimals of %s: empty result", token.Hex())
+	}
+	d.Decimals = int(new(big.Int).SetBytes(out).Int64())
+	return d, nil
+}
+
+// esploraTx is the part of an Esplora /tx response that matters here.
+type esploraTx struct {
+	Status struct {
+		Confirmed bool `json:"confirmed"`
+	} `json:"status"`
+	Vout []struct {
+		Address string `json:"scriptpubkey_address"`
+		Value   int64  `json:"value"`
+	} `json:"vout"`
+}
+
+// verifyUTXO sums the transaction's outputs to the destination.
+func (c *Checker) verifyUTXO(ctx context.Context, api string, asset swaps.Asset, destination, destTx string) (Delivery, error) {
+	url := strings.TrimSuffix(api, "/") + "/tx/" + strings.ToLower(strings.TrimPrefix(destTx, "0x"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Delivery{}, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("fetching %s: %w", destTx, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Delivery{}, fmt.Errorf("fetching %s: HTTP %d", destTx, resp.StatusCode)
+	}
+	var tx esploraTx
+	if err := json.NewDecoder(resp.Body).Decode(&tx); err != nil {
+		return Delivery{}, fmt.Errorf("decoding %s: %w", destTx, err)
+	}
+
+	d := Delivery{Amount: new(big.Int), Decimals: 8, Symbol: asset.Symbol, Confirmed: tx.Status.Confirmed}
+	for _, out := range tx.Vout {
+		// Bech32 addresses can be written in upper case
+		if strings.EqualFold(out.Address, destination) {
+			d.Amount.Add(d.Amount, big.NewInt(out.Value))
+		}
+	}
+	return d, nil
+}
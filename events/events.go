@@ -0,0 +1,181 @@
+// Package events is an in-process bus for live updates: the bot, the topup
+// service and the tracker publish to it, and the web server streams what
+// they publish to open dashboards.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/thorchain"
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind by
+// before it starts missing them.
+const subscriberBuffer = 32
+
+// Event is one update, as streamed to dashboards.
+type Event struct {
+	Type      string      `json:"type"` // "topup", "quote" or "balances"
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+
+	// Admin events carry wallet details and only go to admin sessions
+	Admin bool `json:"-"`
+}
+
+// Topup is the data of a "topup" event: a topup was submitted or its
+// status changed.
+type Topup struct {
+	ShortID   string `json:"short_id"`
+	Status    string `json:"status"`
+	RawStatus string `json:"raw_status,omitempty"`
+	Provider  string `json:"provider"`
+	FromChain string `json:"from_chain"`
+}
+
+// Quote is the data of a "quote" event: a quote was fetched and stored.
+type Quote struct {
+	Provider  string  `json:"provider"`
+	FromChain string  `json:"from_chain"`
+	ToAsset   string  `json:"to_asset"`
+	AmountUSD float64 `json:"amount_usd"`
+}
+
+// Balances is the data of a "balances" event: a wallet's balances were
+// fetched. It has the shape of the admin balances endpoint's entries.
+type Balances struct {
+	Address string                  `json:"address"`
+	Index   uint32                  `json:"index"`
+	Owner   string                  `json:"owner,omitempty"`
+	Chains  map[string]ChainBalance `json:"chains"`
+	USD     float64                 `json:"usd"` // total across chains
+}
+
+// Add records a wallet's fetched balance on one chain and adds its value to
+// the wallet's total.
+func (b *Balances) Add(bal balances.AddressBalance) {
+	cb := ChainBalanceOf(bal)
+	b.Chains[bal.Chain] = cb
+	b.USD += cb.USD
+}
+
+type ChainBalance struct {
+	Native    string          `json:"native"`
+	NativeUSD *float64        `json:"native_usd"` // null when the native coin couldn't be priced
+	Stables   []StableBalance `json:"stables"`
+	Watched   []WatchedToken  `json:"watched,omitempty"` // token_watchlist tokens
+	USD       float64         `json:"usd"`               // leaves out an unpriced native coin and watched tokens
+}
+
+type StableBalance struct {
+	Symbol   string  `json:"symbol"`
+	Balance  string  `json:"balance"`
+	Decimals int     `json:"decimals"`
+	USD      float64 `json:"usd"`
+}
+
+type WatchedToken struct {
+	Symbol   string   `json:"symbol"`
+	Balance  string   `json:"balance"`
+	Decimals int      `json:"decimals"`
+	USD      *float64 `json:"usd"` // null for tokens that aren't stable
+}
+
+// ChainBalanceOf labels a fetched balance with the funding tokens its token
+// balances are for, carrying over the USD values set by balances.Value.
+func ChainBalanceOf(bal balances.AddressBalance) ChainBalance {
+	cb := ChainBalance{Native: bal.NativeBalance, USD: bal.USD()}
+	if bal.NativePriced {
+		cb.NativeUSD = &bal.NativeUSD
+	}
+	for i, token := range thorchain.FundingTokens.ERC20s(bal.Chain) {
+		stable := StableBalance{
+			Symbol:   token.Symbol,
+			Balance:  bal.TokenBalances[i],
+			Decimals: token.Decimals,
+		}
+		if i < len(bal.TokenUSD) {
+			stable.USD = bal.TokenUSD[i]
+		}
+		cb.Stables = append(cb.Stables, stable)
+	}
+	for _, w := range bal.Watched {
+		token := WatchedToken{Symbol: w.Symbol, Balance: w.Balance, Decimals: w.Decimals}
+		if w.Priced {
+			token.USD = &w.USD
+		}
+		cb.Watched = append(cb.Watched, token)
+	}
+	return cb
+}
+
+// Bus fans events out to subscribers. A nil Bus drops every event, so
+// publishers don't need to check whether anything is listening.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan Event]struct{})}
+}
+
+// TopupStatus publishes a "topup" event.
+func (b *Bus) TopupStatus(t Topup) {
+	b.Publish(Event{Type: "topup", Data: t})
+}
+
+// NewQuote publishes a "quote" event.
+func (b *Bus) NewQuote(q Quote) {
+	b.Publish(Event{Type: "quote", Data: q})
+}
+
+// BalanceRefresh publishes a "balances" event for admin sessions.
+func (b *Bus) BalanceRefresh(bal Balances) {
+	b.Publish(Event{Type: "balances", Data: bal, Admin: true})
+}
+
+// Publish sends e to every subscriber without blocking; a subscriber whose
+// buffer is full misses it.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Timestamp == 0 {
+		e.Timestamp = time.Now().Unix()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of events published from now on, and a
+// function that unsubscribes and closes it.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	if b == nil {
+		return ch, func() {}
+	}
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
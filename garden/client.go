@@ -0,0 +1,185 @@
+package garden
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const baseURL = "https://api.garden.finance"
+
+type Client struct {
+	appID      string
+	httpClient *http.Client
+}
+
+func NewClient(appID string, httpClient *http.Client) *Client {
+	return &Client{
+		appID:      appID,
+		httpClient: httpClient,
+	}
+}
+
+// AssetAmount is an asset identifier ("chain:token") with an amount in smallest units.
+type AssetAmount struct {
+	Asset  string `json:"asset"`
+	Amount string `json:"amount"`
+}
+
+// QuoteResult is one solver quote from GET /v2/quote.
+type QuoteResult struct {
+	Source        AssetAmount `json:"source"`
+	Destination   AssetAmount `json:"destination"`
+	SolverID      string      `json:"solver_id"`
+	EstimatedTime int64       `json:"estimated_time"`
+}
+
+// OrderParty is one side of an order request.
+type OrderParty struct {
+	Asset  string `json:"asset"`
+	Owner  string `json:"owner"`
+	Amount string `json:"amount"`
+}
+
+// CreateOrderRequest is the body for POST /v2/orders.
+type CreateOrderRequest struct {
+	Source      OrderParty `json:"source"`
+	Destination OrderParty `json:"destination"`
+	SecretHash  string     `json:"secret_hash"`
+	SolverID    string     `json:"solver_id,omitempty"`
+}
+
+// EVMTx is an unsigned transaction returned for EVM source chains.
+type EVMTx struct {
+	To       string `json:"to"`
+	Data     string `json:"data"`
+	Value    string `json:"value"`
+	GasLimit string `json:"gas_limit"`
+	ChainID  int64  `json:"chain_id"`
+}
+
+// CreateOrderResult is the result of POST /v2/orders.
+type CreateOrderResult struct {
+	OrderID             string `json:"order_id"`
+	ApprovalTransaction *EVMTx `json:"approval_transaction"`
+	InitiateTransaction *EVMTx `json:"initiate_transaction"`
+}
+
+// Swap is one HTLC leg of an order.
+type Swap struct {
+	InitiateTxHash string `json:"initiate_tx_hash"`
+	RedeemTxHash   string `json:"redeem_tx_hash"`
+	RefundTxHash   string `json:"refund_tx_hash"`
+}
+
+// Order is the result of GET /v2/orders/{id}.
+type Order struct {
+	OrderID         string `json:"order_id"`
+	SourceSwap      Swap   `json:"source_swap"`
+	DestinationSwap Swap   `json:"destination_swap"`
+	Status          string `json:"status"`
+}
+
+// envelope is Garden's standard response wrapper.
+type envelope struct {
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+// GetQuote returns solver quotes for swapping amount (smallest units) of from into to.
+func (c *Client) GetQuote(ctx context.Context, from, to, amount string) ([]QuoteResult, error) {
+	params := url.Values{}
+	params.Set("from", from)
+	params.Set("to", to)
+	params.Set("from_amount", amount)
+
+	var result []QuoteResult
+	if err := c.do(ctx, http.MethodGet, baseURL+"/v2/quote?"+params.Encode(), nil, &result); err != nil {
+		return nil, fmt.Errorf("garden quote: %w", err)
+	}
+	return result, nil
+}
+
+// CreateOrder creates an HTLC order and returns the source-chain transactions to send.
+func (c *Client) CreateOrder(ctx context.Context, req CreateOrderRequest) (*CreateOrderResult, error) {
+	var result CreateOrderResult
+	if err := c.do(ctx, http.MethodPost, baseURL+"/v2/orders", req, &result); err != nil {
+		return nil, fmt.Errorf("garden create order: %w", err)
+	}
+	return &result, nil
+}
+
+// GetOrder retrieves an order's HTLC state.
+func (c *Client) GetOrder(ctx context.Context, orderID string) (*Order, error) {
+	var result Order
+	if err := c.do(ctx, http.MethodGet, baseURL+"/v2/orders/"+url.PathEscape(orderID), nil, &result); err != nil {
+		return nil, fmt.Errorf("garden get order: %w", err)
+	}
+	return &result, nil
+}
+
+// Redeem reveals the secret so the destination HTLC is redeemed to the recipient.
+// Garden's relayer submits the redeem transaction on our behalf.
+func (c *Client) Redeem(ctx context.Context, orderID, secret string) error {
+	u := fmt.Sprintf("%s/v2/orders/%s?action=redeem", baseURL, url.PathEscape(orderID))
+	payload := map[string]string{"secret": secret}
+
+	var result json.RawMessage
+	if err := c.do(ctx, http.MethodPatch, u, payload, &result); err != nil {
+		return fmt.Errorf("garden redeem: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, u string, payload interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		jsonBody, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = strings.NewReader(string(jsonBody))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("garden-app-id", c.appID)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if env.Status != "Ok" {
+		return fmt.Errorf("%s", env.Error)
+	}
+
+	if err := json.Unmarshal(env.Result, out); err != nil {
+		return fmt.Errorf("parsing result: %w", err)
+	}
+	return nil
+}
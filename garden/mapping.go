@@ -0,0 +1,38 @@
+package garden
+
+import (
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// assetToGarden maps our Asset notation (CHAIN.SYMBOL) to Garden asset identifiers.
+// Garden is focused on BTC, so only native Bitcoin is supported as a destination.
+var assetToGarden = map[string]string{
+	"BTC.BTC": "bitcoin:btc",
+}
+
+// sourceAssets maps our RPC chain name to the Garden USDC asset on that chain.
+var sourceAssets = map[string]string{
+	"avalanche": "avalanche:usdc",
+	"base":      "base:usdc",
+}
+
+// AssetToGarden looks up the Garden asset for a target asset.
+func AssetToGarden(asset swaps.Asset) (string, bool) {
+	a, ok := assetToGarden[asset.Chain+"."+asset.Symbol]
+	return a, ok
+}
+
+// SourceAsset returns the Garden USDC asset for a source RPC chain.
+func SourceAsset(chain string) (string, bool) {
+	a, ok := sourceAssets[chain]
+	return a, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that Garden can source USDC from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceAssets))
+	for k := range sourceAssets {
+		chains = append(chains, k)
+	}
+	return chains
+}
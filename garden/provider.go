@@ -0,0 +1,397 @@
+package garden
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+// btcDecimals is the number of decimals Garden uses for BTC amounts (satoshis).
+const btcDecimals = 8
+
+// defaultInitiateGas is used when Garden does not supply a gas limit for a transaction.
+const defaultInitiateGas = 300000
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(appID string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(appID, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "garden"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, ok := AssetToGarden(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	to, ok := AssetToGarden(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("garden: unsupported target asset %s", toAsset)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		from, ok := SourceAsset(chain)
+		if !ok {
+			continue
+		}
+
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Garden only lists USDC as a source asset
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, swaps.OnlySymbol("USDC"))
+		if err != nil {
+			log.Printf("garden: skipping %s: %v", chain, err)
+			continue
+		}
+
+		results, err := p.client.GetQuote(ctx, from, to, inputAmount.String())
+		if err != nil {
+			log.Printf("garden quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+
+		best, ok := bestQuote(results)
+		if !ok {
+			log.Printf("garden: no solver quotes for %s via %s", toAsset, chain)
+			continue
+		}
+
+		outSats, _ := new(big.Int).SetString(best.Destination.Amount, 10)
+		expectedOut := formatUnits(outSats, btcDecimals)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "garden",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    expectedOut,
+			ExpectedOutputRaw: parseToBigInt(expectedOut),
+			ExtraData: map[string]interface{}{
+				"garden_from":        from,
+				"garden_to":          to,
+				"garden_out_amount":  best.Destination.Amount,
+				"garden_solver_id":   best.SolverID,
+				"garden_destination": destination,
+				"total_swap_s":       best.EstimatedTime,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("garden: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	from, _ := quote.ExtraData["garden_from"].(string)
+	to, _ := quote.ExtraData["garden_to"].(string)
+	outAmount, _ := quote.ExtraData["garden_out_amount"].(string)
+	solverID, _ := quote.ExtraData["garden_solver_id"].(string)
+	destination, _ := quote.ExtraData["garden_destination"].(string)
+	if from == "" || to == "" || outAmount == "" || destination == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("garden: missing order details in quote ExtraData")
+	}
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	// The HTLC secret is ours: the solver can only claim our USDC once we reveal it
+	// to redeem the BTC, which we do only after the BTC side has been locked.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("generating HTLC secret: %w", err)
+	}
+	secretHash := sha256.Sum256(secret)
+
+	order, err := p.client.CreateOrder(ctx, CreateOrderRequest{
+		Source: OrderParty{
+			Asset:  from,
+			Owner:  fromAddr.Hex(),
+			Amount: quote.InputAmount.String(),
+		},
+		Destination: OrderParty{
+			Asset:  to,
+			Owner:  destination,
+			Amount: outAmount,
+		},
+		SecretHash: hex.EncodeToString(secretHash[:]),
+		SolverID:   solverID,
+	})
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+	if order.InitiateTransaction == nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("garden: order %s has no initiate transaction", order.OrderID)
+	}
+
+	log.Printf("Garden order created: id=%s", order.OrderID)
+
+	// Step 1: Approve the HTLC contract if Garden reports it is needed
+	if order.ApprovalTransaction != nil {
+		if _, err := sendTx(ctx, rpc, chainID, signer, fromAddr, *order.ApprovalTransaction, true); err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("garden approval tx: %w", err)
+		}
+	}
+
+	// Step 2: Lock USDC in the source HTLC
+	txHash, err := sendTx(ctx, rpc, chainID, signer, fromAddr, *order.InitiateTransaction, false)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("garden initiate tx: %w", err)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: order.OrderID + "|" + hex.EncodeToString(secret),
+	}, nil
+}
+
+// CheckStatus polls the order and, once the solver has locked BTC in the
+// destination HTLC, reveals the secret so Garden's relayer redeems it to
+// the recipient. externalID is "orderID|secret".
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	orderID, secret, ok := strings.Cut(externalID, "|")
+	if !ok || orderID == "" || secret == "" {
+		return "", fmt.Errorf("garden: invalid external ID %q", externalID)
+	}
+
+	order, err := p.client.GetOrder(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, order.Status)
+
+	switch {
+	case order.DestinationSwap.RedeemTxHash != "":
+		return "completed", nil
+	case order.SourceSwap.RefundTxHash != "":
+		return "refunded", nil
+	case order.DestinationSwap.InitiateTxHash != "":
+		// Redeeming is idempotent, so retry on every poll until the redeem lands
+		if err := p.client.Redeem(ctx, orderID, secret); err != nil {
+			log.Printf("garden: redeem for order %s failed: %v", orderID, err)
+		}
+		return "pending", nil
+	default:
+		return "pending", nil
+	}
+}
+
+// RefundTx returns the source HTLC refund transaction of the order.
+func (p *Provider) RefundTx(ctx context.Context, txHash string, externalID string) (string, error) {
+	orderID, _, _ := strings.Cut(externalID, "|")
+	order, err := p.client.GetOrder(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	return order.SourceSwap.RefundTxHash, nil
+}
+
+// Result returns the destination redeem transaction. Garden doesn't report
+// the redeemed amount; HTLC swaps pay out exactly what the order locked.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	orderID, _, _ := strings.Cut(externalID, "|")
+	order, err := p.client.GetOrder(ctx, orderID)
+	if err != nil {
+		return swaps.SwapResult{}, err
+	}
+	return swaps.SwapResult{DestTxHash: order.DestinationSwap.RedeemTxHash}, nil
+}
+
+// bestQuote picks the solver quote with the largest destination amount.
+func bestQuote(results []QuoteResult) (QuoteResult, bool) {
+	var best QuoteResult
+	var bestAmount *big.Int
+	for _, r := range results {
+		amt, ok := new(big.Int).SetString(r.Destination.Amount, 10)
+		if !ok {
+			continue
+		}
+		if bestAmount == nil || amt.Cmp(bestAmount) > 0 {
+			best = r
+			bestAmount = amt
+		}
+	}
+	return best, bestAmount != nil
+}
+
+// sendTx signs and sends a Garden-supplied transaction. When wait is set it
+// blocks until the transaction is mined (used for approvals the initiate depends on).
+func sendTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, txData EVMTx, wait bool) (string, error) {
+	data, err := hexutil.Decode(txData.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	value, err := parseBig(txData.Value)
+	if err != nil {
+		return "", fmt.Errorf("invalid value: %w", err)
+	}
+
+	gasLimit := uint64(defaultInitiateGas)
+	if txData.GasLimit != "" {
+		gl, err := parseBig(txData.GasLimit)
+		if err != nil {
+			return "", fmt.Errorf("invalid gas limit: %w", err)
+		}
+		gasLimit = gl.Uint64()
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, common.HexToAddress(txData.To), value, gasLimit, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending tx: %w", err)
+	}
+
+	log.Printf("Garden tx sent: %s", signedTx.Hash().Hex())
+
+	if !wait {
+		return signedTx.Hash().Hex(), nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return "", fmt.Errorf("waiting for tx: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("tx %s failed", signedTx.Hash().Hex())
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// parseBig parses a decimal or 0x-prefixed hex integer; empty means zero.
+func parseBig(s string) (*big.Int, error) {
+	if s == "" {
+		return new(big.Int), nil
+	}
+	if strings.HasPrefix(s, "0x") {
+		return hexutil.DecodeBig(s)
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an integer", s)
+	}
+	return v, nil
+}
+
+// formatUnits renders an integer amount with the given number of decimals.
+func formatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+	if decimals <= 0 {
+		return amount.String()
+	}
+	s := amount.String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	return s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
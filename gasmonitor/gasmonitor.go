@@ -0,0 +1,357 @@
+// Package gasmonitor refills low native gas on every wallet in use, on a
+// timer, instead of waiting for someone to run /balance.
+package gasmonitor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/RaghavSood/fundbot/approvals"
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/gastreasury"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// Monitor periodically reads the native and USDC balances of every wallet
+// in use and places a CoW gas refill where the native balance is below the
+// chain's gas_refills threshold, with the same limits as /balance. Refills
+// above the approval threshold are queued for the admin instead, and
+// wallets without enough USDC are topped up from the gas treasury, if there
+// is one.
+type Monitor struct {
+	cfg        *config.Config
+	store      *db.Store
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+	cowClient  *cowswap.Client
+	treasury   *gastreasury.Treasury
+	approvals  *approvals.Service
+	hooks      *webhooks.Client
+	botAPI     *tgbotapi.BotAPI
+}
+
+func New(cfg *config.Config, store *db.Store, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client, cowClient *cowswap.Client, treasury *gastreasury.Treasury, approvals *approvals.Service, hooks *webhooks.Client, botAPI *tgbotapi.BotAPI) *Monitor {
+	return &Monitor{
+		cfg:        cfg,
+		store:      store,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+		cowClient:  cowClient,
+		treasury:   treasury,
+		approvals:  approvals,
+		hooks:      hooks,
+		botAPI:     botAPI,
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(m.cfg.GasMonitor.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	m.check(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Gas monitor stopped")
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// owned is a wallet in use and who hears about its refills: the Telegram
+// user or group it is assigned to, or the admin in single mode.
+type owned struct {
+	index  uint32
+	userID int64
+	chatID int64
+}
+
+// wallets returns the wallets in use: the shared wallet in single mode, or
+// one per address assignment in multi mode. Wallets of anonymized users
+// are left out.
+func (m *Monitor) wallets(ctx context.Context) ([]owned, error) {
+	if m.cfg.Mode == config.ModeSingle {
+		return []owned{{index: 0, userID: m.cfg.AdminUserID, chatID: m.cfg.AdminUserID}}, nil
+	}
+
+	users, err := m.store.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userByID := make(map[int64]db.User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+	chats, err := m.store.ListChats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatByID := make(map[int64]db.Chat, len(chats))
+	for _, c := range chats {
+		chatByID[c.ID] = c
+	}
+
+	assignments, err := m.store.ListAddressAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var wallets []owned
+	for _, a := range assignments {
+		w := owned{index: uint32(a.ID)}
+		switch a.AssignedToType {
+		case "user":
+			u, ok := userByID[a.AssignedToID]
+			if !ok || u.DeletedAt.Valid {
+				continue
+			}
+			w.userID, w.chatID = u.TelegramID, u.TelegramID
+		case "chat":
+			c, ok := chatByID[a.AssignedToID]
+			if !ok {
+				continue
+			}
+			w.chatID = c.ChatID
+		}
+		wallets = append(wallets, w)
+	}
+	return wallets, nil
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	wallets, err := m.wallets(ctx)
+	if err != nil {
+		log.Printf("Gas monitor: error listing wallets: %v", err)
+		return
+	}
+
+	var addresses []common.Address
+	byAddr := make(map[string]owned)
+	for _, w := range wallets {
+		addr, err := m.keyring.Address(w.index)
+		if err != nil {
+			log.Printf("Gas monitor: error deriving wallet %d: %v", w.index, err)
+			continue
+		}
+		addresses = append(addresses, addr)
+		byAddr[addr.Hex()] = w
+	}
+
+	// Refills sell USDC through CoW, so only those chains are read
+	usdcTokens := make(map[string]swaps.FundingToken)
+	contracts := make(map[string][]common.Address)
+	for chain := range swaps.EnabledClients(m.rpcClients) {
+		if _, ok := cowswap.SupportedChains[chain]; !ok {
+			continue
+		}
+		if _, ok := m.cfg.GasRefill(chain); !ok {
+			continue
+		}
+		usdc, ok := thorchain.FundingTokens.Lookup(chain, "USDC")
+		if !ok || usdc.Native {
+			continue
+		}
+		usdcTokens[chain] = usdc
+		contracts[chain] = []common.Address{usdc.Address}
+	}
+	if len(usdcTokens) == 0 || len(addresses) == 0 {
+		return
+	}
+
+	bals, err := balances.FetchBalances(ctx, swaps.EnabledClients(m.rpcClients), addresses, contracts, nil)
+	if err != nil {
+		log.Printf("Gas monitor: error fetching balances: %v", err)
+		return
+	}
+
+	for _, bal := range bals {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		usdc, ok := usdcTokens[bal.Chain]
+		if !ok || len(bal.TokenBalances) == 0 {
+			continue
+		}
+		refill, ok := m.cfg.GasRefill(bal.Chain)
+		if !ok {
+			continue
+		}
+		threshold := refill.MinNative()
+		native, ok := new(big.Int).SetString(bal.NativeBalance, 10)
+		if !ok || threshold == nil || native.Cmp(threshold) >= 0 {
+			continue
+		}
+		usdcBal, ok := new(big.Int).SetString(bal.TokenBalances[0], 10)
+		if !ok {
+			continue
+		}
+
+		// The monitor's own cooldown applies on top of the chain's minimum
+		// interval. Errors skip the wallet so a broken database can't cause
+		// repeated orders.
+		wait := time.Duration(max(m.cfg.GasMonitor.CooldownMinutes, refill.MinIntervalMinutes)) * time.Minute
+		if err := m.store.CheckGasRefillLimits(ctx, bal.Chain, bal.Address, wait, refill.MaxPerDay); err != nil {
+			if !errors.Is(err, db.ErrGasRefillLimit) {
+				log.Printf("Gas monitor: error checking limits of %s on %s: %v", bal.Address, bal.Chain, err)
+			}
+			continue
+		}
+
+		m.refill(ctx, byAddr[bal.Address], bal.Chain, refill, usdc, native, usdcBal)
+	}
+}
+
+func (m *Monitor) refill(ctx context.Context, w owned, chain string, refill config.GasRefillConfig, usdc swaps.FundingToken, native, usdcBal *big.Int) {
+	signer, err := m.keyring.Signer(w.index)
+	if err != nil {
+		log.Printf("Gas monitor: error loading signer %d: %v", w.index, err)
+		return
+	}
+	if _, ok := signer.(swaps.CallSender); ok {
+		return // smart accounts pay gas through the paymaster
+	}
+	addr := signer.Address()
+	symbol := cowswap.SupportedChains[chain].NativeSymbol
+	amount := usdc.Amount(refill.RefillUSD)
+
+	if m.approvals.Required(refill.RefillUSD, w.userID) && cowswap.CanRefill(native, usdcBal, refill.TargetNative(), amount) {
+		a, queued, err := m.approvals.RequestGasRefill(ctx, chain, w.index, w.userID, w.chatID, refill.RefillUSD)
+		if err != nil {
+			log.Printf("Gas monitor: error queueing approval for %s on %s: %v", addr.Hex(), chain, err)
+			return
+		}
+		if queued {
+			m.send(w.chatID, fmt.Sprintf("Low %s balance on %s. The $%.2f refill is above the approval threshold and is waiting for the admin (approval #%d).",
+				symbol, chain, refill.RefillUSD, a.ID))
+		}
+		return
+	}
+
+	// Stamped before placing, so a failing refill also waits out the
+	// cooldown instead of being retried every check
+	if err := m.store.SetGasRefillCooldown(ctx, db.SetGasRefillCooldownParams{Chain: chain, WalletAddress: addr.Hex()}); err != nil {
+		log.Printf("Gas monitor: error recording cooldown of %s on %s: %v", addr.Hex(), chain, err)
+		return
+	}
+
+	signCtx := wallet.WithConfirmNotifier(ctx, func(what string) {
+		m.send(w.chatID, fmt.Sprintf("Confirm the %s on the hardware wallet to refill %s on %s.", what, symbol, chain))
+	})
+	guards := cowswap.Guards{SlippageBps: refill.SlippageBps, MaxFeeBps: refill.MaxFeeBps, MaxDeviationBps: refill.MaxDeviationBps}
+	result, err := m.cowClient.RefillGasIfNeeded(signCtx, chain, signer, native, usdcBal, refill.MinNative(), refill.TargetNative(), amount, guards)
+	if err != nil {
+		log.Printf("Gas monitor: refill of %s on %s failed: %v", addr.Hex(), chain, err)
+		return
+	}
+	if result == nil {
+		m.fund(signCtx, w, chain, addr, symbol)
+		return
+	}
+
+	params := db.InsertGasRefillParams{
+		Chain:         result.Chain,
+		OrderUid:      result.OrderUID,
+		WalletAddress: addr.Hex(),
+		SellAmount:    result.SellAmount,
+		BuyAmount:     result.BuyAmount,
+		Status:        "open",
+		UserID:        w.userID,
+		ChatID:        w.chatID,
+		WalletIndex:   int64(w.index),
+		Attempt:       1,
+		OrderType:     "market",
+		Kind:          result.Kind,
+		ValidTo:       sql.NullTime{Time: result.ValidTo, Valid: true},
+	}
+	id, err := m.store.InsertGasRefill(ctx, params)
+	if err != nil {
+		log.Printf("Gas monitor: error recording gas refill of %s on %s: %v", addr.Hex(), chain, err)
+	} else {
+		m.hooks.GasRefillStatus(webhooks.GasRefill{
+			ID:            id,
+			Status:        "open",
+			Chain:         params.Chain,
+			OrderUID:      params.OrderUid,
+			WalletAddress: params.WalletAddress,
+			SellAmount:    params.SellAmount,
+			BuyAmount:     params.BuyAmount,
+			Attempt:       params.Attempt,
+			OrderType:     params.OrderType,
+			Kind:          params.Kind,
+			UserID:        params.UserID,
+			ChatID:        params.ChatID,
+		})
+	}
+	log.Printf("Gas monitor: refilling %s on %s with order %s", addr.Hex(), chain, result.OrderUID)
+
+	sold, _ := new(big.Int).SetString(result.SellAmount, 10)
+	if result.Kind == "buy" {
+		m.send(w.chatID, fmt.Sprintf("Low %s balance on %s. Buying %s %s for at most %s USDC via CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
+			symbol, chain, formatNative(result.BuyAmount), symbol, usdc.Format(sold), result.OrderUID))
+		return
+	}
+	m.send(w.chatID, fmt.Sprintf("Low %s balance on %s. Swapping %s USDC → %s via CoWSwap (3m expiry).\n[View Order](https://explorer.cow.fi/orders/%s)",
+		symbol, chain, usdc.Format(sold), symbol, result.OrderUID))
+}
+
+// fund tops the wallet up from the gas treasury when it has too little USDC
+// for a refill.
+func (m *Monitor) fund(ctx context.Context, w owned, chain string, addr common.Address, symbol string) {
+	if m.treasury == nil {
+		return
+	}
+	transfer, err := m.treasury.Fund(ctx, chain, w.index, addr, w.userID, w.chatID)
+	if err != nil {
+		log.Printf("Gas monitor: gas treasury top-up of %s on %s failed: %v", addr.Hex(), chain, err)
+		return
+	}
+	if transfer == nil {
+		return
+	}
+	m.send(w.chatID, fmt.Sprintf("Low %s balance on %s and not enough USDC for a refill. Sent %s %s from the gas treasury.\n[View on Explorer](%s)",
+		symbol, chain, formatNative(transfer.Amount.String()), symbol, m.cfg.ExplorerTxURL(chain, transfer.TxHash)))
+}
+
+// formatNative renders wei with six decimals.
+func formatNative(wei string) string {
+	val, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		val = new(big.Int)
+	}
+	whole := new(big.Int).Div(val, big.NewInt(1e18))
+	frac := new(big.Int).Mod(val, big.NewInt(1e18))
+	return fmt.Sprintf("%s.%s", whole, fmt.Sprintf("%018s", frac.String())[:6])
+}
+
+// send posts a Markdown message to chatID, doing nothing for chat 0.
+func (m *Monitor) send(chatID int64, text string) {
+	if chatID == 0 {
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	if _, err := m.botAPI.Send(msg); err != nil {
+		log.Printf("Gas monitor: error notifying chat %d: %v", chatID, err)
+	}
+}
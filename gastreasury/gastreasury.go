@@ -0,0 +1,136 @@
+// Package gastreasury sends native gas from one of the bot's own wallets to
+// wallets that run low on gas without enough USDC for a CoW refill, which
+// would otherwise be left unable to move their funds.
+package gastreasury
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// Treasury sends top-ups from the gas_treasury wallet within its daily
+// limits, recording each one in gas_transfers.
+type Treasury struct {
+	cfg        *config.Config
+	store      *db.Store
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+
+	// One top-up at a time, so the limits are counted before the next one
+	// and the treasury's nonces don't collide
+	mu sync.Mutex
+}
+
+// Transfer is a top-up that was sent.
+type Transfer struct {
+	Chain  string
+	Amount *big.Int
+	TxHash string
+}
+
+func New(cfg *config.Config, store *db.Store, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client) *Treasury {
+	return &Treasury{
+		cfg:        cfg,
+		store:      store,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+	}
+}
+
+// Fund sends the chain's top-up amount to the wallet at index, owned by
+// userID and chatID. It returns nil, nil if the chain isn't topped up, the
+// wallet is the treasury itself or a limit is reached. Failed sends are
+// recorded too and count toward the limits.
+func (t *Treasury) Fund(ctx context.Context, chain string, index uint32, to common.Address, userID, chatID int64) (*Transfer, error) {
+	settings := t.cfg.GasTreasury
+	amount := settings.Amount(chain)
+	rpc, ok := swaps.EnabledClients(t.rpcClients)[chain]
+	if amount == nil || !ok {
+		return nil, nil
+	}
+
+	signer, err := t.keyring.Signer(settings.Index)
+	if err != nil {
+		return nil, fmt.Errorf("loading gas treasury signer: %w", err)
+	}
+	from := signer.Address()
+	if from == to {
+		return nil, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	since := time.Now().UTC().Add(-24 * time.Hour)
+	n, err := t.store.CountGasTransfersSince(ctx, db.CountGasTransfersSinceParams{
+		Chain:         chain,
+		WalletAddress: to.Hex(),
+		CreatedFrom:   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("counting gas transfers: %w", err)
+	}
+	if n >= int64(settings.MaxPerDay) {
+		return nil, nil
+	}
+	total, err := t.store.CountChainGasTransfersSince(ctx, db.CountChainGasTransfersSinceParams{
+		Chain:       chain,
+		CreatedFrom: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("counting gas transfers: %w", err)
+	}
+	if total >= int64(settings.MaxTotalPerDay) {
+		log.Printf("Gas treasury: daily limit of %d top-ups on %s reached", settings.MaxTotalPerDay, chain)
+		return nil, nil
+	}
+
+	txHash, sendErr := t.send(ctx, rpc, chain, signer, to, amount)
+	status := "sent"
+	if sendErr != nil {
+		status = "failed"
+	}
+	if _, err := t.store.InsertGasTransfer(ctx, db.InsertGasTransferParams{
+		Chain:           chain,
+		WalletIndex:     int64(index),
+		WalletAddress:   to.Hex(),
+		TreasuryAddress: from.Hex(),
+		Amount:          amount.String(),
+		TxHash:          txHash,
+		Status:          status,
+		UserID:          userID,
+		ChatID:          chatID,
+	}); err != nil {
+		log.Printf("Gas treasury: error recording top-up of %s on %s: %v", to.Hex(), chain, err)
+	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	log.Printf("Gas treasury: sent %s wei to %s on %s in %s", amount, to.Hex(), chain, txHash)
+	return &Transfer{Chain: chain, Amount: amount, TxHash: txHash}, nil
+}
+
+// send checks the treasury can cover amount and sends it.
+func (t *Treasury) send(ctx context.Context, rpc *ethclient.Client, chain string, signer wallet.Signer, to common.Address, amount *big.Int) (string, error) {
+	bal, err := rpc.BalanceAt(ctx, signer.Address(), nil)
+	if err != nil {
+		return "", fmt.Errorf("getting gas treasury balance: %w", err)
+	}
+	if bal.Cmp(amount) <= 0 {
+		return "", fmt.Errorf("gas treasury %s holds %s wei on %s, too little to send %s", signer.Address().Hex(), bal, chain, amount)
+	}
+	return swaps.TransferNative(ctx, rpc, chain, signer, to, amount)
+}
@@ -16,6 +16,9 @@ type Client struct {
 	apiKey     string
 	apiSecret  string
 	httpClient *http.Client
+
+	// Partner parameters sent with quotes and exchanges
+	partnerParams map[string]string
 }
 
 func NewClient(apiKey, apiSecret string, httpClient *http.Client) *Client {
@@ -30,6 +33,31 @@ func (c *Client) authHeader() string {
 	return c.apiKey + ":" + c.apiSecret
 }
 
+// quoteURL returns the /quote URL for a swap. Partner parameters are added
+// unless the request sets them itself.
+func (c *Client) quoteURL(from, to string, amount float64, anonymous, cexOnly bool) string {
+	params := url.Values{}
+	for k, v := range c.partnerParams {
+		params.Set(k, v)
+	}
+	params.Set("amount", fmt.Sprintf("%g", amount))
+	params.Set("from", from)
+	params.Set("to", to)
+	params.Set("anonymous", fmt.Sprintf("%t", anonymous))
+	params.Set("cexOnly", fmt.Sprintf("%t", cexOnly))
+	return fmt.Sprintf("%s/quote?%s", baseURL, params.Encode())
+}
+
+// addPartnerParams adds the partner parameters an exchange payload doesn't
+// already set.
+func (c *Client) addPartnerParams(payload map[string]interface{}) {
+	for k, v := range c.partnerParams {
+		if _, ok := payload[k]; !ok {
+			payload[k] = v
+		}
+	}
+}
+
 // QuoteResponse represents the response from GET /quote.
 type QuoteResponse struct {
 	AmountOut    float64 `json:"amountOut"`
@@ -155,8 +183,7 @@ func (c *Client) GetQuote(ctx context.Context, from, to string, amount float64)
 }
 
 func (c *Client) getQuote(ctx context.Context, from, to string, amount float64, cexOnly bool) (*QuoteResponse, error) {
-	u := fmt.Sprintf("%s/quote?amount=%g&from=%s&to=%s&anonymous=false&cexOnly=%t",
-		baseURL, amount, url.QueryEscape(from), url.QueryEscape(to), cexOnly)
+	u := c.quoteURL(from, to, amount, false, cexOnly)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
@@ -189,8 +216,7 @@ func (c *Client) getQuote(ctx context.Context, from, to string, amount float64,
 
 // GetQuoteAnon requests a quote using anonymous routing.
 func (c *Client) GetQuoteAnon(ctx context.Context, from, to string, amount float64) (*QuoteResponse, error) {
-	u := fmt.Sprintf("%s/quote?amount=%g&from=%s&to=%s&anonymous=true&cexOnly=true",
-		baseURL, amount, url.QueryEscape(from), url.QueryEscape(to))
+	u := c.quoteURL(from, to, amount, true, true)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
@@ -236,6 +262,7 @@ func (c *Client) CreateExchangeAnon(ctx context.Context, from, to string, amount
 		"userAgent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
 		"timezone":  "UTC",
 	}
+	c.addPartnerParams(payload)
 
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
@@ -285,6 +312,7 @@ func (c *Client) CreateExchange(ctx context.Context, from, to string, amount flo
 		"userAgent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
 		"timezone":  "UTC",
 	}
+	c.addPartnerParams(payload)
 
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
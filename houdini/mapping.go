@@ -35,10 +35,16 @@ var assetToSymbol = map[string]string{
 	"ZEC.ZEC":   "ZEC",
 }
 
-// sourceChainSymbol maps our RPC chain name to the Houdini USDC token ID for that chain.
-var sourceChainSymbol = map[string]string{
-	"avalanche": "USDCAVAXC",
-	"base":      "USDCBASE",
+// sourceChainSymbol maps our RPC chain name and funding stablecoin symbol to the
+// Houdini token ID for that token.
+var sourceChainSymbol = map[string]map[string]string{
+	"arbitrum":  {"USDC": "USDCARB", "USDT": "USDTARB", "ETH": "ETHARB"},
+	"avalanche": {"USDC": "USDCAVAXC", "USDT": "USDTAVAXC", "AVAX": "AVAXC"},
+	"base":      {"USDC": "USDCBASE", "ETH": "ETHBASE"},
+	"bsc":       {"USDT": "USDTBSC", "USDC": "USDCBSC", "BNB": "BNB"},
+	"ethereum":  {"USDC": "USDC", "USDT": "USDT", "DAI": "DAI", "ETH": "ETH"},
+	"optimism":  {"USDC": "USDCOP", "USDT": "USDTOP"},
+	"polygon":   {"USDC": "USDCPOLYGON", "USDT": "USDTPOLYGON"},
 }
 
 // AssetToSymbol looks up the Houdini token ID for a target asset.
@@ -54,13 +60,13 @@ func LookupSymbol(key string) (string, bool) {
 	return sym, ok
 }
 
-// SourceSymbol returns the Houdini USDC token ID for a source chain.
-func SourceSymbol(chain string) (string, bool) {
-	sym, ok := sourceChainSymbol[chain]
+// SourceSymbol returns the Houdini token ID for a funding token on a source chain.
+func SourceSymbol(chain, token string) (string, bool) {
+	sym, ok := sourceChainSymbol[chain][token]
 	return sym, ok
 }
 
-// SupportedSourceChains returns the RPC chain keys that Houdini can source USDC from.
+// SupportedSourceChains returns the RPC chain keys that Houdini can source stablecoins from.
 func SupportedSourceChains() []string {
 	chains := make([]string, 0, len(sourceChainSymbol))
 	for k := range sourceChainSymbol {
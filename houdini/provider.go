@@ -2,28 +2,32 @@ package houdini
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"github.com/RaghavSood/fundbot/balances"
 	"github.com/RaghavSood/fundbot/swaps"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 // chainIDs for EVM chains
 var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
 	"avalanche": big.NewInt(43114),
 	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
 }
 
 const erc20TransferABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
@@ -33,9 +37,13 @@ type Provider struct {
 	rpcClients map[string]*ethclient.Client
 }
 
-func NewProvider(apiKey, apiSecret string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+// NewProvider returns the Houdini provider. partnerParams are sent with
+// every quote and exchange, e.g. to attribute volume to a partner.
+func NewProvider(apiKey, apiSecret string, partnerParams map[string]string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	client := NewClient(apiKey, apiSecret, httpClient)
+	client.partnerParams = partnerParams
 	return &Provider{
-		client:     NewClient(apiKey, apiSecret, httpClient),
+		client:     client,
 		rpcClients: rpcClients,
 	}
 }
@@ -48,6 +56,17 @@ func (p *Provider) Category() string {
 	return "private"
 }
 
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+// CheckKey verifies the API key and secret by listing tokens.
+func (p *Provider) CheckKey(ctx context.Context) error {
+	_, err := p.client.GetCurrencies(ctx)
+	return err
+}
+
 func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
 	_, ok := AssetToSymbol(asset)
 	return ok
@@ -66,46 +85,39 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 		return nil, fmt.Errorf("houdini: unsupported target asset %s", toAsset)
 	}
 
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var quotes []swaps.Quote
 
 	for _, chain := range SupportedSourceChains() {
-		fromSymbol, ok := SourceSymbol(chain)
+		rpc, ok := p.rpcClients[chain]
 		if !ok {
 			continue
 		}
 
-		// Check dynamic minimum
-		minAmt, _, err := p.client.GetMinMax(ctx, fromSymbol, toSymbol, false)
+		// Pick the first funding token with a Houdini listing and enough balance
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, func(t swaps.FundingToken) bool {
+			_, ok := SourceSymbol(chain, t.Symbol)
+			return ok
+		})
 		if err != nil {
-			log.Printf("houdini: error checking min/max for %s→%s: %v", fromSymbol, toSymbol, err)
-			continue
-		}
-		if usdAmount < minAmt {
-			log.Printf("houdini: skipping %s, below minimum $%.2f (requested $%.2f)", chain, minAmt, usdAmount)
+			log.Printf("houdini: skipping %s: %v", chain, err)
 			continue
 		}
+		fromSymbol, _ := SourceSymbol(chain, token.Symbol)
 
-		rpc, ok := p.rpcClients[chain]
-		if !ok {
-			continue
-		}
-		usdcAddr, ok := thorchain.USDCContracts[chain]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
+		// Check dynamic minimum
+		minAmt, _, err := p.client.GetMinMax(ctx, fromSymbol, toSymbol, false)
 		if err != nil {
-			log.Printf("houdini: error checking USDC balance on %s: %v", chain, err)
+			log.Printf("houdini: error checking min/max for %s→%s: %v", fromSymbol, toSymbol, err)
 			continue
 		}
-		if bal.Cmp(requiredUSDC) < 0 {
-			log.Printf("houdini: skipping %s, insufficient USDC (have %s, need %s)", chain, bal, requiredUSDC)
+		// Houdini limits and amounts are in whole source tokens
+		amount := token.Units(inputAmount)
+		if amount < minAmt {
+			log.Printf("houdini: skipping %s, below minimum %g %s (requested %g)", chain, minAmt, token.Symbol, amount)
 			continue
 		}
 
-		quote, err := p.client.GetQuote(ctx, fromSymbol, toSymbol, usdAmount)
+		quote, err := p.client.GetQuote(ctx, fromSymbol, toSymbol, amount)
 		if err != nil {
 			log.Printf("houdini quote for %s via %s failed: %v", toAsset, chain, err)
 			continue
@@ -113,11 +125,9 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 
 		expectedOut := parseToBigInt(fmt.Sprintf("%g", quote.AmountOut))
 
-		inputAmount := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 		quotes = append(quotes, swaps.Quote{
 			Provider:          "houdini",
-			FromAsset:         mustParseAsset(chain),
+			FromAsset:         token.Asset(chain),
 			ToAsset:           toAsset,
 			FromChain:         chain,
 			InputAmountUSD:    usdAmount,
@@ -140,7 +150,7 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 	return quotes, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *ecdsa.PrivateKey) (swaps.ExecuteResult, error) {
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
 	fromSymbol, _ := quote.ExtraData["houdini_from"].(string)
 	toSymbol, _ := quote.ExtraData["houdini_to"].(string)
 	if fromSymbol == "" || toSymbol == "" {
@@ -164,23 +174,23 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
 	}
 
-	usdcAddr, ok := thorchain.USDCContracts[quote.FromChain]
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
 	if !ok {
-		return swaps.ExecuteResult{}, fmt.Errorf("no USDC contract for %s", quote.FromChain)
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
 	}
 
-	exchange, err := p.client.CreateExchange(ctx, fromSymbol, toSymbol, quote.InputAmountUSD, destination, quoteID)
+	exchange, err := p.client.CreateExchange(ctx, fromSymbol, toSymbol, token.Units(quote.InputAmount), destination, quoteID)
 	if err != nil {
 		return swaps.ExecuteResult{}, fmt.Errorf("houdini create exchange: %w", err)
 	}
 
 	log.Printf("Houdini exchange created: houdiniId=%s, deposit=%s", exchange.HoudiniID, exchange.SenderAddress)
 
-	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	fromAddr := signer.Address()
 
-	txHash, err := transferERC20(ctx, rpc, chainID, privateKey, fromAddr, usdcAddr, common.HexToAddress(exchange.SenderAddress), quote.InputAmount)
+	txHash, err := sendDeposit(ctx, rpc, chainID, signer, fromAddr, token, common.HexToAddress(exchange.SenderAddress), quote.InputAmount)
 	if err != nil {
-		return swaps.ExecuteResult{}, fmt.Errorf("houdini USDC transfer: %w", err)
+		return swaps.ExecuteResult{}, fmt.Errorf("houdini %s transfer: %w", token.Symbol, err)
 	}
 
 	return swaps.ExecuteResult{
@@ -198,6 +208,7 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	if err != nil {
 		return "", fmt.Errorf("houdini get status: %w", err)
 	}
+	swaps.ReportRawStatus(ctx, strconv.Itoa(status.Status))
 
 	// Houdini uses numeric status codes:
 	// 0 = waiting for deposit
@@ -216,7 +227,39 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	}
 }
 
-func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, key *ecdsa.PrivateKey, from, token, to common.Address, amount *big.Int) (string, error) {
+// sendDeposit sends amount of the funding token to a Houdini deposit address:
+// a plain value transfer for native coins, an ERC20 transfer otherwise.
+func sendDeposit(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, token swaps.FundingToken, to common.Address, amount *big.Int) (string, error) {
+	if !token.Native {
+		return transferERC20(ctx, rpc, chainID, signer, from, token.Address, to, amount)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, to, amount, 21000, gasPrice, nil)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing transfer tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending transfer tx: %w", err)
+	}
+
+	log.Printf("Houdini native transfer sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, to common.Address, amount *big.Int) (string, error) {
 	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
 	if err != nil {
 		return "", err
@@ -238,7 +281,7 @@ func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int,
 	}
 
 	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
 	if err != nil {
 		return "", fmt.Errorf("signing transfer tx: %w", err)
 	}
@@ -247,7 +290,7 @@ func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int,
 		return "", fmt.Errorf("sending transfer tx: %w", err)
 	}
 
-	log.Printf("Houdini USDC transfer sent: %s", signedTx.Hash().Hex())
+	log.Printf("Houdini token transfer sent: %s", signedTx.Hash().Hex())
 
 	// Don't wait for mining - return immediately and let status polling handle confirmation
 	return signedTx.Hash().Hex(), nil
@@ -260,9 +303,11 @@ type AnonProvider struct {
 	rpcClients map[string]*ethclient.Client
 }
 
-func NewAnonProvider(apiKey, apiSecret string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *AnonProvider {
+func NewAnonProvider(apiKey, apiSecret string, partnerParams map[string]string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *AnonProvider {
+	client := NewClient(apiKey, apiSecret, httpClient)
+	client.partnerParams = partnerParams
 	return &AnonProvider{
-		client:     NewClient(apiKey, apiSecret, httpClient),
+		client:     client,
 		rpcClients: rpcClients,
 	}
 }
@@ -270,6 +315,12 @@ func NewAnonProvider(apiKey, apiSecret string, rpcClients map[string]*ethclient.
 func (p *AnonProvider) Name() string     { return "houdini-anon" }
 func (p *AnonProvider) Category() string { return "anon-private" }
 
+// CheckKey verifies the API key and secret by listing tokens.
+func (p *AnonProvider) CheckKey(ctx context.Context) error {
+	_, err := p.client.GetCurrencies(ctx)
+	return err
+}
+
 func (p *AnonProvider) SupportsAsset(asset swaps.Asset) bool {
 	_, ok := AssetToSymbol(asset)
 	return ok
@@ -288,56 +339,49 @@ func (p *AnonProvider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount
 		return nil, fmt.Errorf("houdini-anon: unsupported target asset %s", toAsset)
 	}
 
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var quotes []swaps.Quote
 
 	for _, chain := range SupportedSourceChains() {
-		fromSymbol, ok := SourceSymbol(chain)
+		rpc, ok := p.rpcClients[chain]
 		if !ok {
 			continue
 		}
 
-		// Check dynamic minimum (anonymous=true for XMR routes)
-		minAmt, _, err := p.client.GetMinMax(ctx, fromSymbol, toSymbol, true)
+		// Pick the first funding token with a Houdini listing and enough balance
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, func(t swaps.FundingToken) bool {
+			_, ok := SourceSymbol(chain, t.Symbol)
+			return ok
+		})
 		if err != nil {
-			log.Printf("houdini-anon: error checking min/max for %s→%s: %v", fromSymbol, toSymbol, err)
-			continue
-		}
-		if usdAmount < minAmt {
-			log.Printf("houdini-anon: skipping %s, below minimum $%.2f (requested $%.2f)", chain, minAmt, usdAmount)
+			log.Printf("houdini-anon: skipping %s: %v", chain, err)
 			continue
 		}
+		fromSymbol, _ := SourceSymbol(chain, token.Symbol)
 
-		rpc, ok := p.rpcClients[chain]
-		if !ok {
-			continue
-		}
-		usdcAddr, ok := thorchain.USDCContracts[chain]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
+		// Check dynamic minimum (anonymous=true for XMR routes)
+		minAmt, _, err := p.client.GetMinMax(ctx, fromSymbol, toSymbol, true)
 		if err != nil {
-			log.Printf("houdini-anon: error checking USDC balance on %s: %v", chain, err)
+			log.Printf("houdini-anon: error checking min/max for %s→%s: %v", fromSymbol, toSymbol, err)
 			continue
 		}
-		if bal.Cmp(requiredUSDC) < 0 {
+		// Houdini limits and amounts are in whole source tokens
+		amount := token.Units(inputAmount)
+		if amount < minAmt {
+			log.Printf("houdini-anon: skipping %s, below minimum %g %s (requested %g)", chain, minAmt, token.Symbol, amount)
 			continue
 		}
 
-		quote, err := p.client.GetQuoteAnon(ctx, fromSymbol, toSymbol, usdAmount)
+		quote, err := p.client.GetQuoteAnon(ctx, fromSymbol, toSymbol, amount)
 		if err != nil {
 			log.Printf("houdini-anon quote for %s via %s failed: %v", toAsset, chain, err)
 			continue
 		}
 
 		expectedOut := parseToBigInt(fmt.Sprintf("%g", quote.AmountOut))
-		inputAmount := new(big.Int).SetInt64(int64(usdAmount * 1e6))
 
 		quotes = append(quotes, swaps.Quote{
 			Provider:          "houdini-anon",
-			FromAsset:         mustParseAsset(chain),
+			FromAsset:         token.Asset(chain),
 			ToAsset:           toAsset,
 			FromChain:         chain,
 			InputAmountUSD:    usdAmount,
@@ -359,7 +403,7 @@ func (p *AnonProvider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount
 	return quotes, nil
 }
 
-func (p *AnonProvider) Execute(ctx context.Context, quote swaps.Quote, privateKey *ecdsa.PrivateKey) (swaps.ExecuteResult, error) {
+func (p *AnonProvider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
 	fromSymbol, _ := quote.ExtraData["houdini_from"].(string)
 	toSymbol, _ := quote.ExtraData["houdini_to"].(string)
 	if fromSymbol == "" || toSymbol == "" {
@@ -381,23 +425,23 @@ func (p *AnonProvider) Execute(ctx context.Context, quote swaps.Quote, privateKe
 		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
 	}
 
-	usdcAddr, ok := thorchain.USDCContracts[quote.FromChain]
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
 	if !ok {
-		return swaps.ExecuteResult{}, fmt.Errorf("no USDC contract for %s", quote.FromChain)
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
 	}
 
-	exchange, err := p.client.CreateExchangeAnon(ctx, fromSymbol, toSymbol, quote.InputAmountUSD, destination)
+	exchange, err := p.client.CreateExchangeAnon(ctx, fromSymbol, toSymbol, token.Units(quote.InputAmount), destination)
 	if err != nil {
 		return swaps.ExecuteResult{}, fmt.Errorf("houdini-anon create exchange: %w", err)
 	}
 
 	log.Printf("Houdini anon exchange created: houdiniId=%s, deposit=%s", exchange.HoudiniID, exchange.SenderAddress)
 
-	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	fromAddr := signer.Address()
 
-	txHash, err := transferERC20(ctx, rpc, chainID, privateKey, fromAddr, usdcAddr, common.HexToAddress(exchange.SenderAddress), quote.InputAmount)
+	txHash, err := sendDeposit(ctx, rpc, chainID, signer, fromAddr, token, common.HexToAddress(exchange.SenderAddress), quote.InputAmount)
 	if err != nil {
-		return swaps.ExecuteResult{}, fmt.Errorf("houdini-anon USDC transfer: %w", err)
+		return swaps.ExecuteResult{}, fmt.Errorf("houdini-anon %s transfer: %w", token.Symbol, err)
 	}
 
 	return swaps.ExecuteResult{
@@ -415,6 +459,7 @@ func (p *AnonProvider) CheckStatus(ctx context.Context, txHash string, externalI
 	if err != nil {
 		return "", fmt.Errorf("houdini-anon get status: %w", err)
 	}
+	swaps.ReportRawStatus(ctx, strconv.Itoa(status.Status))
 
 	switch {
 	case status.Status == 4:
@@ -426,20 +471,6 @@ func (p *AnonProvider) CheckStatus(ctx context.Context, txHash string, externalI
 	}
 }
 
-// mustParseAsset returns a USDC asset for the given source chain.
-func mustParseAsset(chain string) swaps.Asset {
-	switch chain {
-	case "avalanche":
-		a, _ := swaps.ParseAsset("AVAX.USDC-0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E")
-		return a
-	case "base":
-		a, _ := swaps.ParseAsset("BASE.USDC-0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
-		return a
-	default:
-		return swaps.Asset{Chain: strings.ToUpper(chain), Symbol: "USDC"}
-	}
-}
-
 // parseToBigInt parses a decimal string like "0.00123456" to a big.Int
 // by removing the decimal point. Multiplies by 1e8 for comparison.
 func parseToBigInt(s string) *big.Int {
@@ -0,0 +1,452 @@
+// Package ledger keeps a double-entry record of the funding tokens moving
+// in and out of the derived wallets and reconciles it with their on-chain
+// balances.
+//
+// Token movements are read from the chain's Transfer logs, so nothing the
+// bot does has to report them; the database only labels them (swap, sweep,
+// gas refill). Plain native transfers leave no logs, so native coins are
+// recorded for gas spent by topup transactions but not reconciled.
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// Entry kinds
+const (
+	KindOpening    = "opening"    // balance when the wallet was first seen
+	KindDeposit    = "deposit"    // tokens received from outside
+	KindTransfer   = "transfer"   // between two wallets, e.g. funding a fresh address
+	KindSwap       = "swap"       // spent by a topup transaction
+	KindSweep      = "sweep"      // sent to the treasury, directly or via CoW
+	KindGasRefill  = "gas_refill" // sold on CoW for native gas
+	KindWithdrawal = "withdrawal" // any other outflow
+	KindGas        = "gas"        // native coin paid for a topup transaction
+)
+
+// Accounts on the far side of entries that aren't addresses
+const (
+	accountOpening = "opening"
+	accountGas     = "gas"
+)
+
+// confirmations keeps reconciliation this many blocks behind the head, so a
+// reorg doesn't leave entries for logs that no longer exist.
+const confirmations = 12
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Reconciler periodically records new wallet token movements in the ledger
+// and compares each wallet's ledger balance with its balance on chain.
+type Reconciler struct {
+	cfg        *config.Config
+	store      *db.Store
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+}
+
+func New(cfg *config.Config, store *db.Store, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client) *Reconciler {
+	return &Reconciler{
+		cfg:        cfg,
+		store:      store,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+	}
+}
+
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(r.cfg.Reconcile.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	r.reconcile(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Reconciler stopped")
+			return
+		case <-ticker.C:
+			r.reconcile(ctx)
+		}
+	}
+}
+
+// wallets returns the addresses of the wallets in use: the shared wallet in
+// single mode, or one per address assignment in multi mode.
+func (r *Reconciler) wallets(ctx context.Context) ([]common.Address, error) {
+	indexes := []uint32{0}
+	if r.cfg.Mode != config.ModeSingle {
+		assignments, err := r.store.ListAddressAssignments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		indexes = indexes[:0]
+		for _, a := range assignments {
+			indexes = append(indexes, uint32(a.ID))
+		}
+	}
+
+	addresses := make([]common.Address, 0, len(indexes))
+	for _, index := range indexes {
+		addr, err := r.keyring.Address(index)
+		if err != nil {
+			log.Printf("Reconciler: error deriving wallet %d: %v", index, err)
+			continue
+		}
+		addresses = append(addresses, addr)
+	}
+	return addresses, nil
+}
+
+func (r *Reconciler) reconcile(ctx context.Context) {
+	wallets, err := r.wallets(ctx)
+	if err != nil {
+		log.Printf("Reconciler: error listing wallets: %v", err)
+		return
+	}
+	for chain, rpc := range swaps.EnabledClients(r.rpcClients) {
+		if ctx.Err() != nil {
+			return
+		}
+		tokens := thorchain.FundingTokens.ERC20s(chain)
+		if len(tokens) == 0 {
+			continue
+		}
+		if err := r.reconcileChain(ctx, chain, rpc, tokens, wallets); err != nil {
+			log.Printf("Reconciler: %s: %v", chain, err)
+		}
+	}
+}
+
+// reconcileChain records the token transfers of known wallets since the
+// chain's cursor, then compares every wallet's ledger balance with its
+// balance at the same block. A wallet seen for the first time gets an
+// opening entry for whatever its balance is beyond the entries already
+// recorded against it, and its own transfers are followed from there.
+func (r *Reconciler) reconcileChain(ctx context.Context, chain string, rpc *ethclient.Client, tokens []swaps.FundingToken, wallets []common.Address) error {
+	head, err := rpc.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("reading block number: %w", err)
+	}
+	if head <= confirmations {
+		return nil
+	}
+	to := head - confirmations
+
+	from := to + 1
+	cursor, err := r.store.GetLedgerCursor(ctx, chain)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("reading cursor: %w", err)
+	case uint64(cursor) >= to:
+		return nil
+	default:
+		from = uint64(cursor) + 1
+	}
+
+	addresses, err := r.chainAddresses(ctx, chain, wallets)
+	if err != nil {
+		return fmt.Errorf("listing fresh addresses: %w", err)
+	}
+
+	entries, err := r.store.ListChainLedgerEntries(ctx, chain)
+	if err != nil {
+		return fmt.Errorf("reading ledger: %w", err)
+	}
+	known := make(map[string]bool)
+	for _, e := range entries {
+		if e.Kind == KindOpening {
+			known[e.FromAccount], known[e.ToAccount] = true, true
+		}
+	}
+
+	var followed []common.Address
+	for _, addr := range addresses {
+		if known[addr.Hex()] {
+			followed = append(followed, addr)
+		}
+	}
+	if len(followed) > 0 && from <= to {
+		if err := r.recordTransfers(ctx, chain, rpc, tokens, followed, from, to); err != nil {
+			return err
+		}
+	}
+	r.recordGas(ctx, chain, rpc)
+
+	contracts := make([]common.Address, len(tokens))
+	for i, t := range tokens {
+		contracts[i] = t.Address
+	}
+	onchain, err := balances.FetchChainBalancesAt(ctx, rpc, chain, addresses, contracts, new(big.Int).SetUint64(to))
+	if err != nil {
+		return fmt.Errorf("fetching balances at block %d: %w", to, err)
+	}
+
+	if entries, err = r.store.ListChainLedgerEntries(ctx, chain); err != nil {
+		return fmt.Errorf("reading ledger: %w", err)
+	}
+	ledger := ledgerBalances(entries)
+	opened := make(map[string]bool)
+	for _, e := range entries {
+		if e.Kind == KindOpening {
+			opened[e.Reference+"/"+e.Asset] = true
+		}
+	}
+
+	for _, bal := range onchain {
+		for i, raw := range bal.TokenBalances {
+			actual, ok := new(big.Int).SetString(raw, 10)
+			if !ok || i >= len(tokens) {
+				continue
+			}
+			asset := tokens[i].Symbol
+			recorded := ledger.get(bal.Address, asset)
+
+			if !opened[bal.Address+"/"+asset] {
+				if err := r.recordOpening(ctx, chain, bal.Address, asset, new(big.Int).Sub(actual, recorded), to); err != nil {
+					return fmt.Errorf("recording opening balance of %s: %w", bal.Address, err)
+				}
+				recorded = actual
+			}
+
+			diff := new(big.Int).Sub(actual, recorded)
+			if diff.Sign() != 0 {
+				log.Printf("Reconciler: %s %s on %s is %s on chain but %s in the ledger", bal.Address, asset, chain, actual, recorded)
+			}
+			if err := r.store.UpsertLedgerReconciliation(ctx, db.UpsertLedgerReconciliationParams{
+				Account:        bal.Address,
+				Chain:          chain,
+				Asset:          asset,
+				LedgerBalance:  recorded.String(),
+				OnchainBalance: actual.String(),
+				Difference:     diff.String(),
+				BlockNumber:    int64(to),
+			}); err != nil {
+				return fmt.Errorf("recording reconciliation of %s: %w", bal.Address, err)
+			}
+		}
+	}
+
+	return r.store.SetLedgerCursor(ctx, db.SetLedgerCursorParams{Chain: chain, BlockNumber: int64(to)})
+}
+
+// chainAddresses adds the fresh addresses used on chain to wallets.
+func (r *Reconciler) chainAddresses(ctx context.Context, chain string, wallets []common.Address) ([]common.Address, error) {
+	fresh, err := r.store.ListFundedFreshAddresses(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[common.Address]bool)
+	var addresses []common.Address
+	for _, addr := range wallets {
+		if !seen[addr] {
+			seen[addr] = true
+			addresses = append(addresses, addr)
+		}
+	}
+	for _, f := range fresh {
+		addr := common.HexToAddress(f.Address)
+		if !seen[addr] {
+			seen[addr] = true
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses, nil
+}
+
+// recordTransfers records the funding token Transfer logs from or to the
+// followed addresses in blocks from..to, log_block_range blocks per query.
+func (r *Reconciler) recordTransfers(ctx context.Context, chain string, rpc *ethclient.Client, tokens []swaps.FundingToken, followed []common.Address, from, to uint64) error {
+	contracts := make([]common.Address, len(tokens))
+	symbols := make(map[common.Address]string, len(tokens))
+	for i, t := range tokens {
+		contracts[i] = t.Address
+		symbols[t.Address] = t.Symbol
+	}
+	topics := make([]common.Hash, len(followed))
+	isFollowed := make(map[common.Address]bool, len(followed))
+	for i, addr := range followed {
+		topics[i] = common.BytesToHash(addr.Bytes())
+		isFollowed[addr] = true
+	}
+
+	step := uint64(r.cfg.Reconcile.LogBlockRange)
+	for start := from; start <= to; start += step {
+		end := min(start+step-1, to)
+		// Outgoing and incoming transfers; one between two followed
+		// addresses comes back from both and is recorded once
+		for _, filter := range [][][]common.Hash{
+			{{transferTopic}, topics},
+			{{transferTopic}, nil, topics},
+		} {
+			logs, err := rpc.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Addresses: contracts,
+				Topics:    filter,
+			})
+			if err != nil {
+				return fmt.Errorf("reading logs of blocks %d-%d: %w", start, end, err)
+			}
+			for _, lg := range logs {
+				if lg.Removed || len(lg.Topics) != 3 {
+					continue
+				}
+				sender := common.BytesToAddress(lg.Topics[1].Bytes())
+				recipient := common.BytesToAddress(lg.Topics[2].Bytes())
+				amount := new(big.Int).SetBytes(lg.Data)
+				if err := r.store.InsertLedgerEntry(ctx, db.InsertLedgerEntryParams{
+					Chain:       chain,
+					Asset:       symbols[lg.Address],
+					Amount:      amount.String(),
+					FromAccount: sender.Hex(),
+					ToAccount:   recipient.Hex(),
+					Kind:        r.classify(ctx, chain, lg, sender, recipient, amount, isFollowed),
+					Reference:   fmt.Sprintf("%s:%d", lg.TxHash.Hex(), lg.Index),
+					Note:        fmt.Sprintf("block %d", lg.BlockNumber),
+				}); err != nil {
+					return fmt.Errorf("recording transfer %s: %w", lg.TxHash.Hex(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// classify labels a transfer by what the database knows of it. Lookup
+// errors fall back to the plain deposit and withdrawal kinds; the amounts
+// are right either way.
+func (r *Reconciler) classify(ctx context.Context, chain string, lg types.Log, sender, recipient common.Address, amount *big.Int, followed map[common.Address]bool) string {
+	switch {
+	case followed[sender] && followed[recipient]:
+		return KindTransfer
+	case followed[recipient]:
+		return KindDeposit
+	case r.cfg.Sweep != nil && recipient == common.HexToAddress(r.cfg.Sweep.Treasury):
+		return KindSweep
+	case recipient == common.HexToAddress(cowswap.SettlementContract):
+		// CoW orders are recorded by UID, not tx hash; match them by amount
+		if n, err := r.store.CountCowSweeps(ctx, db.CountCowSweepsParams{Chain: chain, WalletAddress: sender.Hex(), Amount: amount.String()}); err == nil && n > 0 {
+			return KindSweep
+		}
+		if n, err := r.store.CountGasRefillsBySale(ctx, db.CountGasRefillsBySaleParams{Chain: chain, WalletAddress: sender.Hex(), SellAmount: amount.String()}); err == nil && n > 0 {
+			return KindGasRefill
+		}
+	}
+	if n, err := r.store.CountTopupTransactionsByHash(ctx, db.CountTopupTransactionsByHashParams{Chain: chain, TxHash: lg.TxHash.Hex()}); err == nil && n > 0 {
+		return KindSwap
+	}
+	return KindWithdrawal
+}
+
+// recordGas records the native coin spent on topup transactions the
+// tracker has priced, charged to the address that sent each one.
+func (r *Reconciler) recordGas(ctx context.Context, chain string, rpc *ethclient.Client) {
+	txs, err := r.store.ListUnledgeredTopupGas(ctx, chain)
+	if err != nil {
+		log.Printf("Reconciler: error listing gas on %s: %v", chain, err)
+		return
+	}
+	asset := strings.ToUpper(chain)
+	for _, t := range thorchain.FundingTokens[chain] {
+		if t.Native {
+			asset = t.Symbol
+		}
+	}
+	for _, t := range txs {
+		tx, _, err := rpc.TransactionByHash(ctx, common.HexToHash(t.TxHash))
+		if err != nil {
+			log.Printf("Reconciler: error fetching %s on %s: %v", t.TxHash, chain, err)
+			continue
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			log.Printf("Reconciler: error recovering sender of %s on %s: %v", t.TxHash, chain, err)
+			continue
+		}
+		if err := r.store.InsertLedgerEntry(ctx, db.InsertLedgerEntryParams{
+			Chain:       chain,
+			Asset:       asset,
+			Amount:      t.GasCost,
+			FromAccount: sender.Hex(),
+			ToAccount:   accountGas,
+			Kind:        KindGas,
+			Reference:   t.TxHash,
+		}); err != nil {
+			log.Printf("Reconciler: error recording gas of %s on %s: %v", t.TxHash, chain, err)
+		}
+	}
+}
+
+// recordOpening records amount of asset as already held by account at
+// block. A negative amount is recorded as leaving the account.
+func (r *Reconciler) recordOpening(ctx context.Context, chain, account, asset string, amount *big.Int, block uint64) error {
+	from, to := accountOpening, account
+	if amount.Sign() < 0 {
+		from, to = account, accountOpening
+		amount = new(big.Int).Neg(amount)
+	}
+	return r.store.InsertLedgerEntry(ctx, db.InsertLedgerEntryParams{
+		Chain:       chain,
+		Asset:       asset,
+		Amount:      amount.String(),
+		FromAccount: from,
+		ToAccount:   to,
+		Kind:        KindOpening,
+		Reference:   account,
+		Note:        fmt.Sprintf("balance at block %d", block),
+	})
+}
+
+// accountBalances maps account, then asset, to its balance.
+type accountBalances map[string]map[string]*big.Int
+
+func (b accountBalances) get(account, asset string) *big.Int {
+	if v, ok := b[account][asset]; ok {
+		return v
+	}
+	return new(big.Int)
+}
+
+func (b accountBalances) add(account, asset string, amount *big.Int) {
+	if b[account] == nil {
+		b[account] = make(map[string]*big.Int)
+	}
+	b[account][asset] = new(big.Int).Add(b.get(account, asset), amount)
+}
+
+// ledgerBalances sums entries into each account's balance: credited to the
+// receiving account, debited from the sending one.
+func ledgerBalances(entries []db.LedgerEntry) accountBalances {
+	b := make(accountBalances)
+	for _, e := range entries {
+		amount, ok := new(big.Int).SetString(e.Amount, 10)
+		if !ok {
+			continue
+		}
+		b.add(e.ToAccount, e.Asset, amount)
+		b.add(e.FromAccount, e.Asset, new(big.Int).Neg(amount))
+	}
+	return b
+}
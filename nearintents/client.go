@@ -53,32 +53,60 @@ func (c *Client) SubmitDepositTx(ctx context.Context, txHash, depositAddress str
 // executionStatusResponse is a minimal struct for parsing the status endpoint response,
 // bypassing the SDK's strict model validation which rejects valid API responses.
 type executionStatusResponse struct {
-	Status string `json:"status"`
+	Status      string `json:"status"`
+	SwapDetails struct {
+		AmountOutFormatted       string `json:"amountOutFormatted"`
+		DestinationChainTxHashes []struct {
+			Hash string `json:"hash"`
+		} `json:"destinationChainTxHashes"`
+	} `json:"swapDetails"`
 }
 
 // GetExecutionStatus checks the status of a swap by deposit address.
-// Uses direct HTTP instead of the SDK to avoid deserialization errors from strict model validation.
 func (c *Client) GetExecutionStatus(ctx context.Context, depositAddress string) (string, error) {
+	result, err := c.getExecution(ctx, depositAddress)
+	if err != nil {
+		return "", err
+	}
+	return result.Status, nil
+}
+
+// GetExecutionResult returns the delivered amount (formatted, like the quote's
+// amountOutFormatted) and destination tx hash of a swap by deposit address.
+func (c *Client) GetExecutionResult(ctx context.Context, depositAddress string) (amountOut, destTxHash string, err error) {
+	result, err := c.getExecution(ctx, depositAddress)
+	if err != nil {
+		return "", "", err
+	}
+	if hashes := result.SwapDetails.DestinationChainTxHashes; len(hashes) > 0 {
+		destTxHash = hashes[0].Hash
+	}
+	return result.SwapDetails.AmountOutFormatted, destTxHash, nil
+}
+
+// getExecution fetches a swap's status by deposit address.
+// Uses direct HTTP instead of the SDK to avoid deserialization errors from strict model validation.
+func (c *Client) getExecution(ctx context.Context, depositAddress string) (*executionStatusResponse, error) {
 	url := fmt.Sprintf("https://1click.chaindefuser.com/v0/status?depositAddress=%s", depositAddress)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", fmt.Errorf("nearintents GetExecutionStatus: %w", err)
+		return nil, fmt.Errorf("nearintents GetExecutionStatus: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("nearintents GetExecutionStatus: %w", err)
+		return nil, fmt.Errorf("nearintents GetExecutionStatus: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("nearintents GetExecutionStatus: HTTP %d", resp.StatusCode)
+		return nil, fmt.Errorf("nearintents GetExecutionStatus: HTTP %d", resp.StatusCode)
 	}
 
 	var result executionStatusResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("nearintents GetExecutionStatus: %w", err)
+		return nil, fmt.Errorf("nearintents GetExecutionStatus: %w", err)
 	}
-	return result.Status, nil
+	return &result, nil
 }
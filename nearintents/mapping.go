@@ -29,8 +29,10 @@ var assetToTokenID = map[string]string{
 
 // sourceChainTokenID maps RPC chain name to the Near Intents USDC token ID for that chain.
 var sourceChainTokenID = map[string]string{
+	"arbitrum":  "nep141:arb-0xaf88d065e77c8cc2239327c5edb3a432268e5831.omft.near",
 	"avalanche": "nep245:v2_1.omni.hot.tg:43114_3atVJH3r5c4GqiSYmg9fECvjc47o",
 	"base":      "nep141:base-0x833589fcd6edb6e08f4c7c32d4f71b54bda02913.omft.near",
+	"ethereum":  "nep141:eth-0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48.omft.near",
 }
 
 // AssetToTokenID looks up the Near Intents token ID for a target asset.
@@ -2,7 +2,6 @@ package nearintents
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
@@ -14,17 +13,21 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"github.com/RaghavSood/fundbot/balances"
 	"github.com/RaghavSood/fundbot/swaps"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
 	"avalanche": big.NewInt(43114),
 	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
 }
 
 const erc20TransferABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
@@ -49,6 +52,11 @@ func (p *Provider) Category() string {
 	return "dex"
 }
 
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return "https://1click.chaindefuser.com"
+}
+
 func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
 	_, ok := AssetToTokenID(asset)
 	return ok
@@ -67,8 +75,6 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 		return nil, fmt.Errorf("nearintents: unsupported target asset %s", toAsset)
 	}
 
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var quotes []swaps.Quote
 
 	for _, chain := range SupportedSourceChains() {
@@ -81,22 +87,15 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 		if !ok {
 			continue
 		}
-		usdcAddr, ok := thorchain.USDCContracts[chain]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
+
+		// Our Near Intents source token IDs are USDC-only
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, swaps.OnlySymbol("USDC"))
 		if err != nil {
-			log.Printf("nearintents: error checking USDC balance on %s: %v", chain, err)
-			continue
-		}
-		if bal.Cmp(requiredUSDC) < 0 {
-			log.Printf("nearintents: skipping %s, insufficient USDC (have %s, need %s)", chain, bal, requiredUSDC)
+			log.Printf("nearintents: skipping %s: %v", chain, err)
 			continue
 		}
 
-		// USDC has 6 decimals
-		amount := fmt.Sprintf("%d", requiredUSDC.Int64())
+		amount := inputAmount.String()
 		deadline := time.Now().Add(60 * time.Minute)
 
 		quoteReq := *oneclick.NewQuoteRequest(
@@ -132,11 +131,11 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 
 		quotes = append(quotes, swaps.Quote{
 			Provider:          "nearintents",
-			FromAsset:         mustParseAsset(chain),
+			FromAsset:         token.Asset(chain),
 			ToAsset:           toAsset,
 			FromChain:         chain,
 			InputAmountUSD:    usdAmount,
-			InputAmount:       requiredUSDC,
+			InputAmount:       inputAmount,
 			ExpectedOutput:    resp.Quote.AmountOutFormatted,
 			ExpectedOutputRaw: expectedOut,
 			ExtraData: map[string]interface{}{
@@ -154,7 +153,7 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 	return quotes, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *ecdsa.PrivateKey) (swaps.ExecuteResult, error) {
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
 	depositAddr, _ := quote.ExtraData["nearintents_deposit_address"].(string)
 	if depositAddr == "" {
 		return swaps.ExecuteResult{}, fmt.Errorf("nearintents: missing deposit address in quote ExtraData")
@@ -170,14 +169,51 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
 	}
 
-	usdcAddr, ok := thorchain.USDCContracts[quote.FromChain]
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	txHash, err := transferERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, common.HexToAddress(depositAddr), quote.InputAmount)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("nearintents USDC transfer: %w", err)
+	}
+
+	// Submit tx hash to speed up processing (best-effort)
+	if submitErr := p.client.SubmitDepositTx(ctx, txHash, depositAddr); submitErr != nil {
+		log.Printf("nearintents: failed to submit deposit tx (non-fatal): %v", submitErr)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: depositAddr, // used for status polling
+	}, nil
+}
+
+// ExecuteCalls sends the deposit from a smart account.
+func (p *Provider) ExecuteCalls(ctx context.Context, quote swaps.Quote, sender swaps.CallSender) (swaps.ExecuteResult, error) {
+	depositAddr, _ := quote.ExtraData["nearintents_deposit_address"].(string)
+	if depositAddr == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("nearintents: missing deposit address in quote ExtraData")
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
 	if !ok {
-		return swaps.ExecuteResult{}, fmt.Errorf("no USDC contract for %s", quote.FromChain)
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
 	}
 
-	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+	data, err := parsed.Pack("transfer", common.HexToAddress(depositAddr), quote.InputAmount)
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
 
-	txHash, err := transferERC20(ctx, rpc, chainID, privateKey, fromAddr, usdcAddr, common.HexToAddress(depositAddr), quote.InputAmount)
+	txHash, err := sender.SendCalls(ctx, quote.FromChain, []swaps.Call{{To: token.Address, Value: big.NewInt(0), Data: data}})
 	if err != nil {
 		return swaps.ExecuteResult{}, fmt.Errorf("nearintents USDC transfer: %w", err)
 	}
@@ -202,11 +238,14 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	if err != nil {
 		return "", fmt.Errorf("nearintents get status: %w", err)
 	}
+	swaps.ReportRawStatus(ctx, status)
 
 	switch status {
 	case "SUCCESS":
 		return "completed", nil
-	case "FAILED", "REFUNDED":
+	case "REFUNDED":
+		return "refunded", nil
+	case "FAILED":
 		return "failed", nil
 	default:
 		// PENDING_DEPOSIT, INCOMPLETE_DEPOSIT, PROCESSING, KNOWN_DEPOSIT_TX
@@ -214,7 +253,16 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	}
 }
 
-func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, key *ecdsa.PrivateKey, from, token, to common.Address, amount *big.Int) (string, error) {
+// Result returns the delivered amount and destination transaction.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	amountOut, destTxHash, err := p.client.GetExecutionResult(ctx, externalID)
+	if err != nil {
+		return swaps.SwapResult{}, fmt.Errorf("nearintents get result: %w", err)
+	}
+	return swaps.SwapResult{OutputAmount: amountOut, DestTxHash: destTxHash}, nil
+}
+
+func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, to common.Address, amount *big.Int) (string, error) {
 	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
 	if err != nil {
 		return "", err
@@ -236,7 +284,7 @@ func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int,
 	}
 
 	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
 	if err != nil {
 		return "", fmt.Errorf("signing transfer tx: %w", err)
 	}
@@ -251,19 +299,6 @@ func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int,
 	return signedTx.Hash().Hex(), nil
 }
 
-func mustParseAsset(chain string) swaps.Asset {
-	switch chain {
-	case "avalanche":
-		a, _ := swaps.ParseAsset("AVAX.USDC-0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E")
-		return a
-	case "base":
-		a, _ := swaps.ParseAsset("BASE.USDC-0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
-		return a
-	default:
-		return swaps.Asset{Chain: strings.ToUpper(chain), Symbol: "USDC"}
-	}
-}
-
 // parseToBigInt parses a decimal string like "0.00123456" to a big.Int
 // by removing the decimal point. Pads to 8 decimal places for comparison.
 func parseToBigInt(s string) *big.Int {
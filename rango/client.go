@@ -0,0 +1,137 @@
+package rango
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const baseURL = "https://api.rango.exchange/basic"
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// Token describes a token in a Rango route.
+type Token struct {
+	Blockchain string  `json:"blockchain"`
+	Symbol     string  `json:"symbol"`
+	Address    *string `json:"address"`
+	Decimals   int     `json:"decimals"`
+}
+
+// Route is the selected route for a swap.
+type Route struct {
+	OutputAmount    string `json:"outputAmount"`
+	OutputAmountMin string `json:"outputAmountMin"`
+	From            Token  `json:"from"`
+	To              Token  `json:"to"`
+	Swapper         struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"swapper"`
+	EstimatedTimeInSeconds int64 `json:"estimatedTimeInSeconds"`
+}
+
+// EVMTransaction is the transaction returned for EVM source chains.
+type EVMTransaction struct {
+	Type        string  `json:"type"`
+	ApproveTo   *string `json:"approveTo"`
+	ApproveData *string `json:"approveData"`
+	TxTo        string  `json:"txTo"`
+	TxData      string  `json:"txData"`
+	Value       *string `json:"value"`
+	GasLimit    *string `json:"gasLimit"`
+	GasPrice    *string `json:"gasPrice"`
+}
+
+// SwapResponse is the response from GET /swap.
+type SwapResponse struct {
+	RequestID  string          `json:"requestId"`
+	ResultType string          `json:"resultType"` // OK, HIGH_IMPACT, NO_ROUTE, INPUT_LIMIT_ISSUE
+	Route      *Route          `json:"route"`
+	Tx         *EVMTransaction `json:"tx"`
+	Error      *string         `json:"error"`
+}
+
+// StatusResponse is the response from GET /status.
+type StatusResponse struct {
+	Status *string `json:"status"` // running, success, failed (null while unknown)
+	Error  *string `json:"error"`
+	Output *struct {
+		Type   string `json:"type"` // DESIRED_OUTPUT, REVERTED_TO_INPUT, MIDDLE_ASSET_IN_SRC, MIDDLE_ASSET_IN_DEST
+		Amount string `json:"amount"`
+	} `json:"output"`
+}
+
+// GetSwap requests the best route plus an executable transaction for it.
+// amount is in the source token's smallest unit.
+func (c *Client) GetSwap(ctx context.Context, from, to, amount, fromAddress, toAddress string) (*SwapResponse, error) {
+	params := url.Values{}
+	params.Set("from", from)
+	params.Set("to", to)
+	params.Set("amount", amount)
+	params.Set("fromAddress", fromAddress)
+	params.Set("toAddress", toAddress)
+	params.Set("slippage", "1")
+	params.Set("disableEstimate", "false")
+	params.Set("apiKey", c.apiKey)
+
+	var result SwapResponse
+	if err := c.get(ctx, baseURL+"/swap?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("rango swap: %w", err)
+	}
+	return &result, nil
+}
+
+// GetStatus retrieves the status of a swap by request ID and source tx hash.
+func (c *Client) GetStatus(ctx context.Context, requestID, txHash string) (*StatusResponse, error) {
+	params := url.Values{}
+	params.Set("requestId", requestID)
+	params.Set("txId", txHash)
+	params.Set("apiKey", c.apiKey)
+
+	var result StatusResponse
+	if err := c.get(ctx, baseURL+"/status?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("rango status: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *Client) get(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
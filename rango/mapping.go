@@ -0,0 +1,85 @@
+package rango
+
+import (
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// chainToBlockchain maps our chain notation to the Rango blockchain identifier and
+// that chain's native symbol. Rango assets are written BLOCKCHAIN.SYMBOL or
+// BLOCKCHAIN.SYMBOL--ADDRESS.
+var chainToBlockchain = map[string]struct {
+	Blockchain string
+	Native     string
+}{
+	"BTC":     {Blockchain: "BTC", Native: "BTC"},
+	"LTC":     {Blockchain: "LTC", Native: "LTC"},
+	"DOGE":    {Blockchain: "DOGE", Native: "DOGE"},
+	"BCH":     {Blockchain: "BCH", Native: "BCH"},
+	"ETH":     {Blockchain: "ETH", Native: "ETH"},
+	"BSC":     {Blockchain: "BSC", Native: "BNB"},
+	"POLYGON": {Blockchain: "POLYGON", Native: "POL"},
+	"ARB":     {Blockchain: "ARBITRUM", Native: "ETH"},
+	"OP":      {Blockchain: "OPTIMISM", Native: "ETH"},
+	"AVAX":    {Blockchain: "AVAX_CCHAIN", Native: "AVAX"},
+	"BASE":    {Blockchain: "BASE", Native: "ETH"},
+	"SOL":     {Blockchain: "SOLANA", Native: "SOL"},
+	"GAIA":    {Blockchain: "COSMOS", Native: "ATOM"},
+	"OSMO":    {Blockchain: "OSMOSIS", Native: "OSMO"},
+	"THOR":    {Blockchain: "THORCHAIN", Native: "RUNE"},
+	"TRON":    {Blockchain: "TRON", Native: "TRX"},
+	"MAYA":    {Blockchain: "MAYA", Native: "CACAO"},
+	"ZKSYNC":  {Blockchain: "ZKSYNC", Native: "ETH"},
+	"LINEA":   {Blockchain: "LINEA", Native: "ETH"},
+	"SCROLL":  {Blockchain: "SCROLL", Native: "ETH"},
+	"BLAST":   {Blockchain: "BLAST", Native: "ETH"},
+}
+
+// sourceBlockchains maps our RPC chain name to the Rango blockchain we fund swaps from.
+var sourceBlockchains = map[string]string{
+	"avalanche": "AVAX_CCHAIN",
+	"base":      "BASE",
+	"ethereum":  "ETH",
+	"optimism":  "OPTIMISM",
+	"polygon":   "POLYGON",
+}
+
+// AssetToRango converts a target asset to Rango notation. Rango resolves
+// symbol-only tokens server-side, so any symbol on a known chain is accepted.
+func AssetToRango(asset swaps.Asset) (string, bool) {
+	bc, ok := chainToBlockchain[asset.Chain]
+	if !ok {
+		return "", false
+	}
+	if asset.ContractAddress != "" {
+		return bc.Blockchain + "." + asset.Symbol + "--" + asset.ContractAddress, true
+	}
+	return bc.Blockchain + "." + asset.Symbol, true
+}
+
+// IsKnownAsset reports whether an asset is unambiguous without resolution:
+// a chain's native coin, or a token with an explicit contract address.
+func IsKnownAsset(asset swaps.Asset) bool {
+	bc, ok := chainToBlockchain[asset.Chain]
+	if !ok {
+		return false
+	}
+	return asset.ContractAddress != "" || asset.Symbol == bc.Native
+}
+
+// SourceAsset returns the Rango notation for a funding token on a source RPC chain.
+func SourceAsset(chain string, token swaps.FundingToken) (string, bool) {
+	bc, ok := sourceBlockchains[chain]
+	if !ok {
+		return "", false
+	}
+	return bc + "." + token.Symbol + "--" + token.Address.Hex(), true
+}
+
+// SupportedSourceChains returns the RPC chain keys that Rango can fund swaps from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceBlockchains))
+	for k := range sourceBlockchains {
+		chains = append(chains, k)
+	}
+	return chains
+}
@@ -0,0 +1,316 @@
+package rango
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(apiKey, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "rango"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	return IsKnownAsset(asset)
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	to, ok := AssetToRango(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("rango: unsupported target asset %s", toAsset)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Pick the first funding token with enough balance on this chain
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, nil)
+		if err != nil {
+			log.Printf("rango: skipping %s: %v", chain, err)
+			continue
+		}
+		from, _ := SourceAsset(chain, token)
+
+		swap, err := p.client.GetSwap(ctx, from, to, inputAmount.String(), sender.Hex(), destination)
+		if err != nil {
+			log.Printf("rango quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+		if swap.ResultType != "OK" || swap.Route == nil || swap.Tx == nil {
+			reason := swap.ResultType
+			if swap.Error != nil {
+				reason += ": " + *swap.Error
+			}
+			log.Printf("rango quote for %s via %s unusable: %s", toAsset, chain, reason)
+			continue
+		}
+		if swap.Tx.Type != "EVM" {
+			log.Printf("rango quote for %s via %s returned non-EVM tx type %q", toAsset, chain, swap.Tx.Type)
+			continue
+		}
+
+		outputAmount, ok := new(big.Int).SetString(swap.Route.OutputAmount, 10)
+		if !ok {
+			log.Printf("rango: invalid output amount %q", swap.Route.OutputAmount)
+			continue
+		}
+		expectedOut := formatUnits(outputAmount, swap.Route.To.Decimals)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "rango",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    expectedOut,
+			ExpectedOutputRaw: parseToBigInt(expectedOut),
+			Router:            swap.Tx.TxTo,
+			ExtraData: map[string]interface{}{
+				"rango_request_id": swap.RequestID,
+				"rango_tx":         *swap.Tx,
+				"rango_swapper":    swap.Route.Swapper.Title,
+				"rango_min_out":    swap.Route.OutputAmountMin,
+				"rango_eta_s":      swap.Route.EstimatedTimeInSeconds,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("rango: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	requestID, _ := quote.ExtraData["rango_request_id"].(string)
+	tx, ok := quote.ExtraData["rango_tx"].(EVMTransaction)
+	if !ok || requestID == "" || tx.TxTo == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("rango: missing transaction in quote ExtraData")
+	}
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	// Step 1: Approval, if Rango says one is needed
+	if tx.ApproveTo != nil && tx.ApproveData != nil && *tx.ApproveTo != "" {
+		if _, err := sendTx(ctx, rpc, chainID, signer, fromAddr, *tx.ApproveTo, *tx.ApproveData, nil, nil, true); err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", quote.FromAsset.Symbol, err)
+		}
+	}
+
+	// Step 2: Main swap transaction
+	txHash, err := sendTx(ctx, rpc, chainID, signer, fromAddr, tx.TxTo, tx.TxData, tx.Value, tx.GasLimit, false)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("swap tx: %w", err)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: requestID,
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	if externalID == "" {
+		return "pending", nil
+	}
+
+	status, err := p.client.GetStatus(ctx, externalID, txHash)
+	if err != nil {
+		return "", err
+	}
+
+	if status.Status == nil {
+		return "pending", nil
+	}
+	swaps.ReportRawStatus(ctx, *status.Status)
+
+	switch *status.Status {
+	case "success":
+		// A "successful" route can still leave funds in an intermediate asset
+		// or revert them to the input; only the desired output counts as done.
+		if status.Output != nil && status.Output.Type != "" && status.Output.Type != "DESIRED_OUTPUT" {
+			return "failed", nil
+		}
+		return "completed", nil
+	case "failed":
+		return "failed", nil
+	default:
+		// running
+		return "pending", nil
+	}
+}
+
+// sendTx signs and broadcasts a transaction built by Rango.
+// gasLimit is estimated when Rango doesn't supply one. If wait is true, it blocks until mined.
+func sendTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, toHex, dataHex string, valueStr, gasLimitStr *string, wait bool) (string, error) {
+	to := common.HexToAddress(toHex)
+
+	data, err := hexutil.Decode(dataHex)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	value := new(big.Int)
+	if valueStr != nil && *valueStr != "" {
+		if _, ok := value.SetString(*valueStr, 10); !ok {
+			return "", fmt.Errorf("invalid value %q", *valueStr)
+		}
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	var gasLimit uint64
+	if gasLimitStr != nil && *gasLimitStr != "" {
+		g, ok := new(big.Int).SetString(*gasLimitStr, 10)
+		if !ok {
+			return "", fmt.Errorf("invalid gas limit %q", *gasLimitStr)
+		}
+		gasLimit = g.Uint64()
+	} else {
+		gasLimit, err = rpc.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Value: value, Data: data})
+		if err != nil {
+			return "", fmt.Errorf("estimating gas: %w", err)
+		}
+		// Pad the estimate to avoid out-of-gas on state changes between estimate and inclusion
+		gasLimit = gasLimit * 12 / 10
+	}
+
+	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending tx: %w", err)
+	}
+
+	log.Printf("Rango tx sent: %s", signedTx.Hash().Hex())
+
+	if !wait {
+		// Don't wait for mining - return immediately and let status polling handle confirmation
+		return signedTx.Hash().Hex(), nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return "", fmt.Errorf("waiting for tx: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("tx %s failed", signedTx.Hash().Hex())
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// formatUnits renders an integer amount with the given number of decimals.
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+	s := amount.String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	return s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
@@ -0,0 +1,170 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const baseURL = "https://api.relay.link"
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a Relay API client. The API key is optional; Relay
+// serves unauthenticated requests at a lower rate limit.
+func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// QuoteRequest is the body for POST /quote.
+type QuoteRequest struct {
+	User                string `json:"user"`
+	Recipient           string `json:"recipient"`
+	OriginChainID       int64  `json:"originChainId"`
+	DestinationChainID  int64  `json:"destinationChainId"`
+	OriginCurrency      string `json:"originCurrency"`
+	DestinationCurrency string `json:"destinationCurrency"`
+	Amount              string `json:"amount"`
+	TradeType           string `json:"tradeType"`
+}
+
+// TxData is an unsigned transaction returned as part of a quote step.
+type TxData struct {
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	Data                 string `json:"data"`
+	Value                string `json:"value"`
+	ChainID              int64  `json:"chainId"`
+	Gas                  string `json:"gas,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// StepItem is a single action within a step.
+type StepItem struct {
+	Status string `json:"status"`
+	Data   TxData `json:"data"`
+}
+
+// Step is one stage of a Relay execution (e.g. "approve", "deposit").
+type Step struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"` // "transaction" or "signature"
+	RequestID string     `json:"requestId"`
+	Items     []StepItem `json:"items"`
+}
+
+// CurrencyAmount describes an input or output amount in a quote.
+type CurrencyAmount struct {
+	Currency struct {
+		ChainID  int64  `json:"chainId"`
+		Address  string `json:"address"`
+		Symbol   string `json:"symbol"`
+		Decimals int    `json:"decimals"`
+	} `json:"currency"`
+	Amount          string `json:"amount"`
+	AmountFormatted string `json:"amountFormatted"`
+	AmountUsd       string `json:"amountUsd"`
+}
+
+// QuoteResponse is the response from POST /quote.
+type QuoteResponse struct {
+	Steps   []Step `json:"steps"`
+	Details struct {
+		CurrencyIn   CurrencyAmount `json:"currencyIn"`
+		CurrencyOut  CurrencyAmount `json:"currencyOut"`
+		TimeEstimate int            `json:"timeEstimate"`
+	} `json:"details"`
+}
+
+// StatusResponse is the response from GET /intents/status/v2.
+type StatusResponse struct {
+	Status   string   `json:"status"` // waiting, pending, delayed, success, failure, refund
+	TxHashes []string `json:"txHashes"`
+}
+
+// GetQuote requests an executable quote for a cross-chain transfer.
+func (c *Client) GetQuote(ctx context.Context, reqBody QuoteRequest) (*QuoteResponse, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/quote", strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relay quote: %s: %s", resp.Status, body)
+	}
+
+	var result QuoteResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing quote response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetStatus retrieves the status of a Relay request by its request ID.
+func (c *Client) GetStatus(ctx context.Context, requestID string) (*StatusResponse, error) {
+	u := fmt.Sprintf("%s/intents/status/v2?requestId=%s", baseURL, url.QueryEscape(requestID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relay status: %s: %s", resp.Status, body)
+	}
+
+	var status StatusResponse
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("parsing status response: %w", err)
+	}
+
+	return &status, nil
+}
+
+func (c *Client) setAuth(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("x-api-key", c.apiKey)
+	}
+}
@@ -0,0 +1,81 @@
+package relay
+
+import (
+	"strings"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// nativeCurrency is the address Relay uses for a chain's native gas token.
+const nativeCurrency = "0x0000000000000000000000000000000000000000"
+
+// destinationChains maps our chain notation to the EVM chain ID and native symbol
+// for every destination Relay can deliver to.
+var destinationChains = map[string]struct {
+	ChainID      int64
+	NativeSymbol string
+}{
+	"ETH":     {ChainID: 1, NativeSymbol: "ETH"},
+	"ARB":     {ChainID: 42161, NativeSymbol: "ETH"},
+	"OP":      {ChainID: 10, NativeSymbol: "ETH"},
+	"BASE":    {ChainID: 8453, NativeSymbol: "ETH"},
+	"AVAX":    {ChainID: 43114, NativeSymbol: "AVAX"},
+	"POLYGON": {ChainID: 137, NativeSymbol: "POL"},
+	"BSC":     {ChainID: 56, NativeSymbol: "BNB"},
+	"LINEA":   {ChainID: 59144, NativeSymbol: "ETH"},
+	"SCROLL":  {ChainID: 534352, NativeSymbol: "ETH"},
+	"ZKSYNC":  {ChainID: 324, NativeSymbol: "ETH"},
+	"BLAST":   {ChainID: 81457, NativeSymbol: "ETH"},
+}
+
+// sourceChains maps our RPC chain name to the Relay chain ID for that chain.
+var sourceChains = map[string]int64{
+	"avalanche": 43114,
+	"base":      8453,
+	"ethereum":  1,
+	"optimism":  10,
+	"polygon":   137,
+}
+
+// DestinationChainID returns the EVM chain ID for a destination chain in our notation.
+func DestinationChainID(chain string) (int64, bool) {
+	dc, ok := destinationChains[strings.ToUpper(chain)]
+	return dc.ChainID, ok
+}
+
+// AssetToCurrency returns the Relay chain ID and currency address for a target asset.
+// Native assets map to the zero address; ERC20s require a contract address, either on
+// the asset itself or from the resolver.
+func AssetToCurrency(asset swaps.Asset) (int64, string, bool) {
+	dc, ok := destinationChains[asset.Chain]
+	if !ok {
+		return 0, "", false
+	}
+
+	if asset.ContractAddress != "" {
+		return dc.ChainID, asset.ContractAddress, true
+	}
+	if asset.Hints != nil && asset.Hints.RelayCurrency != "" {
+		return dc.ChainID, asset.Hints.RelayCurrency, true
+	}
+	if asset.Symbol == dc.NativeSymbol {
+		return dc.ChainID, nativeCurrency, true
+	}
+
+	return 0, "", false
+}
+
+// SourceChainID returns the Relay chain ID for a source RPC chain.
+func SourceChainID(chain string) (int64, bool) {
+	id, ok := sourceChains[chain]
+	return id, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that Relay can source USDC from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceChains))
+	for k := range sourceChains {
+		chains = append(chains, k)
+	}
+	return chains
+}
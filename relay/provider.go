@@ -0,0 +1,338 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(apiKey, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "relay"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, _, ok := AssetToCurrency(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	destChainID, destCurrency, ok := AssetToCurrency(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("relay: unsupported target asset %s", toAsset)
+	}
+	if !common.IsHexAddress(destination) {
+		return nil, fmt.Errorf("relay: destination %q is not an EVM address", destination)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		originChainID, ok := SourceChainID(chain)
+		if !ok {
+			continue
+		}
+
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Pick the first funding token with enough balance on this chain
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, nil)
+		if err != nil {
+			log.Printf("relay: skipping %s: %v", chain, err)
+			continue
+		}
+
+		resp, err := p.client.GetQuote(ctx, QuoteRequest{
+			User:                sender.Hex(),
+			Recipient:           destination,
+			OriginChainID:       originChainID,
+			DestinationChainID:  destChainID,
+			OriginCurrency:      token.Address.Hex(),
+			DestinationCurrency: destCurrency,
+			Amount:              inputAmount.String(),
+			TradeType:           "EXACT_INPUT",
+		})
+		if err != nil {
+			log.Printf("relay quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+
+		requestID := ""
+		for _, step := range resp.Steps {
+			if step.RequestID != "" {
+				requestID = step.RequestID
+				break
+			}
+		}
+		if len(resp.Steps) == 0 || requestID == "" {
+			log.Printf("relay quote for %s via %s returned no executable steps", toAsset, chain)
+			continue
+		}
+
+		expectedOut := parseToBigInt(resp.Details.CurrencyOut.AmountFormatted)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "relay",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    resp.Details.CurrencyOut.AmountFormatted,
+			ExpectedOutputRaw: expectedOut,
+			ExtraData: map[string]interface{}{
+				"relay_request_id":    requestID,
+				"relay_steps":         resp.Steps,
+				"relay_time_estimate": resp.Details.TimeEstimate,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("relay: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	requestID, _ := quote.ExtraData["relay_request_id"].(string)
+	steps, _ := quote.ExtraData["relay_steps"].([]Step)
+	if requestID == "" || len(steps) == 0 {
+		return swaps.ExecuteResult{}, fmt.Errorf("relay: missing steps in quote ExtraData")
+	}
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	// Flatten the steps into the ordered list of transactions to send.
+	var txs []TxData
+	for _, step := range steps {
+		if step.Kind != "transaction" {
+			return swaps.ExecuteResult{}, fmt.Errorf("relay: unsupported step kind %q (%s)", step.Kind, step.ID)
+		}
+		for _, item := range step.Items {
+			if item.Status == "complete" {
+				continue
+			}
+			txs = append(txs, item.Data)
+		}
+	}
+	if len(txs) == 0 {
+		return swaps.ExecuteResult{}, fmt.Errorf("relay: no transactions to send")
+	}
+
+	var txHash string
+	for i, txData := range txs {
+		if txData.ChainID != chainID.Int64() {
+			return swaps.ExecuteResult{}, fmt.Errorf("relay: step transaction targets chain %d, expected %s", txData.ChainID, chainID)
+		}
+
+		// Every transaction but the last (e.g. approvals) must be mined before the next is sent.
+		last := i == len(txs)-1
+		hash, err := sendStepTx(ctx, rpc, chainID, signer, fromAddr, txData, !last)
+		if err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("relay step %d: %w", i+1, err)
+		}
+		txHash = hash
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: requestID,
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	if externalID == "" {
+		return "pending", nil
+	}
+
+	status, err := p.client.GetStatus(ctx, externalID)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, status.Status)
+
+	switch status.Status {
+	case "success":
+		return "completed", nil
+	case "refund":
+		return "refunded", nil
+	case "failure":
+		return "failed", nil
+	default:
+		// waiting, pending, delayed
+		return "pending", nil
+	}
+}
+
+// Result returns the fill transaction on the destination chain.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	status, err := p.client.GetStatus(ctx, externalID)
+	if err != nil {
+		return swaps.SwapResult{}, err
+	}
+	var result swaps.SwapResult
+	if len(status.TxHashes) > 0 {
+		result.DestTxHash = status.TxHashes[len(status.TxHashes)-1]
+	}
+	return result, nil
+}
+
+// sendStepTx signs and broadcasts a transaction returned by the Relay quote.
+// If wait is true, it blocks until the transaction is mined.
+func sendStepTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, txData TxData, wait bool) (string, error) {
+	to := common.HexToAddress(txData.To)
+
+	data, err := hexutil.Decode(txData.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	value := new(big.Int)
+	if txData.Value != "" {
+		if _, ok := value.SetString(txData.Value, 10); !ok {
+			return "", fmt.Errorf("invalid value %q", txData.Value)
+		}
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	var gasLimit uint64
+	if txData.Gas != "" {
+		g, ok := new(big.Int).SetString(txData.Gas, 10)
+		if !ok {
+			return "", fmt.Errorf("invalid gas %q", txData.Gas)
+		}
+		gasLimit = g.Uint64()
+	} else {
+		gasLimit, err = rpc.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Value: value, Data: data})
+		if err != nil {
+			return "", fmt.Errorf("estimating gas: %w", err)
+		}
+		// Pad the estimate to avoid out-of-gas on state changes between estimate and inclusion
+		gasLimit = gasLimit * 12 / 10
+	}
+
+	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending tx: %w", err)
+	}
+
+	log.Printf("Relay tx sent: %s", signedTx.Hash().Hex())
+
+	if !wait {
+		// Don't wait for mining - return immediately and let status polling handle confirmation
+		return signedTx.Hash().Hex(), nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return "", fmt.Errorf("waiting for tx: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("tx %s failed", signedTx.Hash().Hex())
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
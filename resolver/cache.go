@@ -1,20 +1,41 @@
 package resolver
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/RaghavSood/fundbot/db"
 )
 
+// storeTimeout bounds each read or write of a persisted cache entry, so a
+// slow database can't hold up a lookup for long.
+const storeTimeout = 5 * time.Second
+
 type cacheEntry[T any] struct {
 	value     T
 	fetchedAt time.Time
 }
 
-// Cache is a simple in-memory TTL cache keyed by string.
+// Cache is a simple in-memory TTL cache keyed by string. With Persist, it
+// also keeps entries in the database, so they survive restarts and are
+// shared by instances using the same database.
 type Cache[T any] struct {
 	mu      sync.RWMutex
 	entries map[string]cacheEntry[T]
 	ttl     time.Duration
+
+	// Database and key prefix set by Persist
+	store *db.Store
+	name  string
+
+	// Lookups since startup, for Stats
+	hits, storeHits, misses, errors atomic.Int64
 }
 
 func NewCache[T any](ttl time.Duration) *Cache[T] {
@@ -24,11 +45,21 @@ func NewCache[T any](ttl time.Duration) *Cache[T] {
 	}
 }
 
+// Persist stores entries in the cache_entries table as JSON, keyed by
+// name and the entry's key, and looks there before fetching. Database
+// errors are logged and fall back to fetching.
+func (c *Cache[T]) Persist(store *db.Store, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store, c.name = store, name
+}
+
 // GetOrFetch returns a cached value or calls fetch to populate it.
 func (c *Cache[T]) GetOrFetch(key string, fetch func() (T, error)) (T, error) {
 	c.mu.RLock()
 	if e, ok := c.entries[key]; ok && time.Since(e.fetchedAt) < c.ttl {
 		c.mu.RUnlock()
+		c.hits.Add(1)
 		return e.value, nil
 	}
 	c.mu.RUnlock()
@@ -38,15 +69,86 @@ func (c *Cache[T]) GetOrFetch(key string, fetch func() (T, error)) (T, error) {
 
 	// Double-check after acquiring write lock.
 	if e, ok := c.entries[key]; ok && time.Since(e.fetchedAt) < c.ttl {
+		c.hits.Add(1)
 		return e.value, nil
 	}
 
+	if e, ok := c.load(key); ok {
+		c.entries[key] = e
+		c.storeHits.Add(1)
+		return e.value, nil
+	}
+
+	c.misses.Add(1)
 	val, err := fetch()
 	if err != nil {
+		c.errors.Add(1)
 		var zero T
 		return zero, err
 	}
 
 	c.entries[key] = cacheEntry[T]{value: val, fetchedAt: time.Now()}
+	c.save(key, val)
 	return val, nil
 }
+
+// Stats returns the cache's lookups since startup under name.
+func (c *Cache[T]) Stats(name string) CacheStats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+	return CacheStats{
+		Name:      name,
+		Entries:   entries,
+		Hits:      c.hits.Load(),
+		StoreHits: c.storeHits.Load(),
+		Misses:    c.misses.Load(),
+		Errors:    c.errors.Load(),
+	}
+}
+
+// load reads an unexpired entry from the database. Its age is taken from
+// the expiry, so it leaves memory when it would have.
+func (c *Cache[T]) load(key string) (cacheEntry[T], bool) {
+	if c.store == nil {
+		return cacheEntry[T]{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	row, err := c.store.GetCacheEntry(ctx, db.GetCacheEntryParams{CacheKey: c.name + ":" + key, Now: time.Now().UTC()})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("resolver: error reading cached %s %q: %v", c.name, key, err)
+		}
+		return cacheEntry[T]{}, false
+	}
+	var val T
+	if err := json.Unmarshal([]byte(row.Value), &val); err != nil {
+		log.Printf("resolver: error decoding cached %s %q: %v", c.name, key, err)
+		return cacheEntry[T]{}, false
+	}
+	return cacheEntry[T]{value: val, fetchedAt: row.ExpiresAt.Add(-c.ttl)}, true
+}
+
+// save writes a fetched value to the database, expiring after the TTL.
+func (c *Cache[T]) save(key string, val T) {
+	if c.store == nil {
+		return
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		log.Printf("resolver: error encoding %s %q for the cache: %v", c.name, key, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := c.store.SetCacheEntry(ctx, db.SetCacheEntryParams{
+		CacheKey:  c.name + ":" + key,
+		Value:     string(data),
+		ExpiresAt: time.Now().UTC().Add(c.ttl),
+	}); err != nil {
+		log.Printf("resolver: error caching %s %q: %v", c.name, key, err)
+	}
+}
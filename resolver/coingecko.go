@@ -1,16 +1,38 @@
 package resolver
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-const coingeckoBase = "https://api.coingecko.com/api/v3"
+const (
+	coingeckoBase    = "https://api.coingecko.com/api/v3"
+	coingeckoProBase = "https://pro-api.coingecko.com/api/v3"
+)
+
+// A key CoinGecko rate limits without a Retry-After rests for cgMinBackoff,
+// doubling each time in a row up to cgMaxBackoff. When every key is
+// resting, a request waits up to cgMaxWait for one before failing.
+const (
+	cgMinBackoff = 15 * time.Second
+	cgMaxBackoff = 5 * time.Minute
+	cgMaxWait    = 5 * time.Second
+)
+
+// errNotListed is returned for token contracts CoinGecko doesn't index.
+var errNotListed = errors.New("not listed on CoinGecko")
 
 // platformToChain maps CoinGecko platform names to Thorchain-style chain identifiers.
 var platformToChain = map[string]string{
@@ -21,6 +43,7 @@ var platformToChain = map[string]string{
 	"polygon-pos":          "POLYGON",
 	"solana":               "SOL",
 	"arbitrum-one":         "ARB",
+	"optimistic-ethereum":  "OP",
 	"tron":                 "TRON",
 	"bitcoin":              "BTC",
 	"litecoin":             "LTC",
@@ -47,31 +70,91 @@ type cgSearchResponse struct {
 }
 
 type coingeckoClient struct {
-	apiKey      string
+	baseURL  string
+	keyParam string // query parameter carrying the key
+
+	// Keys are used in turn, skipping those resting after a 429
+	mu   sync.Mutex
+	keys []*cgKey
+	next int
+
 	httpClient  *http.Client
 	searchCache *Cache[[]cgSearchResult]
-	coinCache   *Cache[map[string]string] // coinID → {platform: contractAddr}
+	coinCache   *Cache[cgCoin] // by coin ID
+	// Coins by "<platform>:<contract address>", lower case
+	contractCache *Cache[cgSearchResult]
 }
 
-func newCoingeckoClient(apiKey string) *coingeckoClient {
+// cgCoin is what the resolver uses of a CoinGecko coin: its contracts by
+// chain and the market data token screening checks. Zero market data means
+// CoinGecko has none.
+type cgCoin struct {
+	Platforms    map[string]string `json:"platforms"` // chain (e.g. "ETH") → contract address
+	MarketCapUSD float64           `json:"market_cap_usd"`
+	VolumeUSD    float64           `json:"volume_usd"` // last 24 hours
+}
+
+// cgKey is a CoinGecko API key and its rate limit state.
+type cgKey struct {
+	key          string
+	restingUntil time.Time
+	backoff      time.Duration // last rest without a Retry-After; reset on success
+}
+
+func newCoingeckoClient(apiKeys []string) *coingeckoClient {
+	keys := make([]*cgKey, 0, len(apiKeys))
+	for _, k := range apiKeys {
+		keys = append(keys, &cgKey{key: k})
+	}
 	return &coingeckoClient{
-		apiKey: apiKey,
+		baseURL:  coingeckoBase,
+		keyParam: "x_cg_demo_api_key",
+		keys:     keys,
 		httpClient: &http.Client{
 			Timeout: 15 * time.Second,
 		},
 		searchCache: NewCache[[]cgSearchResult](1 * time.Hour),
-		coinCache:   NewCache[map[string]string](1 * time.Hour),
+		coinCache:   NewCache[cgCoin](1 * time.Hour),
+		// A contract's coin doesn't change
+		contractCache: NewCache[cgSearchResult](24 * time.Hour),
 	}
 }
 
-// search finds coins matching the given symbol, returning results sorted by market cap.
-func (c *coingeckoClient) search(ctx context.Context, symbol string) ([]cgSearchResult, error) {
-	key := strings.ToLower(symbol)
-	return c.searchCache.GetOrFetch(key, func() ([]cgSearchResult, error) {
-		u := fmt.Sprintf("%s/search?query=%s&x_cg_demo_api_key=%s",
-			coingeckoBase, url.QueryEscape(symbol), url.QueryEscape(c.apiKey))
+// setPro sends the keys to the Pro API instead of the demo one.
+func (c *coingeckoClient) setPro(pro bool) {
+	if pro {
+		c.baseURL, c.keyParam = coingeckoProBase, "x_cg_pro_api_key"
+	} else {
+		c.baseURL, c.keyParam = coingeckoBase, "x_cg_demo_api_key"
+	}
+}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+// get requests path with the query and the next key that isn't resting,
+// returning the response for the caller to check and close. A 429 rests
+// the key, for the Retry-After if given, and the request goes again with
+// another key.
+func (c *coingeckoClient) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	for attempt := 0; attempt <= len(c.keys); attempt++ {
+		k, wait := c.pickKey()
+		if k == nil {
+			if wait > cgMaxWait {
+				return nil, fmt.Errorf("coingecko: rate limited on every key, retry in %s", wait.Round(time.Second))
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			if k, _ = c.pickKey(); k == nil {
+				continue
+			}
+		}
+
+		query.Set(c.keyParam, k.key)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -80,6 +163,85 @@ func (c *coingeckoClient) search(ctx context.Context, symbol string) ([]cgSearch
 		if err != nil {
 			return nil, err
 		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			c.rest(k, resp.Header.Get("Retry-After"))
+			continue
+		}
+		c.mu.Lock()
+		k.backoff = 0
+		c.mu.Unlock()
+		return resp, nil
+	}
+	return nil, fmt.Errorf("coingecko: rate limited on every key")
+}
+
+// pickKey returns the next key that isn't resting, or nil and how long
+// until one is.
+func (c *coingeckoClient) pickKey() (*cgKey, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.keys) == 0 {
+		return nil, cgMaxBackoff
+	}
+	now := time.Now()
+	wait := time.Duration(math.MaxInt64)
+	for i := range c.keys {
+		k := c.keys[(c.next+i)%len(c.keys)]
+		if !now.Before(k.restingUntil) {
+			c.next = (c.next + i + 1) % len(c.keys)
+			return k, 0
+		}
+		wait = min(wait, k.restingUntil.Sub(now))
+	}
+	return nil, wait
+}
+
+// rest takes a rate limited key out of turn, for retryAfter seconds if
+// CoinGecko said, or else for its next backoff.
+func (c *coingeckoClient) rest(k *cgKey, retryAfter string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
+		d = time.Duration(secs) * time.Second
+	} else {
+		k.backoff = min(max(2*k.backoff, cgMinBackoff), cgMaxBackoff)
+		d = k.backoff
+	}
+	k.restingUntil = time.Now().Add(d)
+
+	for i := range c.keys {
+		if c.keys[i] == k {
+			log.Printf("resolver: CoinGecko key %d of %d rate limited, resting it for %s", i+1, len(c.keys), d)
+		}
+	}
+}
+
+// keyStatus returns how many keys there are, how many are resting and
+// whether they go to the Pro API.
+func (c *coingeckoClient) keyStatus() (keys, resting int, pro bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for _, k := range c.keys {
+		if now.Before(k.restingUntil) {
+			resting++
+		}
+	}
+	return len(c.keys), resting, c.baseURL == coingeckoProBase
+}
+
+// search finds coins matching the given symbol, returning results sorted by market cap.
+func (c *coingeckoClient) search(ctx context.Context, symbol string) ([]cgSearchResult, error) {
+	key := strings.ToLower(symbol)
+	return c.searchCache.GetOrFetch(key, func() ([]cgSearchResult, error) {
+		resp, err := c.get(ctx, "/search", url.Values{"query": {symbol}})
+		if err != nil {
+			return nil, err
+		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
@@ -97,61 +259,123 @@ func (c *coingeckoClient) search(ctx context.Context, symbol string) ([]cgSearch
 
 // bestMatch picks the coin with the best (lowest) market cap rank whose symbol matches.
 func (c *coingeckoClient) bestMatch(coins []cgSearchResult, symbol string) *cgSearchResult {
-	var best *cgSearchResult
-	for i := range coins {
-		coin := &coins[i]
-		if !strings.EqualFold(coin.Symbol, symbol) {
-			continue
-		}
-		if coin.MarketCapRank == nil || *coin.MarketCapRank == 0 {
-			if best == nil {
-				best = coin
-			}
-			continue
-		}
-		if best == nil || best.MarketCapRank == nil || *coin.MarketCapRank < *best.MarketCapRank {
-			best = coin
+	if matches := c.matches(coins, symbol); len(matches) > 0 {
+		return &matches[0]
+	}
+	return nil
+}
+
+// matches returns the coins whose symbol matches, best (lowest) market cap
+// rank first and unranked coins last in search order.
+func (c *coingeckoClient) matches(coins []cgSearchResult, symbol string) []cgSearchResult {
+	var matches []cgSearchResult
+	for _, coin := range coins {
+		if strings.EqualFold(coin.Symbol, symbol) {
+			matches = append(matches, coin)
 		}
 	}
-	return best
+	slices.SortStableFunc(matches, func(a, b cgSearchResult) int {
+		return cmp.Compare(rankOrder(a), rankOrder(b))
+	})
+	return matches
 }
 
-// getPlatforms returns a map of chain ID → contract address for the given CoinGecko coin ID.
-func (c *coingeckoClient) getPlatforms(ctx context.Context, coinID string) (map[string]string, error) {
-	return c.coinCache.GetOrFetch(coinID, func() (map[string]string, error) {
-		u := fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&market_data=false&community_data=false&developer_data=false&x_cg_demo_api_key=%s",
-			coingeckoBase, url.PathEscape(coinID), url.QueryEscape(c.apiKey))
+// rankOrder sorts unranked coins after every ranked one.
+func rankOrder(coin cgSearchResult) int {
+	if coin.MarketCapRank == nil || *coin.MarketCapRank == 0 {
+		return math.MaxInt
+	}
+	return *coin.MarketCapRank
+}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+// chainPlatform returns the CoinGecko platform of a chain identifier, the
+// reverse of platformToChain.
+func chainPlatform(chain string) (string, bool) {
+	for platform, c := range platformToChain {
+		if strings.EqualFold(c, chain) {
+			return platform, true
+		}
+	}
+	return "", false
+}
+
+// coinByContract returns the CoinGecko coin of a token contract on a chain.
+func (c *coingeckoClient) coinByContract(ctx context.Context, chain, contract string) (cgSearchResult, error) {
+	platform, ok := chainPlatform(chain)
+	if !ok {
+		return cgSearchResult{}, fmt.Errorf("CoinGecko has no platform for chain %s", chain)
+	}
+	key := platform + ":" + strings.ToLower(contract)
+	return c.contractCache.GetOrFetch(key, func() (cgSearchResult, error) {
+		path := fmt.Sprintf("/coins/%s/contract/%s", url.PathEscape(platform), url.PathEscape(strings.ToLower(contract)))
+		resp, err := c.get(ctx, path, nil)
 		if err != nil {
-			return nil, err
+			return cgSearchResult{}, err
 		}
+		defer resp.Body.Close()
 
-		resp, err := c.httpClient.Do(req)
+		if resp.StatusCode == http.StatusNotFound {
+			return cgSearchResult{}, fmt.Errorf("%w: contract %s on %s", errNotListed, contract, chain)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return cgSearchResult{}, fmt.Errorf("coingecko contract: HTTP %d", resp.StatusCode)
+		}
+
+		var result cgSearchResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return cgSearchResult{}, fmt.Errorf("coingecko contract decode: %w", err)
+		}
+		if result.ID == "" || result.Symbol == "" {
+			return cgSearchResult{}, fmt.Errorf("%w: contract %s on %s", errNotListed, contract, chain)
+		}
+
+		return result, nil
+	})
+}
+
+// getCoin returns the contracts, by chain, and market data of the given
+// CoinGecko coin ID.
+func (c *coingeckoClient) getCoin(ctx context.Context, coinID string) (cgCoin, error) {
+	return c.coinCache.GetOrFetch(coinID, func() (cgCoin, error) {
+		resp, err := c.get(ctx, "/coins/"+url.PathEscape(coinID), url.Values{
+			"localization":   {"false"},
+			"tickers":        {"false"},
+			"market_data":    {"true"},
+			"community_data": {"false"},
+			"developer_data": {"false"},
+		})
 		if err != nil {
-			return nil, err
+			return cgCoin{}, err
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("coingecko coin: HTTP %d", resp.StatusCode)
+			return cgCoin{}, fmt.Errorf("coingecko coin: HTTP %d", resp.StatusCode)
 		}
 
 		var raw struct {
-			Platforms map[string]string `json:"platforms"`
+			Platforms  map[string]string `json:"platforms"`
+			MarketData struct {
+				MarketCap   map[string]float64 `json:"market_cap"`
+				TotalVolume map[string]float64 `json:"total_volume"`
+			} `json:"market_data"`
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
-			return nil, fmt.Errorf("coingecko coin decode: %w", err)
+			return cgCoin{}, fmt.Errorf("coingecko coin decode: %w", err)
 		}
 
 		// Convert platform names to chain IDs, skip empty entries.
-		result := make(map[string]string)
+		result := cgCoin{
+			Platforms:    make(map[string]string),
+			MarketCapUSD: raw.MarketData.MarketCap["usd"],
+			VolumeUSD:    raw.MarketData.TotalVolume["usd"],
+		}
 		for platform, addr := range raw.Platforms {
 			if platform == "" || addr == "" {
 				continue
 			}
 			if chain, ok := platformToChain[platform]; ok {
-				result[chain] = addr
+				result.Platforms[chain] = addr
 			}
 		}
 
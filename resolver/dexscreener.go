@@ -0,0 +1,211 @@
+package resolver
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const dexscreenerBase = "https://api.dexscreener.com"
+
+// dexChainToChain maps DexScreener chain IDs to Thorchain-style chain identifiers.
+var dexChainToChain = map[string]string{
+	"ethereum":  "ETH",
+	"base":      "BASE",
+	"avalanche": "AVAX",
+	"bsc":       "BSC",
+	"polygon":   "POLYGON",
+	"arbitrum":  "ARB",
+	"optimism":  "OP",
+	"solana":    "SOL",
+	"tron":      "TRON",
+	"sui":       "SUI",
+	"ton":       "TON",
+}
+
+type dsPairToken struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Symbol  string `json:"symbol"`
+}
+
+type dsPair struct {
+	ChainID    string      `json:"chainId"`
+	BaseToken  dsPairToken `json:"baseToken"`
+	QuoteToken dsPairToken `json:"quoteToken"`
+	Liquidity  struct {
+		USD float64 `json:"usd"`
+	} `json:"liquidity"`
+	Volume struct {
+		H24 float64 `json:"h24"`
+	} `json:"volume"`
+	MarketCap float64 `json:"marketCap"`
+}
+
+// dsToken is a token DexScreener has trading pairs for, with the pairs'
+// liquidity and 24h volume summed.
+type dsToken struct {
+	Chain        string  `json:"chain"` // e.g. "BASE"
+	Address      string  `json:"address"`
+	Name         string  `json:"name"`
+	Symbol       string  `json:"symbol"`
+	LiquidityUSD float64 `json:"liquidity_usd"`
+	VolumeUSD    float64 `json:"volume_usd"`
+	MarketCapUSD float64 `json:"market_cap_usd"` // zero when unknown
+}
+
+// dexscreenerClient looks up tokens on DEXes, for long-tail tokens
+// CoinGecko doesn't index. The API needs no key.
+type dexscreenerClient struct {
+	httpClient  *http.Client
+	searchCache *Cache[[]dsToken] // by lower-case symbol
+	// Tokens by "<chain>:<contract address>", lower case
+	tokenCache *Cache[dsToken]
+}
+
+func newDexscreenerClient() *dexscreenerClient {
+	return &dexscreenerClient{
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		// Liquidity moves quickly on small tokens
+		searchCache: NewCache[[]dsToken](10 * time.Minute),
+		tokenCache:  NewCache[dsToken](10 * time.Minute),
+	}
+}
+
+// search returns the tokens with the given symbol that have liquidity, on
+// any chain we know, most liquid first.
+func (c *dexscreenerClient) search(ctx context.Context, symbol string) ([]dsToken, error) {
+	key := strings.ToLower(symbol)
+	return c.searchCache.GetOrFetch(key, func() ([]dsToken, error) {
+		var result struct {
+			Pairs []dsPair `json:"pairs"`
+		}
+		u := fmt.Sprintf("%s/latest/dex/search?q=%s", dexscreenerBase, url.QueryEscape(symbol))
+		if err := c.get(ctx, u, &result); err != nil {
+			return nil, fmt.Errorf("dexscreener search: %w", err)
+		}
+
+		tokens := aggregatePairs(result.Pairs, func(t dsPairToken) bool {
+			return strings.EqualFold(t.Symbol, symbol)
+		})
+		return slices.DeleteFunc(tokens, func(t dsToken) bool { return t.LiquidityUSD <= 0 }), nil
+	})
+}
+
+// onChain returns the tokens with the given symbol on chain, most liquid first.
+func (c *dexscreenerClient) onChain(ctx context.Context, chain, symbol string) ([]dsToken, error) {
+	tokens, err := c.search(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	var matches []dsToken
+	for _, t := range tokens {
+		if strings.EqualFold(t.Chain, chain) {
+			matches = append(matches, t)
+		}
+	}
+	return matches, nil
+}
+
+// token returns the token of a contract on a chain.
+func (c *dexscreenerClient) token(ctx context.Context, chain, contract string) (dsToken, error) {
+	dexChain, ok := chainDexChain(chain)
+	if !ok {
+		return dsToken{}, fmt.Errorf("DexScreener has no chain %s", chain)
+	}
+	key := strings.ToLower(chain + ":" + contract)
+	return c.tokenCache.GetOrFetch(key, func() (dsToken, error) {
+		var pairs []dsPair
+		u := fmt.Sprintf("%s/tokens/v1/%s/%s", dexscreenerBase, url.PathEscape(dexChain), url.PathEscape(contract))
+		if err := c.get(ctx, u, &pairs); err != nil {
+			return dsToken{}, fmt.Errorf("dexscreener token: %w", err)
+		}
+
+		tokens := aggregatePairs(pairs, func(t dsPairToken) bool {
+			return strings.EqualFold(t.Address, contract)
+		})
+		if len(tokens) == 0 || tokens[0].Symbol == "" {
+			return dsToken{}, fmt.Errorf("DexScreener has no pairs for contract %s on %s", contract, chain)
+		}
+		return tokens[0], nil
+	})
+}
+
+func (c *dexscreenerClient) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// aggregatePairs sums the pairs of each token keep accepts, on either side
+// of the pair, into one entry per chain and contract, most liquid first.
+// Pairs on chains we don't know are skipped.
+func aggregatePairs(pairs []dsPair, keep func(dsPairToken) bool) []dsToken {
+	byKey := make(map[string]*dsToken)
+	for _, p := range pairs {
+		chain, ok := dexChainToChain[p.ChainID]
+		if !ok {
+			continue
+		}
+		for _, t := range []dsPairToken{p.BaseToken, p.QuoteToken} {
+			if t.Address == "" || !keep(t) {
+				continue
+			}
+			key := chain + ":" + strings.ToLower(t.Address)
+			token, ok := byKey[key]
+			if !ok {
+				token = &dsToken{Chain: chain, Address: t.Address, Name: t.Name, Symbol: strings.ToUpper(t.Symbol)}
+				byKey[key] = token
+			}
+			token.LiquidityUSD += p.Liquidity.USD
+			token.VolumeUSD += p.Volume.H24
+			// Market cap is only given for the base token
+			if t == p.BaseToken {
+				token.MarketCapUSD = max(token.MarketCapUSD, p.MarketCap)
+			}
+		}
+	}
+
+	tokens := make([]dsToken, 0, len(byKey))
+	for _, t := range byKey {
+		tokens = append(tokens, *t)
+	}
+	slices.SortFunc(tokens, func(a, b dsToken) int {
+		return cmp.Compare(b.LiquidityUSD, a.LiquidityUSD)
+	})
+	return tokens
+}
+
+// chainDexChain returns the DexScreener chain ID of a chain identifier, the
+// reverse of dexChainToChain.
+func chainDexChain(chain string) (string, bool) {
+	for dexChain, c := range dexChainToChain {
+		if strings.EqualFold(c, chain) {
+			return dexChain, true
+		}
+	}
+	return "", false
+}
@@ -5,11 +5,37 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/RaghavSood/fundbot/houdini"
 	"github.com/RaghavSood/fundbot/simpleswap"
+	"github.com/RaghavSood/fundbot/stealthex"
 )
 
+// refreshState records a matcher's refreshes, for Resolver.Stats.
+type refreshState struct {
+	mu          sync.Mutex
+	refreshedAt time.Time // last successful refresh
+	currencies  int
+	lastErr     string // of the last refresh, if it failed
+}
+
+func (s *refreshState) record(currencies int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastErr = err.Error()
+		return
+	}
+	s.refreshedAt, s.currencies, s.lastErr = time.Now(), currencies, ""
+}
+
+func (s *refreshState) status(provider string) MatcherStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return MatcherStatus{Provider: provider, Currencies: s.currencies, RefreshedAt: s.refreshedAt, Error: s.lastErr}
+}
+
 // simpleswapMatcher provides dynamic lookup of SimpleSwap currencies.
 type simpleswapMatcher struct {
 	client *simpleswap.Client
@@ -19,6 +45,8 @@ type simpleswapMatcher struct {
 	byContract map[string]string
 	// bySymbol maps lowercase "network:symbol" to currency symbol
 	bySymbol map[string]string
+
+	state refreshState
 }
 
 func newSimpleswapMatcher(client *simpleswap.Client) *simpleswapMatcher {
@@ -37,6 +65,7 @@ func (m *simpleswapMatcher) refresh(ctx context.Context) error {
 
 	currencies, err := m.client.GetAllCurrencies(ctx)
 	if err != nil {
+		m.state.record(0, err)
 		return err
 	}
 
@@ -62,6 +91,7 @@ func (m *simpleswapMatcher) refresh(ctx context.Context) error {
 	m.byContract = byContract
 	m.bySymbol = bySymbol
 	m.mu.Unlock()
+	m.state.record(len(currencies), nil)
 
 	log.Printf("resolver: loaded %d SimpleSwap currencies", len(currencies))
 	return nil
@@ -104,6 +134,8 @@ type houdiniMatcher struct {
 	byContract map[string]string
 	// bySymbol maps lowercase "network:symbol" to currency ID
 	bySymbol map[string]string
+
+	state refreshState
 }
 
 func newHoudiniMatcher(client *houdini.Client) *houdiniMatcher {
@@ -122,6 +154,7 @@ func (m *houdiniMatcher) refresh(ctx context.Context) error {
 
 	currencies, err := m.client.GetCurrencies(ctx)
 	if err != nil {
+		m.state.record(0, err)
 		return err
 	}
 
@@ -147,6 +180,7 @@ func (m *houdiniMatcher) refresh(ctx context.Context) error {
 	m.byContract = byContract
 	m.bySymbol = bySymbol
 	m.mu.Unlock()
+	m.state.record(len(currencies), nil)
 
 	log.Printf("resolver: loaded %d Houdini currencies", len(currencies))
 	return nil
@@ -207,3 +241,121 @@ func normalizeChainToNetworks(chain string) []string {
 		return []string{chain}
 	}
 }
+
+// stealthexMatcher provides dynamic lookup of StealthEX currencies.
+type stealthexMatcher struct {
+	client *stealthex.Client
+
+	mu sync.RWMutex
+	// byContract maps lowercase "network:contractaddress" to "symbol:network"
+	byContract map[string]string
+	// bySymbol maps lowercase "network:symbol" to "symbol:network"
+	bySymbol map[string]string
+
+	state refreshState
+}
+
+func newStealthexMatcher(client *stealthex.Client) *stealthexMatcher {
+	return &stealthexMatcher{
+		client:     client,
+		byContract: make(map[string]string),
+		bySymbol:   make(map[string]string),
+	}
+}
+
+// refresh fetches the currency list and rebuilds the indices.
+func (m *stealthexMatcher) refresh(ctx context.Context) error {
+	if m.client == nil {
+		return nil
+	}
+
+	currencies, err := m.client.GetCurrencies(ctx)
+	if err != nil {
+		m.state.record(0, err)
+		return err
+	}
+
+	byContract := make(map[string]string)
+	bySymbol := make(map[string]string)
+
+	for _, c := range currencies {
+		network := strings.ToLower(c.Network)
+		symbol := strings.ToLower(c.Symbol)
+		ref := stealthex.CurrencyRef{Symbol: c.Symbol, Network: c.Network}.String()
+
+		// Index by contract address if present
+		if c.ContractAddress != "" {
+			key := network + ":" + strings.ToLower(c.ContractAddress)
+			byContract[key] = ref
+		}
+
+		// Index by network:symbol
+		key := network + ":" + symbol
+		bySymbol[key] = ref
+	}
+
+	m.mu.Lock()
+	m.byContract = byContract
+	m.bySymbol = bySymbol
+	m.mu.Unlock()
+	m.state.record(len(currencies), nil)
+
+	log.Printf("resolver: loaded %d StealthEX currencies", len(currencies))
+	return nil
+}
+
+// nativeSymbols maps chains whose native coin isn't named after the chain
+// to the coin's symbol.
+var nativeSymbols = map[string]string{
+	"TRON":    "TRX",
+	"BSC":     "BNB",
+	"GAIA":    "ATOM",
+	"POLYGON": "POL",
+}
+
+// isL1Native reports whether symbol is the native coin of chain itself, as
+// ETH is on ETH but not on BASE or ARB, whose ETH is bridged.
+func isL1Native(chain, symbol string) bool {
+	chain = strings.ToUpper(chain)
+	if native, ok := nativeSymbols[chain]; ok {
+		return strings.EqualFold(native, symbol)
+	}
+	return strings.EqualFold(chain, symbol)
+}
+
+// match tries to find a StealthEX "symbol:network" for the given chain and contract/symbol.
+// StealthEX lists native L1 coins under the "mainnet" network, so native lookups
+// (no contract address) of a chain's own coin also try that network. A coin
+// on another chain, such as ETH on BASE, never matches a "mainnet" entry.
+func (m *stealthexMatcher) match(chain, symbol, contractAddr string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	networks := normalizeChainToNetworks(chain)
+
+	// Try contract address first (for each possible network name)
+	if contractAddr != "" {
+		for _, network := range networks {
+			key := network + ":" + strings.ToLower(contractAddr)
+			if ref, ok := m.byContract[key]; ok {
+				return ref, true
+			}
+		}
+	}
+
+	// Try symbol (for each possible network name)
+	for _, network := range networks {
+		key := network + ":" + strings.ToLower(symbol)
+		if ref, ok := m.bySymbol[key]; ok {
+			return ref, true
+		}
+	}
+
+	if contractAddr == "" && isL1Native(chain, symbol) {
+		if ref, ok := m.bySymbol["mainnet:"+strings.ToLower(symbol)]; ok {
+			return ref, true
+		}
+	}
+
+	return "", false
+}
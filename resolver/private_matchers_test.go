@@ -0,0 +1,30 @@
+package resolver
+
+import "testing"
+
+func TestStealthexMatchMainnetOnlyOnOwnChain(t *testing.T) {
+	m := newStealthexMatcher(nil)
+	m.bySymbol["mainnet:eth"] = "eth:mainnet"
+	m.bySymbol["mainnet:trx"] = "trx:mainnet"
+	m.bySymbol["base:usdc"] = "usdc:base"
+
+	tests := []struct {
+		chain, symbol string
+		want          string
+	}{
+		{"ETH", "ETH", "eth:mainnet"},
+		{"TRON", "TRX", "trx:mainnet"},
+		{"BASE", "USDC", "usdc:base"},
+		// Bridged ETH isn't the mainnet coin
+		{"BASE", "ETH", ""},
+		{"ARB", "ETH", ""},
+		{"OP", "ETH", ""},
+		{"BSC", "TRX", ""},
+	}
+	for _, tt := range tests {
+		got, ok := m.match(tt.chain, tt.symbol, "")
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("match(%s, %s) = %q, %v; want %q", tt.chain, tt.symbol, got, ok, tt.want)
+		}
+	}
+}
@@ -2,55 +2,140 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
 	"github.com/RaghavSood/fundbot/houdini"
+	"github.com/RaghavSood/fundbot/relay"
 	"github.com/RaghavSood/fundbot/simpleswap"
+	"github.com/RaghavSood/fundbot/stealthex"
 	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/uniswap"
+	"github.com/RaghavSood/fundbot/zeroex"
+)
+
+// Where a Resolution's token was found
+const (
+	SourceCoinGecko   = "coingecko"
+	SourceDexScreener = "dexscreener" // long-tail tokens CoinGecko doesn't index
 )
 
 // ProviderMatch represents a successful match of a token on a specific provider.
 type ProviderMatch struct {
-	Provider string // "thorchain", "simpleswap", "nearintents"
+	Provider string // "thorchain", "simpleswap", "nearintents", "houdini", "stealthex", "relay", "zeroex", "uniswap"
 	AssetID  string // provider-specific identifier
 }
 
 // Resolution holds the result of resolving an unknown asset.
 type Resolution struct {
-	CoinGeckoID     string
+	Source          string // SourceCoinGecko or SourceDexScreener
+	CoinGeckoID     string // empty for DexScreener tokens
 	Name            string // e.g. "Chainlink"
 	Symbol          string // e.g. "LINK"
 	ContractAddress string // primary contract address for display
 	Providers       []ProviderMatch
+
+	// Market data in USD; zero when the source has none
+	MarketCapUSD float64
+	VolumeUSD    float64
+	LiquidityUSD float64 // DEX pool liquidity, for DexScreener tokens
+
+	// Failed token screening checks; the user has to confirm the token
+	// anyway to use it
+	Warnings []string
+}
+
+// maxCandidates caps the coins Candidates offers, and so the CoinGecko coin
+// lookups it makes.
+const maxCandidates = 5
+
+// Candidate is a CoinGecko coin, or a DexScreener token, whose symbol
+// matches an unknown asset, for the user to pick when several do.
+type Candidate struct {
+	CoinGeckoID     string // empty for DexScreener tokens
+	Name            string
+	Symbol          string
+	MarketCapRank   int // 0 when unranked
+	MarketCapUSD    float64
+	LiquidityUSD    float64  // DexScreener tokens only
+	ContractAddress string   // on the asset's chain; empty if not listed there
+	Chains          []string // chains CoinGecko lists contracts on, sorted
 }
 
 // Resolver resolves unknown assets by querying CoinGecko and matching against provider APIs.
 type Resolver struct {
 	cg    *coingeckoClient
+	dex   *dexscreenerClient
 	pools *poolMatcher
 	near  *nearMatcher
 	// simpleswapLookup checks the SimpleSwap static mapping.
 	simpleswapLookup func(key string) (string, bool)
 	// houdiniLookup checks the Houdini static mapping.
 	houdiniLookup func(key string) (string, bool)
+	// stealthexLookup checks the StealthEX static mapping.
+	stealthexLookup func(key string) (string, bool)
 	// Dynamic matchers for private providers
-	simpleswap *simpleswapMatcher
-	houdiniDyn *houdiniMatcher
+	simpleswap   *simpleswapMatcher
+	houdiniDyn   *houdiniMatcher
+	stealthexDyn *stealthexMatcher
+	// Safety checks on resolved tokens; nil skips them
+	screener *screener
 }
 
-// New creates a new Resolver.
-func New(cgAPIKey string, simpleswapLookup func(key string) (string, bool), houdiniLookup func(key string) (string, bool)) *Resolver {
+// New creates a new Resolver using the given CoinGecko API keys in turn.
+func New(cgAPIKeys []string, simpleswapLookup func(key string) (string, bool), houdiniLookup func(key string) (string, bool), stealthexLookup func(key string) (string, bool)) *Resolver {
 	return &Resolver{
-		cg:               newCoingeckoClient(cgAPIKey),
+		cg:               newCoingeckoClient(cgAPIKeys),
+		dex:              newDexscreenerClient(),
 		pools:            newPoolMatcher(),
 		near:             newNearMatcher(),
 		simpleswapLookup: simpleswapLookup,
 		houdiniLookup:    houdiniLookup,
+		stealthexLookup:  stealthexLookup,
+	}
+}
+
+// SetStore keeps the CoinGecko, DexScreener, THORNode and 1click caches in the
+// database, so restarts and other instances reuse their lookups, and prunes
+// expired entries.
+func (r *Resolver) SetStore(store *db.Store) {
+	r.cg.searchCache.Persist(store, "coingecko.search")
+	r.cg.coinCache.Persist(store, "coingecko.coins")
+	r.cg.contractCache.Persist(store, "coingecko.contracts")
+	r.dex.searchCache.Persist(store, "dexscreener.search")
+	r.dex.tokenCache.Persist(store, "dexscreener.tokens")
+	r.pools.cache.Persist(store, "thorchain.pools")
+	r.near.cache.Persist(store, "near.tokens")
+
+	if n, err := store.DeleteExpiredCacheEntries(context.Background(), time.Now().UTC()); err != nil {
+		log.Printf("resolver: error pruning cache entries: %v", err)
+	} else if n > 0 {
+		log.Printf("resolver: pruned %d expired cache entries", n)
 	}
 }
 
+// SetCoinGeckoPro sends the CoinGecko keys to the Pro API.
+func (r *Resolver) SetCoinGeckoPro(pro bool) {
+	r.cg.setPro(pro)
+}
+
+// SetScreening checks resolved tokens against the token_screening settings.
+// Tokens that fail are refused when cfg.Block is set, and otherwise carry
+// warnings. A nil cfg turns screening off.
+func (r *Resolver) SetScreening(cfg *config.TokenScreeningConfig) {
+	if cfg == nil {
+		r.screener = nil
+		return
+	}
+	r.screener = newScreener(cfg)
+}
+
 // SetSimpleSwapClient sets the SimpleSwap client for dynamic currency lookup.
 func (r *Resolver) SetSimpleSwapClient(client *simpleswap.Client) {
 	r.simpleswap = newSimpleswapMatcher(client)
@@ -61,6 +146,11 @@ func (r *Resolver) SetHoudiniClient(client *houdini.Client) {
 	r.houdiniDyn = newHoudiniMatcher(client)
 }
 
+// SetStealthEXClient sets the StealthEX client for dynamic currency lookup.
+func (r *Resolver) SetStealthEXClient(client *stealthex.Client) {
+	r.stealthexDyn = newStealthexMatcher(client)
+}
+
 // RefreshPrivateProviders refreshes the currency lists from private providers.
 func (r *Resolver) RefreshPrivateProviders(ctx context.Context) {
 	if r.simpleswap != nil {
@@ -73,10 +163,22 @@ func (r *Resolver) RefreshPrivateProviders(ctx context.Context) {
 			log.Printf("resolver: failed to refresh Houdini currencies: %v", err)
 		}
 	}
+	if r.stealthexDyn != nil {
+		if err := r.stealthexDyn.refresh(ctx); err != nil {
+			log.Printf("resolver: failed to refresh StealthEX currencies: %v", err)
+		}
+	}
 }
 
 // Resolve attempts to identify and match an unknown asset across providers.
+// Of several coins with the asset's symbol, the best ranked is used. Symbols
+// CoinGecko doesn't know are looked up on DexScreener, taking the most
+// liquid token on the asset's chain.
 func (r *Resolver) Resolve(ctx context.Context, asset swaps.Asset) (*Resolution, error) {
+	if asset.ContractOnly() {
+		return r.ResolveContract(ctx, asset)
+	}
+
 	// Search CoinGecko for the symbol.
 	coins, err := r.cg.search(ctx, asset.Symbol)
 	if err != nil {
@@ -85,24 +187,225 @@ func (r *Resolver) Resolve(ctx context.Context, asset swaps.Asset) (*Resolution,
 
 	best := r.cg.bestMatch(coins, asset.Symbol)
 	if best == nil {
-		return nil, fmt.Errorf("no CoinGecko result for symbol %q", asset.Symbol)
+		tokens, err := r.dex.onChain(ctx, asset.Chain, asset.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("no CoinGecko or DexScreener result for symbol %q on %s", asset.Symbol, asset.Chain)
+		}
+		for _, t := range tokens {
+			if asset.ContractAddress != "" && strings.EqualFold(t.Address, asset.ContractAddress) {
+				return r.resolveToken(ctx, asset, t)
+			}
+		}
+		return r.resolveToken(ctx, asset, tokens[0])
+	}
+
+	return r.resolve(ctx, asset, best)
+}
+
+// ResolveContract resolves an asset given by contract address alone, e.g.
+// "BASE.0x...", as the coin CoinGecko lists for the contract, or else the
+// token DexScreener has pairs for. The Resolution's Symbol completes the
+// asset.
+func (r *Resolver) ResolveContract(ctx context.Context, asset swaps.Asset) (*Resolution, error) {
+	coin, err := r.cg.coinByContract(ctx, asset.Chain, asset.ContractAddress)
+	if errors.Is(err, errNotListed) {
+		token, dexErr := r.dex.token(ctx, asset.Chain, asset.ContractAddress)
+		if dexErr != nil {
+			return nil, fmt.Errorf("%w; %w", err, dexErr)
+		}
+		asset.Symbol = token.Symbol
+		return r.resolveToken(ctx, asset, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	asset.Symbol = strings.ToUpper(coin.Symbol)
+	return r.resolve(ctx, asset, &coin)
+}
+
+// ResolveCoin resolves asset as the given CoinGecko coin, one of its
+// Candidates.
+func (r *Resolver) ResolveCoin(ctx context.Context, asset swaps.Asset, coinID string) (*Resolution, error) {
+	coins, err := r.cg.search(ctx, asset.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("coingecko search: %w", err)
+	}
+
+	for _, coin := range r.cg.matches(coins, asset.Symbol) {
+		if coin.ID == coinID {
+			return r.resolve(ctx, asset, &coin)
+		}
+	}
+	return nil, fmt.Errorf("no CoinGecko result %q for symbol %q", coinID, asset.Symbol)
+}
+
+// ResolveCandidate resolves asset as one of its Candidates.
+func (r *Resolver) ResolveCandidate(ctx context.Context, asset swaps.Asset, c Candidate) (*Resolution, error) {
+	if c.CoinGeckoID != "" {
+		return r.ResolveCoin(ctx, asset, c.CoinGeckoID)
+	}
+	token, err := r.dex.token(ctx, asset.Chain, c.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveToken(ctx, asset, token)
+}
+
+// Candidates returns the coins the asset's symbol could mean, best ranked
+// first, up to maxCandidates. Coins listed on another chain but not the
+// asset's are left out unless no coin is listed on it; coins with no
+// contracts, such as native coins, are kept. A coin matching the asset's
+// contract address is returned alone. When CoinGecko lists no coin on the
+// asset's chain, the tokens DexScreener has there are offered instead.
+func (r *Resolver) Candidates(ctx context.Context, asset swaps.Asset) ([]Candidate, error) {
+	coins, err := r.cg.search(ctx, asset.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("coingecko search: %w", err)
+	}
+
+	matches := r.cg.matches(coins, asset.Symbol)
+	if len(matches) == 0 {
+		dex, err := r.dexCandidates(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		if len(dex) == 0 {
+			return nil, fmt.Errorf("no CoinGecko or DexScreener result for symbol %q on %s", asset.Symbol, asset.Chain)
+		}
+		return dex, nil
+	}
+	if len(matches) > maxCandidates {
+		matches = matches[:maxCandidates]
+	}
+
+	chain := strings.ToUpper(asset.Chain)
+	var all, onChain []Candidate
+	for _, m := range matches {
+		coin, err := r.cg.getCoin(ctx, m.ID)
+		if err != nil {
+			log.Printf("resolver: error looking up CoinGecko coin %s: %v", m.ID, err)
+			continue
+		}
+		c := Candidate{
+			CoinGeckoID:     m.ID,
+			Name:            m.Name,
+			Symbol:          strings.ToUpper(m.Symbol),
+			MarketCapUSD:    coin.MarketCapUSD,
+			ContractAddress: coin.Platforms[chain],
+		}
+		if m.MarketCapRank != nil {
+			c.MarketCapRank = *m.MarketCapRank
+		}
+		for p := range coin.Platforms {
+			c.Chains = append(c.Chains, p)
+		}
+		sort.Strings(c.Chains)
+
+		if asset.ContractAddress != "" && strings.EqualFold(c.ContractAddress, asset.ContractAddress) {
+			return []Candidate{c}, nil
+		}
+		all = append(all, c)
+		if c.ContractAddress != "" || len(c.Chains) == 0 {
+			onChain = append(onChain, c)
+		}
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("coingecko coin lookups failed for symbol %q", asset.Symbol)
+	}
+	if len(onChain) > 0 {
+		return onChain, nil
 	}
 
-	// Get platform/contract info.
-	platforms, err := r.cg.getPlatforms(ctx, best.ID)
+	// The coins CoinGecko knows are elsewhere; a token on the asset's
+	// chain is more likely what the user means
+	dex, err := r.dexCandidates(ctx, asset)
 	if err != nil {
-		return nil, fmt.Errorf("coingecko platforms: %w", err)
+		log.Printf("resolver: DexScreener lookup of %s failed: %v", asset, err)
+	} else if len(dex) > 0 {
+		return dex, nil
 	}
+	return all, nil
+}
+
+// dexCandidates returns the tokens DexScreener has with the asset's symbol
+// on its chain, most liquid first, up to maxCandidates. A token matching the
+// asset's contract address is returned alone.
+func (r *Resolver) dexCandidates(ctx context.Context, asset swaps.Asset) ([]Candidate, error) {
+	tokens, err := r.dex.onChain(ctx, asset.Chain, asset.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	for _, t := range tokens {
+		c := Candidate{
+			Name:            t.Name,
+			Symbol:          t.Symbol,
+			MarketCapUSD:    t.MarketCapUSD,
+			LiquidityUSD:    t.LiquidityUSD,
+			ContractAddress: t.Address,
+			Chains:          []string{t.Chain},
+		}
+		if asset.ContractAddress != "" && strings.EqualFold(t.Address, asset.ContractAddress) {
+			return []Candidate{c}, nil
+		}
+		if len(candidates) < maxCandidates {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
 
+// resolve matches the CoinGecko coin best against the providers.
+func (r *Resolver) resolve(ctx context.Context, asset swaps.Asset, best *cgSearchResult) (*Resolution, error) {
+	// Get platform/contract info and market data.
+	coin, err := r.cg.getCoin(ctx, best.ID)
+	if err != nil {
+		return nil, fmt.Errorf("coingecko coin: %w", err)
+	}
+
+	res := &Resolution{
+		Source:       SourceCoinGecko,
+		CoinGeckoID:  best.ID,
+		Name:         best.Name,
+		Symbol:       strings.ToUpper(best.Symbol),
+		MarketCapUSD: coin.MarketCapUSD,
+		VolumeUSD:    coin.VolumeUSD,
+	}
+	return r.match(ctx, asset, coin, res)
+}
+
+// resolveToken matches a token found on DexScreener against the providers.
+// Its contract stands in for CoinGecko's platforms.
+func (r *Resolver) resolveToken(ctx context.Context, asset swaps.Asset, token dsToken) (*Resolution, error) {
+	coin := cgCoin{
+		Platforms:    map[string]string{token.Chain: token.Address},
+		MarketCapUSD: token.MarketCapUSD,
+		VolumeUSD:    token.VolumeUSD,
+	}
 	res := &Resolution{
-		CoinGeckoID: best.ID,
-		Name:        best.Name,
-		Symbol:      strings.ToUpper(best.Symbol),
+		Source:       SourceDexScreener,
+		Name:         token.Name,
+		Symbol:       token.Symbol,
+		MarketCapUSD: token.MarketCapUSD,
+		VolumeUSD:    token.VolumeUSD,
+		LiquidityUSD: token.LiquidityUSD,
 	}
+	return r.match(ctx, asset, coin, res)
+}
+
+// match fills in res's providers from the coin's contracts, then screens it.
+func (r *Resolver) match(ctx context.Context, asset swaps.Asset, coin cgCoin, res *Resolution) (*Resolution, error) {
+	platforms := coin.Platforms
+	chainContract := platforms[strings.ToUpper(asset.Chain)]
 
 	// Try to find a display contract address for the user's specified chain.
-	if addr, ok := platforms[strings.ToUpper(asset.Chain)]; ok {
-		res.ContractAddress = addr
+	if chainContract != "" {
+		res.ContractAddress = chainContract
 	}
 
 	// --- Thorchain matching ---
@@ -117,8 +420,24 @@ func (r *Resolver) Resolve(ctx context.Context, asset swaps.Asset) (*Resolution,
 	// --- Houdini matching ---
 	r.matchHoudini(asset, res)
 
+	// --- StealthEX matching ---
+	r.matchStealthEX(asset, res)
+
+	// --- Relay matching ---
+	r.matchRelay(asset, res)
+
+	// --- 0x and Uniswap matching ---
+	r.matchSameChain(asset, chainContract, res)
+
 	if len(res.Providers) == 0 {
-		return nil, fmt.Errorf("token %s (%s) found on CoinGecko but not supported by any provider", res.Name, res.Symbol)
+		return nil, fmt.Errorf("token %s (%s) found on %s but not supported by any provider", res.Name, res.Symbol, res.SourceName())
+	}
+
+	if r.screener != nil {
+		res.Warnings = r.screener.screen(ctx, asset, coin, res)
+		if len(res.Warnings) > 0 && r.screener.cfg.Block {
+			return nil, fmt.Errorf("token %s (%s) failed safety checks: %s", res.Name, res.Symbol, strings.Join(res.Warnings, "; "))
+		}
 	}
 
 	return res, nil
@@ -268,6 +587,70 @@ func (r *Resolver) matchHoudini(asset swaps.Asset, res *Resolution) {
 	}
 }
 
+func (r *Resolver) matchStealthEX(asset swaps.Asset, res *Resolution) {
+	// Try dynamic lookup first (by contract address from CoinGecko)
+	if r.stealthexDyn != nil && res.ContractAddress != "" {
+		if ref, ok := r.stealthexDyn.match(asset.Chain, asset.Symbol, res.ContractAddress); ok {
+			res.Providers = append(res.Providers, ProviderMatch{Provider: "stealthex", AssetID: ref})
+			return
+		}
+	}
+
+	// Try dynamic lookup by symbol only
+	if r.stealthexDyn != nil {
+		if ref, ok := r.stealthexDyn.match(asset.Chain, asset.Symbol, ""); ok {
+			res.Providers = append(res.Providers, ProviderMatch{Provider: "stealthex", AssetID: ref})
+			return
+		}
+	}
+
+	// Fall back to static lookup
+	if r.stealthexLookup == nil {
+		return
+	}
+
+	key := strings.ToUpper(asset.Chain + "." + asset.Symbol)
+	if ref, ok := r.stealthexLookup(key); ok {
+		res.Providers = append(res.Providers, ProviderMatch{Provider: "stealthex", AssetID: ref})
+	}
+}
+
+// matchRelay matches ERC20s on Relay-supported EVM chains. Relay accepts any token
+// contract, so a CoinGecko contract address on the requested chain is sufficient.
+func (r *Resolver) matchRelay(asset swaps.Asset, res *Resolution) {
+	if res.ContractAddress == "" {
+		return
+	}
+	if _, ok := relay.DestinationChainID(asset.Chain); !ok {
+		return
+	}
+	res.Providers = append(res.Providers, ProviderMatch{Provider: "relay", AssetID: res.ContractAddress})
+}
+
+// matchSameChain matches ERC20s on the chains 0x and Uniswap swap on. Both
+// take any token contract, so a contract on the requested chain itself is
+// sufficient; one on another chain is no use to them.
+func (r *Resolver) matchSameChain(asset swaps.Asset, contract string, res *Resolution) {
+	if contract == "" {
+		return
+	}
+	token := swaps.Asset{Chain: strings.ToUpper(asset.Chain), Symbol: asset.Symbol, ContractAddress: contract}
+	if _, _, _, ok := zeroex.AssetToBuyToken(token); ok {
+		res.Providers = append(res.Providers, ProviderMatch{Provider: "zeroex", AssetID: contract})
+	}
+	if _, _, ok := uniswap.AssetToTokenOut(token); ok {
+		res.Providers = append(res.Providers, ProviderMatch{Provider: "uniswap", AssetID: contract})
+	}
+}
+
+// SourceName returns the name of the service the token was found on.
+func (res *Resolution) SourceName() string {
+	if res.Source == SourceDexScreener {
+		return "DexScreener"
+	}
+	return "CoinGecko"
+}
+
 // ToHints converts a Resolution into ResolvedHints for the swap providers.
 func (res *Resolution) ToHints() *swaps.ResolvedHints {
 	hints := &swaps.ResolvedHints{}
@@ -281,6 +664,14 @@ func (res *Resolution) ToHints() *swaps.ResolvedHints {
 			hints.NearIntentsTokenID = pm.AssetID
 		case "houdini":
 			hints.HoudiniSymbol = pm.AssetID
+		case "stealthex":
+			hints.StealthEXCurrency = pm.AssetID
+		case "relay":
+			hints.RelayCurrency = pm.AssetID
+		case "zeroex":
+			hints.ZeroExToken = pm.AssetID
+		case "uniswap":
+			hints.UniswapToken = pm.AssetID
 		}
 	}
 	return hints
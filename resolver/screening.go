@@ -0,0 +1,142 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// maxSellTax is the sell tax, in percent, above which the honeypot check
+// warns about a token that can still be sold.
+const maxSellTax = 10
+
+// screener runs the token_screening checks on a resolved token.
+type screener struct {
+	cfg        *config.TokenScreeningConfig
+	httpClient *http.Client
+}
+
+func newScreener(cfg *config.TokenScreeningConfig) *screener {
+	return &screener{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// screen returns why the token asset resolved to shouldn't be trusted, if
+// anything. A honeypot API that can't be reached is logged and skipped.
+func (s *screener) screen(ctx context.Context, asset swaps.Asset, coin cgCoin, res *Resolution) []string {
+	var warnings []string
+	chain := strings.ToUpper(asset.Chain)
+
+	// A contract the user typed must be the one listed for the token
+	listed, ok := coin.Platforms[chain]
+	if asset.ContractAddress != "" {
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s lists no %s contract on %s", res.SourceName(), res.Symbol, chain))
+		} else if !strings.EqualFold(listed, asset.ContractAddress) {
+			warnings = append(warnings, fmt.Sprintf("Contract %s is not the %s contract %s lists on %s (%s)", asset.ContractAddress, res.Symbol, res.SourceName(), chain, listed))
+		}
+	}
+
+	if min := s.cfg.MinMarketCapUSD; min > 0 {
+		if coin.MarketCapUSD == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s has no market cap for %s", res.SourceName(), res.Symbol))
+		} else if coin.MarketCapUSD < min {
+			warnings = append(warnings, fmt.Sprintf("Market cap of $%.0f is below $%.0f", coin.MarketCapUSD, min))
+		}
+	}
+	if min := s.cfg.MinVolumeUSD; min > 0 && coin.VolumeUSD < min {
+		warnings = append(warnings, fmt.Sprintf("24h trading volume of $%.0f is below $%.0f", coin.VolumeUSD, min))
+	}
+
+	if s.cfg.HoneypotCheck {
+		contract := asset.ContractAddress
+		if contract == "" {
+			contract = res.ContractAddress
+		}
+		if w, err := s.honeypot(ctx, chain, contract); err != nil {
+			log.Printf("resolver: honeypot check of %s on %s failed: %v", contract, chain, err)
+		} else if w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+
+	return warnings
+}
+
+// honeypot asks the honeypot API whether the token contract on chain can be
+// sold. It returns "" for tokens that can, and for native coins and chains
+// the API doesn't cover.
+func (s *screener) honeypot(ctx context.Context, chain, contract string) (string, error) {
+	rpcChain, ok := swaps.AssetChain(chain)
+	if !ok || contract == "" {
+		return "", nil
+	}
+	chainID, ok := swaps.ChainIDs[rpcChain]
+	if !ok {
+		return "", nil
+	}
+
+	u, err := url.Parse(s.cfg.HoneypotURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("address", contract)
+	q.Set("chainID", strconv.FormatInt(chainID, 10))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var raw struct {
+		SimulationSuccess bool `json:"simulationSuccess"`
+		HoneypotResult    struct {
+			IsHoneypot     bool   `json:"isHoneypot"`
+			HoneypotReason string `json:"honeypotReason"`
+		} `json:"honeypotResult"`
+		SimulationResult struct {
+			SellTax float64 `json:"sellTax"`
+		} `json:"simulationResult"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+
+	switch {
+	case raw.HoneypotResult.IsHoneypot:
+		if raw.HoneypotResult.HoneypotReason != "" {
+			return "Flagged as a honeypot: " + raw.HoneypotResult.HoneypotReason, nil
+		}
+		return "Flagged as a honeypot, it may not be sellable", nil
+	case !raw.SimulationSuccess:
+		return "", fmt.Errorf("no sale could be simulated")
+	case raw.SimulationResult.SellTax > maxSellTax:
+		return fmt.Sprintf("Sells are taxed %.0f%%", raw.SimulationResult.SellTax), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"time"
+)
+
+// CacheStats counts a cache's lookups since startup.
+type CacheStats struct {
+	Name      string // as persisted, e.g. "coingecko.search"
+	Entries   int    // in memory, expired ones included until refetched
+	Hits      int64  // served from memory
+	StoreHits int64  // served from the database
+	Misses    int64  // fetched
+	Errors    int64  // fetches that failed
+}
+
+// HitRate returns the share of lookups served without fetching, or 0
+// before any.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.StoreHits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.StoreHits) / float64(total)
+}
+
+// MatcherStatus is the state of a private provider's currency list.
+type MatcherStatus struct {
+	Provider    string
+	Currencies  int
+	RefreshedAt time.Time // zero before the first successful refresh
+	Error       string    // of the last refresh, if it failed
+}
+
+// Stats is what the resolver reports about itself for the admin panel.
+type Stats struct {
+	Caches   []CacheStats
+	Matchers []MatcherStatus // configured private providers only
+
+	// CoinGecko keys, and those resting after a rate limit
+	CoinGeckoKeys        int
+	CoinGeckoKeysResting int
+	CoinGeckoPro         bool
+}
+
+// Stats returns the resolver's cache counters, private provider currency
+// lists and CoinGecko key state.
+func (r *Resolver) Stats() Stats {
+	st := Stats{
+		Caches: []CacheStats{
+			r.cg.searchCache.Stats("coingecko.search"),
+			r.cg.coinCache.Stats("coingecko.coins"),
+			r.cg.contractCache.Stats("coingecko.contracts"),
+			r.dex.searchCache.Stats("dexscreener.search"),
+			r.dex.tokenCache.Stats("dexscreener.tokens"),
+			r.pools.cache.Stats("thorchain.pools"),
+			r.near.cache.Stats("near.tokens"),
+		},
+	}
+	if r.simpleswap != nil {
+		st.Matchers = append(st.Matchers, r.simpleswap.state.status("simpleswap"))
+	}
+	if r.houdiniDyn != nil {
+		st.Matchers = append(st.Matchers, r.houdiniDyn.state.status("houdini"))
+	}
+	if r.stealthexDyn != nil {
+		st.Matchers = append(st.Matchers, r.stealthexDyn.state.status("stealthex"))
+	}
+	st.CoinGeckoKeys, st.CoinGeckoKeysResting, st.CoinGeckoPro = r.cg.keyStatus()
+	return st
+}
@@ -0,0 +1,395 @@
+// Package rpcpool spreads a chain's JSON-RPC calls over several endpoints,
+// failing over to the next when one errors, times out or falls behind.
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// attemptTimeout bounds one try at an endpoint before the next is tried
+	attemptTimeout = 15 * time.Second
+
+	// checkInterval is how often every endpoint is health-checked
+	checkInterval = 30 * time.Second
+
+	// checkTimeout bounds one endpoint's health check
+	checkTimeout = 5 * time.Second
+
+	// maxLag is how far an endpoint's latest block may trail the newest
+	// block any endpoint reports before it counts as unhealthy
+	maxLag = time.Minute
+)
+
+// Pool is an http.RoundTripper for one chain's JSON-RPC client. Each call
+// goes to the first healthy endpoint in order of preference; on a transport
+// error, timeout, 429 or 5xx it is retried on the next, and the failed
+// endpoint is skipped until a health check passes again.
+type Pool struct {
+	chain     string
+	chainID   int64
+	transport http.RoundTripper
+	checker   *http.Client
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+type endpoint struct {
+	url       *url.URL
+	healthy   bool
+	err       string
+	latency   time.Duration
+	block     uint64
+	blockTime time.Time
+	checkedAt time.Time
+	failovers int64
+}
+
+// EndpointStatus is an endpoint's state for the dashboard. URL keeps only
+// the scheme and host, since paths often carry API keys.
+type EndpointStatus struct {
+	URL       string     `json:"url"`
+	Active    bool       `json:"active"` // where calls go now
+	Healthy   bool       `json:"healthy"`
+	Error     string     `json:"error,omitempty"`
+	LatencyMS int64      `json:"latency_ms"`
+	Block     uint64     `json:"block"`
+	CheckedAt *time.Time `json:"checked_at"`
+
+	// Calls moved off the endpoint after it failed them
+	Failovers int64 `json:"failovers"`
+}
+
+// Dial connects to a chain's endpoints. A single ws:// or IPC endpoint is
+// dialed directly and gets no pool; http(s) endpoints go through one.
+// chainID, if not 0, is what the health check expects them to report.
+func Dial(chain string, chainID int64, urls []string) (*ethclient.Client, *Pool, error) {
+	if len(urls) == 1 {
+		if u, err := url.Parse(urls[0]); err == nil && u.Scheme != "http" && u.Scheme != "https" {
+			client, err := ethclient.Dial(urls[0])
+			return client, nil, err
+		}
+	}
+
+	p := &Pool{
+		chain:     chain,
+		chainID:   chainID,
+		transport: http.DefaultTransport,
+		checker:   &http.Client{Timeout: checkTimeout},
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing %s RPC URL: %w", chain, err)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u, healthy: true})
+	}
+	client, err := rpc.DialOptions(context.Background(), urls[0], rpc.WithHTTPClient(&http.Client{Transport: p}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ethclient.NewClient(client), p, nil
+}
+
+// RoundTrip sends a JSON-RPC request to the endpoints in turn until one
+// answers.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	// A transaction an endpoint timed out on may still have been broadcast,
+	// and sending it again elsewhere would fail as already known, so it is
+	// only retried when the endpoint couldn't be reached at all
+	sendsTx := bytes.Contains(body, []byte(`"eth_sendRawTransaction"`))
+
+	var lastErr error
+	for _, i := range p.order() {
+		resp, err := p.try(req, i, body)
+		if err == nil {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			// The caller gave up, which is not the endpoint's fault
+			return nil, err
+		}
+		p.fail(i, err)
+		lastErr = err
+		var opErr *net.OpError
+		if sendsTx && !(errors.As(err, &opErr) && opErr.Op == "dial") {
+			break
+		}
+	}
+	return nil, fmt.Errorf("%s RPC: %w", p.chain, lastErr)
+}
+
+// try sends the request to endpoint i, returning an error for responses
+// that should go to another endpoint.
+func (p *Pool) try(req *http.Request, i int, body []byte) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), attemptTimeout)
+	r := req.Clone(ctx)
+	u := *p.endpoints[i].url
+	r.URL, r.Host = &u, ""
+	r.Body, r.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+
+	resp, err := p.transport.RoundTrip(r)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	// The attempt's deadline also covers reading the body
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// order returns the endpoint indexes to try: healthy ones in order of
+// preference, then the others as a last resort.
+func (p *Pool) order() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var healthy, unhealthy []int
+	for i, e := range p.endpoints {
+		if e.healthy {
+			healthy = append(healthy, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (p *Pool) fail(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e := p.endpoints[i]
+	e.failovers++
+	e.err = err.Error()
+	if e.healthy && len(p.endpoints) > 1 {
+		log.Printf("%s RPC %s failed, failing over: %v", p.chain, redact(e.url), err)
+	}
+	e.healthy = false
+}
+
+// Run health-checks the endpoints until ctx is done.
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		p.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// headResult is what a health check learned from one endpoint.
+type headResult struct {
+	block     uint64
+	blockTime time.Time
+	latency   time.Duration
+	err       error
+}
+
+func (p *Pool) check(ctx context.Context) {
+	results := make([]headResult, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Go(func() {
+			start := time.Now()
+			r := p.head(ctx, e.url)
+			r.latency = time.Since(start)
+			results[i] = r
+		})
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	var newest time.Time
+	for _, r := range results {
+		if r.err == nil && r.blockTime.After(newest) {
+			newest = r.blockTime
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now().UTC()
+	for i, r := range results {
+		e := p.endpoints[i]
+		if r.err == nil && newest.Sub(r.blockTime) > maxLag {
+			r.err = fmt.Errorf("block %d is %s behind the newest", r.block, newest.Sub(r.blockTime).Truncate(time.Second))
+		}
+		e.checkedAt, e.latency = now, r.latency
+		if r.err == nil {
+			e.block, e.blockTime = r.block, r.blockTime
+		}
+		healthy := r.err == nil
+		if healthy != e.healthy && len(p.endpoints) > 1 {
+			if healthy {
+				log.Printf("%s RPC %s is healthy again", p.chain, redact(e.url))
+			} else {
+				log.Printf("%s RPC %s is unhealthy: %v", p.chain, redact(e.url), r.err)
+			}
+		}
+		e.healthy, e.err = healthy, ""
+		if r.err != nil {
+			e.err = r.err.Error()
+		}
+	}
+}
+
+// head checks an endpoint's chain ID and reads its latest block.
+func (p *Pool) head(ctx context.Context, u *url.URL) headResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var chainID string
+	if err := p.call(ctx, u, "eth_chainId", nil, &chainID); err != nil {
+		return headResult{err: fmt.Errorf("chain ID: %w", err)}
+	}
+	id, ok := new(big.Int).SetString(trimHex(chainID), 16)
+	if !ok {
+		return headResult{err: fmt.Errorf("chain ID %q is not a number", chainID)}
+	}
+	if p.chainID != 0 && id.Int64() != p.chainID {
+		return headResult{err: fmt.Errorf("chain ID %s, expected %d", id, p.chainID)}
+	}
+
+	var head struct {
+		Number    string `json:"number"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := p.call(ctx, u, "eth_getBlockByNumber", []any{"latest", false}, &head); err != nil {
+		return headResult{err: fmt.Errorf("latest block: %w", err)}
+	}
+	number, err := strconv.ParseUint(trimHex(head.Number), 16, 64)
+	if err != nil {
+		return headResult{err: fmt.Errorf("latest block number %q: %w", head.Number, err)}
+	}
+	ts, err := strconv.ParseInt(trimHex(head.Timestamp), 16, 64)
+	if err != nil {
+		return headResult{err: fmt.Errorf("latest block time %q: %w", head.Timestamp, err)}
+	}
+	return headResult{block: number, blockTime: time.Unix(ts, 0)}
+}
+
+// call makes one JSON-RPC call to an endpoint, bypassing the pool.
+func (p *Pool) call(ctx context.Context, u *url.URL, method string, params []any, result any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.checker.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if reply.Error != nil {
+		return errors.New(reply.Error.Message)
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+func trimHex(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
+
+// Status reports each endpoint in order of preference.
+func (p *Pool) Status() []EndpointStatus {
+	active := p.order()[0]
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = EndpointStatus{
+			URL:       redact(e.url),
+			Active:    i == active,
+			Healthy:   e.healthy,
+			Error:     e.err,
+			LatencyMS: e.latency.Milliseconds(),
+			Block:     e.block,
+			Failovers: e.failovers,
+		}
+		if !e.checkedAt.IsZero() {
+			checked := e.checkedAt
+			status[i].CheckedAt = &checked
+		}
+	}
+	return status
+}
+
+// redact drops everything after the host, where providers put API keys.
+func redact(u *url.URL) string {
+	s := u.Scheme + "://" + u.Host
+	if !slices.Contains([]string{"", "/"}, u.Path) || u.RawQuery != "" {
+		s += "/…"
+	}
+	return s
+}
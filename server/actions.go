@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/topups"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// SetGasRefills enables cancelling open gas refill orders from the admin
+// panel. hooks receives the resulting gas_refill.cancelled events.
+func (s *Server) SetGasRefills(cowClient *cowswap.Client, hooks *webhooks.Client) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.cowClient = cowClient
+	s.hooks = hooks
+}
+
+func (s *Server) gasRefills() (*cowswap.Client, *webhooks.Client) {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.cowClient, s.hooks
+}
+
+// topupActionError writes the response for a failed topup admin action.
+func topupActionError(w http.ResponseWriter, err error) {
+	var unsigned *wallet.UnsignedTxError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "topup not found", http.StatusNotFound)
+	case errors.Is(err, topups.ErrStatus):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &unsigned):
+		http.Error(w, "watch-only wallet: retries must be signed offline via the bot", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+// POST /api/admin/topup-resolve/{id} marks a pending, stalled or failed
+// topup resolved with a note.
+func (s *Server) handleAdminTopupResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	shortID := r.URL.Path[len("/api/admin/topup-resolve/"):]
+	var req resolveRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc, _ := s.topupService()
+	if svc == nil {
+		http.Error(w, "wallet is locked", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := svc.Resolve(r.Context(), shortID, req.Note); err != nil {
+		topupActionError(w, err)
+		return
+	}
+	log.Printf("Admin %s resolved topup %s: %s", currentAdmin(r).Username, shortID, req.Note)
+	if err := s.audit(r, auditTopupResolve, shortID, req.Note); err != nil {
+		log.Printf("Error recording resolve of %s: %v", shortID, err)
+	}
+	writeJSON(w, topupResolution{ShortID: shortID, Status: "resolved", Note: req.Note})
+}
+
+// POST /api/admin/topup-retry/{id} submits a failed or refunded topup again
+// and returns once the new swap is broadcast.
+func (s *Server) handleAdminTopupRetry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	shortID := r.URL.Path[len("/api/admin/topup-retry/"):]
+	svc, _ := s.topupService()
+	if svc == nil {
+		http.Error(w, "wallet is locked", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Closing the admin panel must not abandon a half-executed swap
+	ctx := context.WithoutCancel(r.Context())
+	result, err := svc.Retry(ctx, shortID, func(text string) {
+		log.Printf("Admin retry of %s: %s", shortID, text)
+	})
+	if err != nil {
+		topupActionError(w, err)
+		return
+	}
+	log.Printf("Admin %s retried topup %s as %s", currentAdmin(r).Username, shortID, result.Topup.ShortID)
+	if err := s.audit(r, auditTopupRetry, shortID, fmt.Sprintf("retried as %s via %s, tx %s", result.Topup.ShortID, result.Quote.Provider, result.Swap.TxHash)); err != nil {
+		log.Printf("Error recording retry of %s: %v", shortID, err)
+	}
+	writeJSON(w, topupRetry{
+		ShortID:     shortID,
+		RetriedAs:   result.Topup.ShortID,
+		Provider:    result.Quote.Provider,
+		FromChain:   result.Quote.FromChain,
+		TxHash:      result.Swap.TxHash,
+		ExplorerURL: s.cfg.ExplorerTxURL(result.Quote.FromChain, result.Swap.TxHash),
+	})
+}
+
+// GET /api/admin/gas-refills lists the gas refill orders still open.
+func (s *Server) handleAdminGasRefills(w http.ResponseWriter, r *http.Request) {
+	refills, err := s.store.ListPendingGasRefills(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, refills)
+}
+
+// POST /api/admin/gas-refill-cancel/{id} cancels an open gas refill order.
+// Cancelled refills are not resubmitted by the tracker.
+func (s *Server) handleAdminGasRefillCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/gas-refill-cancel/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	cowClient, hooks := s.gasRefills()
+	if cowClient == nil {
+		http.Error(w, "gas refills are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := r.Context()
+	refill, err := s.store.GetGasRefill(ctx, id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "gas refill not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if refill.Status != "open" {
+		http.Error(w, "gas refill is "+refill.Status, http.StatusConflict)
+		return
+	}
+
+	keyring := s.wallets()
+	if refill.WalletIndex < 0 || wallet.WatchOnly(keyring) {
+		http.Error(w, "the wallet can't sign the cancellation here; cancel the order on explorer.cow.fi", http.StatusConflict)
+		return
+	}
+	signer, err := keyring.Signer(uint32(refill.WalletIndex))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if signer.Address().Hex() != refill.WalletAddress {
+		http.Error(w, "the wallet has moved pools since the order was placed; cancel it on explorer.cow.fi", http.StatusConflict)
+		return
+	}
+
+	if err := cowClient.CancelOrder(ctx, refill.Chain, signer, refill.OrderUid); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	// The tracker may have seen the cancellation first; the note still
+	// keeps the refill from being resubmitted
+	note := "Cancelled by admin"
+	if _, err := s.store.CancelGasRefill(ctx, db.CancelGasRefillParams{ResolutionNote: note, ID: refill.ID}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin %s cancelled gas refill %d (order %s)", currentAdmin(r).Username, refill.ID, refill.OrderUid)
+	if err := s.audit(r, auditRefillCancel, strconv.FormatInt(refill.ID, 10), fmt.Sprintf("%s order %s", refill.Chain, refill.OrderUid)); err != nil {
+		log.Printf("Error recording cancel of gas refill %d: %v", refill.ID, err)
+	}
+
+	refill.Status, refill.ResolutionNote = "cancelled", note
+	hooks.GasRefillStatus(webhooks.GasRefill{
+		ID:            refill.ID,
+		Status:        refill.Status,
+		Chain:         refill.Chain,
+		OrderUID:      refill.OrderUid,
+		WalletAddress: refill.WalletAddress,
+		SellAmount:    refill.SellAmount,
+		BuyAmount:     refill.BuyAmount,
+		Attempt:       refill.Attempt,
+		OrderType:     refill.OrderType,
+		Kind:          refill.Kind,
+		UserID:        refill.UserID,
+		ChatID:        refill.ChatID,
+	})
+	writeJSON(w, refill)
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// Admin roles. Each role may do everything the roles before it may:
+// viewers read, operators also act on topups, refills, chains, broadcasts
+// and the keystore, and superadmins also export keys, manage accounts,
+// purge API logs and take backups.
+const (
+	roleViewer     = "viewer"
+	roleOperator   = "operator"
+	roleSuperadmin = "superadmin"
+)
+
+var roleRank = map[string]int{roleViewer: 1, roleOperator: 2, roleSuperadmin: 3}
+
+// minAdminPassword is the shortest admin password accepted.
+const minAdminPassword = 8
+
+// dummyHash is compared against when a login names no account, so unknown
+// usernames take as long to reject as wrong passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("fundbot-no-such-admin"), bcrypt.DefaultCost)
+
+// adminSession is the account an admin session cookie belongs to.
+type adminSession struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// can reports whether the session's role includes role.
+func (a adminSession) can(role string) bool {
+	return roleRank[a.Role] >= roleRank[role]
+}
+
+type adminContextKey struct{}
+
+// currentAdmin returns the account behind a request that passed
+// withAdminAuth.
+func currentAdmin(r *http.Request) adminSession {
+	a, _ := r.Context().Value(adminContextKey{}).(adminSession)
+	return a
+}
+
+// HashAdminPassword returns the bcrypt hash stored for an admin password.
+func HashAdminPassword(password string) (string, error) {
+	if len(password) < minAdminPassword {
+		return "", fmt.Errorf("password must be at least %d characters", minAdminPassword)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// ValidateRole checks that role names one of the admin roles: viewer,
+// operator or superadmin.
+func ValidateRole(role string) error {
+	if roleRank[role] == 0 {
+		return fmt.Errorf("role must be %s, %s or %s", roleViewer, roleOperator, roleSuperadmin)
+	}
+	return nil
+}
+
+// CreateAdmin adds an admin account.
+func CreateAdmin(ctx context.Context, store *db.Store, username, password, role string) (int64, error) {
+	if username == "" {
+		return 0, fmt.Errorf("username is required")
+	}
+	if err := ValidateRole(role); err != nil {
+		return 0, err
+	}
+	hash, err := HashAdminPassword(password)
+	if err != nil {
+		return 0, err
+	}
+	return store.InsertAdmin(ctx, db.InsertAdminParams{Username: username, PasswordHash: hash, Role: role})
+}
+
+// SeedAdmin creates the superadmin "admin" with the configured
+// admin_password while there are no admin accounts, so a deployment that
+// used the shared password keeps its login. It reports whether any admin
+// account exists afterwards.
+func SeedAdmin(ctx context.Context, cfg *config.Config, store *db.Store) (bool, error) {
+	n, err := store.CountAdmins(ctx)
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return true, nil
+	}
+	if cfg.AdminPassword == "" {
+		return false, nil
+	}
+	if _, err := CreateAdmin(ctx, store, "admin", cfg.AdminPassword, roleSuperadmin); err != nil {
+		return false, fmt.Errorf("creating admin account: %w", err)
+	}
+	log.Println("Created superadmin account \"admin\" from admin_password")
+	return true, nil
+}
+
+// withAdminRole is withAdminAuth for endpoints that need at least role.
+func (s *Server) withAdminRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return s.withAdminAuth(func(w http.ResponseWriter, r *http.Request) {
+		if !requireRole(w, r, role) {
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requireRole writes a 403 and returns false unless the request's admin has
+// at least role.
+func requireRole(w http.ResponseWriter, r *http.Request, role string) bool {
+	if !currentAdmin(r).can(role) {
+		http.Error(w, fmt.Sprintf("requires the %s role", role), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// dropAdminSessions signs an account out everywhere, after its role changes
+// or it is deleted.
+func dropAdminSessions(id int64) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	for token, a := range adminSessions {
+		if a.ID == id {
+			delete(adminSessions, token)
+		}
+	}
+}
+
+// GET /api/admin/me returns the signed-in account.
+func (s *Server) handleAdminMe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, currentAdmin(r))
+}
+
+// GET, POST /api/admin/admins lists admin accounts or creates one.
+func (s *Server) handleAdminAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		admins, err := s.store.ListAdmins(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, admins)
+	case http.MethodPost:
+		var req adminCreateRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := s.store.GetAdminByUsername(ctx, req.Username); err == nil {
+			http.Error(w, fmt.Sprintf("admin %s already exists", req.Username), http.StatusConflict)
+			return
+		}
+		id, err := CreateAdmin(ctx, s.store, req.Username, req.Password, req.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Admin %s created %s account %s", currentAdmin(r).Username, req.Role, req.Username)
+		if err := s.audit(r, auditAdminCreate, req.Username, "role "+req.Role); err != nil {
+			log.Printf("Error recording creation of admin %s: %v", req.Username, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(adminSession{ID: id, Username: req.Username, Role: req.Role})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// POST /api/admin/admin/{id} changes an account's role or password;
+// DELETE removes it. The last superadmin can't be demoted or removed, and
+// nobody can remove their own account.
+func (s *Server) handleAdminAccount(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/admin/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	me := currentAdmin(r)
+
+	s.adminsMu.Lock()
+	defer s.adminsMu.Unlock()
+
+	ctx := r.Context()
+	admin, err := s.store.GetAdmin(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "admin not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	lastSuperadmin := func() bool {
+		n, err := s.store.CountSuperadmins(ctx)
+		return err != nil || (admin.Role == roleSuperadmin && n <= 1)
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req adminUpdateRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Role != "" && req.Role != admin.Role {
+			if lastSuperadmin() {
+				http.Error(w, "can't demote the last superadmin", http.StatusConflict)
+				return
+			}
+			if err := s.store.UpdateAdminRole(ctx, db.UpdateAdminRoleParams{Role: req.Role, ID: id}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			log.Printf("Admin %s changed %s from %s to %s", me.Username, admin.Username, admin.Role, req.Role)
+			if err := s.audit(r, auditAdminUpdate, admin.Username, fmt.Sprintf("role %s -> %s", admin.Role, req.Role)); err != nil {
+				log.Printf("Error recording role change of admin %s: %v", admin.Username, err)
+			}
+			admin.Role = req.Role
+		}
+		if req.Password != "" {
+			hash, err := HashAdminPassword(req.Password)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := s.store.UpdateAdminPassword(ctx, db.UpdateAdminPasswordParams{PasswordHash: hash, ID: id}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			log.Printf("Admin %s reset the password of %s", me.Username, admin.Username)
+			if err := s.audit(r, auditAdminUpdate, admin.Username, "password reset"); err != nil {
+				log.Printf("Error recording password reset of admin %s: %v", admin.Username, err)
+			}
+		}
+		dropAdminSessions(id)
+		writeJSON(w, adminSession{ID: admin.ID, Username: admin.Username, Role: admin.Role})
+	case http.MethodDelete:
+		if id == me.ID {
+			http.Error(w, "can't delete your own account", http.StatusConflict)
+			return
+		}
+		if lastSuperadmin() {
+			http.Error(w, "can't delete the last superadmin", http.StatusConflict)
+			return
+		}
+		if err := s.store.DeleteAdmin(ctx, id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		dropAdminSessions(id)
+		log.Printf("Admin %s deleted account %s", me.Username, admin.Username)
+		if err := s.audit(r, auditAdminDelete, admin.Username, "role "+admin.Role); err != nil {
+			log.Printf("Error recording deletion of admin %s: %v", admin.Username, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// adminLogin checks a username and password, returning the account's
+// session on success.
+func (s *Server) adminLogin(ctx context.Context, username, password string) (adminSession, bool) {
+	admin, err := s.store.GetAdminByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error loading admin %s: %v", username, err)
+		}
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return adminSession{}, false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password)) != nil {
+		return adminSession{}, false
+	}
+	return adminSession{ID: admin.ID, Username: admin.Username, Role: admin.Role}, true
+}
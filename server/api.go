@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/topups"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// SetTopups enables the /api/v1 REST API, which runs quotes and topups
+// through the same service as the bot.
+func (s *Server) SetTopups(svc *topups.Service, swapMgr *swaps.Manager) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.topups = svc
+	s.swapMgr = swapMgr
+}
+
+func (s *Server) topupService() (*topups.Service, *swaps.Manager) {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.topups, s.swapMgr
+}
+
+type apiHandler func(w http.ResponseWriter, r *http.Request, key config.APIKeyConfig)
+
+// withAPIKey authenticates /api/v1 requests by their bearer token.
+func (s *Server) withAPIKey(next apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeAPIError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		for _, key := range s.cfg.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(key.Key)) == 1 {
+				// The key's user may have been removed from the whitelist
+				if !s.cfg.IsAuthorized(key.UserID) {
+					writeAPIError(w, http.StatusForbidden, "user is no longer authorized")
+					return
+				}
+				if svc, _ := s.topupService(); svc == nil {
+					writeAPIError(w, http.StatusServiceUnavailable, "wallet is locked")
+					return
+				}
+				next(w, r, key)
+				return
+			}
+		}
+		writeAPIError(w, http.StatusUnauthorized, "invalid API key")
+	}
+}
+
+type apiSwapRequest struct {
+	Destination string  `json:"destination"`
+	AmountUSD   float64 `json:"amount_usd"`
+	Asset       string  `json:"asset"` // CHAIN.ASSET, e.g. "BTC.BTC"
+	Route       string  `json:"route"` // optional routing hint, as in the bot
+}
+
+func (r *apiSwapRequest) validate() error {
+	if r.Destination == "" {
+		return fmt.Errorf("destination is required")
+	}
+	if r.AmountUSD <= 0 {
+		return fmt.Errorf("amount_usd must be positive")
+	}
+	if r.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	return nil
+}
+
+// topupRequest validates a quote or topup request body. API clients act as
+// the DM of the key's Telegram user.
+func (s *Server) topupRequest(r *http.Request, key config.APIKeyConfig) (topups.Request, error) {
+	var body apiSwapRequest
+	if err := decodeJSON(r, &body); err != nil {
+		return topups.Request{}, err
+	}
+	asset, err := swaps.ParseAsset(body.Asset)
+	if err != nil {
+		return topups.Request{}, fmt.Errorf("invalid asset: %v", err)
+	}
+	// The bot resolves unknown tokens interactively; the API can't ask, so
+	// it takes tokens pinned in the registry or statically known
+	svc, swapMgr := s.topupService()
+	asset, pinned, err := svc.Registered(r.Context(), asset)
+	if err != nil {
+		return topups.Request{}, err
+	}
+	if !pinned && !swapMgr.IsStaticallyKnown(asset) {
+		return topups.Request{}, fmt.Errorf("unknown asset %s", asset)
+	}
+	var hint swaps.RoutingHint
+	if body.Route != "" {
+		var ok bool
+		if hint, ok = swaps.ParseRoutingHint(body.Route); !ok {
+			return topups.Request{}, fmt.Errorf("unknown route %q", body.Route)
+		}
+	}
+
+	return topups.Request{
+		Owner:       topups.Owner{UserID: key.UserID, ChatID: key.UserID, Private: true},
+		Asset:       asset,
+		Destination: body.Destination,
+		USDAmount:   body.AmountUSD,
+		Hint:        hint,
+	}, nil
+}
+
+type apiQuote struct {
+	QuoteID        int64   `json:"quote_id"`
+	Provider       string  `json:"provider"`
+	FromAsset      string  `json:"from_asset"`
+	FromChain      string  `json:"from_chain"`
+	ToAsset        string  `json:"to_asset"`
+	InputAmountUSD float64 `json:"input_amount_usd"`
+	InputAmount    string  `json:"input_amount"`
+	ExpectedOutput string  `json:"expected_output"`
+	Memo           string  `json:"memo,omitempty"`
+	Expiry         int64   `json:"expiry,omitempty"`
+	GasWarning     string  `json:"gas_warning,omitempty"`
+}
+
+// POST /api/v1/quotes
+func (s *Server) handleAPIQuote(w http.ResponseWriter, r *http.Request, key config.APIKeyConfig) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	req, err := s.topupRequest(r, key)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	svc, swapMgr := s.topupService()
+	quote, quoteID, err := svc.Quote(r.Context(), req)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, apiQuote{
+		QuoteID:        quoteID,
+		Provider:       quote.Provider,
+		FromAsset:      quote.FromAsset.String(),
+		FromChain:      quote.FromChain,
+		ToAsset:        quote.ToAsset.String(),
+		InputAmountUSD: quote.InputAmountUSD,
+		InputAmount:    quote.InputAmount.String(),
+		ExpectedOutput: quote.ExpectedOutput,
+		Memo:           quote.Memo,
+		Expiry:         quote.Expiry,
+		GasWarning:     swapMgr.GasWarning(r.Context(), quote),
+	})
+}
+
+type apiStatusEvent struct {
+	Status    string    `json:"status"`
+	RawStatus string    `json:"raw_status,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type apiTopup struct {
+	ID           string           `json:"id"`
+	Status       string           `json:"status"`
+	Provider     string           `json:"provider"`
+	FromChain    string           `json:"from_chain"`
+	TxHash       string           `json:"tx_hash"`
+	ExplorerURL  string           `json:"explorer_url"`
+	ExternalID   string           `json:"external_id,omitempty"`
+	FreshAddress string           `json:"fresh_address,omitempty"`
+	ActualOutput string           `json:"actual_output,omitempty"`
+	DestTxHash   string           `json:"dest_tx_hash,omitempty"`
+	RefundTxHash string           `json:"refund_tx_hash,omitempty"`
+	CreatedAt    *time.Time       `json:"created_at,omitempty"`
+	History      []apiStatusEvent `json:"history,omitempty"`
+}
+
+// apiApproval is a topup held for admin approval. Once executed, Result
+// holds the topup's ID.
+type apiApproval struct {
+	ID        int64     `json:"approval_id"`
+	Status    string    `json:"status"`
+	AmountUSD float64   `json:"amount_usd"`
+	Summary   string    `json:"summary"`
+	Result    string    `json:"result,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toAPIApproval(a db.Approval) apiApproval {
+	return apiApproval{
+		ID:        a.ID,
+		Status:    a.Status,
+		AmountUSD: a.AmountUsd,
+		Summary:   a.Summary,
+		Result:    a.Result,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// POST /api/v1/topups executes a swap and returns once it is broadcast; the
+// tracker follows it from there, like a /topup from the bot. Topups above
+// the approval threshold are queued instead, answered with 202 and an
+// approval to poll.
+func (s *Server) handleAPITopups(w http.ResponseWriter, r *http.Request, key config.APIKeyConfig) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	req, err := s.topupRequest(r, key)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("API: topup of $%.2f to %s %s by key %q", req.USDAmount, req.Asset, req.Destination, key.Name)
+
+	// A client hanging up must not abandon a half-executed swap
+	ctx := context.WithoutCancel(r.Context())
+	if appr := s.approvalService(); appr.Required(req.USDAmount, key.UserID) {
+		a, err := appr.RequestTopup(ctx, req, 0)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(toAPIApproval(a))
+		return
+	}
+	svc, _ := s.topupService()
+	result, err := svc.Execute(ctx, req, func(text string) {
+		log.Printf("API: %s", text)
+	})
+	var unsigned *wallet.UnsignedTxError
+	if errors.As(err, &unsigned) {
+		writeAPIError(w, http.StatusConflict, "watch-only wallet: topups must be signed offline via the bot")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	topup := apiTopup{
+		ID:          result.Topup.ShortID,
+		Status:      "pending",
+		Provider:    result.Quote.Provider,
+		FromChain:   result.Quote.FromChain,
+		TxHash:      result.Swap.TxHash,
+		ExplorerURL: s.cfg.ExplorerTxURL(result.Quote.FromChain, result.Swap.TxHash),
+		ExternalID:  result.Swap.ExternalID,
+	}
+	if result.Fresh != nil {
+		topup.FreshAddress = result.Fresh.Address
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(topup)
+}
+
+// GET /api/v1/topups/{id} returns a topup made with the key's user.
+func (s *Server) handleAPITopup(w http.ResponseWriter, r *http.Request, key config.APIKeyConfig) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	shortID := r.URL.Path[len("/api/v1/topups/"):]
+
+	row, err := s.store.GetTopupByShortID(r.Context(), shortID)
+	if err == sql.ErrNoRows || (err == nil && row.UserID != key.UserID) {
+		writeAPIError(w, http.StatusNotFound, "topup not found")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	topup := apiTopup{
+		ID:           row.ShortID,
+		Status:       row.Status,
+		Provider:     row.Provider,
+		FromChain:    row.FromChain,
+		TxHash:       row.TxHash,
+		ExplorerURL:  s.cfg.ExplorerTxURL(row.FromChain, row.TxHash),
+		ExternalID:   row.ExternalID,
+		ActualOutput: row.ActualOutput,
+		DestTxHash:   row.DestTxHash,
+		RefundTxHash: row.RefundTxHash,
+		CreatedAt:    &row.CreatedAt,
+	}
+	events, err := s.store.ListTopupStatusEvents(r.Context(), row.ID)
+	if err != nil {
+		log.Printf("API: error loading status history of %s: %v", row.ShortID, err)
+	}
+	for _, e := range events {
+		topup.History = append(topup.History, apiStatusEvent{Status: e.Status, RawStatus: e.RawStatus, CreatedAt: e.CreatedAt})
+	}
+	writeJSON(w, topup)
+}
+
+// GET /api/v1/approvals/{id} returns a topup approval requested with the
+// key's user.
+func (s *Server) handleAPIApproval(w http.ResponseWriter, r *http.Request, key config.APIKeyConfig) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/v1/approvals/"):], 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid approval ID")
+		return
+	}
+
+	a, err := s.store.GetApproval(r.Context(), id)
+	if err == sql.ErrNoRows || (err == nil && (a.UserID != key.UserID || a.Kind != "topup")) {
+		writeAPIError(w, http.StatusNotFound, "approval not found")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, toAPIApproval(a))
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{Error: msg})
+}
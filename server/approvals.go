@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/approvals"
+)
+
+// SetApprovals enables deciding on queued topups and gas refills from the
+// admin panel.
+func (s *Server) SetApprovals(a *approvals.Service) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.approvals = a
+}
+
+func (s *Server) approvalService() *approvals.Service {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.approvals
+}
+
+// GET /api/admin/approvals lists the topups and gas refills waiting for
+// approval, oldest first.
+func (s *Server) handleAdminApprovals(w http.ResponseWriter, r *http.Request) {
+	svc := s.approvalService()
+	if svc == nil {
+		writeJSON(w, []struct{}{})
+		return
+	}
+	pending, err := svc.ListPending(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, pending)
+}
+
+// POST /api/admin/approval-approve/{id} approves a queued request and runs
+// it, returning once it has been submitted or has failed.
+func (s *Server) handleAdminApprovalApprove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/approval-approve/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	svc := s.approvalService()
+	if svc == nil {
+		http.Error(w, "approvals are not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Closing the admin panel must not abandon a half-executed swap
+	ctx := context.WithoutCancel(r.Context())
+	admin := currentAdmin(r).Username
+	a, err := svc.Approve(ctx, id, "admin:"+admin)
+	if err != nil {
+		approvalActionError(w, err)
+		return
+	}
+	log.Printf("Admin %s approved request %d: %s", admin, a.ID, a.Status)
+	if err := s.audit(r, auditApprovalApprove, strconv.FormatInt(a.ID, 10), fmt.Sprintf("%s: %s %s", a.Summary, a.Status, a.Result)); err != nil {
+		log.Printf("Error recording approval of %d: %v", a.ID, err)
+	}
+	writeJSON(w, a)
+}
+
+// POST /api/admin/approval-reject/{id} rejects a queued request, with a
+// note passed on to the requester.
+func (s *Server) handleAdminApprovalReject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/approval-reject/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var req rejectRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc := s.approvalService()
+	if svc == nil {
+		http.Error(w, "approvals are not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	admin := currentAdmin(r).Username
+	a, err := svc.Reject(r.Context(), id, "admin:"+admin, req.Note)
+	if err != nil {
+		approvalActionError(w, err)
+		return
+	}
+	log.Printf("Admin %s rejected request %d", admin, a.ID)
+	if err := s.audit(r, auditApprovalReject, strconv.FormatInt(a.ID, 10), fmt.Sprintf("%s: %s", a.Summary, req.Note)); err != nil {
+		log.Printf("Error recording rejection of %d: %v", a.ID, err)
+	}
+	writeJSON(w, a)
+}
+
+// approvalActionError writes the response for a failed approval decision.
+func approvalActionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "approval not found", http.StatusNotFound)
+	case errors.Is(err, approvals.ErrStatus):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
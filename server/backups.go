@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/RaghavSood/fundbot/backup"
+)
+
+// SetBackups enables the backup status in the admin panel.
+func (s *Server) SetBackups(b *backup.Backuper) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.backups = b
+}
+
+func (s *Server) backuper() *backup.Backuper {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.backups
+}
+
+// handleAdminBackups reports the backup schedule, the latest run and the
+// snapshots on disk (GET), or takes a snapshot now (POST, superadmin).
+func (s *Server) handleAdminBackups(w http.ResponseWriter, r *http.Request) {
+	b := s.backuper()
+	if b == nil {
+		http.Error(w, "backups are not configured", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireRole(w, r, roleSuperadmin) {
+			return
+		}
+		// A snapshot of a large database can outlast the request
+		file, err := b.Backup(context.WithoutCancel(r.Context()))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Admin %s backed up the database to %s", currentAdmin(r).Username, file.Name)
+		if err := s.audit(r, auditBackup, file.Name, fmt.Sprintf("%d bytes", file.Size)); err != nil {
+			log.Printf("Error recording backup: %v", err)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := b.Status()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status)
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// RunBalanceHistory records a snapshot of every wallet's balances into
+// balances_history every balance_history interval_minutes, until ctx is
+// cancelled, and deletes snapshots beyond retention_days. Snapshots are
+// skipped while the wallet is locked. It returns at once without a
+// balance_history config.
+func (s *Server) RunBalanceHistory(ctx context.Context) {
+	cfg := s.cfg.BalanceHistory
+	if cfg == nil {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(cfg.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		if !s.locked() {
+			if taken, err := s.recordBalanceHistory(ctx, last); err != nil && ctx.Err() == nil {
+				log.Printf("Error recording balance history: %v", err)
+			} else if err == nil {
+				last = taken
+			}
+		}
+		if cfg.RetentionDays > 0 {
+			n, err := s.store.DeleteBalanceHistoryBefore(ctx, time.Now().UTC().AddDate(0, 0, -cfg.RetentionDays))
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Error pruning balance history: %v", err)
+			} else if n > 0 {
+				log.Printf("Pruned %d balance history rows", n)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			log.Println("Balance history stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordBalanceHistory stores the cached balances, reading them first if
+// they are older than balance_refresh_minutes, and returns when they were
+// read. A read already stored as last isn't stored again.
+func (s *Server) recordBalanceHistory(ctx context.Context, last time.Time) (time.Time, error) {
+	since := time.Now().Add(-time.Duration(s.cfg.BalanceRefreshMinutes) * time.Minute)
+	snap, err := s.refreshBalances(ctx, since)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if snap.UpdatedAt.Equal(last) {
+		return last, nil
+	}
+
+	var rows []db.InsertBalanceHistoryParams
+	for _, w := range snap.Wallets {
+		for chain, cb := range w.Chains {
+			row := db.InsertBalanceHistoryParams{
+				TakenAt:       snap.UpdatedAt,
+				WalletIndex:   int64(w.Index),
+				Address:       w.Address,
+				Chain:         chain,
+				NativeBalance: cb.Native,
+				Usd:           cb.USD,
+			}
+			if cb.NativeUSD != nil {
+				row.NativeUsd = *cb.NativeUSD
+			}
+			row.StableUsd = cb.USD - row.NativeUsd
+			rows = append(rows, row)
+		}
+	}
+	if err := s.store.InsertBalanceSnapshot(ctx, rows); err != nil {
+		return time.Time{}, err
+	}
+	return snap.UpdatedAt, nil
+}
+
+// GET /api/admin/balance-history?from=&to=&group=day|week|month returns the
+// USD held on each chain at the last snapshot of each period between from
+// and to (inclusive days, as for the charts), with the change from the
+// chain's previous period and its drawdown from the highest value so far.
+func (s *Server) handleAdminBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := dateRange(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	group := query.Get("group")
+	switch group {
+	case "":
+		group = "day"
+	case "day", "week", "month":
+	default:
+		http.Error(w, "group must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := s.store.BalanceHistoryByChain(r.Context(), db.BalanceHistoryByChainParams{TakenFrom: from, TakenTo: to})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, balanceHistoryResponse{
+		Group:           group,
+		BalanceByPeriod: balanceByPeriod(rows, group),
+	})
+}
+
+// balanceByPeriod keeps the last snapshot of each chain in each period,
+// ordered by period and chain, and fills in the change and drawdown.
+func balanceByPeriod(rows []db.BalanceHistoryByChainRow, group string) []periodBalance {
+	var series []periodBalance
+	index := make(map[[2]string]int)
+	for _, row := range rows {
+		b := periodBalance{
+			Period:    periodOf(row.TakenAt, group),
+			Chain:     row.Chain,
+			TakenAt:   row.TakenAt,
+			USD:       toFloat(row.TotalUsd),
+			NativeUSD: toFloat(row.NativeUsd),
+			StableUSD: toFloat(row.StableUsd),
+			Wallets:   row.WalletCount,
+		}
+		// Rows come in snapshot order, so a later one replaces the period's
+		key := [2]string{b.Period, b.Chain}
+		if i, ok := index[key]; ok {
+			series[i] = b
+			continue
+		}
+		index[key] = len(series)
+		series = append(series, b)
+	}
+	slices.SortFunc(series, func(a, b periodBalance) int {
+		return cmp.Or(cmp.Compare(a.Period, b.Period), cmp.Compare(a.Chain, b.Chain))
+	})
+
+	prev := make(map[string]float64)
+	peak := make(map[string]float64)
+	for i := range series {
+		b := &series[i]
+		if p, ok := prev[b.Chain]; ok {
+			b.Change = b.USD - p
+		}
+		prev[b.Chain] = b.USD
+		peak[b.Chain] = max(peak[b.Chain], b.USD)
+		b.Drawdown = peak[b.Chain] - b.USD
+	}
+	return series
+}
+
+// periodOf returns the first day of t's day, week (from Monday) or month,
+// as the charts' SQL buckets topups.
+func periodOf(t time.Time, group string) string {
+	t = t.UTC()
+	switch group {
+	case "week":
+		t = t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case "month":
+		t = t.AddDate(0, 0, 1-t.Day())
+	}
+	return t.Format(time.DateOnly)
+}
@@ -1,10 +1,214 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
 )
 
-// Re-export for use in server handlers.
-type AddressBalance = balances.AddressBalance
+// balanceCache holds the last read of every wallet's balances, so the admin
+// panel doesn't derive every address and multicall every chain on each view.
+type balanceCache struct {
+	// refresh serializes reads; mu guards snapshot
+	refresh  sync.Mutex
+	mu       sync.RWMutex
+	snapshot *balanceSnapshot
+}
+
+func (c *balanceCache) current() *balanceSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
+}
+
+// RunBalances refreshes the balance cache every balance_refresh_minutes
+// until ctx is cancelled, skipping refreshes while the wallet is locked.
+func (s *Server) RunBalances(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.cfg.BalanceRefreshMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if !s.locked() {
+			if _, err := s.refreshBalances(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				log.Printf("Error refreshing balances: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			log.Println("Balance refresher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshBalances reads every wallet's balances into the cache and
+// publishes them to admin sessions. A read that started after since, made
+// while waiting for another to finish, is returned instead of reading again.
+// On failure the cached balances are kept and the error is recorded beside
+// them.
+func (s *Server) refreshBalances(ctx context.Context, since time.Time) (*balanceSnapshot, error) {
+	c := &s.balances
+	c.refresh.Lock()
+	defer c.refresh.Unlock()
+
+	if snap := c.current(); snap != nil && snap.Error == "" && !snap.UpdatedAt.Before(since) {
+		return snap, nil
+	}
+
+	readAt := time.Now().UTC()
+	wallets, err := s.readBalances(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		if c.snapshot != nil {
+			stale := *c.snapshot
+			stale.Error = err.Error()
+			c.snapshot = &stale
+		}
+		return nil, err
+	}
+	c.snapshot = &balanceSnapshot{Wallets: wallets, ChainUSD: make(map[string]float64), UpdatedAt: readAt}
+	for _, bal := range wallets {
+		for chain, cb := range bal.Chains {
+			c.snapshot.ChainUSD[chain] += cb.USD
+		}
+		c.snapshot.USD += bal.USD
+		s.events.BalanceRefresh(bal)
+	}
+	return c.snapshot, nil
+}
+
+// readBalances fetches the balances of the wallets in use: the shared
+// wallet in single mode, or every address assignment in multi mode.
+func (s *Server) readBalances(ctx context.Context) ([]events.Balances, error) {
+	type addrInfo struct {
+		addr  common.Address
+		index uint32
+		owner string
+	}
+	var infos []addrInfo
+
+	if s.cfg.Mode == config.ModeSingle {
+		addr, err := s.wallets().Address(0)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, addrInfo{addr: addr, index: 0, owner: "Shared Wallet"})
+	} else {
+		users, _ := s.store.ListUsers(ctx)
+		userMap := make(map[int64]db.User)
+		for _, u := range users {
+			userMap[u.ID] = u
+		}
+		chats, _ := s.store.ListChats(ctx)
+		chatMap := make(map[int64]db.Chat)
+		for _, c := range chats {
+			chatMap[c.ID] = c
+		}
+
+		assignments, err := s.store.ListAddressAssignments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assignments {
+			addr, err := s.wallets().Address(uint32(a.ID))
+			if err != nil {
+				continue
+			}
+			owner := "Unknown"
+			switch a.AssignedToType {
+			case "user":
+				if u, ok := userMap[a.AssignedToID]; ok {
+					if u.Username != "" {
+						owner = u.Username
+					} else {
+						owner = fmt.Sprintf("User #%d", u.TelegramID)
+					}
+				}
+			case "chat":
+				if c, ok := chatMap[a.AssignedToID]; ok {
+					owner = c.Title
+				}
+			}
+			infos = append(infos, addrInfo{addr: addr, index: uint32(a.ID), owner: owner})
+		}
+	}
+
+	addresses := make([]common.Address, len(infos))
+	byAddr := make(map[common.Address]addrInfo, len(infos))
+	for i, info := range infos {
+		addresses[i] = info.addr
+		if _, ok := byAddr[info.addr]; !ok {
+			byAddr[info.addr] = info
+		}
+	}
+
+	bals, err := balances.FetchBalances(ctx, swaps.EnabledClients(s.rpcClients), addresses, thorchain.FundingTokens.Contracts(), balances.Watchlist(s.cfg))
+	if err != nil {
+		return nil, err
+	}
+	balances.Value(ctx, bals, swaps.NativePriceUSD, thorchain.FundingTokens.Decimals())
+
+	// One entry per address, in assignment order
+	wallets := balances.GroupByAddress(bals, addresses)
+	result := make([]events.Balances, 0, len(wallets))
+	for _, w := range wallets {
+		info := byAddr[w.Address]
+		eb := events.Balances{Address: w.Address.Hex(), Index: info.index, Owner: info.owner, Chains: make(map[string]events.ChainBalance)}
+		for _, b := range w.Chains {
+			eb.Add(b)
+		}
+		result = append(result, eb)
+	}
+	return result, nil
+}
+
+// GET /api/admin/balances returns the cached balances, reading them first
+// if nothing is cached yet.
+func (s *Server) handleAdminBalances(w http.ResponseWriter, r *http.Request) {
+	snap := s.balances.current()
+	if snap == nil {
+		var err error
+		// Cached for the refresher too, so a closed tab mustn't abandon it
+		if snap, err = s.refreshBalances(context.WithoutCancel(r.Context()), time.Time{}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+	if err := s.audit(r, auditBalancesView, "", fmt.Sprintf("%d wallets", len(snap.Wallets))); err != nil {
+		log.Printf("Error recording balances view: %v", err)
+	}
+	writeJSON(w, snap)
+}
 
-var FetchBalances = balances.FetchBalances
+// POST /api/admin/balances/refresh reads every wallet's balances now
+// instead of waiting for the background refresh.
+func (s *Server) handleAdminBalancesRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	snap, err := s.refreshBalances(context.WithoutCancel(r.Context()), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if err := s.audit(r, auditBalancesView, "", fmt.Sprintf("%d wallets, refreshed", len(snap.Wallets))); err != nil {
+		log.Printf("Error recording balances view: %v", err)
+	}
+	writeJSON(w, snap)
+}
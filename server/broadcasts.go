@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/broadcast"
+)
+
+// SetBroadcaster enables admin announcements.
+func (s *Server) SetBroadcaster(b *broadcast.Broadcaster) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.broadcaster = b
+}
+
+func (s *Server) announcer() *broadcast.Broadcaster {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.broadcaster
+}
+
+// POST /api/admin/broadcast queues an announcement to every known chat that
+// hasn't opted out. Delivery runs in the background; follow it with
+// /api/admin/broadcasts.
+func (s *Server) handleAdminBroadcast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req broadcastRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b := s.announcer()
+	if b == nil {
+		http.Error(w, "the bot is not running yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	id, err := b.Queue(r.Context(), req.Text)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin %s queued broadcast %d", currentAdmin(r).Username, id)
+	if err := s.audit(r, auditBroadcast, strconv.FormatInt(id, 10), req.Text); err != nil {
+		log.Printf("Error recording broadcast %d: %v", id, err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(broadcastQueued{ID: id})
+}
+
+// GET /api/admin/broadcasts lists recent announcements with their delivery
+// counts, newest first.
+func (s *Server) handleAdminBroadcasts(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.store.ListBroadcasts(r.Context(), 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows)
+}
+
+// GET /api/admin/broadcast-failures/{id} lists the chats an announcement
+// couldn't be delivered to.
+func (s *Server) handleAdminBroadcastFailures(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/broadcast-failures/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	rows, err := s.store.ListBroadcastFailures(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows)
+}
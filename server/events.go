@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// eventsKeepAlive is how often an idle event stream gets a comment, so
+// proxies don't time it out.
+const eventsKeepAlive = 30 * time.Second
+
+// GET /api/events streams live updates as Server-Sent Events: topup status
+// changes and new quotes to every dashboard, and balance refreshes to admin
+// sessions only.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	admin := isAdmin(r)
+
+	updates, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e := <-updates:
+			if e.Admin && !admin {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Printf("Error encoding %s event: %v", e.Type, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// defaultRefillUSDC is the USDC sold by an admin gas refill when the request
+// names no amount and the chain has no gas_refills refill_usd.
+const defaultRefillUSDC = 5.0
+
+// errWalletNotInUse is returned for wallet indexes no user, chat or the
+// shared wallet holds.
+var errWalletNotInUse = errors.New("wallet index is not in use")
+
+// walletInUse checks that index is the shared wallet in single mode, or an
+// address assignment in multi mode, and returns its address.
+func (s *Server) walletInUse(ctx context.Context, keyring wallet.Keyring, index uint32) (common.Address, error) {
+	if s.cfg.Mode == config.ModeSingle {
+		if index != 0 {
+			return common.Address{}, errWalletNotInUse
+		}
+	} else if _, err := s.store.GetAddressAssignmentByID(ctx, int64(index)); errors.Is(err, sql.ErrNoRows) {
+		return common.Address{}, errWalletNotInUse
+	} else if err != nil {
+		return common.Address{}, err
+	}
+	return keyring.Address(index)
+}
+
+// fundsSigner returns the signer of a wallet index for admin refills and
+// transfers, writing an error and returning nil if it can't sign here.
+func (s *Server) fundsSigner(w http.ResponseWriter, r *http.Request, index uint32, chain string) wallet.Signer {
+	rpc := s.rpcClients[chain]
+	if rpc == nil {
+		http.Error(w, fmt.Sprintf("no RPC endpoint for %s", chain), http.StatusBadRequest)
+		return nil
+	}
+	if !swaps.ChainEnabled(chain) {
+		http.Error(w, chain+" is disabled", http.StatusConflict)
+		return nil
+	}
+	keyring := s.wallets()
+	if wallet.WatchOnly(keyring) {
+		http.Error(w, "watch-only wallet: funds can only be moved with transactions signed offline", http.StatusConflict)
+		return nil
+	}
+	if _, err := s.walletInUse(r.Context(), keyring, index); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errWalletNotInUse) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return nil
+	}
+	signer, err := keyring.Signer(index)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	return signer
+}
+
+// usdcOf returns a wallet's USDC balance on chain.
+func usdcOf(ctx context.Context, rpc *ethclient.Client, chain string, usdc swaps.FundingToken, addr common.Address) (*big.Int, error) {
+	bals, err := balances.FetchBalances(ctx, map[string]*ethclient.Client{chain: rpc}, []common.Address{addr}, map[string][]common.Address{chain: {usdc.Address}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(bals) == 0 || len(bals[0].TokenBalances) == 0 {
+		return nil, fmt.Errorf("no USDC balance returned for %s", chain)
+	}
+	balance, ok := new(big.Int).SetString(bals[0].TokenBalances[0], 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid USDC balance %q", bals[0].TokenBalances[0])
+	}
+	return balance, nil
+}
+
+// POST /api/admin/refill sells USDC from a wallet for native gas on CoW,
+// whatever its gas balance. The order is tracked and resubmitted like the
+// refills /balance triggers.
+func (s *Server) handleAdminRefill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req refillRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cowClient, hooks := s.gasRefills()
+	if cowClient == nil {
+		http.Error(w, "gas refills are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+	usdc, ok := thorchain.FundingTokens.Lookup(req.Chain, "USDC")
+	if _, cow := cowswap.SupportedChains[req.Chain]; !cow || !ok || usdc.Native {
+		http.Error(w, fmt.Sprintf("gas refills are not supported on %s", req.Chain), http.StatusBadRequest)
+		return
+	}
+	if req.Amount == 0 {
+		req.Amount = defaultRefillUSDC
+		if refill, ok := s.cfg.GasRefill(req.Chain); ok {
+			req.Amount = refill.RefillUSD
+		}
+	}
+	index := *req.Index
+	signer := s.fundsSigner(w, r, index, req.Chain)
+	if signer == nil {
+		return
+	}
+	if _, ok := signer.(swaps.CallSender); ok {
+		http.Error(w, "smart accounts pay gas through the paymaster", http.StatusConflict)
+		return
+	}
+
+	// Closing the admin panel must not abandon an order being signed
+	ctx := context.WithoutCancel(r.Context())
+	amount := usdc.Amount(req.Amount)
+	balance, err := usdcOf(ctx, s.rpcClients[req.Chain], req.Chain, usdc, signer.Address())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if balance.Cmp(amount) < 0 {
+		http.Error(w, fmt.Sprintf("the wallet has %s USDC on %s", usdc.Format(balance), req.Chain), http.StatusConflict)
+		return
+	}
+
+	// The chain's guards still apply; only the amount and cap are the admin's
+	var guards cowswap.Guards
+	if refill, ok := s.cfg.GasRefill(req.Chain); ok {
+		guards = cowswap.Guards{SlippageBps: refill.SlippageBps, MaxFeeBps: refill.MaxFeeBps, MaxDeviationBps: refill.MaxDeviationBps}
+	}
+	result, err := cowClient.SellUSDC(ctx, req.Chain, signer, amount, cowswap.NativeToken, signer.Address(), guards)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	params := db.InsertGasRefillParams{
+		Chain:         result.Chain,
+		OrderUid:      result.OrderUID,
+		WalletAddress: signer.Address().Hex(),
+		SellAmount:    result.SellAmount,
+		BuyAmount:     result.BuyAmount,
+		Status:        "open",
+		WalletIndex:   int64(index),
+		Attempt:       1,
+		OrderType:     "market",
+		Kind:          result.Kind,
+		ValidTo:       sql.NullTime{Time: result.ValidTo, Valid: true},
+	}
+	id, err := s.store.InsertGasRefill(ctx, params)
+	if err != nil {
+		// The order is placed; say so rather than invite a second one
+		log.Printf("Error storing admin gas refill %s: %v", result.OrderUID, err)
+		http.Error(w, fmt.Sprintf("order %s was placed but could not be recorded: %v", result.OrderUID, err), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin %s refilled gas of index %d on %s with %s USDC (order %s)", currentAdmin(r).Username, index, req.Chain, usdc.Format(amount), result.OrderUID)
+	if err := s.audit(r, auditRefill, strconv.FormatInt(id, 10), fmt.Sprintf("index %d, %s USDC on %s, order %s", index, usdc.Format(amount), req.Chain, result.OrderUID)); err != nil {
+		log.Printf("Error recording gas refill %d: %v", id, err)
+	}
+	hooks.GasRefillStatus(webhooks.GasRefill{
+		ID:            id,
+		Status:        params.Status,
+		Chain:         params.Chain,
+		OrderUID:      params.OrderUid,
+		WalletAddress: params.WalletAddress,
+		SellAmount:    params.SellAmount,
+		BuyAmount:     params.BuyAmount,
+		Attempt:       params.Attempt,
+		OrderType:     params.OrderType,
+		Kind:          params.Kind,
+	})
+
+	refill, err := s.store.GetGasRefill(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, refill)
+}
+
+// POST /api/admin/transfer sends USDC from a wallet to another wallet in use
+// or the sweep treasury. Other destinations need the superadmin role.
+func (s *Server) handleAdminTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req transferRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	usdc, ok := thorchain.FundingTokens.Lookup(req.Chain, "USDC")
+	if !ok || usdc.Native {
+		http.Error(w, fmt.Sprintf("no USDC on %s", req.Chain), http.StatusBadRequest)
+		return
+	}
+	if req.Amount == 0 {
+		req.Amount = defaultRefillUSDC
+		if refill, ok := s.cfg.GasRefill(req.Chain); ok {
+			req.Amount = refill.RefillUSD
+		}
+	}
+	index := *req.Index
+	signer := s.fundsSigner(w, r, index, req.Chain)
+	if signer == nil {
+		return
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	var to common.Address
+	switch {
+	case req.ToIndex != nil:
+		addr, err := s.walletInUse(ctx, s.wallets(), *req.ToIndex)
+		if errors.Is(err, errWalletNotInUse) {
+			http.Error(w, fmt.Sprintf("to_index: %v", err), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		to = addr
+	case s.cfg.Sweep != nil && strings.EqualFold(req.To, s.cfg.Sweep.Treasury):
+		to = common.HexToAddress(s.cfg.Sweep.Treasury)
+	default:
+		if !requireRole(w, r, roleSuperadmin) {
+			return
+		}
+		to = common.HexToAddress(req.To)
+	}
+	if to == signer.Address() {
+		http.Error(w, "the destination is the sending wallet", http.StatusBadRequest)
+		return
+	}
+
+	rpc := s.rpcClients[req.Chain]
+	amount := usdc.Amount(req.Amount)
+	balance, err := usdcOf(ctx, rpc, req.Chain, usdc, signer.Address())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if balance.Cmp(amount) < 0 {
+		http.Error(w, fmt.Sprintf("the wallet has %s USDC on %s", usdc.Format(balance), req.Chain), http.StatusConflict)
+		return
+	}
+
+	txHash, err := swaps.TransferERC20(ctx, rpc, req.Chain, signer, usdc.Address, to, amount)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	log.Printf("Admin %s transferred %s USDC on %s from index %d to %s: %s", currentAdmin(r).Username, usdc.Format(amount), req.Chain, index, to.Hex(), txHash)
+	if err := s.audit(r, auditTransfer, strconv.FormatUint(uint64(index), 10), fmt.Sprintf("%s USDC on %s to %s, tx %s", usdc.Format(amount), req.Chain, to.Hex(), txHash)); err != nil {
+		log.Printf("Error recording transfer %s: %v", txHash, err)
+	}
+	writeJSON(w, transferResult{
+		Index:       index,
+		Chain:       req.Chain,
+		From:        signer.Address().Hex(),
+		To:          to.Hex(),
+		Amount:      usdc.Format(amount),
+		TxHash:      txHash,
+		ExplorerURL: s.cfg.ExplorerTxURL(req.Chain, txHash),
+	})
+}
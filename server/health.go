@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+const (
+	// healthTimeout bounds each dependency check
+	healthTimeout = 5 * time.Second
+
+	// readyCacheTTL is how long /readyz reuses its last result, so frequent
+	// probes don't hammer RPCs and provider APIs
+	readyCacheTTL = 30 * time.Second
+
+	// maxBlockAge is how old an RPC's latest block may be before the RPC
+	// counts as stale
+	maxBlockAge = 5 * time.Minute
+)
+
+// check is the result of one dependency check.
+type check struct {
+	Status    string `json:"status"` // "ok" or "fail"
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// healthReport is the body of /healthz and /readyz. Status is "ok", "fail",
+// or "degraded" when only provider APIs are unreachable.
+type healthReport struct {
+	Status    string           `json:"status"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Checks    map[string]check `json:"checks"`
+}
+
+// readyCache holds the last /readyz report.
+type readyCache struct {
+	mu     sync.Mutex
+	report *healthReport
+}
+
+// GET /healthz: liveness. Only the database is checked, so a slow RPC or
+// provider can't get the process restarted.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	report := &healthReport{
+		CheckedAt: time.Now().UTC(),
+		Checks:    map[string]check{"db": timeCheck(r.Context(), s.checkDB)},
+	}
+	report.Status = report.Checks["db"].Status
+	writeHealth(w, report)
+}
+
+// GET /readyz: readiness. Checks the database, the wallet, every enabled
+// chain's RPC (chain ID and latest block age) and every provider API.
+// Unreachable providers only degrade the report since quotes fall back to
+// the others; anything else failing makes it 503.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.ready.mu.Lock()
+	defer s.ready.mu.Unlock()
+	if s.ready.report == nil || time.Since(s.ready.report.CheckedAt) > readyCacheTTL {
+		// Cached for other probes too, so one probe hanging up mustn't fail it
+		s.ready.report = s.readiness(context.WithoutCancel(r.Context()))
+	}
+	writeHealth(w, s.ready.report)
+}
+
+func (s *Server) readiness(ctx context.Context) *healthReport {
+	checks := map[string]func(context.Context) (string, error){
+		"db": s.checkDB,
+		"wallet": func(context.Context) (string, error) {
+			if s.locked() {
+				return "", fmt.Errorf("wallet is locked")
+			}
+			return "unlocked", nil
+		},
+	}
+	for chain, rpc := range swaps.EnabledClients(s.rpcClients) {
+		checks["rpc:"+chain] = func(ctx context.Context) (string, error) {
+			return checkRPC(ctx, chain, rpc)
+		}
+	}
+	if _, swapMgr := s.topupService(); swapMgr != nil {
+		for name, endpoint := range swapMgr.Endpoints() {
+			checks["provider:"+name] = func(ctx context.Context) (string, error) {
+				return checkEndpoint(ctx, endpoint)
+			}
+		}
+	}
+
+	report := &healthReport{Status: "ok", CheckedAt: time.Now().UTC(), Checks: make(map[string]check)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fn := range checks {
+		wg.Go(func() {
+			c := timeCheck(ctx, fn)
+			mu.Lock()
+			report.Checks[name] = c
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	for name, c := range report.Checks {
+		if c.Status == "ok" {
+			continue
+		}
+		if strings.HasPrefix(name, "provider:") {
+			if report.Status == "ok" {
+				report.Status = "degraded"
+			}
+			continue
+		}
+		report.Status = "fail"
+	}
+	return report
+}
+
+// timeCheck runs fn with the check timeout and records how long it took.
+func timeCheck(ctx context.Context, fn func(context.Context) (string, error)) check {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := fn(ctx)
+	c := check{Status: "ok", Detail: detail, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		c.Status = "fail"
+		c.Error = err.Error()
+	}
+	return c
+}
+
+func (s *Server) checkDB(ctx context.Context) (string, error) {
+	return "", s.store.Ping(ctx)
+}
+
+// checkRPC verifies an RPC serves the chain it is configured for and is
+// keeping up with it.
+func checkRPC(ctx context.Context, chain string, rpc *ethclient.Client) (string, error) {
+	chainID, err := rpc.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("chain ID: %w", err)
+	}
+	if want, ok := swaps.ChainIDs[chain]; ok && chainID.Int64() != want {
+		return "", fmt.Errorf("chain ID %s, expected %d", chainID, want)
+	}
+
+	head, err := rpc.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("latest block: %w", err)
+	}
+	age := time.Since(time.Unix(int64(head.Time), 0)).Truncate(time.Second)
+	detail := fmt.Sprintf("chain %s, block %s, %s old", chainID, head.Number, age)
+	if age > maxBlockAge {
+		return detail, fmt.Errorf("latest block is %s old", age)
+	}
+	return detail, nil
+}
+
+// checkEndpoint checks that a provider API answers. Any response below 500
+// counts, since base URLs rarely serve a 200 themselves.
+func checkEndpoint(ctx context.Context, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode), nil
+}
+
+func writeHealth(w http.ResponseWriter, report *healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == "fail" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
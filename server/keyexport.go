@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// exportWindow is the period key_export.max_per_hour counts attempts over.
+const exportWindow = time.Hour
+
+// SetBotAPI lets the server message the admin on Telegram, for key export
+// notifications.
+func (s *Server) SetBotAPI(botAPI *tgbotapi.BotAPI) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.botAPI = botAPI
+}
+
+// notifyAdmin DMs admin_user_id, if the bot is running. The text is sent
+// plain, since it carries admin-entered reasons.
+func (s *Server) notifyAdmin(text string) {
+	s.lockMu.RLock()
+	botAPI := s.botAPI
+	s.lockMu.RUnlock()
+	if botAPI == nil {
+		log.Printf("Not notifying admin, the bot is not running yet: %s", text)
+		return
+	}
+	msg := tgbotapi.NewMessage(s.cfg.AdminUserID, text)
+	msg.DisableWebPagePreview = true
+	if _, err := botAPI.Send(msg); err != nil {
+		log.Printf("Error notifying admin: %v", err)
+	}
+}
+
+// allowExport counts a key export attempt by an admin account against
+// key_export.max_per_hour. When the limit is reached it returns false and
+// how long until the oldest attempt leaves the window.
+func (s *Server) allowExport(adminID int64) (bool, time.Duration) {
+	s.exportMu.Lock()
+	defer s.exportMu.Unlock()
+
+	now := time.Now()
+	var recent []time.Time
+	for _, t := range s.exportAttempts[adminID] {
+		if now.Sub(t) < exportWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= s.cfg.KeyExport.MaxPerHour {
+		s.exportAttempts[adminID] = recent
+		return false, exportWindow - now.Sub(recent[0])
+	}
+	s.exportAttempts[adminID] = append(recent, now)
+	return true, 0
+}
+
+// checkAdminPassword reports whether password is the current password of
+// the admin account id.
+func (s *Server) checkAdminPassword(ctx context.Context, id int64, password string) bool {
+	admin, err := s.store.GetAdmin(ctx, id)
+	if err != nil {
+		log.Printf("Error loading admin %d: %v", id, err)
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password)) == nil
+}
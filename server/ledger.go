@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/thorchain"
+)
+
+// assetDecimals returns the decimals of a ledger asset: a funding token's,
+// or 18 for native coins.
+func assetDecimals(chain, asset string) int {
+	if t, ok := thorchain.FundingTokens.Lookup(chain, asset); ok {
+		return t.Decimals
+	}
+	return 18
+}
+
+// GET /api/admin/ledger lists ledger entries, newest first, optionally
+// filtered by account (address), chain and kind.
+func (s *Server) handleAdminLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	limit, _ := strconv.ParseInt(query.Get("limit"), 10, 64)
+	offset, _ := strconv.ParseInt(query.Get("offset"), 10, 64)
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	rows, err := s.store.ListLedgerEntries(ctx, db.ListLedgerEntriesParams{
+		Account:    query.Get("account"),
+		Chain:      query.Get("chain"),
+		Kind:       query.Get("kind"),
+		PageSize:   limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	total, _ := s.store.CountLedgerEntries(ctx, db.CountLedgerEntriesParams{
+		Account: query.Get("account"),
+		Chain:   query.Get("chain"),
+		Kind:    query.Get("kind"),
+	})
+
+	page := ledgerPage{Rows: make([]ledgerEntry, len(rows)), Total: total}
+	for i, e := range rows {
+		page.Rows[i] = ledgerEntry{LedgerEntry: e, Decimals: assetDecimals(e.Chain, e.Asset)}
+	}
+	writeJSON(w, page)
+}
+
+// GET /api/admin/reconciliation returns the latest comparison of every
+// wallet's ledger balance with its on-chain balance, discrepancies first.
+func (s *Server) handleAdminReconciliation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rows, err := s.store.ListLedgerReconciliations(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	discrepancies, err := s.store.CountLedgerDiscrepancies(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	report := reconciliationReport{
+		Enabled:       s.cfg.Reconcile != nil,
+		Discrepancies: discrepancies,
+		Rows:          make([]reconciliation, len(rows)),
+	}
+	for i, row := range rows {
+		report.Rows[i] = reconciliation{LedgerReconciliation: row, Decimals: assetDecimals(row.Chain, row.Asset)}
+	}
+	writeJSON(w, report)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"io/fs"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// withForwarding applies the X-Forwarded-For and X-Forwarded-Proto headers
+// of requests from trusted_proxies: RemoteAddr becomes the client's address,
+// so the audit log records it, and the proto decides secure cookies. Other
+// requests have the headers removed, so handlers can't be fooled by clients
+// setting them.
+func (s *Server) withForwarding(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer, err := netip.ParseAddrPort(r.RemoteAddr)
+		if err != nil || !s.cfg.TrustedProxy(peer.Addr()) {
+			r.Header.Del("X-Forwarded-For")
+			r.Header.Del("X-Forwarded-Proto")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if client, ok := s.forwardedClient(r.Header.Values("X-Forwarded-For")); ok {
+			r.RemoteAddr = netip.AddrPortFrom(client, 0).String()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedClient walks X-Forwarded-For from the nearest hop back and returns
+// the first address that isn't a trusted proxy, or the farthest one if all
+// are. Addresses further back could have been made up by the client.
+func (s *Server) forwardedClient(headers []string) (netip.Addr, bool) {
+	var hops []string
+	for _, h := range headers {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !s.cfg.TrustedProxy(client) {
+			break
+		}
+	}
+	return client, client.IsValid()
+}
+
+// secure reports whether the client reached the server over HTTPS, directly
+// or through a trusted proxy, so session cookies can be marked Secure.
+func (s *Server) secure(r *http.Request) bool {
+	return s.cfg.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// url prefixes a root-relative path with base_path, for redirects and
+// cookies.
+func (s *Server) url(path string) string {
+	return s.cfg.BasePath + path
+}
+
+// sessionCookie returns a session cookie scoped to base_path.
+func (s *Server) sessionCookie(r *http.Request, name, value string) *http.Cookie {
+	path := s.cfg.BasePath
+	if path == "" {
+		path = "/"
+	}
+	return &http.Cookie{Name: name, Value: value, Path: path, HttpOnly: true, Secure: s.secure(r), SameSite: http.SameSiteStrictMode}
+}
+
+// withBasePath serves mux under base_path. Requests are accepted with or
+// without the prefix, so the proxy may pass it through or strip it.
+func (s *Server) withBasePath(mux http.Handler) http.Handler {
+	base := s.cfg.BasePath
+	if base == "" {
+		return mux
+	}
+	root := http.NewServeMux()
+	root.Handle(base+"/", http.StripPrefix(base, mux))
+	root.Handle(base, http.RedirectHandler(base+"/", http.StatusMovedPermanently))
+	root.Handle("/", mux)
+	return root
+}
+
+// pageRewriter points the root-relative links of the HTML pages and their
+// base-path meta tag, which their scripts prefix API calls with, at
+// base_path. Nil when serving from the root.
+func (s *Server) pageRewriter() *strings.Replacer {
+	base := s.cfg.BasePath
+	if base == "" {
+		return nil
+	}
+	return strings.NewReplacer(
+		`<meta name="base-path" content="">`, `<meta name="base-path" content="`+base+`">`,
+		`href="/`, `href="`+base+`/`,
+		`action="/`, `action="`+base+`/`,
+	)
+}
+
+// servePage serves one of the HTML pages in static/, rewritten for
+// base_path.
+func servePage(w http.ResponseWriter, r *http.Request, static fs.FS, rewrite *strings.Replacer, name string) {
+	if rewrite == nil {
+		http.ServeFileFS(w, r, static, name)
+		return
+	}
+	page, err := fs.ReadFile(static, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rewrite.WriteString(w, string(page))
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// SetReloader enables POST /api/admin/config-reload with a function that
+// reloads the config and returns the changed keys that need a restart.
+func (s *Server) SetReloader(reload func() ([]string, error)) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.reload = reload
+}
+
+func (s *Server) reloader() func() ([]string, error) {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.reload
+}
+
+type configReloadResult struct {
+	// Changed keys that only take effect on restart
+	RestartRequired []string `json:"restart_required"`
+}
+
+// POST /api/admin/config-reload re-reads the config file, as SIGHUP does.
+// An invalid file is refused with 422 and the running config is kept.
+func (s *Server) handleAdminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reload := s.reloader()
+	if reload == nil {
+		http.Error(w, "config reload is not available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	restart, err := reload()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	detail := ""
+	if len(restart) > 0 {
+		detail = "restart required for " + strings.Join(restart, ", ")
+	}
+	log.Printf("Admin %s reloaded the config", currentAdmin(r).Username)
+	if err := s.audit(r, auditConfigReload, "", detail); err != nil {
+		log.Printf("Error recording config reload: %v", err)
+	}
+	if restart == nil {
+		restart = []string{}
+	}
+	writeJSON(w, configReloadResult{RestartRequired: restart})
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/RaghavSood/fundbot/resolver"
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// SetResolver enables the token resolver's status and test lookups in the
+// admin panel.
+func (s *Server) SetResolver(res *resolver.Resolver) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.resolver = res
+}
+
+func (s *Server) tokenResolver() *resolver.Resolver {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.resolver
+}
+
+// handleAdminResolver reports the resolver's cache hit rates, private
+// provider currency lists and CoinGecko keys (GET), or refetches the
+// currency lists now (POST, operator).
+func (s *Server) handleAdminResolver(w http.ResponseWriter, r *http.Request) {
+	res := s.tokenResolver()
+	if res == nil {
+		http.Error(w, "token resolver is not configured", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireRole(w, r, roleOperator) {
+			return
+		}
+		res.RefreshPrivateProviders(r.Context())
+		log.Printf("Admin %s refreshed the resolver's currency lists", currentAdmin(r).Username)
+		if err := s.audit(r, auditResolverRefresh, "", ""); err != nil {
+			log.Printf("Error recording resolver refresh: %v", err)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	st := res.Stats()
+	out := resolverStatus{
+		Caches:               make([]resolverCache, 0, len(st.Caches)),
+		Matchers:             make([]resolverMatcher, 0, len(st.Matchers)),
+		CoinGeckoKeys:        st.CoinGeckoKeys,
+		CoinGeckoKeysResting: st.CoinGeckoKeysResting,
+		CoinGeckoPro:         st.CoinGeckoPro,
+	}
+	for _, c := range st.Caches {
+		out.Caches = append(out.Caches, resolverCache{
+			Name:      c.Name,
+			Entries:   c.Entries,
+			Hits:      c.Hits,
+			StoreHits: c.StoreHits,
+			Misses:    c.Misses,
+			Errors:    c.Errors,
+			HitRate:   c.HitRate(),
+		})
+	}
+	for _, m := range st.Matchers {
+		entry := resolverMatcher{Provider: m.Provider, Currencies: m.Currencies, Error: m.Error}
+		if !m.RefreshedAt.IsZero() {
+			entry.RefreshedAt = &m.RefreshedAt
+		}
+		out.Matchers = append(out.Matchers, entry)
+	}
+	writeJSON(w, out)
+}
+
+// POST /api/admin/resolver/resolve looks an asset up as the bot would,
+// without quoting: the candidates it would offer and the resolution of the
+// first. Failures are reported in the body, not the status.
+func (s *Server) handleAdminResolverResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res := s.tokenResolver()
+	if res == nil {
+		http.Error(w, "token resolver is not configured", http.StatusNotFound)
+		return
+	}
+	var req resolveTestRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	asset, err := swaps.ParseAsset(req.Asset)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid asset: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	out := resolveTestResult{Asset: asset.String(), Candidates: []resolveCandidate{}}
+	var resolution *resolver.Resolution
+	if asset.ContractOnly() {
+		resolution, err = res.ResolveContract(r.Context(), asset)
+	} else {
+		var candidates []resolver.Candidate
+		if candidates, err = res.Candidates(r.Context(), asset); err == nil {
+			for _, c := range candidates {
+				out.Candidates = append(out.Candidates, resolveCandidate{
+					CoinGeckoID:     c.CoinGeckoID,
+					Name:            c.Name,
+					Symbol:          c.Symbol,
+					MarketCapRank:   c.MarketCapRank,
+					MarketCapUSD:    c.MarketCapUSD,
+					LiquidityUSD:    c.LiquidityUSD,
+					ContractAddress: c.ContractAddress,
+					Chains:          c.Chains,
+				})
+			}
+			resolution, err = res.ResolveCandidate(r.Context(), asset, candidates[0])
+		}
+	}
+	if err != nil {
+		out.Error = err.Error()
+		writeJSON(w, out)
+		return
+	}
+
+	result := &resolveResult{
+		Source:          resolution.Source,
+		CoinGeckoID:     resolution.CoinGeckoID,
+		Name:            resolution.Name,
+		Symbol:          resolution.Symbol,
+		ContractAddress: resolution.ContractAddress,
+		Providers:       make([]resolveProvider, 0, len(resolution.Providers)),
+		MarketCapUSD:    resolution.MarketCapUSD,
+		VolumeUSD:       resolution.VolumeUSD,
+		LiquidityUSD:    resolution.LiquidityUSD,
+		Warnings:        resolution.Warnings,
+	}
+	for _, pm := range resolution.Providers {
+		result.Providers = append(result.Providers, resolveProvider{Provider: pm.Provider, AssetID: pm.AssetID})
+	}
+	out.Resolution = result
+	writeJSON(w, out)
+}
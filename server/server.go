@@ -1,27 +1,47 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
+	"database/sql"
 	"embed"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/RaghavSood/fundbot/apilog"
+	"github.com/RaghavSood/fundbot/approvals"
+	"github.com/RaghavSood/fundbot/backup"
+	"github.com/RaghavSood/fundbot/broadcast"
 	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
 	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/resolver"
+	"github.com/RaghavSood/fundbot/rpcpool"
+	"github.com/RaghavSood/fundbot/swaps"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/topups"
 	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
 )
 
 //go:embed static
@@ -30,22 +50,118 @@ var staticFiles embed.FS
 // session tokens (in-memory)
 var (
 	sessionMu     sync.RWMutex
-	adminSessions = map[string]bool{}
+	adminSessions = map[string]adminSession{}
 	dashSessions  = map[string]bool{}
+	userSessions  = map[string]userSession{}
 )
 
 type Server struct {
 	cfg        *config.Config
 	store      *db.Store
 	rpcClients map[string]*ethclient.Client
+	rpcPools   map[string]*rpcpool.Pool
+	httpServer *http.Server
+	events     *events.Bus
+
+	// redirectServer sends plain HTTP to HTTPS when tls.http_port is set
+	redirectServer *http.Server
+
+	// closing is closed on shutdown to end open event streams
+	closing chan struct{}
+
+	ready  readyCache
+	status statusCache
+
+	// balances caches wallet balances for the admin panel
+	balances balanceCache
+
+	// unlock is set while the mnemonic keystore is still locked; keyring is
+	// set once wallet keys are available
+	lockMu  sync.RWMutex
+	unlock  func(passphrase string) error
+	keyring wallet.Keyring
+
+	// topups and swapMgr back the REST API once the wallet is available
+	topups  *topups.Service
+	swapMgr *swaps.Manager
+
+	// cowClient and hooks let the admin cancel open gas refill orders
+	cowClient *cowswap.Client
+	hooks     *webhooks.Client
+
+	// approvals holds topups and gas refills above the approval threshold
+	approvals *approvals.Service
+
+	// broadcaster sends admin announcements once the bot is running
+	broadcaster *broadcast.Broadcaster
+
+	// backups takes the scheduled database snapshots, if configured
+	backups *backup.Backuper
+
+	// resolver is the bot's token resolver, if enabled
+	resolver *resolver.Resolver
+
+	// reload re-reads the config file once the swap providers are set up
+	reload func() ([]string, error)
+
+	// adminsMu serializes admin account changes, so the last superadmin
+	// can't be removed by two requests at once
+	adminsMu sync.Mutex
+
+	// botAPI notifies the admin of key exports and names the bot for the
+	// Telegram login once the bot is running
+	botAPI *tgbotapi.BotAPI
+
+	// exportAttempts holds each admin account's key export attempts in the
+	// last hour, for key_export.max_per_hour
+	exportMu       sync.Mutex
+	exportAttempts map[int64][]time.Time
 }
 
-func New(cfg *config.Config, store *db.Store, rpcClients map[string]*ethclient.Client) *Server {
-	return &Server{
+func New(cfg *config.Config, store *db.Store, rpcClients map[string]*ethclient.Client, bus *events.Bus) *Server {
+	s := &Server{
 		cfg:        cfg,
 		store:      store,
 		rpcClients: rpcClients,
+		httpServer: &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port)},
+		events:     bus,
+		closing:    make(chan struct{}),
+
+		exportAttempts: make(map[int64][]time.Time),
+	}
+	s.httpServer.RegisterOnShutdown(func() { close(s.closing) })
+	if cfg.TLS != nil && cfg.TLS.HTTPPort != 0 {
+		s.redirectServer = &http.Server{Addr: fmt.Sprintf(":%d", cfg.TLS.HTTPPort)}
 	}
+	return s
+}
+
+// SetUnlocker puts the server in locked mode: wallet endpoints return 503
+// until POST /api/admin/unlock calls unlock with a passphrase it accepts.
+func (s *Server) SetUnlocker(unlock func(passphrase string) error) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.unlock = unlock
+}
+
+// SetKeyring provides the wallets for address lookups. Wallet endpoints
+// return 503 until it is set.
+func (s *Server) SetKeyring(keyring wallet.Keyring) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	s.keyring = keyring
+}
+
+func (s *Server) locked() bool {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.unlock != nil || s.keyring == nil
+}
+
+func (s *Server) wallets() wallet.Keyring {
+	s.lockMu.RLock()
+	defer s.lockMu.RUnlock()
+	return s.keyring
 }
 
 func (s *Server) Start() error {
@@ -54,6 +170,12 @@ func (s *Server) Start() error {
 	// Static files
 	staticSub, _ := fs.Sub(staticFiles, "static")
 	fileServer := http.FileServer(http.FS(staticSub))
+	rewrite := s.pageRewriter()
+	page := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			servePage(w, r, staticSub, rewrite, name)
+		}
+	}
 
 	// Home page and static files
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -61,34 +183,111 @@ func (s *Server) Start() error {
 			fileServer.ServeHTTP(w, r)
 			return
 		}
-		http.ServeFileFS(w, r, staticSub, "index.html")
+		servePage(w, r, staticSub, rewrite, "index.html")
 	})
-	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFileFS(w, r, staticSub, "docs.html")
+	mux.HandleFunc("/docs", page("docs.html"))
+	// Health checks for load balancers and uptime monitors, unauthenticated
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.BasePath == "" {
+			http.ServeFileFS(w, r, staticSub, "openapi.json")
+			return
+		}
+		doc, _ := fs.ReadFile(staticSub, "openapi.json")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(bytes.Replace(doc, []byte(`"servers": [{ "url": "/" }]`), []byte(`"servers": [{ "url": "`+s.cfg.BasePath+`" }]`), 1))
 	})
 	mux.HandleFunc("/api/dashboard", s.withDashAuth(s.handleDashboardAPI))
 	mux.HandleFunc("/api/charts", s.withDashAuth(s.handleChartsAPI))
+	mux.HandleFunc("/api/events", s.withDashAuth(s.handleEvents))
 
 	// Dashboard login
 	mux.HandleFunc("/login", s.handleDashLogin)
 
 	// Admin routes
-	mux.HandleFunc("/admin", s.withAdminAuth(func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFileFS(w, r, staticSub, "admin.html")
-	}))
+	mux.HandleFunc("/admin", s.withAdminAuth(page("admin.html")))
 	mux.HandleFunc("/admin/login", s.handleAdminLogin)
+	mux.HandleFunc("/api/admin/me", s.withAdminAuth(s.handleAdminMe))
 	mux.HandleFunc("/api/admin/topups", s.withAdminAuth(s.handleAdminTopups))
-	mux.HandleFunc("/api/admin/users", s.withAdminAuth(s.handleAdminUsers))
-	mux.HandleFunc("/api/admin/user/", s.withAdminAuth(s.handleAdminUserDetail))
-	mux.HandleFunc("/api/admin/balances", s.withAdminAuth(s.handleAdminBalances))
-	mux.HandleFunc("/api/admin/export-key", s.withAdminAuth(s.handleExportKey))
+	mux.HandleFunc("/api/admin/stalled", s.withAdminAuth(s.handleAdminStalled))
+	mux.HandleFunc("/api/admin/topup-events/", s.withAdminAuth(s.handleAdminTopupEvents))
+	mux.HandleFunc("/api/admin/topup-txs/", s.withAdminAuth(s.handleAdminTopupTransactions))
+	mux.HandleFunc("/api/admin/quote-raw/", s.withAdminAuth(s.handleAdminQuoteRaw))
+	mux.HandleFunc("/api/admin/export", s.withAdminAuth(s.handleAdminExport))
+	mux.HandleFunc("/api/admin/topup-resolve/", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminTopupResolve)))
+	mux.HandleFunc("/api/admin/topup-retry/", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminTopupRetry)))
+	mux.HandleFunc("/api/admin/gas-refills", s.withAdminAuth(s.handleAdminGasRefills))
+	mux.HandleFunc("/api/admin/gas-refill-cancel/", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminGasRefillCancel)))
+	mux.HandleFunc("/api/admin/approvals", s.withAdminAuth(s.handleAdminApprovals))
+	mux.HandleFunc("/api/admin/approval-approve/", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminApprovalApprove)))
+	mux.HandleFunc("/api/admin/approval-reject/", s.withAdminRole(roleOperator, s.handleAdminApprovalReject))
+	mux.HandleFunc("/api/admin/refill", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminRefill)))
+	mux.HandleFunc("/api/admin/transfer", s.withAdminRole(roleOperator, s.withUnlocked(s.handleAdminTransfer)))
+	mux.HandleFunc("/api/admin/broadcast", s.withAdminRole(roleOperator, s.handleAdminBroadcast))
+	mux.HandleFunc("/api/admin/broadcasts", s.withAdminAuth(s.handleAdminBroadcasts))
+	mux.HandleFunc("/api/admin/broadcast-failures/", s.withAdminAuth(s.handleAdminBroadcastFailures))
+	mux.HandleFunc("/api/admin/users", s.withAdminAuth(s.withUnlocked(s.handleAdminUsers)))
+	mux.HandleFunc("/api/admin/user/", s.withAdminAuth(s.withUnlocked(s.handleAdminUserDetail)))
+	mux.HandleFunc("/api/admin/user-wipe/", s.withAdminRole(roleSuperadmin, s.handleAdminUserWipe))
+	mux.HandleFunc("/api/admin/balances", s.withAdminAuth(s.withUnlocked(s.handleAdminBalances)))
+	mux.HandleFunc("/api/admin/balances/refresh", s.withAdminAuth(s.withUnlocked(s.handleAdminBalancesRefresh)))
+	mux.HandleFunc("/api/admin/balance-history", s.withAdminAuth(s.handleAdminBalanceHistory))
+	mux.HandleFunc("/api/admin/export-key", s.withAdminRole(roleSuperadmin, s.withUnlocked(s.handleExportKey)))
+	mux.HandleFunc("/api/admin/chains", s.withAdminAuth(s.handleAdminChains))
+	mux.HandleFunc("/api/admin/unlock", s.withAdminAuth(s.handleAdminUnlock))
 	mux.HandleFunc("/api/admin/api-logs", s.withAdminAuth(s.handleAdminAPILogs))
+	mux.HandleFunc("/api/admin/api-logs/purge", s.withAdminRole(roleSuperadmin, s.handleAdminAPILogPurge))
 	mux.HandleFunc("/api/admin/api-log/", s.withAdminAuth(s.handleAdminAPILogDetail))
+	mux.HandleFunc("/api/admin/audit-log", s.withAdminAuth(s.handleAdminAuditLog))
+	mux.HandleFunc("/api/admin/backups", s.withAdminAuth(s.handleAdminBackups))
+	mux.HandleFunc("/api/admin/config-reload", s.withAdminRole(roleSuperadmin, s.handleAdminConfigReload))
+	mux.HandleFunc("/api/admin/ledger", s.withAdminAuth(s.handleAdminLedger))
+	mux.HandleFunc("/api/admin/reconciliation", s.withAdminAuth(s.handleAdminReconciliation))
+	mux.HandleFunc("/api/admin/tokens", s.withAdminAuth(s.handleAdminTokens))
+	mux.HandleFunc("/api/admin/tokens/", s.withAdminRole(roleOperator, s.handleAdminToken))
+	mux.HandleFunc("/api/admin/resolver", s.withAdminAuth(s.handleAdminResolver))
+	mux.HandleFunc("/api/admin/resolver/resolve", s.withAdminRole(roleOperator, s.handleAdminResolverResolve))
+	mux.HandleFunc("/api/admin/whitelist", s.withAdminAuth(s.handleAdminWhitelist))
+	mux.HandleFunc("/api/admin/whitelist/", s.withAdminRole(roleSuperadmin, s.handleAdminWhitelistUser))
+	mux.HandleFunc("/api/admin/admins", s.withAdminRole(roleSuperadmin, s.handleAdminAccounts))
+	mux.HandleFunc("/api/admin/admin/", s.withAdminRole(roleSuperadmin, s.handleAdminAccount))
 	mux.HandleFunc("/api/explorers", s.withDashAuth(s.handleExplorers))
 
-	addr := fmt.Sprintf(":%d", s.cfg.Port)
-	log.Printf("HTTP server listening on %s", addr)
-	return http.ListenAndServe(addr, mux)
+	// Per-user dashboard, signed in with Telegram
+	if s.cfg.UserDashboard {
+		mux.HandleFunc("/me", page("me.html"))
+		mux.HandleFunc("/me/login", s.handleUserLogin)
+		mux.HandleFunc("/me/logout", s.handleUserLogout)
+		mux.HandleFunc("/api/me", s.withUserAuth(s.withUnlocked(s.handleUserProfile)))
+		mux.HandleFunc("/api/me/balances", s.withUserAuth(s.withUnlocked(s.handleUserBalances)))
+		mux.HandleFunc("/api/me/login-config", s.handleUserLoginConfig)
+	}
+
+	// REST API
+	if len(s.cfg.APIKeys) > 0 {
+		mux.HandleFunc("/api/v1/quotes", s.withAPIKey(s.handleAPIQuote))
+		mux.HandleFunc("/api/v1/topups", s.withAPIKey(s.handleAPITopups))
+		mux.HandleFunc("/api/v1/topups/", s.withAPIKey(s.handleAPITopup))
+		mux.HandleFunc("/api/v1/approvals/", s.withAPIKey(s.handleAPIApproval))
+	}
+
+	s.httpServer.Handler = s.withForwarding(s.withBasePath(mux))
+	if s.cfg.TLS != nil {
+		return s.serveTLS()
+	}
+	log.Printf("HTTP server listening on %s", s.httpServer.Addr)
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown stops the HTTP server, waiting for open requests to finish until
+// ctx is done. Start returns http.ErrServerClosed afterwards.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.shutdownRedirect(ctx); err != nil {
+		log.Printf("Error stopping HTTP redirect server: %v", err)
+	}
+	return s.httpServer.Shutdown(ctx)
 }
 
 // --- Auth helpers ---
@@ -111,32 +310,57 @@ func (s *Server) withDashAuth(next http.HandlerFunc) http.HandlerFunc {
 		}
 		cookie, err := r.Cookie("dash_session")
 		if err != nil {
-			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			http.Redirect(w, r, s.url("/login"), http.StatusSeeOther)
 			return
 		}
 		sessionMu.RLock()
 		valid := dashSessions[cookie.Value]
 		sessionMu.RUnlock()
 		if !valid {
-			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			http.Redirect(w, r, s.url("/login"), http.StatusSeeOther)
 			return
 		}
 		next(w, r)
 	}
 }
 
+// withAdminAuth lets through requests with an admin session of any role,
+// making the account available to next via currentAdmin.
 func (s *Server) withAdminAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("admin_session")
-		if err != nil {
-			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		admin, ok := adminFromCookie(r)
+		if !ok {
+			http.Redirect(w, r, s.url("/admin/login"), http.StatusSeeOther)
 			return
 		}
-		sessionMu.RLock()
-		valid := adminSessions[cookie.Value]
-		sessionMu.RUnlock()
-		if !valid {
-			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		next(w, r.WithContext(context.WithValue(r.Context(), adminContextKey{}, admin)))
+	}
+}
+
+// adminFromCookie returns the account of r's admin session, if it has a
+// valid one.
+func adminFromCookie(r *http.Request) (adminSession, bool) {
+	cookie, err := r.Cookie("admin_session")
+	if err != nil {
+		return adminSession{}, false
+	}
+	sessionMu.RLock()
+	defer sessionMu.RUnlock()
+	admin, ok := adminSessions[cookie.Value]
+	return admin, ok
+}
+
+// isAdmin reports whether r carries a valid admin session.
+func isAdmin(r *http.Request) bool {
+	_, ok := adminFromCookie(r)
+	return ok
+}
+
+// withUnlocked rejects requests that need the wallet while it is locked.
+func (s *Server) withUnlocked(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.locked() {
+			http.Error(w, "wallet is locked", http.StatusServiceUnavailable)
 			return
 		}
 		next(w, r)
@@ -146,7 +370,7 @@ func (s *Server) withAdminAuth(next http.HandlerFunc) http.HandlerFunc {
 func (s *Server) handleDashLogin(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodGet {
 		staticSub, _ := fs.Sub(staticFiles, "static")
-		http.ServeFileFS(w, r, staticSub, "login.html")
+		servePage(w, r, staticSub, s.pageRewriter(), "login.html")
 		return
 	}
 	if r.Method != http.MethodPost {
@@ -158,21 +382,21 @@ func (s *Server) handleDashLogin(w http.ResponseWriter, r *http.Request) {
 	expected := hashPassword(s.cfg.DashboardPassword)
 	got := hashPassword(pw)
 	if subtle.ConstantTimeCompare(expected[:], got[:]) != 1 {
-		http.Redirect(w, r, "/login?error=1", http.StatusSeeOther)
+		http.Redirect(w, r, s.url("/login?error=1"), http.StatusSeeOther)
 		return
 	}
 	token := generateToken()
 	sessionMu.Lock()
 	dashSessions[token] = true
 	sessionMu.Unlock()
-	http.SetCookie(w, &http.Cookie{Name: "dash_session", Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
-	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+	http.SetCookie(w, s.sessionCookie(r, "dash_session", token))
+	http.Redirect(w, r, s.url("/dashboard"), http.StatusSeeOther)
 }
 
 func (s *Server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodGet {
 		staticSub, _ := fs.Sub(staticFiles, "static")
-		http.ServeFileFS(w, r, staticSub, "login.html")
+		servePage(w, r, staticSub, s.pageRewriter(), "login.html")
 		return
 	}
 	if r.Method != http.MethodPost {
@@ -180,19 +404,18 @@ func (s *Server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	r.ParseForm()
-	pw := r.FormValue("password")
-	expected := hashPassword(s.cfg.AdminPassword)
-	got := hashPassword(pw)
-	if subtle.ConstantTimeCompare(expected[:], got[:]) != 1 {
-		http.Redirect(w, r, "/admin/login?error=1", http.StatusSeeOther)
+	admin, ok := s.adminLogin(r.Context(), r.FormValue("username"), r.FormValue("password"))
+	if !ok {
+		http.Redirect(w, r, s.url("/admin/login?error=1"), http.StatusSeeOther)
 		return
 	}
+	log.Printf("Admin %s (%s) signed in", admin.Username, admin.Role)
 	token := generateToken()
 	sessionMu.Lock()
-	adminSessions[token] = true
+	adminSessions[token] = admin
 	sessionMu.Unlock()
-	http.SetCookie(w, &http.Cookie{Name: "admin_session", Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
-	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	http.SetCookie(w, s.sessionCookie(r, "admin_session", token))
+	http.Redirect(w, r, s.url("/admin"), http.StatusSeeOther)
 }
 
 // --- API handlers ---
@@ -201,28 +424,75 @@ func (s *Server) handleDashboardAPI(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	users, _ := s.store.CountUsers(ctx)
 	topups, _ := s.store.CountTopups(ctx)
+	refunded, _ := s.store.CountRefundedTopups(ctx)
 	volume, _ := s.store.TotalVolumeUSD(ctx)
 	pairs, _ := s.store.CountDistinctPairs(ctx)
 	providers, _ := s.store.CountDistinctProviders(ctx)
 
-	writeJSON(w, map[string]interface{}{
-		"users":     users,
-		"topups":    topups,
-		"volume":    volume,
-		"pairs":     pairs,
-		"providers": providers,
+	writeJSON(w, dashboardStats{
+		Users:     users,
+		Topups:    topups,
+		Refunded:  refunded,
+		Volume:    toFloat(volume),
+		Pairs:     pairs,
+		Providers: providers,
 	})
 }
 
+// topupSorts are the orders handleAdminTopups accepts; "" is newest first.
+var topupSorts = map[string]bool{"": true, "oldest": true, "usd_desc": true, "usd_asc": true}
+
+// handleAdminTopups lists topups, newest first unless sort says otherwise,
+// optionally filtered by status, provider, chain, user (Telegram ID) and a
+// from/to date range.
 func (s *Server) handleAdminTopups(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
-	offset, _ := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	query := r.URL.Query()
+	limit, _ := strconv.ParseInt(query.Get("limit"), 10, 64)
+	offset, _ := strconv.ParseInt(query.Get("offset"), 10, 64)
 	if limit <= 0 || limit > 100 {
 		limit = 50
 	}
 
-	topups, err := s.store.ListRecentTopups(ctx, db.ListRecentTopupsParams{Limit: limit, Offset: offset})
+	var userID int64
+	if v := query.Get("user"); v != "" {
+		var err error
+		if userID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, "invalid user ID", http.StatusBadRequest)
+			return
+		}
+	}
+	order := query.Get("sort")
+	if !topupSorts[order] {
+		http.Error(w, fmt.Sprintf("unknown sort %q", order), http.StatusBadRequest)
+		return
+	}
+	from, to, err := dateRange(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topups, err := s.store.ListRecentTopups(ctx, db.ListRecentTopupsParams{
+		Status:      query.Get("status"),
+		Provider:    query.Get("provider"),
+		FromChain:   query.Get("chain"),
+		UserID:      userID,
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Sort:        order,
+		PageSize:    limit,
+		PageOffset:  offset,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, topups)
+}
+
+func (s *Server) handleAdminStalled(w http.ResponseWriter, r *http.Request) {
+	topups, err := s.store.ListStalledTopups(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -230,6 +500,65 @@ func (s *Server) handleAdminTopups(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, topups)
 }
 
+func (s *Server) handleAdminTopupEvents(w http.ResponseWriter, r *http.Request) {
+	idStr := r.URL.Path[len("/api/admin/topup-events/"):]
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	events, err := s.store.ListTopupStatusEvents(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, events)
+}
+
+func (s *Server) handleAdminTopupTransactions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.URL.Path[len("/api/admin/topup-txs/"):]
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	txs, err := s.store.ListTopupTransactions(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, txs)
+}
+
+// handleAdminQuoteRaw returns the provider responses stored with a quote,
+// as the JSON array apilog.Capture builds. X-Truncated is set when bodies
+// were cut to fit.
+func (s *Server) handleAdminQuoteRaw(w http.ResponseWriter, r *http.Request) {
+	idStr := r.URL.Path[len("/api/admin/quote-raw/"):]
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	payload, truncated, err := s.store.LoadQuoteRaw(r.Context(), id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "no provider response stored for this quote", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if truncated {
+		w.Header().Set("X-Truncated", "true")
+	}
+	w.Write(payload)
+}
+
 func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	users, err := s.store.ListUsers(ctx)
@@ -238,12 +567,6 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	type userWithAddr struct {
-		db.User
-		Address string `json:"address"`
-		Index   uint32 `json:"index"`
-	}
-
 	// Build lookup maps for users and chats
 	userMap := make(map[int64]db.User)
 	for _, u := range users {
@@ -261,7 +584,7 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 
 	var result []userWithAddr
 	if s.cfg.Mode == config.ModeSingle {
-		addr, _ := wallet.DeriveAddress(s.cfg.Mnemonic, 0)
+		addr, _ := s.wallets().Address(0)
 		result = append(result, userWithAddr{
 			User:    db.User{ID: 0, Username: "(shared wallet)"},
 			Address: addr.Hex(),
@@ -278,7 +601,7 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 		}
 		for _, a := range assignments {
 			idx := uint32(a.ID)
-			addr, _ := wallet.DeriveAddress(s.cfg.Mnemonic, idx)
+			addr, _ := s.wallets().Address(idx)
 			var user db.User
 			switch a.AssignedToType {
 			case "user":
@@ -294,7 +617,7 @@ func (s *Server) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 					user = db.User{ID: a.AssignedToID, Username: "(unknown chat)"}
 				}
 			}
-			result = append(result, userWithAddr{User: user, Address: addr.Hex(), Index: idx})
+			result = append(result, userWithAddr{User: user, Address: addr.Hex(), Index: idx, Pool: a.Pool})
 		}
 	}
 
@@ -319,137 +642,100 @@ func (s *Server) handleAdminUserDetail(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, topups)
 }
 
-func (s *Server) handleAdminBalances(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	type addrInfo struct {
-		addr  common.Address
-		owner string
+// POST /api/admin/user-wipe/{telegram_id} anonymizes a user, for erasure
+// requests. Their topups and wallet stay on the books under a tombstone.
+func (s *Server) handleAdminUserWipe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	telegramID, err := strconv.ParseInt(r.URL.Path[len("/api/admin/user-wipe/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if telegramID == s.cfg.AdminUserID {
+		http.Error(w, "the admin user can't be wiped", http.StatusConflict)
+		return
 	}
-	var infos []addrInfo
 
+	user, err := s.store.WipeUser(r.Context(), telegramID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	case errors.Is(err, db.ErrUserBusy):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	endUserSessions(telegramID)
 	if s.cfg.Mode == config.ModeSingle {
-		addr, err := wallet.DeriveAddress(s.cfg.Mnemonic, 0)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		infos = append(infos, addrInfo{addr: addr, owner: "Shared Wallet"})
-	} else {
-		users, _ := s.store.ListUsers(ctx)
-		userMap := make(map[int64]db.User)
-		for _, u := range users {
-			userMap[u.ID] = u
-		}
-		chats, _ := s.store.ListChats(ctx)
-		chatMap := make(map[int64]db.Chat)
-		for _, c := range chats {
-			chatMap[c.ID] = c
-		}
-
-		assignments, err := s.store.ListAddressAssignments(ctx)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		for _, a := range assignments {
-			addr, err := wallet.DeriveAddress(s.cfg.Mnemonic, uint32(a.ID))
-			if err != nil {
-				continue
-			}
-			owner := "Unknown"
-			switch a.AssignedToType {
-			case "user":
-				if u, ok := userMap[a.AssignedToID]; ok {
-					if u.Username != "" {
-						owner = u.Username
-					} else {
-						owner = fmt.Sprintf("User #%d", u.TelegramID)
-					}
-				}
-			case "chat":
-				if c, ok := chatMap[a.AssignedToID]; ok {
-					owner = c.Title
-				}
-			}
-			infos = append(infos, addrInfo{addr: addr, owner: owner})
-		}
+		s.cfg.SetWhitelisted(telegramID, false)
 	}
 
-	addresses := make([]common.Address, len(infos))
-	for i, info := range infos {
-		addresses[i] = info.addr
+	// The audit entry names the user by row ID, so it doesn't keep the
+	// Telegram ID the wipe removed
+	admin := currentAdmin(r)
+	log.Printf("Admin %s wiped user %d", admin.Username, user.ID)
+	if err := s.audit(r, auditUserWipe, strconv.FormatInt(user.ID, 10), ""); err != nil {
+		log.Printf("Error recording wipe of user %d: %v", user.ID, err)
 	}
+	writeJSON(w, user)
+}
 
-	balances, err := FetchBalances(ctx, s.rpcClients, addresses, thorchain.USDCContracts)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+func (s *Server) handleExportKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Build owner lookup
-	ownerByAddr := make(map[string]string)
-	for _, info := range infos {
-		ownerByAddr[info.addr.Hex()] = info.owner
+	var req exportKeyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	index := *req.Index
+	admin := currentAdmin(r)
 
-	// Group balances by address
-	type groupedBalance struct {
-		Address       string `json:"address"`
-		Owner         string `json:"owner"`
-		AvaxNative    string `json:"avax_native"`
-		AvaxUSDC      string `json:"avax_usdc"`
-		BaseNative    string `json:"base_native"`
-		BaseUSDC      string `json:"base_usdc"`
-	}
-	grouped := make(map[string]*groupedBalance)
-	// Ensure order matches input
-	var orderedAddrs []string
-	for _, info := range infos {
-		hex := info.addr.Hex()
-		if _, ok := grouped[hex]; !ok {
-			orderedAddrs = append(orderedAddrs, hex)
-			grouped[hex] = &groupedBalance{Address: hex, Owner: ownerByAddr[hex], AvaxNative: "0", AvaxUSDC: "0", BaseNative: "0", BaseUSDC: "0"}
-		}
+	// Wrong passwords count towards the limit too, so a stolen session
+	// can't guess its way to the keys
+	if ok, wait := s.allowExport(admin.ID); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		http.Error(w, fmt.Sprintf("too many key exports; try again in %s", wait.Round(time.Minute)), http.StatusTooManyRequests)
+		return
 	}
-	for _, b := range balances {
-		g, ok := grouped[b.Address]
-		if !ok {
-			continue
-		}
-		switch b.Chain {
-		case "avalanche":
-			g.AvaxNative = b.NativeBalance
-			g.AvaxUSDC = b.USDCBalance
-		case "base":
-			g.BaseNative = b.NativeBalance
-			g.BaseUSDC = b.USDCBalance
+	if !s.checkAdminPassword(r.Context(), admin.ID, req.Password) {
+		log.Printf("Admin %s key export of index %d refused: wrong password", admin.Username, index)
+		if err := s.audit(r, auditKeyExportDenied, strconv.FormatUint(uint64(index), 10), "wrong password"); err != nil {
+			log.Printf("Error recording refused key export: %v", err)
 		}
+		http.Error(w, "wrong password", http.StatusForbidden)
+		return
 	}
 
-	result := make([]groupedBalance, 0, len(orderedAddrs))
-	for _, addr := range orderedAddrs {
-		result = append(result, *grouped[addr])
-	}
-
-	writeJSON(w, result)
-}
-
-func (s *Server) handleExportKey(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	pool, err := s.store.WalletPool(r.Context(), index)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var req struct {
-		Index uint32 `json:"index"`
+	// Only a local mnemonic can be exported; remote signers and KMS keep their keys
+	mnemonic := s.cfg.Mnemonic
+	if pool != wallet.DefaultPool {
+		mnemonic = s.cfg.WalletPools[pool].Mnemonic
+	} else if _, ok := s.cfg.KMSKeys[index]; ok {
+		http.Error(w, fmt.Sprintf("index %d signs via KMS and has no exportable key", index), http.StatusBadRequest)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request", http.StatusBadRequest)
+	if mnemonic == "" {
+		http.Error(w, "key export needs a local mnemonic", http.StatusBadRequest)
 		return
 	}
 
-	key, err := wallet.DeriveKey(s.cfg.Mnemonic, req.Index)
+	key, err := wallet.DeriveKey(mnemonic, index)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("error deriving key: %v", err), http.StatusInternalServerError)
 		return
@@ -457,14 +743,106 @@ func (s *Server) handleExportKey(w http.ResponseWriter, r *http.Request) {
 
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	privHex := hex.EncodeToString(crypto.FromECDSA(key))
+	if err := s.audit(r, auditKeyExport, strconv.FormatUint(uint64(index), 10), fmt.Sprintf("pool %s, %s: %s", pool, addr.Hex(), req.Reason)); err != nil {
+		log.Printf("Error recording key export of index %d: %v", index, err)
+		http.Error(w, "the export could not be recorded in the audit log", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin %s exported the key of index %d (pool %s, %s): %s", admin.Username, index, pool, addr.Hex(), req.Reason)
+	if s.cfg.KeyExport.NotifyAdmin {
+		go s.notifyAdmin(fmt.Sprintf("🔑 Private key exported from the admin panel\n\nIndex: %d (pool %s)\nAddress: %s\nBy: %s from %s\nReason: %s",
+			index, pool, addr.Hex(), admin.Username, sourceIP(r), req.Reason))
+	}
 
-	writeJSON(w, map[string]string{
-		"index":       fmt.Sprintf("%d", req.Index),
-		"address":     addr.Hex(),
-		"private_key": privHex,
+	writeJSON(w, exportKeyResponse{
+		Index:      index,
+		Pool:       pool,
+		Address:    addr.Hex(),
+		PrivateKey: privHex,
 	})
 }
 
+// handleAdminUnlock reports whether the wallet is locked (GET) or unlocks the
+// mnemonic keystore (POST {"passphrase": "..."}).
+func (s *Server) handleAdminUnlock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireRole(w, r, roleOperator) {
+			return
+		}
+		var req unlockRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.lockMu.Lock()
+		if s.unlock != nil {
+			if err := s.unlock(req.Passphrase); err != nil {
+				s.lockMu.Unlock()
+				log.Printf("Admin %s unlock failed: %v", currentAdmin(r).Username, err)
+				http.Error(w, "unlock failed", http.StatusForbidden)
+				return
+			}
+			s.unlock = nil
+			log.Printf("Wallet unlocked via admin panel by %s", currentAdmin(r).Username)
+			if err := s.audit(r, auditUnlock, "", ""); err != nil {
+				log.Printf("Error recording unlock: %v", err)
+			}
+		}
+		s.lockMu.Unlock()
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.lockMu.RLock()
+	locked := s.unlock != nil
+	s.lockMu.RUnlock()
+
+	writeJSON(w, lockState{Locked: locked})
+}
+
+// handleAdminChains lists configured chains with their enabled state (GET) or
+// switches one on or off at runtime (POST {"chain": "...", "enabled": bool}).
+// Runtime changes are not persisted; disabled_chains in the config sets the
+// state at startup.
+func (s *Server) handleAdminChains(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireRole(w, r, roleOperator) {
+			return
+		}
+		var req chainToggleRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.rpcClients[req.Chain]; !ok {
+			http.Error(w, fmt.Sprintf("unknown chain %q", req.Chain), http.StatusBadRequest)
+			return
+		}
+		swaps.SetChainEnabled(req.Chain, *req.Enabled)
+		log.Printf("Admin %s set chain %s enabled=%v", currentAdmin(r).Username, req.Chain, *req.Enabled)
+		if err := s.audit(r, auditChainToggle, req.Chain, fmt.Sprintf("enabled=%v", *req.Enabled)); err != nil {
+			log.Printf("Error recording chain toggle: %v", err)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	chains := make([]chainState, 0, len(s.rpcClients))
+	for chain := range s.rpcClients {
+		chains = append(chains, chainState{Chain: chain, Enabled: swaps.ChainEnabled(chain)})
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Chain < chains[j].Chain })
+
+	writeJSON(w, chains)
+}
+
 func (s *Server) handleExplorers(w http.ResponseWriter, r *http.Request) {
 	// Return explorer base URLs for all known chains
 	explorers := make(map[string]string)
@@ -476,21 +854,85 @@ func (s *Server) handleExplorers(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, explorers)
 }
 
+// GET /api/charts?from=&to=&group=day|week|month breaks volume and gas
+// down for topups created between from and to (inclusive days, as for the
+// CSV export), with the time series bucketed by group.
 func (s *Server) handleChartsAPI(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, err := dateRange(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	group := query.Get("group")
+	switch group {
+	case "":
+		group = "day"
+	case "day", "week", "month":
+	default:
+		http.Error(w, "group must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
-	byAsset, _ := s.store.VolumeByToAsset(ctx)
-	byChain, _ := s.store.VolumeByFromChain(ctx)
-	byDay, _ := s.store.VolumeByDay(ctx)
-	byProvider, _ := s.store.VolumeByProvider(ctx)
-
-	writeJSON(w, map[string]interface{}{
-		"volume_by_asset":    byAsset,
-		"volume_by_chain":    byChain,
-		"volume_by_day":      byDay,
-		"volume_by_provider": byProvider,
+	byAsset, _ := s.store.VolumeByToAsset(ctx, db.VolumeByToAssetParams{CreatedFrom: from, CreatedTo: to})
+	byChain, _ := s.store.VolumeByFromChain(ctx, db.VolumeByFromChainParams{CreatedFrom: from, CreatedTo: to})
+	byPeriod, _ := s.store.VolumeByPeriod(ctx, db.VolumeByPeriodParams{Grouping: group, CreatedFrom: from, CreatedTo: to})
+	byProvider, _ := s.store.VolumeByProvider(ctx, db.VolumeByProviderParams{CreatedFrom: from, CreatedTo: to})
+	gas, _ := s.store.GasCostByChain(ctx, db.GasCostByChainParams{CreatedFrom: from, CreatedTo: to})
+	fees, _ := s.store.FeesByPeriod(ctx, db.FeesByPeriodParams{Grouping: group, CreatedFrom: from, CreatedTo: to})
+	surplus, _ := s.store.SurplusByChain(ctx, db.SurplusByChainParams{CreatedFrom: from, CreatedTo: to})
+
+	gasByChain := make([]chainGas, 0, len(gas))
+	for _, g := range gas {
+		gasByChain = append(gasByChain, gasIn(g.Chain, g.TotalWei, g.TxCount))
+	}
+	feesByPeriod := make([]periodGas, 0, len(fees))
+	for _, f := range fees {
+		feesByPeriod = append(feesByPeriod, periodGas{Period: f.Period, chainGas: gasIn(f.Chain, f.TotalWei, f.TxCount)})
+	}
+	surplusByChain := make([]chainSurplus, 0, len(surplus))
+	for _, sp := range surplus {
+		surplusByChain = append(surplusByChain, surplusIn(sp.Chain, sp.Kind, sp.TotalSurplus, sp.OrderCount))
+	}
+
+	writeJSON(w, chartsResponse{
+		Group:            group,
+		VolumeByAsset:    byAsset,
+		VolumeByChain:    byChain,
+		VolumeByPeriod:   byPeriod,
+		VolumeByProvider: byProvider,
+		GasByChain:       gasByChain,
+		FeesByPeriod:     feesByPeriod,
+		SurplusByChain:   surplusByChain,
 	})
 }
 
+// gasIn converts gas summed in wei to the chain's native coin for display.
+func gasIn(chain string, totalWei interface{}, txCount int64) chainGas {
+	wei := toFloat(totalWei)
+	row := chainGas{Chain: chain, Symbol: strings.ToUpper(chain), Amount: wei / 1e18, TxCount: txCount}
+	if native, ok := thorchain.NativeToken(chain); ok {
+		row.Symbol = native.Symbol
+		row.Amount = wei / math.Pow10(native.Decimals)
+	}
+	return row
+}
+
+// surplusIn converts gas refill surplus summed in smallest units to the
+// token it was paid in: USDC for buy orders, the native coin otherwise.
+func surplusIn(chain, kind string, total interface{}, orders int64) chainSurplus {
+	if kind != "buy" {
+		g := gasIn(chain, total, orders)
+		return chainSurplus{Chain: g.Chain, Symbol: g.Symbol, Amount: g.Amount, Orders: orders}
+	}
+	row := chainSurplus{Chain: chain, Symbol: "USDC", Amount: toFloat(total) / 1e6, Orders: orders}
+	if usdc, ok := thorchain.FundingTokens.Lookup(chain, "USDC"); ok {
+		row.Amount = toFloat(total) / math.Pow10(usdc.Decimals)
+	}
+	return row
+}
+
 func (s *Server) handleAdminAPILogs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
@@ -512,10 +954,7 @@ func (s *Server) handleAdminAPILogs(w http.ResponseWriter, r *http.Request) {
 
 	total, _ := s.store.CountAPIRequests(ctx, search)
 
-	writeJSON(w, map[string]interface{}{
-		"rows":  rows,
-		"total": total,
-	})
+	writeJSON(w, apiLogPage{Rows: rows, Total: total})
 }
 
 func (s *Server) handleAdminAPILogDetail(w http.ResponseWriter, r *http.Request) {
@@ -534,6 +973,63 @@ func (s *Server) handleAdminAPILogDetail(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, row)
 }
 
+// POST /api/admin/api-logs/purge deletes logged provider calls older than
+// a number of days, or applies the api_log retention limits now.
+func (s *Server) handleAdminAPILogPurge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req apiLogPurgeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var deleted int64
+	var detail string
+	var err error
+	if req.OlderThanDays != nil {
+		detail = fmt.Sprintf("older than %d days", *req.OlderThanDays)
+		deleted, err = s.store.DeleteAPIRequestsBefore(r.Context(), time.Now().UTC().AddDate(0, 0, -*req.OlderThanDays))
+	} else {
+		if s.cfg.APILog.RetentionDays == 0 && s.cfg.APILog.MaxRows == 0 {
+			http.Error(w, "no api_log retention is configured; give older_than_days", http.StatusBadRequest)
+			return
+		}
+		detail = fmt.Sprintf("retention %d days, %d rows", s.cfg.APILog.RetentionDays, s.cfg.APILog.MaxRows)
+		deleted, err = apilog.Prune(r.Context(), s.store, s.cfg.APILog)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin %s purged %d logged API calls (%s)", currentAdmin(r).Username, deleted, detail)
+	if err := s.audit(r, auditAPILogPurge, "", fmt.Sprintf("%s: %d deleted", detail, deleted)); err != nil {
+		log.Printf("Error recording API log purge: %v", err)
+	}
+	writeJSON(w, apiLogPurged{Deleted: deleted})
+}
+
+// dateRange parses the from and to query parameters, days in UTC formatted
+// YYYY-MM-DD, into a half-open range covering both days. Either can be
+// left out for an open end.
+func dateRange(query url.Values) (from, to time.Time, err error) {
+	to = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if v := query.Get("from"); v != "" {
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			return from, to, fmt.Errorf("invalid from date, want YYYY-MM-DD")
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if to, err = time.Parse(time.DateOnly, v); err != nil {
+			return from, to, fmt.Errorf("invalid to date, want YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
 func writeJSON(w http.ResponseWriter, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(v)
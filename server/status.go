@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/RaghavSood/fundbot/rpcpool"
+)
+
+const (
+	// statusWindow is the rolling window /api/status rates calls over
+	statusWindow = time.Hour
+
+	// statusCacheTTL is how long /api/status reuses its last report
+	statusCacheTTL = 30 * time.Second
+
+	// degradedErrorRate is the share of failed calls in the window at which
+	// a provider counts as degraded
+	degradedErrorRate = 0.25
+)
+
+// Provider states in /api/status. fundbot has no circuit breaker that stops
+// calling a provider; the state is read from the logged calls.
+const (
+	providerOK       = "ok"
+	providerDegraded = "degraded"
+	providerDown     = "down" // every call in the window failed
+	providerIdle     = "idle" // no calls in the window
+)
+
+// statusCache holds the last /api/status report.
+type statusCache struct {
+	mu     sync.Mutex
+	report *statusReport
+}
+
+// GET /api/status reports, per logged API client, calls, error rate and
+// average latency over the last hour and the last successful call. A call
+// fails on a transport error or a 5xx response, as in /readyz. It also
+// reports each chain's RPC endpoints as last health-checked.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+	if s.status.report == nil || time.Since(s.status.report.CheckedAt) > statusCacheTTL {
+		report, err := s.providerStatus(context.WithoutCancel(r.Context()))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.status.report = report
+	}
+	writeJSON(w, s.status.report)
+}
+
+// SetRPCPools provides the chains' RPC endpoint pools for /api/status.
+func (s *Server) SetRPCPools(pools map[string]*rpcpool.Pool) {
+	s.rpcPools = pools
+}
+
+func (s *Server) providerStatus(ctx context.Context) (*statusReport, error) {
+	now := time.Now().UTC()
+	rows, err := s.store.ProviderCallStats(ctx, now.Add(-statusWindow))
+	if err != nil {
+		return nil, err
+	}
+	report := &statusReport{
+		WindowMinutes: int(statusWindow / time.Minute),
+		CheckedAt:     now,
+		Providers:     make([]providerStatus, 0, len(rows)),
+	}
+	for _, row := range rows {
+		p := providerStatus{
+			Provider:     row.Provider,
+			State:        providerIdle,
+			Calls:        row.Calls,
+			Failures:     row.Failures,
+			AvgLatencyMS: row.AvgDurationMs,
+		}
+		if row.Calls > 0 {
+			p.ErrorRate = float64(row.Failures) / float64(row.Calls)
+			switch {
+			case row.Failures == row.Calls:
+				p.State = providerDown
+			case p.ErrorRate >= degradedErrorRate:
+				p.State = providerDegraded
+			default:
+				p.State = providerOK
+			}
+		}
+		// created_at is UTC, read back as text
+		if t, err := time.Parse(time.DateTime, row.LastSuccess); err == nil {
+			p.LastSuccess = &t
+		}
+		report.Providers = append(report.Providers, p)
+	}
+	report.RPC = make(map[string][]rpcpool.EndpointStatus)
+	for chain, pool := range s.rpcPools {
+		report.RPC[chain] = pool.Status()
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// serveTLS serves HTTPS with the configured certificate files or, with
+// autocert_domains, certificates from Let's Encrypt.
+func (s *Server) serveTLS() error {
+	cfg := s.cfg.TLS
+	redirect := http.Handler(http.HandlerFunc(s.redirectHTTPS))
+
+	certFile, keyFile := cfg.CertFile, cfg.KeyFile
+	if cfg.Autocert() {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+			Email:      cfg.AutocertEmail,
+		}
+		s.httpServer.TLSConfig = m.TLSConfig()
+		s.httpServer.TLSConfig.MinVersion = tls.VersionTLS12
+		// Answers http-01 challenges, redirecting everything else
+		redirect = m.HTTPHandler(redirect)
+		certFile, keyFile = "", ""
+	} else {
+		s.httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if s.redirectServer != nil {
+		s.redirectServer.Handler = redirect
+		go func() {
+			log.Printf("HTTP redirect listening on %s", s.redirectServer.Addr)
+			if err := s.redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("HTTP redirect server error: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("HTTPS server listening on %s", s.httpServer.Addr)
+	return s.httpServer.ListenAndServeTLS(certFile, keyFile)
+}
+
+// redirectHTTPS sends plain HTTP requests to the same path over HTTPS.
+func (s *Server) redirectHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if s.cfg.Port != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(s.cfg.Port))
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+}
+
+// shutdownRedirect stops the HTTP redirect server, if one is configured.
+func (s *Server) shutdownRedirect(ctx context.Context) error {
+	if s.redirectServer == nil {
+		return nil
+	}
+	return s.redirectServer.Shutdown(ctx)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// GET, POST /api/admin/tokens lists the token registry, or pins or disables
+// a token by chain and symbol, replacing any earlier entry for it. Pinned
+// tokens are used as given instead of static mappings and the resolver;
+// disabled ones are refused for quotes and topups.
+func (s *Server) handleAdminTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireRole(w, r, roleOperator) {
+			return
+		}
+		var req tokenRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		admin := currentAdmin(r)
+		if err := s.store.UpsertToken(r.Context(), db.UpsertTokenParams{
+			Chain:              req.Chain,
+			Symbol:             req.Symbol,
+			ContractAddress:    req.ContractAddress,
+			ThorchainAsset:     req.ThorchainAsset,
+			SimpleswapSymbol:   req.SimpleSwapSymbol,
+			NearintentsTokenID: req.NearIntentsTokenID,
+			HoudiniSymbol:      req.HoudiniSymbol,
+			StealthexCurrency:  req.StealthEXCurrency,
+			RelayCurrency:      req.RelayCurrency,
+			Enabled:            *req.Enabled,
+			Note:               req.Note,
+			ChangedBy:          admin.Username,
+		}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		target := req.Chain + "." + req.Symbol
+		log.Printf("Admin %s set token %s enabled=%v", admin.Username, target, *req.Enabled)
+		if err := s.audit(r, auditTokenSet, target, fmt.Sprintf("enabled=%v contract=%s", *req.Enabled, req.ContractAddress)); err != nil {
+			log.Printf("Error recording change of token %s: %v", target, err)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.writeTokens(w, r)
+}
+
+// DELETE /api/admin/tokens/{id} removes a token from the registry, leaving
+// it to the static mappings and the resolver again.
+func (s *Server) handleAdminToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Path[len("/api/admin/tokens/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid token ID", http.StatusBadRequest)
+		return
+	}
+	token, err := s.store.GetToken(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "token not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.DeleteToken(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	target := token.Chain + "." + token.Symbol
+	log.Printf("Admin %s removed token %s", currentAdmin(r).Username, target)
+	if err := s.audit(r, auditTokenDelete, target, ""); err != nil {
+		log.Printf("Error recording removal of token %s: %v", target, err)
+	}
+	s.writeTokens(w, r)
+}
+
+func (s *Server) writeTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := s.store.ListTokens(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries := make([]tokenEntry, 0, len(tokens))
+	for _, t := range tokens {
+		entries = append(entries, tokenEntry{
+			ID:                 t.ID,
+			Chain:              t.Chain,
+			Symbol:             t.Symbol,
+			ContractAddress:    t.ContractAddress,
+			ThorchainAsset:     t.ThorchainAsset,
+			SimpleSwapSymbol:   t.SimpleswapSymbol,
+			NearIntentsTokenID: t.NearintentsTokenID,
+			HoudiniSymbol:      t.HoudiniSymbol,
+			StealthEXCurrency:  t.StealthexCurrency,
+			RelayCurrency:      t.RelayCurrency,
+			Enabled:            t.Enabled,
+			Note:               t.Note,
+			ChangedBy:          t.ChangedBy,
+			UpdatedAt:          t.UpdatedAt,
+		})
+	}
+	writeJSON(w, entries)
+}
@@ -0,0 +1,646 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/broadcast"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/rpcpool"
+)
+
+// Request and response bodies of the JSON endpoints. static/openapi.json
+// documents them; keep the two in step.
+
+// request is a JSON request body that checks its own fields.
+type request interface {
+	validate() error
+}
+
+// decodeJSON decodes a JSON request body into req and validates it. Unknown
+// fields are rejected so typos don't silently fall back to zero values.
+func decodeJSON(r *http.Request, req request) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	return req.validate()
+}
+
+// toFloat converts a numeric SQLite aggregate, which scans as int64 or
+// float64 depending on its value, to a float64.
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
+// GET /api/dashboard
+type dashboardStats struct {
+	Users     int64   `json:"users"`
+	Topups    int64   `json:"topups"`
+	Refunded  int64   `json:"refunded"`
+	Volume    float64 `json:"volume"`
+	Pairs     int64   `json:"pairs"`
+	Providers int64   `json:"providers"`
+}
+
+// GET /api/charts
+type chartsResponse struct {
+	Group            string                    `json:"group"`
+	VolumeByAsset    []db.VolumeByToAssetRow   `json:"volume_by_asset"`
+	VolumeByChain    []db.VolumeByFromChainRow `json:"volume_by_chain"`
+	VolumeByPeriod   []db.VolumeByPeriodRow    `json:"volume_by_period"`
+	VolumeByProvider []db.VolumeByProviderRow  `json:"volume_by_provider"`
+	GasByChain       []chainGas                `json:"gas_by_chain"`
+	FeesByPeriod     []periodGas               `json:"fees_by_period"`
+	SurplusByChain   []chainSurplus            `json:"surplus_by_chain"`
+}
+
+// chainGas is the gas spent on a chain in its native coin. Fields are
+// untagged to match the database rows it sits beside.
+type chainGas struct {
+	Chain   string
+	Symbol  string
+	Amount  float64
+	TxCount int64
+}
+
+// chainSurplus is the surplus CoW gas refills got on a chain beyond their
+// limit prices, in the token it came in: the native coin for sell orders,
+// USDC for buy orders.
+type chainSurplus struct {
+	Chain  string
+	Symbol string
+	Amount float64
+	Orders int64
+}
+
+// periodGas is the gas spent on a chain in one period of the fees series.
+// Period is the first day of the day, week (Monday) or month.
+type periodGas struct {
+	Period string
+	chainGas
+}
+
+// GET /api/admin/balance-history
+type balanceHistoryResponse struct {
+	Group           string          `json:"group"`
+	BalanceByPeriod []periodBalance `json:"balance_by_period"`
+}
+
+// periodBalance is the USD held across the wallets on a chain at the last
+// snapshot of a period. Change is from the chain's previous period in the
+// range and Drawdown how far it sits below the highest value in the range.
+// Fields are untagged to match the charts' rows.
+type periodBalance struct {
+	Period    string
+	Chain     string
+	TakenAt   time.Time
+	USD       float64
+	NativeUSD float64
+	StableUSD float64
+	Wallets   int64
+	Change    float64
+	Drawdown  float64
+}
+
+// GET /api/status
+type statusReport struct {
+	WindowMinutes int              `json:"window_minutes"`
+	CheckedAt     time.Time        `json:"checked_at"`
+	Providers     []providerStatus `json:"providers"`
+
+	// Endpoints of each chain with http(s) RPCs, from the last health check
+	RPC map[string][]rpcpool.EndpointStatus `json:"rpc"`
+}
+
+// providerStatus is one logged API client's calls over the status window.
+// Provider is the apilog name, so resolvers and CoW appear beside the swap
+// providers.
+type providerStatus struct {
+	Provider     string     `json:"provider"`
+	State        string     `json:"state"` // ok, degraded, down or idle
+	Calls        int64      `json:"calls"`
+	Failures     int64      `json:"failures"`
+	ErrorRate    float64    `json:"error_rate"`
+	AvgLatencyMS float64    `json:"avg_latency_ms"`
+	LastSuccess  *time.Time `json:"last_success"` // null if no call ever succeeded
+}
+
+// GET /api/admin/balances, POST /api/admin/balances/refresh
+type balanceSnapshot struct {
+	Wallets []events.Balances `json:"wallets"`
+
+	// USD held across the wallets, per chain and in total. Native coins
+	// that couldn't be priced are left out.
+	ChainUSD map[string]float64 `json:"chain_usd"`
+	USD      float64            `json:"usd"`
+
+	// When the balances were read
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Why the last refresh failed, if it did; wallets are from the one before
+	Error string `json:"error,omitempty"`
+}
+
+// GET /api/me/login-config
+type userLoginConfig struct {
+	BotUsername string `json:"bot_username"`
+}
+
+// GET /api/me
+type userProfile struct {
+	UserID    int64       `json:"user_id"`
+	Username  string      `json:"username,omitempty"`
+	FirstName string      `json:"first_name,omitempty"`
+	Wallet    *userWallet `json:"wallet"` // null until the user is assigned one
+	Topups    []userTopup `json:"topups"`
+}
+
+// userWallet is the wallet a user tops up from. Shared is set in single
+// mode, where every whitelisted user tops up from the same wallet.
+type userWallet struct {
+	Index   uint32 `json:"index"`
+	Address string `json:"address"`
+	Shared  bool   `json:"shared"`
+}
+
+// userTopup is one of a user's topups. ExplorerURL is untagged to match the
+// database row it extends.
+type userTopup struct {
+	db.ListUserTopupsRow
+	ExplorerURL string
+}
+
+// GET /api/admin/api-logs
+type apiLogPage struct {
+	Rows  []db.ApiRequest `json:"rows"`
+	Total int64           `json:"total"`
+}
+
+// POST /api/admin/api-logs/purge
+type apiLogPurgeRequest struct {
+	// Delete calls older than this many days, 0 for all of them; left out,
+	// the api_log retention limits are applied now
+	OlderThanDays *int `json:"older_than_days"`
+}
+
+func (r *apiLogPurgeRequest) validate() error {
+	if r.OlderThanDays != nil && *r.OlderThanDays < 0 {
+		return fmt.Errorf("older_than_days must not be negative")
+	}
+	return nil
+}
+
+type apiLogPurged struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// GET /api/admin/audit-log
+type auditLogPage struct {
+	Rows  []db.AuditLog `json:"rows"`
+	Total int64         `json:"total"`
+}
+
+// GET /api/admin/ledger
+type ledgerPage struct {
+	Rows  []ledgerEntry `json:"rows"`
+	Total int64         `json:"total"`
+}
+
+// ledgerEntry is an entry with its asset's decimals. Fields are untagged to
+// match the database row.
+type ledgerEntry struct {
+	db.LedgerEntry
+	Decimals int
+}
+
+// GET /api/admin/reconciliation
+type reconciliationReport struct {
+	Enabled       bool             `json:"enabled"`
+	Discrepancies int64            `json:"discrepancies"`
+	Rows          []reconciliation `json:"rows"`
+}
+
+// reconciliation is an account's latest reconciliation with its asset's
+// decimals. Fields are untagged to match the database row.
+type reconciliation struct {
+	db.LedgerReconciliation
+	Decimals int
+}
+
+// GET /api/admin/users
+type userWithAddr struct {
+	db.User
+	Address string `json:"address"`
+	Index   uint32 `json:"index"`
+	Pool    string `json:"pool,omitempty"`
+}
+
+// POST /api/admin/export-key
+type exportKeyRequest struct {
+	Index *uint32 `json:"index"`
+
+	// The exporting admin's own password, entered again
+	Password string `json:"password"`
+
+	// Why the key is needed, for the audit log and notification
+	Reason string `json:"reason"`
+}
+
+func (r *exportKeyRequest) validate() error {
+	if r.Index == nil {
+		return fmt.Errorf("index is required")
+	}
+	if r.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if len(r.Reason) > 500 {
+		return fmt.Errorf("reason is longer than 500 characters")
+	}
+	return nil
+}
+
+type exportKeyResponse struct {
+	Index      uint32 `json:"index"`
+	Pool       string `json:"pool"`
+	Address    string `json:"address"`
+	PrivateKey string `json:"private_key"`
+}
+
+// GET, POST /api/admin/unlock
+type unlockRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+func (r *unlockRequest) validate() error {
+	if r.Passphrase == "" {
+		return fmt.Errorf("passphrase is required")
+	}
+	return nil
+}
+
+type lockState struct {
+	Locked bool `json:"locked"`
+}
+
+// GET, POST /api/admin/chains
+type chainToggleRequest struct {
+	Chain   string `json:"chain"`
+	Enabled *bool  `json:"enabled"`
+}
+
+func (r *chainToggleRequest) validate() error {
+	if r.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if r.Enabled == nil {
+		return fmt.Errorf("enabled is required")
+	}
+	return nil
+}
+
+type chainState struct {
+	Chain   string `json:"chain"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Error body of the /api/v1 endpoints
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// POST /api/admin/topup-resolve/{id}
+type resolveRequest struct {
+	Note string `json:"note"`
+}
+
+func (r *resolveRequest) validate() error {
+	if strings.TrimSpace(r.Note) == "" {
+		return fmt.Errorf("note is required")
+	}
+	return nil
+}
+
+type rejectRequest struct {
+	Note string `json:"note"`
+}
+
+func (r *rejectRequest) validate() error { return nil }
+
+type topupResolution struct {
+	ShortID string `json:"short_id"`
+	Status  string `json:"status"`
+	Note    string `json:"note"`
+}
+
+// POST /api/admin/topup-retry/{id}
+type topupRetry struct {
+	ShortID     string `json:"short_id"`
+	RetriedAs   string `json:"retried_as"`
+	Provider    string `json:"provider"`
+	FromChain   string `json:"from_chain"`
+	TxHash      string `json:"tx_hash"`
+	ExplorerURL string `json:"explorer_url"`
+}
+
+// POST /api/admin/refill
+type refillRequest struct {
+	Index  *uint32 `json:"index"`
+	Chain  string  `json:"chain"`
+	Amount float64 `json:"amount"` // USDC to sell, default the chain's refill_usd
+}
+
+func (r *refillRequest) validate() error {
+	if r.Index == nil {
+		return fmt.Errorf("index is required")
+	}
+	if r.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if r.Amount < 0 || r.Amount > 100 {
+		return fmt.Errorf("amount must be between 0 and 100 USDC")
+	}
+	return nil
+}
+
+// POST /api/admin/transfer
+type transferRequest struct {
+	Index  *uint32 `json:"index"`
+	Chain  string  `json:"chain"`
+	Amount float64 `json:"amount"` // USDC
+
+	// Destination: another wallet index in use, or an address
+	ToIndex *uint32 `json:"to_index"`
+	To      string  `json:"to"`
+}
+
+func (r *transferRequest) validate() error {
+	if r.Index == nil {
+		return fmt.Errorf("index is required")
+	}
+	if r.Chain == "" {
+		return fmt.Errorf("chain is required")
+	}
+	if r.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if (r.ToIndex == nil) == (r.To == "") {
+		return fmt.Errorf("exactly one of to_index and to is required")
+	}
+	if r.To != "" && !common.IsHexAddress(r.To) {
+		return fmt.Errorf("to must be an address")
+	}
+	return nil
+}
+
+type transferResult struct {
+	Index       uint32 `json:"index"`
+	Chain       string `json:"chain"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Amount      string `json:"amount"`
+	TxHash      string `json:"tx_hash"`
+	ExplorerURL string `json:"explorer_url"`
+}
+
+// POST /api/admin/broadcast
+type broadcastRequest struct {
+	Text string `json:"text"`
+}
+
+func (r *broadcastRequest) validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Text == "" {
+		return fmt.Errorf("text is required")
+	}
+	if len(r.Text) > broadcast.MaxLength {
+		return fmt.Errorf("text is longer than %d bytes", broadcast.MaxLength)
+	}
+	return nil
+}
+
+type broadcastQueued struct {
+	ID int64 `json:"id"`
+}
+
+// POST /api/admin/admins
+type adminCreateRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+func (r *adminCreateRequest) validate() error {
+	r.Username = strings.TrimSpace(r.Username)
+	if r.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if len(r.Password) < minAdminPassword {
+		return fmt.Errorf("password must be at least %d characters", minAdminPassword)
+	}
+	return ValidateRole(r.Role)
+}
+
+// POST /api/admin/admin/{id}
+type adminUpdateRequest struct {
+	Role     string `json:"role"`
+	Password string `json:"password"`
+}
+
+func (r *adminUpdateRequest) validate() error {
+	if r.Role == "" && r.Password == "" {
+		return fmt.Errorf("role or password is required")
+	}
+	if r.Role != "" {
+		if err := ValidateRole(r.Role); err != nil {
+			return err
+		}
+	}
+	if r.Password != "" && len(r.Password) < minAdminPassword {
+		return fmt.Errorf("password must be at least %d characters", minAdminPassword)
+	}
+	return nil
+}
+
+// GET, POST /api/admin/whitelist
+type whitelistRequest struct {
+	UserID int64  `json:"user_id"`
+	Note   string `json:"note"`
+}
+
+func (r *whitelistRequest) validate() error {
+	if r.UserID <= 0 {
+		return fmt.Errorf("user_id must be a Telegram user ID")
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	if len(r.Note) > 200 {
+		return fmt.Errorf("note is longer than 200 characters")
+	}
+	return nil
+}
+
+// tokenRequest pins or disables a token in the registry, keyed by chain and
+// symbol. Provider IDs left empty fall back to the static mappings.
+type tokenRequest struct {
+	Chain              string `json:"chain"`
+	Symbol             string `json:"symbol"`
+	ContractAddress    string `json:"contract_address"`
+	ThorchainAsset     string `json:"thorchain_asset"`
+	SimpleSwapSymbol   string `json:"simpleswap_symbol"`
+	NearIntentsTokenID string `json:"nearintents_token_id"`
+	HoudiniSymbol      string `json:"houdini_symbol"`
+	StealthEXCurrency  string `json:"stealthex_currency"`
+	RelayCurrency      string `json:"relay_currency"`
+	Enabled            *bool  `json:"enabled"`
+	Note               string `json:"note"`
+}
+
+func (r *tokenRequest) validate() error {
+	r.Chain = strings.ToUpper(strings.TrimSpace(r.Chain))
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	if r.Chain == "" || r.Symbol == "" {
+		return fmt.Errorf("chain and symbol are required")
+	}
+	if strings.ContainsAny(r.Chain+r.Symbol, ".- ") {
+		return fmt.Errorf("chain and symbol must not contain '.', '-' or spaces")
+	}
+	r.ContractAddress = strings.TrimSpace(r.ContractAddress)
+	if r.Enabled == nil {
+		return fmt.Errorf("enabled is required")
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	if len(r.Note) > 200 {
+		return fmt.Errorf("note is longer than 200 characters")
+	}
+	return nil
+}
+
+// tokenEntry is a token in the registry.
+type tokenEntry struct {
+	ID                 int64     `json:"id"`
+	Chain              string    `json:"chain"`
+	Symbol             string    `json:"symbol"`
+	ContractAddress    string    `json:"contract_address"`
+	ThorchainAsset     string    `json:"thorchain_asset"`
+	SimpleSwapSymbol   string    `json:"simpleswap_symbol"`
+	NearIntentsTokenID string    `json:"nearintents_token_id"`
+	HoudiniSymbol      string    `json:"houdini_symbol"`
+	StealthEXCurrency  string    `json:"stealthex_currency"`
+	RelayCurrency      string    `json:"relay_currency"`
+	Enabled            bool      `json:"enabled"`
+	Note               string    `json:"note"`
+	ChangedBy          string    `json:"changed_by"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// resolverStatus is the token resolver's state for the admin panel.
+type resolverStatus struct {
+	Caches               []resolverCache   `json:"caches"`
+	Matchers             []resolverMatcher `json:"matchers"`
+	CoinGeckoKeys        int               `json:"coingecko_keys"`
+	CoinGeckoKeysResting int               `json:"coingecko_keys_resting"` // rate limited for now
+	CoinGeckoPro         bool              `json:"coingecko_pro"`
+}
+
+// resolverCache counts a resolver cache's lookups since startup.
+type resolverCache struct {
+	Name      string  `json:"name"`
+	Entries   int     `json:"entries"`
+	Hits      int64   `json:"hits"`
+	StoreHits int64   `json:"store_hits"`
+	Misses    int64   `json:"misses"`
+	Errors    int64   `json:"errors"`
+	HitRate   float64 `json:"hit_rate"` // 0-1, memory and database hits
+}
+
+// resolverMatcher is a private provider's currency list.
+type resolverMatcher struct {
+	Provider    string     `json:"provider"`
+	Currencies  int        `json:"currencies"`
+	RefreshedAt *time.Time `json:"refreshed_at"` // null before the first successful refresh
+	Error       string     `json:"error,omitempty"`
+}
+
+// resolveTestRequest looks an asset up with the resolver, e.g. "BASE.DEGEN"
+// or "BASE.0x...".
+type resolveTestRequest struct {
+	Asset string `json:"asset"`
+}
+
+func (r *resolveTestRequest) validate() error {
+	r.Asset = strings.TrimSpace(r.Asset)
+	if r.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	return nil
+}
+
+type resolveTestResult struct {
+	Asset      string             `json:"asset"`
+	Candidates []resolveCandidate `json:"candidates"`
+	Resolution *resolveResult     `json:"resolution"` // null on error
+	Error      string             `json:"error,omitempty"`
+}
+
+type resolveCandidate struct {
+	CoinGeckoID     string   `json:"coingecko_id"` // empty for DexScreener tokens
+	Name            string   `json:"name"`
+	Symbol          string   `json:"symbol"`
+	MarketCapRank   int      `json:"market_cap_rank"`
+	MarketCapUSD    float64  `json:"market_cap_usd"`
+	LiquidityUSD    float64  `json:"liquidity_usd"`
+	ContractAddress string   `json:"contract_address"`
+	Chains          []string `json:"chains"`
+}
+
+type resolveResult struct {
+	Source          string            `json:"source"`
+	CoinGeckoID     string            `json:"coingecko_id"`
+	Name            string            `json:"name"`
+	Symbol          string            `json:"symbol"`
+	ContractAddress string            `json:"contract_address"`
+	Providers       []resolveProvider `json:"providers"`
+	MarketCapUSD    float64           `json:"market_cap_usd"`
+	VolumeUSD       float64           `json:"volume_usd"`
+	LiquidityUSD    float64           `json:"liquidity_usd"`
+	Warnings        []string          `json:"warnings,omitempty"`
+}
+
+type resolveProvider struct {
+	Provider string `json:"provider"`
+	AssetID  string `json:"asset_id"`
+}
+
+type whitelistState struct {
+	Mode        string           `json:"mode"`
+	AdminUserID int64            `json:"admin_user_id"`
+	Users       []whitelistEntry `json:"users"`
+}
+
+// whitelistEntry is a whitelisted user. Source is "config" for
+// whitelisted_users and "admin" for users added from the admin panel.
+type whitelistEntry struct {
+	UserID   int64      `json:"user_id"`
+	Username string     `json:"username,omitempty"`
+	Source   string     `json:"source"`
+	Note     string     `json:"note,omitempty"`
+	AddedBy  string     `json:"added_by,omitempty"`
+	AddedAt  *time.Time `json:"added_at,omitempty"`
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+)
+
+// telegramLoginMaxAge is how old a Telegram login may be when it reaches
+// /me/login, so a leaked login URL can't be replayed later.
+const telegramLoginMaxAge = 24 * time.Hour
+
+// userTopupLimit is the number of recent topups /api/me returns.
+const userTopupLimit = 50
+
+// userSession is the Telegram user a user session cookie belongs to.
+type userSession struct {
+	UserID    int64
+	Username  string
+	FirstName string
+}
+
+type userContextKey struct{}
+
+// currentUser returns the Telegram user behind a request that passed
+// withUserAuth.
+func currentUser(r *http.Request) userSession {
+	u, _ := r.Context().Value(userContextKey{}).(userSession)
+	return u
+}
+
+// verifyTelegramLogin checks the fields the Telegram Login Widget signs with
+// the bot token, as described at https://core.telegram.org/widgets/login,
+// and returns the user they name.
+func verifyTelegramLogin(botToken string, fields url.Values, now time.Time) (userSession, error) {
+	hash := fields.Get("hash")
+	if hash == "" {
+		return userSession{}, fmt.Errorf("missing hash")
+	}
+	var pairs []string
+	for k := range fields {
+		if k != "hash" {
+			pairs = append(pairs, k+"="+fields.Get(k))
+		}
+	}
+	sort.Strings(pairs)
+
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(hash))) {
+		return userSession{}, fmt.Errorf("invalid hash")
+	}
+
+	authDate, err := strconv.ParseInt(fields.Get("auth_date"), 10, 64)
+	if err != nil {
+		return userSession{}, fmt.Errorf("invalid auth_date")
+	}
+	if now.Sub(time.Unix(authDate, 0)) > telegramLoginMaxAge {
+		return userSession{}, fmt.Errorf("login expired")
+	}
+	id, err := strconv.ParseInt(fields.Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return userSession{}, fmt.Errorf("invalid id")
+	}
+	return userSession{UserID: id, Username: fields.Get("username"), FirstName: fields.Get("first_name")}, nil
+}
+
+// withUserAuth lets through requests with a user session whose user may
+// still use the bot, making the user available to next via currentUser.
+func (s *Server) withUserAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("user_session")
+		if err != nil {
+			http.Error(w, "not signed in", http.StatusUnauthorized)
+			return
+		}
+		sessionMu.RLock()
+		user, ok := userSessions[cookie.Value]
+		sessionMu.RUnlock()
+		// Whitelist changes take effect on open sessions too
+		if !ok || !s.cfg.IsAuthorized(user.UserID) {
+			http.Error(w, "not signed in", http.StatusUnauthorized)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	}
+}
+
+// GET /me/login is where the Telegram Login Widget sends users after they
+// confirm the login in Telegram.
+func (s *Server) handleUserLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, err := verifyTelegramLogin(s.cfg.TelegramToken, r.URL.Query(), time.Now())
+	if err != nil {
+		log.Printf("Rejected Telegram login: %v", err)
+		http.Redirect(w, r, s.url("/me?error=invalid"), http.StatusSeeOther)
+		return
+	}
+	if !s.cfg.IsAuthorized(user.UserID) {
+		http.Redirect(w, r, s.url("/me?error=unauthorized"), http.StatusSeeOther)
+		return
+	}
+	log.Printf("User %d (%s) signed in to the dashboard", user.UserID, user.Username)
+	token := generateToken()
+	sessionMu.Lock()
+	userSessions[token] = user
+	sessionMu.Unlock()
+	// Lax, not Strict: the login arrives as a redirect from Telegram, and
+	// a Strict cookie would be held back from the /me load that follows it
+	cookie := s.sessionCookie(r, "user_session", token)
+	cookie.SameSite = http.SameSiteLaxMode
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, s.url("/me"), http.StatusSeeOther)
+}
+
+// POST /me/logout ends the user session.
+func (s *Server) handleUserLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if cookie, err := r.Cookie("user_session"); err == nil {
+		sessionMu.Lock()
+		delete(userSessions, cookie.Value)
+		sessionMu.Unlock()
+	}
+	cookie := s.sessionCookie(r, "user_session", "")
+	cookie.MaxAge = -1
+	cookie.SameSite = http.SameSiteLaxMode
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, s.url("/me"), http.StatusSeeOther)
+}
+
+// endUserSessions signs a user out of every dashboard session.
+func endUserSessions(userID int64) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	for token, user := range userSessions {
+		if user.UserID == userID {
+			delete(userSessions, token)
+		}
+	}
+}
+
+// GET /api/me/login-config names the bot the login widget signs in with.
+func (s *Server) handleUserLoginConfig(w http.ResponseWriter, r *http.Request) {
+	s.lockMu.RLock()
+	botAPI := s.botAPI
+	s.lockMu.RUnlock()
+	if botAPI == nil {
+		http.Error(w, "the bot is not running yet", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, userLoginConfig{BotUsername: botAPI.Self.UserName})
+}
+
+// userWallet returns the wallet a Telegram user tops up from: the shared
+// wallet in single mode, or their own address assignment in multi mode. It
+// returns nil if they have never been assigned one; viewing the dashboard
+// doesn't assign one.
+func (s *Server) userWallet(ctx context.Context, userID int64) (*userWallet, error) {
+	if s.cfg.Mode == config.ModeSingle {
+		addr, err := s.wallets().Address(0)
+		if err != nil {
+			return nil, err
+		}
+		return &userWallet{Index: 0, Address: addr.Hex(), Shared: true}, nil
+	}
+	user, err := s.store.GetUserByTelegramID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := s.store.GetAddressAssignment(ctx, db.GetAddressAssignmentParams{AssignedToID: user.ID, AssignedToType: "user"})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addr, err := s.wallets().Address(uint32(assignment.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &userWallet{Index: uint32(assignment.ID), Address: addr.Hex()}, nil
+}
+
+// GET /api/me returns the signed-in user's wallet and recent topups.
+func (s *Server) handleUserProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := currentUser(r)
+	wallet, err := s.userWallet(ctx, user.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows, err := s.store.ListUserTopups(ctx, db.ListUserTopupsParams{UserID: user.UserID, Limit: userTopupLimit})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	topups := make([]userTopup, len(rows))
+	for i, row := range rows {
+		topups[i] = userTopup{ListUserTopupsRow: row, ExplorerURL: s.cfg.ExplorerTxURL(row.FromChain, row.TxHash)}
+	}
+	writeJSON(w, userProfile{
+		UserID:    user.UserID,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		Wallet:    wallet,
+		Topups:    topups,
+	})
+}
+
+// GET /api/me/balances reads the signed-in user's wallet balances.
+func (s *Server) handleUserBalances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	wallet, err := s.userWallet(ctx, currentUser(r).UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if wallet == nil {
+		http.Error(w, "no wallet yet; send the bot a topup to get one", http.StatusNotFound)
+		return
+	}
+	bals, err := balances.FetchBalances(ctx, swaps.EnabledClients(s.rpcClients), []common.Address{common.HexToAddress(wallet.Address)}, thorchain.FundingTokens.Contracts(), balances.Watchlist(s.cfg))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	balances.Value(ctx, bals, swaps.NativePriceUSD, thorchain.FundingTokens.Decimals())
+
+	result := events.Balances{Address: wallet.Address, Index: wallet.Index, Chains: make(map[string]events.ChainBalance)}
+	for _, b := range bals {
+		result.Add(b)
+	}
+	writeJSON(w, result)
+}
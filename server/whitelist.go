@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+)
+
+// LoadWhitelist applies the whitelist changes made from the admin panel on
+// top of the configured whitelisted_users.
+func LoadWhitelist(ctx context.Context, cfg *config.Config, store *db.Store) error {
+	overrides, err := store.ListWhitelistOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	for _, o := range overrides {
+		cfg.SetWhitelisted(o.UserID, o.Allowed)
+	}
+	return nil
+}
+
+// GET, POST /api/admin/whitelist lists the users allowed to use the bot in
+// single mode, or adds one. Adding needs the superadmin role, since
+// whitelisted users spend from the shared wallet.
+func (s *Server) handleAdminWhitelist(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.writeWhitelist(w, r)
+	case http.MethodPost:
+		if !requireRole(w, r, roleSuperadmin) {
+			return
+		}
+		var req whitelistRequest
+		if err := decodeJSON(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !s.setWhitelisted(w, r, req.UserID, true, req.Note) {
+			return
+		}
+		s.writeWhitelist(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// DELETE /api/admin/whitelist/{user_id} removes a user from the whitelist,
+// whether they were added from the panel or in whitelisted_users.
+func (s *Server) handleAdminWhitelistUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := strconv.ParseInt(r.URL.Path[len("/api/admin/whitelist/"):], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if userID == s.cfg.AdminUserID {
+		http.Error(w, "the admin user is always authorized", http.StatusConflict)
+		return
+	}
+	if !s.setWhitelisted(w, r, userID, false, "") {
+		return
+	}
+	s.writeWhitelist(w, r)
+}
+
+// setWhitelisted stores a whitelist change and applies it to the running
+// bot, writing an error and returning false if it can't.
+func (s *Server) setWhitelisted(w http.ResponseWriter, r *http.Request, userID int64, allowed bool, note string) bool {
+	if s.cfg.Mode != config.ModeSingle {
+		http.Error(w, "the whitelist only applies in single mode", http.StatusConflict)
+		return false
+	}
+	admin := currentAdmin(r)
+	if err := s.store.UpsertWhitelistOverride(r.Context(), db.UpsertWhitelistOverrideParams{
+		UserID:    userID,
+		Allowed:   allowed,
+		Note:      note,
+		ChangedBy: admin.Username,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	s.cfg.SetWhitelisted(userID, allowed)
+
+	action := auditWhitelistAdd
+	if allowed {
+		log.Printf("Admin %s added user %d to the whitelist", admin.Username, userID)
+	} else {
+		action = auditWhitelistRemove
+		log.Printf("Admin %s removed user %d from the whitelist", admin.Username, userID)
+	}
+	if err := s.audit(r, action, strconv.FormatInt(userID, 10), note); err != nil {
+		log.Printf("Error recording whitelist change of user %d: %v", userID, err)
+	}
+	return true
+}
+
+func (s *Server) writeWhitelist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	overrides, err := s.store.ListWhitelistOverrides(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	changes := make(map[int64]db.WhitelistOverride, len(overrides))
+	for _, o := range overrides {
+		changes[o.UserID] = o
+	}
+
+	state := whitelistState{Mode: string(s.cfg.Mode), AdminUserID: s.cfg.AdminUserID, Users: []whitelistEntry{}}
+	for _, id := range s.cfg.Whitelist() {
+		entry := whitelistEntry{UserID: id, Source: "config"}
+		if o, ok := changes[id]; ok && !s.cfg.ConfigWhitelisted(id) {
+			entry.Source = "admin"
+			entry.Note = o.Note
+			entry.AddedBy = o.ChangedBy
+			entry.AddedAt = &o.ChangedAt
+		}
+		user, err := s.store.GetUserByTelegramID(ctx, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		entry.Username = user.Username
+		state.Users = append(state.Users, entry)
+	}
+	writeJSON(w, state)
+}
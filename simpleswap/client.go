@@ -15,6 +15,9 @@ const baseURL = "https://api.simpleswap.io"
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
+
+	// Partner parameters sent with estimates and exchanges
+	partnerParams map[string]string
 }
 
 func NewClient(apiKey string, httpClient *http.Client) *Client {
@@ -26,8 +29,16 @@ func NewClient(apiKey string, httpClient *http.Client) *Client {
 
 // GetEstimated returns the estimated output amount for a swap.
 func (c *Client) GetEstimated(ctx context.Context, from, to string, amount float64) (string, error) {
-	u := fmt.Sprintf("%s/get_estimated?api_key=%s&fixed=false&currency_from=%s&currency_to=%s&amount=%g",
-		baseURL, c.apiKey, from, to, amount)
+	params := url.Values{}
+	for k, v := range c.partnerParams {
+		params.Set(k, v)
+	}
+	params.Set("api_key", c.apiKey)
+	params.Set("fixed", "false")
+	params.Set("currency_from", from)
+	params.Set("currency_to", to)
+	params.Set("amount", fmt.Sprintf("%g", amount))
+	u := fmt.Sprintf("%s/get_estimated?%s", baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
@@ -65,6 +76,7 @@ type Exchange struct {
 	AddressTo   string `json:"address_to"`
 	AmountFrom  string `json:"expected_amount"`
 	AmountTo    string `json:"amount_to"`
+	TxTo        string `json:"tx_to"`
 }
 
 // CreateExchange creates a new exchange and returns the exchange details including the deposit address.
@@ -80,6 +92,11 @@ func (c *Client) CreateExchange(ctx context.Context, from, to, amount, addressTo
 		"extra_id_to":    "",
 		"user_refund_address": refundAddress,
 	}
+	for k, v := range c.partnerParams {
+		if _, ok := payload[k]; !ok {
+			payload[k] = v
+		}
+	}
 
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
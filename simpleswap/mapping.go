@@ -49,10 +49,17 @@ var assetToSymbol = map[string]string{
 	"CRO.CRO":   "cro", // ERC20 on ETH, not native Cronos
 }
 
-// sourceChainSymbol maps our RPC chain name to the SimpleSwap USDC symbol for that chain.
-var sourceChainSymbol = map[string]string{
-	"avalanche": "usdcavaxc",
-	"base":      "usdcbase",
+// sourceChainSymbol maps our RPC chain name and funding token symbol to the
+// SimpleSwap symbol for that token. Native coins deposit to the same kind of
+// exchange address with a plain value transfer.
+var sourceChainSymbol = map[string]map[string]string{
+	"arbitrum":  {"USDC": "usdcarb", "USDT": "usdtarb", "ETH": "etharb"},
+	"avalanche": {"USDC": "usdcavaxc", "USDT": "usdtavaxc", "AVAX": "avaxc"},
+	"base":      {"USDC": "usdcbase", "ETH": "ethbase"},
+	"bsc":       {"USDT": "usdtbsc", "USDC": "usdcbsc", "BNB": "bnb-bsc"},
+	"ethereum":  {"USDC": "usdc", "USDT": "usdt", "DAI": "dai", "ETH": "eth"},
+	"optimism":  {"USDC": "usdcop", "USDT": "usdtop"},
+	"polygon":   {"USDC": "usdcpoly", "USDT": "usdtpoly", "POL": "pol"},
 }
 
 // AssetToSymbol looks up the SimpleSwap symbol for a target asset.
@@ -68,13 +75,13 @@ func LookupSymbol(key string) (string, bool) {
 	return sym, ok
 }
 
-// SourceSymbol returns the SimpleSwap USDC symbol for a source chain.
-func SourceSymbol(chain string) (string, bool) {
-	sym, ok := sourceChainSymbol[chain]
+// SourceSymbol returns the SimpleSwap symbol for a funding token on a source chain.
+func SourceSymbol(chain, token string) (string, bool) {
+	sym, ok := sourceChainSymbol[chain][token]
 	return sym, ok
 }
 
-// SupportedSourceChains returns the RPC chain keys that SimpleSwap can source USDC from.
+// SupportedSourceChains returns the RPC chain keys that SimpleSwap can source stablecoins from.
 func SupportedSourceChains() []string {
 	chains := make([]string, 0, len(sourceChainSymbol))
 	for k := range sourceChainSymbol {
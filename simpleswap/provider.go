@@ -2,7 +2,6 @@ package simpleswap
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
@@ -12,18 +11,22 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"github.com/RaghavSood/fundbot/balances"
 	"github.com/RaghavSood/fundbot/swaps"
 	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 // chainIDs for EVM chains
 var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
 	"avalanche": big.NewInt(43114),
 	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
 }
 
 const erc20TransferABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
@@ -33,9 +36,13 @@ type Provider struct {
 	rpcClients map[string]*ethclient.Client
 }
 
-func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+// NewProvider returns the SimpleSwap provider. partnerParams are sent with
+// every estimate and exchange, e.g. to attribute volume to a partner.
+func NewProvider(apiKey string, partnerParams map[string]string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	client := NewClient(apiKey, httpClient)
+	client.partnerParams = partnerParams
 	return &Provider{
-		client:     NewClient(apiKey, httpClient),
+		client:     client,
 		rpcClients: rpcClients,
 	}
 }
@@ -48,6 +55,17 @@ func (p *Provider) Category() string {
 	return "private"
 }
 
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+// CheckKey verifies the API key by listing currencies.
+func (p *Provider) CheckKey(ctx context.Context) error {
+	_, err := p.client.GetAllCurrencies(ctx)
+	return err
+}
+
 func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
 	_, ok := AssetToSymbol(asset)
 	return ok
@@ -66,38 +84,27 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 		return nil, fmt.Errorf("simpleswap: unsupported target asset %s", toAsset)
 	}
 
-	// Required USDC in smallest unit (6 decimals)
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var quotes []swaps.Quote
 
 	for _, chain := range SupportedSourceChains() {
-		fromSymbol, ok := SourceSymbol(chain)
-		if !ok {
-			continue
-		}
-
-		// Check USDC balance on this chain
 		rpc, ok := p.rpcClients[chain]
 		if !ok {
 			continue
 		}
-		usdcAddr, ok := thorchain.USDCContracts[chain]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
+
+		// Pick the first funding token with a SimpleSwap listing and enough balance
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, func(t swaps.FundingToken) bool {
+			_, ok := SourceSymbol(chain, t.Symbol)
+			return ok
+		})
 		if err != nil {
-			log.Printf("simpleswap: error checking USDC balance on %s: %v", chain, err)
-			continue
-		}
-		if bal.Cmp(requiredUSDC) < 0 {
-			log.Printf("simpleswap: skipping %s, insufficient USDC (have %s, need %s)", chain, bal, requiredUSDC)
+			log.Printf("simpleswap: skipping %s: %v", chain, err)
 			continue
 		}
+		fromSymbol, _ := SourceSymbol(chain, token.Symbol)
 
-		// SimpleSwap amount is in USDC units (e.g. 5.00 for $5)
-		estimated, err := p.client.GetEstimated(ctx, fromSymbol, toSymbol, usdAmount)
+		// SimpleSwap amounts are in whole source tokens (e.g. 5.00 USDC, 0.0015 ETH)
+		estimated, err := p.client.GetEstimated(ctx, fromSymbol, toSymbol, token.Units(inputAmount))
 		if err != nil {
 			log.Printf("simpleswap quote for %s via %s failed: %v", toAsset, chain, err)
 			continue
@@ -106,13 +113,9 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 		// Parse estimated output as a big.Int (raw units depend on the asset)
 		expectedOut := parseToBigInt(estimated)
 
-		// Input in USDC smallest unit (6 decimals)
-		inputAmount := new(big.Int)
-		inputAmount.SetInt64(int64(usdAmount * 1e6))
-
 		quotes = append(quotes, swaps.Quote{
 			Provider:          "simpleswap",
-			FromAsset:         mustParseAsset(chain),
+			FromAsset:         token.Asset(chain),
 			ToAsset:           toAsset,
 			FromChain:         chain,
 			InputAmountUSD:    usdAmount,
@@ -134,7 +137,7 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 	return quotes, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *ecdsa.PrivateKey) (swaps.ExecuteResult, error) {
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
 	fromSymbol, _ := quote.ExtraData["simpleswap_from"].(string)
 	toSymbol, _ := quote.ExtraData["simpleswap_to"].(string)
 	if fromSymbol == "" || toSymbol == "" {
@@ -151,9 +154,9 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
 	}
 
-	usdcAddr, ok := thorchain.USDCContracts[quote.FromChain]
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
 	if !ok {
-		return swaps.ExecuteResult{}, fmt.Errorf("no USDC contract for %s", quote.FromChain)
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
 	}
 
 	destination, _ := quote.ExtraData["simpleswap_destination"].(string)
@@ -161,8 +164,8 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 		return swaps.ExecuteResult{}, fmt.Errorf("simpleswap: missing destination in quote ExtraData")
 	}
 
-	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
-	amountStr := fmt.Sprintf("%g", quote.InputAmountUSD)
+	fromAddr := signer.Address()
+	amountStr := fmt.Sprintf("%g", token.Units(quote.InputAmount))
 
 	// Create exchange on SimpleSwap
 	exchange, err := p.client.CreateExchange(ctx, fromSymbol, toSymbol, amountStr, destination, fromAddr.Hex())
@@ -172,10 +175,17 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 
 	log.Printf("SimpleSwap exchange created: id=%s, deposit=%s", exchange.ID, exchange.AddressFrom)
 
-	// Send USDC to the deposit address via ERC20 transfer
-	txHash, err := p.transferERC20(ctx, rpc, chainID, privateKey, fromAddr, usdcAddr, common.HexToAddress(exchange.AddressFrom), quote.InputAmount)
+	// Send the funding token to the deposit address: a plain value transfer for
+	// native coins, an ERC20 transfer otherwise
+	depositAddr := common.HexToAddress(exchange.AddressFrom)
+	var txHash string
+	if token.Native {
+		txHash, err = p.transferNative(ctx, rpc, chainID, signer, fromAddr, depositAddr, quote.InputAmount)
+	} else {
+		txHash, err = p.transferERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, depositAddr, quote.InputAmount)
+	}
 	if err != nil {
-		return swaps.ExecuteResult{}, fmt.Errorf("simpleswap USDC transfer: %w", err)
+		return swaps.ExecuteResult{}, fmt.Errorf("simpleswap %s transfer: %w", token.Symbol, err)
 	}
 
 	return swaps.ExecuteResult{
@@ -193,11 +203,14 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	if err != nil {
 		return "", fmt.Errorf("simpleswap get exchange: %w", err)
 	}
+	swaps.ReportRawStatus(ctx, exchange.Status)
 
 	switch exchange.Status {
 	case "finished":
 		return "completed", nil
-	case "failed", "refunded", "expired":
+	case "refunded":
+		return "refunded", nil
+	case "failed", "expired":
 		return "failed", nil
 	default:
 		// waiting, confirming, exchanging, sending
@@ -205,7 +218,16 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	}
 }
 
-func (p *Provider) transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, key *ecdsa.PrivateKey, from, token, to common.Address, amount *big.Int) (string, error) {
+// Result returns the exchange's payout amount and transaction.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	exchange, err := p.client.GetExchange(ctx, externalID)
+	if err != nil {
+		return swaps.SwapResult{}, fmt.Errorf("simpleswap get exchange: %w", err)
+	}
+	return swaps.SwapResult{OutputAmount: exchange.AmountTo, DestTxHash: exchange.TxTo}, nil
+}
+
+func (p *Provider) transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, to common.Address, amount *big.Int) (string, error) {
 	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
 	if err != nil {
 		return "", err
@@ -227,7 +249,7 @@ func (p *Provider) transferERC20(ctx context.Context, rpc *ethclient.Client, cha
 	}
 
 	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
 	if err != nil {
 		return "", fmt.Errorf("signing transfer tx: %w", err)
 	}
@@ -236,26 +258,12 @@ func (p *Provider) transferERC20(ctx context.Context, rpc *ethclient.Client, cha
 		return "", fmt.Errorf("sending transfer tx: %w", err)
 	}
 
-	log.Printf("SimpleSwap USDC transfer sent: %s", signedTx.Hash().Hex())
+	log.Printf("SimpleSwap token transfer sent: %s", signedTx.Hash().Hex())
 
 	// Don't wait for mining - return immediately and let status polling handle confirmation
 	return signedTx.Hash().Hex(), nil
 }
 
-// mustParseAsset returns a USDC asset for the given source chain.
-func mustParseAsset(chain string) swaps.Asset {
-	switch chain {
-	case "avalanche":
-		a, _ := swaps.ParseAsset("AVAX.USDC-0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E")
-		return a
-	case "base":
-		a, _ := swaps.ParseAsset("BASE.USDC-0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
-		return a
-	default:
-		return swaps.Asset{Chain: strings.ToUpper(chain), Symbol: "USDC"}
-	}
-}
-
 // parseToBigInt parses a decimal string like "0.00123456" to a big.Int
 // by removing the decimal point (treating as raw integer representation).
 // For comparison purposes, we multiply by 1e8 to get a common base.
@@ -284,3 +292,29 @@ func parseToBigInt(s string) *big.Int {
 	val.SetString(combined, 10)
 	return val
 }
+
+func (p *Provider) transferNative(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, to common.Address, amount *big.Int) (string, error) {
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, to, amount, 21000, gasPrice, nil)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing transfer tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending transfer tx: %w", err)
+	}
+
+	log.Printf("SimpleSwap native transfer sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
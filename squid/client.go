@@ -0,0 +1,157 @@
+package squid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const baseURL = "https://v2.api.squidrouter.com/v2"
+
+type Client struct {
+	integratorID string
+	httpClient   *http.Client
+}
+
+func NewClient(integratorID string, httpClient *http.Client) *Client {
+	return &Client{
+		integratorID: integratorID,
+		httpClient:   httpClient,
+	}
+}
+
+// RouteRequest is the body for POST /route.
+type RouteRequest struct {
+	FromAddress    string         `json:"fromAddress"`
+	FromChain      string         `json:"fromChain"`
+	FromToken      string         `json:"fromToken"`
+	FromAmount     string         `json:"fromAmount"`
+	ToChain        string         `json:"toChain"`
+	ToToken        string         `json:"toToken"`
+	ToAddress      string         `json:"toAddress"`
+	SlippageConfig SlippageConfig `json:"slippageConfig"`
+	QuoteOnly      bool           `json:"quoteOnly"`
+}
+
+// SlippageConfig controls Squid's slippage handling. AutoMode 1 lets Squid pick.
+type SlippageConfig struct {
+	AutoMode int `json:"autoMode"`
+}
+
+// TransactionRequest is the unsigned source-chain transaction for a route.
+type TransactionRequest struct {
+	Target   string `json:"target"`
+	Data     string `json:"data"`
+	Value    string `json:"value"`
+	GasLimit string `json:"gasLimit"`
+	GasPrice string `json:"gasPrice"`
+}
+
+// RouteResponse is the response from POST /route.
+type RouteResponse struct {
+	Route struct {
+		Estimate struct {
+			ToAmount               string `json:"toAmount"`
+			ToAmountMin            string `json:"toAmountMin"`
+			EstimatedRouteDuration int64  `json:"estimatedRouteDuration"`
+			ToToken                struct {
+				Symbol   string `json:"symbol"`
+				Decimals int    `json:"decimals"`
+			} `json:"toToken"`
+		} `json:"estimate"`
+		TransactionRequest TransactionRequest `json:"transactionRequest"`
+	} `json:"route"`
+
+	// RequestID comes from the x-request-id response header and is required for status lookups.
+	RequestID string `json:"-"`
+}
+
+// StatusResponse is the response from GET /status.
+type StatusResponse struct {
+	ID                     string `json:"id"`
+	Status                 string `json:"status"`
+	SquidTransactionStatus string `json:"squidTransactionStatus"` // success, ongoing, partial_success, needs_gas, not_found, refund
+}
+
+// GetRoute requests an executable cross-chain route.
+func (c *Client) GetRoute(ctx context.Context, reqBody RouteRequest) (*RouteResponse, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/route", strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-integrator-id", c.integratorID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("squid route: %s: %s", resp.Status, body)
+	}
+
+	var result RouteResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing route response: %w", err)
+	}
+	result.RequestID = resp.Header.Get("x-request-id")
+
+	return &result, nil
+}
+
+// GetStatus retrieves the status of a route by its source tx hash and request ID.
+func (c *Client) GetStatus(ctx context.Context, txHash, requestID, fromChain, toChain string) (*StatusResponse, error) {
+	params := url.Values{}
+	params.Set("transactionId", txHash)
+	params.Set("requestId", requestID)
+	params.Set("fromChainId", fromChain)
+	params.Set("toChainId", toChain)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/status?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-integrator-id", c.integratorID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Squid returns 404 until the source transaction has been indexed.
+	if resp.StatusCode == http.StatusNotFound {
+		return &StatusResponse{SquidTransactionStatus: "not_found"}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("squid status: %s: %s", resp.Status, body)
+	}
+
+	var status StatusResponse
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("parsing status response: %w", err)
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,103 @@
+package squid
+
+import (
+	"strings"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// nativeToken is the address Squid uses for a chain's native gas token on EVM chains.
+const nativeToken = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+
+// Destination identifies a Squid chain and token.
+type Destination struct {
+	ChainID string
+	Token   string
+}
+
+// assetToDestination maps our Asset notation (CHAIN.SYMBOL) to a Squid chain ID and token.
+// Cosmos destinations are the main reason to use Squid; they're reached via Axelar GMP.
+var assetToDestination = map[string]Destination{
+	// Cosmos ecosystem (denoms)
+	"GAIA.ATOM":   {ChainID: "cosmoshub-4", Token: "uatom"},
+	"OSMO.OSMO":   {ChainID: "osmosis-1", Token: "uosmo"},
+	"INJ.INJ":     {ChainID: "injective-1", Token: "inj"},
+	"AXL.AXL":     {ChainID: "axelar-dojo-1", Token: "uaxl"},
+	"NTRN.NTRN":   {ChainID: "neutron-1", Token: "untrn"},
+	"JUNO.JUNO":   {ChainID: "juno-1", Token: "ujuno"},
+	"KUJI.KUJI":   {ChainID: "kaiyo-1", Token: "ukuji"},
+	"STARS.STARS": {ChainID: "stargaze-1", Token: "ustars"},
+	"AKT.AKT":     {ChainID: "akashnet-2", Token: "uakt"},
+	"TIA.TIA":     {ChainID: "celestia", Token: "utia"},
+	"NOBLE.USDC":  {ChainID: "noble-1", Token: "uusdc"},
+	"DYDX.DYDX":   {ChainID: "dydx-mainnet-1", Token: "adydx"},
+	"KAVA.KAVA":   {ChainID: "kava_2222-10", Token: "ukava"},
+
+	// EVM natives
+	"ETH.ETH":       {ChainID: "1", Token: nativeToken},
+	"ARB.ETH":       {ChainID: "42161", Token: nativeToken},
+	"OP.ETH":        {ChainID: "10", Token: nativeToken},
+	"BASE.ETH":      {ChainID: "8453", Token: nativeToken},
+	"AVAX.AVAX":     {ChainID: "43114", Token: nativeToken},
+	"POLYGON.POL":   {ChainID: "137", Token: nativeToken},
+	"BSC.BNB":       {ChainID: "56", Token: nativeToken},
+	"FTM.FTM":       {ChainID: "250", Token: nativeToken},
+	"CELO.CELO":     {ChainID: "42220", Token: nativeToken},
+	"MOONBEAM.GLMR": {ChainID: "1284", Token: nativeToken},
+}
+
+// evmChains maps our chain notation to the Squid chain ID for EVM chains where
+// ERC20 destinations can be addressed by contract.
+var evmChains = map[string]string{
+	"ETH":     "1",
+	"ARB":     "42161",
+	"OP":      "10",
+	"BASE":    "8453",
+	"AVAX":    "43114",
+	"POLYGON": "137",
+	"BSC":     "56",
+}
+
+// sourceChains maps our RPC chain name to the Squid chain ID.
+var sourceChains = map[string]string{
+	"avalanche": "43114",
+	"base":      "8453",
+	"ethereum":  "1",
+	"optimism":  "10",
+	"polygon":   "137",
+}
+
+// AssetToDestination looks up the Squid chain and token for a target asset.
+// ERC20s on supported EVM chains are addressed directly by contract.
+func AssetToDestination(asset swaps.Asset) (Destination, bool) {
+	if asset.ContractAddress != "" {
+		chainID, ok := evmChains[asset.Chain]
+		if !ok {
+			return Destination{}, false
+		}
+		return Destination{ChainID: chainID, Token: asset.ContractAddress}, true
+	}
+
+	dest, ok := assetToDestination[asset.Chain+"."+asset.Symbol]
+	return dest, ok
+}
+
+// IsEVM reports whether a Squid chain ID refers to an EVM chain (numeric IDs).
+func IsEVM(chainID string) bool {
+	return strings.Trim(chainID, "0123456789") == ""
+}
+
+// SourceChainID returns the Squid chain ID for a source RPC chain.
+func SourceChainID(chain string) (string, bool) {
+	id, ok := sourceChains[chain]
+	return id, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that Squid can source USDC from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceChains))
+	for k := range sourceChains {
+		chains = append(chains, k)
+	}
+	return chains
+}
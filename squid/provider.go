@@ -0,0 +1,359 @@
+package squid
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(integratorID string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(integratorID, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "squid"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, ok := AssetToDestination(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	dest, ok := AssetToDestination(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("squid: unsupported target asset %s", toAsset)
+	}
+	if IsEVM(dest.ChainID) && !common.IsHexAddress(destination) {
+		return nil, fmt.Errorf("squid: destination %q is not an EVM address", destination)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		fromChainID, ok := SourceChainID(chain)
+		if !ok {
+			continue
+		}
+
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Pick the first funding token with enough balance on this chain
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, nil)
+		if err != nil {
+			log.Printf("squid: skipping %s: %v", chain, err)
+			continue
+		}
+
+		route, err := p.client.GetRoute(ctx, RouteRequest{
+			FromAddress:    sender.Hex(),
+			FromChain:      fromChainID,
+			FromToken:      token.Address.Hex(),
+			FromAmount:     inputAmount.String(),
+			ToChain:        dest.ChainID,
+			ToToken:        dest.Token,
+			ToAddress:      destination,
+			SlippageConfig: SlippageConfig{AutoMode: 1},
+		})
+		if err != nil {
+			log.Printf("squid quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+		if route.Route.TransactionRequest.Target == "" {
+			log.Printf("squid quote for %s via %s returned no transaction", toAsset, chain)
+			continue
+		}
+
+		toAmount, ok := new(big.Int).SetString(route.Route.Estimate.ToAmount, 10)
+		if !ok {
+			log.Printf("squid: invalid toAmount %q", route.Route.Estimate.ToAmount)
+			continue
+		}
+		expectedOut := formatUnits(toAmount, route.Route.Estimate.ToToken.Decimals)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "squid",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    expectedOut,
+			ExpectedOutputRaw: parseToBigInt(expectedOut),
+			Router:            route.Route.TransactionRequest.Target,
+			ExtraData: map[string]interface{}{
+				"squid_request_id": route.RequestID,
+				"squid_from_chain": fromChainID,
+				"squid_to_chain":   dest.ChainID,
+				"squid_tx":         route.Route.TransactionRequest,
+				"squid_min_out":    route.Route.Estimate.ToAmountMin,
+				"squid_duration_s": route.Route.Estimate.EstimatedRouteDuration,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("squid: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	txReq, ok := quote.ExtraData["squid_tx"].(TransactionRequest)
+	if !ok || txReq.Target == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("squid: missing transaction in quote ExtraData")
+	}
+	requestID, _ := quote.ExtraData["squid_request_id"].(string)
+	fromChain, _ := quote.ExtraData["squid_from_chain"].(string)
+	toChain, _ := quote.ExtraData["squid_to_chain"].(string)
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	target := common.HexToAddress(txReq.Target)
+	fromAddr := signer.Address()
+
+	// Step 1: Approve the Squid router to spend the funding token
+	if err := approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, target, quote.InputAmount); err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", token.Symbol, err)
+	}
+
+	// Step 2: Send the route transaction
+	txHash, err := sendRouteTx(ctx, rpc, chainID, signer, fromAddr, txReq)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("route tx: %w", err)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: encodeExternalID(requestID, fromChain, toChain),
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	requestID, fromChain, toChain, ok := decodeExternalID(externalID)
+	if !ok {
+		return "", fmt.Errorf("squid: invalid external ID %q", externalID)
+	}
+
+	status, err := p.client.GetStatus(ctx, txHash, requestID, fromChain, toChain)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, status.SquidTransactionStatus)
+
+	switch status.SquidTransactionStatus {
+	case "success":
+		return "completed", nil
+	case "refund":
+		return "refunded", nil
+	case "partial_success":
+		// partial_success means the bridge leg landed but the destination swap reverted;
+		// the user received an intermediate token rather than what they asked for.
+		return "failed", nil
+	default:
+		// ongoing, needs_gas, not_found
+		return "pending", nil
+	}
+}
+
+// encodeExternalID packs the fields Squid's status API needs into a single string.
+func encodeExternalID(requestID, fromChain, toChain string) string {
+	return requestID + "|" + fromChain + "|" + toChain
+}
+
+func decodeExternalID(s string) (requestID, fromChain, toChain string, ok bool) {
+	parts := strings.Split(s, "|")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
+	parsed, err := abi.JSON(strings.NewReader(thorchain.ERC20ApproveABI))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("signing approve tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("sending approve tx: %w", err)
+	}
+
+	log.Printf("Squid approve tx sent: %s", signedTx.Hash().Hex())
+
+	// Wait for approval to be mined with 2-minute timeout (route tx depends on this)
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return fmt.Errorf("waiting for approve: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx failed")
+	}
+
+	return nil
+}
+
+func sendRouteTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, txReq TransactionRequest) (string, error) {
+	data, err := hexutil.Decode(txReq.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	// Value covers Axelar gas fees for cross-chain routes
+	value := new(big.Int)
+	if txReq.Value != "" {
+		if _, ok := value.SetString(txReq.Value, 10); !ok {
+			return "", fmt.Errorf("invalid value %q", txReq.Value)
+		}
+	}
+
+	gasLimit, ok := new(big.Int).SetString(txReq.GasLimit, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid gas limit %q", txReq.GasLimit)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, common.HexToAddress(txReq.Target), value, gasLimit.Uint64(), gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing route tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending route tx: %w", err)
+	}
+
+	log.Printf("Squid route tx sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// formatUnits renders an integer amount with the given number of decimals.
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+	s := amount.String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	return s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
@@ -0,0 +1,201 @@
+package stealthex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const baseURL = "https://api.stealthex.io/v4"
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// CurrencyRef identifies a currency on StealthEX by symbol and network.
+type CurrencyRef struct {
+	Symbol  string `json:"symbol"`
+	Network string `json:"network"`
+}
+
+// Route describes the from/to pair for an estimate or exchange.
+type Route struct {
+	From CurrencyRef `json:"from"`
+	To   CurrencyRef `json:"to"`
+}
+
+// Currency represents a supported currency from StealthEX.
+type Currency struct {
+	Symbol          string `json:"symbol"`
+	Network         string `json:"network"`
+	Name            string `json:"name"`
+	ContractAddress string `json:"contract_address"`
+	HasExtraID      bool   `json:"has_extra_id"`
+}
+
+// EstimateResponse is the response from POST /rates/estimated-amount.
+type EstimateResponse struct {
+	EstimatedAmount float64 `json:"estimated_amount"`
+	RateID          string  `json:"rate_id"`
+}
+
+// RangeResponse is the response from POST /rates/range.
+type RangeResponse struct {
+	MinAmount float64  `json:"min_amount"`
+	MaxAmount *float64 `json:"max_amount"`
+}
+
+// Exchange represents a StealthEX exchange.
+type Exchange struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Deposit struct {
+		Symbol  string  `json:"symbol"`
+		Network string  `json:"network"`
+		Address string  `json:"address"`
+		ExtraID string  `json:"extra_id"`
+		Amount  float64 `json:"amount"`
+	} `json:"deposit"`
+	Withdrawal struct {
+		Symbol  string  `json:"symbol"`
+		Network string  `json:"network"`
+		Address string  `json:"address"`
+		Amount  float64 `json:"amount"`
+		Hash    string  `json:"hash"`
+	} `json:"withdrawal"`
+}
+
+// GetCurrencies returns all supported currencies from StealthEX.
+func (c *Client) GetCurrencies(ctx context.Context) ([]Currency, error) {
+	var currencies []Currency
+	if err := c.do(ctx, http.MethodGet, baseURL+"/currencies?limit=250&offset=0", nil, &currencies); err != nil {
+		return nil, fmt.Errorf("stealthex currencies: %w", err)
+	}
+
+	// The API pages currencies; keep fetching until a short page is returned.
+	for offset := len(currencies); offset > 0 && offset%250 == 0; offset = len(currencies) {
+		var page []Currency
+		u := fmt.Sprintf("%s/currencies?limit=250&offset=%d", baseURL, offset)
+		if err := c.do(ctx, http.MethodGet, u, nil, &page); err != nil {
+			return nil, fmt.Errorf("stealthex currencies: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		currencies = append(currencies, page...)
+	}
+
+	return currencies, nil
+}
+
+// GetRange returns the min/max amounts (in source currency units) for a route.
+func (c *Client) GetRange(ctx context.Context, route Route) (*RangeResponse, error) {
+	payload := map[string]interface{}{
+		"route":      route,
+		"estimation": "direct",
+		"rate":       "floating",
+	}
+
+	var result RangeResponse
+	if err := c.do(ctx, http.MethodPost, baseURL+"/rates/range", payload, &result); err != nil {
+		return nil, fmt.Errorf("stealthex range: %w", err)
+	}
+	return &result, nil
+}
+
+// GetEstimate returns the estimated output amount for a floating-rate swap.
+func (c *Client) GetEstimate(ctx context.Context, route Route, amount float64) (*EstimateResponse, error) {
+	payload := map[string]interface{}{
+		"route":      route,
+		"estimation": "direct",
+		"rate":       "floating",
+		"amount":     amount,
+	}
+
+	var result EstimateResponse
+	if err := c.do(ctx, http.MethodPost, baseURL+"/rates/estimated-amount", payload, &result); err != nil {
+		return nil, fmt.Errorf("stealthex estimate: %w", err)
+	}
+	return &result, nil
+}
+
+// CreateExchange creates a floating-rate exchange and returns the deposit details.
+func (c *Client) CreateExchange(ctx context.Context, route Route, amount float64, addressTo, refundAddress string) (*Exchange, error) {
+	payload := map[string]interface{}{
+		"route":          route,
+		"amount":         amount,
+		"estimation":     "direct",
+		"rate":           "floating",
+		"address":        addressTo,
+		"extra_id":       "",
+		"refund_address": refundAddress,
+	}
+
+	var exchange Exchange
+	if err := c.do(ctx, http.MethodPost, baseURL+"/exchanges", payload, &exchange); err != nil {
+		return nil, fmt.Errorf("stealthex create exchange: %w", err)
+	}
+	return &exchange, nil
+}
+
+// GetExchange retrieves the current state of an exchange.
+func (c *Client) GetExchange(ctx context.Context, id string) (*Exchange, error) {
+	var exchange Exchange
+	u := fmt.Sprintf("%s/exchanges/%s", baseURL, url.PathEscape(id))
+	if err := c.do(ctx, http.MethodGet, u, nil, &exchange); err != nil {
+		return nil, fmt.Errorf("stealthex get exchange: %w", err)
+	}
+	return &exchange, nil
+}
+
+func (c *Client) do(ctx context.Context, method, u string, payload interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		jsonBody, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = strings.NewReader(string(jsonBody))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
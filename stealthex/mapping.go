@@ -0,0 +1,108 @@
+package stealthex
+
+import (
+	"strings"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// assetToCurrency maps our Asset notation (CHAIN.SYMBOL) to a StealthEX symbol/network pair.
+// Focused on privacy coins and L1s that the other custodial providers don't list.
+var assetToCurrency = map[string]CurrencyRef{
+	// Major L1s
+	"BTC.BTC":   {Symbol: "btc", Network: "mainnet"},
+	"ETH.ETH":   {Symbol: "eth", Network: "mainnet"},
+	"SOL.SOL":   {Symbol: "sol", Network: "mainnet"},
+	"AVAX.AVAX": {Symbol: "avax", Network: "avaxc"},
+	"DOT.DOT":   {Symbol: "dot", Network: "mainnet"},
+	"ADA.ADA":   {Symbol: "ada", Network: "mainnet"},
+	"TON.TON":   {Symbol: "ton", Network: "mainnet"},
+	"TRX.TRX":   {Symbol: "trx", Network: "mainnet"},
+	"XRP.XRP":   {Symbol: "xrp", Network: "mainnet"},
+
+	// Privacy coins
+	"XMR.XMR":   {Symbol: "xmr", Network: "mainnet"},
+	"ZEC.ZEC":   {Symbol: "zec", Network: "mainnet"},
+	"DASH.DASH": {Symbol: "dash", Network: "mainnet"},
+	"FIRO.FIRO": {Symbol: "firo", Network: "mainnet"},
+	"ARRR.ARRR": {Symbol: "arrr", Network: "mainnet"},
+	"BEAM.BEAM": {Symbol: "beam", Network: "mainnet"},
+
+	// Exotic L1s
+	"XLM.XLM":   {Symbol: "xlm", Network: "mainnet"},
+	"ALGO.ALGO": {Symbol: "algo", Network: "mainnet"},
+	"NEAR.NEAR": {Symbol: "near", Network: "mainnet"},
+	"KAS.KAS":   {Symbol: "kas", Network: "mainnet"},
+	"ETC.ETC":   {Symbol: "etc", Network: "mainnet"},
+	"EGLD.EGLD": {Symbol: "egld", Network: "mainnet"},
+	"HBAR.HBAR": {Symbol: "hbar", Network: "mainnet"},
+	"ICP.ICP":   {Symbol: "icp", Network: "mainnet"},
+	"FIL.FIL":   {Symbol: "fil", Network: "mainnet"},
+	"XTZ.XTZ":   {Symbol: "xtz", Network: "mainnet"},
+	"APT.APT":   {Symbol: "apt", Network: "mainnet"},
+	"INJ.INJ":   {Symbol: "inj", Network: "mainnet"},
+	"KDA.KDA":   {Symbol: "kda", Network: "mainnet"},
+	"RVN.RVN":   {Symbol: "rvn", Network: "mainnet"},
+	"ERG.ERG":   {Symbol: "erg", Network: "mainnet"},
+
+	// UTXO chains
+	"LTC.LTC":   {Symbol: "ltc", Network: "mainnet"},
+	"BCH.BCH":   {Symbol: "bch", Network: "mainnet"},
+	"DOGE.DOGE": {Symbol: "doge", Network: "mainnet"},
+
+	// Cosmos ecosystem
+	"GAIA.ATOM": {Symbol: "atom", Network: "mainnet"},
+	"OSMO.OSMO": {Symbol: "osmo", Network: "mainnet"},
+}
+
+// sourceChainCurrency maps our RPC chain name to the StealthEX USDC currency for that chain.
+var sourceChainCurrency = map[string]CurrencyRef{
+	"avalanche": {Symbol: "usdc", Network: "avaxc"},
+	"base":      {Symbol: "usdc", Network: "base"},
+}
+
+// AssetToCurrency looks up the StealthEX currency for a target asset.
+func AssetToCurrency(asset swaps.Asset) (CurrencyRef, bool) {
+	key := asset.Chain + "." + asset.Symbol
+	cur, ok := assetToCurrency[key]
+	return cur, ok
+}
+
+// LookupCurrency checks the static mapping by a CHAIN.SYMBOL key string (uppercase)
+// and returns the currency encoded as "symbol:network".
+func LookupCurrency(key string) (string, bool) {
+	cur, ok := assetToCurrency[key]
+	if !ok {
+		return "", false
+	}
+	return cur.String(), true
+}
+
+// ParseCurrency decodes a "symbol:network" string produced by CurrencyRef.String.
+func ParseCurrency(s string) (CurrencyRef, bool) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return CurrencyRef{}, false
+	}
+	return CurrencyRef{Symbol: parts[0], Network: parts[1]}, true
+}
+
+// String encodes the currency as "symbol:network" for use in resolver hints.
+func (c CurrencyRef) String() string {
+	return c.Symbol + ":" + c.Network
+}
+
+// SourceCurrency returns the StealthEX USDC currency for a source chain.
+func SourceCurrency(chain string) (CurrencyRef, bool) {
+	cur, ok := sourceChainCurrency[chain]
+	return cur, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that StealthEX can source USDC from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceChainCurrency))
+	for k := range sourceChainCurrency {
+		chains = append(chains, k)
+	}
+	return chains
+}
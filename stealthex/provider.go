@@ -0,0 +1,299 @@
+package stealthex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+const erc20TransferABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(apiKey, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "stealthex"
+}
+
+func (p *Provider) Category() string {
+	return "private"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+// CheckKey verifies the API key by fetching one currency.
+func (p *Provider) CheckKey(ctx context.Context) error {
+	var page []Currency
+	return p.client.do(ctx, http.MethodGet, baseURL+"/currencies?limit=1&offset=0", nil, &page)
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, ok := AssetToCurrency(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	var to CurrencyRef
+	var ok bool
+	if toAsset.Hints != nil && toAsset.Hints.StealthEXCurrency != "" {
+		to, ok = ParseCurrency(toAsset.Hints.StealthEXCurrency)
+	} else {
+		to, ok = AssetToCurrency(toAsset)
+	}
+	if !ok {
+		return nil, fmt.Errorf("stealthex: unsupported target asset %s", toAsset)
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		from, ok := SourceCurrency(chain)
+		if !ok {
+			continue
+		}
+		route := Route{From: from, To: to}
+
+		// Check dynamic minimum
+		rng, err := p.client.GetRange(ctx, route)
+		if err != nil {
+			log.Printf("stealthex: error checking range for %s→%s: %v", from, to, err)
+			continue
+		}
+		if usdAmount < rng.MinAmount {
+			log.Printf("stealthex: skipping %s, below minimum $%.2f (requested $%.2f)", chain, rng.MinAmount, usdAmount)
+			continue
+		}
+		if rng.MaxAmount != nil && usdAmount > *rng.MaxAmount {
+			log.Printf("stealthex: skipping %s, above maximum $%.2f (requested $%.2f)", chain, *rng.MaxAmount, usdAmount)
+			continue
+		}
+
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// StealthEX only lists USDC on our source networks
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, swaps.OnlySymbol("USDC"))
+		if err != nil {
+			log.Printf("stealthex: skipping %s: %v", chain, err)
+			continue
+		}
+
+		estimate, err := p.client.GetEstimate(ctx, route, usdAmount)
+		if err != nil {
+			log.Printf("stealthex quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+
+		estimated := strconv.FormatFloat(estimate.EstimatedAmount, 'f', -1, 64)
+		expectedOut := parseToBigInt(estimated)
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "stealthex",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    estimated,
+			ExpectedOutputRaw: expectedOut,
+			ExtraData: map[string]interface{}{
+				"stealthex_from":        from.String(),
+				"stealthex_to":          to.String(),
+				"stealthex_destination": destination,
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("stealthex: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	fromStr, _ := quote.ExtraData["stealthex_from"].(string)
+	toStr, _ := quote.ExtraData["stealthex_to"].(string)
+	from, okFrom := ParseCurrency(fromStr)
+	to, okTo := ParseCurrency(toStr)
+	if !okFrom || !okTo {
+		return swaps.ExecuteResult{}, fmt.Errorf("stealthex: missing exchange currencies in quote ExtraData")
+	}
+
+	destination, _ := quote.ExtraData["stealthex_destination"].(string)
+	if destination == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("stealthex: missing destination in quote ExtraData")
+	}
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	exchange, err := p.client.CreateExchange(ctx, Route{From: from, To: to}, quote.InputAmountUSD, destination, fromAddr.Hex())
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+
+	log.Printf("StealthEX exchange created: id=%s, deposit=%s", exchange.ID, exchange.Deposit.Address)
+
+	txHash, err := transferERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, common.HexToAddress(exchange.Deposit.Address), quote.InputAmount)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("stealthex USDC transfer: %w", err)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: exchange.ID,
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	if externalID == "" {
+		return "pending", nil
+	}
+
+	exchange, err := p.client.GetExchange(ctx, externalID)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, exchange.Status)
+
+	switch exchange.Status {
+	case "finished":
+		return "completed", nil
+	case "refunded":
+		return "refunded", nil
+	case "failed", "expired":
+		return "failed", nil
+	default:
+		// waiting, confirming, exchanging, sending, verifying
+		return "pending", nil
+	}
+}
+
+// Result returns the exchange's withdrawal amount and transaction.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	exchange, err := p.client.GetExchange(ctx, externalID)
+	if err != nil {
+		return swaps.SwapResult{}, err
+	}
+	var result swaps.SwapResult
+	if exchange.Withdrawal.Amount > 0 {
+		result.OutputAmount = fmt.Sprintf("%g", exchange.Withdrawal.Amount)
+	}
+	result.DestTxHash = exchange.Withdrawal.Hash
+	return result, nil
+}
+
+func transferERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, to common.Address, amount *big.Int) (string, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
+	if err != nil {
+		return "", err
+	}
+
+	data, err := parsed.Pack("transfer", to, amount)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing transfer tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending transfer tx: %w", err)
+	}
+
+	log.Printf("StealthEX USDC transfer sent: %s", signedTx.Hash().Hex())
+
+	// Don't wait for mining - return immediately and let status polling handle confirmation
+	return signedTx.Hash().Hex(), nil
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
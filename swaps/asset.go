@@ -3,6 +3,8 @@ package swaps
 import (
 	"fmt"
 	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Asset represents a blockchain asset in Thorchain notation: CHAIN.SYMBOL or CHAIN.SYMBOL-0xCONTRACT
@@ -15,6 +17,8 @@ type Asset struct {
 
 // ParseAsset parses Thorchain asset notation.
 // Examples: "BTC.BTC", "ETH.USDC-0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+// A bare EVM contract address, e.g. "BASE.0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+// parses with an empty symbol for the resolver to look up.
 func ParseAsset(s string) (Asset, error) {
 	parts := strings.SplitN(s, ".", 2)
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
@@ -24,6 +28,10 @@ func ParseAsset(s string) (Asset, error) {
 	chain := strings.ToUpper(parts[0])
 	symbolPart := parts[1]
 
+	if strings.HasPrefix(symbolPart, "0x") && common.IsHexAddress(symbolPart) {
+		return Asset{Chain: chain, ContractAddress: symbolPart}, nil
+	}
+
 	var symbol, contract string
 	if idx := strings.Index(symbolPart, "-"); idx != -1 {
 		symbol = strings.ToUpper(symbolPart[:idx])
@@ -41,12 +49,21 @@ func ParseAsset(s string) (Asset, error) {
 
 // String returns the asset in Thorchain notation.
 func (a Asset) String() string {
+	if a.Symbol == "" {
+		return fmt.Sprintf("%s.%s", a.Chain, a.ContractAddress)
+	}
 	if a.ContractAddress != "" {
 		return fmt.Sprintf("%s.%s-%s", a.Chain, a.Symbol, a.ContractAddress)
 	}
 	return fmt.Sprintf("%s.%s", a.Chain, a.Symbol)
 }
 
+// ContractOnly returns true for an asset given by contract address alone,
+// whose symbol is not known yet.
+func (a Asset) ContractOnly() bool {
+	return a.Symbol == "" && a.ContractAddress != ""
+}
+
 // IsNative returns true if the asset is a chain-native asset (no contract address).
 func (a Asset) IsNative() bool {
 	return a.ContractAddress == ""
@@ -58,4 +75,8 @@ type ResolvedHints struct {
 	SimpleSwapSymbol   string
 	NearIntentsTokenID string
 	HoudiniSymbol      string
+	StealthEXCurrency  string // "symbol:network"
+	RelayCurrency      string // ERC20 contract address on the destination chain
+	ZeroExToken        string // ERC20 contract address on the destination chain
+	UniswapToken       string // ERC20 contract address on Base
 }
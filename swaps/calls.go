@@ -0,0 +1,45 @@
+package swaps
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// Call is a single contract call made while executing a swap.
+type Call struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// CallSender is a signer that executes calls from its own address instead of
+// signing transactions, such as an ERC-4337 smart account. The calls run
+// atomically in one batch.
+type CallSender interface {
+	wallet.Signer
+
+	// SendCalls executes calls on chain and returns the hash of the
+	// transaction that included them, once mined.
+	SendCalls(ctx context.Context, chain string, calls []Call) (string, error)
+}
+
+// CallExecutor is implemented by providers that can run a swap as a batch of
+// calls from a CallSender.
+type CallExecutor interface {
+	ExecuteCalls(ctx context.Context, quote Quote, sender CallSender) (ExecuteResult, error)
+}
+
+// CallExecutors returns the providers that implement CallExecutor.
+func CallExecutors(providers []Provider) []Provider {
+	var filtered []Provider
+	for _, p := range providers {
+		if _, ok := p.(CallExecutor); ok {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
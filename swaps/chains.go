@@ -0,0 +1,70 @@
+package swaps
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ChainIDs are the EVM chain IDs of the RPC chain keys, for checking that a
+// configured RPC serves the chain it is named for.
+var ChainIDs = map[string]int64{
+	"arbitrum":  42161,
+	"avalanche": 43114,
+	"base":      8453,
+	"bsc":       56,
+	"ethereum":  1,
+	"gnosis":    100,
+	"optimism":  10,
+	"polygon":   137,
+}
+
+// disabledChains holds the RPC chain keys operators have switched off, e.g.
+// while a chain's RPC is broken. Disabled chains are skipped for quoting,
+// balance fetching, status tracking and gas refills.
+var (
+	chainsMu       sync.RWMutex
+	disabledChains = map[string]bool{}
+)
+
+// ChainEnabled reports whether swaps may use the given RPC chain key.
+func ChainEnabled(chain string) bool {
+	chainsMu.RLock()
+	defer chainsMu.RUnlock()
+	return !disabledChains[chain]
+}
+
+// SetChainEnabled switches a chain on or off at runtime.
+func SetChainEnabled(chain string, enabled bool) {
+	chainsMu.Lock()
+	defer chainsMu.Unlock()
+	if enabled {
+		delete(disabledChains, chain)
+	} else {
+		disabledChains[chain] = true
+	}
+}
+
+// DisabledChains returns the currently disabled chain keys, sorted.
+func DisabledChains() []string {
+	chainsMu.RLock()
+	defer chainsMu.RUnlock()
+	chains := make([]string, 0, len(disabledChains))
+	for chain := range disabledChains {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
+
+// EnabledClients returns the subset of rpcClients whose chains are enabled.
+func EnabledClients(rpcClients map[string]*ethclient.Client) map[string]*ethclient.Client {
+	enabled := make(map[string]*ethclient.Client, len(rpcClients))
+	for chain, rpc := range rpcClients {
+		if ChainEnabled(chain) {
+			enabled[chain] = rpc
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,95 @@
+package swaps
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// freshGasUnits is the native gas sent along to a fresh address: enough for
+// an approval plus the deposit or swap transaction.
+const freshGasUnits = 600_000
+
+// FundFresh moves amount of token, plus enough native gas for the swap, from
+// signer to a freshly derived address on chain and waits for the transfers to
+// be mined. Returns the hash of the token transfer.
+func (m *Manager) FundFresh(ctx context.Context, chain string, signer wallet.Signer, fresh common.Address, token FundingToken, amount *big.Int) (string, error) {
+	rpc, ok := m.rpcClients[chain]
+	if !ok {
+		return "", fmt.Errorf("no RPC client for %s", chain)
+	}
+
+	chainID, err := rpc.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting chain ID: %w", err)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, signer.Address())
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+	gas := new(big.Int).Mul(gasPrice, big.NewInt(freshGasUnits))
+
+	var txs []*types.Transaction
+	if token.Native {
+		value := new(big.Int).Add(amount, gas)
+		txs = append(txs, types.NewTransaction(nonce, fresh, value, 21000, gasPrice, nil))
+	} else {
+		parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
+		if err != nil {
+			return "", err
+		}
+		data, err := parsed.Pack("transfer", fresh, amount)
+		if err != nil {
+			return "", err
+		}
+		txs = append(txs,
+			types.NewTransaction(nonce, token.Address, big.NewInt(0), 100000, gasPrice, data),
+			types.NewTransaction(nonce+1, fresh, gas, 21000, gasPrice, nil),
+		)
+	}
+
+	var signed []*types.Transaction
+	for _, tx := range txs {
+		signedTx, err := signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			return "", fmt.Errorf("signing funding tx: %w", err)
+		}
+		if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+			return "", fmt.Errorf("sending funding tx: %w", err)
+		}
+		log.Printf("Fresh address funding tx sent: %s", signedTx.Hash().Hex())
+		signed = append(signed, signedTx)
+	}
+
+	// The swap is funded from the fresh address, so it can't start until both land
+	waitCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	for _, signedTx := range signed {
+		receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+		if err != nil {
+			return "", fmt.Errorf("waiting for funding tx %s: %w", signedTx.Hash().Hex(), err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return "", fmt.Errorf("funding tx %s failed", signedTx.Hash().Hex())
+		}
+	}
+
+	return signed[0].Hash().Hex(), nil
+}
@@ -0,0 +1,279 @@
+package swaps
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/balances"
+)
+
+// FundingToken is a token accepted to fund swaps on a source chain: usually a
+// stablecoin, or the chain's native coin when Native is set.
+type FundingToken struct {
+	Symbol   string
+	Address  common.Address // zero for native coins
+	Decimals int
+	Native   bool
+}
+
+// nativeGasUnits is the gas kept in reserve when sizing a native-funded swap,
+// so the wallet can still pay for the deposit transaction itself.
+const nativeGasUnits = 300_000
+
+// NativePricer returns the USD price of a chain's native coin.
+type NativePricer interface {
+	NativePriceUSD(ctx context.Context, chain string) (float64, error)
+}
+
+var nativePricer NativePricer
+
+// SetNativePricer installs the price source used to size native-funded swaps
+// and value balances. Without one, native funding tokens are never selected
+// and native balances are left unvalued.
+func SetNativePricer(p NativePricer) {
+	nativePricer = p
+}
+
+// nativePriceTTL is how long NativePriceUSD reuses a price, so a balance view
+// across many wallets asks the price source once per chain.
+const nativePriceTTL = time.Minute
+
+type nativePrice struct {
+	usd float64
+	at  time.Time
+}
+
+var (
+	nativePricesMu sync.Mutex
+	nativePrices   = make(map[string]nativePrice)
+)
+
+// NativePriceUSD returns the USD price of a chain's native coin from the
+// installed NativePricer, reusing prices fetched in the last minute.
+func NativePriceUSD(ctx context.Context, chain string) (float64, error) {
+	nativePricesMu.Lock()
+	cached, ok := nativePrices[chain]
+	nativePricesMu.Unlock()
+	if ok && time.Since(cached.at) < nativePriceTTL {
+		return cached.usd, nil
+	}
+
+	if nativePricer == nil {
+		return 0, fmt.Errorf("no native price source")
+	}
+	usd, err := nativePricer.NativePriceUSD(ctx, chain)
+	if err != nil {
+		return 0, err
+	}
+	if usd <= 0 {
+		return 0, fmt.Errorf("invalid %s native price %f", chain, usd)
+	}
+
+	nativePricesMu.Lock()
+	nativePrices[chain] = nativePrice{usd: usd, at: time.Now()}
+	nativePricesMu.Unlock()
+	return usd, nil
+}
+
+// Amount converts a USD amount to the token's smallest unit, assuming a $1 peg.
+// The USD amount is first taken to 6 decimals, matching the USDC math used elsewhere.
+func (t FundingToken) Amount(usd float64) *big.Int {
+	micro := new(big.Int).SetInt64(int64(usd * 1e6))
+	if t.Decimals >= 6 {
+		return micro.Mul(micro, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals-6)), nil))
+	}
+	return micro.Div(micro, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(6-t.Decimals)), nil))
+}
+
+// AmountAt converts a USD amount to the token's smallest unit at the given USD
+// price per whole token. Used for native coins, which aren't $1-pegged.
+func (t FundingToken) AmountAt(usd, price float64) *big.Int {
+	units := new(big.Float).Quo(big.NewFloat(usd), big.NewFloat(price))
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil))
+	amount, _ := units.Mul(units, unit).Int(nil)
+	return amount
+}
+
+// Units converts a raw amount to whole tokens (e.g. 1500000 USDC -> 1.5).
+func (t FundingToken) Units(raw *big.Int) float64 {
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil))
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), unit).Float64()
+	return f
+}
+
+// Format renders a raw token amount with two decimal places (e.g. "12.34").
+func (t FundingToken) Format(raw *big.Int) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil)
+	whole := new(big.Int).Div(raw, unit)
+	frac := new(big.Int).Mod(raw, unit)
+	// Scale the fractional part down to cents
+	cents := new(big.Int).Div(new(big.Int).Mul(frac, big.NewInt(100)), unit)
+	return fmt.Sprintf("%s.%02d", whole, cents.Int64())
+}
+
+// assetChains maps RPC chain key to the chain prefix used in asset notation.
+var assetChains = map[string]string{
+	"arbitrum":  "ARB",
+	"avalanche": "AVAX",
+	"base":      "BASE",
+	"bsc":       "BSC",
+	"ethereum":  "ETH",
+	"optimism":  "OP",
+	"polygon":   "POLYGON",
+}
+
+// Asset returns the token as an Asset on the given source chain (e.g. "BASE.USDC-0x...").
+func (t FundingToken) Asset(chain string) Asset {
+	prefix, ok := assetChains[chain]
+	if !ok {
+		prefix = strings.ToUpper(chain)
+	}
+	if t.Native {
+		return Asset{Chain: prefix, Symbol: t.Symbol}
+	}
+	return Asset{Chain: prefix, Symbol: t.Symbol, ContractAddress: t.Address.Hex()}
+}
+
+// AssetChain returns the RPC chain key of a chain prefix in asset notation
+// (e.g. "ARB" → "arbitrum"), or false for chains the bot has no RPC for,
+// such as "BTC".
+func AssetChain(prefix string) (string, bool) {
+	for chain, p := range assetChains {
+		if strings.EqualFold(p, prefix) {
+			return chain, true
+		}
+	}
+	return "", false
+}
+
+// FundingTokens maps RPC chain key to the stablecoins accepted there, in preference order.
+type FundingTokens map[string][]FundingToken
+
+// Lookup returns the funding token with the given symbol on a chain.
+func (f FundingTokens) Lookup(chain, symbol string) (FundingToken, bool) {
+	for _, t := range f[chain] {
+		if strings.EqualFold(t.Symbol, symbol) {
+			return t, true
+		}
+	}
+	return FundingToken{}, false
+}
+
+// ERC20s returns the non-native funding tokens on a chain, in preference order.
+func (f FundingTokens) ERC20s(chain string) []FundingToken {
+	var tokens []FundingToken
+	for _, t := range f[chain] {
+		if !t.Native {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// Contracts returns the ERC20 funding token addresses per chain, in ERC20s
+// order, for balance lookups. Native balances are fetched separately.
+func (f FundingTokens) Contracts() map[string][]common.Address {
+	contracts := make(map[string][]common.Address, len(f))
+	for chain := range f {
+		for _, t := range f.ERC20s(chain) {
+			contracts[chain] = append(contracts[chain], t.Address)
+		}
+	}
+	return contracts
+}
+
+// Decimals returns the ERC20 funding token decimals per chain, in ERC20s
+// order, to value the balances Contracts looks up.
+func (f FundingTokens) Decimals() map[string][]int {
+	decimals := make(map[string][]int, len(f))
+	for chain := range f {
+		for _, t := range f.ERC20s(chain) {
+			decimals[chain] = append(decimals[chain], t.Decimals)
+		}
+	}
+	return decimals
+}
+
+// Select returns the first token on chain (in preference order) that accept
+// allows and that sender holds at least usdAmount of, along with the required
+// amount in that token's smallest unit. accept may be nil to allow any ERC20;
+// native coins are only considered when accept allows them explicitly.
+func (f FundingTokens) Select(ctx context.Context, rpc *ethclient.Client, chain string, sender common.Address, usdAmount float64, accept func(FundingToken) bool) (FundingToken, *big.Int, error) {
+	if !ChainEnabled(chain) {
+		return FundingToken{}, nil, fmt.Errorf("%s is disabled", chain)
+	}
+
+	var have []string
+	for _, t := range f[chain] {
+		if accept == nil && t.Native || accept != nil && !accept(t) {
+			continue
+		}
+		if t.Native {
+			amount, required, bal, err := nativeRequirement(ctx, rpc, sender, chain, t, usdAmount)
+			if err != nil {
+				log.Printf("funding: skipping native %s on %s: %v", t.Symbol, chain, err)
+				continue
+			}
+			if bal.Cmp(required) >= 0 {
+				return t, amount, nil
+			}
+			have = append(have, fmt.Sprintf("%s %s", t.Format(bal), t.Symbol))
+			continue
+		}
+		required := t.Amount(usdAmount)
+		bal, err := balances.TokenBalance(ctx, rpc, t.Address, sender)
+		if err != nil {
+			return FundingToken{}, nil, fmt.Errorf("checking %s balance: %w", t.Symbol, err)
+		}
+		if bal.Cmp(required) >= 0 {
+			return t, required, nil
+		}
+		have = append(have, fmt.Sprintf("%s %s", t.Format(bal), t.Symbol))
+	}
+	if len(have) == 0 {
+		return FundingToken{}, nil, fmt.Errorf("no accepted funding token on %s", chain)
+	}
+	return FundingToken{}, nil, fmt.Errorf("insufficient stablecoin balance (have %s, need $%.2f)", strings.Join(have, ", "), usdAmount)
+}
+
+// nativeRequirement prices a native-funded swap. It returns the amount to sell,
+// the balance needed to cover it plus gas for the deposit, and the current balance.
+func nativeRequirement(ctx context.Context, rpc *ethclient.Client, sender common.Address, chain string, t FundingToken, usdAmount float64) (amount, required, bal *big.Int, err error) {
+	price, err := NativePriceUSD(ctx, chain)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pricing %s: %w", t.Symbol, err)
+	}
+	amount = t.AmountAt(usdAmount, price)
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("getting gas price: %w", err)
+	}
+	required = new(big.Int).Add(amount, new(big.Int).Mul(gasPrice, big.NewInt(nativeGasUnits)))
+
+	bal, err = rpc.BalanceAt(ctx, sender, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("checking %s balance: %w", t.Symbol, err)
+	}
+	return amount, required, bal, nil
+}
+
+// AnyToken is a Select filter that accepts every funding token, including native coins.
+func AnyToken(FundingToken) bool {
+	return true
+}
+
+// OnlySymbol returns a Select filter that accepts only tokens with the given symbol.
+func OnlySymbol(symbol string) func(FundingToken) bool {
+	return func(t FundingToken) bool {
+		return strings.EqualFold(t.Symbol, symbol)
+	}
+}
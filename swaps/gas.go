@@ -0,0 +1,70 @@
+package swaps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// SentTx is a transaction the bot signed while executing a swap.
+type SentTx struct {
+	Hash string
+	Kind string // "approval", "transfer", "swap" or "funding"
+}
+
+var (
+	approveSelector  = []byte{0x09, 0x5e, 0xa7, 0xb3}
+	transferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+)
+
+// txKind classifies a transaction by its ERC20 method, if any.
+func txKind(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, approveSelector):
+		return "approval"
+	case bytes.HasPrefix(data, transferSelector):
+		return "transfer"
+	}
+	return "swap"
+}
+
+// recordingSigner remembers the transactions it signs, so the gas spent by a
+// swap can be looked up without every provider reporting its transactions.
+type recordingSigner struct {
+	wallet.Signer
+	sent []SentTx
+}
+
+func (s *recordingSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.Signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	s.sent = append(s.sent, SentTx{Hash: signed.Hash().Hex(), Kind: txKind(tx.Data())})
+	return signed, nil
+}
+
+// GasCost returns the gas used by a mined transaction and what it cost in wei
+// (gas used × effective gas price). Returns ethereum.NotFound while the
+// transaction is unmined.
+func (m *Manager) GasCost(ctx context.Context, chain, txHash string) (uint64, *big.Int, error) {
+	rpc, ok := m.rpcClients[chain]
+	if !ok {
+		return 0, nil, fmt.Errorf("no RPC client for %s", chain)
+	}
+	receipt, err := rpc.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return 0, nil, err
+	}
+	price := receipt.EffectiveGasPrice
+	if price == nil {
+		price = new(big.Int)
+	}
+	return receipt.GasUsed, new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price), nil
+}
@@ -0,0 +1,83 @@
+package swaps
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// routingHints maps accepted routing hint strings to their type and normalized value.
+var routingHints = map[string]RoutingHint{
+	"thorchain":  {Type: "provider", Value: "thorchain"},
+	"stream":     {Type: "provider", Value: "thorchain-streaming"},
+	"thorswap":   {Type: "provider", Value: "thorswap"},
+	"simpleswap": {Type: "provider", Value: "simpleswap"},
+	"near":       {Type: "provider", Value: "nearintents"},
+	"houdini":    {Type: "provider", Value: "houdini"},
+	"hanon":      {Type: "provider", Value: "houdini-anon"},
+	"stealthex":  {Type: "provider", Value: "stealthex"},
+	"relay":      {Type: "provider", Value: "relay"},
+	"across":     {Type: "provider", Value: "across"},
+	"squid":      {Type: "provider", Value: "squid"},
+	"rango":      {Type: "provider", Value: "rango"},
+	"0x":         {Type: "provider", Value: "zeroex"},
+	"uniswap":    {Type: "provider", Value: "uniswap"},
+	"garden":     {Type: "provider", Value: "garden"},
+	"dex":        {Type: "category", Value: "dex"},
+	"private":    {Type: "category", Value: "private"},
+}
+
+// StreamHint sets how a streaming swap is split: Interval blocks between
+// sub-swaps and Quantity sub-swaps (0 lets Thorchain pick).
+type StreamHint struct {
+	Interval int64
+	Quantity int64
+}
+
+// ParseRoutingHint looks up a routing hint as typed by users: a provider
+// (thorchain, stream, near, 0x, ...) or a category (dex, private). The
+// stream hint takes optional parameters as "stream:<interval>/<quantity>",
+// or "stream:<interval>" for an automatic quantity.
+func ParseRoutingHint(s string) (RoutingHint, bool) {
+	s = strings.ToLower(s)
+	if params, ok := strings.CutPrefix(s, "stream:"); ok {
+		stream, ok := parseStreamHint(params)
+		if !ok {
+			return RoutingHint{}, false
+		}
+		h := routingHints["stream"]
+		h.Stream = &stream
+		return h, true
+	}
+	h, ok := routingHints[s]
+	return h, ok
+}
+
+func parseStreamHint(s string) (StreamHint, bool) {
+	interval, quantity, hasQuantity := strings.Cut(s, "/")
+	var h StreamHint
+	var err error
+	if h.Interval, err = strconv.ParseInt(interval, 10, 64); err != nil || h.Interval < 1 {
+		return StreamHint{}, false
+	}
+	if hasQuantity {
+		if h.Quantity, err = strconv.ParseInt(quantity, 10, 64); err != nil || h.Quantity < 0 {
+			return StreamHint{}, false
+		}
+	}
+	return h, true
+}
+
+type streamHintKey struct{}
+
+// WithStreamHint returns a context that carries a stream hint's parameters
+// to the streaming provider's Quote.
+func WithStreamHint(ctx context.Context, h StreamHint) context.Context {
+	return context.WithValue(ctx, streamHintKey{}, h)
+}
+
+// StreamHintFrom returns the stream hint parameters ctx carries, if any.
+func StreamHintFrom(ctx context.Context) (StreamHint, bool) {
+	h, ok := ctx.Value(streamHintKey{}).(StreamHint)
+	return h, ok
+}
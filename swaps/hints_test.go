@@ -0,0 +1,56 @@
+package swaps
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRoutingHintStream(t *testing.T) {
+	tests := []struct {
+		in     string
+		ok     bool
+		stream *StreamHint
+	}{
+		{"stream", true, nil},
+		{"STREAM:5/10", true, &StreamHint{Interval: 5, Quantity: 10}},
+		{"stream:3", true, &StreamHint{Interval: 3}},
+		{"stream:1/0", true, &StreamHint{Interval: 1}},
+		{"stream:0/10", false, nil},
+		{"stream:-1/10", false, nil},
+		{"stream:5/-1", false, nil},
+		{"stream:5/", false, nil},
+		{"stream:", false, nil},
+		{"stream:x/y", false, nil},
+		{"thorchain:5/10", false, nil},
+	}
+	for _, tt := range tests {
+		h, ok := ParseRoutingHint(tt.in)
+		if ok != tt.ok {
+			t.Errorf("ParseRoutingHint(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if h.Type != "provider" || h.Value != "thorchain-streaming" {
+			t.Errorf("ParseRoutingHint(%q) = %s %s, want provider thorchain-streaming", tt.in, h.Type, h.Value)
+		}
+		switch {
+		case tt.stream == nil && h.Stream != nil:
+			t.Errorf("ParseRoutingHint(%q).Stream = %+v, want nil", tt.in, *h.Stream)
+		case tt.stream != nil && (h.Stream == nil || *h.Stream != *tt.stream):
+			t.Errorf("ParseRoutingHint(%q).Stream = %v, want %+v", tt.in, h.Stream, *tt.stream)
+		}
+	}
+}
+
+func TestStreamHintContext(t *testing.T) {
+	if _, ok := StreamHintFrom(context.Background()); ok {
+		t.Fatal("StreamHintFrom found a hint in an empty context")
+	}
+	want := StreamHint{Interval: 5, Quantity: 10}
+	got, ok := StreamHintFrom(WithStreamHint(context.Background(), want))
+	if !ok || got != want {
+		t.Fatalf("StreamHintFrom = %+v, %v; want %+v", got, ok, want)
+	}
+}
@@ -2,34 +2,56 @@ package swaps
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/RaghavSood/fundbot/apilog"
 	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
+// maxQuoteRaw bounds the response bodies kept with each quote; providers
+// quoting several chains at once can answer with large route lists.
+const maxQuoteRaw = 256 * 1024
+
 // Manager orchestrates swap providers and selects the best quote.
 type Manager struct {
+	mu            sync.RWMutex
 	providers     []Provider
 	rpcClients    map[string]*ethclient.Client
-	usdcContracts map[string]common.Address
+	fundingTokens FundingTokens
 }
 
 // NewManager creates a Manager with the given providers.
-func NewManager(rpcClients map[string]*ethclient.Client, usdcContracts map[string]common.Address, providers ...Provider) *Manager {
+// fundingTokens lists the stablecoins that can fund swaps on each source chain.
+func NewManager(rpcClients map[string]*ethclient.Client, fundingTokens FundingTokens, providers ...Provider) *Manager {
 	return &Manager{
 		providers:     providers,
 		rpcClients:    rpcClients,
-		usdcContracts: usdcContracts,
+		fundingTokens: fundingTokens,
 	}
 }
 
+// SetProviders replaces the providers, e.g. after their API keys were
+// reloaded. Calls already running finish with the old ones.
+func (m *Manager) SetProviders(providers ...Provider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers = providers
+}
+
+func (m *Manager) list() []Provider {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.providers
+}
+
 // BestQuote queries all providers and returns the quote with the highest expected output.
 // sender is the EVM address that will fund the swap.
 func (m *Manager) BestQuote(ctx context.Context, toAsset Asset, usdAmount float64, destination string, sender common.Address, hint RoutingHint) (*Quote, error) {
@@ -37,18 +59,27 @@ func (m *Manager) BestQuote(ctx context.Context, toAsset Asset, usdAmount float6
 	if err != nil {
 		return nil, err
 	}
+	if hint.Stream != nil {
+		ctx = WithStreamHint(ctx, *hint.Stream)
+	}
 
 	var best *Quote
 
 	for _, p := range providers {
-		quotes, err := p.Quote(ctx, toAsset, usdAmount, destination, sender)
+		pctx, capture := apilog.WithCapture(ctx)
+		quotes, err := p.Quote(pctx, toAsset, usdAmount, destination, sender)
 		if err != nil {
 			log.Printf("provider %s quote error: %v", p.Name(), err)
 			continue
 		}
+		raw, truncated := capture.Payload(maxQuoteRaw)
 
 		for i := range quotes {
 			q := &quotes[i]
+			if !ChainEnabled(q.FromChain) {
+				continue
+			}
+			q.Raw, q.RawTruncated = raw, truncated
 			if best == nil || q.ExpectedOutputRaw.Cmp(best.ExpectedOutputRaw) > 0 {
 				best = q
 			}
@@ -62,14 +93,27 @@ func (m *Manager) BestQuote(ctx context.Context, toAsset Asset, usdAmount float6
 	return best, nil
 }
 
+// defaultCategories are the provider categories used when no routing hint is given.
+// Other categories (e.g. "anon-private", "dex-streaming") are opt-in only.
+var defaultCategories = map[string]bool{
+	"dex":     true,
+	"private": true,
+}
+
 // filterProviders returns the subset of providers matching the routing hint.
 func (m *Manager) filterProviders(hint RoutingHint) ([]Provider, error) {
 	if hint.Type == "" {
-		return m.providers, nil
+		var filtered []Provider
+		for _, p := range m.list() {
+			if defaultCategories[p.Category()] {
+				filtered = append(filtered, p)
+			}
+		}
+		return filtered, nil
 	}
 
 	var filtered []Provider
-	for _, p := range m.providers {
+	for _, p := range m.list() {
 		switch hint.Type {
 		case "provider":
 			if p.Name() == hint.Value {
@@ -90,18 +134,95 @@ func (m *Manager) filterProviders(hint RoutingHint) ([]Provider, error) {
 }
 
 // ExecuteSwap executes the given quote.
-func (m *Manager) ExecuteSwap(ctx context.Context, quote *Quote, privateKey *ecdsa.PrivateKey) (ExecuteResult, error) {
-	for _, p := range m.providers {
-		if p.Name() == quote.Provider {
-			return p.Execute(ctx, *quote, privateKey)
+func (m *Manager) ExecuteSwap(ctx context.Context, quote *Quote, signer wallet.Signer) (ExecuteResult, error) {
+	if !ChainEnabled(quote.FromChain) {
+		return ExecuteResult{}, fmt.Errorf("chain %s is disabled", quote.FromChain)
+	}
+	for _, p := range m.list() {
+		if p.Name() != quote.Provider {
+			continue
 		}
+		if sender, ok := signer.(CallSender); ok {
+			executor, ok := p.(CallExecutor)
+			if !ok {
+				return ExecuteResult{}, fmt.Errorf("provider %q can't execute from a smart account", p.Name())
+			}
+			result, err := executor.ExecuteCalls(ctx, *quote, sender)
+			if err == nil {
+				result.Transactions = []SentTx{{Hash: result.TxHash, Kind: "swap"}}
+			}
+			return result, err
+		}
+		recorder := &recordingSigner{Signer: signer}
+		result, err := p.Execute(ctx, *quote, recorder)
+		result.Transactions = recorder.sent
+		return result, err
 	}
 	return ExecuteResult{}, fmt.Errorf("provider %q not found", quote.Provider)
 }
 
+// RefundTx looks up the refund transaction of a swap via the named provider.
+// Returns "" when the provider can't report refund transactions.
+func (m *Manager) RefundTx(ctx context.Context, provider, txHash, externalID string) (string, error) {
+	for _, p := range m.list() {
+		if p.Name() != provider {
+			continue
+		}
+		finder, ok := p.(RefundTxFinder)
+		if !ok {
+			return "", nil
+		}
+		return finder.RefundTx(ctx, txHash, externalID)
+	}
+	return "", fmt.Errorf("unknown provider: %s", provider)
+}
+
+// Result looks up what a completed swap delivered via the named provider.
+// Returns an empty result when the provider can't report it.
+func (m *Manager) Result(ctx context.Context, provider, txHash, externalID string) (SwapResult, error) {
+	for _, p := range m.list() {
+		if p.Name() != provider {
+			continue
+		}
+		finder, ok := p.(ResultFinder)
+		if !ok {
+			return SwapResult{}, nil
+		}
+		return finder.Result(ctx, txHash, externalID)
+	}
+	return SwapResult{}, fmt.Errorf("unknown provider: %s", provider)
+}
+
+// Stages returns the progress stages of the named provider, or nil when it
+// doesn't report any.
+func (m *Manager) Stages(provider string) []Stage {
+	for _, p := range m.list() {
+		if p.Name() != provider {
+			continue
+		}
+		if reporter, ok := p.(StageReporter); ok {
+			return reporter.Stages()
+		}
+		return nil
+	}
+	return nil
+}
+
+// Endpoints returns the API base URL of each provider that reports one, by
+// provider name.
+func (m *Manager) Endpoints() map[string]string {
+	endpoints := make(map[string]string)
+	for _, p := range m.list() {
+		if reporter, ok := p.(EndpointReporter); ok {
+			endpoints[p.Name()] = reporter.Endpoint()
+		}
+	}
+	return endpoints
+}
+
 // CheckStatus checks the status of a swap via the named provider.
 func (m *Manager) CheckStatus(ctx context.Context, provider, txHash, externalID string) (string, error) {
-	for _, p := range m.providers {
+	for _, p := range m.list() {
 		if p.Name() == provider {
 			return p.CheckStatus(ctx, txHash, externalID)
 		}
@@ -110,8 +231,13 @@ func (m *Manager) CheckStatus(ctx context.Context, provider, txHash, externalID
 }
 
 // IsStaticallyKnown returns true if any provider has a static mapping for the asset.
+// An asset given by contract address alone never is, as its symbol has to be
+// resolved first.
 func (m *Manager) IsStaticallyKnown(asset Asset) bool {
-	for _, p := range m.providers {
+	if asset.ContractOnly() {
+		return false
+	}
+	for _, p := range m.list() {
 		if p.SupportsAsset(asset) {
 			return true
 		}
@@ -122,39 +248,63 @@ func (m *Manager) IsStaticallyKnown(asset Asset) bool {
 // noQuotesError builds a descriptive error when no quotes are available,
 // checking whether insufficient balance is the cause.
 func (m *Manager) noQuotesError(ctx context.Context, toAsset Asset, usdAmount float64, sender common.Address) error {
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var lines []string
 	allInsufficient := true
 	checkedAny := false
 
-	for chain, rpc := range m.rpcClients {
-		usdcAddr, ok := m.usdcContracts[chain]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
-		if err != nil {
-			log.Printf("noQuotesError: error checking %s balance: %v", chain, err)
-			continue
-		}
-		checkedAny = true
+	for chain, rpc := range EnabledClients(m.rpcClients) {
+		for _, token := range m.fundingTokens.ERC20s(chain) {
+			bal, err := balances.TokenBalance(ctx, rpc, token.Address, sender)
+			if err != nil {
+				log.Printf("noQuotesError: error checking %s %s balance: %v", chain, token.Symbol, err)
+				continue
+			}
+			checkedAny = true
 
-		// Format as human-readable USDC (6 decimals)
-		whole := new(big.Int).Div(bal, big.NewInt(1e6))
-		frac := new(big.Int).Mod(bal, big.NewInt(1e6))
-		balStr := fmt.Sprintf("%d.%06d", whole.Int64(), frac.Int64())
-		lines = append(lines, fmt.Sprintf("  %s: %s USDC", strings.Title(chain), balStr))
+			lines = append(lines, fmt.Sprintf("  %s: %s %s", strings.Title(chain), token.Format(bal), token.Symbol))
 
-		if bal.Cmp(requiredUSDC) >= 0 {
-			allInsufficient = false
+			if bal.Cmp(token.Amount(usdAmount)) >= 0 {
+				allInsufficient = false
+			}
 		}
 	}
 
 	if checkedAny && allInsufficient {
-		return fmt.Errorf("insufficient USDC balance for $%.2f swap to %s\nCurrent balances:\n%s",
+		return fmt.Errorf("insufficient stablecoin balance for $%.2f swap to %s\nCurrent balances:\n%s",
 			usdAmount, toAsset, strings.Join(lines, "\n"))
 	}
 
 	return fmt.Errorf("no quotes available for %s", toAsset)
 }
+
+// highGasChains are source chains where the approve + deposit transactions cost
+// enough to matter for a typical topup, so quotes sourced there carry a warning.
+var highGasChains = map[string]bool{
+	"ethereum": true,
+}
+
+// sourceTxGas approximates the gas used by an ERC20 approve plus a deposit/swap call.
+const sourceTxGas = 150_000
+
+// GasWarning returns a human-readable estimate of the source-chain gas cost when
+// the quote is funded from a high-gas chain, or "" otherwise.
+func (m *Manager) GasWarning(ctx context.Context, quote *Quote) string {
+	if !highGasChains[quote.FromChain] {
+		return ""
+	}
+	rpc, ok := m.rpcClients[quote.FromChain]
+	if !ok {
+		return ""
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		log.Printf("GasWarning: error getting %s gas price: %v", quote.FromChain, err)
+		return fmt.Sprintf("Funding from %s: approve + deposit gas can be expensive.", strings.Title(quote.FromChain))
+	}
+
+	costWei := new(big.Int).Mul(gasPrice, big.NewInt(sourceTxGas))
+	costEth, _ := new(big.Float).Quo(new(big.Float).SetInt(costWei), big.NewFloat(1e18)).Float64()
+	return fmt.Sprintf("Funding from %s: approve + deposit will cost ~%.5f ETH in gas (%s gwei).",
+		strings.Title(quote.FromChain), costEth, new(big.Int).Div(gasPrice, big.NewInt(1e9)))
+}
@@ -0,0 +1,116 @@
+package swaps
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// fakeProvider quotes a fixed output and records the stream hint it saw.
+type fakeProvider struct {
+	name, category string
+	output         int64
+
+	stream   StreamHint
+	streamed bool
+}
+
+func (p *fakeProvider) Name() string             { return p.name }
+func (p *fakeProvider) Category() string         { return p.category }
+func (p *fakeProvider) SupportsAsset(Asset) bool { return false }
+func (p *fakeProvider) Execute(context.Context, Quote, wallet.Signer) (ExecuteResult, error) {
+	return ExecuteResult{}, nil
+}
+func (p *fakeProvider) CheckStatus(context.Context, string, string) (string, error) {
+	return "pending", nil
+}
+
+func (p *fakeProvider) Quote(ctx context.Context, toAsset Asset, usdAmount float64, destination string, sender common.Address) ([]Quote, error) {
+	p.stream, p.streamed = StreamHintFrom(ctx)
+	return []Quote{{Provider: p.name, ToAsset: toAsset, FromChain: "base", ExpectedOutputRaw: big.NewInt(p.output)}}, nil
+}
+
+func testProviders() []Provider {
+	return []Provider{
+		&fakeProvider{name: "thorchain", category: "dex", output: 100},
+		&fakeProvider{name: "simpleswap", category: "private", output: 90},
+		&fakeProvider{name: "houdini-anon", category: "anon-private", output: 200},
+		&fakeProvider{name: "thorchain-streaming", category: "dex-streaming", output: 300},
+	}
+}
+
+func providerNames(providers []Provider) []string {
+	var names []string
+	for _, p := range providers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+func TestFilterProvidersDefaultCategories(t *testing.T) {
+	m := NewManager(nil, nil, testProviders()...)
+
+	tests := []struct {
+		hint RoutingHint
+		want []string
+	}{
+		// Opt-in categories are left out without a hint
+		{RoutingHint{}, []string{"thorchain", "simpleswap"}},
+		{RoutingHint{Type: "category", Value: "private"}, []string{"simpleswap"}},
+		{RoutingHint{Type: "category", Value: "anon-private"}, []string{"houdini-anon"}},
+		{RoutingHint{Type: "provider", Value: "thorchain-streaming"}, []string{"thorchain-streaming"}},
+	}
+	for _, tt := range tests {
+		got, err := m.filterProviders(tt.hint)
+		if err != nil {
+			t.Errorf("filterProviders(%+v): %v", tt.hint, err)
+			continue
+		}
+		if names := providerNames(got); !slices.Equal(names, tt.want) {
+			t.Errorf("filterProviders(%+v) = %v, want %v", tt.hint, names, tt.want)
+		}
+	}
+
+	if _, err := m.filterProviders(RoutingHint{Type: "provider", Value: "garden"}); err == nil {
+		t.Error("filterProviders matched a provider that isn't configured")
+	}
+}
+
+func TestBestQuoteSkipsOptInCategories(t *testing.T) {
+	m := NewManager(nil, nil, testProviders()...)
+	q, err := m.BestQuote(context.Background(), Asset{Chain: "BTC", Symbol: "BTC"}, 100, "bc1q", common.Address{}, RoutingHint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Provider != "thorchain" {
+		t.Errorf("best quote from %s, want thorchain", q.Provider)
+	}
+}
+
+func TestBestQuotePassesStreamHint(t *testing.T) {
+	providers := testProviders()
+	streaming := providers[3].(*fakeProvider)
+	m := NewManager(nil, nil, providers...)
+
+	hint := RoutingHint{Type: "provider", Value: "thorchain-streaming", Stream: &StreamHint{Interval: 5, Quantity: 10}}
+	if _, err := m.BestQuote(context.Background(), Asset{Chain: "BTC", Symbol: "BTC"}, 100, "bc1q", common.Address{}, hint); err != nil {
+		t.Fatal(err)
+	}
+	if want := (StreamHint{Interval: 5, Quantity: 10}); !streaming.streamed || streaming.stream != want {
+		t.Errorf("provider saw stream hint %+v (%v), want %+v", streaming.stream, streaming.streamed, want)
+	}
+
+	// A plain stream hint leaves the provider's own parameters
+	hint.Stream = nil
+	if _, err := m.BestQuote(context.Background(), Asset{Chain: "BTC", Symbol: "BTC"}, 100, "bc1q", common.Address{}, hint); err != nil {
+		t.Fatal(err)
+	}
+	if streaming.streamed {
+		t.Errorf("provider saw stream hint %+v without parameters", streaming.stream)
+	}
+}
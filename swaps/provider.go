@@ -2,10 +2,11 @@ package swaps
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 // Quote represents a swap quote from a provider.
@@ -23,18 +24,32 @@ type Quote struct {
 	VaultAddress     string // inbound/vault address
 	Expiry           int64  // unix timestamp
 	ExtraData        map[string]interface{}
+
+	// Raw is the provider's API responses behind the quote, as JSON (see
+	// apilog.Capture), set by Manager.BestQuote. Quotes made without an
+	// API call (e.g. on-chain quoters) have none.
+	Raw          []byte
+	RawTruncated bool
 }
 
 // ExecuteResult holds the result of executing a swap.
 type ExecuteResult struct {
 	TxHash     string
 	ExternalID string // provider-specific ID (e.g. SimpleSwap exchange ID)
+
+	// Transactions lists every transaction signed during the swap, including
+	// approvals and transfers. Filled in by Manager.ExecuteSwap.
+	Transactions []SentTx
 }
 
 // RoutingHint controls provider selection for a quote request.
 type RoutingHint struct {
 	Type  string // "" (no hint), "provider", or "category"
 	Value string // provider name or category ("dex", "private")
+
+	// Sub-swap parameters of a "stream:<interval>/<quantity>" hint; nil
+	// keeps the streaming provider's configured ones
+	Stream *StreamHint
 }
 
 // Provider is the interface that swap providers must implement.
@@ -50,14 +65,47 @@ type Provider interface {
 	// sender is the EVM address that will fund the swap (used to check USDC balances).
 	Quote(ctx context.Context, toAsset Asset, usdAmount float64, destination string, sender common.Address) ([]Quote, error)
 
-	// Execute submits the swap transaction for the given quote using the provided private key.
-	Execute(ctx context.Context, quote Quote, privateKey *ecdsa.PrivateKey) (ExecuteResult, error)
+	// Execute submits the swap transaction for the given quote, signing with signer.
+	Execute(ctx context.Context, quote Quote, signer wallet.Signer) (ExecuteResult, error)
 
 	// CheckStatus checks the status of a swap by its source chain tx hash.
 	// externalID is a provider-specific identifier (ignored by some providers).
-	// Returns "pending", "completed", or "failed".
+	// Returns "pending", "completed", "failed", or "refunded" when the
+	// provider sent the input back.
 	CheckStatus(ctx context.Context, txHash string, externalID string) (string, error)
 
 	// SupportsAsset returns true if the asset is in the provider's static mapping.
 	SupportsAsset(asset Asset) bool
 }
+
+// RefundTxFinder is implemented by providers that can name the transaction
+// that refunded a swap.
+type RefundTxFinder interface {
+	// RefundTx returns the refund transaction hash for a swap CheckStatus
+	// reported as "refunded", or "" if it isn't known yet.
+	RefundTx(ctx context.Context, txHash string, externalID string) (string, error)
+}
+
+// SwapResult is what a completed swap delivered.
+type SwapResult struct {
+	OutputAmount string // in the same units as Quote.ExpectedOutput; "" if unknown
+	DestTxHash   string // payout transaction on the destination chain; "" if unknown
+}
+
+// ResultFinder is implemented by providers that can report the realized
+// output of a completed swap.
+type ResultFinder interface {
+	Result(ctx context.Context, txHash string, externalID string) (SwapResult, error)
+}
+
+// EndpointReporter is implemented by providers backed by an HTTP API, so
+// health checks can tell whether it is reachable.
+type EndpointReporter interface {
+	Endpoint() string
+}
+
+// KeyChecker is implemented by providers that can verify their API key with
+// a cheap authenticated request.
+type KeyChecker interface {
+	CheckKey(ctx context.Context) error
+}
@@ -0,0 +1,34 @@
+package swaps
+
+import "context"
+
+type rawStatusKey struct{}
+
+// WithRawStatus returns a context that captures the provider's own status
+// string behind a CheckStatus result (e.g. SimpleSwap "exchanging"), and the
+// pointer it is written to.
+func WithRawStatus(ctx context.Context) (context.Context, *string) {
+	raw := new(string)
+	return context.WithValue(ctx, rawStatusKey{}, raw), raw
+}
+
+// ReportRawStatus records the provider-specific status seen by CheckStatus.
+// It does nothing unless ctx came from WithRawStatus.
+func ReportRawStatus(ctx context.Context, raw string) {
+	if p, ok := ctx.Value(rawStatusKey{}).(*string); ok {
+		*p = raw
+	}
+}
+
+// Stage is one step of a swap's progress, keyed by the raw status the
+// provider reports once the step is done.
+type Stage struct {
+	Key   string
+	Label string
+}
+
+// StageReporter is implemented by providers whose raw statuses move through
+// a fixed sequence of stages, which the tracker shows as progress updates.
+type StageReporter interface {
+	Stages() []Stage
+}
@@ -0,0 +1,99 @@
+package swaps
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+const erc20TransferABI = `[{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
+
+// TransferERC20 sends amount of token from the signer to to on chain and
+// returns the transaction hash. Plain signers need native gas and return
+// without waiting for the transaction to be mined; a CallSender sends the
+// transfer as a call and returns once it is mined.
+func TransferERC20(ctx context.Context, rpc *ethclient.Client, chain string, signer wallet.Signer, token, to common.Address, amount *big.Int) (string, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20TransferABI))
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack("transfer", to, amount)
+	if err != nil {
+		return "", err
+	}
+
+	if sender, ok := signer.(CallSender); ok {
+		return sender.SendCalls(ctx, chain, []Call{{To: token, Value: big.NewInt(0), Data: data}})
+	}
+
+	chainID, err := rpc.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting chain ID: %w", err)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, signer.Address())
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing transfer tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending transfer tx: %w", err)
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// TransferNative sends amount of the chain's native token from the signer
+// to to and returns the transaction hash, with the same waiting as
+// TransferERC20.
+func TransferNative(ctx context.Context, rpc *ethclient.Client, chain string, signer wallet.Signer, to common.Address, amount *big.Int) (string, error) {
+	if sender, ok := signer.(CallSender); ok {
+		return sender.SendCalls(ctx, chain, []Call{{To: to, Value: amount}})
+	}
+
+	chainID, err := rpc.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting chain ID: %w", err)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, signer.Address())
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, to, amount, 21000, gasPrice, nil)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing transfer tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending transfer tx: %w", err)
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
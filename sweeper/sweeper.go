@@ -0,0 +1,308 @@
+package sweeper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/RaghavSood/fundbot/balances"
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/cowswap"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// WalletLocker serializes spends from a wallet. A sweep holds the wallet's
+// lock so it can't race a topup for nonces and balance.
+type WalletLocker interface {
+	LockWallet(index uint32) (unlock func())
+}
+
+// Sweeper periodically moves USDC above the configured ceiling out of the
+// derived wallets and into the treasury.
+type Sweeper struct {
+	cfg        *config.Config
+	store      *db.Store
+	keyring    wallet.Keyring
+	rpcClients map[string]*ethclient.Client
+	cowClient  *cowswap.Client
+	wallets    WalletLocker
+	botAPI     *tgbotapi.BotAPI
+}
+
+func New(cfg *config.Config, store *db.Store, keyring wallet.Keyring, rpcClients map[string]*ethclient.Client, cowClient *cowswap.Client, wallets WalletLocker, botAPI *tgbotapi.BotAPI) *Sweeper {
+	return &Sweeper{
+		cfg:        cfg,
+		store:      store,
+		keyring:    keyring,
+		rpcClients: rpcClients,
+		cowClient:  cowClient,
+		wallets:    wallets,
+		botAPI:     botAPI,
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.cfg.Sweep.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Sweeper stopped")
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// walletIndexes returns the wallet indexes in use: the shared wallet in
+// single mode, or one per address assignment in multi mode.
+func (s *Sweeper) walletIndexes(ctx context.Context) ([]uint32, error) {
+	if s.cfg.Mode == config.ModeSingle {
+		return []uint32{0}, nil
+	}
+	assignments, err := s.store.ListAddressAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]uint32, len(assignments))
+	for i, a := range assignments {
+		indexes[i] = uint32(a.ID)
+	}
+	return indexes, nil
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	indexes, err := s.walletIndexes(ctx)
+	if err != nil {
+		log.Printf("Sweeper: error listing wallets: %v", err)
+		return
+	}
+
+	var addresses []common.Address
+	indexByAddr := make(map[string]uint32)
+	for _, index := range indexes {
+		addr, err := s.keyring.Address(index)
+		if err != nil {
+			log.Printf("Sweeper: error deriving wallet %d: %v", index, err)
+			continue
+		}
+		addresses = append(addresses, addr)
+		indexByAddr[addr.Hex()] = index
+	}
+
+	// Only read USDC; it's the only token swept
+	usdcTokens := make(map[string]swaps.FundingToken)
+	contracts := make(map[string][]common.Address)
+	for chain := range swaps.EnabledClients(s.rpcClients) {
+		usdc, ok := thorchain.FundingTokens.Lookup(chain, "USDC")
+		if !ok || usdc.Native {
+			continue
+		}
+		if s.cfg.Sweep.Method == "cow" {
+			if _, ok := cowswap.SupportedChains[chain]; !ok {
+				continue
+			}
+			if _, err := s.cowGuards(chain); err != nil {
+				log.Printf("Sweeper: not sweeping %s via cow: %v", chain, err)
+				continue
+			}
+		}
+		usdcTokens[chain] = usdc
+		contracts[chain] = []common.Address{usdc.Address}
+	}
+
+	bals, err := balances.FetchBalances(ctx, swaps.EnabledClients(s.rpcClients), addresses, contracts, nil)
+	if err != nil {
+		log.Printf("Sweeper: error fetching balances: %v", err)
+		return
+	}
+
+	for _, bal := range bals {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if len(bal.TokenBalances) == 0 {
+			continue
+		}
+		usdc := usdcTokens[bal.Chain]
+		balance, ok := new(big.Int).SetString(bal.TokenBalances[0], 10)
+		if !ok {
+			continue
+		}
+
+		if s.excess(usdc, balance) == nil {
+			continue
+		}
+
+		if s.coolingDown(ctx, bal.Address, bal.Chain) {
+			continue
+		}
+
+		s.sweepWallet(ctx, indexByAddr[bal.Address], bal.Chain, usdc)
+	}
+}
+
+// excess returns the USDC above the ceiling in a balance, or nil when that
+// is below the minimum sweep.
+func (s *Sweeper) excess(usdc swaps.FundingToken, balance *big.Int) *big.Int {
+	excess := new(big.Int).Sub(balance, usdc.Amount(s.cfg.Sweep.Ceiling))
+	if excess.Cmp(usdc.Amount(s.cfg.Sweep.MinAmount)) < 0 {
+		return nil
+	}
+	return excess
+}
+
+// buyToken returns the token CoW sweeps buy on chain.
+func (s *Sweeper) buyToken(chain string) string {
+	if token, ok := s.cfg.Sweep.BuyTokens[chain]; ok {
+		return token
+	}
+	return cowswap.NativeToken
+}
+
+// cowGuards returns the chain's gas refill guards for CoW sweeps. Sweeps
+// sell far more than refills, so a chain whose guards don't cap the fee,
+// or the price of a native buy, is not swept.
+func (s *Sweeper) cowGuards(chain string) (cowswap.Guards, error) {
+	refill, ok := s.cfg.GasRefill(chain)
+	if !ok {
+		return cowswap.Guards{}, fmt.Errorf("gas_refills has no %s entry", chain)
+	}
+	guards := cowswap.Guards{SlippageBps: refill.SlippageBps, MaxFeeBps: refill.MaxFeeBps, MaxDeviationBps: refill.MaxDeviationBps}
+	if guards.MaxFeeBps == 0 {
+		return cowswap.Guards{}, fmt.Errorf("gas_refills %s sets no max_fee_bps", chain)
+	}
+	if strings.EqualFold(s.buyToken(chain), cowswap.NativeToken) && guards.MaxDeviationBps == 0 {
+		return cowswap.Guards{}, fmt.Errorf("gas_refills %s sets no max_deviation_bps", chain)
+	}
+	return guards, nil
+}
+
+// coolingDown reports whether the wallet was swept on chain within the
+// cooldown window. Lookup errors count as cooling down so a broken database
+// can't cause repeated sweeps.
+func (s *Sweeper) coolingDown(ctx context.Context, address, chain string) bool {
+	last, err := s.store.GetLastSweep(ctx, db.GetLastSweepParams{
+		WalletAddress: address,
+		Chain:         chain,
+	})
+	if err == sql.ErrNoRows {
+		return false
+	}
+	if err != nil {
+		log.Printf("Sweeper: error checking last sweep of %s on %s: %v", address, chain, err)
+		return true
+	}
+	cooldown := time.Duration(s.cfg.Sweep.CooldownHours) * time.Hour
+	return time.Since(last.CreatedAt) < cooldown
+}
+
+func (s *Sweeper) sweepWallet(ctx context.Context, index uint32, chain string, usdc swaps.FundingToken) {
+	signer, err := s.keyring.Signer(index)
+	if err != nil {
+		log.Printf("Sweeper: error loading signer %d: %v", index, err)
+		return
+	}
+	treasury := common.HexToAddress(s.cfg.Sweep.Treasury)
+
+	// Hold the wallet's topup lock so a swap in flight can't race the sweep
+	// for nonces, and read the balance again once no topup can spend it
+	unlock := s.wallets.LockWallet(index)
+	defer unlock()
+	balance, err := balances.TokenBalance(ctx, s.rpcClients[chain], usdc.Address, signer.Address())
+	if err != nil {
+		log.Printf("Sweeper: error reading USDC of %s on %s: %v", signer.Address().Hex(), chain, err)
+		return
+	}
+	amount := s.excess(usdc, balance)
+	if amount == nil {
+		return
+	}
+
+	ctx = wallet.WithConfirmNotifier(ctx, func(what string) {
+		s.notifyAdmin(fmt.Sprintf("Confirm the %s on the hardware wallet to sweep %s USDC on %s.", what, usdc.Format(amount), chain))
+	})
+
+	log.Printf("Sweeper: sweeping %s USDC from %s on %s via %s", usdc.Format(amount), signer.Address().Hex(), chain, s.cfg.Sweep.Method)
+
+	var reference, status string
+	switch s.cfg.Sweep.Method {
+	case "cow":
+		var guards cowswap.Guards
+		if guards, err = s.cowGuards(chain); err != nil {
+			break
+		}
+		var result *cowswap.OrderResult
+		result, err = s.cowClient.SellUSDC(ctx, chain, signer, amount, s.buyToken(chain), treasury, guards)
+		if err == nil {
+			reference, status = result.OrderUID, result.Status
+		}
+	default:
+		reference, err = swaps.TransferERC20(ctx, s.rpcClients[chain], chain, signer, usdc.Address, treasury, amount)
+		status = "sent"
+	}
+	if err != nil {
+		// Failures are recorded too, so a wallet that can't be swept waits out
+		// the cooldown instead of alerting on every run
+		status = "failed"
+	}
+
+	if _, dbErr := s.store.InsertSweep(ctx, db.InsertSweepParams{
+		Chain:           chain,
+		WalletIndex:     int64(index),
+		WalletAddress:   signer.Address().Hex(),
+		TreasuryAddress: treasury.Hex(),
+		Amount:          amount.String(),
+		Method:          s.cfg.Sweep.Method,
+		Reference:       reference,
+		Status:          status,
+	}); dbErr != nil {
+		log.Printf("Sweeper: error recording sweep of %s on %s: %v", signer.Address().Hex(), chain, dbErr)
+	}
+
+	if err != nil {
+		log.Printf("Sweeper: sweep of %s on %s failed: %v", signer.Address().Hex(), chain, err)
+		s.notifyAdmin(fmt.Sprintf("Sweep of %s USDC from `%s` on %s failed: %v", usdc.Format(amount), signer.Address().Hex(), chain, err))
+		return
+	}
+
+	var link string
+	if s.cfg.Sweep.Method == "cow" {
+		link = fmt.Sprintf("[View Order](https://explorer.cow.fi/orders/%s)", reference)
+	} else {
+		link = fmt.Sprintf("[View on Explorer](%s)", s.cfg.ExplorerTxURL(chain, reference))
+	}
+	s.notifyAdmin(fmt.Sprintf("*Swept %s USDC on %s*\nFrom: `%s`\nTo: `%s`\n%s",
+		usdc.Format(amount), chain, signer.Address().Hex(), treasury.Hex(), link))
+}
+
+func (s *Sweeper) notifyAdmin(text string) {
+	msg := tgbotapi.NewMessage(s.cfg.AdminUserID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	if _, err := s.botAPI.Send(msg); err != nil {
+		// Errors in the text can break Markdown; retry as plain text
+		msg.ParseMode = ""
+		if _, err := s.botAPI.Send(msg); err != nil {
+			log.Printf("Sweeper: error notifying admin: %v", err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +27,8 @@ type QuoteResponse struct {
 	OutboundDelayBlocks int64        `json:"outbound_delay_blocks"`
 	OutboundDelaySecs   int64        `json:"outbound_delay_seconds"`
 	StreamingSwapBlocks int64        `json:"streaming_swap_blocks"`
+	StreamingSwapSecs   int64        `json:"streaming_swap_seconds"`
+	TotalSwapSecs       int64        `json:"total_swap_seconds"`
 	MaxStreamingQty     int64        `json:"max_streaming_quantity"`
 	Warning             string       `json:"warning"`
 	Notes               string       `json:"notes"`
@@ -60,6 +63,7 @@ type SwapStage struct {
 }
 
 type TxStatusResponse struct {
+	OutTxs []OutTx `json:"out_txs"`
 	Stages struct {
 		InboundObserved            TxStage    `json:"inbound_observed"`
 		InboundConfirmationCounted TxStage    `json:"inbound_confirmation_counted"`
@@ -70,9 +74,52 @@ type TxStatusResponse struct {
 	} `json:"stages"`
 }
 
+// Stage names the furthest completed stage of the swap, e.g.
+// "inbound_finalised" or "outbound_signed".
+func (s *TxStatusResponse) Stage() string {
+	st := s.Stages
+	switch {
+	case st.OutboundSigned != nil && st.OutboundSigned.Completed:
+		return "outbound_signed"
+	case st.SwapFinalised != nil && st.SwapFinalised.Completed:
+		return "swap_finalised"
+	case st.InboundFinalised.Completed:
+		return "inbound_finalised"
+	case st.InboundConfirmationCounted.Completed:
+		return "inbound_confirmation_counted"
+	case st.InboundObserved.Completed:
+		return "inbound_observed"
+	}
+	return "not_observed"
+}
+
+// OutTx is an outbound transaction Thorchain sent for a swap. Refunds carry
+// a "REFUND:<inbound hash>" memo.
+type OutTx struct {
+	ID    string `json:"id"`
+	Chain string `json:"chain"`
+	Memo  string `json:"memo"`
+	Coins []struct {
+		Asset  string `json:"asset"`
+		Amount string `json:"amount"`
+	} `json:"coins"`
+}
+
+// RefundTx returns the outbound refund transaction, if Thorchain refunded
+// the inbound.
+func (s *TxStatusResponse) RefundTx() (OutTx, bool) {
+	for _, out := range s.OutTxs {
+		if strings.HasPrefix(strings.ToUpper(out.Memo), "REFUND") {
+			return out, true
+		}
+	}
+	return OutTx{}, false
+}
+
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	affiliate  Affiliate
 	mu         sync.Mutex
 	lastReq    time.Time
 }
@@ -95,7 +142,26 @@ func (c *Client) rateLimit() {
 	c.lastReq = time.Now()
 }
 
-func (c *Client) GetQuote(ctx context.Context, fromAsset, toAsset, destination string, amount int64) (*QuoteResponse, error) {
+// StreamingParams controls how Thorchain splits a swap into sub-swaps.
+// Interval is the number of blocks between sub-swaps; Quantity is the number
+// of sub-swaps (0 lets Thorchain pick the optimal count).
+type StreamingParams struct {
+	Interval int64
+	Quantity int64
+}
+
+// Affiliate is credited with Bps basis points of each swap quoted. Name is a
+// THORName or THORChain address; an empty Name adds no affiliate.
+type Affiliate struct {
+	Name string
+	Bps  int
+}
+
+// DefaultStreaming is used for normal Thorchain routing: one block between
+// sub-swaps and auto quantity, which is fast and still avoids most slippage.
+var DefaultStreaming = StreamingParams{Interval: 1, Quantity: 0}
+
+func (c *Client) GetQuote(ctx context.Context, fromAsset, toAsset, destination string, amount int64, streaming StreamingParams) (*QuoteResponse, error) {
 	c.rateLimit()
 
 	params := url.Values{}
@@ -103,8 +169,13 @@ func (c *Client) GetQuote(ctx context.Context, fromAsset, toAsset, destination s
 	params.Set("to_asset", toAsset)
 	params.Set("amount", fmt.Sprintf("%d", amount))
 	params.Set("destination", destination)
-	params.Set("streaming_interval", "1")
-	params.Set("streaming_quantity", "0")
+	params.Set("streaming_interval", fmt.Sprintf("%d", streaming.Interval))
+	params.Set("streaming_quantity", fmt.Sprintf("%d", streaming.Quantity))
+	// The returned memo carries the affiliate, so deposits pay it too
+	if c.affiliate.Name != "" {
+		params.Set("affiliate", c.affiliate.Name)
+		params.Set("affiliate_bps", fmt.Sprintf("%d", c.affiliate.Bps))
+	}
 
 	reqURL := fmt.Sprintf("%s/thorchain/quote/swap?%s", c.baseURL, params.Encode())
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
@@ -201,3 +272,75 @@ func (c *Client) GetTxStatus(ctx context.Context, txHash string) (*TxStatusRespo
 
 	return &status, nil
 }
+
+// Pool is the subset of a Thorchain pool used for pricing.
+type Pool struct {
+	Asset         string `json:"asset"`
+	Status        string `json:"status"`
+	AssetTorPrice string `json:"asset_tor_price"` // USD price, 1e8 units
+}
+
+func (c *Client) GetPool(ctx context.Context, asset string) (*Pool, error) {
+	c.rateLimit()
+
+	reqURL := fmt.Sprintf("%s/thorchain/pool/%s", c.baseURL, asset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting pool: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pool API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var pool Pool
+	if err := json.Unmarshal(body, &pool); err != nil {
+		return nil, fmt.Errorf("parsing pool: %w", err)
+	}
+
+	return &pool, nil
+}
+
+// NativePriceUSD returns the USD price of a chain's native coin from its
+// Thorchain pool. Implements swaps.NativePricer. ETH on chains without a pool
+// (Optimism) is priced from Ethereum's, and XDAI is taken at $1; other chains
+// without a pool (Polygon) can't be priced.
+func (c *Client) NativePriceUSD(ctx context.Context, chain string) (float64, error) {
+	native, ok := NativeToken(chain)
+	if !ok {
+		return 0, fmt.Errorf("no native coin known for %s", chain)
+	}
+	asset, ok := SourceAsset(chain, native)
+	if !ok {
+		switch native.Symbol {
+		case "ETH":
+			asset = "ETH.ETH"
+		case "XDAI":
+			return 1, nil
+		default:
+			return 0, fmt.Errorf("no Thorchain pool for %s", chain)
+		}
+	}
+
+	pool, err := c.GetPool(ctx, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := strconv.ParseFloat(pool.AssetTorPrice, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s price %q: %w", asset, pool.AssetTorPrice, err)
+	}
+	return price / 1e8, nil
+}
@@ -1,41 +1,131 @@
 package thorchain
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
 
 const (
 	ThornodeBaseURL = "https://thornode.ninerealms.com"
-
-	// Thorchain asset notation for source USDC on each chain
-	AVAXUSDCAsset = "AVAX.USDC-0XB97EF9EF8734C71904D8002F8B6BC66DD9C48A6E"
-	BASEUSDCAsset = "BASE.USDC-0X833589FCD6EDB6E08F4C7C32D4F71B54BDA02913"
 )
 
-// USDC contract addresses per chain (checksummed)
-var USDCContracts = map[string]common.Address{
-	"avalanche": common.HexToAddress("0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E"),
-	"base":      common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+// KnownFundingTokens is the catalog of tokens that can be enabled as funding
+// tokens per RPC chain key, keyed by symbol: stablecoins plus the native coin.
+var KnownFundingTokens = map[string]map[string]swaps.FundingToken{
+	"arbitrum": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831"), Decimals: 6},
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9"), Decimals: 6},
+		"DAI":  {Symbol: "DAI", Address: common.HexToAddress("0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1"), Decimals: 18},
+		"ETH":  {Symbol: "ETH", Decimals: 18, Native: true},
+	},
+	"avalanche": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0xB97EF9Ef8734C71904D8002F8B6BC66Dd9c48a6E"), Decimals: 6},
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0x9702230A8Ea53601f5cD2dc00fDBc13d4dF4A8c7"), Decimals: 6},
+		"AVAX": {Symbol: "AVAX", Decimals: 18, Native: true},
+	},
+	"base": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"), Decimals: 6},
+		"DAI":  {Symbol: "DAI", Address: common.HexToAddress("0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb"), Decimals: 18},
+		"ETH":  {Symbol: "ETH", Decimals: 18, Native: true},
+	},
+	"bsc": {
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0x55d398326f99059fF775485246999027B3197955"), Decimals: 18},
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d"), Decimals: 18},
+		"BNB":  {Symbol: "BNB", Decimals: 18, Native: true},
+	},
+	"ethereum": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Decimals: 6},
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), Decimals: 6},
+		"DAI":  {Symbol: "DAI", Address: common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), Decimals: 18},
+		"ETH":  {Symbol: "ETH", Decimals: 18, Native: true},
+	},
+	"optimism": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85"), Decimals: 6},
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0x94b008aA00579c1307B0EF2c499aD98a8ce58e58"), Decimals: 6},
+		"DAI":  {Symbol: "DAI", Address: common.HexToAddress("0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1"), Decimals: 18},
+		"ETH":  {Symbol: "ETH", Decimals: 18, Native: true},
+	},
+	"gnosis": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0x2a22f9c3b484c3629090FeED35F17Ff8F88f76F0"), Decimals: 6},
+		"XDAI": {Symbol: "XDAI", Decimals: 18, Native: true},
+	},
+	"polygon": {
+		"USDC": {Symbol: "USDC", Address: common.HexToAddress("0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"), Decimals: 6},
+		"USDT": {Symbol: "USDT", Address: common.HexToAddress("0xc2132D05D31c914a87C6611C10748AEb04B58e8F"), Decimals: 6},
+		"DAI":  {Symbol: "DAI", Address: common.HexToAddress("0x8f3Cf7ad23Cd3CaDbD9735AFf958023239c6A063"), Decimals: 18},
+		"POL":  {Symbol: "POL", Decimals: 18, Native: true},
+	},
+}
+
+// FundingTokens is the active list of accepted funding stablecoins per RPC chain
+// key, in preference order. Defaults to USDC everywhere except BSC (USDT); the
+// funding_tokens config overrides it per chain via SetFundingTokens.
+var FundingTokens = swaps.FundingTokens{
+	"arbitrum":  {KnownFundingTokens["arbitrum"]["USDC"]},
+	"avalanche": {KnownFundingTokens["avalanche"]["USDC"]},
+	"base":      {KnownFundingTokens["base"]["USDC"]},
+	"bsc":       {KnownFundingTokens["bsc"]["USDT"]},
+	"ethereum":  {KnownFundingTokens["ethereum"]["USDC"]},
+	"optimism":  {KnownFundingTokens["optimism"]["USDC"]},
+	"polygon":   {KnownFundingTokens["polygon"]["USDC"]},
 }
 
-// SourceAssets maps RPC chain key to Thorchain USDC asset notation
-var SourceAssets = map[string]string{
-	"avalanche": AVAXUSDCAsset,
-	"base":      BASEUSDCAsset,
+// SetFundingTokens replaces the funding token list for each chain present in overrides.
+func SetFundingTokens(overrides swaps.FundingTokens) {
+	for chain, tokens := range overrides {
+		FundingTokens[chain] = tokens
+	}
 }
 
-// ThorchainChainID maps RPC chain key to Thorchain chain identifier
+// ThorchainChainID maps RPC chain key to Thorchain chain identifier.
+// Only these chains can fund Thorchain swaps.
 var ThorchainChainID = map[string]string{
+	"arbitrum":  "ARB",
 	"avalanche": "AVAX",
 	"base":      "BASE",
+	"bsc":       "BSC",
+	"ethereum":  "ETH",
 }
 
 // ChainFromThorchain maps Thorchain chain ID back to RPC key
 var ChainFromThorchain = map[string]string{
+	"ARB":  "arbitrum",
 	"AVAX": "avalanche",
 	"BASE": "base",
+	"BSC":  "bsc",
+	"ETH":  "ethereum",
+}
+
+// SourceAsset returns the Thorchain notation for a funding token on a chain
+// (e.g. "BASE.USDC-0X8335..." or "BASE.ETH"), or false if Thorchain doesn't
+// support the chain.
+func SourceAsset(chain string, token swaps.FundingToken) (string, bool) {
+	tcChain, ok := ThorchainChainID[chain]
+	if !ok {
+		return "", false
+	}
+	if token.Native {
+		return tcChain + "." + token.Symbol, true
+	}
+	return strings.ToUpper(fmt.Sprintf("%s.%s-%s", tcChain, token.Symbol, token.Address.Hex())), true
 }
 
 // ERC20 ABI for approve function
 const ERC20ApproveABI = `[{"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
 
+// NativeToken returns the catalog entry for a chain's native coin.
+func NativeToken(chain string) (swaps.FundingToken, bool) {
+	for _, t := range KnownFundingTokens[chain] {
+		if t.Native {
+			return t, true
+		}
+	}
+	return swaps.FundingToken{}, false
+}
+
 // Thorchain Router ABI for depositWithExpiry
 const RouterDepositABI = `[{"inputs":[{"name":"vault","type":"address"},{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"memo","type":"string"},{"name":"expiry","type":"uint256"}],"name":"depositWithExpiry","outputs":[],"stateMutability":"payable","type":"function"}]`
@@ -2,7 +2,6 @@ package thorchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
@@ -14,37 +13,83 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 
-	"github.com/RaghavSood/fundbot/balances"
 	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/wallet"
 )
 
 // ChainIDs for EVM chains
 var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
 	"avalanche": big.NewInt(43114),
 	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
 }
 
 type Provider struct {
 	client     *Client
 	rpcClients map[string]*ethclient.Client // keyed by "avalanche", "base"
+	name       string
+	category   string
+	streaming  StreamingParams
 }
 
-func NewProvider(rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+func NewProvider(rpcClients map[string]*ethclient.Client, httpClient *http.Client, affiliate Affiliate) *Provider {
+	client := NewClient(httpClient)
+	client.affiliate = affiliate
 	return &Provider{
-		client:     NewClient(httpClient),
+		client:     client,
 		rpcClients: rpcClients,
+		name:       "thorchain",
+		category:   "dex",
+		streaming:  DefaultStreaming,
 	}
 }
 
+// NewStreamingProvider returns a Thorchain provider that quotes and executes
+// long streaming swaps with the given parameters. Streaming swaps get better
+// pricing on large amounts but can take many minutes to complete, so the
+// provider uses the "dex-streaming" category and is only used when requested
+// explicitly via the "stream" hint.
+func NewStreamingProvider(rpcClients map[string]*ethclient.Client, httpClient *http.Client, streaming StreamingParams, affiliate Affiliate) *Provider {
+	client := NewClient(httpClient)
+	client.affiliate = affiliate
+	return &Provider{
+		client:     client,
+		rpcClients: rpcClients,
+		name:       "thorchain-streaming",
+		category:   "dex-streaming",
+		streaming:  streaming,
+	}
+}
+
+// streamingFor returns the streaming parameters to quote with: those of a
+// "stream:<interval>/<quantity>" hint on the streaming provider, otherwise
+// the provider's own.
+func (p *Provider) streamingFor(ctx context.Context) StreamingParams {
+	if p.category == "dex-streaming" {
+		if h, ok := swaps.StreamHintFrom(ctx); ok {
+			return StreamingParams{Interval: h.Interval, Quantity: h.Quantity}
+		}
+	}
+	return p.streaming
+}
+
 func (p *Provider) Name() string {
-	return "thorchain"
+	return p.name
 }
 
 func (p *Provider) Category() string {
-	return "dex"
+	return p.category
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return ThornodeBaseURL
 }
 
 func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
@@ -53,57 +98,41 @@ func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
 }
 
 func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
-	// USDC has 6 decimals; Thorchain expects 1e8, so multiply USD by 1e8
-	// (1 USDC = 1 USD, 6 decimals native, thorchain uses 8 decimal representation)
-	thorAmount := int64(usdAmount * 1e8)
-
 	// Use resolved hint if available, otherwise use the asset string directly.
 	toAssetStr := toAsset.String()
 	if toAsset.Hints != nil && toAsset.Hints.ThorchainAsset != "" {
 		toAssetStr = toAsset.Hints.ThorchainAsset
 	}
 
-	// Required USDC in smallest unit (6 decimals)
-	requiredUSDC := new(big.Int).SetInt64(int64(usdAmount * 1e6))
-
 	var quotes []swaps.Quote
 
-	for rpcKey, tcAsset := range SourceAssets {
-		// Check USDC balance on this chain
+	for rpcKey := range ThorchainChainID {
 		rpc, ok := p.rpcClients[rpcKey]
 		if !ok {
 			continue
 		}
-		usdcAddr, ok := USDCContracts[rpcKey]
-		if !ok {
-			continue
-		}
-		bal, err := balances.USDCBalance(ctx, rpc, usdcAddr, sender)
+
+		// Pick the first funding token with enough balance on this chain; the
+		// router accepts native deposits too
+		token, inputAmount, err := FundingTokens.Select(ctx, rpc, rpcKey, sender, usdAmount, swaps.AnyToken)
 		if err != nil {
-			log.Printf("thorchain: error checking USDC balance on %s: %v", rpcKey, err)
-			continue
-		}
-		if bal.Cmp(requiredUSDC) < 0 {
-			log.Printf("thorchain: skipping %s, insufficient USDC (have %s, need %s)", rpcKey, bal, requiredUSDC)
+			log.Printf("%s: skipping %s: %v", p.name, rpcKey, err)
 			continue
 		}
+		tcAsset, _ := SourceAsset(rpcKey, token)
 
-		quoteResp, err := p.client.GetQuote(ctx, tcAsset, toAssetStr, destination, thorAmount)
+		quoteResp, err := p.client.GetQuote(ctx, tcAsset, toAssetStr, destination, thorUnits(inputAmount, token.Decimals), p.streamingFor(ctx))
 		if err != nil {
-			log.Printf("thorchain quote for %s via %s failed: %v", toAsset, rpcKey, err)
+			log.Printf("%s quote for %s via %s failed: %v", p.name, toAsset, rpcKey, err)
 			continue
 		}
 
-		// Convert input USD to USDC smallest unit (6 decimals)
-		inputAmount := new(big.Int)
-		inputAmount.SetInt64(int64(usdAmount * 1e6))
-
 		expectedOut := new(big.Int)
 		expectedOut.SetString(quoteResp.ExpectedAmountOut, 10)
 
 		quotes = append(quotes, swaps.Quote{
-			Provider:          "thorchain",
-			FromAsset:         mustParseAsset(tcAsset),
+			Provider:          p.name,
+			FromAsset:         token.Asset(rpcKey),
 			ToAsset:           toAsset,
 			FromChain:         rpcKey,
 			InputAmountUSD:    usdAmount,
@@ -119,18 +148,21 @@ func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount flo
 				"recommended_min":   quoteResp.RecommendedMinIn,
 				"gas_rate":          quoteResp.RecommendedGasRate,
 				"outbound_delay_s":  quoteResp.OutboundDelaySecs,
+				"streaming_blocks":  quoteResp.StreamingSwapBlocks,
+				"streaming_swap_s":  quoteResp.StreamingSwapSecs,
+				"total_swap_s":      quoteResp.TotalSwapSecs,
 			},
 		})
 	}
 
 	if len(quotes) == 0 {
-		return nil, fmt.Errorf("no thorchain quotes available for %s", toAsset)
+		return nil, fmt.Errorf("no %s quotes available for %s", p.name, toAsset)
 	}
 
 	return quotes, nil
 }
 
-func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *ecdsa.PrivateKey) (swaps.ExecuteResult, error) {
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
 	rpc, ok := p.rpcClients[quote.FromChain]
 	if !ok {
 		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
@@ -141,22 +173,29 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
 	}
 
-	usdcAddr, ok := USDCContracts[quote.FromChain]
+	token, ok := FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
 	if !ok {
-		return swaps.ExecuteResult{}, fmt.Errorf("no USDC contract for %s", quote.FromChain)
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
 	}
 
 	routerAddr := common.HexToAddress(quote.Router)
 	vaultAddr := common.HexToAddress(quote.VaultAddress)
-	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
-
-	// Step 1: Approve router to spend USDC
-	if err := p.approveERC20(ctx, rpc, chainID, privateKey, fromAddr, usdcAddr, routerAddr, quote.InputAmount); err != nil {
-		return swaps.ExecuteResult{}, fmt.Errorf("approving USDC: %w", err)
+	fromAddr := signer.Address()
+
+	// Native deposits carry the amount as tx value with the zero asset address;
+	// ERC20 deposits need the router approved first
+	value := big.NewInt(0)
+	if token.Native {
+		value = quote.InputAmount
+	} else {
+		// Step 1: Approve router to spend the funding token
+		if err := p.approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, routerAddr, quote.InputAmount); err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", token.Symbol, err)
+		}
 	}
 
 	// Step 2: Call depositWithExpiry on router
-	txHash, err := p.depositWithExpiry(ctx, rpc, chainID, privateKey, fromAddr, routerAddr, vaultAddr, usdcAddr, quote.InputAmount, quote.Memo, quote.Expiry)
+	txHash, err := p.depositWithExpiry(ctx, rpc, chainID, signer, fromAddr, routerAddr, vaultAddr, token.Address, quote.InputAmount, value, quote.Memo, quote.Expiry)
 	if err != nil {
 		return swaps.ExecuteResult{}, fmt.Errorf("deposit: %w", err)
 	}
@@ -164,7 +203,56 @@ func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, privateKey *e
 	return swaps.ExecuteResult{TxHash: txHash}, nil
 }
 
-func (p *Provider) approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, key *ecdsa.PrivateKey, from, token, spender common.Address, amount *big.Int) error {
+// ExecuteCalls runs the approve and router deposit as one batch from a smart
+// account, so there is no wait between them.
+func (p *Provider) ExecuteCalls(ctx context.Context, quote swaps.Quote, sender swaps.CallSender) (swaps.ExecuteResult, error) {
+	token, ok := FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	routerAddr := common.HexToAddress(quote.Router)
+	vaultAddr := common.HexToAddress(quote.VaultAddress)
+
+	var calls []swaps.Call
+	value := big.NewInt(0)
+	if token.Native {
+		value = quote.InputAmount
+	} else {
+		approveABI, err := abi.JSON(strings.NewReader(ERC20ApproveABI))
+		if err != nil {
+			return swaps.ExecuteResult{}, err
+		}
+		data, err := approveABI.Pack("approve", routerAddr, quote.InputAmount)
+		if err != nil {
+			return swaps.ExecuteResult{}, err
+		}
+		calls = append(calls, swaps.Call{To: token.Address, Value: big.NewInt(0), Data: data})
+	}
+
+	depositABI, err := abi.JSON(strings.NewReader(RouterDepositABI))
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+	expiry := quote.Expiry
+	if minExpiry := time.Now().Unix() + 3600; expiry < minExpiry {
+		expiry = minExpiry
+	}
+	data, err := depositABI.Pack("depositWithExpiry", vaultAddr, token.Address, quote.InputAmount, quote.Memo, big.NewInt(expiry))
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("packing deposit: %w", err)
+	}
+	calls = append(calls, swaps.Call{To: routerAddr, Value: value, Data: data})
+
+	txHash, err := sender.SendCalls(ctx, quote.FromChain, calls)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("deposit: %w", err)
+	}
+
+	return swaps.ExecuteResult{TxHash: txHash}, nil
+}
+
+func (p *Provider) approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
 	parsed, err := abi.JSON(strings.NewReader(ERC20ApproveABI))
 	if err != nil {
 		return err
@@ -186,7 +274,7 @@ func (p *Provider) approveERC20(ctx context.Context, rpc *ethclient.Client, chai
 	}
 
 	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
 	if err != nil {
 		return fmt.Errorf("signing approve tx: %w", err)
 	}
@@ -212,7 +300,7 @@ func (p *Provider) approveERC20(ctx context.Context, rpc *ethclient.Client, chai
 	return nil
 }
 
-func (p *Provider) depositWithExpiry(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, key *ecdsa.PrivateKey, from, router, vault, asset common.Address, amount *big.Int, memo string, expiry int64) (string, error) {
+func (p *Provider) depositWithExpiry(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, router, vault, asset common.Address, amount, value *big.Int, memo string, expiry int64) (string, error) {
 	parsed, err := abi.JSON(strings.NewReader(RouterDepositABI))
 	if err != nil {
 		return "", err
@@ -239,9 +327,10 @@ func (p *Provider) depositWithExpiry(ctx context.Context, rpc *ethclient.Client,
 		return "", fmt.Errorf("getting gas price: %w", err)
 	}
 
-	// ERC20 deposit: value is 0 (tokens transferred via approve+transferFrom)
-	tx := types.NewTransaction(nonce, router, big.NewInt(0), 200000, gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	// ERC20 deposits send no value (tokens move via approve+transferFrom);
+	// native deposits send the amount itself
+	tx := types.NewTransaction(nonce, router, value, 200000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
 	if err != nil {
 		return "", fmt.Errorf("signing deposit tx: %w", err)
 	}
@@ -255,11 +344,38 @@ func (p *Provider) depositWithExpiry(ctx context.Context, rpc *ethclient.Client,
 	return signedTx.Hash().Hex(), nil
 }
 
+// thorUnits converts a raw token amount to Thorchain's 8 decimal representation,
+// which it uses for every asset regardless of the token's own decimals.
+func thorUnits(amount *big.Int, decimals int) int64 {
+	if decimals >= 8 {
+		return new(big.Int).Div(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-8)), nil)).Int64()
+	}
+	return new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(8-decimals)), nil)).Int64()
+}
+
+// Stages lists the swap stages CheckStatus reports, in order. Keys match
+// TxStatusResponse.Stage.
+func (p *Provider) Stages() []swaps.Stage {
+	return []swaps.Stage{
+		{Key: "inbound_observed", Label: "Deposit observed by THORChain"},
+		{Key: "inbound_confirmation_counted", Label: "Deposit confirmations counted"},
+		{Key: "inbound_finalised", Label: "Deposit finalised"},
+		{Key: "swap_finalised", Label: "Swap executed"},
+		{Key: "outbound_signed", Label: "Payout sent"},
+	}
+}
+
 func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
 	status, err := p.client.GetTxStatus(ctx, txHash)
 	if err != nil {
 		return "", err
 	}
+	swaps.ReportRawStatus(ctx, status.Stage())
+
+	// Refunds go out through the same outbound stages, so check them first
+	if _, ok := status.RefundTx(); ok {
+		return "refunded", nil
+	}
 
 	// Cross-chain swaps: completed when outbound is signed
 	if status.Stages.OutboundSigned != nil && status.Stages.OutboundSigned.Completed {
@@ -276,10 +392,50 @@ func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID st
 	return "pending", nil
 }
 
-func mustParseAsset(s string) swaps.Asset {
-	a, err := swaps.ParseAsset(s)
+// Result returns the payout outbound of a completed swap. Amounts are in
+// Thorchain's 1e8 units, like the quote's expected output.
+func (p *Provider) Result(ctx context.Context, txHash string, externalID string) (swaps.SwapResult, error) {
+	status, err := p.client.GetTxStatus(ctx, txHash)
+	if err != nil {
+		return swaps.SwapResult{}, err
+	}
+	for _, out := range status.OutTxs {
+		if strings.HasPrefix(strings.ToUpper(out.Memo), "REFUND") {
+			continue
+		}
+		var result swaps.SwapResult
+		if len(out.Coins) > 0 {
+			result.OutputAmount = out.Coins[0].Amount
+		}
+		result.DestTxHash = outTxHash(out)
+		return result, nil
+	}
+	return swaps.SwapResult{}, nil
+}
+
+// outTxHash formats an outbound ID the way the destination chain's explorers
+// expect: 0x-prefixed lower-case hex for EVM chains, as-is otherwise.
+func outTxHash(out OutTx) string {
+	if out.ID == "" || strings.Trim(out.ID, "0") == "" {
+		return "" // native RUNE payouts have no outbound hash
+	}
+	switch out.Chain {
+	case "ETH", "AVAX", "BSC", "BASE":
+		return "0x" + strings.ToLower(out.ID)
+	}
+	return out.ID
+}
+
+// RefundTx returns the hash of Thorchain's refund outbound for the swap.
+func (p *Provider) RefundTx(ctx context.Context, txHash string, externalID string) (string, error) {
+	status, err := p.client.GetTxStatus(ctx, txHash)
 	if err != nil {
-		panic(err)
+		return "", err
+	}
+	out, ok := status.RefundTx()
+	if !ok {
+		return "", nil
 	}
-	return a
+	// Thorchain reports EVM hashes as upper-case hex without the prefix
+	return outTxHash(out), nil
 }
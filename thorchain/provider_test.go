@@ -0,0 +1,46 @@
+package thorchain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+func TestStreamHintReachesQuote(t *testing.T) {
+	var interval, quantity string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		interval = r.URL.Query().Get("streaming_interval")
+		quantity = r.URL.Query().Get("streaming_quantity")
+		w.Write([]byte(`{"expected_amount_out": "1000"}`))
+	}))
+	defer srv.Close()
+
+	streaming := NewStreamingProvider(nil, srv.Client(), StreamingParams{Interval: 3}, Affiliate{})
+	plain := NewProvider(nil, srv.Client(), Affiliate{})
+
+	hinted := swaps.WithStreamHint(context.Background(), swaps.StreamHint{Interval: 5, Quantity: 10})
+
+	tests := []struct {
+		name     string
+		p        *Provider
+		ctx      context.Context
+		interval string
+		quantity string
+	}{
+		{"hinted", streaming, hinted, "5", "10"},
+		{"configured", streaming, context.Background(), "3", "0"},
+		{"not streaming", plain, hinted, "1", "0"},
+	}
+	for _, tt := range tests {
+		tt.p.client.baseURL = srv.URL
+		if _, err := tt.p.client.GetQuote(tt.ctx, "BASE.USDC", "BTC.BTC", "bc1q", 100, tt.p.streamingFor(tt.ctx)); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if interval != tt.interval || quantity != tt.quantity {
+			t.Errorf("%s: quoted streaming %s/%s, want %s/%s", tt.name, interval, quantity, tt.interval, tt.quantity)
+		}
+	}
+}
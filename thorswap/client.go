@@ -0,0 +1,133 @@
+package thorswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ThorSwap's aggregator is served by the SwapKit API.
+const baseURL = "https://api.swapkit.dev"
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// QuoteRequest is the body for POST /quote.
+type QuoteRequest struct {
+	SellAsset          string  `json:"sellAsset"`
+	BuyAsset           string  `json:"buyAsset"`
+	SellAmount         string  `json:"sellAmount"` // decimal units, e.g. "25.5"
+	SourceAddress      string  `json:"sourceAddress"`
+	DestinationAddress string  `json:"destinationAddress"`
+	Slippage           float64 `json:"slippage"`
+	IncludeTx          bool    `json:"includeTx"`
+}
+
+// Tx is the EVM transaction for a route.
+type Tx struct {
+	To    string `json:"to"`
+	From  string `json:"from"`
+	Data  string `json:"data"`
+	Value string `json:"value"`
+	Gas   string `json:"gas"`
+}
+
+// Route is one candidate route in a quote.
+type Route struct {
+	Providers                    []string `json:"providers"`
+	SellAsset                    string   `json:"sellAsset"`
+	BuyAsset                     string   `json:"buyAsset"`
+	ExpectedBuyAmount            string   `json:"expectedBuyAmount"`
+	ExpectedBuyAmountMaxSlippage string   `json:"expectedBuyAmountMaxSlippage"`
+	TargetAddress                string   `json:"targetAddress"`
+	Tx                           *Tx      `json:"tx"`
+	Meta                         struct {
+		ApprovalAddress string   `json:"approvalAddress"`
+		Tags            []string `json:"tags"`
+	} `json:"meta"`
+	EstimatedTime struct {
+		Total float64 `json:"total"`
+	} `json:"estimatedTime"`
+}
+
+// QuoteResponse is the response from POST /quote.
+type QuoteResponse struct {
+	QuoteID string  `json:"quoteId"`
+	Routes  []Route `json:"routes"`
+	Error   string  `json:"error"`
+}
+
+// TrackResponse is the response from POST /track.
+type TrackResponse struct {
+	Status string `json:"status"` // not_started, pending, swapping, completed, refunded, failed, unknown
+	Hash   string `json:"hash"`
+}
+
+// GetQuote requests routes for a swap, including executable transactions.
+func (c *Client) GetQuote(ctx context.Context, reqBody QuoteRequest) (*QuoteResponse, error) {
+	var result QuoteResponse
+	if err := c.post(ctx, "/quote", reqBody, &result); err != nil {
+		return nil, fmt.Errorf("thorswap quote: %w", err)
+	}
+	return &result, nil
+}
+
+// Track returns the status of a swap by its source chain ID and tx hash.
+func (c *Client) Track(ctx context.Context, chainID, txHash string) (*TrackResponse, error) {
+	payload := map[string]string{
+		"chainId": chainID,
+		"hash":    txHash,
+	}
+
+	var result TrackResponse
+	if err := c.post(ctx, "/track", payload, &result); err != nil {
+		return nil, fmt.Errorf("thorswap track: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, payload interface{}, out interface{}) error {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+path, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package thorswap
+
+import "github.com/RaghavSood/fundbot/swaps"
+
+// ThorSwap uses the same CHAIN.SYMBOL-0xCONTRACT notation as Thorchain, so target
+// assets are passed through unchanged and validated server-side.
+
+// sourceChainIDs maps our RPC chain name to the chain ID used by the track endpoint.
+var sourceChainIDs = map[string]string{
+	"avalanche": "43114",
+	"base":      "8453",
+	"ethereum":  "1",
+	"optimism":  "10",
+	"polygon":   "137",
+}
+
+// SourceAsset returns the ThorSwap notation for a funding token on a source RPC chain.
+func SourceAsset(chain string, token swaps.FundingToken) (string, bool) {
+	if _, ok := sourceChainIDs[chain]; !ok {
+		return "", false
+	}
+	return token.Asset(chain).String(), true
+}
+
+// SourceChainID returns the tracking chain ID for a source RPC chain.
+func SourceChainID(chain string) (string, bool) {
+	id, ok := sourceChainIDs[chain]
+	return id, ok
+}
+
+// SupportedSourceChains returns the RPC chain keys that ThorSwap can fund swaps from.
+func SupportedSourceChains() []string {
+	chains := make([]string, 0, len(sourceChainIDs))
+	for k := range sourceChainIDs {
+		chains = append(chains, k)
+	}
+	return chains
+}
@@ -0,0 +1,352 @@
+package thorswap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(apiKey, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "thorswap"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	// Like Thorchain, ThorSwap has no static mapping — assets are validated server-side.
+	return false
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	// Use resolved hint if available, otherwise use the asset string directly.
+	buyAsset := toAsset.String()
+	if toAsset.Hints != nil && toAsset.Hints.ThorchainAsset != "" {
+		buyAsset = toAsset.Hints.ThorchainAsset
+	}
+
+	var quotes []swaps.Quote
+
+	for _, chain := range SupportedSourceChains() {
+		rpc, ok := p.rpcClients[chain]
+		if !ok {
+			continue
+		}
+
+		// Pick the first funding token with enough balance on this chain
+		token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, nil)
+		if err != nil {
+			log.Printf("thorswap: skipping %s: %v", chain, err)
+			continue
+		}
+		sellAsset, _ := SourceAsset(chain, token)
+
+		resp, err := p.client.GetQuote(ctx, QuoteRequest{
+			SellAsset:          sellAsset,
+			BuyAsset:           buyAsset,
+			SellAmount:         strconv.FormatFloat(usdAmount, 'f', 6, 64),
+			SourceAddress:      sender.Hex(),
+			DestinationAddress: destination,
+			Slippage:           3,
+			IncludeTx:          true,
+		})
+		if err != nil {
+			log.Printf("thorswap quote for %s via %s failed: %v", toAsset, chain, err)
+			continue
+		}
+
+		route := bestRoute(resp.Routes)
+		if route == nil {
+			log.Printf("thorswap quote for %s via %s returned no executable route", toAsset, chain)
+			continue
+		}
+
+		quotes = append(quotes, swaps.Quote{
+			Provider:          "thorswap",
+			FromAsset:         token.Asset(chain),
+			ToAsset:           toAsset,
+			FromChain:         chain,
+			InputAmountUSD:    usdAmount,
+			InputAmount:       inputAmount,
+			ExpectedOutput:    route.ExpectedBuyAmount,
+			ExpectedOutputRaw: parseToBigInt(route.ExpectedBuyAmount),
+			Router:            route.Tx.To,
+			ExtraData: map[string]interface{}{
+				"thorswap_tx":        *route.Tx,
+				"thorswap_approval":  route.Meta.ApprovalAddress,
+				"thorswap_providers": strings.Join(route.Providers, ","),
+				"thorswap_min_out":   route.ExpectedBuyAmountMaxSlippage,
+				"total_swap_s":       int64(route.EstimatedTime.Total),
+			},
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("thorswap: no quotes available for %s", toAsset)
+	}
+
+	return quotes, nil
+}
+
+// bestRoute returns the executable route with the highest expected output.
+func bestRoute(routes []Route) *Route {
+	var best *Route
+	var bestOut *big.Int
+	for i := range routes {
+		r := &routes[i]
+		if r.Tx == nil || r.Tx.To == "" {
+			continue
+		}
+		out := parseToBigInt(r.ExpectedBuyAmount)
+		if best == nil || out.Cmp(bestOut) > 0 {
+			best = r
+			bestOut = out
+		}
+	}
+	return best
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	tx, ok := quote.ExtraData["thorswap_tx"].(Tx)
+	if !ok || tx.To == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("thorswap: missing transaction in quote ExtraData")
+	}
+	approval, _ := quote.ExtraData["thorswap_approval"].(string)
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	trackChainID, ok := SourceChainID(quote.FromChain)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no tracking chain ID for %s", quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	// Step 1: Approve the route's spender, if it needs one
+	if approval != "" {
+		if err := approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, common.HexToAddress(approval), quote.InputAmount); err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", token.Symbol, err)
+		}
+	}
+
+	// Step 2: Send the swap transaction
+	txHash, err := sendSwapTx(ctx, rpc, chainID, signer, fromAddr, tx)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("swap tx: %w", err)
+	}
+
+	// The track endpoint is keyed by source chain ID + tx hash
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: trackChainID,
+	}, nil
+}
+
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	if externalID == "" {
+		return "", fmt.Errorf("thorswap: missing source chain ID")
+	}
+
+	status, err := p.client.Track(ctx, externalID, txHash)
+	if err != nil {
+		return "", err
+	}
+	swaps.ReportRawStatus(ctx, status.Status)
+
+	switch status.Status {
+	case "completed":
+		return "completed", nil
+	case "refunded":
+		return "refunded", nil
+	case "failed":
+		return "failed", nil
+	default:
+		// not_started, pending, swapping, unknown
+		return "pending", nil
+	}
+}
+
+func approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
+	parsed, err := abi.JSON(strings.NewReader(thorchain.ERC20ApproveABI))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("signing approve tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("sending approve tx: %w", err)
+	}
+
+	log.Printf("ThorSwap approve tx sent: %s", signedTx.Hash().Hex())
+
+	// Wait for approval to be mined with 2-minute timeout (swap depends on this)
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return fmt.Errorf("waiting for approve: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx failed")
+	}
+
+	return nil
+}
+
+func sendSwapTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, txData Tx) (string, error) {
+	to := common.HexToAddress(txData.To)
+
+	data, err := hexutil.Decode(txData.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	// Values may be decimal or 0x-prefixed hex
+	value := new(big.Int)
+	if txData.Value != "" {
+		if _, ok := value.SetString(txData.Value, 0); !ok {
+			return "", fmt.Errorf("invalid value %q", txData.Value)
+		}
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	var gasLimit uint64
+	if g, ok := new(big.Int).SetString(txData.Gas, 0); ok && g.Sign() > 0 {
+		gasLimit = g.Uint64()
+	} else {
+		gasLimit, err = rpc.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Value: value, Data: data})
+		if err != nil {
+			return "", fmt.Errorf("estimating gas: %w", err)
+		}
+		// Pad the estimate to avoid out-of-gas on state changes between estimate and inclusion
+		gasLimit = gasLimit * 12 / 10
+	}
+
+	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing swap tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending swap tx: %w", err)
+	}
+
+	log.Printf("ThorSwap swap tx sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
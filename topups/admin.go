@@ -0,0 +1,129 @@
+package topups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// ErrStatus is returned by admin actions on a topup whose status doesn't
+// allow them.
+var ErrStatus = errors.New("action not allowed")
+
+// Statuses an admin can mark resolved: ones the tracker is stuck on or
+// gave up on.
+var resolvable = map[string]bool{"pending": true, "stalled": true, "failed": true}
+
+// Resolve marks a topup resolved with the admin's note. The tracker stops
+// following it, so it is for records that need no further action or were
+// settled by hand.
+func (s *Service) Resolve(ctx context.Context, shortID, note string) error {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+
+	topup, err := s.store.GetTopupByShortID(ctx, shortID)
+	if err != nil {
+		return err
+	}
+	if !resolvable[topup.Status] {
+		return fmt.Errorf("%w: topup %s is %s", ErrStatus, shortID, topup.Status)
+	}
+	return s.resolve(ctx, topup, note)
+}
+
+// Retry submits a failed or refunded topup again for the same owner, asset,
+// destination and amount, routed afresh, and resolves the original with a
+// note naming the new topup. Assets the bot resolved interactively are
+// retried without the resolver's hints.
+func (s *Service) Retry(ctx context.Context, shortID string, progress func(string)) (*Result, error) {
+	s.adminMu.Lock()
+	defer s.adminMu.Unlock()
+
+	topup, err := s.store.GetTopupByShortID(ctx, shortID)
+	if err != nil {
+		return nil, err
+	}
+	if topup.Status != "failed" && topup.Status != "refunded" {
+		return nil, fmt.Errorf("%w: topup %s is %s", ErrStatus, shortID, topup.Status)
+	}
+	quote, err := s.store.GetQuote(ctx, topup.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("loading quote: %w", err)
+	}
+	asset, err := swaps.ParseAsset(quote.ToAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy topups have no chat; they were made in the user's DM
+	owner := Owner{UserID: topup.UserID, ChatID: topup.ChatID}
+	if owner.ChatID == 0 {
+		owner.ChatID = owner.UserID
+	}
+	owner.Private = owner.ChatID == owner.UserID
+
+	result, err := s.Execute(ctx, Request{
+		Owner:       owner,
+		Asset:       asset,
+		Destination: quote.Destination,
+		USDAmount:   quote.InputAmountUsd,
+	}, progress)
+	if err != nil {
+		return result, err
+	}
+
+	note := fmt.Sprintf("Retried as %s", result.Topup.ShortID)
+	if result.Topup.ShortID == "" {
+		note = fmt.Sprintf("Retried in tx %s", result.Swap.TxHash)
+	}
+	if err := s.resolve(ctx, topup, note); err != nil {
+		// The new swap is out either way
+		log.Printf("Error resolving retried topup %s: %v", shortID, err)
+	}
+	return result, nil
+}
+
+// resolve moves topup from its loaded status to resolved, failing if the
+// tracker changed it in the meantime.
+func (s *Service) resolve(ctx context.Context, topup db.GetTopupByShortIDRow, note string) error {
+	n, err := s.store.ResolveTopup(ctx, db.ResolveTopupParams{
+		ResolutionNote: note,
+		ID:             topup.ID,
+		Status:         topup.Status,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: topup %s is no longer %s", ErrStatus, topup.ShortID, topup.Status)
+	}
+
+	log.Printf("Topup %s resolved (was %s): %s", topup.ShortID, topup.Status, note)
+	if err := s.store.InsertTopupStatusEvent(ctx, db.InsertTopupStatusEventParams{TopupID: topup.ID, Status: "resolved"}); err != nil {
+		log.Printf("Error recording status of topup %s: %v", topup.ShortID, err)
+	}
+	s.events.TopupStatus(events.Topup{
+		ShortID:   topup.ShortID,
+		Status:    "resolved",
+		Provider:  topup.Provider,
+		FromChain: topup.FromChain,
+	})
+	s.hooks.TopupStatus(webhooks.Topup{
+		ShortID:        topup.ShortID,
+		Status:         "resolved",
+		PreviousStatus: topup.Status,
+		Provider:       topup.Provider,
+		FromChain:      topup.FromChain,
+		TxHash:         topup.TxHash,
+		ExternalID:     topup.ExternalID,
+		UserID:         topup.UserID,
+		ChatID:         topup.ChatID,
+	})
+	return nil
+}
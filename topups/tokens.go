@@ -0,0 +1,80 @@
+package topups
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// ErrTokenDisabled is returned for assets the admin disabled in the token
+// registry.
+var ErrTokenDisabled = errors.New("token disabled by the operator")
+
+// Registered applies the admin's token registry to asset. Tokens are found
+// by contract address when the asset has one, then by chain and symbol
+// unless they are pinned to another contract. A pinned token comes back
+// with its symbol, contract and provider IDs filled in and true, taking
+// precedence over static mappings and the resolver; a disabled one fails
+// with ErrTokenDisabled. Other assets are returned unchanged.
+func (s *Service) Registered(ctx context.Context, asset swaps.Asset) (swaps.Asset, bool, error) {
+	token, ok, err := s.lookupToken(ctx, asset)
+	if err != nil {
+		return asset, false, fmt.Errorf("token registry: %w", err)
+	}
+	if !ok {
+		return asset, false, nil
+	}
+	if !token.Enabled {
+		return asset, false, fmt.Errorf("%s.%s: %w", token.Chain, token.Symbol, ErrTokenDisabled)
+	}
+
+	asset.Symbol = token.Symbol
+	if token.ContractAddress != "" {
+		asset.ContractAddress = token.ContractAddress
+	}
+	hints := &swaps.ResolvedHints{
+		ThorchainAsset:     token.ThorchainAsset,
+		SimpleSwapSymbol:   token.SimpleswapSymbol,
+		NearIntentsTokenID: token.NearintentsTokenID,
+		HoudiniSymbol:      token.HoudiniSymbol,
+		StealthEXCurrency:  token.StealthexCurrency,
+		RelayCurrency:      token.RelayCurrency,
+	}
+	if *hints != (swaps.ResolvedHints{}) {
+		asset.Hints = hints
+	}
+	return asset, true, nil
+}
+
+func (s *Service) lookupToken(ctx context.Context, asset swaps.Asset) (db.Token, bool, error) {
+	chain := strings.ToUpper(asset.Chain)
+	if asset.ContractAddress != "" {
+		token, err := s.store.GetTokenByContract(ctx, db.GetTokenByContractParams{Chain: chain, ContractAddress: asset.ContractAddress})
+		if err == nil {
+			return token, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return db.Token{}, false, err
+		}
+	}
+	if asset.Symbol == "" {
+		return db.Token{}, false, nil
+	}
+
+	token, err := s.store.GetTokenBySymbol(ctx, db.GetTokenBySymbolParams{Chain: chain, Symbol: strings.ToUpper(asset.Symbol)})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Token{}, false, nil
+	}
+	if err != nil {
+		return db.Token{}, false, err
+	}
+	if asset.ContractAddress != "" && token.ContractAddress != "" && !strings.EqualFold(token.ContractAddress, asset.ContractAddress) {
+		return db.Token{}, false, nil
+	}
+	return token, true, nil
+}
@@ -0,0 +1,366 @@
+// Package topups runs quotes and topups for the Telegram bot and the REST
+// API: wallet selection, fresh addresses, swap execution and the records the
+// tracker picks up afterwards.
+package topups
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/RaghavSood/fundbot/config"
+	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/events"
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+// Owner identifies who a quote or topup is for: a Telegram user, in a group
+// chat or their DM. API clients act as the DM of their configured user.
+type Owner struct {
+	UserID    int64 // Telegram user ID
+	Username  string
+	ChatID    int64
+	ChatTitle string
+	Private   bool
+}
+
+// Request is a swap of USDAmount of funding stablecoin into Asset, sent to
+// Destination.
+type Request struct {
+	Owner       Owner
+	Asset       swaps.Asset
+	Destination string
+	USDAmount   float64
+	Hint        swaps.RoutingHint
+}
+
+// Result is a submitted topup.
+type Result struct {
+	Topup db.InsertTopupRow
+	Quote *swaps.Quote
+	Swap  swaps.ExecuteResult
+
+	// Fresh is the fresh address the swap was sent from, when enabled
+	Fresh *db.FreshAddress
+}
+
+type Service struct {
+	cfg     *config.Config
+	store   *db.Store
+	swapMgr *swaps.Manager
+	keyring wallet.Keyring
+	hooks   *webhooks.Client
+	events  *events.Bus
+
+	// Swaps from one wallet are serialized so concurrent bot and API
+	// requests don't race for nonces
+	walletMu sync.Mutex
+	wallets  map[uint32]*sync.Mutex
+
+	// Admin retries and resolutions run one at a time, so a topup can't be
+	// retried twice
+	adminMu sync.Mutex
+}
+
+func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, keyring wallet.Keyring, hooks *webhooks.Client, bus *events.Bus) *Service {
+	return &Service{
+		cfg:     cfg,
+		store:   store,
+		swapMgr: swapMgr,
+		keyring: keyring,
+		hooks:   hooks,
+		events:  bus,
+		wallets: make(map[uint32]*sync.Mutex),
+	}
+}
+
+// WalletIndex returns the BIP44 derivation index for an owner.
+// Single mode: always 0. Multi mode: address_assignments row ID of the user
+// in DMs, or of the group chat.
+func (s *Service) WalletIndex(ctx context.Context, owner Owner) (uint32, error) {
+	if s.cfg.Mode == config.ModeSingle {
+		return 0, nil
+	}
+
+	var assignedToID int64
+	var assignedToType string
+
+	if owner.Private {
+		user, err := s.store.GetOrCreateUser(ctx, owner.UserID, owner.Username)
+		if err != nil {
+			return 0, err
+		}
+		assignedToID = user.ID
+		assignedToType = "user"
+	} else {
+		chat, err := s.store.GetOrCreateChat(ctx, owner.ChatID, owner.ChatTitle)
+		if err != nil {
+			return 0, err
+		}
+		assignedToID = chat.ID
+		assignedToType = "chat"
+	}
+
+	assignment, err := s.store.GetOrCreateAddressAssignment(ctx, assignedToID, assignedToType, s.cfg.PoolFor(owner.ChatID))
+	if err != nil {
+		return 0, fmt.Errorf("address assignment: %w", err)
+	}
+	return uint32(assignment.ID), nil
+}
+
+// Quote fetches and stores the best quote for req from the owner's wallet.
+// A quote that can't be stored is still returned, with ID 0.
+func (s *Service) Quote(ctx context.Context, req Request) (*swaps.Quote, int64, error) {
+	asset, _, err := s.Registered(ctx, req.Asset)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Asset = asset
+
+	index, err := s.WalletIndex(ctx, req.Owner)
+	if err != nil {
+		return nil, 0, err
+	}
+	sender, err := s.keyring.Address(index)
+	if err != nil {
+		return nil, 0, fmt.Errorf("deriving address: %w", err)
+	}
+
+	quote, err := s.swapMgr.BestQuote(ctx, req.Asset, req.USDAmount, req.Destination, sender, req.Hint)
+	if err != nil {
+		return nil, 0, fmt.Errorf("quote: %w", err)
+	}
+
+	quoteID, err := s.insertQuote(ctx, quote, req.Owner, req.Destination)
+	if err != nil {
+		log.Printf("Error storing quote: %v", err)
+	}
+	return quote, quoteID, nil
+}
+
+// Execute quotes and executes req from the owner's wallet, and records the
+// topup for the tracker. progress is told about each step worth reporting
+// (gas warnings, fresh address funding). A watch-only wallet returns a
+// *wallet.UnsignedTxError for the first transaction to sign; execution errors
+// come with the result so far, naming the quote that was tried.
+func (s *Service) Execute(ctx context.Context, req Request, progress func(string)) (*Result, error) {
+	asset, _, err := s.Registered(ctx, req.Asset)
+	if err != nil {
+		return nil, err
+	}
+	req.Asset = asset
+
+	index, err := s.WalletIndex(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := s.keyring.Signer(index)
+	if err != nil {
+		return nil, fmt.Errorf("loading signer: %w", err)
+	}
+
+	mu := s.walletLock(index)
+	mu.Lock()
+	defer mu.Unlock()
+
+	quote, err := s.swapMgr.BestQuote(ctx, req.Asset, req.USDAmount, req.Destination, signer.Address(), req.Hint)
+	if err != nil {
+		return nil, fmt.Errorf("quote: %w", err)
+	}
+
+	var fresh *db.FreshAddress
+	if s.cfg.FreshAddresses {
+		fresh, signer, quote, err = s.moveToFresh(ctx, index, signer, quote, req, progress)
+		if err != nil {
+			return nil, fmt.Errorf("fresh address: %w", err)
+		}
+	}
+
+	quoteID, err := s.insertQuote(ctx, quote, req.Owner, req.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("storing quote: %w", err)
+	}
+
+	if warning := s.swapMgr.GasWarning(ctx, quote); warning != "" {
+		progress(warning)
+	}
+
+	result := &Result{Quote: quote, Fresh: fresh}
+	result.Swap, err = s.swapMgr.ExecuteSwap(ctx, quote, signer)
+	if err != nil {
+		return result, fmt.Errorf("swap execution failed: %w", err)
+	}
+	swap := result.Swap
+
+	// The swap is out; storing it can fail without failing the topup
+	topup, err := s.store.InsertTopupWithShortID(ctx, db.InsertTopupParams{
+		Type:        "fast",
+		QuoteID:     quoteID,
+		UserID:      req.Owner.UserID,
+		Provider:    quote.Provider,
+		FromChain:   quote.FromChain,
+		TxHash:      swap.TxHash,
+		Status:      "pending",
+		ChatID:      req.Owner.ChatID,
+		ExternalID:  swap.ExternalID,
+		WalletIndex: int64(index),
+	})
+	if err != nil {
+		log.Printf("Error storing topup: %v", err)
+		return result, nil
+	}
+	result.Topup = topup
+
+	if err := s.store.InsertTopupStatusEvent(ctx, db.InsertTopupStatusEventParams{TopupID: topup.ID, Status: "pending"}); err != nil {
+		log.Printf("Error recording status of topup %s: %v", topup.ShortID, err)
+	}
+	s.recordTransactions(ctx, topup, quote.FromChain, fresh, swap.Transactions)
+	s.events.TopupStatus(events.Topup{
+		ShortID:   topup.ShortID,
+		Status:    "pending",
+		Provider:  quote.Provider,
+		FromChain: quote.FromChain,
+	})
+	s.hooks.TopupStatus(webhooks.Topup{
+		ShortID:    topup.ShortID,
+		Status:     "pending",
+		Provider:   quote.Provider,
+		FromChain:  quote.FromChain,
+		TxHash:     swap.TxHash,
+		ExternalID: swap.ExternalID,
+		UserID:     req.Owner.UserID,
+		ChatID:     req.Owner.ChatID,
+	})
+	if fresh != nil {
+		if err := s.store.SetFreshAddressTopup(ctx, db.SetFreshAddressTopupParams{TopupID: topup.ID, ID: fresh.ID}); err != nil {
+			log.Printf("Error linking fresh address %d to topup %s: %v", fresh.ID, topup.ShortID, err)
+		}
+	}
+	return result, nil
+}
+
+// LockWallet holds the wallet's swap lock until the returned function is
+// called, so other spends from it (sweeps) don't race a topup for nonces
+// and balance.
+func (s *Service) LockWallet(index uint32) (unlock func()) {
+	mu := s.walletLock(index)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *Service) walletLock(index uint32) *sync.Mutex {
+	s.walletMu.Lock()
+	defer s.walletMu.Unlock()
+	mu, ok := s.wallets[index]
+	if !ok {
+		mu = new(sync.Mutex)
+		s.wallets[index] = mu
+	}
+	return mu
+}
+
+func (s *Service) insertQuote(ctx context.Context, quote *swaps.Quote, owner Owner, destination string) (int64, error) {
+	id, err := s.store.InsertQuote(ctx, db.InsertQuoteParams{
+		Type:           "fast",
+		Provider:       quote.Provider,
+		UserID:         owner.UserID,
+		FromAsset:      quote.FromAsset.String(),
+		FromChain:      quote.FromChain,
+		ToAsset:        quote.ToAsset.String(),
+		Destination:    destination,
+		InputAmountUsd: quote.InputAmountUSD,
+		InputAmount:    quote.InputAmount.String(),
+		ExpectedOutput: quote.ExpectedOutput,
+		Memo:           quote.Memo,
+		Router:         quote.Router,
+		VaultAddress:   quote.VaultAddress,
+		Expiry:         quote.Expiry,
+		ChatID:         owner.ChatID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if quote.Raw != nil {
+		if err := s.store.SaveQuoteRaw(ctx, id, quote.Raw, quote.RawTruncated); err != nil {
+			log.Printf("Error storing provider response of quote %d: %v", id, err)
+		}
+	}
+	s.events.NewQuote(events.Quote{
+		Provider:  quote.Provider,
+		FromChain: quote.FromChain,
+		ToAsset:   quote.ToAsset.String(),
+		AmountUSD: quote.InputAmountUSD,
+	})
+	return id, nil
+}
+
+// moveToFresh funds a freshly derived address with the quote's input and
+// re-quotes from it with the same provider, so the swap's source and refund
+// address isn't the wallet's stable one. The address is recorded before any
+// funds move, so nothing sent there is lost track of.
+func (s *Service) moveToFresh(ctx context.Context, index uint32, signer wallet.Signer, quote *swaps.Quote, req Request, progress func(string)) (*db.FreshAddress, wallet.Signer, *swaps.Quote, error) {
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unknown funding token %s on %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	fresh, err := s.store.CreateFreshAddress(ctx, db.CreateFreshAddressParams{
+		ParentIndex:   int64(index),
+		ParentAddress: signer.Address().Hex(),
+		Chain:         quote.FromChain,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("allocating fresh address: %w", err)
+	}
+	freshSigner, err := s.keyring.Signer(db.FreshIndex(fresh.ID))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("deriving fresh address: %w", err)
+	}
+	freshAddr := freshSigner.Address()
+	if err := s.store.SetFreshAddressFunding(ctx, db.SetFreshAddressFundingParams{Address: freshAddr.Hex(), ID: fresh.ID}); err != nil {
+		return nil, nil, nil, fmt.Errorf("recording fresh address: %w", err)
+	}
+
+	progress(fmt.Sprintf("Moving %s %s on %s to fresh address `%s`...",
+		token.Format(quote.InputAmount), token.Symbol, quote.FromChain, freshAddr.Hex()))
+
+	txHash, err := s.swapMgr.FundFresh(ctx, quote.FromChain, signer, freshAddr, token, quote.InputAmount)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("funding %s: %w", freshAddr.Hex(), err)
+	}
+	if err := s.store.SetFreshAddressFunding(ctx, db.SetFreshAddressFundingParams{Address: freshAddr.Hex(), FundingTxHash: txHash, ID: fresh.ID}); err != nil {
+		log.Printf("Error recording funding of fresh address %d: %v", fresh.ID, err)
+	}
+
+	// Quotes can embed the sender, so quote again from the fresh address
+	freshQuote, err := s.swapMgr.BestQuote(ctx, req.Asset, req.USDAmount, req.Destination, freshAddr, swaps.RoutingHint{Type: "provider", Value: quote.Provider, Stream: req.Hint.Stream})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("re-quoting from %s (funds are there): %w", freshAddr.Hex(), err)
+	}
+
+	fresh.Address = freshAddr.Hex()
+	fresh.FundingTxHash = txHash
+	return &fresh, freshSigner, freshQuote, nil
+}
+
+// recordTransactions stores the transactions broadcast for a topup so the
+// tracker can price their gas once mined.
+func (s *Service) recordTransactions(ctx context.Context, topup db.InsertTopupRow, chain string, fresh *db.FreshAddress, txs []swaps.SentTx) {
+	if fresh != nil && fresh.FundingTxHash != "" {
+		txs = append([]swaps.SentTx{{Hash: fresh.FundingTxHash, Kind: "funding"}}, txs...)
+	}
+	for _, tx := range txs {
+		if err := s.store.InsertTopupTransaction(ctx, db.InsertTopupTransactionParams{
+			TopupID: topup.ID,
+			Chain:   chain,
+			TxHash:  tx.Hash,
+			Kind:    tx.Kind,
+		}); err != nil {
+			log.Printf("Error recording tx %s of topup %s: %v", tx.Hash, topup.ShortID, err)
+		}
+	}
+}
@@ -2,17 +2,45 @@ package tracker
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
+	"math/rand/v2"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/RaghavSood/fundbot/config"
 	"github.com/RaghavSood/fundbot/cowswap"
 	"github.com/RaghavSood/fundbot/db"
+	"github.com/RaghavSood/fundbot/delivery"
+	"github.com/RaghavSood/fundbot/events"
 	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+	"github.com/RaghavSood/fundbot/webhooks"
+)
+
+const (
+	// Gas refill orders that expire, are cancelled or can't be placed are
+	// resubmitted until this many attempts have been made
+	maxGasRefillAttempts = 3
+
+	// Wait between gas refill attempts, counted from when the last one was
+	// placed
+	gasRefillRetryCooldown = 5 * time.Minute
+
+	// Limit orders can stay open for days, so their status is checked at
+	// most this often
+	limitOrderCheckInterval = 10 * time.Minute
+
+	// How long after its valid_to a limit order CoW no longer reports is
+	// marked expired
+	limitOrderExpiryGrace = time.Hour
 )
 
 type Tracker struct {
@@ -20,55 +48,160 @@ type Tracker struct {
 	store     *db.Store
 	swapMgr   *swaps.Manager
 	cowClient *cowswap.Client
+	delivery  *delivery.Checker
+	keyring   wallet.Keyring
+	hooks     *webhooks.Client
+	events    *events.Bus
 	botAPI    *tgbotapi.BotAPI
+
+	// Earliest time each provider is due to be polled again
+	nextPoll map[string]time.Time
+
+	// Consecutive status check errors per topup ID
+	failures map[int64]*checkFailures
+
+	// When each open limit order was last checked, by gas refill ID
+	limitChecked map[int64]time.Time
 }
 
-func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, cowClient *cowswap.Client, botAPI *tgbotapi.BotAPI) *Tracker {
+// checkFailures tracks a topup whose status checks keep erroring, so it can
+// be backed off instead of retried on every poll.
+type checkFailures struct {
+	count   int
+	retryAt time.Time
+	alerted bool
+}
+
+func New(cfg *config.Config, store *db.Store, swapMgr *swaps.Manager, cowClient *cowswap.Client, checker *delivery.Checker, keyring wallet.Keyring, hooks *webhooks.Client, bus *events.Bus, botAPI *tgbotapi.BotAPI) *Tracker {
 	return &Tracker{
 		cfg:       cfg,
 		store:     store,
 		swapMgr:   swapMgr,
 		cowClient: cowClient,
+		delivery:  checker,
+		keyring:   keyring,
+		hooks:     hooks,
+		events:    bus,
 		botAPI:    botAPI,
+		nextPoll:  make(map[string]time.Time),
+		failures:  make(map[int64]*checkFailures),
+
+		limitChecked: make(map[int64]time.Time),
 	}
 }
 
 func (t *Tracker) Run(ctx context.Context) {
-	ticker := time.NewTicker(15 * time.Second)
-	defer ticker.Stop()
-
 	// Run once immediately on start
 	t.poll(ctx)
 
 	for {
+		timer := time.NewTimer(t.wait())
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			log.Println("Tracker stopped")
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			t.poll(ctx)
 		}
 	}
 }
 
+// wait returns the delay before the next poll: the base interval plus
+// random jitter.
+func (t *Tracker) wait() time.Duration {
+	d := time.Duration(t.cfg.Tracker.IntervalSeconds) * time.Second
+	if t.cfg.Tracker.JitterSeconds > 0 {
+		d += rand.N(time.Duration(t.cfg.Tracker.JitterSeconds) * time.Second)
+	}
+	return d
+}
+
 func (t *Tracker) poll(ctx context.Context) {
-	t.pollTopups(ctx)
-	t.pollGasRefills(ctx)
+	now := time.Now()
+	t.pollTopups(ctx, now)
+	t.pollGasRefills(ctx, now)
+	t.pollGasCosts(ctx)
+}
+
+// pollGasCosts fills in the gas spent by topup transactions once they are
+// mined. Transactions still unmined after a day are left unpriced; they were
+// most likely dropped or replaced.
+func (t *Tracker) pollGasCosts(ctx context.Context) {
+	txs, err := t.store.ListUnpricedTopupTransactions(ctx)
+	if err != nil {
+		log.Printf("Tracker: error listing unpriced transactions: %v", err)
+		return
+	}
+	for _, tx := range txs {
+		if !swaps.ChainEnabled(tx.Chain) {
+			continue
+		}
+		gasUsed, cost, err := t.swapMgr.GasCost(ctx, tx.Chain, tx.TxHash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Tracker: error fetching receipt of %s on %s: %v", tx.TxHash, tx.Chain, err)
+			continue
+		}
+		if err := t.store.SetTopupTransactionGas(ctx, db.SetTopupTransactionGasParams{
+			GasUsed: int64(gasUsed),
+			GasCost: cost.String(),
+			ID:      tx.ID,
+		}); err != nil {
+			log.Printf("Tracker: error recording gas of %s: %v", tx.TxHash, err)
+		}
+	}
 }
 
-func (t *Tracker) pollTopups(ctx context.Context) {
+// due reports whether provider should be polled in the poll that started at
+// now. Providers without an override are due on every poll; slower ones are
+// skipped until their interval has passed.
+func (t *Tracker) due(provider string, now time.Time) bool {
+	return !now.Before(t.nextPoll[provider])
+}
+
+// polled schedules the next poll of provider after the poll started at now.
+func (t *Tracker) polled(provider string, now time.Time) {
+	interval, ok := t.cfg.Tracker.ProviderIntervals[provider]
+	if !ok {
+		return
+	}
+	t.nextPoll[provider] = now.Add(time.Duration(interval) * time.Second)
+}
+
+func (t *Tracker) pollTopups(ctx context.Context, now time.Time) {
 	pending, err := t.store.ListPendingTopups(ctx)
 	if err != nil {
 		log.Printf("Tracker: error listing pending topups: %v", err)
 		return
 	}
 
+	// Forget error counts for topups that are no longer pending
+	pendingIDs := make(map[int64]bool, len(pending))
+	for _, topup := range pending {
+		pendingIDs[topup.ID] = true
+	}
+	for id := range t.failures {
+		if !pendingIDs[id] {
+			delete(t.failures, id)
+		}
+	}
+
 	if len(pending) == 0 {
 		return
 	}
 
 	log.Printf("Tracker: checking %d pending topup(s)", len(pending))
 
+	checked := make(map[string]bool)
+	defer func() {
+		for provider := range checked {
+			t.polled(provider, now)
+		}
+	}()
+
 	for _, topup := range pending {
 		select {
 		case <-ctx.Done():
@@ -76,13 +209,29 @@ func (t *Tracker) pollTopups(ctx context.Context) {
 		default:
 		}
 
+		// Leave topups on disabled chains pending until the chain is re-enabled
+		if !swaps.ChainEnabled(topup.FromChain) {
+			continue
+		}
+
+		if !t.due(topup.Provider, now) {
+			continue
+		}
+		checked[topup.Provider] = true
+
+		if f, ok := t.failures[topup.ID]; ok && now.Before(f.retryAt) {
+			continue
+		}
+
 		log.Printf("Tracker: checking %s (tx %s)", topup.ShortID, topup.TxHash)
 
-		status, err := t.swapMgr.CheckStatus(ctx, topup.Provider, topup.TxHash, topup.ExternalID)
+		checkCtx, raw := swaps.WithRawStatus(ctx)
+		status, err := t.swapMgr.CheckStatus(checkCtx, topup.Provider, topup.TxHash, topup.ExternalID)
 		if err != nil {
-			log.Printf("Tracker: error checking %s: %v", topup.ShortID, err)
+			t.checkFailed(topup, now, err)
 			continue
 		}
+		t.checkSucceeded(topup)
 
 		log.Printf("Tracker: %s status = %s", topup.ShortID, status)
 
@@ -96,7 +245,15 @@ func (t *Tracker) pollTopups(ctx context.Context) {
 				continue
 			}
 			log.Printf("Tracker: topup %s completed", topup.ShortID)
-			t.notifyUser(topup, "completed")
+			t.reportProgress(ctx, topup, *raw)
+			t.recordStatus(ctx, topup, "completed", *raw)
+			result := t.recordResult(ctx, topup)
+			verified := t.verifyDelivery(ctx, topup, result.DestTxHash)
+			t.notifyUser(topup, "completed", verified)
+			event := topupEvent(topup, "completed")
+			event.ActualOutput, event.DestTxHash = result.OutputAmount, result.DestTxHash
+			event.VerifiedOutput = verified
+			t.hooks.TopupStatus(event)
 		case "failed":
 			if err := t.store.UpdateTopupStatus(ctx, db.UpdateTopupStatusParams{
 				Status: "failed",
@@ -106,16 +263,333 @@ func (t *Tracker) pollTopups(ctx context.Context) {
 				continue
 			}
 			log.Printf("Tracker: topup %s failed", topup.ShortID)
-			t.notifyUser(topup, "failed")
+			t.recordStatus(ctx, topup, "failed", *raw)
+			t.notifyUser(topup, "failed", "")
+			t.hooks.TopupStatus(topupEvent(topup, "failed"))
+		case "refunded":
+			refundTx, err := t.swapMgr.RefundTx(ctx, topup.Provider, topup.TxHash, topup.ExternalID)
+			if err != nil {
+				// The status is what matters; the hash is a nicety
+				log.Printf("Tracker: error looking up refund tx for %s: %v", topup.ShortID, err)
+			}
+			if err := t.store.SetTopupRefunded(ctx, db.SetTopupRefundedParams{
+				RefundTxHash: refundTx,
+				ID:           topup.ID,
+			}); err != nil {
+				log.Printf("Tracker: error updating %s: %v", topup.ShortID, err)
+				continue
+			}
+			log.Printf("Tracker: topup %s refunded (refund tx %q)", topup.ShortID, refundTx)
+			t.recordStatus(ctx, topup, "refunded", *raw)
+			t.notifyRefund(topup, refundTx)
+			event := topupEvent(topup, "refunded")
+			event.RefundTxHash = refundTx
+			t.hooks.TopupStatus(event)
+		default:
+			t.reportProgress(ctx, topup, *raw)
+			if topup.Status == "pending" && now.Sub(topup.CreatedAt) > t.stallAfter(topup.Provider) {
+				t.markStalled(ctx, topup, status, *raw, now)
+			} else {
+				t.recordStatus(ctx, topup, topup.Status, *raw)
+			}
 		}
 	}
 }
 
-func (t *Tracker) pollGasRefills(ctx context.Context) {
-	if t.cowClient == nil {
+// reportProgress keeps a progress message in the topup's chat for providers
+// with stages (see swaps.StageReporter). The first stage reached sends it;
+// later ones edit it in place rather than sending a message per stage.
+func (t *Tracker) reportProgress(ctx context.Context, topup db.ListPendingTopupsRow, raw string) {
+	stages := t.swapMgr.Stages(topup.Provider)
+	reached := -1
+	for i, stage := range stages {
+		if stage.Key == raw {
+			reached = i
+		}
+	}
+	if reached < 0 {
+		return
+	}
+	// Called before recordStatus, so the latest event is the previous poll
+	latest, err := t.store.GetLatestTopupStatusEvent(ctx, topup.ID)
+	if err == nil && latest.RawStatus == raw && topup.ProgressMessageID != 0 {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Topup %s Progress*\n", topup.ShortID)
+	for i, stage := range stages {
+		mark := "·"
+		if i <= reached {
+			mark = "✓"
+		}
+		fmt.Fprintf(&b, "%s %s\n", mark, stage.Label)
+	}
+	fmt.Fprintf(&b, "[View on Explorer](%s)", t.cfg.ExplorerTxURL(topup.FromChain, topup.TxHash))
+
+	chatID := topupChatID(topup)
+	if topup.ProgressMessageID != 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, int(topup.ProgressMessageID), b.String())
+		edit.ParseMode = "Markdown"
+		edit.DisableWebPagePreview = true
+		if _, err := t.botAPI.Send(edit); err != nil {
+			log.Printf("Tracker: error updating progress of %s: %v", topup.ShortID, err)
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	sent, err := t.botAPI.Send(msg)
+	if err != nil {
+		log.Printf("Tracker: error sending progress of %s: %v", topup.ShortID, err)
+		return
+	}
+	if err := t.store.SetTopupProgressMessage(ctx, db.SetTopupProgressMessageParams{
+		ProgressMessageID: int64(sent.MessageID),
+		ID:                topup.ID,
+	}); err != nil {
+		log.Printf("Tracker: error storing progress message of %s: %v", topup.ShortID, err)
+	}
+}
+
+// recordStatus appends to the topup's status history when the status or the
+// provider's raw status has changed since the last recorded event, so
+// repeated polls of an unchanged swap don't grow the table.
+func (t *Tracker) recordStatus(ctx context.Context, topup db.ListPendingTopupsRow, status, raw string) {
+	latest, err := t.store.GetLatestTopupStatusEvent(ctx, topup.ID)
+	if err == nil && latest.Status == status && latest.RawStatus == raw {
+		return
+	}
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Tracker: error loading status history of %s: %v", topup.ShortID, err)
+		return
+	}
+	if err := t.store.InsertTopupStatusEvent(ctx, db.InsertTopupStatusEventParams{
+		TopupID:   topup.ID,
+		Status:    status,
+		RawStatus: raw,
+	}); err != nil {
+		log.Printf("Tracker: error recording status of %s: %v", topup.ShortID, err)
+		return
+	}
+	t.events.TopupStatus(events.Topup{
+		ShortID:   topup.ShortID,
+		Status:    status,
+		RawStatus: raw,
+		Provider:  topup.Provider,
+		FromChain: topup.FromChain,
+	})
+}
+
+// recordResult stores what a completed topup actually delivered, when the
+// provider can report it, for realized-vs-quoted reporting.
+func (t *Tracker) recordResult(ctx context.Context, topup db.ListPendingTopupsRow) swaps.SwapResult {
+	result, err := t.swapMgr.Result(ctx, topup.Provider, topup.TxHash, topup.ExternalID)
+	if err != nil {
+		log.Printf("Tracker: error fetching result of %s: %v", topup.ShortID, err)
+		return swaps.SwapResult{}
+	}
+	if result.OutputAmount == "" && result.DestTxHash == "" {
+		return result
+	}
+	if err := t.store.SetTopupResult(ctx, db.SetTopupResultParams{
+		ActualOutput: result.OutputAmount,
+		DestTxHash:   result.DestTxHash,
+		ID:           topup.ID,
+	}); err != nil {
+		log.Printf("Tracker: error recording result of %s: %v", topup.ShortID, err)
+		return result
+	}
+	log.Printf("Tracker: topup %s delivered %q (dest tx %q)", topup.ShortID, result.OutputAmount, result.DestTxHash)
+	return result
+}
+
+// verifyDelivery reads the destination transaction of a completed topup,
+// with delivery_check on, and returns what it paid the destination, e.g.
+// "0.0123 BTC", or "" if that couldn't be checked. A transaction that paid
+// the destination nothing is reported to the admin.
+func (t *Tracker) verifyDelivery(ctx context.Context, topup db.ListPendingTopupsRow, destTx string) string {
+	if t.delivery == nil || destTx == "" {
+		return ""
+	}
+	quote, err := t.store.GetQuote(ctx, topup.QuoteID)
+	if err != nil {
+		log.Printf("Tracker: error loading quote of %s: %v", topup.ShortID, err)
+		return ""
+	}
+	d, err := t.delivery.Verify(ctx, quote.ToAsset, quote.Destination, destTx)
+	if errors.Is(err, delivery.ErrUnsupported) {
+		return ""
+	}
+	if err != nil {
+		log.Printf("Tracker: error verifying delivery of %s in %s: %v", topup.ShortID, destTx, err)
+		return ""
+	}
+	if d.Amount.Sign() == 0 {
+		log.Printf("Tracker: destination tx %s of %s paid %s nothing", destTx, topup.ShortID, quote.Destination)
+		t.notifyAdmin(fmt.Sprintf("Topup %s (%s) completed, but its destination tx %s paid %s no %s.",
+			topup.ShortID, topup.Provider, destTx, quote.Destination, d.Symbol))
+		return ""
+	}
+	log.Printf("Tracker: verified %s delivered %s to %s (confirmed %v)", topup.ShortID, d, quote.Destination, d.Confirmed)
+	if !d.Confirmed {
+		return d.String() + " (unconfirmed)"
+	}
+	return d.String()
+}
+
+// topupEvent describes a topup moving to status for webhooks.
+func topupEvent(topup db.ListPendingTopupsRow, status string) webhooks.Topup {
+	return webhooks.Topup{
+		ShortID:        topup.ShortID,
+		Status:         status,
+		PreviousStatus: topup.Status,
+		Provider:       topup.Provider,
+		FromChain:      topup.FromChain,
+		TxHash:         topup.TxHash,
+		ExternalID:     topup.ExternalID,
+		UserID:         topup.UserID,
+		ChatID:         topup.ChatID,
+	}
+}
+
+// gasRefillEvent describes a gas refill in status for webhooks.
+func gasRefillEvent(refill db.GasRefill, status string) webhooks.GasRefill {
+	return webhooks.GasRefill{
+		ID:            refill.ID,
+		Status:        status,
+		Chain:         refill.Chain,
+		OrderUID:      refill.OrderUid,
+		WalletAddress: refill.WalletAddress,
+		SellAmount:    refill.SellAmount,
+		BuyAmount:     refill.BuyAmount,
+		Attempt:       refill.Attempt,
+		OrderType:     refill.OrderType,
+		Kind:          refill.Kind,
+		UserID:        refill.UserID,
+		ChatID:        refill.ChatID,
+
+		ExecutedSellAmount: refill.ExecutedSellAmount,
+		ExecutedBuyAmount:  refill.ExecutedBuyAmount,
+		Surplus:            refill.Surplus,
+	}
+}
+
+// stallAfter returns how long a topup with provider may stay pending before
+// it counts as stalled.
+func (t *Tracker) stallAfter(provider string) time.Duration {
+	minutes, ok := t.cfg.Tracker.ProviderStallMinutes[provider]
+	if !ok {
+		minutes = t.cfg.Tracker.StallAfterMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// markStalled flags a topup that has been pending too long. Stalled topups
+// keep being polled and complete or fail as usual; the chat and admin are
+// told once, the admin with the provider's last status response.
+func (t *Tracker) markStalled(ctx context.Context, topup db.ListPendingTopupsRow, status, raw string, now time.Time) {
+	if err := t.store.UpdateTopupStatus(ctx, db.UpdateTopupStatusParams{
+		Status: "stalled",
+		ID:     topup.ID,
+	}); err != nil {
+		log.Printf("Tracker: error updating %s: %v", topup.ShortID, err)
+		return
+	}
+	age := now.Sub(topup.CreatedAt).Round(time.Minute)
+	log.Printf("Tracker: topup %s stalled (%s pending)", topup.ShortID, age)
+	t.recordStatus(ctx, topup, "stalled", raw)
+	t.notifyUser(topup, "stalled", "")
+	t.hooks.TopupStatus(topupEvent(topup, "stalled"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Topup %s (%s) stalled: still %s after %s.\n", topup.ShortID, topup.Provider, status, age)
+	fmt.Fprintf(&b, "Tx: %s\n", t.cfg.ExplorerTxURL(topup.FromChain, topup.TxHash))
+	if topup.ExternalID != "" {
+		fmt.Fprintf(&b, "External ID: %s\n", topup.ExternalID)
+	}
+	b.WriteString(t.lastProviderResponse(ctx, topup))
+	t.notifyAdmin(b.String())
+}
+
+// lastProviderResponse describes the most recent logged status response for
+// the topup, matched on its external ID or tx hash in the request URL.
+func (t *Tracker) lastProviderResponse(ctx context.Context, topup db.ListPendingTopupsRow) string {
+	ref := topup.ExternalID
+	if ref == "" {
+		ref = strings.TrimPrefix(topup.TxHash, "0x")
+	}
+	// Provider HTTP clients are logged under the base provider name
+	provider, _, _ := strings.Cut(topup.Provider, "-")
+	req, err := t.store.GetLatestAPIRequestFor(ctx, db.GetLatestAPIRequestForParams{
+		Provider: provider,
+		Ref:      ref,
+	})
+	if err != nil {
+		return "No logged provider response found."
+	}
+
+	body := req.ResponseBody.String
+	if req.Error.Valid {
+		body = req.Error.String
+	}
+	if len(body) > 500 {
+		body = body[:500] + "..."
+	}
+	return fmt.Sprintf("Last provider response (API log #%d, HTTP %d):\n%s", req.ID, req.ResponseStatus.Int64, body)
+}
+
+// checkFailed backs the topup off exponentially from the poll interval, up
+// to max_backoff_s, and alerts the admin once it reaches alert_after_errors.
+func (t *Tracker) checkFailed(topup db.ListPendingTopupsRow, now time.Time, err error) {
+	f, ok := t.failures[topup.ID]
+	if !ok {
+		f = &checkFailures{}
+		t.failures[topup.ID] = f
+	}
+	f.count++
+
+	backoff := time.Duration(t.cfg.Tracker.IntervalSeconds) * time.Second
+	maxBackoff := time.Duration(t.cfg.Tracker.MaxBackoffSeconds) * time.Second
+	for i := 1; i < f.count && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxBackoff)
+	f.retryAt = now.Add(backoff)
+
+	log.Printf("Tracker: error checking %s (%d in a row, retrying in %s): %v", topup.ShortID, f.count, backoff, err)
+
+	if f.count >= t.cfg.Tracker.AlertAfterErrors && !f.alerted {
+		f.alerted = true
+		t.notifyAdmin(fmt.Sprintf("Status checks for topup %s (%s) have failed %d times in a row. Last error: %v",
+			topup.ShortID, topup.Provider, f.count, err))
+	}
+}
+
+// checkSucceeded clears the topup's error count, telling the admin if they
+// were alerted about it.
+func (t *Tracker) checkSucceeded(topup db.ListPendingTopupsRow) {
+	f, ok := t.failures[topup.ID]
+	if !ok {
 		return
 	}
+	delete(t.failures, topup.ID)
+	if f.alerted {
+		t.notifyAdmin(fmt.Sprintf("Status checks for topup %s (%s) are working again after %d errors.", topup.ShortID, topup.Provider, f.count))
+	}
+}
+
+func (t *Tracker) pollGasRefills(ctx context.Context, now time.Time) {
+	if t.cowClient == nil || !t.due("cowswap", now) {
+		return
+	}
+	t.checkGasRefills(ctx, now)
+	t.resubmitGasRefills(ctx)
+}
 
+func (t *Tracker) checkGasRefills(ctx context.Context, now time.Time) {
 	pending, err := t.store.ListPendingGasRefills(ctx)
 	if err != nil {
 		log.Printf("Tracker: error listing pending gas refills: %v", err)
@@ -127,6 +601,7 @@ func (t *Tracker) pollGasRefills(ctx context.Context) {
 	}
 
 	log.Printf("Tracker: checking %d pending gas refill(s)", len(pending))
+	t.polled("cowswap", now)
 
 	for _, refill := range pending {
 		select {
@@ -135,10 +610,30 @@ func (t *Tracker) pollGasRefills(ctx context.Context) {
 		default:
 		}
 
-		status, err := t.cowClient.CheckOrderStatus(refill.Chain, refill.OrderUid)
+		if !swaps.ChainEnabled(refill.Chain) {
+			continue
+		}
+
+		expired := refill.ValidTo.Valid && now.After(refill.ValidTo.Time)
+		if refill.OrderType == "limit" {
+			if !expired && now.Sub(t.limitChecked[refill.ID]) < limitOrderCheckInterval {
+				continue
+			}
+			t.limitChecked[refill.ID] = now
+		}
+
+		var status string
+		order, err := t.cowClient.GetOrder(refill.Chain, refill.OrderUid)
 		if err != nil {
 			log.Printf("Tracker: error checking gas refill %d: %v", refill.ID, err)
-			continue
+			// An order CoW has dropped long after it lapsed won't come back
+			if !expired || now.Sub(refill.ValidTo.Time) < limitOrderExpiryGrace {
+				continue
+			}
+			status = "expired"
+		} else {
+			status = order.Status
+			t.recordFill(ctx, &refill, order)
 		}
 
 		log.Printf("Tracker: gas refill %d (%s) status = %s", refill.ID, refill.Chain, status)
@@ -160,31 +655,209 @@ func (t *Tracker) pollGasRefills(ctx context.Context) {
 			log.Printf("Tracker: error updating gas refill %d: %v", refill.ID, err)
 			continue
 		}
+		delete(t.limitChecked, refill.ID)
 
 		t.notifyGasRefill(refill, newStatus)
+		t.hooks.GasRefillStatus(gasRefillEvent(refill, newStatus))
+	}
+}
+
+// recordFill stores how much of a gas refill's order has executed, if that
+// changed, and tells the chat about progress on a partially fillable order
+// that is still open.
+func (t *Tracker) recordFill(ctx context.Context, refill *db.GasRefill, order *cowswap.Order) {
+	if order.ExecutedSellAmount == "" || order.ExecutedBuyAmount == "" ||
+		(order.ExecutedSellAmount == refill.ExecutedSellAmount && order.ExecutedBuyAmount == refill.ExecutedBuyAmount) {
+		return
+	}
+	surplus := cowswap.Surplus(refill.Kind, refill.SellAmount, refill.BuyAmount, order.ExecutedSellAmount, order.ExecutedBuyAmount)
+	if err := t.store.UpdateGasRefillFill(ctx, db.UpdateGasRefillFillParams{
+		ExecutedSellAmount: order.ExecutedSellAmount,
+		ExecutedBuyAmount:  order.ExecutedBuyAmount,
+		Surplus:            surplus.String(),
+		ID:                 refill.ID,
+	}); err != nil {
+		log.Printf("Tracker: error recording fill of gas refill %d: %v", refill.ID, err)
+		return
+	}
+	before := t.filledPercent(*refill)
+	refill.ExecutedSellAmount, refill.ExecutedBuyAmount = order.ExecutedSellAmount, order.ExecutedBuyAmount
+	refill.Surplus = surplus.String()
+	after := t.filledPercent(*refill)
+	log.Printf("Tracker: gas refill %d is %d%% filled, surplus %s", refill.ID, after, refill.Surplus)
+
+	if refill.PartiallyFillable && order.Status == "open" && after > before {
+		t.send(refillChatID(*refill), fmt.Sprintf("Limit order #%d on %s is %d%% filled: %s so far.\n[View Order](https://explorer.cow.fi/orders/%s)",
+			refill.ID, nativeSymbol(refill.Chain), after, formatNative(refill.ExecutedBuyAmount, refill.Chain), refill.OrderUid))
 	}
 }
 
-func (t *Tracker) notifyUser(topup db.ListPendingTopupsRow, status string) {
+func (t *Tracker) filledPercent(refill db.GasRefill) int {
+	return cowswap.FilledPercent(refill.Kind, refill.SellAmount, refill.BuyAmount, refill.ExecutedSellAmount, refill.ExecutedBuyAmount)
+}
+
+// resubmitGasRefills places a new order for the latest gas refill of each
+// wallet and chain that didn't fill, once the cooldown has passed and while
+// attempts remain. Refills from before wallet indexes were recorded are left
+// for the next /balance.
+func (t *Tracker) resubmitGasRefills(ctx context.Context) {
+	if t.keyring == nil || wallet.WatchOnly(t.keyring) {
+		return
+	}
+
+	refills, err := t.store.ListRetryableGasRefills(ctx, maxGasRefillAttempts)
+	if err != nil {
+		log.Printf("Tracker: error listing retryable gas refills: %v", err)
+		return
+	}
+
+	for _, refill := range refills {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if !swaps.ChainEnabled(refill.Chain) || time.Since(refill.CreatedAt) < gasRefillRetryCooldown {
+			continue
+		}
+
+		signer, err := t.keyring.Signer(uint32(refill.WalletIndex))
+		if err != nil {
+			log.Printf("Tracker: error loading signer for gas refill %d: %v", refill.ID, err)
+			continue
+		}
+		if signer.Address().Hex() != refill.WalletAddress {
+			// The wallet moved pools since; its new address refills via /balance
+			continue
+		}
+
+		sellAmount, ok := new(big.Int).SetString(refill.SellAmount, 10)
+		if !ok {
+			continue
+		}
+		buyAmount, _ := new(big.Int).SetString(refill.BuyAmount, 10)
+		settings, enabled := t.cfg.GasRefill(refill.Chain)
+		if refill.Kind == "buy" {
+			// Buy the same amount, for up to the chain's refill_usd now
+			usdc, ok := thorchain.FundingTokens.Lookup(refill.Chain, "USDC")
+			if !ok || !enabled || buyAmount == nil || buyAmount.Sign() <= 0 {
+				continue
+			}
+			sellAmount = usdc.Amount(settings.RefillUSD)
+		}
+
+		chatID := refillChatID(refill)
+		signCtx := wallet.WithConfirmNotifier(ctx, func(what string) {
+			t.send(chatID, fmt.Sprintf("Confirm the %s on the hardware wallet to resubmit the gas refill on %s.", what, refill.Chain))
+		})
+
+		attempt := refill.Attempt + 1
+		params := db.InsertGasRefillParams{
+			Chain:         refill.Chain,
+			WalletAddress: refill.WalletAddress,
+			SellAmount:    refill.SellAmount,
+			BuyAmount:     "0",
+			Kind:          refill.Kind,
+			Status:        "failed",
+			UserID:        refill.UserID,
+			ChatID:        refill.ChatID,
+			WalletIndex:   refill.WalletIndex,
+			Attempt:       attempt,
+			OrderType:     "market",
+		}
+
+		// The chain's current guards, as for a new refill
+		guards := cowswap.Guards{SlippageBps: settings.SlippageBps, MaxFeeBps: settings.MaxFeeBps, MaxDeviationBps: settings.MaxDeviationBps}
+		var result *cowswap.OrderResult
+		if refill.Kind == "buy" {
+			// A failed attempt keeps the amount for the next one
+			params.BuyAmount = refill.BuyAmount
+			result, err = t.cowClient.BuyWithUSDC(signCtx, refill.Chain, signer, cowswap.NativeToken, buyAmount, sellAmount, signer.Address(), guards)
+		} else {
+			result, err = t.cowClient.SellUSDC(signCtx, refill.Chain, signer, sellAmount, cowswap.NativeToken, signer.Address(), guards)
+		}
+		if err := t.store.SetGasRefillCooldown(ctx, db.SetGasRefillCooldownParams{Chain: refill.Chain, WalletAddress: refill.WalletAddress}); err != nil {
+			log.Printf("Tracker: error recording gas refill cooldown of %d: %v", refill.ID, err)
+		}
+		if err != nil {
+			// Recorded as a failed attempt so it counts towards the limit
+			log.Printf("Tracker: error resubmitting gas refill %d (attempt %d): %v", refill.ID, attempt, err)
+		} else {
+			params.OrderUid = result.OrderUID
+			params.BuyAmount = result.BuyAmount
+			params.Status = "open"
+			if refill.Kind == "buy" {
+				params.SellAmount = result.SellAmount
+			}
+			params.ValidTo = sql.NullTime{Time: result.ValidTo, Valid: true}
+		}
+
+		id, err := t.store.InsertGasRefill(ctx, params)
+		if err != nil {
+			log.Printf("Tracker: error recording resubmitted gas refill %d: %v", refill.ID, err)
+			continue
+		}
+		t.hooks.GasRefillStatus(gasRefillEvent(db.GasRefill{
+			ID:            id,
+			Chain:         params.Chain,
+			OrderUid:      params.OrderUid,
+			WalletAddress: params.WalletAddress,
+			SellAmount:    params.SellAmount,
+			BuyAmount:     params.BuyAmount,
+			UserID:        params.UserID,
+			ChatID:        params.ChatID,
+			Attempt:       params.Attempt,
+			OrderType:     params.OrderType,
+			Kind:          params.Kind,
+		}, params.Status))
+		if result != nil {
+			log.Printf("Tracker: gas refill %d resubmitted as order %s (attempt %d)", refill.ID, result.OrderUID, attempt)
+			t.send(chatID, fmt.Sprintf("Gas refill on %s resubmitted (attempt %d of %d).\n[View Order](https://explorer.cow.fi/orders/%s)",
+				nativeSymbol(refill.Chain), attempt, maxGasRefillAttempts, result.OrderUID))
+		}
+	}
+}
+
+// notifyUser tells the topup's chat it reached status. verified is what a
+// completed topup was seen to deliver, if anything.
+func (t *Tracker) notifyUser(topup db.ListPendingTopupsRow, status, verified string) {
 	explorerURL := t.cfg.ExplorerTxURL(topup.FromChain, topup.TxHash)
 	var text string
 	switch status {
 	case "completed":
 		text = fmt.Sprintf("*Topup %s Complete*\nYour swap has been completed successfully.\nTx: `%s`\n[View on Explorer](%s)",
 			topup.ShortID, topup.TxHash, explorerURL)
+		if verified != "" {
+			text += fmt.Sprintf("\nVerified on the destination chain: %s received.", verified)
+		}
 	case "failed":
 		text = fmt.Sprintf("*Topup %s Failed*\nYour swap has failed. Funds may be refunded automatically.\nTx: `%s`\n[View on Explorer](%s)",
 			topup.ShortID, topup.TxHash, explorerURL)
+	case "stalled":
+		text = fmt.Sprintf("*Topup %s Delayed*\nYour swap is taking longer than expected. The admin has been notified and it is still being tracked; you'll get another message when it completes.\nTx: `%s`\n[View on Explorer](%s)",
+			topup.ShortID, topup.TxHash, explorerURL)
 	default:
 		return
 	}
 
-	// Notify the chat where the topup was initiated; fall back to user DM for legacy topups.
-	chatID := topup.ChatID
-	if chatID == 0 {
-		chatID = topup.UserID
+	chatID := topupChatID(topup)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	if _, err := t.botAPI.Send(msg); err != nil {
+		log.Printf("Tracker: error notifying chat %d: %v", chatID, err)
+	}
+}
+
+func (t *Tracker) notifyRefund(topup db.ListPendingTopupsRow, refundTx string) {
+	text := fmt.Sprintf("*Topup %s Refunded*\nThe provider couldn't complete your swap and sent the funds back to the wallet.\nTx: `%s`\n[View on Explorer](%s)",
+		topup.ShortID, topup.TxHash, t.cfg.ExplorerTxURL(topup.FromChain, topup.TxHash))
+	if refundTx != "" {
+		text += fmt.Sprintf("\nRefund: `%s`\n[View Refund](%s)", refundTx, t.cfg.ExplorerTxURL(topup.FromChain, refundTx))
 	}
 
+	chatID := topupChatID(topup)
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
 	msg.DisableWebPagePreview = true
@@ -194,29 +867,63 @@ func (t *Tracker) notifyUser(topup db.ListPendingTopupsRow, status string) {
 }
 
 func (t *Tracker) notifyGasRefill(refill db.GasRefill, status string) {
-	symbol := strings.ToUpper(refill.Chain)
-	if refill.Chain == "avalanche" {
-		symbol = "AVAX"
-	} else if refill.Chain == "base" {
-		symbol = "ETH"
-	}
-
+	symbol := nativeSymbol(refill.Chain)
 	explorerURL := fmt.Sprintf("https://explorer.cow.fi/orders/%s", refill.OrderUid)
 
+	retry := "It will be retried next time you check /balance."
+	if refill.WalletIndex >= 0 && refill.Attempt < maxGasRefillAttempts {
+		retry = "It will be resubmitted automatically."
+	}
+
 	var text string
+	if refill.OrderType == "limit" {
+		filled := t.filledPercent(refill)
+		switch {
+		case status == "fulfilled":
+			text = fmt.Sprintf("Limit order #%d on %s filled. USDC → %s swap completed.\n[View Order](%s)", refill.ID, symbol, symbol, explorerURL)
+		case filled > 0:
+			text = fmt.Sprintf("Limit order #%d on %s %s after filling %d%% (%s).\n[View Order](%s)",
+				refill.ID, symbol, status, filled, formatNative(refill.ExecutedBuyAmount, refill.Chain), explorerURL)
+		case status == "expired":
+			text = fmt.Sprintf("Limit order #%d on %s expired without reaching its price.\n[View Order](%s)", refill.ID, symbol, explorerURL)
+		case status == "cancelled":
+			text = fmt.Sprintf("Limit order #%d on %s was cancelled.\n[View Order](%s)", refill.ID, symbol, explorerURL)
+		}
+		t.send(refillChatID(refill), text)
+		return
+	}
 	switch status {
 	case "fulfilled":
 		text = fmt.Sprintf("Gas refill on %s completed. USDC → %s swap filled.\n[View Order](%s)", symbol, symbol, explorerURL)
 	case "expired":
-		text = fmt.Sprintf("Gas refill order on %s expired unfilled. It will be retried next time you check /balance.\n[View Order](%s)", symbol, explorerURL)
+		text = fmt.Sprintf("Gas refill order on %s expired unfilled. %s\n[View Order](%s)", symbol, retry, explorerURL)
 	case "cancelled":
-		text = fmt.Sprintf("Gas refill order on %s was cancelled. It will be retried next time you check /balance.\n[View Order](%s)", symbol, explorerURL)
+		text = fmt.Sprintf("Gas refill order on %s was cancelled. %s\n[View Order](%s)", symbol, retry, explorerURL)
 	}
 
-	chatID := refill.ChatID
-	if chatID == 0 {
-		chatID = refill.UserID
+	t.send(refillChatID(refill), text)
+}
+
+// topupChatID returns the chat where the topup was initiated, falling back to
+// the user's DM for legacy topups.
+func topupChatID(topup db.ListPendingTopupsRow) int64 {
+	if topup.ChatID != 0 {
+		return topup.ChatID
 	}
+	return topup.UserID
+}
+
+// refillChatID returns the chat to notify about a gas refill, or 0 if there
+// is no one to notify.
+func refillChatID(refill db.GasRefill) int64 {
+	if refill.ChatID != 0 {
+		return refill.ChatID
+	}
+	return refill.UserID
+}
+
+// send posts a Markdown message to chatID, doing nothing for chat 0.
+func (t *Tracker) send(chatID int64, text string) {
 	if chatID == 0 {
 		return // no one to notify
 	}
@@ -228,3 +935,29 @@ func (t *Tracker) notifyGasRefill(refill db.GasRefill, status string) {
 		log.Printf("Tracker: error notifying gas refill to %d: %v", chatID, err)
 	}
 }
+
+func nativeSymbol(chain string) string {
+	if cc, ok := cowswap.SupportedChains[chain]; ok {
+		return cc.NativeSymbol
+	}
+	return strings.ToUpper(chain)
+}
+
+// formatNative formats an amount of a chain's native coin in wei to 6
+// decimals.
+func formatNative(wei string, chain string) string {
+	val, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		val = new(big.Int)
+	}
+	whole := new(big.Int).Div(val, big.NewInt(1e18))
+	frac := new(big.Int).Mod(val, big.NewInt(1e18))
+	return fmt.Sprintf("%s.%s %s", whole, fmt.Sprintf("%018s", frac.String())[:6], nativeSymbol(chain))
+}
+
+func (t *Tracker) notifyAdmin(text string) {
+	msg := tgbotapi.NewMessage(t.cfg.AdminUserID, text)
+	if _, err := t.botAPI.Send(msg); err != nil {
+		log.Printf("Tracker: error notifying admin: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const quoterABI = `[{"inputs":[{"components":[{"name":"tokenIn","type":"address"},{"name":"tokenOut","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"fee","type":"uint24"},{"name":"sqrtPriceLimitX96","type":"uint160"}],"name":"params","type":"tuple"}],"name":"quoteExactInputSingle","outputs":[{"name":"amountOut","type":"uint256"},{"name":"sqrtPriceX96After","type":"uint160"},{"name":"initializedTicksCrossed","type":"uint32"},{"name":"gasEstimate","type":"uint256"}],"stateMutability":"nonpayable","type":"function"}]`
+
+const routerABI = `[{"inputs":[{"components":[{"name":"tokenIn","type":"address"},{"name":"tokenOut","type":"address"},{"name":"fee","type":"uint24"},{"name":"recipient","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"amountOutMinimum","type":"uint256"},{"name":"sqrtPriceLimitX96","type":"uint160"}],"name":"params","type":"tuple"}],"name":"exactInputSingle","outputs":[{"name":"amountOut","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"amountMinimum","type":"uint256"},{"name":"recipient","type":"address"}],"name":"unwrapWETH9","outputs":[],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"deadline","type":"uint256"},{"name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"name":"","type":"bytes[]"}],"stateMutability":"payable","type":"function"}]`
+
+const erc20DecimalsABI = `[{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"}]`
+
+// addressThis is SwapRouter02's sentinel recipient meaning "the router itself",
+// used to hold WETH before unwrapping it to the real recipient.
+var addressThis = common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+type quoteParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	AmountIn          *big.Int
+	Fee               *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+type exactInputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	Fee               *big.Int
+	Recipient         common.Address
+	AmountIn          *big.Int
+	AmountOutMinimum  *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+var (
+	parsedQuoterABI   abi.ABI
+	parsedRouterABI   abi.ABI
+	parsedDecimalsABI abi.ABI
+)
+
+func init() {
+	var err error
+	parsedQuoterABI, err = abi.JSON(strings.NewReader(quoterABI))
+	if err != nil {
+		panic(err)
+	}
+	parsedRouterABI, err = abi.JSON(strings.NewReader(routerABI))
+	if err != nil {
+		panic(err)
+	}
+	parsedDecimalsABI, err = abi.JSON(strings.NewReader(erc20DecimalsABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Client quotes Uniswap v3 pools directly on-chain via QuoterV2.
+type Client struct {
+	rpc *ethclient.Client
+}
+
+func NewClient(rpc *ethclient.Client) *Client {
+	return &Client{rpc: rpc}
+}
+
+// QuoteExactInputSingle simulates a single-pool swap and returns the output amount.
+// Returns an error if the pool for the fee tier doesn't exist or lacks liquidity.
+func (c *Client) QuoteExactInputSingle(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, fee uint32) (*big.Int, error) {
+	data, err := parsedQuoterABI.Pack("quoteExactInputSingle", quoteParams{
+		TokenIn:           tokenIn,
+		TokenOut:          tokenOut,
+		AmountIn:          amountIn,
+		Fee:               big.NewInt(int64(fee)),
+		SqrtPriceLimitX96: big.NewInt(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.rpc.CallContract(ctx, ethereum.CallMsg{To: &QuoterV2, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := parsedQuoterABI.Unpack("quoteExactInputSingle", output)
+	if err != nil {
+		return nil, fmt.Errorf("unpacking quote: %w", err)
+	}
+	return results[0].(*big.Int), nil
+}
+
+// BestQuote tries every fee tier and returns the highest output and its fee.
+func (c *Client) BestQuote(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int) (*big.Int, uint32, error) {
+	var best *big.Int
+	var bestFee uint32
+	for _, fee := range feeTiers {
+		out, err := c.QuoteExactInputSingle(ctx, tokenIn, tokenOut, amountIn, fee)
+		if err != nil {
+			continue
+		}
+		if best == nil || out.Cmp(best) > 0 {
+			best = out
+			bestFee = fee
+		}
+	}
+	if best == nil || best.Sign() == 0 {
+		return nil, 0, fmt.Errorf("no uniswap v3 pool with liquidity for %s → %s", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, bestFee, nil
+}
+
+// SwapCalldata builds SwapRouter02 calldata for an exact-input single-pool swap.
+// If unwrap is true, the WETH output is unwrapped to native ETH for the recipient
+// via multicall(exactInputSingle → router, unwrapWETH9 → recipient).
+func (c *Client) SwapCalldata(tokenIn, tokenOut common.Address, fee uint32, recipient common.Address, amountIn, minOut *big.Int, unwrap bool, deadline int64) ([]byte, error) {
+	swapRecipient := recipient
+	if unwrap {
+		swapRecipient = addressThis
+	}
+
+	swapData, err := parsedRouterABI.Pack("exactInputSingle", exactInputSingleParams{
+		TokenIn:           tokenIn,
+		TokenOut:          tokenOut,
+		Fee:               big.NewInt(int64(fee)),
+		Recipient:         swapRecipient,
+		AmountIn:          amountIn,
+		AmountOutMinimum:  minOut,
+		SqrtPriceLimitX96: big.NewInt(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("packing exactInputSingle: %w", err)
+	}
+
+	calls := [][]byte{swapData}
+	if unwrap {
+		unwrapData, err := parsedRouterABI.Pack("unwrapWETH9", minOut, recipient)
+		if err != nil {
+			return nil, fmt.Errorf("packing unwrapWETH9: %w", err)
+		}
+		calls = append(calls, unwrapData)
+	}
+
+	return parsedRouterABI.Pack("multicall", big.NewInt(deadline), calls)
+}
+
+// TokenDecimals reads an ERC20's decimals.
+func (c *Client) TokenDecimals(ctx context.Context, token common.Address) (int, error) {
+	data, err := parsedDecimalsABI.Pack("decimals")
+	if err != nil {
+		return 0, err
+	}
+
+	output, err := c.rpc.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := parsedDecimalsABI.Unpack("decimals", output)
+	if err != nil {
+		return 0, err
+	}
+	return int(results[0].(uint8)), nil
+}
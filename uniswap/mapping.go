@@ -0,0 +1,43 @@
+package uniswap
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// Uniswap v3 deployment addresses on Base
+var (
+	QuoterV2     = common.HexToAddress("0x3d4e44Eb1374240CE5F1B871ab261CD16335B76a")
+	SwapRouter02 = common.HexToAddress("0x2626664c2603336E57B271c5C0b26F421741e481")
+	WETH         = common.HexToAddress("0x4200000000000000000000000000000000000006")
+)
+
+// feeTiers are the pool fees (in hundredths of a bip) tried when quoting.
+var feeTiers = []uint32{100, 500, 3000, 10000}
+
+// sourceChain is the only RPC chain this provider swaps on.
+const sourceChain = "base"
+
+// AssetToTokenOut returns the token to buy for a target asset and whether the output
+// should be unwrapped to native ETH. Only Base assets are supported: native ETH
+// (bought as WETH and unwrapped) or ERC20s with a contract address, either on the
+// asset itself or from the resolver.
+func AssetToTokenOut(asset swaps.Asset) (token common.Address, unwrap bool, ok bool) {
+	if asset.Chain != "BASE" {
+		return common.Address{}, false, false
+	}
+	if asset.ContractAddress != "" {
+		if !common.IsHexAddress(asset.ContractAddress) {
+			return common.Address{}, false, false
+		}
+		return common.HexToAddress(asset.ContractAddress), false, true
+	}
+	if asset.Hints != nil && common.IsHexAddress(asset.Hints.UniswapToken) {
+		return common.HexToAddress(asset.Hints.UniswapToken), false, true
+	}
+	if asset.Symbol == "ETH" {
+		return WETH, true, true
+	}
+	return common.Address{}, false, false
+}
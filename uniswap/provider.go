@@ -0,0 +1,310 @@
+package uniswap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+// slippageBps is the maximum accepted slippage between quote and execution.
+const slippageBps = 100
+
+// Provider swaps funding stablecoins on Base through Uniswap v3 with no off-chain API dependency.
+type Provider struct {
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(rpcClients map[string]*ethclient.Client) *Provider {
+	return &Provider{
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "uniswap"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, _, ok := AssetToTokenOut(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	tokenOut, unwrap, ok := AssetToTokenOut(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("uniswap: unsupported target asset %s", toAsset)
+	}
+	if !common.IsHexAddress(destination) {
+		return nil, fmt.Errorf("uniswap: destination %q is not an EVM address", destination)
+	}
+
+	rpc, ok := p.rpcClients[sourceChain]
+	if !ok {
+		return nil, fmt.Errorf("uniswap: no RPC client for %s", sourceChain)
+	}
+
+	token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, sourceChain, sender, usdAmount, nil)
+	if err != nil {
+		return nil, fmt.Errorf("uniswap: %w", err)
+	}
+
+	client := NewClient(rpc)
+
+	amountOut, fee, err := client.BestQuote(ctx, token.Address, tokenOut, inputAmount)
+	if err != nil {
+		return nil, fmt.Errorf("uniswap: %w", err)
+	}
+
+	decimals := 18
+	if !unwrap {
+		decimals, err = client.TokenDecimals(ctx, tokenOut)
+		if err != nil {
+			return nil, fmt.Errorf("uniswap: reading decimals of %s: %w", tokenOut.Hex(), err)
+		}
+	}
+
+	expectedOut := formatUnits(amountOut, decimals)
+
+	minOut := new(big.Int).Mul(amountOut, big.NewInt(10000-slippageBps))
+	minOut.Div(minOut, big.NewInt(10000))
+
+	return []swaps.Quote{{
+		Provider:          "uniswap",
+		FromAsset:         token.Asset(sourceChain),
+		ToAsset:           toAsset,
+		FromChain:         sourceChain,
+		InputAmountUSD:    usdAmount,
+		InputAmount:       inputAmount,
+		ExpectedOutput:    expectedOut,
+		ExpectedOutputRaw: parseToBigInt(expectedOut),
+		Router:            SwapRouter02.Hex(),
+		ExtraData: map[string]interface{}{
+			"uniswap_token_out":   tokenOut.Hex(),
+			"uniswap_fee":         fee,
+			"uniswap_min_out":     minOut.String(),
+			"uniswap_unwrap":      unwrap,
+			"uniswap_destination": destination,
+		},
+	}}, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	tokenOutStr, _ := quote.ExtraData["uniswap_token_out"].(string)
+	fee, okFee := quote.ExtraData["uniswap_fee"].(uint32)
+	minOutStr, _ := quote.ExtraData["uniswap_min_out"].(string)
+	unwrap, _ := quote.ExtraData["uniswap_unwrap"].(bool)
+	destination, _ := quote.ExtraData["uniswap_destination"].(string)
+
+	minOut, okMin := new(big.Int).SetString(minOutStr, 10)
+	if !common.IsHexAddress(tokenOutStr) || !okFee || !okMin || !common.IsHexAddress(destination) {
+		return swaps.ExecuteResult{}, fmt.Errorf("uniswap: incomplete quote ExtraData")
+	}
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+	client := NewClient(rpc)
+
+	deadline := time.Now().Add(20 * time.Minute).Unix()
+	data, err := client.SwapCalldata(token.Address, common.HexToAddress(tokenOutStr), fee, common.HexToAddress(destination), quote.InputAmount, minOut, unwrap, deadline)
+	if err != nil {
+		return swaps.ExecuteResult{}, err
+	}
+
+	// Step 1: Approve SwapRouter02 to spend the funding token
+	if err := approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, SwapRouter02, quote.InputAmount); err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", token.Symbol, err)
+	}
+
+	// Step 2: Swap via multicall
+	txHash, err := sendSwapTx(ctx, rpc, chainID, signer, fromAddr, data)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("swap tx: %w", err)
+	}
+
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: quote.FromChain,
+	}, nil
+}
+
+// CheckStatus reads the swap receipt directly; externalID is the RPC chain key.
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	chain := externalID
+	if chain == "" {
+		chain = sourceChain
+	}
+	rpc, ok := p.rpcClients[chain]
+	if !ok {
+		return "", fmt.Errorf("uniswap: no RPC client for chain %q", chain)
+	}
+
+	receipt, err := rpc.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return "pending", nil
+		}
+		return "", err
+	}
+
+	swaps.ReportRawStatus(ctx, fmt.Sprintf("receipt status %d", receipt.Status))
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return "completed", nil
+	}
+	return "failed", nil
+}
+
+func approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
+	parsed, err := abi.JSON(strings.NewReader(thorchain.ERC20ApproveABI))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("signing approve tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("sending approve tx: %w", err)
+	}
+
+	log.Printf("Uniswap approve tx sent: %s", signedTx.Hash().Hex())
+
+	// Wait for approval to be mined with 2-minute timeout (swap depends on this)
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return fmt.Errorf("waiting for approve: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx failed")
+	}
+
+	return nil
+}
+
+func sendSwapTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, data []byte) (string, error) {
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	// Single-pool swap plus optional unwrap comfortably fits in 300k gas
+	tx := types.NewTransaction(nonce, SwapRouter02, big.NewInt(0), 300000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing swap tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending swap tx: %w", err)
+	}
+
+	log.Printf("Uniswap swap tx sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// formatUnits renders an integer amount with the given number of decimals.
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+	s := amount.String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	return s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}
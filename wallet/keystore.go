@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Keystore is an encrypted mnemonic file. The crypto section uses the same
+// scrypt + AES-128-CTR scheme as Ethereum V3 keystores.
+type Keystore struct {
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+const keystoreVersion = 1
+
+// EncryptMnemonic encrypts a mnemonic with the given passphrase.
+func EncryptMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = strings.TrimSpace(mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3([]byte(mnemonic), []byte(passphrase), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting mnemonic: %w", err)
+	}
+
+	return json.MarshalIndent(Keystore{Version: keystoreVersion, Crypto: cryptoJSON}, "", "  ")
+}
+
+// DecryptMnemonic decrypts a keystore produced by EncryptMnemonic.
+func DecryptMnemonic(data []byte, passphrase string) (string, error) {
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return "", fmt.Errorf("parsing keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion {
+		return "", fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+
+	plain, err := keystore.DecryptDataV3(ks.Crypto, passphrase)
+	if err != nil {
+		// keystore.ErrDecrypt on a wrong passphrase
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// LoadMnemonic reads and decrypts a keystore file.
+func LoadMnemonic(path, passphrase string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading keystore: %w", err)
+	}
+	return DecryptMnemonic(data, passphrase)
+}
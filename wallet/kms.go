@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// KMSSigner signs with an AWS KMS key of spec ECC_SECG_P256K1. The key never
+// leaves KMS; the address is derived from its public key.
+type KMSSigner struct {
+	client *kms.Client
+	keyID  string
+	pubKey []byte // uncompressed, 65 bytes
+	addr   common.Address
+}
+
+// NewKMSSigner fetches the public key for keyID and returns a signer for it.
+func NewKMSSigner(ctx context.Context, client *kms.Client, keyID string) (*KMSSigner, error) {
+	out, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, fmt.Errorf("getting KMS public key: %w", err)
+	}
+	if out.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("KMS key %s has spec %s, want %s", keyID, out.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+
+	// SubjectPublicKeyInfo; x509 can't parse secp256k1 keys, so decode by hand
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(out.PublicKey, &spki); err != nil {
+		return nil, fmt.Errorf("parsing KMS public key: %w", err)
+	}
+	pub, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing KMS public key: %w", err)
+	}
+
+	return &KMSSigner{
+		client: client,
+		keyID:  keyID,
+		pubKey: spki.PublicKey.Bytes,
+		addr:   crypto.PubkeyToAddress(*pub),
+	}, nil
+}
+
+func (s *KMSSigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *KMSSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(chainID)
+	h := txSigner.Hash(tx)
+	sig, err := s.SignDigest(ctx, h[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+func (s *KMSSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return signTypedData(ctx, data, s.SignDigest)
+}
+
+func (s *KMSSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	out, err := s.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(s.keyID),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("KMS sign: %w", err)
+	}
+
+	var der struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(out.Signature, &der); err != nil {
+		return nil, fmt.Errorf("parsing KMS signature: %w", err)
+	}
+
+	// Ethereum only accepts low-s signatures
+	if der.S.Cmp(secp256k1HalfN) > 0 {
+		der.S = new(big.Int).Sub(secp256k1N, der.S)
+	}
+
+	sig := make([]byte, 65)
+	der.R.FillBytes(sig[:32])
+	der.S.FillBytes(sig[32:64])
+
+	// KMS doesn't return the recovery ID; find the one that yields our key
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.Ecrecover(digest, sig)
+		if err == nil && bytes.Equal(pub, s.pubKey) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("KMS signature does not recover to %s", s.addr.Hex())
+}
@@ -0,0 +1,182 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ErrConfirmTimeout is returned when nobody confirms a signature on the
+// hardware wallet in time.
+var ErrConfirmTimeout = errors.New("no confirmation on device before timeout")
+
+type confirmNotifierKey struct{}
+
+// WithConfirmNotifier returns a context that makes hardware signers call
+// notify before each signature that waits for a confirmation on the device.
+func WithConfirmNotifier(ctx context.Context, notify func(what string)) context.Context {
+	return context.WithValue(ctx, confirmNotifierKey{}, notify)
+}
+
+func notifyConfirm(ctx context.Context, what string) {
+	if notify, ok := ctx.Value(confirmNotifierKey{}).(func(string)); ok {
+		notify(what)
+	}
+}
+
+// LedgerKeyring signs with the first connected Ledger over USB HID, using the
+// same m/44'/60'/0'/0/{index} paths as the mnemonic. Every signature needs a
+// confirmation on the device.
+type LedgerKeyring struct {
+	hub     *usbwallet.Hub
+	timeout time.Duration
+
+	mu        sync.Mutex
+	wallet    accounts.Wallet
+	addresses map[uint32]accounts.Account
+}
+
+// NewLedgerKeyring starts watching for Ledger devices. timeout bounds how
+// long a signature waits for confirmation on the device.
+func NewLedgerKeyring(timeout time.Duration) (*LedgerKeyring, error) {
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, fmt.Errorf("starting Ledger hub: %w", err)
+	}
+	return &LedgerKeyring{hub: hub, timeout: timeout, addresses: make(map[uint32]accounts.Account)}, nil
+}
+
+// account opens the device if needed and derives the account for index.
+func (k *LedgerKeyring) account(index uint32) (accounts.Wallet, accounts.Account, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.wallet != nil {
+		if _, err := k.wallet.Status(); err != nil {
+			// Unplugged or the Ethereum app was closed; start over
+			k.wallet.Close()
+			k.wallet = nil
+			k.addresses = make(map[uint32]accounts.Account)
+		}
+	}
+	if k.wallet == nil {
+		wallets := k.hub.Wallets()
+		if len(wallets) == 0 {
+			return nil, accounts.Account{}, fmt.Errorf("no Ledger connected")
+		}
+		w := wallets[0]
+		if err := w.Open(""); err != nil && !errors.Is(err, accounts.ErrWalletAlreadyOpen) {
+			return nil, accounts.Account{}, fmt.Errorf("opening Ledger (is the Ethereum app open?): %w", err)
+		}
+		k.wallet = w
+	}
+
+	if acct, ok := k.addresses[index]; ok {
+		return k.wallet, acct, nil
+	}
+	path, err := accounts.ParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", index))
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+	acct, err := k.wallet.Derive(path, true)
+	if err != nil {
+		return nil, accounts.Account{}, fmt.Errorf("deriving Ledger account %d: %w", index, err)
+	}
+	k.addresses[index] = acct
+	return k.wallet, acct, nil
+}
+
+func (k *LedgerKeyring) Address(index uint32) (common.Address, error) {
+	_, acct, err := k.account(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return acct.Address, nil
+}
+
+func (k *LedgerKeyring) Signer(index uint32) (Signer, error) {
+	w, acct, err := k.account(index)
+	if err != nil {
+		return nil, err
+	}
+	return &LedgerSigner{wallet: w, account: acct, timeout: k.timeout}, nil
+}
+
+// LedgerSigner signs with one Ledger account.
+type LedgerSigner struct {
+	wallet  accounts.Wallet
+	account accounts.Account
+	timeout time.Duration
+}
+
+func (s *LedgerSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *LedgerSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	notifyConfirm(ctx, fmt.Sprintf("transaction (chain %s, nonce %d)", chainID, tx.Nonce()))
+	return awaitDevice(ctx, s.timeout, func() (*types.Transaction, error) {
+		return s.wallet.SignTx(s.account, tx, chainID)
+	})
+}
+
+func (s *LedgerSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	_, raw, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("hashing typed data: %w", err)
+	}
+
+	notifyConfirm(ctx, fmt.Sprintf("%s signature", data.PrimaryType))
+	sig, err := awaitDevice(ctx, s.timeout, func() ([]byte, error) {
+		return s.wallet.SignData(s.account, accounts.MimetypeTypedData, []byte(raw))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
+
+// SignDigest is not supported: the Ledger Ethereum app refuses blind hashes.
+func (s *LedgerSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return nil, fmt.Errorf("Ledger does not sign raw digests")
+}
+
+// awaitDevice runs a blocking device call, giving up after timeout. The
+// device request itself can't be cancelled; it stays on screen until
+// confirmed or rejected, and later requests queue behind it.
+func awaitDevice[T any](ctx context.Context, timeout time.Duration, sign func() (T, error)) (T, error) {
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		val, err := sign()
+		done <- result{val, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var zero T
+	select {
+	case r := <-done:
+		return r.val, r.err
+	case <-timer.C:
+		return zero, ErrConfirmTimeout
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
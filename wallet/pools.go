@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultPool is the wallet pool backed by the main wallet config (mnemonic,
+// keystore, remote signer, Ledger or xpub).
+const DefaultPool = "default"
+
+// PoolKeyring routes each wallet index to the keyring of the pool it is
+// assigned to, so operators can keep separate mnemonics for risk tiers. The
+// index itself is unchanged across pools; moving a wallet to another pool
+// derives the same index from a different mnemonic.
+type PoolKeyring struct {
+	pools  map[string]Keyring
+	poolOf func(index uint32) (string, error)
+}
+
+// NewPoolKeyring serves indexes from base (the default pool) and the named
+// pools. poolOf looks up the pool an index is currently assigned to.
+func NewPoolKeyring(base Keyring, pools map[string]Keyring, poolOf func(index uint32) (string, error)) *PoolKeyring {
+	all := map[string]Keyring{DefaultPool: base}
+	for name, k := range pools {
+		all[name] = k
+	}
+	return &PoolKeyring{pools: all, poolOf: poolOf}
+}
+
+func (k *PoolKeyring) keyring(index uint32) (Keyring, error) {
+	pool, err := k.poolOf(index)
+	if err != nil {
+		return nil, fmt.Errorf("looking up pool of index %d: %w", index, err)
+	}
+	kr, ok := k.pools[pool]
+	if !ok || kr == nil {
+		return nil, fmt.Errorf("index %d is in unknown wallet pool %q", index, pool)
+	}
+	return kr, nil
+}
+
+func (k *PoolKeyring) Address(index uint32) (common.Address, error) {
+	kr, err := k.keyring(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return kr.Address(index)
+}
+
+func (k *PoolKeyring) Signer(index uint32) (Signer, error) {
+	kr, err := k.keyring(index)
+	if err != nil {
+		return nil, err
+	}
+	return kr.Signer(index)
+}
+
+// Pool returns the keyring for a named pool.
+func (k *PoolKeyring) Pool(name string) (Keyring, bool) {
+	kr, ok := k.pools[name]
+	return kr, ok && kr != nil
+}
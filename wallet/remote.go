@@ -0,0 +1,146 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteKeyring signs through a JSON-RPC signer such as web3signer, clef or
+// an HSM gateway. The signer holds the keys; the keyring only maps wallet
+// indexes to the addresses it manages.
+type RemoteKeyring struct {
+	client    *rpc.Client
+	addresses []common.Address
+}
+
+// NewRemoteKeyring connects to the signer at url. addresses[i] is the
+// address used for wallet index i.
+func NewRemoteKeyring(url string, addresses []common.Address) (*RemoteKeyring, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to remote signer: %w", err)
+	}
+	return &RemoteKeyring{client: client, addresses: addresses}, nil
+}
+
+func (k *RemoteKeyring) Address(index uint32) (common.Address, error) {
+	if int(index) >= len(k.addresses) {
+		return common.Address{}, fmt.Errorf("remote signer has no address for index %d", index)
+	}
+	return k.addresses[index], nil
+}
+
+func (k *RemoteKeyring) Signer(index uint32) (Signer, error) {
+	addr, err := k.Address(index)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSigner{client: k.client, addr: addr}, nil
+}
+
+// RemoteSigner signs with one address held by a JSON-RPC signer.
+type RemoteSigner struct {
+	client *rpc.Client
+	addr   common.Address
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.addr
+}
+
+// signTxArgs is the eth_signTransaction request object.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    s.addr,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Data:    tx.Data(),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("eth_signTransaction: %w", err)
+	}
+
+	// web3signer returns the raw transaction; clef wraps it as {"raw": ...}
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var wrapped struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := json.Unmarshal(result, &wrapped); err != nil || len(wrapped.Raw) == 0 {
+			return nil, fmt.Errorf("unexpected eth_signTransaction result: %s", result)
+		}
+		raw = wrapped.Raw
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decoding signed tx: %w", err)
+	}
+
+	// Don't broadcast something other than what we asked for
+	txSigner := types.LatestSignerForChainID(chainID)
+	if txSigner.Hash(signed) != txSigner.Hash(tx) {
+		return nil, fmt.Errorf("remote signer returned a different transaction")
+	}
+	sender, err := types.Sender(txSigner, signed)
+	if err != nil {
+		return nil, fmt.Errorf("recovering signer: %w", err)
+	}
+	if sender != s.addr {
+		return nil, fmt.Errorf("remote signer signed with %s, want %s", sender.Hex(), s.addr.Hex())
+	}
+
+	return signed, nil
+}
+
+func (s *RemoteSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.client.CallContext(ctx, &sig, "eth_signTypedData", s.addr, data); err != nil {
+		return nil, fmt.Errorf("eth_signTypedData: %w", err)
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("unexpected signature length %d", len(sig))
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
+
+// SignDigest is not supported: remote signers only sign transactions and
+// structured messages, never arbitrary hashes.
+func (s *RemoteSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return nil, fmt.Errorf("remote signer does not sign raw digests")
+}
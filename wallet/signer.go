@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer signs on behalf of a single wallet address. Providers and the CoW
+// client only see a Signer, so the private key can live outside the process.
+type Signer interface {
+	// Address returns the address whose key signs.
+	Address() common.Address
+
+	// SignTx signs tx for the given chain.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignTypedData signs EIP-712 typed data and returns a 65 byte
+	// [R || S || V] signature with V = 27 or 28.
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+
+	// SignDigest signs a 32 byte hash and returns a 65 byte [R || S || V]
+	// signature with V = 0 or 1.
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Keyring hands out signers by wallet index.
+type Keyring interface {
+	// Address returns the address for index without needing its key.
+	Address(index uint32) (common.Address, error)
+
+	Signer(index uint32) (Signer, error)
+}
+
+// LocalSigner signs with an in-memory private key.
+type LocalSigner struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+}
+
+func NewLocalSigner(key *ecdsa.PrivateKey) *LocalSigner {
+	return &LocalSigner{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *LocalSigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *LocalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *LocalSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return signTypedData(ctx, data, s.SignDigest)
+}
+
+func (s *LocalSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, s.key)
+}
+
+// signTypedData hashes EIP-712 data and signs the digest with signDigest.
+func signTypedData(ctx context.Context, data apitypes.TypedData, signDigest func(context.Context, []byte) ([]byte, error)) ([]byte, error) {
+	digest, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("hashing typed data: %w", err)
+	}
+
+	sig, err := signDigest(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ethereum signature convention: v = 27 or 28
+	sig[64] += 27
+	return sig, nil
+}
+
+// MnemonicKeyring derives local signers from a BIP39 mnemonic.
+type MnemonicKeyring struct {
+	mnemonic string
+}
+
+func NewMnemonicKeyring(mnemonic string) *MnemonicKeyring {
+	return &MnemonicKeyring{mnemonic: mnemonic}
+}
+
+func (k *MnemonicKeyring) Address(index uint32) (common.Address, error) {
+	return DeriveAddress(k.mnemonic, index)
+}
+
+func (k *MnemonicKeyring) Signer(index uint32) (Signer, error) {
+	key, err := DeriveKey(k.mnemonic, index)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalSigner(key), nil
+}
+
+// OverrideKeyring serves fixed signers for some wallet indexes, such as KMS
+// keys, and defers to a base keyring for the rest. base may be nil when every
+// index in use has an override.
+type OverrideKeyring struct {
+	base    Keyring
+	signers map[uint32]Signer
+}
+
+func NewOverrideKeyring(base Keyring, signers map[uint32]Signer) *OverrideKeyring {
+	return &OverrideKeyring{base: base, signers: signers}
+}
+
+func (k *OverrideKeyring) Address(index uint32) (common.Address, error) {
+	if s, ok := k.signers[index]; ok {
+		return s.Address(), nil
+	}
+	if k.base == nil {
+		return common.Address{}, fmt.Errorf("no signer configured for index %d", index)
+	}
+	return k.base.Address(index)
+}
+
+func (k *OverrideKeyring) Signer(index uint32) (Signer, error) {
+	if s, ok := k.signers[index]; ok {
+		return s, nil
+	}
+	if k.base == nil {
+		return nil, fmt.Errorf("no signer configured for index %d", index)
+	}
+	return k.base.Signer(index)
+}
@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// UnsignedTxError is returned by watch-only signers in place of a signature.
+// It carries the transaction that would have been signed so callers can hand
+// it to the operator to sign elsewhere.
+type UnsignedTxError struct {
+	From    common.Address
+	Tx      *types.Transaction
+	ChainID *big.Int
+}
+
+func (e *UnsignedTxError) Error() string {
+	return fmt.Sprintf("watch-only wallet: transaction from %s on chain %s needs signing elsewhere", e.From.Hex(), e.ChainID)
+}
+
+// WatchKeyring derives addresses from an account-level extended public key
+// (the xpub of m/44'/60'/0'), so no private material lives on the server.
+type WatchKeyring struct {
+	change *bip32.Key // m/44'/60'/0'/0
+}
+
+// NewWatchKeyring parses an xpub for m/44'/60'/0'.
+func NewWatchKeyring(xpub string) (*WatchKeyring, error) {
+	account, err := bip32.B58Deserialize(xpub)
+	if err != nil {
+		return nil, fmt.Errorf("parsing xpub: %w", err)
+	}
+	if account.IsPrivate {
+		return nil, fmt.Errorf("expected an xpub, got a private extended key")
+	}
+	change, err := account.NewChildKey(0)
+	if err != nil {
+		return nil, fmt.Errorf("deriving change: %w", err)
+	}
+	return &WatchKeyring{change: change}, nil
+}
+
+func (k *WatchKeyring) Address(index uint32) (common.Address, error) {
+	child, err := k.change.NewChildKey(index)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("deriving child %d: %w", index, err)
+	}
+	pub, err := crypto.DecompressPubkey(child.Key)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("decompressing public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func (k *WatchKeyring) Signer(index uint32) (Signer, error) {
+	addr, err := k.Address(index)
+	if err != nil {
+		return nil, err
+	}
+	return &WatchSigner{addr: addr}, nil
+}
+
+// WatchSigner knows its address but cannot sign.
+type WatchSigner struct {
+	addr common.Address
+}
+
+func (s *WatchSigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *WatchSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, &UnsignedTxError{From: s.addr, Tx: tx, ChainID: chainID}
+}
+
+func (s *WatchSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return nil, fmt.Errorf("watch-only wallet cannot sign %s messages", data.PrimaryType)
+}
+
+func (s *WatchSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return nil, fmt.Errorf("watch-only wallet cannot sign")
+}
+
+// WatchOnly reports whether k can only derive addresses.
+func WatchOnly(k Keyring) bool {
+	_, ok := k.(*WatchKeyring)
+	return ok
+}
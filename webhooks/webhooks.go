@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/RaghavSood/fundbot/config"
+)
+
+// maxAttempts is how many times an event is posted to an endpoint before it
+// is dropped.
+const maxAttempts = 4
+
+// Event is the JSON body posted to webhook endpoints.
+type Event struct {
+	Type      string      `json:"type"` // e.g. "topup.completed", "gas_refill.expired"
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Topup is the data of a "topup.<status>" event.
+type Topup struct {
+	ShortID        string `json:"short_id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	Provider       string `json:"provider"`
+	FromChain      string `json:"from_chain"`
+	TxHash         string `json:"tx_hash"`
+	ExternalID     string `json:"external_id,omitempty"`
+	UserID         int64  `json:"user_id"`
+	ChatID         int64  `json:"chat_id"`
+	ActualOutput   string `json:"actual_output,omitempty"`
+	DestTxHash     string `json:"dest_tx_hash,omitempty"`
+	VerifiedOutput string `json:"verified_output,omitempty"`
+	RefundTxHash   string `json:"refund_tx_hash,omitempty"`
+}
+
+// GasRefill is the data of a "gas_refill.<status>" event.
+type GasRefill struct {
+	ID            int64  `json:"id"`
+	Status        string `json:"status"`
+	Chain         string `json:"chain"`
+	OrderUID      string `json:"order_uid,omitempty"`
+	WalletAddress string `json:"wallet_address"`
+	SellAmount    string `json:"sell_amount"`
+	BuyAmount     string `json:"buy_amount"`
+	Attempt       int64  `json:"attempt"`
+	OrderType     string `json:"order_type"`
+	Kind          string `json:"kind"`
+	UserID        int64  `json:"user_id"`
+	ChatID        int64  `json:"chat_id"`
+
+	// Running totals of what the order sold and bought, once known, and
+	// the surplus over its limit price
+	ExecutedSellAmount string `json:"executed_sell_amount,omitempty"`
+	ExecutedBuyAmount  string `json:"executed_buy_amount,omitempty"`
+	Surplus            string `json:"surplus,omitempty"`
+}
+
+// Client posts status events to the configured endpoints. A nil Client
+// drops every event, so callers don't need to check whether webhooks are
+// configured.
+type Client struct {
+	endpoints  []config.WebhookConfig
+	httpClient *http.Client
+}
+
+// New returns a client for the configured endpoints, or nil if there are none.
+func New(endpoints []config.WebhookConfig) *Client {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	return &Client{
+		endpoints:  endpoints,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// TopupStatus sends a "topup.<status>" event.
+func (c *Client) TopupStatus(t Topup) {
+	c.Send("topup."+t.Status, t)
+}
+
+// GasRefillStatus sends a "gas_refill.<status>" event.
+func (c *Client) GasRefillStatus(r GasRefill) {
+	c.Send("gas_refill."+r.Status, r)
+}
+
+// Send posts an event to every endpoint subscribed to eventType. Delivery
+// happens in the background with retries; failures are only logged.
+func (c *Client) Send(eventType string, data interface{}) {
+	if c == nil {
+		return
+	}
+
+	event := Event{Type: eventType, Timestamp: time.Now().Unix(), Data: data}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Webhooks: error encoding %s event: %v", eventType, err)
+		return
+	}
+
+	for _, endpoint := range c.endpoints {
+		if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, eventType) {
+			continue
+		}
+		go c.deliver(endpoint, eventType, event.Timestamp, body)
+	}
+}
+
+func (c *Client) deliver(endpoint config.WebhookConfig, eventType string, timestamp int64, body []byte) {
+	backoff := 2 * time.Second
+	for attempt := 1; ; attempt++ {
+		err := c.post(endpoint, timestamp, body)
+		if err == nil {
+			return
+		}
+		if attempt == maxAttempts {
+			log.Printf("Webhooks: giving up on %s event to %s after %d attempts: %v", eventType, endpoint.URL, attempt, err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (c *Client) post(endpoint config.WebhookConfig, timestamp int64, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(timestamp, 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-FundBot-Timestamp", ts)
+	req.Header.Set("X-FundBot-Signature", "sha256="+Sign(endpoint.Secret, ts, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret, as
+// sent in the X-FundBot-Signature header. Receivers should recompute it and
+// reject stale timestamps.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package zeroex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const baseURL = "https://api.0x.org"
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, httpClient *http.Client) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// Transaction is the swap transaction returned by the quote endpoint.
+type Transaction struct {
+	To       string `json:"to"`
+	Data     string `json:"data"`
+	Gas      string `json:"gas"`
+	GasPrice string `json:"gasPrice"`
+	Value    string `json:"value"`
+}
+
+// QuoteResponse is the response from GET /swap/allowance-holder/quote.
+type QuoteResponse struct {
+	LiquidityAvailable bool   `json:"liquidityAvailable"`
+	BuyAmount          string `json:"buyAmount"`
+	MinBuyAmount       string `json:"minBuyAmount"`
+	BuyToken           string `json:"buyToken"`
+	SellAmount         string `json:"sellAmount"`
+	SellToken          string `json:"sellToken"`
+	Issues             struct {
+		// Allowance is non-nil when the taker must approve Spender before swapping.
+		Allowance *struct {
+			Actual  string `json:"actual"`
+			Spender string `json:"spender"`
+		} `json:"allowance"`
+		Balance *struct {
+			Token    string `json:"token"`
+			Actual   string `json:"actual"`
+			Expected string `json:"expected"`
+		} `json:"balance"`
+	} `json:"issues"`
+	Transaction Transaction `json:"transaction"`
+}
+
+// GetQuote requests a firm same-chain swap quote via the AllowanceHolder flow.
+// sellAmount is in the sell token's smallest unit.
+func (c *Client) GetQuote(ctx context.Context, chainID int64, sellToken, buyToken, sellAmount, taker, recipient string) (*QuoteResponse, error) {
+	params := url.Values{}
+	params.Set("chainId", fmt.Sprintf("%d", chainID))
+	params.Set("sellToken", sellToken)
+	params.Set("buyToken", buyToken)
+	params.Set("sellAmount", sellAmount)
+	params.Set("taker", taker)
+	params.Set("recipient", recipient)
+	params.Set("slippageBps", "100")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/swap/allowance-holder/quote?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("0x-api-key", c.apiKey)
+	req.Header.Set("0x-version", "v2")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("0x quote: %s: %s", resp.Status, body)
+	}
+
+	var result QuoteResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing quote response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,39 @@
+package zeroex
+
+import (
+	"github.com/RaghavSood/fundbot/swaps"
+)
+
+// nativeToken is the address 0x uses for a chain's native gas token.
+const nativeToken = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+
+// chains maps our asset chain notation to the RPC chain key, EVM chain ID, and native symbol.
+// 0x only swaps within a chain, so the destination chain is also the source chain.
+var chains = map[string]struct {
+	RPCKey       string
+	ChainID      int64
+	NativeSymbol string
+}{
+	"BASE": {RPCKey: "base", ChainID: 8453, NativeSymbol: "ETH"},
+	"AVAX": {RPCKey: "avalanche", ChainID: 43114, NativeSymbol: "AVAX"},
+}
+
+// AssetToBuyToken returns the RPC chain key, chain ID, and 0x buy token for a target asset.
+// Natives map to the 0xEeee... sentinel; ERC20s need a contract address, either on the
+// asset itself or from the resolver.
+func AssetToBuyToken(asset swaps.Asset) (rpcKey string, chainID int64, token string, ok bool) {
+	c, ok := chains[asset.Chain]
+	if !ok {
+		return "", 0, "", false
+	}
+	if asset.ContractAddress != "" {
+		return c.RPCKey, c.ChainID, asset.ContractAddress, true
+	}
+	if asset.Hints != nil && asset.Hints.ZeroExToken != "" {
+		return c.RPCKey, c.ChainID, asset.Hints.ZeroExToken, true
+	}
+	if asset.Symbol == c.NativeSymbol {
+		return c.RPCKey, c.ChainID, nativeToken, true
+	}
+	return "", 0, "", false
+}
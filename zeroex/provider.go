@@ -0,0 +1,350 @@
+package zeroex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/RaghavSood/fundbot/swaps"
+	"github.com/RaghavSood/fundbot/thorchain"
+	"github.com/RaghavSood/fundbot/wallet"
+)
+
+// chainIDs for EVM chains
+var chainIDs = map[string]*big.Int{
+	"arbitrum":  big.NewInt(42161),
+	"avalanche": big.NewInt(43114),
+	"base":      big.NewInt(8453),
+	"bsc":       big.NewInt(56),
+	"ethereum":  big.NewInt(1),
+	"optimism":  big.NewInt(10),
+	"polygon":   big.NewInt(137),
+}
+
+const erc20DecimalsABI = `[{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"}]`
+
+type Provider struct {
+	client     *Client
+	rpcClients map[string]*ethclient.Client
+}
+
+func NewProvider(apiKey string, rpcClients map[string]*ethclient.Client, httpClient *http.Client) *Provider {
+	return &Provider{
+		client:     NewClient(apiKey, httpClient),
+		rpcClients: rpcClients,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "zeroex"
+}
+
+func (p *Provider) Category() string {
+	return "dex"
+}
+
+// Endpoint returns the API base URL, for health checks.
+func (p *Provider) Endpoint() string {
+	return baseURL
+}
+
+func (p *Provider) SupportsAsset(asset swaps.Asset) bool {
+	_, _, _, ok := AssetToBuyToken(asset)
+	return ok
+}
+
+func (p *Provider) Quote(ctx context.Context, toAsset swaps.Asset, usdAmount float64, destination string, sender common.Address) ([]swaps.Quote, error) {
+	chain, chainID, buyToken, ok := AssetToBuyToken(toAsset)
+	if !ok {
+		return nil, fmt.Errorf("zeroex: unsupported target asset %s", toAsset)
+	}
+	if !common.IsHexAddress(destination) {
+		return nil, fmt.Errorf("zeroex: destination %q is not an EVM address", destination)
+	}
+
+	rpc, ok := p.rpcClients[chain]
+	if !ok {
+		return nil, fmt.Errorf("zeroex: no RPC client for %s", chain)
+	}
+
+	// Same-chain only: there is exactly one possible source chain
+	token, inputAmount, err := thorchain.FundingTokens.Select(ctx, rpc, chain, sender, usdAmount, nil)
+	if err != nil {
+		return nil, fmt.Errorf("zeroex: %w", err)
+	}
+
+	q, err := p.client.GetQuote(ctx, chainID, token.Address.Hex(), buyToken, inputAmount.String(), sender.Hex(), destination)
+	if err != nil {
+		return nil, err
+	}
+	if !q.LiquidityAvailable {
+		return nil, fmt.Errorf("zeroex: no liquidity for %s", toAsset)
+	}
+
+	decimals := 18
+	if buyToken != nativeToken {
+		decimals, err = tokenDecimals(ctx, rpc, common.HexToAddress(buyToken))
+		if err != nil {
+			return nil, fmt.Errorf("zeroex: reading decimals of %s: %w", buyToken, err)
+		}
+	}
+
+	buyAmount, ok := new(big.Int).SetString(q.BuyAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("zeroex: invalid buy amount %q", q.BuyAmount)
+	}
+	expectedOut := formatUnits(buyAmount, decimals)
+
+	spender := ""
+	if q.Issues.Allowance != nil {
+		spender = q.Issues.Allowance.Spender
+	}
+
+	return []swaps.Quote{{
+		Provider:          "zeroex",
+		FromAsset:         token.Asset(chain),
+		ToAsset:           toAsset,
+		FromChain:         chain,
+		InputAmountUSD:    usdAmount,
+		InputAmount:       inputAmount,
+		ExpectedOutput:    expectedOut,
+		ExpectedOutputRaw: parseToBigInt(expectedOut),
+		Router:            q.Transaction.To,
+		ExtraData: map[string]interface{}{
+			"zeroex_tx":        q.Transaction,
+			"zeroex_spender":   spender,
+			"zeroex_min_buy":   q.MinBuyAmount,
+			"zeroex_buy_token": buyToken,
+		},
+	}}, nil
+}
+
+func (p *Provider) Execute(ctx context.Context, quote swaps.Quote, signer wallet.Signer) (swaps.ExecuteResult, error) {
+	txData, ok := quote.ExtraData["zeroex_tx"].(Transaction)
+	if !ok || txData.To == "" {
+		return swaps.ExecuteResult{}, fmt.Errorf("zeroex: missing transaction in quote ExtraData")
+	}
+	spender, _ := quote.ExtraData["zeroex_spender"].(string)
+
+	rpc, ok := p.rpcClients[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no RPC client for chain %s", quote.FromChain)
+	}
+
+	chainID, ok := chainIDs[quote.FromChain]
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("unknown chain ID for %s", quote.FromChain)
+	}
+
+	token, ok := thorchain.FundingTokens.Lookup(quote.FromChain, quote.FromAsset.Symbol)
+	if !ok {
+		return swaps.ExecuteResult{}, fmt.Errorf("no %s funding token for %s", quote.FromAsset.Symbol, quote.FromChain)
+	}
+
+	fromAddr := signer.Address()
+
+	// Step 1: Approve the AllowanceHolder only if 0x reported insufficient allowance
+	if spender != "" {
+		if err := approveERC20(ctx, rpc, chainID, signer, fromAddr, token.Address, common.HexToAddress(spender), quote.InputAmount); err != nil {
+			return swaps.ExecuteResult{}, fmt.Errorf("approving %s: %w", token.Symbol, err)
+		}
+	}
+
+	// Step 2: Send the swap transaction
+	txHash, err := sendSwapTx(ctx, rpc, chainID, signer, fromAddr, txData)
+	if err != nil {
+		return swaps.ExecuteResult{}, fmt.Errorf("swap tx: %w", err)
+	}
+
+	// Same-chain swap: status is just the receipt on this chain
+	return swaps.ExecuteResult{
+		TxHash:     txHash,
+		ExternalID: quote.FromChain,
+	}, nil
+}
+
+// CheckStatus reads the swap receipt directly; externalID is the RPC chain key.
+func (p *Provider) CheckStatus(ctx context.Context, txHash string, externalID string) (string, error) {
+	rpc, ok := p.rpcClients[externalID]
+	if !ok {
+		return "", fmt.Errorf("zeroex: no RPC client for chain %q", externalID)
+	}
+
+	receipt, err := rpc.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return "pending", nil
+		}
+		return "", err
+	}
+
+	swaps.ReportRawStatus(ctx, fmt.Sprintf("receipt status %d", receipt.Status))
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return "completed", nil
+	}
+	return "failed", nil
+}
+
+func tokenDecimals(ctx context.Context, rpc *ethclient.Client, token common.Address) (int, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20DecimalsABI))
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := parsed.Pack("decimals")
+	if err != nil {
+		return 0, err
+	}
+
+	output, err := rpc.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := parsed.Unpack("decimals", output)
+	if err != nil {
+		return 0, err
+	}
+	return int(results[0].(uint8)), nil
+}
+
+func approveERC20(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from, token, spender common.Address, amount *big.Int) error {
+	parsed, err := abi.JSON(strings.NewReader(thorchain.ERC20ApproveABI))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, token, big.NewInt(0), 100000, gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("signing approve tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("sending approve tx: %w", err)
+	}
+
+	log.Printf("0x approve tx sent: %s", signedTx.Hash().Hex())
+
+	// Wait for approval to be mined with 2-minute timeout (swap depends on this)
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(waitCtx, rpc, signedTx)
+	if err != nil {
+		return fmt.Errorf("waiting for approve: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx failed")
+	}
+
+	return nil
+}
+
+func sendSwapTx(ctx context.Context, rpc *ethclient.Client, chainID *big.Int, signer wallet.Signer, from common.Address, txData Transaction) (string, error) {
+	data, err := hexutil.Decode(txData.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding calldata: %w", err)
+	}
+
+	value := new(big.Int)
+	if txData.Value != "" {
+		if _, ok := value.SetString(txData.Value, 10); !ok {
+			return "", fmt.Errorf("invalid value %q", txData.Value)
+		}
+	}
+
+	gasLimit, ok := new(big.Int).SetString(txData.Gas, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid gas %q", txData.Gas)
+	}
+
+	nonce, err := rpc.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("getting nonce: %w", err)
+	}
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting gas price: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, common.HexToAddress(txData.To), value, gasLimit.Uint64(), gasPrice, data)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return "", fmt.Errorf("signing swap tx: %w", err)
+	}
+
+	if err := rpc.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("sending swap tx: %w", err)
+	}
+
+	log.Printf("0x swap tx sent: %s", signedTx.Hash().Hex())
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// formatUnits renders an integer amount with the given number of decimals.
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+	s := amount.String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	return s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
+
+// parseToBigInt parses a decimal string like "0.00123456" to a big.Int
+// by removing the decimal point. Multiplies by 1e8 for comparison.
+func parseToBigInt(s string) *big.Int {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) == 1 {
+		val := new(big.Int)
+		val.SetString(s, 10)
+		val.Mul(val, big.NewInt(1e8))
+		return val
+	}
+
+	frac := parts[1]
+	if len(frac) > 8 {
+		frac = frac[:8]
+	}
+	for len(frac) < 8 {
+		frac += "0"
+	}
+
+	combined := parts[0] + frac
+	val := new(big.Int)
+	val.SetString(combined, 10)
+	return val
+}